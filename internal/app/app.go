@@ -55,6 +55,8 @@ func Run() error {
 		scheduledTransactions: time.Minute,
 		openBankingSync:       5 * time.Minute,
 		notificationDelivery:  30 * time.Second,
+		transactionExports:    30 * time.Second,
+		importPreviews:        10 * time.Minute,
 	})
 	// This defer is registered after svc.Close, so workers always join before the store closes.
 	defer workers.Stop()
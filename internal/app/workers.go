@@ -22,16 +22,28 @@ type notificationDeliveryMaintainer interface {
 	RunNotificationDeliveryMaintenance(context.Context) (model.NotificationDeliveryResult, error)
 }
 
+type transactionExportMaintainer interface {
+	RunTransactionExportMaintenance(context.Context) (model.TransactionExportMaintenanceResult, error)
+}
+
+type importPreviewMaintainer interface {
+	RunImportPreviewMaintenance(context.Context) (model.ImportPreviewMaintenanceResult, error)
+}
+
 type maintenanceService interface {
 	scheduledTransactionMaintainer
 	openBankingSyncMaintainer
 	notificationDeliveryMaintainer
+	transactionExportMaintainer
+	importPreviewMaintainer
 }
 
 type maintenanceIntervals struct {
 	scheduledTransactions time.Duration
 	openBankingSync       time.Duration
 	notificationDelivery  time.Duration
+	transactionExports    time.Duration
+	importPreviews        time.Duration
 }
 
 type maintenanceWorkers struct {
@@ -57,6 +69,12 @@ func startMaintenanceWorkers(
 	workers.start(func() {
 		runNotificationDeliveryWorker(ctx, service, logger, intervals.notificationDelivery)
 	})
+	workers.start(func() {
+		runTransactionExportWorker(ctx, service, logger, intervals.transactionExports)
+	})
+	workers.start(func() {
+		runImportPreviewWorker(ctx, service, logger, intervals.importPreviews)
+	})
 	return workers
 }
 
@@ -179,12 +197,14 @@ func runScheduledTransactionWorker(
 			}
 			return
 		}
-		if result.Materialized > 0 || result.Posted > 0 || result.ScheduleReminders > 0 || result.BudgetAlerts > 0 || result.InvestmentPosted > 0 {
+		if result.Materialized > 0 || result.Posted > 0 || result.ScheduleReminders > 0 || result.BudgetAlerts > 0 ||
+			result.SavingsGoalMilestones > 0 || result.InvestmentPosted > 0 {
 			logger.InfoContext(ctx, "scheduled transaction maintenance completed",
 				"materialized", result.Materialized,
 				"posted", result.Posted,
 				"schedule_reminders", result.ScheduleReminders,
 				"budget_alerts", result.BudgetAlerts,
+				"savings_goal_milestones", result.SavingsGoalMilestones,
 				"investment_posted", result.InvestmentPosted,
 			)
 		}
@@ -201,3 +221,76 @@ func runScheduledTransactionWorker(
 		}
 	}
 }
+
+func runTransactionExportWorker(
+	ctx context.Context,
+	maintainer transactionExportMaintainer,
+	logger *slog.Logger,
+	interval time.Duration,
+) {
+	run := func() {
+		// Large exports take longer than the polling interval; the claim in the
+		// store keeps other instances away from a job while it is written.
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		defer cancel()
+		result, err := maintainer.RunTransactionExportMaintenance(runCtx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.ErrorContext(ctx, "transaction export maintenance failed", "error", err,
+					"claimed", result.Claimed, "completed", result.Completed, "failed", result.Failed,
+				)
+			}
+			return
+		}
+		if result.Claimed > 0 || result.Expired > 0 {
+			logger.InfoContext(ctx, "transaction export maintenance completed",
+				"claimed", result.Claimed, "completed", result.Completed,
+				"failed", result.Failed, "expired", result.Expired,
+			)
+		}
+	}
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+func runImportPreviewWorker(
+	ctx context.Context,
+	maintainer importPreviewMaintainer,
+	logger *slog.Logger,
+	interval time.Duration,
+) {
+	run := func() {
+		runCtx, cancel := context.WithTimeout(ctx, min(interval, 30*time.Second))
+		defer cancel()
+		result, err := maintainer.RunImportPreviewMaintenance(runCtx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.ErrorContext(ctx, "import preview maintenance failed", "error", err)
+			}
+			return
+		}
+		if result.Expired > 0 {
+			logger.InfoContext(ctx, "import preview maintenance completed", "expired", result.Expired)
+		}
+	}
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -12,8 +12,8 @@ import (
 
 func TestMaintenanceWorkersStopCancelsAndJoinsEveryWorker(t *testing.T) {
 	maintainer := &blockingMaintenanceService{
-		started:   make(chan string, 3),
-		cancelled: make(chan string, 3),
+		started:   make(chan string, 5),
+		cancelled: make(chan string, 5),
 		release:   make(chan struct{}),
 	}
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -21,6 +21,8 @@ func TestMaintenanceWorkersStopCancelsAndJoinsEveryWorker(t *testing.T) {
 		scheduledTransactions: time.Hour,
 		openBankingSync:       time.Hour,
 		notificationDelivery:  time.Hour,
+		transactionExports:    time.Hour,
+		importPreviews:        time.Hour,
 	})
 	released := false
 	defer func() {
@@ -57,8 +59,8 @@ func TestMaintenanceWorkersStopCancelsAndJoinsEveryWorker(t *testing.T) {
 
 func waitForWorkerSignals(t *testing.T, signals <-chan string) {
 	t.Helper()
-	seen := make(map[string]bool, 3)
-	for len(seen) < 3 {
+	seen := make(map[string]bool, 5)
+	for len(seen) < 5 {
 		select {
 		case name := <-signals:
 			seen[name] = true
@@ -93,3 +95,11 @@ func (s *blockingMaintenanceService) RunOpenBankingSyncMaintenance(ctx context.C
 func (s *blockingMaintenanceService) RunNotificationDeliveryMaintenance(ctx context.Context) (model.NotificationDeliveryResult, error) {
 	return model.NotificationDeliveryResult{}, s.run(ctx, "notification delivery")
 }
+
+func (s *blockingMaintenanceService) RunTransactionExportMaintenance(ctx context.Context) (model.TransactionExportMaintenanceResult, error) {
+	return model.TransactionExportMaintenanceResult{}, s.run(ctx, "transaction exports")
+}
+
+func (s *blockingMaintenanceService) RunImportPreviewMaintenance(ctx context.Context) (model.ImportPreviewMaintenanceResult, error) {
+	return model.ImportPreviewMaintenanceResult{}, s.run(ctx, "import previews")
+}
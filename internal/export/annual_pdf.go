@@ -0,0 +1,130 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+const PDFContentType = "application/pdf"
+
+const (
+	pdfMargin     = 50
+	pdfRowHeight  = 15
+	pdfBodySize   = 10
+	pdfHeaderSize = 13
+)
+
+// pdfColumns are the left edges of the label and up to three value columns.
+var pdfColumns = []float64{pdfMargin, 260, 360, 460}
+
+// pdfLayout places rows top to bottom and starts a new page when one is
+// full.
+type pdfLayout struct {
+	pages []*pdfPage
+	y     float64
+}
+
+func newPDFLayout() *pdfLayout {
+	layout := &pdfLayout{}
+	layout.newPage()
+	return layout
+}
+
+func (l *pdfLayout) newPage() {
+	l.pages = append(l.pages, &pdfPage{})
+	l.y = pdfPageHeight - pdfMargin
+}
+
+func (l *pdfLayout) page() *pdfPage {
+	return l.pages[len(l.pages)-1]
+}
+
+func (l *pdfLayout) advance(height float64) {
+	if l.y-height < pdfMargin {
+		l.newPage()
+	}
+	l.y -= height
+}
+
+func (l *pdfLayout) title(value, subtitle string) {
+	l.advance(22)
+	l.page().text(pdfMargin, l.y, 22, true, value)
+	l.advance(18)
+	l.page().text(pdfMargin, l.y, pdfBodySize, false, subtitle)
+}
+
+func (l *pdfLayout) heading(value string) {
+	l.advance(30)
+	l.page().text(pdfMargin, l.y, pdfHeaderSize, true, value)
+	l.page().line(pdfMargin, l.y-5, pdfPageWidth-pdfMargin, l.y-5)
+	l.advance(6)
+}
+
+func (l *pdfLayout) row(bold bool, cells ...string) {
+	l.advance(pdfRowHeight)
+	for index, value := range cells {
+		if index < len(pdfColumns) && value != "" {
+			l.page().text(pdfColumns[index], l.y, pdfBodySize, bold, value)
+		}
+	}
+}
+
+// WriteAnnualReportPDF renders a year-in-review report as a shareable
+// document.
+func WriteAnnualReportPDF(w io.Writer, report model.AnnualReport, generatedAt time.Time) error {
+	money := func(amount string) string { return amount + " " + report.Currency }
+	percent := func(value string) string {
+		if value == "" {
+			return "n/a"
+		}
+		return value + "%"
+	}
+	title := fmt.Sprintf("Year in review %d", report.Year)
+	layout := newPDFLayout()
+	layout.title(title, "Generated "+generatedAt.UTC().Format(time.DateOnly)+" · amounts in "+report.Currency)
+
+	layout.heading("Overview")
+	layout.row(false, "Income", money(report.Totals.Income))
+	layout.row(false, "Spending", money(report.Totals.Expense))
+	layout.row(false, "Net", money(report.Totals.Net))
+	layout.row(false, "Savings rate", percent(report.SavingsRatePercent))
+	if report.TopSpendingMonth != nil {
+		layout.row(false, "Month with the most spending", report.TopSpendingMonth.Month, money(report.TopSpendingMonth.Expense))
+	}
+
+	layout.heading("Monthly cash flow")
+	layout.row(true, "Month", "Income", "Spending", "Net")
+	for _, month := range report.Months {
+		layout.row(false, month.Month, month.Income, month.Expense, month.Net)
+	}
+
+	layout.heading("Biggest categories")
+	if len(report.TopCategories) == 0 {
+		layout.row(false, "No spending recorded")
+	}
+	for _, category := range report.TopCategories {
+		layout.row(false, category.Category, money(category.Amount), percent(category.SharePercent))
+	}
+
+	layout.heading("Biggest merchants")
+	if len(report.TopMerchants) == 0 {
+		layout.row(false, "No merchants recorded")
+	}
+	for _, merchant := range report.TopMerchants {
+		layout.row(false, merchant.Name, money(merchant.Amount), percent(merchant.SharePercent))
+	}
+
+	layout.heading("Subscriptions, investments and budgets")
+	layout.row(false, "Subscriptions", money(report.Subscriptions.Total), strconv.Itoa(report.Subscriptions.Count)+" detected")
+	layout.row(false, "Investment contributions", money(report.Investments.Contributions),
+		strconv.Itoa(report.Investments.Trades)+" trades")
+	layout.row(false, "Realized profit or loss", money(report.Investments.RealizedProfit))
+	layout.row(false, "Budget hit rate", percent(report.Budgets.HitRatePercent),
+		fmt.Sprintf("%d of %d periods", report.Budgets.WithinBudget, report.Budgets.Periods))
+
+	return writePDF(w, title, generatedAt, layout.pages)
+}
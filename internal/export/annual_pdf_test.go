@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+func TestWriteAnnualReportPDF(t *testing.T) {
+	report := model.AnnualReport{
+		Year: 2026, Currency: "EUR",
+		Totals:             model.CashFlowTotals{Income: "3000.00", Expense: "1200.00", Net: "1800.00"},
+		SavingsRatePercent: "60.0",
+		TopCategories:      []model.CategoryShare{{Category: "Café (Sofia)", Amount: "400.00", SharePercent: "33.3"}},
+		TopMerchants:       []model.MerchantReportItem{{Name: "Лидл", Amount: "250.00", SharePercent: "20.8"}},
+		Budgets:            model.AnnualBudgets{Periods: 4, WithinBudget: 3, HitRatePercent: "75.0"},
+	}
+	for month := 1; month <= 12; month++ {
+		report.Months = append(report.Months, model.CashFlowMonth{
+			Month: fmt.Sprintf("2026-%02d", month), CashFlowTotals: model.CashFlowTotals{Income: "250.00", Expense: "100.00", Net: "150.00"},
+		})
+	}
+	report.TopSpendingMonth = &report.Months[2]
+
+	var output bytes.Buffer
+	if err := WriteAnnualReportPDF(&output, report, time.Date(2026, 12, 31, 9, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	document := output.String()
+	if !strings.HasPrefix(document, "%PDF-1.4\n") || !strings.HasSuffix(document, "%%EOF\n") {
+		t.Fatalf("document framing = %q ... %q", document[:12], document[len(document)-12:])
+	}
+	for _, want := range []string{
+		"(Year in review 2026)", "(Caf\\351 \\(Sofia\\))", "(????)", "(60.0%)", "(2026-03)",
+		"(3 of 4 periods)", "/CreationDate (D:20261231090000Z)", "/Count 1",
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document does not contain %s", want)
+		}
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(document)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(document[xref:], "xref\n0 8\n") {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(document[xref:], -1)
+	if len(entries) != 7 {
+		t.Fatalf("xref has %d objects, want 7", len(entries))
+	}
+	for index, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj\n", index+1); !strings.HasPrefix(document[offset:], want) {
+			t.Errorf("xref entry %d points at %q", index+1, document[offset:offset+10])
+		}
+	}
+	length := regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindStringSubmatchIndex(document)
+	streamLength, _ := strconv.Atoi(document[length[2]:length[3]])
+	if !strings.HasPrefix(document[length[1]+streamLength:], "endstream") {
+		t.Fatal("content stream length does not match its contents")
+	}
+}
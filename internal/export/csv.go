@@ -0,0 +1,68 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"money-manager-server/internal/model"
+)
+
+type csvEncoder struct{ writer *csv.Writer }
+
+func newCSVEncoder(w io.Writer, _ Options) (Encoder, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"occurred_at", "type", "category", "description", "amount", "currency", "source", "status",
+		"excluded_from_budget",
+	}); err != nil {
+		return nil, err
+	}
+	return &csvEncoder{writer: writer}, nil
+}
+
+func (e *csvEncoder) Encode(transaction model.Transaction) error {
+	return e.writer.Write([]string{
+		transaction.OccurredAt,
+		transaction.Type,
+		transaction.Category,
+		transaction.Description,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Source,
+		transaction.Status,
+		strconv.FormatBool(transaction.ExcludedFromBudget),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type tradeCSVEncoder struct{ writer *csv.Writer }
+
+func newTradeCSVEncoder(w io.Writer, _ Options) (TradeEncoder, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"occurred_at", "asset_type", "symbol", "asset_name", "exchange", "market_currency", "broker", "side",
+		"amount", "quantity", "price_per_unit", "price_provider", "price_as_of",
+		"fees", "currency", "notes",
+	}); err != nil {
+		return nil, err
+	}
+	return &tradeCSVEncoder{writer: writer}, nil
+}
+
+func (e *tradeCSVEncoder) Encode(trade model.InvestmentTrade) error {
+	return e.writer.Write([]string{
+		trade.OccurredAt, trade.AssetType, trade.Symbol, trade.AssetName, trade.Exchange, trade.MarketCurrency, trade.Broker,
+		trade.Side, trade.Amount, trade.Quantity, trade.PricePerUnit, trade.PriceProvider,
+		trade.PriceAsOf, trade.Fees, trade.Currency, trade.Notes,
+	})
+}
+
+func (e *tradeCSVEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
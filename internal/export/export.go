@@ -0,0 +1,132 @@
+// Package export encodes transactions and investment trades into downloadable
+// file formats. Encoders receive one record at a time, so an export of any
+// size is written with bounded memory; only per-category summaries and
+// security lists are kept until Close. The year-in-review report is also
+// rendered here as a PDF.
+package export
+
+import (
+	"io"
+	"slices"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+// Encoder writes transactions in one file format. Close writes any trailer
+// and flushes buffered output; it does not close the underlying writer.
+type Encoder interface {
+	Encode(model.Transaction) error
+	Close() error
+}
+
+// TradeEncoder writes investment trades in one file format.
+type TradeEncoder interface {
+	Encode(model.InvestmentTrade) error
+	Close() error
+}
+
+// Options describe the export as a whole, for formats whose headers carry
+// the statement period, currency, or creation time.
+type Options struct {
+	From        string
+	To          string
+	Currency    string
+	GeneratedAt time.Time
+}
+
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newEncoder  func(io.Writer, Options) (Encoder, error)
+}
+
+func (f Format) NewEncoder(w io.Writer, options Options) (Encoder, error) {
+	return f.newEncoder(w, options)
+}
+
+type TradeFormat struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newEncoder  func(io.Writer, Options) (TradeEncoder, error)
+}
+
+func (f TradeFormat) NewEncoder(w io.Writer, options Options) (TradeEncoder, error) {
+	return f.newEncoder(w, options)
+}
+
+const (
+	ofxContentType  = "application/x-ofx"
+	qifContentType  = "application/qif"
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	jsonContentType = "application/json"
+)
+
+var formats = map[string]Format{
+	"csv": {
+		Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv",
+		newEncoder: newCSVEncoder,
+	},
+	"jsonl": {
+		Name: "jsonl", ContentType: "application/x-ndjson", Extension: "jsonl",
+		newEncoder: func(w io.Writer, _ Options) (Encoder, error) {
+			return newJSONLinesEncoder[model.Transaction](w), nil
+		},
+	},
+	"json": {
+		Name: "json", ContentType: jsonContentType, Extension: "json",
+		newEncoder: func(w io.Writer, _ Options) (Encoder, error) {
+			return newJSONArrayEncoder[model.Transaction](w), nil
+		},
+	},
+	"ofx":  {Name: "ofx", ContentType: ofxContentType, Extension: "ofx", newEncoder: newOFXEncoder},
+	"qif":  {Name: "qif", ContentType: qifContentType, Extension: "qif", newEncoder: newQIFEncoder},
+	"xlsx": {Name: "xlsx", ContentType: xlsxContentType, Extension: "xlsx", newEncoder: newXLSXEncoder},
+}
+
+var tradeFormats = map[string]TradeFormat{
+	"csv": {
+		Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv",
+		newEncoder: newTradeCSVEncoder,
+	},
+	"json": {
+		Name: "json", ContentType: jsonContentType, Extension: "json",
+		newEncoder: func(w io.Writer, _ Options) (TradeEncoder, error) {
+			return newJSONArrayEncoder[model.InvestmentTrade](w), nil
+		},
+	},
+	"ofx":  {Name: "ofx", ContentType: ofxContentType, Extension: "ofx", newEncoder: newTradeOFXEncoder},
+	"qif":  {Name: "qif", ContentType: qifContentType, Extension: "qif", newEncoder: newTradeQIFEncoder},
+	"xlsx": {Name: "xlsx", ContentType: xlsxContentType, Extension: "xlsx", newEncoder: newTradeXLSXEncoder},
+}
+
+func Lookup(name string) (Format, bool) {
+	format, ok := formats[name]
+	return format, ok
+}
+
+func LookupTrades(name string) (TradeFormat, bool) {
+	format, ok := tradeFormats[name]
+	return format, ok
+}
+
+// Names returns the supported transaction format names in a stable order.
+func Names() []string {
+	return sortedKeys(formats)
+}
+
+// TradeNames returns the supported trade format names in a stable order.
+func TradeNames() []string {
+	return sortedKeys(tradeFormats)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
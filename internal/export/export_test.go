@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"money-manager-server/internal/model"
+)
+
+var sampleTransactions = []model.Transaction{
+	{
+		ID: 1, Type: "expense", Category: "groceries", Description: `LIDL "Sofia", 12`, Amount: "12.50",
+		Currency: "EUR", OccurredAt: "2026-07-10", Source: "manual", Status: "booked", Tags: []string{},
+	},
+	{
+		ID: 2, Type: "income", Category: "salary", Description: "Salary <July>", Amount: "2500.00",
+		Currency: "EUR", OccurredAt: "2026-07-25", Source: "import", Status: "booked",
+		ExcludedFromBudget: true, Tags: []string{"work"},
+	},
+}
+
+func encodeAll(t *testing.T, name string, transactions []model.Transaction) string {
+	t.Helper()
+	format, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("Lookup(%q) failed", name)
+	}
+	var output bytes.Buffer
+	encoder, err := format.NewEncoder(&output, Options{From: "2026-07-01", To: "2026-07-31", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("NewEncoder() error = %v", err)
+	}
+	for _, transaction := range transactions {
+		if err := encoder.Encode(transaction); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return output.String()
+}
+
+func TestCSVEncoderWritesHeaderAndQuotedRows(t *testing.T) {
+	got := encodeAll(t, "csv", sampleTransactions)
+	want := "occurred_at,type,category,description,amount,currency,source,status,excluded_from_budget\n" +
+		"2026-07-10,expense,groceries,\"LIDL \"\"Sofia\"\", 12\",12.50,EUR,manual,booked,false\n" +
+		"2026-07-25,income,salary,Salary <July>,2500.00,EUR,import,booked,true\n"
+	if got != want {
+		t.Fatalf("csv = %q", got)
+	}
+	if empty := encodeAll(t, "csv", nil); strings.Count(empty, "\n") != 1 {
+		t.Fatalf("empty csv = %q", empty)
+	}
+}
+
+func TestJSONLinesEncoderWritesOneObjectPerLine(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(encodeAll(t, "jsonl", sampleTransactions), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "Salary <July>") {
+		t.Fatalf("jsonl = %q", lines)
+	}
+	var decoded model.Transaction
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatalf("decode line: %v", err)
+	}
+	if decoded.ID != 2 || !decoded.ExcludedFromBudget || len(decoded.Tags) != 1 {
+		t.Fatalf("decoded = %#v", decoded)
+	}
+	if _, ok := Lookup("xml"); ok {
+		t.Fatal("Lookup accepted an unknown format")
+	}
+}
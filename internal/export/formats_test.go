@@ -0,0 +1,208 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/statement"
+)
+
+var roundTripTransactions = append([]model.Transaction{{
+	ID: 3, Type: "expense", Category: "travel", Description: "Airline booking reference ABC123 Sofia to Lisbon",
+	Amount: "310.05", Currency: "EUR", OccurredAt: "2026-07-28", Source: "manual", Status: "booked", Tags: []string{},
+}}, sampleTransactions...)
+
+func assertStatementRoundTrip(t *testing.T, parsed []statement.Transaction) {
+	t.Helper()
+	if len(parsed) != len(roundTripTransactions) {
+		t.Fatalf("parsed %d transactions, want %d", len(parsed), len(roundTripTransactions))
+	}
+	for index, want := range roundTripTransactions {
+		got := parsed[index]
+		if got.Invalid != "" {
+			t.Fatalf("row %d invalid field %q", index, got.Invalid)
+		}
+		if got.Date.Format(time.DateOnly) != want.OccurredAt || got.Amount != want.Amount ||
+			got.Debit != (want.Type == "expense") || got.Description != want.Description {
+			t.Fatalf("row %d = %#v, want %#v", index, got, want)
+		}
+	}
+}
+
+func TestOFXExportRoundTripsThroughImporter(t *testing.T) {
+	parsed, err := statement.ParseOFX([]byte(encodeAll(t, "ofx", roundTripTransactions)))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	assertStatementRoundTrip(t, parsed)
+	if parsed[0].ID != "mm-3" {
+		t.Fatalf("FITID = %q", parsed[0].ID)
+	}
+}
+
+func TestQIFExportRoundTripsThroughImporter(t *testing.T) {
+	parsed, err := statement.ParseQIF([]byte(encodeAll(t, "qif", roundTripTransactions)), "")
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	assertStatementRoundTrip(t, parsed)
+}
+
+func TestJSONExportDecodesAsArray(t *testing.T) {
+	var decoded []model.Transaction
+	if err := json.Unmarshal([]byte(encodeAll(t, "json", roundTripTransactions)), &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(decoded) != 3 || decoded[2].Description != "Salary <July>" {
+		t.Fatalf("decoded = %#v", decoded)
+	}
+	if empty := encodeAll(t, "json", nil); strings.TrimSpace(empty) != "[]" {
+		t.Fatalf("empty json = %q", empty)
+	}
+}
+
+func readZipEntry(t *testing.T, contents string, name string) string {
+	t.Helper()
+	archive, err := zip.NewReader(strings.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	entry, err := archive.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer entry.Close()
+	data, err := io.ReadAll(entry)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestXLSXExportWritesTransactionsAndCategorySummary(t *testing.T) {
+	contents := encodeAll(t, "xlsx", append(roundTripTransactions, model.Transaction{
+		ID: 4, Type: "expense", Category: "groceries", Description: "Market", Amount: "7.55",
+		Currency: "EUR", OccurredAt: "2026-07-29", Source: "manual", Status: "booked",
+	}))
+	workbook := readZipEntry(t, contents, "xl/workbook.xml")
+	if !strings.Contains(workbook, `name="Transactions"`) || !strings.Contains(workbook, `name="Categories"`) {
+		t.Fatalf("workbook = %s", workbook)
+	}
+	transactions := readZipEntry(t, contents, "xl/worksheets/sheet1.xml")
+	if strings.Count(transactions, "<row>") != 5 || !strings.Contains(transactions, "Salary &lt;July&gt;") ||
+		!strings.Contains(transactions, "<v>2500.00</v>") {
+		t.Fatalf("transactions sheet = %s", transactions)
+	}
+	categories := readZipEntry(t, contents, "xl/worksheets/sheet2.xml")
+	if !strings.Contains(categories, `groceries</t></is></c><c><v>2</v></c><c><v>20.05</v>`) ||
+		!strings.Contains(categories, `salary</t></is></c><c><v>1</v></c><c><v>2500.00</v>`) {
+		t.Fatalf("categories sheet = %s", categories)
+	}
+	readZipEntry(t, contents, "[Content_Types].xml")
+	readZipEntry(t, contents, "xl/styles.xml")
+}
+
+var sampleTrades = []model.InvestmentTrade{
+	{
+		ID: 1, AssetType: "stock", Symbol: "aapl", AssetName: "Apple Inc.", Side: "buy", Amount: "200.00",
+		Quantity: "1.25", PricePerUnit: "160.00", Fees: "1.50", Currency: "EUR", OccurredAt: "2026-07-10",
+	},
+	{
+		ID: 2, AssetType: "crypto", Symbol: "BTC", AssetName: "Bitcoin", Side: "sell", Amount: "500.00",
+		Quantity: "0.01", PricePerUnit: "50000.00", Fees: "0.00", Currency: "EUR", OccurredAt: "2026-07-12",
+		Notes: "rebalance",
+	},
+	{
+		ID: 3, AssetType: "stock", Symbol: "AAPL", AssetName: "Apple Inc.", Side: "sell", Amount: "170.00",
+		Quantity: "1", PricePerUnit: "170.00", Fees: "1.00", Currency: "EUR", OccurredAt: "2026-07-20",
+	},
+}
+
+func encodeTrades(t *testing.T, name string) string {
+	t.Helper()
+	format, ok := LookupTrades(name)
+	if !ok {
+		t.Fatalf("LookupTrades(%q) failed", name)
+	}
+	var output bytes.Buffer
+	encoder, err := format.NewEncoder(&output, Options{From: "2026-07-01", To: "2026-07-31", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("NewEncoder() error = %v", err)
+	}
+	for _, trade := range sampleTrades {
+		if err := encoder.Encode(trade); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return output.String()
+}
+
+func TestTradeOFXExportWritesInvestmentStatement(t *testing.T) {
+	contents := encodeTrades(t, "ofx")
+	for _, want := range []string{
+		"<BUYSTOCK><INVBUY>", "<FITID>mm-trade-1</FITID>", "<UNITS>1.25</UNITS>", "<TOTAL>-201.50</TOTAL>",
+		"<SELLOTHER><INVSELL>", "<UNITS>-0.01</UNITS>", "<TOTAL>500.00</TOTAL>",
+		"<STOCKINFO>", "<OTHERINFO>", "<UNIQUEID>BTC</UNIQUEID>",
+	} {
+		if !strings.Contains(contents, want) {
+			t.Fatalf("trade ofx missing %q:\n%s", want, contents)
+		}
+	}
+	if strings.Count(contents, "<STOCKINFO>") != 1 {
+		t.Fatalf("securities are not deduplicated:\n%s", contents)
+	}
+}
+
+func TestTradeQIFAndXLSXExports(t *testing.T) {
+	qif := encodeTrades(t, "qif")
+	if !strings.HasPrefix(qif, "!Type:Invst\n") || strings.Count(qif, "^\n") != 3 ||
+		!strings.Contains(qif, "NBuy\nYAAPL\n") || !strings.Contains(qif, "Mrebalance\n") {
+		t.Fatalf("trade qif = %q", qif)
+	}
+	contents := encodeTrades(t, "xlsx")
+	assets := readZipEntry(t, contents, "xl/worksheets/sheet2.xml")
+	if !strings.Contains(assets, `AAPL</t></is></c><c><v>2</v></c><c><v>200.00</v></c><c><v>170.00</v></c><c><v>2.50</v></c><c><v>0.25</v>`) {
+		t.Fatalf("assets sheet = %s", assets)
+	}
+	if _, ok := LookupTrades("jsonl"); ok {
+		t.Fatal("LookupTrades accepted a transaction-only format")
+	}
+}
+
+func TestJournalNamesAreValidIdentifiers(t *testing.T) {
+	if got := AccountName("Expenses", "eating out", "café & bar"); got != "Expenses:Eating-Out:Café-Bar" {
+		t.Fatalf("AccountName() = %q", got)
+	}
+	if got := AccountName("Assets", "Bank", "", "2nd account"); got != "Assets:Bank:X:2nd-Account" {
+		t.Fatalf("AccountName() with blank component = %q", got)
+	}
+	beancount, _ := LookupJournal("beancount")
+	ledger, _ := LookupJournal("ledger")
+	var output bytes.Buffer
+	bean, err := beancount.NewJournal(&output, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := ledger.NewJournal(&output, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for symbol, want := range map[string][2]string{
+		"aapl":  {"AAPL", "AAPL"},
+		"BRK.B": {"BRK.B", `"BRK.B"`},
+		"7203":  {"X7203", `"7203"`},
+	} {
+		if got := [2]string{bean.commodity(symbol), plain.commodity(symbol)}; got != want {
+			t.Fatalf("commodity(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
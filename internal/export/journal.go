@@ -0,0 +1,291 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// JournalFormat is a plain-text accounting dialect. Beancount and Ledger share
+// the double-entry model but differ in directive syntax and in how lots and
+// opening balances are written.
+type JournalFormat struct {
+	Name        string
+	ContentType string
+	Extension   string
+	ledger      bool
+}
+
+var journalFormats = map[string]JournalFormat{
+	"beancount": {Name: "beancount", ContentType: "text/plain; charset=utf-8", Extension: "beancount"},
+	"ledger":    {Name: "ledger", ContentType: "text/plain; charset=utf-8", Extension: "ledger", ledger: true},
+}
+
+func LookupJournal(name string) (JournalFormat, bool) {
+	format, ok := journalFormats[name]
+	return format, ok
+}
+
+// JournalNames returns the supported journal dialects in a stable order.
+func JournalNames() []string {
+	return sortedKeys(journalFormats)
+}
+
+// Posting is one leg of a journal transaction. Amount is a signed decimal in
+// Commodity. Cost is the total cost of a lot in CostCurrency and Price the
+// total price it was traded at; both are optional.
+type Posting struct {
+	Account      string
+	Amount       string
+	Commodity    string
+	Cost         string
+	CostCurrency string
+	Price        string
+}
+
+type JournalTransaction struct {
+	Date      string
+	Payee     string
+	Narration string
+	Tags      []string
+	Postings  []Posting
+}
+
+// Journal writes directives in the order they are given. Beancount sorts
+// directives by date itself, but Ledger checks balance assertions in file
+// order, so callers must write dated directives chronologically.
+type Journal struct {
+	format     JournalFormat
+	buffer     *bufio.Writer
+	afterBlock bool
+	err        error
+}
+
+func (f JournalFormat) NewJournal(w io.Writer, options Options) (*Journal, error) {
+	options = withDefaults(options)
+	journal := &Journal{format: f, buffer: bufio.NewWriter(w)}
+	journal.printf("; Money Manager journal from %s to %s, generated %s\n",
+		options.From, options.To, options.GeneratedAt.UTC().Format(time.RFC3339))
+	if f.ledger {
+		journal.printf("commodity %s\n\n", journal.commodity(options.Currency))
+	} else {
+		journal.printf("option \"title\" \"Money Manager\"\noption \"operating_currency\" %s\n\n", quote(options.Currency))
+	}
+	return journal, journal.err
+}
+
+// Open declares an account. Commodities and booking constrain a Beancount
+// account; Ledger declarations carry neither.
+func (j *Journal) Open(date, account string, commodities []string, booking string) error {
+	if j.format.ledger {
+		j.printf("account %s\n", account)
+		return j.err
+	}
+	line := date + " open " + account
+	if len(commodities) > 0 {
+		names := make([]string, 0, len(commodities))
+		for _, commodity := range commodities {
+			names = append(names, j.commodity(commodity))
+		}
+		line += " " + strings.Join(names, ",")
+	}
+	if booking != "" {
+		line += " " + quote(booking)
+	}
+	j.printf("%s\n", line)
+	return j.err
+}
+
+// Transaction writes a balanced transaction. Ledger balances lots on their
+// cost, so a posting with a cost is written at that cost and its price is
+// left out; Beancount keeps both.
+func (j *Journal) Transaction(transaction JournalTransaction) error {
+	j.separate(true)
+	if j.format.ledger {
+		j.printf("%s * %s\n", ledgerDate(transaction.Date), ledgerTitle(transaction))
+		if tags := journalTags(transaction.Tags); len(tags) > 0 {
+			j.printf("    ; :%s:\n", strings.Join(tags, ":"))
+		}
+	} else {
+		line := transaction.Date + " *"
+		if transaction.Payee != "" {
+			line += " " + quote(transaction.Payee)
+		}
+		line += " " + quote(transaction.Narration)
+		for _, tag := range journalTags(transaction.Tags) {
+			line += " #" + tag
+		}
+		j.printf("%s\n", line)
+	}
+	for _, posting := range transaction.Postings {
+		j.posting(posting)
+	}
+	return j.err
+}
+
+func (j *Journal) posting(posting Posting) {
+	line := j.indent() + posting.Account + "  " + posting.Amount + " " + j.commodity(posting.Commodity)
+	switch {
+	case j.format.ledger && posting.Cost != "":
+		line += " @@ " + strings.TrimPrefix(posting.Cost, "-") + " " + j.commodity(posting.CostCurrency)
+	case posting.Cost != "":
+		line += " {{" + strings.TrimPrefix(posting.Cost, "-") + " " + j.commodity(posting.CostCurrency) + "}}"
+		if posting.Price != "" {
+			line += " @@ " + strings.TrimPrefix(posting.Price, "-") + " " + j.commodity(posting.CostCurrency)
+		}
+	}
+	j.printf("%s\n", line)
+}
+
+// Price records the market price of one unit of commodity.
+func (j *Journal) Price(date, commodity, price, currency string) error {
+	j.separate(false)
+	if j.format.ledger {
+		j.printf("P %s %s %s %s\n", ledgerDate(date), j.commodity(commodity), price, j.commodity(currency))
+	} else {
+		j.printf("%s price %s %s %s\n", date, j.commodity(commodity), price, j.commodity(currency))
+	}
+	return j.err
+}
+
+// Balance asserts the balance of account at the start of date. When opening
+// is set, the difference to the journal's own postings is first booked
+// against it: Beancount pads from padDate, and Ledger assigns the balance on
+// date, since it has no pad directive.
+func (j *Journal) Balance(date, account, amount, currency, opening, padDate string) error {
+	j.separate(j.format.ledger)
+	if j.format.ledger {
+		j.printf("%s * Balance %s\n", ledgerDate(date), account)
+		if opening != "" {
+			j.printf("    %s  = %s %s\n    %s\n", account, amount, j.commodity(currency), opening)
+		} else {
+			j.printf("    %s  0 %s = %s %s\n", account, j.commodity(currency), amount, j.commodity(currency))
+		}
+		return j.err
+	}
+	if opening != "" {
+		j.printf("%s pad %s %s\n", padDate, account, opening)
+	}
+	j.printf("%s balance %s  %s %s\n", date, account, amount, j.commodity(currency))
+	return j.err
+}
+
+// Close flushes buffered output; it does not close the underlying writer.
+func (j *Journal) Close() error {
+	if j.err != nil {
+		return j.err
+	}
+	return j.buffer.Flush()
+}
+
+func (j *Journal) printf(format string, arguments ...any) {
+	if j.err == nil {
+		_, j.err = fmt.Fprintf(j.buffer, format, arguments...)
+	}
+}
+
+// separate puts a blank line around multi-line entries so one-line
+// directives stay grouped.
+func (j *Journal) separate(block bool) {
+	if block || j.afterBlock {
+		j.printf("\n")
+	}
+	j.afterBlock = block
+}
+
+func (j *Journal) indent() string {
+	if j.format.ledger {
+		return "    "
+	}
+	return "  "
+}
+
+// commodity renders a symbol as a valid commodity name. Beancount commodities
+// are upper-case and start with a letter; Ledger quotes any name that is not
+// purely alphabetic.
+func (j *Journal) commodity(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if j.format.ledger {
+		for _, r := range symbol {
+			if !unicode.IsLetter(r) {
+				return `"` + strings.ReplaceAll(symbol, `"`, "") + `"`
+			}
+		}
+		return symbol
+	}
+	var builder strings.Builder
+	for _, r := range symbol {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-', r == '\'':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('-')
+		}
+	}
+	name := strings.TrimRight(builder.String(), ".-_'")
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		name = "X" + name
+	}
+	if len(name) > 24 {
+		name = strings.TrimRight(name[:24], ".-_'")
+	}
+	return name
+}
+
+// AccountName joins sanitized components under root. Each component becomes
+// capitalized letters and digits with dashes between words, which both
+// Beancount and Ledger accept.
+func AccountName(root string, components ...string) string {
+	parts := []string{root}
+	for _, component := range components {
+		words := strings.FieldsFunc(component, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for index, word := range words {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			words[index] = string(runes)
+		}
+		part := strings.Join(words, "-")
+		if part == "" || !unicode.IsUpper([]rune(part)[0]) && !unicode.IsDigit([]rune(part)[0]) {
+			part = "X" + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ":")
+}
+
+func journalTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '/' || r == '.' {
+				return r
+			}
+			return '-'
+		}, strings.TrimSpace(tag))
+		if tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func ledgerTitle(transaction JournalTransaction) string {
+	if transaction.Payee == "" {
+		return oneLine(transaction.Narration)
+	}
+	return oneLine(transaction.Payee) + " | " + oneLine(transaction.Narration)
+}
+
+func ledgerDate(date string) string {
+	return strings.ReplaceAll(date, "-", "/")
+}
+
+func quote(value string) string {
+	value = strings.ReplaceAll(oneLine(value), `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonLinesEncoder writes one object per line, using the same fields as the
+// JSON API.
+type jsonLinesEncoder[T any] struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLinesEncoder[T any](w io.Writer) *jsonLinesEncoder[T] {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	return &jsonLinesEncoder[T]{buffer: buffer, encoder: encoder}
+}
+
+func (e *jsonLinesEncoder[T]) Encode(value T) error {
+	return e.encoder.Encode(value)
+}
+
+func (e *jsonLinesEncoder[T]) Close() error {
+	return e.buffer.Flush()
+}
+
+// jsonArrayEncoder writes a single JSON array, one element per line, so the
+// file can be produced without holding the records in memory.
+type jsonArrayEncoder[T any] struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+func newJSONArrayEncoder[T any](w io.Writer) *jsonArrayEncoder[T] {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	return &jsonArrayEncoder[T]{buffer: buffer, encoder: encoder}
+}
+
+func (e *jsonArrayEncoder[T]) Encode(value T) error {
+	separator := ","
+	if e.count == 0 {
+		separator = "[\n"
+	}
+	e.count++
+	if _, err := e.buffer.WriteString(separator); err != nil {
+		return err
+	}
+	return e.encoder.Encode(value)
+}
+
+func (e *jsonArrayEncoder[T]) Close() error {
+	closing := "]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	if _, err := e.buffer.WriteString(closing); err != nil {
+		return err
+	}
+	return e.buffer.Flush()
+}
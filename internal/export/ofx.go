@@ -0,0 +1,233 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-manager-server/internal/model"
+)
+
+// ofxNameRunes is the OFX limit for NAME. Longer descriptions go to MEMO
+// alone so importers do not join a truncated name with the full text.
+const ofxNameRunes = 32
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>` +
+	`<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+`
+
+// ofxEncoder writes an OFX 2.2 bank statement for one synthetic account.
+// The ledger balance is the net of the exported transactions, since the
+// ledger does not track account balances.
+type ofxEncoder struct {
+	buffer  *bufio.Writer
+	options Options
+	net     *big.Rat
+}
+
+func newOFXEncoder(w io.Writer, options Options) (Encoder, error) {
+	options = withDefaults(options)
+	encoder := &ofxEncoder{buffer: bufio.NewWriter(w), options: options, net: new(big.Rat)}
+	_, err := fmt.Fprintf(encoder.buffer, ofxHeader+`<BANKMSGSRSV1><STMTTRNRS><TRNUID>1</TRNUID>`+
+		`<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>MONEYMANAGER</BANKID><ACCTID>LEDGER</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, ofxTimestamp(options.GeneratedAt), xmlText(options.Currency), ofxDate(options.From), ofxDate(options.To))
+	return encoder, err
+}
+
+func (e *ofxEncoder) Encode(transaction model.Transaction) error {
+	transactionType, amount := "CREDIT", transaction.Amount
+	if transaction.Type == "expense" {
+		transactionType, amount = "DEBIT", "-"+transaction.Amount
+	}
+	if value, ok := new(big.Rat).SetString(amount); ok {
+		e.net.Add(e.net, value)
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>mm-%d</FITID>",
+		transactionType, ofxDate(transaction.OccurredAt), amount, transaction.ID)
+	description := oneLine(transaction.Description)
+	switch {
+	case description == "":
+	case utf8.RuneCountInString(description) <= ofxNameRunes:
+		fmt.Fprintf(&builder, "<NAME>%s</NAME>", xmlText(description))
+	default:
+		fmt.Fprintf(&builder, "<MEMO>%s</MEMO>", xmlText(description))
+	}
+	if transaction.Currency != "" && transaction.Currency != e.options.Currency {
+		fmt.Fprintf(&builder, "<CURRENCY><CURRATE>1</CURRATE><CURSYM>%s</CURSYM></CURRENCY>", xmlText(transaction.Currency))
+	}
+	builder.WriteString("</STMTTRN>\n")
+	_, err := e.buffer.WriteString(builder.String())
+	return err
+}
+
+func (e *ofxEncoder) Close() error {
+	if _, err := fmt.Fprintf(e.buffer, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`, e.net.FloatString(2), ofxDate(e.options.To)); err != nil {
+		return err
+	}
+	return e.buffer.Flush()
+}
+
+// tradeOFXEncoder writes an OFX 2.2 investment statement. Stocks use
+// BUYSTOCK and SELLSTOCK; crypto assets have no OFX security type and use
+// BUYOTHER and SELLOTHER. The security list is written at Close.
+type tradeOFXEncoder struct {
+	buffer     *bufio.Writer
+	options    Options
+	securities map[string]ofxSecurity
+	order      []string
+}
+
+type ofxSecurity struct {
+	assetType string
+	name      string
+}
+
+func newTradeOFXEncoder(w io.Writer, options Options) (TradeEncoder, error) {
+	options = withDefaults(options)
+	encoder := &tradeOFXEncoder{buffer: bufio.NewWriter(w), options: options, securities: map[string]ofxSecurity{}}
+	_, err := fmt.Fprintf(encoder.buffer, ofxHeader+`<INVSTMTMSGSRSV1><INVSTMTTRNRS><TRNUID>1</TRNUID>`+
+		`<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<INVSTMTRS><DTASOF>%s</DTASOF><CURDEF>%s</CURDEF>
+<INVACCTFROM><BROKERID>money-manager</BROKERID><ACCTID>INVESTMENTS</ACCTID></INVACCTFROM>
+<INVTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, ofxTimestamp(options.GeneratedAt), ofxDate(options.To), xmlText(options.Currency), ofxDate(options.From), ofxDate(options.To))
+	return encoder, err
+}
+
+func (e *tradeOFXEncoder) Encode(trade model.InvestmentTrade) error {
+	symbol := strings.ToUpper(trade.Symbol)
+	if _, seen := e.securities[symbol]; !seen {
+		e.securities[symbol] = ofxSecurity{assetType: trade.AssetType, name: trade.AssetName}
+		e.order = append(e.order, symbol)
+	}
+	amount := decimal(trade.Amount)
+	fees := decimal(trade.Fees)
+	units := decimal(trade.Quantity)
+	total := new(big.Rat).Sub(amount, fees)
+	action, detail, kind := "SELL", "INVSELL", "OTHER"
+	if trade.Side == "buy" {
+		action, detail = "BUY", "INVBUY"
+		total.Neg(new(big.Rat).Add(amount, fees))
+	} else {
+		units.Neg(units)
+	}
+	if trade.AssetType == "stock" {
+		kind = "STOCK"
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "<%s%s><%s><INVTRAN><FITID>mm-trade-%d</FITID><DTTRADE>%s</DTTRADE>",
+		action, kind, detail, trade.ID, ofxTimestampValue(trade.OccurredAt))
+	if notes := oneLine(trade.Notes); notes != "" {
+		fmt.Fprintf(&builder, "<MEMO>%s</MEMO>", xmlText(notes))
+	}
+	fmt.Fprintf(&builder, "</INVTRAN><SECID><UNIQUEID>%s</UNIQUEID><UNIQUEIDTYPE>TICKER</UNIQUEIDTYPE></SECID>"+
+		"<UNITS>%s</UNITS><UNITPRICE>%s</UNITPRICE><FEES>%s</FEES><TOTAL>%s</TOTAL>"+
+		"<SUBACCTSEC>CASH</SUBACCTSEC><SUBACCTFUND>CASH</SUBACCTFUND></%s>",
+		xmlText(symbol), decimalString(units), decimalString(decimal(trade.PricePerUnit)),
+		fees.FloatString(2), total.FloatString(2), detail)
+	if kind == "STOCK" {
+		fmt.Fprintf(&builder, "<%sTYPE>%s</%sTYPE>", action, action, action)
+	}
+	fmt.Fprintf(&builder, "</%s%s>\n", action, kind)
+	_, err := e.buffer.WriteString(builder.String())
+	return err
+}
+
+func (e *tradeOFXEncoder) Close() error {
+	if _, err := e.buffer.WriteString("</INVTRANLIST>\n</INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>\n<SECLISTMSGSRSV1><SECLIST>\n"); err != nil {
+		return err
+	}
+	for _, symbol := range e.order {
+		security := e.securities[symbol]
+		kind := "OTHERINFO"
+		if security.assetType == "stock" {
+			kind = "STOCKINFO"
+		}
+		name := security.name
+		if name == "" {
+			name = symbol
+		}
+		if _, err := fmt.Fprintf(e.buffer,
+			"<%s><SECINFO><SECID><UNIQUEID>%s</UNIQUEID><UNIQUEIDTYPE>TICKER</UNIQUEIDTYPE></SECID>"+
+				"<SECNAME>%s</SECNAME><TICKER>%s</TICKER></SECINFO></%s>\n",
+			kind, xmlText(symbol), xmlText(oneLine(name)), xmlText(symbol), kind); err != nil {
+			return err
+		}
+	}
+	if _, err := e.buffer.WriteString("</SECLIST></SECLISTMSGSRSV1>\n</OFX>\n"); err != nil {
+		return err
+	}
+	return e.buffer.Flush()
+}
+
+func withDefaults(options Options) Options {
+	if options.Currency == "" {
+		options.Currency = "EUR"
+	}
+	if options.GeneratedAt.IsZero() {
+		options.GeneratedAt = time.Now()
+	}
+	return options
+}
+
+// ofxDate turns YYYY-MM-DD into the OFX date form YYYYMMDD.
+func ofxDate(value string) string {
+	return strings.ReplaceAll(value, "-", "")
+}
+
+func ofxTimestamp(value time.Time) string {
+	return value.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// ofxTimestampValue converts an RFC3339 trade time, or a plain date, to OFX.
+func ofxTimestampValue(value string) string {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return ofxTimestamp(timestamp)
+	}
+	return ofxDate(value)
+}
+
+func xmlText(value string) string {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(value))
+	return builder.String()
+}
+
+// oneLine collapses whitespace so a value fits on one line of a
+// line-oriented format.
+func oneLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// decimal parses a decimal string, treating an empty or invalid value as 0.
+func decimal(value string) *big.Rat {
+	number, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return new(big.Rat)
+	}
+	return number
+}
+
+// decimalString prints a decimal with up to eighteen fractional digits, the
+// precision of stored quantities, and no trailing zeros.
+func decimalString(value *big.Rat) string {
+	text := value.FloatString(18)
+	text = strings.TrimRight(text, "0")
+	return strings.TrimSuffix(text, ".")
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// An A4 page in points.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+)
+
+// pdfPage collects the content stream of one page. Text uses the standard
+// Helvetica fonts with WinAnsi encoding, so no font has to be embedded;
+// characters outside Windows-1252 are drawn as "?".
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(x, y, size float64, bold bool, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfString(value))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %s %s m %s %s l S\n", pdfNumber(x1), pdfNumber(y1), pdfNumber(x2), pdfNumber(y2))
+}
+
+// writePDF writes a complete PDF 1.4 file. Objects are numbered in writing
+// order: catalog, page tree, the two fonts, the info dictionary, and then a
+// page and its content stream per page.
+func writePDF(w io.Writer, title string, created time.Time, pages []*pdfPage) error {
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	offsets := make([]int64, 0, 5+2*len(pages))
+	object := func(body string) {
+		offsets = append(offsets, out.written)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]byte, 0, 8*len(pages))
+	for index := range pages {
+		kids = fmt.Appendf(kids, "%d 0 R ", 6+2*index)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (money-manager-server) /CreationDate (D:%s) >>",
+		pdfString(title), created.UTC().Format("20060102150405Z")))
+	for index, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*index))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.Bytes()))
+	}
+	xref := out.written
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// pdfString is a literal string in Windows-1252, with delimiters escaped and
+// bytes outside printable ASCII written as octal escapes.
+func pdfString(value string) string {
+	var result bytes.Buffer
+	result.WriteByte('(')
+	for _, r := range value {
+		encoded, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			encoded = '?'
+		}
+		switch {
+		case encoded == '(' || encoded == ')' || encoded == '\\':
+			result.WriteByte('\\')
+			result.WriteByte(encoded)
+		case encoded < 0x20 || encoded > 0x7e:
+			fmt.Fprintf(&result, "\\%03o", encoded)
+		default:
+			result.WriteByte(encoded)
+		}
+	}
+	result.WriteByte(')')
+	return result.String()
+}
+
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// countingWriter tracks the byte offset of every object for the xref table
+// and keeps the first write error, so writePDF checks it once at the end.
+type countingWriter struct {
+	w       *bufio.Writer
+	written int64
+	err     error
+}
+
+func (c *countingWriter) Write(contents []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(contents)
+	c.written += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(value string) {
+	_, _ = c.Write([]byte(value))
+}
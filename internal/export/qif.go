@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+// qifDateLayout is the month-first date order Quicken writes and importers
+// assume when a file is ambiguous.
+const qifDateLayout = "01/02/2006"
+
+// qifEncoder writes a QIF bank register. The category goes in the L field.
+type qifEncoder struct{ buffer *bufio.Writer }
+
+func newQIFEncoder(w io.Writer, _ Options) (Encoder, error) {
+	encoder := &qifEncoder{buffer: bufio.NewWriter(w)}
+	_, err := encoder.buffer.WriteString("!Type:Bank\n")
+	return encoder, err
+}
+
+func (e *qifEncoder) Encode(transaction model.Transaction) error {
+	amount := transaction.Amount
+	if transaction.Type == "expense" {
+		amount = "-" + amount
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "D%s\nT%s\n", qifDate(transaction.OccurredAt), amount)
+	if description := oneLine(transaction.Description); description != "" {
+		fmt.Fprintf(&builder, "P%s\n", description)
+	}
+	if category := oneLine(transaction.Category); category != "" {
+		fmt.Fprintf(&builder, "L%s\n", category)
+	}
+	builder.WriteString("^\n")
+	_, err := e.buffer.WriteString(builder.String())
+	return err
+}
+
+func (e *qifEncoder) Close() error {
+	return e.buffer.Flush()
+}
+
+// tradeQIFEncoder writes a QIF investment register with Buy and Sell
+// actions. The security is named by its symbol.
+type tradeQIFEncoder struct{ buffer *bufio.Writer }
+
+func newTradeQIFEncoder(w io.Writer, _ Options) (TradeEncoder, error) {
+	encoder := &tradeQIFEncoder{buffer: bufio.NewWriter(w)}
+	_, err := encoder.buffer.WriteString("!Type:Invst\n")
+	return encoder, err
+}
+
+func (e *tradeQIFEncoder) Encode(trade model.InvestmentTrade) error {
+	action := "Sell"
+	if trade.Side == "buy" {
+		action = "Buy"
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "D%s\nN%s\nY%s\n", qifDate(trade.OccurredAt), action, oneLine(strings.ToUpper(trade.Symbol)))
+	if trade.PricePerUnit != "" {
+		fmt.Fprintf(&builder, "I%s\n", decimalString(decimal(trade.PricePerUnit)))
+	}
+	if trade.Quantity != "" {
+		fmt.Fprintf(&builder, "Q%s\n", decimalString(decimal(trade.Quantity)))
+	}
+	fmt.Fprintf(&builder, "T%s\nO%s\n", decimal(trade.Amount).FloatString(2), decimal(trade.Fees).FloatString(2))
+	if notes := oneLine(trade.Notes); notes != "" {
+		fmt.Fprintf(&builder, "M%s\n", notes)
+	}
+	builder.WriteString("^\n")
+	_, err := e.buffer.WriteString(builder.String())
+	return err
+}
+
+func (e *tradeQIFEncoder) Close() error {
+	return e.buffer.Flush()
+}
+
+// qifDate converts YYYY-MM-DD or an RFC3339 time to the QIF date order.
+func qifDate(value string) string {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp.UTC().Format(qifDateLayout)
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.Format(qifDateLayout)
+	}
+	return value
+}
@@ -0,0 +1,284 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+
+	"money-manager-server/internal/model"
+)
+
+// maximumSheetRows is the row limit of an Excel worksheet.
+const maximumSheetRows = 1 << 20
+
+// workbook writes a minimal Office Open XML spreadsheet straight into a zip
+// stream. Sheets are written one after another and text uses inline strings,
+// so no shared-string table has to be held in memory.
+type workbook struct {
+	archive *zip.Writer
+	sheets  []string
+	sheet   *bufio.Writer
+	rows    int
+}
+
+// cell is one worksheet value. Numbers must be plain decimal strings.
+type cell struct {
+	value  string
+	number bool
+}
+
+func text(value string) cell   { return cell{value: value} }
+func number(value string) cell { return cell{value: value, number: true} }
+
+func newWorkbook(w io.Writer) *workbook {
+	return &workbook{archive: zip.NewWriter(w)}
+}
+
+func (b *workbook) startSheet(name string, header ...string) error {
+	if err := b.finishSheet(); err != nil {
+		return err
+	}
+	b.sheets = append(b.sheets, name)
+	entry, err := b.archive.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(b.sheets)))
+	if err != nil {
+		return err
+	}
+	b.sheet, b.rows = bufio.NewWriter(entry), 0
+	if _, err := b.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`); err != nil {
+		return err
+	}
+	cells := make([]cell, 0, len(header))
+	for _, title := range header {
+		cells = append(cells, text(title))
+	}
+	return b.writeRow(1, cells...)
+}
+
+func (b *workbook) row(cells ...cell) error {
+	return b.writeRow(0, cells...)
+}
+
+func (b *workbook) writeRow(style int, cells ...cell) error {
+	if b.rows >= maximumSheetRows {
+		return errors.New("xlsx worksheets hold at most 1,048,576 rows; use csv or jsonl")
+	}
+	b.rows++
+	var builder strings.Builder
+	builder.WriteString("<row>")
+	for _, value := range cells {
+		styleAttribute := ""
+		if style > 0 {
+			styleAttribute = fmt.Sprintf(` s="%d"`, style)
+		}
+		if value.number {
+			fmt.Fprintf(&builder, `<c%s><v>%s</v></c>`, styleAttribute, value.value)
+			continue
+		}
+		fmt.Fprintf(&builder, `<c t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`, styleAttribute, xmlText(value.value))
+	}
+	builder.WriteString("</row>")
+	_, err := b.sheet.WriteString(builder.String())
+	return err
+}
+
+func (b *workbook) finishSheet() error {
+	if b.sheet == nil {
+		return nil
+	}
+	if _, err := b.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	err := b.sheet.Flush()
+	b.sheet = nil
+	return err
+}
+
+func (b *workbook) close() error {
+	if err := b.finishSheet(); err != nil {
+		return err
+	}
+	var sheets, relationships, overrides strings.Builder
+	for index, name := range b.sheets {
+		id := index + 1
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlText(name), id, id)
+		fmt.Fprintf(&relationships, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, id, id)
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id)
+	}
+	stylesID := len(b.sheets) + 1
+	files := []struct{ name, contents string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			overrides.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			relationships.String() +
+			fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, stylesID) +
+			`</Relationships>`},
+		{"xl/styles.xml", `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, file := range files {
+		entry, err := b.archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, xml.Header+file.contents); err != nil {
+			return err
+		}
+	}
+	return b.archive.Close()
+}
+
+// xlsxEncoder writes a Transactions sheet and a Categories sheet with the
+// count and total of each type and category.
+type xlsxEncoder struct {
+	book   *workbook
+	totals map[[2]string]*categoryTotal
+}
+
+type categoryTotal struct {
+	count int
+	total *big.Rat
+}
+
+func newXLSXEncoder(w io.Writer, _ Options) (Encoder, error) {
+	encoder := &xlsxEncoder{book: newWorkbook(w), totals: map[[2]string]*categoryTotal{}}
+	return encoder, encoder.book.startSheet("Transactions",
+		"occurred_at", "type", "category", "description", "amount", "currency", "source", "status",
+		"excluded_from_budget", "tags",
+	)
+}
+
+func (e *xlsxEncoder) Encode(transaction model.Transaction) error {
+	key := [2]string{transaction.Type, transaction.Category}
+	total := e.totals[key]
+	if total == nil {
+		total = &categoryTotal{total: new(big.Rat)}
+		e.totals[key] = total
+	}
+	total.count++
+	total.total.Add(total.total, decimal(transaction.Amount))
+	excluded := "false"
+	if transaction.ExcludedFromBudget {
+		excluded = "true"
+	}
+	return e.book.row(
+		text(transaction.OccurredAt), text(transaction.Type), text(transaction.Category), text(transaction.Description),
+		number(decimal(transaction.Amount).FloatString(2)), text(transaction.Currency), text(transaction.Source),
+		text(transaction.Status), text(excluded), text(strings.Join(transaction.Tags, ", ")),
+	)
+}
+
+func (e *xlsxEncoder) Close() error {
+	if err := e.book.startSheet("Categories", "type", "category", "transactions", "total"); err != nil {
+		return err
+	}
+	keys := make([][2]string, 0, len(e.totals))
+	for key := range e.totals {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	})
+	for _, key := range keys {
+		total := e.totals[key]
+		if err := e.book.row(
+			text(key[0]), text(key[1]), number(fmt.Sprint(total.count)), number(total.total.FloatString(2)),
+		); err != nil {
+			return err
+		}
+	}
+	return e.book.close()
+}
+
+// tradeXLSXEncoder writes a Trades sheet and an Assets sheet with the bought
+// and sold amounts and net quantity of each asset.
+type tradeXLSXEncoder struct {
+	book   *workbook
+	assets map[[2]string]*assetTotal
+}
+
+type assetTotal struct {
+	trades   int
+	bought   *big.Rat
+	sold     *big.Rat
+	fees     *big.Rat
+	quantity *big.Rat
+}
+
+func newTradeXLSXEncoder(w io.Writer, _ Options) (TradeEncoder, error) {
+	encoder := &tradeXLSXEncoder{book: newWorkbook(w), assets: map[[2]string]*assetTotal{}}
+	return encoder, encoder.book.startSheet("Trades",
+		"occurred_at", "asset_type", "symbol", "asset_name", "exchange", "broker", "side",
+		"amount", "quantity", "price_per_unit", "fees", "currency", "notes",
+	)
+}
+
+func (e *tradeXLSXEncoder) Encode(trade model.InvestmentTrade) error {
+	key := [2]string{trade.AssetType, strings.ToUpper(trade.Symbol)}
+	total := e.assets[key]
+	if total == nil {
+		total = &assetTotal{bought: new(big.Rat), sold: new(big.Rat), fees: new(big.Rat), quantity: new(big.Rat)}
+		e.assets[key] = total
+	}
+	total.trades++
+	total.fees.Add(total.fees, decimal(trade.Fees))
+	if trade.Side == "buy" {
+		total.bought.Add(total.bought, decimal(trade.Amount))
+		total.quantity.Add(total.quantity, decimal(trade.Quantity))
+	} else {
+		total.sold.Add(total.sold, decimal(trade.Amount))
+		total.quantity.Sub(total.quantity, decimal(trade.Quantity))
+	}
+	return e.book.row(
+		text(trade.OccurredAt), text(trade.AssetType), text(trade.Symbol), text(trade.AssetName), text(trade.Exchange),
+		text(trade.Broker), text(trade.Side), number(decimal(trade.Amount).FloatString(2)),
+		number(decimalString(decimal(trade.Quantity))), number(decimalString(decimal(trade.PricePerUnit))),
+		number(decimal(trade.Fees).FloatString(2)), text(trade.Currency), text(trade.Notes),
+	)
+}
+
+func (e *tradeXLSXEncoder) Close() error {
+	if err := e.book.startSheet("Assets", "asset_type", "symbol", "trades", "bought", "sold", "fees", "net_quantity"); err != nil {
+		return err
+	}
+	keys := make([][2]string, 0, len(e.assets))
+	for key := range e.assets {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	})
+	for _, key := range keys {
+		total := e.assets[key]
+		if err := e.book.row(
+			text(key[0]), text(key[1]), number(fmt.Sprint(total.trades)), number(total.bought.FloatString(2)),
+			number(total.sold.FloatString(2)), number(total.fees.FloatString(2)), number(decimalString(total.quantity)),
+		); err != nil {
+			return err
+		}
+	}
+	return e.book.close()
+}
@@ -0,0 +1,154 @@
+// Package holidays bundles public holiday calendars and moves dates that fall
+// on a weekend or holiday to a business day.
+package holidays
+
+import (
+	"slices"
+	"time"
+)
+
+// Calendar is a bundled holiday calendar. The zero Calendar has no holidays,
+// so only weekends are non-business days.
+type Calendar struct {
+	Code     string
+	Name     string
+	holidays func(year int) []time.Time
+}
+
+var calendars = []Calendar{
+	{Code: "BG", Name: "Bulgaria", holidays: bulgarianHolidays},
+	{Code: "TARGET2", Name: "TARGET2 euro payments", holidays: target2Holidays},
+}
+
+// Lookup returns the bundled calendar with the given code.
+func Lookup(code string) (Calendar, bool) {
+	for _, calendar := range calendars {
+		if calendar.Code == code {
+			return calendar, true
+		}
+	}
+	return Calendar{}, false
+}
+
+// Codes lists the bundled calendar codes.
+func Codes() []string {
+	codes := make([]string, len(calendars))
+	for index, calendar := range calendars {
+		codes[index] = calendar.Code
+	}
+	return codes
+}
+
+func (calendar Calendar) IsHoliday(day time.Time) bool {
+	if calendar.holidays == nil {
+		return false
+	}
+	day = dateOnly(day)
+	return slices.ContainsFunc(calendar.holidays(day.Year()), day.Equal)
+}
+
+func (calendar Calendar) IsBusinessDay(day time.Time) bool {
+	weekday := day.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday && !calendar.IsHoliday(day)
+}
+
+// Adjust moves a day that is not a business day according to policy:
+// "previous" and "next" take the nearest business day before or after it,
+// and "modified_following" takes the next one unless that is in the
+// following month, in which case it takes the previous one. "none" and
+// unknown policies leave the day as it is.
+func (calendar Calendar) Adjust(day time.Time, policy string) time.Time {
+	day = dateOnly(day)
+	switch policy {
+	case "previous":
+		return calendar.step(day, -1)
+	case "next":
+		return calendar.step(day, 1)
+	case "modified_following":
+		if next := calendar.step(day, 1); next.Month() == day.Month() {
+			return next
+		}
+		return calendar.step(day, -1)
+	default:
+		return day
+	}
+}
+
+func (calendar Calendar) step(day time.Time, direction int) time.Time {
+	for !calendar.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, direction)
+	}
+	return day
+}
+
+// bulgarianHolidays follows the Labour Code: Orthodox Good Friday through
+// Easter Monday, and fixed holidays that move to the next working day when
+// they fall on a weekend. Extra days off declared by the government each
+// year are not included.
+func bulgarianHolidays(year int) []time.Time {
+	easter := orthodoxEaster(year)
+	days := []time.Time{
+		easter.AddDate(0, 0, -2), easter.AddDate(0, 0, -1), easter, easter.AddDate(0, 0, 1),
+	}
+	fixed := []time.Time{
+		date(year, time.January, 1), date(year, time.March, 3), date(year, time.May, 1),
+		date(year, time.May, 6), date(year, time.May, 24), date(year, time.September, 6),
+		date(year, time.September, 22), date(year, time.December, 24), date(year, time.December, 25),
+		date(year, time.December, 26),
+	}
+	days = append(days, fixed...)
+	for _, day := range fixed {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			continue
+		}
+		substitute := day.AddDate(0, 0, 1)
+		for substitute.Weekday() == time.Saturday || substitute.Weekday() == time.Sunday ||
+			slices.ContainsFunc(days, substitute.Equal) {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		days = append(days, substitute)
+	}
+	return days
+}
+
+// target2Holidays are the closing days of the TARGET2 payment system.
+func target2Holidays(year int) []time.Time {
+	easter := westernEaster(year)
+	return []time.Time{
+		date(year, time.January, 1), easter.AddDate(0, 0, -2), easter.AddDate(0, 0, 1),
+		date(year, time.May, 1), date(year, time.December, 25), date(year, time.December, 26),
+	}
+}
+
+// orthodoxEaster uses Meeus's Julian algorithm shifted to the Gregorian
+// calendar, which is valid from 1900 through 2099.
+func orthodoxEaster(year int) time.Time {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month, day := (d+e+114)/31, (d+e+114)%31+1
+	return date(year, time.Month(month), day).AddDate(0, 0, 13)
+}
+
+// westernEaster uses the anonymous Gregorian algorithm.
+func westernEaster(year int) time.Time {
+	a, b, c := year%19, year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month, day := (h+l-7*m+114)/31, (h+l-7*m+114)%31+1
+	return date(year, time.Month(month), day)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func dateOnly(value time.Time) time.Time {
+	year, month, day := value.Date()
+	return date(year, month, day)
+}
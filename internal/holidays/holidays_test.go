@@ -0,0 +1,84 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEasterDates(t *testing.T) {
+	tests := []struct {
+		got  time.Time
+		want string
+	}{
+		{orthodoxEaster(2026), "2026-04-12"},
+		{orthodoxEaster(2027), "2027-05-02"},
+		{westernEaster(2026), "2026-04-05"},
+		{westernEaster(2027), "2027-03-28"},
+	}
+	for _, test := range tests {
+		if got := test.got.Format(time.DateOnly); got != test.want {
+			t.Errorf("easter = %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestBulgarianHolidaysMoveOffWeekends(t *testing.T) {
+	calendar, ok := Lookup("BG")
+	if !ok {
+		t.Fatal("BG calendar is missing")
+	}
+	holidays := []string{
+		"2026-04-10", "2026-04-11", "2026-04-13", "2026-03-03",
+		// 6 September and 26 December 2026 fall on a weekend.
+		"2026-09-07", "2026-12-28",
+		// 1 May 2027 is a Saturday and 3 May is already Easter Monday.
+		"2027-05-04",
+	}
+	for _, value := range holidays {
+		if !calendar.IsHoliday(day(value)) {
+			t.Errorf("%s is not a Bulgarian holiday", value)
+		}
+	}
+	for _, value := range []string{"2026-09-08", "2026-12-29", "2027-05-05"} {
+		if !calendar.IsBusinessDay(day(value)) {
+			t.Errorf("%s is not a Bulgarian business day", value)
+		}
+	}
+}
+
+func TestAdjustPolicies(t *testing.T) {
+	target2, ok := Lookup("TARGET2")
+	if !ok {
+		t.Fatal("TARGET2 calendar is missing")
+	}
+	tests := []struct {
+		calendar Calendar
+		day      string
+		policy   string
+		want     string
+	}{
+		{target2, "2026-04-03", "next", "2026-04-07"},
+		{target2, "2026-04-06", "previous", "2026-04-02"},
+		{target2, "2026-05-01", "none", "2026-05-01"},
+		{target2, "2026-05-01", "next", "2026-05-04"},
+		{target2, "2026-05-31", "modified_following", "2026-05-29"},
+		{target2, "2026-05-30", "modified_following", "2026-05-29"},
+		{target2, "2026-08-15", "modified_following", "2026-08-17"},
+		{Calendar{}, "2026-07-18", "next", "2026-07-20"},
+		{Calendar{}, "2026-07-21", "previous", "2026-07-21"},
+	}
+	for _, test := range tests {
+		got := test.calendar.Adjust(day(test.day), test.policy).Format(time.DateOnly)
+		if got != test.want {
+			t.Errorf("Adjust(%s, %s) with %q = %s, want %s", test.day, test.policy, test.calendar.Code, got, test.want)
+		}
+	}
+}
+
+func day(value string) time.Time {
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,112 @@
+package merchant
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaximumNameRunes bounds merchant names, matching the column constraint.
+const MaximumNameRunes = 100
+
+// processorPrefixes are payment facilitators that prepend their own name to
+// the merchant's, as in "SQ *BLUE BOTTLE" or "PAYPAL *STEAM GAMES".
+var processorPrefixes = []string{
+	"paypal *", "paypal*", "pp*", "sq *", "sq*", "sumup *", "sumup*", "zettle_*", "iz *", "iz*",
+	"sp *", "sp*", "crv*", "mypos*", "vivawallet*", "viva*", "stripe*", "www.",
+}
+
+// countryCodes are ISO 3166 codes card networks append to a merchant
+// location. Only a trailing code is removed.
+var countryCodes = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CH": true, "CY": true, "CZ": true, "DE": true, "DK": true,
+	"EE": true, "ES": true, "FI": true, "FR": true, "GB": true, "GR": true, "HR": true, "HU": true,
+	"IE": true, "IS": true, "IT": true, "LT": true, "LU": true, "LV": true, "MK": true, "MT": true,
+	"NL": true, "NO": true, "PL": true, "PT": true, "RO": true, "RS": true, "SE": true, "SI": true,
+	"SK": true, "TR": true, "UA": true, "UK": true, "US": true, "USA": true, "BGR": true, "DEU": true,
+	"GBR": true, "IRL": true, "NLD": true, "LUX": true,
+}
+
+// cities are trailing locations removed from card descriptions. Names are
+// lower case and may span several words.
+var cities = []string{
+	"sofia", "sofiya", "plovdiv", "varna", "burgas", "ruse", "stara zagora", "pleven", "sliven",
+	"dobrich", "shumen", "pernik", "haskovo", "yambol", "pazardzhik", "blagoevgrad", "veliko tarnovo",
+	"vratsa", "gabrovo", "bansko", "london", "dublin", "paris", "berlin", "munich", "frankfurt",
+	"amsterdam", "brussels", "luxembourg", "vienna", "zurich", "madrid", "barcelona", "lisbon", "rome",
+	"milan", "prague", "budapest", "bucharest", "warsaw", "athens", "thessaloniki", "istanbul",
+	"belgrade", "skopje", "new york", "san francisco",
+}
+
+// Normalize reduces a raw bank description to a merchant. Key is the lower
+// case form used to match aliases and Name a readable display name. Both are
+// empty when nothing recognisable remains, such as for a bare reference.
+//
+// A payment processor prefix is removed, then every word containing a digit
+// (store numbers, terminal IDs, dates), then a trailing country code and
+// city: "LIDL 1234 SOFIA BG" becomes "lidl" and "Lidl".
+func Normalize(description string) (key, name string) {
+	description = strings.TrimSpace(description)
+	lower := strings.ToLower(description)
+	for _, prefix := range processorPrefixes {
+		if strings.HasPrefix(lower, prefix) && len(lower) > len(prefix) {
+			description = description[len(prefix):]
+			break
+		}
+	}
+	words := make([]string, 0)
+	for _, field := range strings.FieldsFunc(description, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '*' || r == '|'
+	}) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '\''
+		})
+		if word == "" || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) > 1 && countryCodes[strings.ToUpper(words[len(words)-1])] {
+		words = words[:len(words)-1]
+	}
+	words = trimCity(words)
+	if len(words) == 0 {
+		return "", ""
+	}
+	keyWords := make([]string, len(words))
+	nameWords := make([]string, len(words))
+	for index, word := range words {
+		keyWords[index] = strings.ToLower(word)
+		nameWords[index] = displayWord(word)
+	}
+	name = strings.Join(nameWords, " ")
+	if utf8.RuneCountInString(name) > MaximumNameRunes {
+		name = strings.TrimSpace(string([]rune(name)[:MaximumNameRunes]))
+	}
+	return strings.Join(keyWords, " "), name
+}
+
+func trimCity(words []string) []string {
+	for _, city := range cities {
+		cityWords := strings.Fields(city)
+		if len(words) <= len(cityWords) {
+			continue
+		}
+		tail := words[len(words)-len(cityWords):]
+		if strings.EqualFold(strings.Join(tail, " "), city) {
+			return words[:len(words)-len(cityWords)]
+		}
+	}
+	return words
+}
+
+// displayWord capitalizes a word the bank sent in all caps and keeps any
+// other casing, so "NETFLIX.COM" reads "Netflix.com" but "eBay" is kept.
+func displayWord(word string) string {
+	if strings.ToUpper(word) != word {
+		return word
+	}
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
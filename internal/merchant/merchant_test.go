@@ -0,0 +1,28 @@
+package merchant
+
+import "testing"
+
+func TestNormalizeStripsBankNoise(t *testing.T) {
+	tests := []struct {
+		description, key, name string
+	}{
+		{"LIDL 1234 SOFIA BG", "lidl", "Lidl"},
+		{"Lidl Bulgaria EOOD 0042 Veliko Tarnovo", "lidl bulgaria eood", "Lidl Bulgaria Eood"},
+		{"SQ *BLUE BOTTLE COFFEE", "blue bottle coffee", "Blue Bottle Coffee"},
+		{"PAYPAL *STEAM GAMES 4029357733 LU", "steam games", "Steam Games"},
+		{"NETFLIX.COM 866-579-7172 NL", "netflix.com", "Netflix.com"},
+		{"AMAZON.DE*AB12CD34E", "amazon.de", "Amazon.de"},
+		{"Happy Bar & Grill", "happy bar & grill", "Happy Bar & Grill"},
+		{"eBay O*12-34567-89012", "ebay o", "eBay O"},
+		{"BG", "bg", "Bg"},
+		{"Sofia", "sofia", "Sofia"},
+		{"  ", "", ""},
+		{"2026-07-14 123456", "", ""},
+	}
+	for _, test := range tests {
+		key, name := Normalize(test.description)
+		if key != test.key || name != test.name {
+			t.Errorf("Normalize(%q) = %q, %q; want %q, %q", test.description, key, name, test.key, test.name)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+// CategorizationRuleConditions must all hold for a rule to match. Empty
+// fields are ignored; a rule needs at least one condition.
+type CategorizationRuleConditions struct {
+	DescriptionContains  string `json:"description_contains,omitempty"`
+	DescriptionPattern   string `json:"description_pattern,omitempty"`
+	AmountMin            string `json:"amount_min,omitempty"`
+	AmountMax            string `json:"amount_max,omitempty"`
+	AccountID            *int   `json:"account_id,omitempty"`
+	MerchantCategoryCode string `json:"merchant_category_code,omitempty"`
+	Type                 string `json:"type,omitempty"`
+}
+
+// CategorizationRuleActions are applied to a matching transaction. Marking
+// a transfer excludes it from budgets and tags it "transfer".
+type CategorizationRuleActions struct {
+	Category          string   `json:"category,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	ExcludeFromBudget bool     `json:"exclude_from_budget,omitempty"`
+	MarkAsTransfer    bool     `json:"mark_as_transfer,omitempty"`
+}
+
+type CategorizationRule struct {
+	ID         int                          `json:"id"`
+	Name       string                       `json:"name"`
+	Priority   int                          `json:"priority"`
+	Enabled    bool                         `json:"enabled"`
+	Conditions CategorizationRuleConditions `json:"conditions"`
+	Actions    CategorizationRuleActions    `json:"actions"`
+	CreatedAt  string                       `json:"created_at"`
+	UpdatedAt  string                       `json:"updated_at"`
+}
+
+type CategorizationRuleRequest struct {
+	Name       string                       `json:"name"`
+	Priority   int                          `json:"priority"`
+	Enabled    *bool                        `json:"enabled,omitempty"`
+	Conditions CategorizationRuleConditions `json:"conditions"`
+	Actions    CategorizationRuleActions    `json:"actions"`
+}
+
+// CategorizationRuleRunRequest re-applies the rules to booked history.
+type CategorizationRuleRunRequest struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+type CategorizationRuleRunResult struct {
+	Scanned int `json:"scanned"`
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,30 @@
+package model
+
+// CategoryModel summarizes what the per-user category classifier learned
+// from manual entries and category corrections.
+type CategoryModel struct {
+	Examples int                  `json:"examples"`
+	Classes  []CategoryModelClass `json:"classes"`
+}
+
+// CategoryModelClass is one learned category. Tokens are its most frequent
+// description words, most frequent first.
+type CategoryModelClass struct {
+	Type     string   `json:"type"`
+	Category string   `json:"category"`
+	Examples int      `json:"examples"`
+	Tokens   []string `json:"tokens"`
+}
+
+// CategoryPrediction is the classifier's best guess. Applied reports whether
+// the confidence is high enough for imports and syncs to use it.
+type CategoryPrediction struct {
+	Type       string  `json:"type"`
+	Category   string  `json:"category,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Applied    bool    `json:"applied"`
+}
+
+type CategoryModelReset struct {
+	Forgotten int `json:"forgotten"`
+}
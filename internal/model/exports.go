@@ -0,0 +1,42 @@
+package model
+
+type TransactionExportRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Format string `json:"format"`
+}
+
+type InvestmentExportRequest struct {
+	From    string `json:"from"`
+	Through string `json:"through"`
+	Format  string `json:"format"`
+}
+
+// TransactionExport is a background export job. Completed files can be
+// downloaded until ExpiresAt.
+type TransactionExport struct {
+	ID          int    `json:"id"`
+	Format      string `json:"format"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Status      string `json:"status"`
+	Rows        int    `json:"rows"`
+	Bytes       int64  `json:"bytes"`
+	Error       string `json:"error,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// ExportFile describes a streamed download before its first byte is written.
+type ExportFile struct {
+	Filename    string
+	ContentType string
+}
+
+type TransactionExportMaintenanceResult struct {
+	Claimed   int `json:"claimed"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Expired   int `json:"expired"`
+}
@@ -0,0 +1,71 @@
+package model
+
+// ForecastSettings hold the balance below which a forecast warns. An empty
+// threshold turns the warning off.
+type ForecastSettings struct {
+	LowBalanceThreshold string `json:"low_balance_threshold"`
+}
+
+// ForecastDay is the expected end-of-day balance with a 90% band around it.
+type ForecastDay struct {
+	Date    string `json:"date"`
+	Balance string `json:"balance"`
+	Low     string `json:"low"`
+	High    string `json:"high"`
+}
+
+// ForecastAccount projects one linked bank account from its latest recorded
+// balance. Flows that cannot be tied to a bank account are collected in an
+// unassigned entry without an account ID, which starts at zero.
+type ForecastAccount struct {
+	AccountID       *int          `json:"account_id,omitempty"`
+	Name            string        `json:"name"`
+	BalanceDate     string        `json:"balance_date,omitempty"`
+	StartingBalance string        `json:"starting_balance"`
+	Days            []ForecastDay `json:"days"`
+}
+
+// ForecastFlow is one known future income or expense. Amount is signed:
+// negative for money leaving. Source is schedule, subscription, or
+// investment_schedule.
+type ForecastFlow struct {
+	Date      string `json:"date"`
+	Source    string `json:"source"`
+	Name      string `json:"name"`
+	Category  string `json:"category,omitempty"`
+	Amount    string `json:"amount"`
+	Estimated bool   `json:"estimated,omitempty"`
+	AccountID *int   `json:"account_id,omitempty"`
+}
+
+// ForecastCategoryRate is the average daily amount of unplanned income or
+// spending in a category, learned from recent history.
+type ForecastCategoryRate struct {
+	Type         string `json:"type"`
+	Category     string `json:"category"`
+	DailyAverage string `json:"daily_average"`
+}
+
+// ForecastWarning marks the first day the total balance is expected, or at
+// the low end of its band may be, below the threshold.
+type ForecastWarning struct {
+	Type      string `json:"type"`
+	Date      string `json:"date"`
+	Balance   string `json:"balance"`
+	Threshold string `json:"threshold"`
+}
+
+// Forecast projects daily balances from today through a future date. Today
+// is the starting point; projected flows begin tomorrow.
+type Forecast struct {
+	From                string                 `json:"from"`
+	Through             string                 `json:"through"`
+	Currency            string                 `json:"currency"`
+	LowBalanceThreshold string                 `json:"low_balance_threshold,omitempty"`
+	StartingBalance     string                 `json:"starting_balance"`
+	Days                []ForecastDay          `json:"days"`
+	Accounts            []ForecastAccount      `json:"accounts"`
+	Flows               []ForecastFlow         `json:"flows"`
+	VariableRates       []ForecastCategoryRate `json:"variable_rates"`
+	Warnings            []ForecastWarning      `json:"warnings"`
+}
@@ -0,0 +1,111 @@
+package model
+
+type CSVImportColumns struct {
+	Date        string `json:"date"`
+	Amount      string `json:"amount,omitempty"`
+	Debit       string `json:"debit,omitempty"`
+	Credit      string `json:"credit,omitempty"`
+	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	Currency    string `json:"currency,omitempty"`
+}
+
+type CSVImportProfile struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	Delimiter        string           `json:"delimiter"`
+	Encoding         string           `json:"encoding"`
+	DateFormat       string           `json:"date_format"`
+	DecimalSeparator string           `json:"decimal_separator"`
+	Columns          CSVImportColumns `json:"columns"`
+	CreatedAt        string           `json:"created_at"`
+	UpdatedAt        string           `json:"updated_at"`
+}
+
+type CSVImportProfileRequest struct {
+	Name             string           `json:"name"`
+	Delimiter        string           `json:"delimiter"`
+	Encoding         string           `json:"encoding"`
+	DateFormat       string           `json:"date_format"`
+	DecimalSeparator string           `json:"decimal_separator"`
+	Columns          CSVImportColumns `json:"columns"`
+}
+
+// CSVImportDetection describes an uploaded file so the client can confirm or
+// correct the suggested mapping before saving it as a profile.
+type CSVImportDetection struct {
+	Delimiter        string           `json:"delimiter"`
+	Encoding         string           `json:"encoding"`
+	DateFormat       string           `json:"date_format,omitempty"`
+	DecimalSeparator string           `json:"decimal_separator"`
+	Headers          []string         `json:"headers"`
+	SampleRows       [][]string       `json:"sample_rows"`
+	Columns          CSVImportColumns `json:"columns"`
+}
+
+// ImportPreviewRequest selects the importer for a dry run. ProfileID applies
+// to generic CSV files and DateFormat to QIF files.
+type ImportPreviewRequest struct {
+	Format     string
+	ProfileID  int
+	DateFormat string
+}
+
+// ImportPreview is a dry run of one statement file. It is kept on the server
+// until it expires so the reviewed rows can be committed without uploading
+// the file again.
+type ImportPreview struct {
+	ID        int                 `json:"id"`
+	Source    string              `json:"source"`
+	Counts    ImportPreviewCounts `json:"counts"`
+	Rows      []ImportPreviewRow  `json:"rows"`
+	CreatedAt string              `json:"created_at"`
+	ExpiresAt string              `json:"expires_at"`
+}
+
+type ImportPreviewCounts struct {
+	New       int `json:"new"`
+	Duplicate int `json:"duplicate"`
+	Ignored   int `json:"ignored"`
+	Rejected  int `json:"rejected"`
+}
+
+// ImportPreviewRow is one parsed row. Status is new, duplicate (already
+// imported from an earlier file), ignored, or rejected; Reason explains the
+// last two. ClassificationSource is annotation, rule, learned,
+// expense_keyword, income_keyword, or fallback; learned rows carry the
+// classifier's confidence.
+type ImportPreviewRow struct {
+	Row                  int    `json:"row"`
+	Status               string `json:"status"`
+	Type                 string `json:"type,omitempty"`
+	Category             string `json:"category,omitempty"`
+	ClassificationSource string `json:"classification_source,omitempty"`
+	// ClassificationConfidence is between 0 and 1.
+	ClassificationConfidence float64 `json:"classification_confidence,omitempty"`
+	Description              string  `json:"description,omitempty"`
+	Amount                   string  `json:"amount,omitempty"`
+	Currency                 string  `json:"currency,omitempty"`
+	OccurredAt               string  `json:"occurred_at,omitempty"`
+	// Tags and ExcludedFromBudget are added by matching categorization rules.
+	Tags               []string `json:"tags,omitempty"`
+	ExcludedFromBudget bool     `json:"excluded_from_budget,omitempty"`
+	Reason             string   `json:"reason,omitempty"`
+}
+
+type ImportCommitRequest struct {
+	Overrides []ImportRowOverride `json:"overrides"`
+}
+
+// ImportRowOverride changes one new row before it is committed. Skip leaves
+// the row out; Type and Category replace the proposed classification.
+type ImportRowOverride struct {
+	Row      int    `json:"row"`
+	Type     string `json:"type,omitempty"`
+	Category string `json:"category,omitempty"`
+	Skip     bool   `json:"skip,omitempty"`
+}
+
+type ImportPreviewMaintenanceResult struct {
+	Expired int `json:"expired"`
+}
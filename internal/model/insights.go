@@ -0,0 +1,26 @@
+package model
+
+// Subscription is a periodic charge detected in booked expense history.
+// Merchant is the normalized key the charges were grouped by; Amount is the
+// median charge and AnnualCost that amount times the charges in a year.
+type Subscription struct {
+	Merchant         string `json:"merchant"`
+	Description      string `json:"description"`
+	Category         string `json:"category"`
+	Frequency        string `json:"frequency"`
+	Amount           string `json:"amount"`
+	Currency         string `json:"currency"`
+	Charges          int    `json:"charges"`
+	FirstChargeDate  string `json:"first_charge_date"`
+	LastChargeDate   string `json:"last_charge_date"`
+	NextExpectedDate string `json:"next_expected_date"`
+	AnnualCost       string `json:"annual_cost"`
+}
+
+// SubscriptionScheduleRequest converts a detected subscription into a
+// transaction schedule. Name defaults to the latest charge description.
+type SubscriptionScheduleRequest struct {
+	Merchant string `json:"merchant"`
+	Name     string `json:"name,omitempty"`
+	AutoPost bool   `json:"auto_post"`
+}
@@ -127,46 +127,70 @@ type InvestmentMarketHistoryPrice struct {
 }
 
 type InvestmentSchedule struct {
-	ID                  int    `json:"id"`
-	UserID              int    `json:"-"`
-	AssetType           string `json:"asset_type"`
-	Symbol              string `json:"symbol"`
-	AssetName           string `json:"asset_name"`
-	Exchange            string `json:"exchange,omitempty"`
-	MarketCurrency      string `json:"market_currency"`
-	Broker              string `json:"broker"`
-	Amount              string `json:"amount"`
-	Currency            string `json:"currency"`
-	Frequency           string `json:"frequency"`
-	FrequencyInterval   int    `json:"frequency_interval"`
-	StartDate           string `json:"start_date"`
-	EndDate             string `json:"end_date,omitempty"`
-	DayOfWeek           *int   `json:"day_of_week,omitempty"`
-	DayOfMonth          *int   `json:"day_of_month,omitempty"`
-	Timezone            string `json:"timezone"`
-	Status              string `json:"status"`
-	LastNotifiedOn      string `json:"last_notified_on,omitempty"`
-	MaterializedThrough string `json:"materialized_through,omitempty"`
-	LastPostedOn        string `json:"last_posted_on,omitempty"`
-	NextOccurrence      string `json:"next_occurrence,omitempty"`
-	CreatedAt           string `json:"created_at"`
-	UpdatedAt           string `json:"updated_at"`
+	ID                    int    `json:"id"`
+	UserID                int    `json:"-"`
+	AssetType             string `json:"asset_type"`
+	Symbol                string `json:"symbol"`
+	AssetName             string `json:"asset_name"`
+	Exchange              string `json:"exchange,omitempty"`
+	MarketCurrency        string `json:"market_currency"`
+	Broker                string `json:"broker"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	Frequency             string `json:"frequency"`
+	FrequencyInterval     int    `json:"frequency_interval"`
+	StartDate             string `json:"start_date"`
+	EndDate               string `json:"end_date,omitempty"`
+	DayOfWeek             *int   `json:"day_of_week,omitempty"`
+	DayOfMonth            *int   `json:"day_of_month,omitempty"`
+	RRule                 string `json:"rrule,omitempty"`
+	BusinessDayAdjustment string `json:"business_day_adjustment"`
+	HolidayCalendar       string `json:"holiday_calendar,omitempty"`
+	Timezone              string `json:"timezone"`
+	Status                string `json:"status"`
+	LastNotifiedOn        string `json:"last_notified_on,omitempty"`
+	MaterializedThrough   string `json:"materialized_through,omitempty"`
+	LastPostedOn          string `json:"last_posted_on,omitempty"`
+	NextOccurrence        string `json:"next_occurrence,omitempty"`
+	CreatedAt             string `json:"created_at"`
+	UpdatedAt             string `json:"updated_at"`
 }
 
 type InvestmentScheduleRequest struct {
-	AssetType         string `json:"asset_type"`
-	Symbol            string `json:"symbol"`
-	AssetName         string `json:"asset_name"`
-	Exchange          string `json:"exchange,omitempty"`
-	MarketCurrency    string `json:"market_currency,omitempty"`
-	Broker            string `json:"broker"`
-	Amount            string `json:"amount"`
-	Currency          string `json:"currency,omitempty"`
-	Frequency         string `json:"frequency"`
-	FrequencyInterval int    `json:"frequency_interval,omitempty"`
-	StartDate         string `json:"start_date"`
-	EndDate           string `json:"end_date,omitempty"`
-	DayOfWeek         *int   `json:"day_of_week,omitempty"`
-	DayOfMonth        *int   `json:"day_of_month,omitempty"`
-	Timezone          string `json:"timezone,omitempty"`
+	AssetType             string `json:"asset_type"`
+	Symbol                string `json:"symbol"`
+	AssetName             string `json:"asset_name"`
+	Exchange              string `json:"exchange,omitempty"`
+	MarketCurrency        string `json:"market_currency,omitempty"`
+	Broker                string `json:"broker"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency,omitempty"`
+	Frequency             string `json:"frequency"`
+	FrequencyInterval     int    `json:"frequency_interval,omitempty"`
+	StartDate             string `json:"start_date"`
+	EndDate               string `json:"end_date,omitempty"`
+	DayOfWeek             *int   `json:"day_of_week,omitempty"`
+	DayOfMonth            *int   `json:"day_of_month,omitempty"`
+	RRule                 string `json:"rrule,omitempty"`
+	BusinessDayAdjustment string `json:"business_day_adjustment,omitempty"`
+	HolidayCalendar       string `json:"holiday_calendar,omitempty"`
+	Timezone              string `json:"timezone,omitempty"`
+}
+
+type InvestmentScheduleOccurrence struct {
+	ID           int    `json:"id"`
+	ScheduleID   int    `json:"schedule_id"`
+	ScheduledFor string `json:"scheduled_for"`
+	NominalDate  string `json:"nominal_date"`
+	Status       string `json:"status"`
+	Symbol       string `json:"symbol"`
+	AssetName    string `json:"asset_name"`
+	Amount       string `json:"amount"`
+	Currency     string `json:"currency"`
+	Overridden   bool   `json:"overridden"`
+}
+
+type InvestmentScheduleOccurrenceRequest struct {
+	ScheduledFor string `json:"scheduled_for"`
+	Amount       string `json:"amount"`
 }
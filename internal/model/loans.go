@@ -0,0 +1,84 @@
+package model
+
+// Loan is an amortizing loan repaid in equal monthly installments. The
+// computed fields assume every installment up to today was paid on its date.
+type Loan struct {
+	ID                     int    `json:"id"`
+	Name                   string `json:"name"`
+	Kind                   string `json:"kind"`
+	Principal              string `json:"principal"`
+	AnnualRate             string `json:"annual_rate"`
+	TermMonths             int    `json:"term_months"`
+	StartDate              string `json:"start_date"`
+	PaymentDay             int    `json:"payment_day"`
+	Currency               string `json:"currency"`
+	Status                 string `json:"status"`
+	MonthlyPayment         string `json:"monthly_payment"`
+	RemainingBalance       string `json:"remaining_balance"`
+	PrincipalPaid          string `json:"principal_paid"`
+	InterestPaidYearToDate string `json:"interest_paid_ytd"`
+	TotalInterest          string `json:"total_interest"`
+	NextPaymentDate        string `json:"next_payment_date,omitempty"`
+	PayoffDate             string `json:"payoff_date"`
+	CreatedAt              string `json:"created_at"`
+	UpdatedAt              string `json:"updated_at"`
+}
+
+type LoanRequest struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind,omitempty"`
+	Principal  string `json:"principal"`
+	AnnualRate string `json:"annual_rate"`
+	TermMonths int    `json:"term_months"`
+	StartDate  string `json:"start_date"`
+	PaymentDay int    `json:"payment_day,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+}
+
+// LoanInstallment is one row of an amortization schedule. ExtraPayment is
+// principal paid on top of the installment.
+type LoanInstallment struct {
+	Number        int    `json:"number"`
+	Date          string `json:"date"`
+	Payment       string `json:"payment"`
+	Principal     string `json:"principal"`
+	Interest      string `json:"interest"`
+	ExtraPayment  string `json:"extra_payment,omitempty"`
+	Balance       string `json:"balance"`
+	Paid          bool   `json:"paid"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+}
+
+// LoanPayment links a ledger transaction to a loan, or records an extra
+// principal payment made outside the ledger. Kind is installment or extra.
+type LoanPayment struct {
+	ID            int    `json:"id"`
+	Kind          string `json:"kind"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Amount        string `json:"amount"`
+	Date          string `json:"date"`
+}
+
+type LoanPaymentRequest struct {
+	Kind          string `json:"kind"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Amount        string `json:"amount,omitempty"`
+	Date          string `json:"date,omitempty"`
+}
+
+// LoanPayoffProjection compares the remaining schedule with one that adds
+// extra_monthly to every future installment and pays lump_sum today.
+type LoanPayoffProjection struct {
+	ExtraMonthly  string     `json:"extra_monthly"`
+	LumpSum       string     `json:"lump_sum"`
+	Baseline      LoanPayoff `json:"baseline"`
+	Scenario      LoanPayoff `json:"scenario"`
+	MonthsSaved   int        `json:"months_saved"`
+	InterestSaved string     `json:"interest_saved"`
+}
+
+type LoanPayoff struct {
+	PayoffDate        string `json:"payoff_date"`
+	RemainingPayments int    `json:"remaining_payments"`
+	RemainingInterest string `json:"remaining_interest"`
+}
@@ -0,0 +1,50 @@
+package model
+
+// MerchantMatch is the normalized form of a raw description. Key is matched
+// against merchant aliases; Name names a merchant created for a new key.
+type MerchantMatch struct {
+	Key  string
+	Name string
+}
+
+type Merchant struct {
+	ID               int      `json:"id"`
+	Name             string   `json:"name"`
+	Aliases          []string `json:"aliases"`
+	TransactionCount int      `json:"transaction_count"`
+}
+
+type MerchantRequest struct {
+	Name string `json:"name"`
+}
+
+type MerchantMergeRequest struct {
+	KeepID  int `json:"keep_id"`
+	MergeID int `json:"merge_id"`
+}
+
+type MerchantRefreshResult struct {
+	Assigned int `json:"assigned"`
+}
+
+// MerchantSpending totals the booked expenses of one merchant in a range.
+type MerchantSpending struct {
+	MerchantID   int    `json:"merchant_id"`
+	Name         string `json:"name"`
+	Transactions int    `json:"transactions"`
+	Total        string `json:"total"`
+	Currency     string `json:"currency"`
+}
+
+type MerchantMonthSpending struct {
+	Month        string `json:"month"`
+	Transactions int    `json:"transactions"`
+	Total        string `json:"total"`
+}
+
+type MerchantSpendingDetail struct {
+	MerchantSpending
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Months []MerchantMonthSpending `json:"months"`
+}
@@ -86,6 +86,7 @@ type OpenBankingSyncResult struct {
 	Unchanged     int `json:"unchanged"`
 	Ignored       int `json:"ignored"`
 	Notifications int `json:"notifications"`
+	Reconciled    int `json:"reconciled"`
 }
 
 type OpenBankingMaintenanceResult struct {
@@ -95,6 +96,7 @@ type OpenBankingMaintenanceResult struct {
 	Imported      int `json:"imported"`
 	Updated       int `json:"updated"`
 	Notifications int `json:"notifications"`
+	Reconciled    int `json:"reconciled"`
 }
 
 type OpenBankingPSUContext struct {
@@ -108,3 +110,13 @@ type OpenBankingPSUContext struct {
 }
 
 type OpenBankingProviderData = json.RawMessage
+
+// OpenBankingAccountBalance is a booked balance reported by the bank at the
+// end of ReferenceDate.
+type OpenBankingAccountBalance struct {
+	AccountID     int    `json:"account_id"`
+	ReferenceDate string `json:"reference_date"`
+	Type          string `json:"balance_type"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+}
@@ -0,0 +1,139 @@
+package model
+
+// ReportRequest selects an inclusive month range, YYYY-MM, for a report.
+// Both ends default to a twelve-month range ending this month. Type applies
+// to category reports and Limit to merchant reports.
+type ReportRequest struct {
+	From  string
+	To    string
+	Type  string
+	Limit int
+}
+
+// CashFlowTotals are booked income and expenses with Net = Income - Expense.
+type CashFlowTotals struct {
+	Income  string `json:"income"`
+	Expense string `json:"expense"`
+	Net     string `json:"net"`
+}
+
+type CashFlowMonth struct {
+	Month string `json:"month"`
+	CashFlowTotals
+}
+
+// CashFlowComparison is a reference range of the same length as the report.
+// Change percentages are relative to it and omitted when it had nothing.
+type CashFlowComparison struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	CashFlowTotals
+	IncomeChangePercent  string `json:"income_change_percent,omitempty"`
+	ExpenseChangePercent string `json:"expense_change_percent,omitempty"`
+}
+
+// CashFlowReport lists every month of the range, including empty ones, so
+// charts can plot it directly.
+type CashFlowReport struct {
+	From               string             `json:"from"`
+	To                 string             `json:"to"`
+	Currency           string             `json:"currency"`
+	Months             []CashFlowMonth    `json:"months"`
+	Total              CashFlowTotals     `json:"total"`
+	PreviousPeriod     CashFlowComparison `json:"previous_period"`
+	SamePeriodLastYear CashFlowComparison `json:"same_period_last_year"`
+}
+
+// CategoryShare is one top-level category's amount and percentage of the
+// total; subcategories are rolled into their parent.
+type CategoryShare struct {
+	Category     string `json:"category"`
+	Amount       string `json:"amount"`
+	SharePercent string `json:"share_percent"`
+}
+
+type CategoryReportMonth struct {
+	Month      string          `json:"month"`
+	Total      string          `json:"total"`
+	Categories []CategoryShare `json:"categories"`
+}
+
+// CategoryReportItem is a category over the whole range compared with the
+// previous period and the same period last year.
+type CategoryReportItem struct {
+	CategoryShare
+	PreviousAmount        string `json:"previous_amount"`
+	LastYearAmount        string `json:"last_year_amount"`
+	PreviousChangePercent string `json:"previous_change_percent,omitempty"`
+	LastYearChangePercent string `json:"last_year_change_percent,omitempty"`
+}
+
+type CategoryReport struct {
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	Type       string                `json:"type"`
+	Currency   string                `json:"currency"`
+	Total      string                `json:"total"`
+	Categories []CategoryReportItem  `json:"categories"`
+	Months     []CategoryReportMonth `json:"months"`
+}
+
+// MerchantReportItem is a merchant's booked expenses in the range. Its share
+// is of all expenses in the range, including rows without a merchant.
+type MerchantReportItem struct {
+	MerchantID     int    `json:"merchant_id"`
+	Name           string `json:"name"`
+	Transactions   int    `json:"transactions"`
+	Amount         string `json:"amount"`
+	SharePercent   string `json:"share_percent"`
+	PreviousAmount string `json:"previous_amount"`
+	LastYearAmount string `json:"last_year_amount"`
+}
+
+type MerchantReport struct {
+	From      string               `json:"from"`
+	To        string               `json:"to"`
+	Currency  string               `json:"currency"`
+	Total     string               `json:"total"`
+	Merchants []MerchantReportItem `json:"merchants"`
+}
+
+// AnnualReport is one calendar year at a glance. The current year covers
+// January through this month; budget periods count once they have ended.
+type AnnualReport struct {
+	Year               int                  `json:"year"`
+	Currency           string               `json:"currency"`
+	Totals             CashFlowTotals       `json:"totals"`
+	SavingsRatePercent string               `json:"savings_rate_percent,omitempty"`
+	Months             []CashFlowMonth      `json:"months"`
+	TopSpendingMonth   *CashFlowMonth       `json:"top_spending_month,omitempty"`
+	TopCategories      []CategoryShare      `json:"top_categories"`
+	TopMerchants       []MerchantReportItem `json:"top_merchants"`
+	Subscriptions      AnnualSubscriptions  `json:"subscriptions"`
+	Investments        AnnualInvestments    `json:"investments"`
+	Budgets            AnnualBudgets        `json:"budgets"`
+}
+
+// AnnualSubscriptions is what recurring charges detected within the year
+// cost in that year.
+type AnnualSubscriptions struct {
+	Count int    `json:"count"`
+	Total string `json:"total"`
+}
+
+// AnnualInvestments are the year's buys, including fees, and the profit or
+// loss realized by its sales against their average cost.
+type AnnualInvestments struct {
+	Trades         int    `json:"trades"`
+	Contributions  string `json:"contributions"`
+	RealizedProfit string `json:"realized_profit"`
+}
+
+// AnnualBudgets counts the ended periods of active budgets in the year and
+// how many of them stayed within their amount. The hit rate is omitted when
+// no period has ended.
+type AnnualBudgets struct {
+	Periods        int    `json:"periods"`
+	WithinBudget   int    `json:"within_budget"`
+	HitRatePercent string `json:"hit_rate_percent,omitempty"`
+}
@@ -0,0 +1,53 @@
+package model
+
+// SavingsGoal tracks saving toward a target amount by a target date.
+// SavedAmount adds manual contributions to the goal's tagged transactions
+// and transfers into its linked bank account since the start date.
+type SavingsGoal struct {
+	ID                          int    `json:"id"`
+	Name                        string `json:"name"`
+	TargetAmount                string `json:"target_amount"`
+	Currency                    string `json:"currency"`
+	StartDate                   string `json:"start_date"`
+	TargetDate                  string `json:"target_date"`
+	AccountID                   *int   `json:"account_id,omitempty"`
+	Tag                         string `json:"tag,omitempty"`
+	Status                      string `json:"status"`
+	SavedAmount                 string `json:"saved_amount"`
+	RemainingAmount             string `json:"remaining_amount"`
+	ProgressPercent             string `json:"progress_percent"`
+	ExpectedAmount              string `json:"expected_amount"`
+	RequiredMonthlyContribution string `json:"required_monthly_contribution"`
+	// ProgressStatus is completed, overdue, on_track, or behind.
+	ProgressStatus string `json:"progress_status"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type SavingsGoalRequest struct {
+	Name         string `json:"name"`
+	TargetAmount string `json:"target_amount"`
+	Currency     string `json:"currency,omitempty"`
+	StartDate    string `json:"start_date,omitempty"`
+	TargetDate   string `json:"target_date"`
+	AccountID    *int   `json:"account_id,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+// SavingsGoalContribution is one entry toward a goal. Source is manual,
+// tag, or account; only manual contributions have an ID and can be deleted.
+// Negative amounts are withdrawals.
+type SavingsGoalContribution struct {
+	ID            int    `json:"id,omitempty"`
+	Source        string `json:"source"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Amount        string `json:"amount"`
+	Date          string `json:"date"`
+	Note          string `json:"note,omitempty"`
+}
+
+type SavingsGoalContributionRequest struct {
+	Amount string `json:"amount"`
+	Date   string `json:"date,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
@@ -1,65 +1,96 @@
 package model
 
 type TransactionSchedule struct {
-	ID                  int    `json:"id"`
-	UserID              int    `json:"-"`
-	Type                string `json:"type"`
-	Name                string `json:"name"`
-	Category            string `json:"category"`
-	Description         string `json:"description"`
-	Amount              string `json:"amount"`
-	Currency            string `json:"currency"`
-	Frequency           string `json:"frequency"`
-	FrequencyInterval   int    `json:"frequency_interval"`
-	StartDate           string `json:"start_date"`
-	EndDate             string `json:"end_date,omitempty"`
-	DayOfWeek           *int   `json:"day_of_week,omitempty"`
-	DayOfMonth          *int   `json:"day_of_month,omitempty"`
-	Timezone            string `json:"timezone"`
-	AutoPost            bool   `json:"auto_post"`
-	Status              string `json:"status"`
-	MaterializedThrough string `json:"materialized_through,omitempty"`
-	NextOccurrenceDate  string `json:"next_occurrence_date,omitempty"`
-	CreatedAt           string `json:"created_at"`
-	UpdatedAt           string `json:"updated_at"`
+	ID                    int    `json:"id"`
+	UserID                int    `json:"-"`
+	Type                  string `json:"type"`
+	Name                  string `json:"name"`
+	Category              string `json:"category"`
+	Description           string `json:"description"`
+	Amount                string `json:"amount"`
+	AmountMode            string `json:"amount_mode"`
+	AmountMin             string `json:"amount_min,omitempty"`
+	AmountMax             string `json:"amount_max,omitempty"`
+	AverageWindow         *int   `json:"average_window,omitempty"`
+	Currency              string `json:"currency"`
+	Frequency             string `json:"frequency"`
+	FrequencyInterval     int    `json:"frequency_interval"`
+	StartDate             string `json:"start_date"`
+	EndDate               string `json:"end_date,omitempty"`
+	DayOfWeek             *int   `json:"day_of_week,omitempty"`
+	DayOfMonth            *int   `json:"day_of_month,omitempty"`
+	RRule                 string `json:"rrule,omitempty"`
+	BusinessDayAdjustment string `json:"business_day_adjustment"`
+	HolidayCalendar       string `json:"holiday_calendar,omitempty"`
+	Timezone              string `json:"timezone"`
+	AutoPost              bool   `json:"auto_post"`
+	Status                string `json:"status"`
+	MaterializedThrough   string `json:"materialized_through,omitempty"`
+	NextOccurrenceDate    string `json:"next_occurrence_date,omitempty"`
+	CreatedAt             string `json:"created_at"`
+	UpdatedAt             string `json:"updated_at"`
 }
 
 type TransactionScheduleRequest struct {
-	Type              string `json:"type"`
-	Name              string `json:"name"`
-	Category          string `json:"category"`
-	Description       string `json:"description"`
-	Amount            string `json:"amount"`
-	Currency          string `json:"currency"`
-	Frequency         string `json:"frequency"`
-	FrequencyInterval int    `json:"frequency_interval,omitempty"`
-	StartDate         string `json:"start_date"`
-	EndDate           string `json:"end_date,omitempty"`
-	DayOfWeek         *int   `json:"day_of_week,omitempty"`
-	DayOfMonth        *int   `json:"day_of_month,omitempty"`
-	Timezone          string `json:"timezone,omitempty"`
-	AutoPost          bool   `json:"auto_post"`
+	Type                  string `json:"type"`
+	Name                  string `json:"name"`
+	Category              string `json:"category"`
+	Description           string `json:"description"`
+	Amount                string `json:"amount"`
+	AmountMode            string `json:"amount_mode,omitempty"`
+	AmountMin             string `json:"amount_min,omitempty"`
+	AmountMax             string `json:"amount_max,omitempty"`
+	AverageWindow         *int   `json:"average_window,omitempty"`
+	Currency              string `json:"currency"`
+	Frequency             string `json:"frequency"`
+	FrequencyInterval     int    `json:"frequency_interval,omitempty"`
+	StartDate             string `json:"start_date"`
+	EndDate               string `json:"end_date,omitempty"`
+	DayOfWeek             *int   `json:"day_of_week,omitempty"`
+	DayOfMonth            *int   `json:"day_of_month,omitempty"`
+	RRule                 string `json:"rrule,omitempty"`
+	BusinessDayAdjustment string `json:"business_day_adjustment,omitempty"`
+	HolidayCalendar       string `json:"holiday_calendar,omitempty"`
+	Timezone              string `json:"timezone,omitempty"`
+	AutoPost              bool   `json:"auto_post"`
 }
 
 type TransactionScheduleOccurrence struct {
-	ID            int    `json:"id"`
-	ScheduleID    int    `json:"schedule_id"`
-	ScheduledFor  string `json:"scheduled_for"`
-	Status        string `json:"status"`
-	Type          string `json:"type"`
-	Name          string `json:"name"`
-	Category      string `json:"category"`
-	Description   string `json:"description"`
-	Amount        string `json:"amount"`
-	Currency      string `json:"currency"`
-	AutoPost      bool   `json:"auto_post"`
-	TransactionID *int   `json:"transaction_id,omitempty"`
+	ID              int    `json:"id"`
+	ScheduleID      int    `json:"schedule_id"`
+	ScheduledFor    string `json:"scheduled_for"`
+	NominalDate     string `json:"nominal_date"`
+	Status          string `json:"status"`
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	Category        string `json:"category"`
+	Description     string `json:"description"`
+	Amount          string `json:"amount"`
+	Currency        string `json:"currency"`
+	Estimated       bool   `json:"estimated"`
+	EstimatedAmount string `json:"estimated_amount,omitempty"`
+	AmountMin       string `json:"amount_min,omitempty"`
+	AmountMax       string `json:"amount_max,omitempty"`
+	AutoPost        bool   `json:"auto_post"`
+	Overridden      bool   `json:"overridden"`
+	TransactionID   *int   `json:"transaction_id,omitempty"`
+	ReconciledAt    string `json:"reconciled_at,omitempty"`
+}
+
+type ScheduleOccurrenceRequest struct {
+	ScheduledFor string `json:"scheduled_for"`
+	Amount       string `json:"amount"`
+	Category     string `json:"category"`
+	Description  string `json:"description"`
 }
 
 type ScheduleMaintenanceResult struct {
-	Materialized      int `json:"materialized"`
-	Posted            int `json:"posted"`
-	ScheduleReminders int `json:"schedule_reminders"`
-	BudgetAlerts      int `json:"budget_alerts"`
-	InvestmentPosted  int `json:"investment_posted"`
+	Materialized          int `json:"materialized"`
+	Posted                int `json:"posted"`
+	ScheduleReminders     int `json:"schedule_reminders"`
+	BudgetAlerts          int `json:"budget_alerts"`
+	SavingsGoalMilestones int `json:"savings_goal_milestones"`
+	InvestmentPosted      int `json:"investment_posted"`
+	Reconciled            int `json:"reconciled"`
+	MissedPayments        int `json:"missed_payments"`
 }
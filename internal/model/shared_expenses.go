@@ -0,0 +1,124 @@
+package model
+
+// ExpenseGroup is a set of people sharing costs. Exactly one member, marked
+// is_self, stands for the owner of the group.
+type ExpenseGroup struct {
+	ID        int                  `json:"id"`
+	Name      string               `json:"name"`
+	Currency  string               `json:"currency"`
+	Status    string               `json:"status"`
+	Members   []ExpenseGroupMember `json:"members"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+// ExpenseGroupMember is a participant; HasAccount is set when their email
+// belongs to a registered user.
+type ExpenseGroupMember struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Email      string `json:"email,omitempty"`
+	HasAccount bool   `json:"has_account"`
+	IsSelf     bool   `json:"is_self"`
+}
+
+// ExpenseGroupRequest creates or renames a group. Members are only read on
+// create; later ones are added one by one.
+type ExpenseGroupRequest struct {
+	Name     string                      `json:"name"`
+	Currency string                      `json:"currency,omitempty"`
+	SelfName string                      `json:"self_name,omitempty"`
+	Members  []ExpenseGroupMemberRequest `json:"members,omitempty"`
+}
+
+type ExpenseGroupMemberRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// SharedExpense is a cost paid by one member and split among several. Split
+// method is equal, exact, or percentage.
+type SharedExpense struct {
+	ID             int                  `json:"id"`
+	Description    string               `json:"description"`
+	Amount         string               `json:"amount"`
+	Date           string               `json:"date"`
+	PaidByMemberID int                  `json:"paid_by_member_id"`
+	SplitMethod    string               `json:"split_method"`
+	TransactionID  *int                 `json:"transaction_id,omitempty"`
+	Shares         []SharedExpenseShare `json:"shares"`
+	CreatedAt      string               `json:"created_at"`
+}
+
+type SharedExpenseShare struct {
+	MemberID   int    `json:"member_id"`
+	Amount     string `json:"amount"`
+	Percentage string `json:"percentage,omitempty"`
+}
+
+// SharedExpenseRequest records a shared expense. With a transaction_id the
+// owner paid, and the amount, date, and description come from that
+// transaction. For an equal split, shares only name the members taking
+// part, and all members take part when none are given.
+type SharedExpenseRequest struct {
+	Description    string                      `json:"description,omitempty"`
+	Amount         string                      `json:"amount,omitempty"`
+	Date           string                      `json:"date,omitempty"`
+	PaidByMemberID int                         `json:"paid_by_member_id,omitempty"`
+	TransactionID  *int                        `json:"transaction_id,omitempty"`
+	SplitMethod    string                      `json:"split_method"`
+	Shares         []SharedExpenseShareRequest `json:"shares,omitempty"`
+}
+
+type SharedExpenseShareRequest struct {
+	MemberID   int    `json:"member_id"`
+	Amount     string `json:"amount,omitempty"`
+	Percentage string `json:"percentage,omitempty"`
+}
+
+// ExpenseSettlement is a transfer from one member to another that pays back
+// what they owe.
+type ExpenseSettlement struct {
+	ID            int    `json:"id"`
+	FromMemberID  int    `json:"from_member_id"`
+	ToMemberID    int    `json:"to_member_id"`
+	Amount        string `json:"amount"`
+	Date          string `json:"date"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Note          string `json:"note,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ExpenseSettlementRequest struct {
+	FromMemberID  int    `json:"from_member_id"`
+	ToMemberID    int    `json:"to_member_id"`
+	Amount        string `json:"amount,omitempty"`
+	Date          string `json:"date,omitempty"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Note          string `json:"note,omitempty"`
+}
+
+// ExpenseGroupBalances reports each member's net position, positive when the
+// others owe them, and the fewest transfers that settle the group.
+type ExpenseGroupBalances struct {
+	GroupID   int                         `json:"group_id"`
+	Currency  string                      `json:"currency"`
+	Members   []ExpenseGroupMemberBalance `json:"members"`
+	Transfers []ExpenseGroupTransfer      `json:"transfers"`
+}
+
+type ExpenseGroupMemberBalance struct {
+	MemberID int    `json:"member_id"`
+	Name     string `json:"name"`
+	Paid     string `json:"paid"`
+	Share    string `json:"share"`
+	Sent     string `json:"sent"`
+	Received string `json:"received"`
+	Balance  string `json:"balance"`
+}
+
+type ExpenseGroupTransfer struct {
+	FromMemberID int    `json:"from_member_id"`
+	ToMemberID   int    `json:"to_member_id"`
+	Amount       string `json:"amount"`
+}
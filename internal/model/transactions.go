@@ -1,17 +1,19 @@
 package model
 
 type Transaction struct {
-	ID                   int    `json:"id"`
-	Type                 string `json:"type"`
-	Category             string `json:"category"`
-	Description          string `json:"description"`
-	Amount               string `json:"amount"`
-	Currency             string `json:"currency"`
-	OccurredAt           string `json:"occurred_at"`
-	Source               string `json:"source"`
-	Status               string `json:"status"`
-	ExcludedFromBudget   bool   `json:"excluded_from_budget"`
-	ScheduleOccurrenceID *int   `json:"schedule_occurrence_id,omitempty"`
+	ID                   int      `json:"id"`
+	Type                 string   `json:"type"`
+	Category             string   `json:"category"`
+	Description          string   `json:"description"`
+	Amount               string   `json:"amount"`
+	Currency             string   `json:"currency"`
+	OccurredAt           string   `json:"occurred_at"`
+	Source               string   `json:"source"`
+	Status               string   `json:"status"`
+	ExcludedFromBudget   bool     `json:"excluded_from_budget"`
+	ScheduleOccurrenceID *int     `json:"schedule_occurrence_id,omitempty"`
+	Tags                 []string `json:"tags"`
+	MerchantID           *int     `json:"merchant_id,omitempty"`
 }
 
 type TransactionRequest struct {
@@ -22,32 +24,101 @@ type TransactionRequest struct {
 	Currency           string `json:"currency"`
 	OccurredAt         string `json:"occurred_at"`
 	ExcludedFromBudget bool   `json:"excluded_from_budget"`
+	// Tags are set by categorization rules; clients tag through bulk actions.
+	Tags []string `json:"-"`
+	// CategoryChosen marks a manual entry whose category the user picked
+	// rather than left at the catch-all, so the classifier learns from it.
+	CategoryChosen bool `json:"-"`
 	// Accepted temporarily so older mobile builds receive a normal category
 	// validation response instead of failing strict JSON decoding.
 	LegacyPurpose              string `json:"purpose,omitempty"`
 	LegacyInvestmentScheduleID *int   `json:"investment_schedule_id,omitempty"`
 }
 
+type BulkTransactionRequest struct {
+	Action             string                 `json:"action"`
+	IDs                []int                  `json:"ids,omitempty"`
+	Filter             *BulkTransactionFilter `json:"filter,omitempty"`
+	Type               string                 `json:"type,omitempty"`
+	Category           string                 `json:"category,omitempty"`
+	ExcludedFromBudget *bool                  `json:"excluded_from_budget,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+}
+
+type BulkTransactionFilter struct {
+	Month    string `json:"month"`
+	Type     string `json:"type,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+type BulkTransactionResult struct {
+	Action  string                      `json:"action"`
+	Matched int                         `json:"matched"`
+	Changed int                         `json:"changed"`
+	Items   []BulkTransactionItemResult `json:"items"`
+}
+
+type BulkTransactionItemResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// Category is a transaction category. ParentID is set on a subcategory,
+// whose spending also counts towards its top-level parent.
 type Category struct {
 	ID        int    `json:"id"`
 	Type      string `json:"type"`
 	Name      string `json:"name"`
 	IsDefault bool   `json:"is_default"`
+	ParentID  *int   `json:"parent_id,omitempty"`
 }
 
 type CategoryRequest struct {
-	Type string `json:"type"`
-	Name string `json:"name"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	ParentID *int   `json:"parent_id,omitempty"`
+}
+
+// CategoryUpdateRequest renames a category and moves it in the hierarchy; a
+// missing ParentID makes it top-level. The type cannot change.
+type CategoryUpdateRequest struct {
+	Name     string `json:"name"`
+	ParentID *int   `json:"parent_id,omitempty"`
+}
+
+// CategoryMergeRequest folds the category in the path into TargetID.
+type CategoryMergeRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+// CategoryMergeResult is the surviving category and how many records were
+// moved onto it.
+type CategoryMergeResult struct {
+	Category            Category `json:"category"`
+	Transactions        int      `json:"transactions"`
+	Budgets             int      `json:"budgets"`
+	Schedules           int      `json:"schedules"`
+	CategorizationRules int      `json:"categorization_rules"`
+}
+
+// CategoryTotal is booked spending or income in one top-level category;
+// Amount includes its subcategories, which are listed separately.
+type CategoryTotal struct {
+	Type          string          `json:"type"`
+	Category      string          `json:"category"`
+	Amount        string          `json:"amount"`
+	Subcategories []CategoryTotal `json:"subcategories,omitempty"`
 }
 
 type Summary struct {
-	Month            string `json:"month"`
-	Income           string `json:"income"`
-	Expense          string `json:"expense"`
-	CashOutflow      string `json:"cash_outflow"`
-	Balance          string `json:"balance"`
-	Currency         string `json:"currency"`
-	TransactionCount int    `json:"transaction_count"`
+	Month            string          `json:"month"`
+	Income           string          `json:"income"`
+	Expense          string          `json:"expense"`
+	CashOutflow      string          `json:"cash_outflow"`
+	Balance          string          `json:"balance"`
+	Currency         string          `json:"currency"`
+	TransactionCount int             `json:"transaction_count"`
+	Categories       []CategoryTotal `json:"categories"`
 }
 
 type ImportResult struct {
@@ -56,7 +127,56 @@ type ImportResult struct {
 	Ignored  int `json:"ignored"`
 }
 
+// StatementImportResult is returned by importers that keep going past invalid
+// rows and report each rejected row instead of failing the whole file.
+type StatementImportResult struct {
+	ImportResult
+	Rejected   []ImportRejection `json:"rejected"`
+	Reconciled int               `json:"reconciled"`
+}
+
+type ImportRejection struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
 type ImportedTransaction struct {
 	Request     TransactionRequest
+	Source      string
 	Fingerprint string
+	Merchant    MerchantMatch
+	// CategoryChosen marks a category the user picked while committing a
+	// preview, which makes the row a training example.
+	CategoryChosen bool
+}
+
+// DuplicateTransactionPair is a likely duplicate recorded by two different
+// sources. Score is between 0 and 1; the signals explain it.
+type DuplicateTransactionPair struct {
+	Score              float64       `json:"score"`
+	DayDifference      int           `json:"day_difference"`
+	MerchantSimilarity float64       `json:"merchant_similarity"`
+	Transactions       []Transaction `json:"transactions"`
+	Origins            []string      `json:"origins"`
+}
+
+type TransactionMergeRequest struct {
+	KeepID  int `json:"keep_id"`
+	MergeID int `json:"merge_id"`
+}
+
+type TransactionMergeResult struct {
+	Transaction Transaction             `json:"transaction"`
+	Provenance  []TransactionProvenance `json:"provenance"`
+}
+
+// TransactionProvenance names one source of a transaction: the row itself or
+// a duplicate merged into it. Origin is manual, import:<format>, or
+// open_banking:<account id>.
+type TransactionProvenance struct {
+	Origin      string `json:"origin"`
+	Description string `json:"description"`
+	Amount      string `json:"amount"`
+	OccurredAt  string `json:"occurred_at"`
+	MergedAt    string `json:"merged_at,omitempty"`
 }
@@ -2,9 +2,15 @@ package recurrence
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 )
 
+// Rule describes a recurrence. DayOfWeek and DayOfMonth are the original
+// single-day weekly and monthly rules; a monthly DayOfMonth past the end of a
+// month falls on its last day. The BY fields follow RFC 5545 instead, so a
+// BYMONTHDAY of 31 skips shorter months and -1 is the last day.
 type Rule struct {
 	Frequency  string
 	Interval   int
@@ -12,12 +18,28 @@ type Rule struct {
 	EndDate    *time.Time
 	DayOfWeek  int
 	DayOfMonth int
+	// Count limits the rule to its first Count occurrences from the start
+	// date. Zero means no limit.
+	Count      int
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []Weekday
+	BySetPos   []int
+}
+
+// Weekday is an ISO weekday (1 is Monday) with an optional ordinal:
+// {Ordinal: 2, Day: 2} is the second Tuesday and {Ordinal: -1, Day: 5} the
+// last Friday of the month, or of the year in a yearly rule without BYMONTH.
+type Weekday struct {
+	Ordinal int
+	Day     int
 }
 
 func Occurrences(rule Rule, from, through time.Time) ([]time.Time, error) {
 	if err := validate(rule); err != nil {
 		return nil, err
 	}
+	rule.StartDate = dateOnly(rule.StartDate)
 	from = dateOnly(from)
 	through = dateOnly(through)
 	if through.Before(from) {
@@ -33,15 +55,191 @@ func Occurrences(rule Rule, from, through time.Time) ([]time.Time, error) {
 		return []time.Time{}, nil
 	}
 
-	next := nextOnOrAfter(rule, from)
+	if rule.expanded() {
+		return expand(rule, from, through), nil
+	}
+	// COUNT counts from the start date, not from the requested range.
+	next := from
+	if rule.Count > 0 {
+		next = rule.StartDate
+	}
+	next = nextOnOrAfter(rule, next)
 	out := make([]time.Time, 0)
-	for !next.After(through) {
-		out = append(out, next)
+	for seen := 0; !next.After(through) && (rule.Count == 0 || seen < rule.Count); seen++ {
+		if !next.Before(from) {
+			out = append(out, next)
+		}
 		next = nextOnOrAfter(rule, next.AddDate(0, 0, 1))
 	}
 	return out, nil
 }
 
+// expanded reports whether the rule is evaluated as RFC 5545 describes rather
+// than with the single day of week or month of the original rules.
+func (rule Rule) expanded() bool {
+	if len(rule.ByMonth)+len(rule.ByMonthDay)+len(rule.ByDay)+len(rule.BySetPos) > 0 {
+		return true
+	}
+	switch rule.Frequency {
+	case "weekly":
+		return rule.DayOfWeek == 0
+	case "monthly":
+		return rule.DayOfMonth == 0
+	case "yearly":
+		return true
+	default:
+		return false
+	}
+}
+
+// expand walks the rule period by period. Each period yields the days its BY
+// parts select, BYSETPOS picks among them, and days before the start date are
+// neither returned nor counted.
+func expand(rule Rule, from, through time.Time) []time.Time {
+	period := 0
+	if rule.Count == 0 {
+		period = periodIndex(rule, from)
+	}
+	out := make([]time.Time, 0)
+	seen := 0
+	for ; rule.Count == 0 || seen < rule.Count; period++ {
+		first, last := periodBounds(rule, period)
+		if first.After(through) {
+			break
+		}
+		for _, day := range setPositions(rule, candidates(rule, first, last)) {
+			if day.Before(rule.StartDate) {
+				continue
+			}
+			if day.After(through) || (rule.Count > 0 && seen == rule.Count) {
+				return out
+			}
+			seen++
+			if !day.Before(from) {
+				out = append(out, day)
+			}
+		}
+	}
+	return out
+}
+
+// periodBounds returns the first and last day of the period-th interval after
+// the one holding the start date. Weeks start on Monday.
+func periodBounds(rule Rule, period int) (time.Time, time.Time) {
+	start := rule.StartDate
+	switch rule.Frequency {
+	case "daily":
+		day := start.AddDate(0, 0, period*rule.Interval)
+		return day, day
+	case "weekly":
+		first := start.AddDate(0, 0, 1-isoWeekday(start)+7*period*rule.Interval)
+		return first, first.AddDate(0, 0, 6)
+	case "monthly":
+		first := time.Date(start.Year(), start.Month()+time.Month(period*rule.Interval), 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(0, 1, -1)
+	default:
+		first := time.Date(start.Year()+period*rule.Interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(1, 0, -1)
+	}
+}
+
+// periodIndex returns the period holding target, which is not before the
+// start date.
+func periodIndex(rule Rule, target time.Time) int {
+	start := rule.StartDate
+	switch rule.Frequency {
+	case "daily":
+		return daysBetween(start, target) / rule.Interval
+	case "weekly":
+		return daysBetween(start.AddDate(0, 0, 1-isoWeekday(start)), target) / (7 * rule.Interval)
+	case "monthly":
+		return monthsBetween(start, target) / rule.Interval
+	default:
+		return (target.Year() - start.Year()) / rule.Interval
+	}
+}
+
+func candidates(rule Rule, first, last time.Time) []time.Time {
+	out := make([]time.Time, 0)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if matches(rule, day) {
+			out = append(out, day)
+		}
+	}
+	return out
+}
+
+func matches(rule Rule, day time.Time) bool {
+	if len(rule.ByMonth) > 0 && !slices.Contains(rule.ByMonth, int(day.Month())) {
+		return false
+	}
+	if len(rule.ByMonthDay) > 0 && !monthDayMatches(rule.ByMonthDay, day) {
+		return false
+	}
+	if len(rule.ByDay) > 0 && !weekdayMatches(rule, day) {
+		return false
+	}
+	// Without BY parts that pick days, the start date supplies them.
+	pinned := len(rule.ByMonthDay) > 0 || len(rule.ByDay) > 0
+	switch rule.Frequency {
+	case "weekly":
+		return pinned || isoWeekday(day) == isoWeekday(rule.StartDate)
+	case "monthly":
+		return pinned || day.Day() == rule.StartDate.Day()
+	case "yearly":
+		return pinned || (day.Day() == rule.StartDate.Day() &&
+			(len(rule.ByMonth) > 0 || day.Month() == rule.StartDate.Month()))
+	default:
+		return true
+	}
+}
+
+func monthDayMatches(values []int, day time.Time) bool {
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, value := range values {
+		if value == day.Day() || value == day.Day()-lastDay-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdayMatches counts ordinals within the month, or within the year for a
+// yearly rule without BYMONTH.
+func weekdayMatches(rule Rule, day time.Time) bool {
+	position, length := day.Day(), time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if rule.Frequency == "yearly" && len(rule.ByMonth) == 0 {
+		position, length = day.YearDay(), time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	for _, weekday := range rule.ByDay {
+		if weekday.Day != isoWeekday(day) {
+			continue
+		}
+		if weekday.Ordinal == 0 || weekday.Ordinal == (position-1)/7+1 || weekday.Ordinal == -((length-position)/7+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func setPositions(rule Rule, days []time.Time) []time.Time {
+	if len(rule.BySetPos) == 0 {
+		return days
+	}
+	picked := make([]time.Time, 0, len(rule.BySetPos))
+	for _, position := range rule.BySetPos {
+		index := position - 1
+		if position < 0 {
+			index = len(days) + position
+		}
+		if index >= 0 && index < len(days) {
+			picked = append(picked, days[index])
+		}
+	}
+	slices.SortFunc(picked, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(picked, time.Time.Equal)
+}
+
 func nextOnOrAfter(rule Rule, target time.Time) time.Time {
 	target = dateOnly(target)
 	switch rule.Frequency {
@@ -96,21 +294,23 @@ func validate(rule Rule) error {
 		}
 	}
 	switch rule.Frequency {
-	case "daily":
-		return nil
-	case "weekly":
-		if rule.DayOfWeek < 1 || rule.DayOfWeek > 7 {
-			return errors.New("recurrence: weekly day must be between 1 and 7")
-		}
-		return nil
-	case "monthly":
-		if rule.DayOfMonth < 1 || rule.DayOfMonth > 31 {
-			return errors.New("recurrence: monthly day must be between 1 and 31")
-		}
-		return nil
+	case "daily", "weekly", "monthly", "yearly":
 	default:
 		return errors.New("recurrence: unsupported frequency")
 	}
+	if rule.DayOfWeek != 0 && (rule.Frequency != "weekly" || rule.DayOfWeek < 1 || rule.DayOfWeek > 7) {
+		return errors.New("recurrence: weekly day must be between 1 and 7")
+	}
+	if rule.DayOfMonth != 0 && (rule.Frequency != "monthly" || rule.DayOfMonth < 1 || rule.DayOfMonth > 31) {
+		return errors.New("recurrence: monthly day must be between 1 and 31")
+	}
+	if (rule.DayOfWeek != 0 || rule.DayOfMonth != 0) && rule.expanded() {
+		return errors.New("recurrence: day of week or month cannot be combined with BY parts")
+	}
+	if err := validateParts(rule); err != nil {
+		return fmt.Errorf("recurrence: %w", err)
+	}
+	return nil
 }
 
 func dateOnly(value time.Time) time.Time {
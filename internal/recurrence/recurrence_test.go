@@ -63,6 +63,99 @@ func TestDailyOccurrencesRespectRangeAndEndDate(t *testing.T) {
 	}
 }
 
+func TestMonthlyBySetPosPicksLastBusinessDay(t *testing.T) {
+	rule := Rule{
+		Frequency: "monthly", Interval: 1, StartDate: date("2026-01-01"),
+		ByDay: []Weekday{{Day: 1}, {Day: 2}, {Day: 3}, {Day: 4}, {Day: 5}}, BySetPos: []int{-1},
+	}
+	got, err := Occurrences(rule, date("2026-01-01"), date("2026-06-30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-01-30", "2026-02-27", "2026-03-31", "2026-04-30", "2026-05-29", "2026-06-30"}
+	if !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("occurrences = %#v, want %#v", format(got), want)
+	}
+}
+
+func TestMonthlyByDayOrdinalAndNegativeMonthDay(t *testing.T) {
+	secondTuesday := Rule{
+		Frequency: "monthly", Interval: 1, StartDate: date("2026-07-01"), ByDay: []Weekday{{Ordinal: 2, Day: 2}},
+	}
+	got, err := Occurrences(secondTuesday, date("2026-07-01"), date("2026-10-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-07-14", "2026-08-11", "2026-09-08", "2026-10-13"}
+	if !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("second Tuesday = %#v, want %#v", format(got), want)
+	}
+
+	lastDay := Rule{Frequency: "monthly", Interval: 1, StartDate: date("2028-01-15"), ByMonthDay: []int{-1}}
+	got, err = Occurrences(lastDay, date("2028-01-01"), date("2028-04-30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"2028-01-31", "2028-02-29", "2028-03-31", "2028-04-30"}
+	if !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("last day = %#v, want %#v", format(got), want)
+	}
+}
+
+func TestCountStartsAtTheStartDate(t *testing.T) {
+	rule := Rule{
+		Frequency: "monthly", Interval: 1, StartDate: date("2026-07-10"), ByMonthDay: []int{1, 15}, Count: 3,
+	}
+	got, err := Occurrences(rule, date("2026-08-02"), date("2026-12-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-08-15"}; !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("occurrences = %#v, want %#v", format(got), want)
+	}
+
+	legacy := Rule{Frequency: "weekly", Interval: 1, StartDate: date("2026-07-13"), DayOfWeek: 1, Count: 2}
+	got, err = Occurrences(legacy, date("2026-07-01"), date("2026-08-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-07-13", "2026-07-20"}; !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("legacy occurrences = %#v, want %#v", format(got), want)
+	}
+}
+
+func TestYearlyOccurrences(t *testing.T) {
+	leapDay := Rule{Frequency: "yearly", Interval: 1, StartDate: date("2028-02-29")}
+	got, err := Occurrences(leapDay, date("2028-01-01"), date("2033-12-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2028-02-29", "2032-02-29"}; !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("leap day = %#v, want %#v", format(got), want)
+	}
+
+	quarterly := Rule{
+		Frequency: "yearly", Interval: 1, StartDate: date("2026-02-01"),
+		ByMonth: []int{1, 4, 7, 10}, ByMonthDay: []int{1},
+	}
+	got, err = Occurrences(quarterly, date("2026-01-01"), date("2027-01-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-04-01", "2026-07-01", "2026-10-01", "2027-01-01"}; !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("quarterly = %#v, want %#v", format(got), want)
+	}
+
+	lastFriday := Rule{Frequency: "yearly", Interval: 1, StartDate: date("2026-01-01"), ByDay: []Weekday{{Ordinal: -1, Day: 5}}}
+	got, err = Occurrences(lastFriday, date("2026-01-01"), date("2026-12-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-12-25"}; !reflect.DeepEqual(format(got), want) {
+		t.Fatalf("last Friday of the year = %#v, want %#v", format(got), want)
+	}
+}
+
 func date(value string) time.Time {
 	parsed, err := time.Parse("2006-01-02", value)
 	if err != nil {
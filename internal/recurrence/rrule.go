@@ -0,0 +1,213 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayCodes = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// ParseRRule parses the RFC 5545 subset the schedules support: FREQ (DAILY,
+// WEEKLY, MONTHLY, or YEARLY), INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY,
+// BYDAY, BYSETPOS, and WKST=MO, for example
+// "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" for the last business day
+// of every month. UNTIL becomes the end date; the caller sets the start date.
+func ParseRRule(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+	rule := Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, raw, ok := strings.Cut(part, "=")
+		name, raw = strings.ToUpper(strings.TrimSpace(name)), strings.ToUpper(strings.TrimSpace(raw))
+		if !ok || name == "" || raw == "" {
+			return Rule{}, fmt.Errorf("rrule: %q is not a NAME=VALUE part", part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("rrule: %s is set more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Frequency = strings.ToLower(raw)
+			switch rule.Frequency {
+			case "daily", "weekly", "monthly", "yearly":
+			default:
+				return Rule{}, errors.New("rrule: FREQ must be DAILY, WEEKLY, MONTHLY, or YEARLY")
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(raw)
+			if err != nil || rule.Interval < 1 {
+				return Rule{}, errors.New("rrule: INTERVAL must be a positive integer")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(raw)
+			if err != nil || rule.Count < 1 {
+				return Rule{}, errors.New("rrule: COUNT must be a positive integer")
+			}
+		case "UNTIL":
+			day, _, _ := strings.Cut(raw, "T")
+			until, err := time.Parse("20060102", day)
+			if err != nil {
+				return Rule{}, errors.New("rrule: UNTIL must be a date such as 20261231")
+			}
+			rule.EndDate = &until
+		case "BYMONTH":
+			rule.ByMonth, err = parseRRuleInts(name, raw)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseRRuleInts(name, raw)
+		case "BYSETPOS":
+			rule.BySetPos, err = parseRRuleInts(name, raw)
+		case "BYDAY":
+			rule.ByDay, err = parseRRuleWeekdays(raw)
+		case "WKST":
+			if raw != "MO" {
+				return Rule{}, errors.New("rrule: only WKST=MO is supported")
+			}
+		default:
+			return Rule{}, fmt.Errorf("rrule: %s is not supported", name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+	if rule.Frequency == "" {
+		return Rule{}, errors.New("rrule: FREQ is required")
+	}
+	if rule.Count > 0 && rule.EndDate != nil {
+		return Rule{}, errors.New("rrule: COUNT and UNTIL cannot both be set")
+	}
+	if err := validateParts(rule); err != nil {
+		return Rule{}, fmt.Errorf("rrule: %w", err)
+	}
+	return rule, nil
+}
+
+// RRule formats the RFC 5545 parts of the rule in a fixed order. DayOfWeek
+// and DayOfMonth have no exact RRULE equivalent and are left out.
+func (rule Rule) RRule() string {
+	parts := []string{"FREQ=" + strings.ToUpper(rule.Frequency)}
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rule.Count))
+	}
+	if rule.EndDate != nil {
+		parts = append(parts, "UNTIL="+rule.EndDate.Format("20060102"))
+	}
+	parts = appendRRuleInts(parts, "BYMONTH", rule.ByMonth)
+	parts = appendRRuleInts(parts, "BYMONTHDAY", rule.ByMonthDay)
+	if len(rule.ByDay) > 0 {
+		values := make([]string, len(rule.ByDay))
+		for index, weekday := range rule.ByDay {
+			values[index] = weekdayCodes[weekday.Day-1]
+			if weekday.Ordinal != 0 {
+				values[index] = strconv.Itoa(weekday.Ordinal) + values[index]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(values, ","))
+	}
+	parts = appendRRuleInts(parts, "BYSETPOS", rule.BySetPos)
+	return strings.Join(parts, ";")
+}
+
+// validateParts checks the fields that have an RRULE equivalent.
+func validateParts(rule Rule) error {
+	if rule.Count < 0 {
+		return errors.New("COUNT must not be negative")
+	}
+	for _, month := range rule.ByMonth {
+		if month < 1 || month > 12 {
+			return errors.New("BYMONTH values must be between 1 and 12")
+		}
+	}
+	for _, day := range rule.ByMonthDay {
+		if day == 0 || day < -31 || day > 31 {
+			return errors.New("BYMONTHDAY values must be between 1 and 31 or -31 and -1")
+		}
+	}
+	if rule.Frequency == "weekly" && len(rule.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, weekday := range rule.ByDay {
+		if weekday.Day < 1 || weekday.Day > 7 {
+			return errors.New("BYDAY weekday must be between 1 and 7")
+		}
+		if weekday.Ordinal != 0 && rule.Frequency != "monthly" && rule.Frequency != "yearly" {
+			return errors.New("BYDAY ordinals need FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		if weekday.Ordinal < -53 || weekday.Ordinal > 53 {
+			return errors.New("BYDAY ordinals must be between 1 and 53 or -53 and -1")
+		}
+	}
+	for _, position := range rule.BySetPos {
+		if position == 0 || position < -366 || position > 366 {
+			return errors.New("BYSETPOS values must be between 1 and 366 or -366 and -1")
+		}
+	}
+	if len(rule.BySetPos) > 0 && len(rule.ByMonth)+len(rule.ByMonthDay)+len(rule.ByDay) == 0 {
+		return errors.New("BYSETPOS needs BYMONTH, BYMONTHDAY, or BYDAY")
+	}
+	return nil
+}
+
+func parseRRuleInts(name, raw string) ([]int, error) {
+	fields := strings.Split(raw, ",")
+	values := make([]int, len(fields))
+	for index, field := range fields {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("rrule: %s must be a list of integers", name)
+		}
+		values[index] = value
+	}
+	return values, nil
+}
+
+func parseRRuleWeekdays(raw string) ([]Weekday, error) {
+	fields := strings.Split(raw, ",")
+	values := make([]Weekday, len(fields))
+	for index, field := range fields {
+		field = strings.TrimSpace(field)
+		day := 0
+		if len(field) >= 2 {
+			for code, name := range weekdayCodes {
+				if field[len(field)-2:] == name {
+					day = code + 1
+				}
+			}
+		}
+		if day == 0 {
+			return nil, fmt.Errorf("rrule: BYDAY value %q is not a weekday such as MO or 2TU", field)
+		}
+		ordinal := 0
+		if prefix := field[:len(field)-2]; prefix != "" {
+			value, err := strconv.Atoi(prefix)
+			if err != nil || value == 0 {
+				return nil, fmt.Errorf("rrule: BYDAY value %q has an invalid ordinal", field)
+			}
+			ordinal = value
+		}
+		values[index] = Weekday{Ordinal: ordinal, Day: day}
+	}
+	return values, nil
+}
+
+func appendRRuleInts(parts []string, name string, values []int) []string {
+	if len(values) == 0 {
+		return parts
+	}
+	formatted := make([]string, len(values))
+	for index, value := range values {
+		formatted[index] = strconv.Itoa(value)
+	}
+	return append(parts, name+"="+strings.Join(formatted, ","))
+}
@@ -0,0 +1,57 @@
+package recurrence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRRuleRoundTrips(t *testing.T) {
+	rule, err := ParseRRule("RRULE:freq=monthly;interval=2;byday=mo,tu,we,th,fr;bysetpos=-1;wkst=MO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Rule{
+		Frequency: "monthly", Interval: 2,
+		ByDay:    []Weekday{{Day: 1}, {Day: 2}, {Day: 3}, {Day: 4}, {Day: 5}},
+		BySetPos: []int{-1},
+	}
+	if !reflect.DeepEqual(rule, want) {
+		t.Fatalf("rule = %+v, want %+v", rule, want)
+	}
+	if got := rule.RRule(); got != "FREQ=MONTHLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" {
+		t.Fatalf("RRule() = %q", got)
+	}
+
+	rule, err = ParseRRule("FREQ=YEARLY;UNTIL=20301231T235959Z;BYMONTH=3;BYDAY=-1SU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.EndDate == nil || !rule.EndDate.Equal(date("2030-12-31")) || !reflect.DeepEqual(rule.ByDay, []Weekday{{Ordinal: -1, Day: 7}}) {
+		t.Fatalf("unexpected yearly rule: %+v", rule)
+	}
+	if got := rule.RRule(); got != "FREQ=YEARLY;UNTIL=20301231;BYMONTH=3;BYDAY=-1SU" {
+		t.Fatalf("RRule() = %q", got)
+	}
+}
+
+func TestParseRRuleRejectsUnsupportedRules(t *testing.T) {
+	tests := map[string]string{
+		"FREQ=HOURLY":                       "FREQ must be",
+		"INTERVAL=2":                        "FREQ is required",
+		"FREQ=DAILY;BYHOUR=9":               "BYHOUR is not supported",
+		"FREQ=DAILY;FREQ=WEEKLY":            "more than once",
+		"FREQ=DAILY;COUNT=2;UNTIL=20301231": "COUNT and UNTIL",
+		"FREQ=WEEKLY;BYDAY=2MO":             "BYDAY ordinals",
+		"FREQ=WEEKLY;BYMONTHDAY=1":          "BYMONTHDAY cannot",
+		"FREQ=MONTHLY;BYMONTHDAY=0":         "BYMONTHDAY values",
+		"FREQ=MONTHLY;BYDAY=XX":             "not a weekday",
+		"FREQ=MONTHLY;BYSETPOS=1":           "BYSETPOS needs",
+		"FREQ=MONTHLY;WKST=SU":              "WKST=MO",
+	}
+	for value, message := range tests {
+		if _, err := ParseRRule(value); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("ParseRRule(%q) error = %v, want %q", value, err, message)
+		}
+	}
+}
@@ -8,6 +8,17 @@ import (
 	"money-manager-server/internal/model"
 )
 
+// budgetSpend is what an expense row t counts toward budgets: the owner's
+// share when it backs a shared expense, nothing when it settles a debt within
+// a shared-expense group, and its full amount otherwise.
+const budgetSpend = `CASE
+	WHEN EXISTS(SELECT 1 FROM expense_settlements settlement WHERE settlement.transaction_id=t.id) THEN 0
+	ELSE COALESCE((SELECT COALESCE(own.amount,0) FROM shared_expenses shared
+		JOIN expense_group_members self ON self.group_id=shared.group_id AND self.is_self
+		LEFT JOIN shared_expense_shares own ON own.expense_id=shared.id AND own.member_id=self.id
+		WHERE shared.transaction_id=t.id),t.amount)
+END`
+
 const budgetSelect = `WITH selected AS (
 	SELECT b.*,
 		CASE b.period
@@ -23,7 +34,7 @@ const budgetSelect = `WITH selected AS (
 			ELSE (selected.period_start + INTERVAL '1 month - 1 day')::date
 		END AS period_end,
 		COALESCE((
-			SELECT sum(t.amount)
+			SELECT sum(` + budgetSpend + `)
 			FROM transactions t
 			WHERE t.user_id=selected.user_id AND t.type='expense' AND t.status='booked'
 				AND NOT t.excluded_from_budget
@@ -32,7 +43,11 @@ const budgetSelect = `WITH selected AS (
 					WHEN 'weekly' THEN selected.period_start + 7
 					ELSE (selected.period_start + INTERVAL '1 month')::date
 				END
-				AND (selected.category='' OR lower(t.category)=lower(selected.category))
+				AND (selected.category='' OR lower(t.category)=lower(selected.category)
+					OR lower(t.category) IN (SELECT lower(child.name)
+						FROM categories parent JOIN categories child ON child.parent_id=parent.id AND child.active
+						WHERE parent.user_id=selected.user_id AND parent.type='expense' AND parent.active
+							AND lower(parent.name)=lower(selected.category)))
 		),0) AS spent
 	FROM selected
 )
@@ -131,13 +146,17 @@ func (r *Repository) QueueBudgetAlerts(ctx context.Context, reference time.Time)
 		FROM budgets b WHERE b.status='active'
 	), spending AS (
 		SELECT active.*,
-			COALESCE((SELECT sum(t.amount) FROM transactions t
+			COALESCE((SELECT sum(`+budgetSpend+`) FROM transactions t
 				WHERE t.user_id=active.user_id AND t.type='expense' AND t.status='booked'
 					AND NOT t.excluded_from_budget
 					AND t.occurred_at >= active.period_start
 					AND t.occurred_at < CASE active.period WHEN 'weekly' THEN active.period_start+7
 						ELSE (active.period_start+INTERVAL '1 month')::date END
-					AND (active.category='' OR lower(t.category)=lower(active.category))),0) AS spent
+					AND (active.category='' OR lower(t.category)=lower(active.category)
+						OR lower(t.category) IN (SELECT lower(child.name)
+							FROM categories parent JOIN categories child ON child.parent_id=parent.id AND child.active
+							WHERE parent.user_id=active.user_id AND parent.type='expense' AND parent.active
+								AND lower(parent.name)=lower(active.category)))),0) AS spent
 		FROM active
 	), candidates AS (
 		SELECT spending.*,level
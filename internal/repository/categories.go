@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"money-manager-server/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var defaultCategories = map[string][]string{
@@ -12,7 +15,7 @@ var defaultCategories = map[string][]string{
 }
 
 func (r *Repository) ListCategories(ctx context.Context, userID int, transactionType string) ([]model.Category, error) {
-	rows, err := r.db.Query(ctx, `SELECT id,type,name,is_default
+	rows, err := r.db.Query(ctx, `SELECT `+categoryColumns+`
 		FROM categories WHERE user_id=$1 AND type=$2 AND active
 		ORDER BY sort_order ASC,name ASC`, userID, transactionType)
 	if err != nil {
@@ -22,8 +25,8 @@ func (r *Repository) ListCategories(ctx context.Context, userID int, transaction
 
 	out := make([]model.Category, 0)
 	for rows.Next() {
-		var category model.Category
-		if err := rows.Scan(&category.ID, &category.Type, &category.Name, &category.IsDefault); err != nil {
+		category, err := scanCategory(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, category)
@@ -31,19 +34,186 @@ func (r *Repository) ListCategories(ctx context.Context, userID int, transaction
 	return out, rows.Err()
 }
 
+const categoryColumns = `id,type,name,is_default,parent_id`
+
+// GetCategory returns an active category.
+func (r *Repository) GetCategory(ctx context.Context, userID, categoryID int) (model.Category, error) {
+	category, err := scanCategory(r.db.QueryRow(ctx, `SELECT `+categoryColumns+`
+		FROM categories WHERE id=$1 AND user_id=$2 AND active`, categoryID, userID))
+	return category, mapNotFound(err)
+}
+
 func (r *Repository) CreateCategory(ctx context.Context, userID int, request model.CategoryRequest) (model.Category, error) {
-	var category model.Category
-	err := r.db.QueryRow(ctx, `INSERT INTO categories(user_id,type,name,is_default,active,sort_order)
-		SELECT $1,$2,$3,false,true,COALESCE(MAX(sort_order),999)+1
+	category, err := scanCategory(r.db.QueryRow(ctx, `INSERT INTO categories(user_id,type,name,is_default,active,sort_order,parent_id)
+		SELECT $1,$2,$3,false,true,COALESCE(MAX(sort_order),999)+1,$4
 		FROM categories WHERE user_id=$1 AND type=$2
-		RETURNING id,type,name,is_default`, userID, request.Type, request.Name,
-	).Scan(&category.ID, &category.Type, &category.Name, &category.IsDefault)
+		RETURNING `+categoryColumns, userID, request.Type, request.Name, request.ParentID,
+	))
 	return category, mapConflict(err)
 }
 
+// UpdateCategory renames a category and sets its parent in one transaction.
+// A new name is carried over to the transactions, budgets, schedules and
+// categorization rules that reference the old one, since they all refer to
+// categories by name.
+func (r *Repository) UpdateCategory(
+	ctx context.Context,
+	userID, categoryID int,
+	request model.CategoryUpdateRequest,
+) (model.Category, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.Category{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	existing, err := scanCategory(tx.QueryRow(ctx, `SELECT `+categoryColumns+`
+		FROM categories WHERE id=$1 AND user_id=$2 AND active FOR UPDATE`, categoryID, userID))
+	if err != nil {
+		return model.Category{}, mapNotFound(err)
+	}
+	category, err := scanCategory(tx.QueryRow(ctx, `UPDATE categories SET name=$1,parent_id=$2,updated_at=now()
+		WHERE id=$3 AND user_id=$4
+		RETURNING `+categoryColumns, request.Name, request.ParentID, categoryID, userID))
+	if err != nil {
+		return model.Category{}, mapConflict(err)
+	}
+	if existing.Name != category.Name {
+		if _, err := retargetCategoryReferences(ctx, tx, userID, existing, category.Name); err != nil {
+			return model.Category{}, mapConflict(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.Category{}, err
+	}
+	return category, nil
+}
+
+// MergeCategories moves everything that references the source category onto
+// the target, hands the source's subcategories to the target (a target that
+// was one of them becomes top-level) and archives the source, all in one
+// transaction. A source budget whose target already
+// has an active budget for the same period is archived instead of moved.
+func (r *Repository) MergeCategories(ctx context.Context, userID, sourceID, targetID int) (model.CategoryMergeResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	rows, err := tx.Query(ctx, `SELECT `+categoryColumns+`
+		FROM categories WHERE id IN ($1,$2) AND user_id=$3 AND active
+		ORDER BY id FOR UPDATE`, sourceID, targetID, userID)
+	if err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	var source, target model.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			rows.Close()
+			return model.CategoryMergeResult{}, err
+		}
+		if category.ID == sourceID {
+			source = category
+		} else {
+			target = category
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if source.ID == 0 || target.ID == 0 {
+		return model.CategoryMergeResult{}, ErrNotFound
+	}
+	if source.Type == "expense" {
+		if _, err := tx.Exec(ctx, `UPDATE budgets b SET status='archived',updated_at=now()
+			WHERE b.user_id=$1 AND b.status='active' AND lower(b.category)=lower($2)
+				AND EXISTS(SELECT 1 FROM budgets existing
+					WHERE existing.user_id=b.user_id AND existing.status='active'
+						AND existing.period=b.period AND lower(existing.category)=lower($3))`,
+			userID, source.Name, target.Name); err != nil {
+			return model.CategoryMergeResult{}, err
+		}
+	}
+	result, err := retargetCategoryReferences(ctx, tx, userID, source, target.Name)
+	if err != nil {
+		return model.CategoryMergeResult{}, mapConflict(err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE categories
+		SET parent_id=CASE WHEN id=$1 THEN NULL ELSE $1 END,updated_at=now()
+		WHERE parent_id=$2 AND user_id=$3`, target.ID, source.ID, userID); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE categories SET active=false,parent_id=NULL,updated_at=now()
+		WHERE id=$1 AND user_id=$2`, source.ID, userID); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if result.Category, err = scanCategory(tx.QueryRow(ctx, `SELECT `+categoryColumns+`
+		FROM categories WHERE id=$1`, target.ID)); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	return result, nil
+}
+
+// retargetCategoryReferences points every record that names the category
+// at a new name and counts what changed.
+func retargetCategoryReferences(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID int,
+	category model.Category,
+	name string,
+) (model.CategoryMergeResult, error) {
+	var result model.CategoryMergeResult
+	tag, err := tx.Exec(ctx, `UPDATE transactions SET category=$1,updated_at=now()
+		WHERE user_id=$2 AND type=$3 AND lower(category)=lower($4)`, name, userID, category.Type, category.Name)
+	if err != nil {
+		return result, err
+	}
+	result.Transactions = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, `UPDATE transaction_schedules SET category=$1,updated_at=now()
+		WHERE user_id=$2 AND type=$3 AND lower(category)=lower($4)`, name, userID, category.Type, category.Name)
+	if err != nil {
+		return result, err
+	}
+	result.Schedules = int(tag.RowsAffected())
+	if _, err := tx.Exec(ctx, `UPDATE transaction_schedule_occurrences SET category=$1,updated_at=now()
+		WHERE user_id=$2 AND type=$3 AND lower(category)=lower($4) AND status='planned'`,
+		name, userID, category.Type, category.Name); err != nil {
+		return result, err
+	}
+	tag, err = tx.Exec(ctx, `UPDATE categorization_rules SET category=$1,updated_at=now()
+		WHERE user_id=$2 AND transaction_type=$3 AND lower(category)=lower($4)`, name, userID, category.Type, category.Name)
+	if err != nil {
+		return result, err
+	}
+	result.CategorizationRules = int(tag.RowsAffected())
+	if category.Type == "expense" {
+		tag, err = tx.Exec(ctx, `UPDATE budgets SET category=$1,updated_at=now()
+			WHERE user_id=$2 AND status='active' AND lower(category)=lower($3)`, name, userID, category.Name)
+		if err != nil {
+			return result, err
+		}
+		result.Budgets = int(tag.RowsAffected())
+	}
+	return result, nil
+}
+
+// DeleteCategory archives a custom category. Its subcategories become
+// top-level.
 func (r *Repository) DeleteCategory(ctx context.Context, userID, categoryID int) error {
-	tag, err := r.db.Exec(ctx,
-		"UPDATE categories SET active=false,updated_at=now() WHERE id=$1 AND user_id=$2 AND is_default=false AND active",
+	tag, err := r.db.Exec(ctx, `WITH archived AS (
+			UPDATE categories SET active=false,parent_id=NULL,updated_at=now()
+			WHERE id=$1 AND user_id=$2 AND is_default=false AND active
+			RETURNING id
+		), orphaned AS (
+			UPDATE categories SET parent_id=NULL,updated_at=now()
+			WHERE parent_id IN (SELECT id FROM archived)
+		)
+		SELECT id FROM archived`,
 		categoryID, userID,
 	)
 	if err != nil {
@@ -55,6 +225,17 @@ func (r *Repository) DeleteCategory(ctx context.Context, userID, categoryID int)
 	return nil
 }
 
+func scanCategory(row rowScanner) (model.Category, error) {
+	var category model.Category
+	var parentID pgtype.Int4
+	err := row.Scan(&category.ID, &category.Type, &category.Name, &category.IsDefault, &parentID)
+	if parentID.Valid {
+		value := int(parentID.Int32)
+		category.ParentID = &value
+	}
+	return category, err
+}
+
 func (r *Repository) FindActiveCategoryName(ctx context.Context, userID int, transactionType, name string) (string, error) {
 	var canonicalName string
 	err := r.db.QueryRow(ctx, `SELECT name FROM categories
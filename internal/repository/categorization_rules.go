@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"money-manager-server/internal/model"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CategorizationRuleTarget is a booked row the rules are re-run against.
+type CategorizationRuleTarget struct {
+	TransactionID        int
+	Type                 string
+	Category             string
+	Description          string
+	Amount               string
+	AccountID            int
+	MerchantCategoryCode string
+	Tags                 []string
+	ExcludedFromBudget   bool
+}
+
+// CategorizationRuleUpdate is the outcome of the rules for one row.
+type CategorizationRuleUpdate struct {
+	TransactionID      int
+	Category           string
+	Tags               []string
+	ExcludedFromBudget bool
+}
+
+const categorizationRuleColumns = `id,name,priority,enabled,
+	COALESCE(description_contains,''),COALESCE(description_pattern,''),
+	COALESCE(amount_min::text,''),COALESCE(amount_max::text,''),account_id,
+	COALESCE(merchant_category_code,''),COALESCE(transaction_type,''),
+	COALESCE(category,''),tags,exclude_from_budget,mark_as_transfer,
+	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// ListCategorizationRules returns rules in evaluation order: highest
+// priority first, then oldest first.
+func (r *Repository) ListCategorizationRules(ctx context.Context, userID int) ([]model.CategorizationRule, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+categorizationRuleColumns+`
+		FROM categorization_rules WHERE user_id=$1 ORDER BY priority DESC,id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := make([]model.CategorizationRule, 0)
+	for rows.Next() {
+		rule, err := scanCategorizationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// ListActiveCategorizationRules returns the enabled rules in evaluation
+// order. A category action whose category has since been archived is
+// dropped so the rule's other actions still apply.
+func (r *Repository) ListActiveCategorizationRules(ctx context.Context, userID int) ([]model.CategorizationRule, error) {
+	rules, err := r.ListCategorizationRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]model.CategorizationRule, 0, len(rules))
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if rule.Actions.Category != "" {
+			var available bool
+			if err := r.db.QueryRow(ctx, `SELECT EXISTS(
+				SELECT 1 FROM categories
+				WHERE user_id=$1 AND type=$2 AND name=$3 AND active
+			)`, userID, rule.Conditions.Type, rule.Actions.Category).Scan(&available); err != nil {
+				return nil, err
+			}
+			if !available {
+				rule.Actions.Category = ""
+			}
+		}
+		active = append(active, rule)
+	}
+	return active, nil
+}
+
+func (r *Repository) GetCategorizationRule(ctx context.Context, userID, ruleID int) (model.CategorizationRule, error) {
+	rule, err := scanCategorizationRule(r.db.QueryRow(ctx, `SELECT `+categorizationRuleColumns+`
+		FROM categorization_rules WHERE id=$1 AND user_id=$2`, ruleID, userID))
+	return rule, mapNotFound(err)
+}
+
+// CreateCategorizationRule stores a validated rule. A rule scoped to an
+// account the user does not own reports ErrNotFound.
+func (r *Repository) CreateCategorizationRule(
+	ctx context.Context,
+	userID int,
+	request model.CategorizationRuleRequest,
+) (model.CategorizationRule, error) {
+	rule, err := scanCategorizationRule(r.db.QueryRow(ctx, `INSERT INTO categorization_rules(
+		user_id,name,priority,enabled,description_contains,description_pattern,amount_min,amount_max,
+		account_id,merchant_category_code,transaction_type,category,tags,exclude_from_budget,mark_as_transfer
+	)
+	SELECT $1,$2,$3,$4,NULLIF($5,''),NULLIF($6,''),NULLIF($7,'')::numeric,NULLIF($8,'')::numeric,
+		$9,NULLIF($10,''),NULLIF($11,''),NULLIF($12,''),$13,$14,$15
+	WHERE $9::bigint IS NULL OR EXISTS(`+ownedOpenBankingAccount+`)
+	RETURNING `+categorizationRuleColumns, categorizationRuleArguments(userID, request)...))
+	return rule, mapNotFound(err)
+}
+
+func (r *Repository) UpdateCategorizationRule(
+	ctx context.Context,
+	userID, ruleID int,
+	request model.CategorizationRuleRequest,
+) (model.CategorizationRule, error) {
+	arguments := append(categorizationRuleArguments(userID, request), ruleID)
+	rule, err := scanCategorizationRule(r.db.QueryRow(ctx, `UPDATE categorization_rules SET
+		name=$2,priority=$3,enabled=$4,description_contains=NULLIF($5,''),description_pattern=NULLIF($6,''),
+		amount_min=NULLIF($7,'')::numeric,amount_max=NULLIF($8,'')::numeric,account_id=$9,
+		merchant_category_code=NULLIF($10,''),transaction_type=NULLIF($11,''),category=NULLIF($12,''),
+		tags=$13,exclude_from_budget=$14,mark_as_transfer=$15,updated_at=now()
+	WHERE id=$16 AND user_id=$1 AND ($9::bigint IS NULL OR EXISTS(`+ownedOpenBankingAccount+`))
+	RETURNING `+categorizationRuleColumns, arguments...))
+	return rule, mapNotFound(err)
+}
+
+func (r *Repository) DeleteCategorizationRule(ctx context.Context, userID, ruleID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM categorization_rules WHERE id=$1 AND user_id=$2`, ruleID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const ownedOpenBankingAccount = `SELECT 1 FROM open_banking_accounts a
+	JOIN open_banking_connections c ON c.id=a.connection_id
+	WHERE a.id=$9 AND c.user_id=$1`
+
+func categorizationRuleArguments(userID int, request model.CategorizationRuleRequest) []any {
+	conditions, actions := request.Conditions, request.Actions
+	return []any{
+		userID, request.Name, request.Priority, request.Enabled == nil || *request.Enabled,
+		conditions.DescriptionContains, conditions.DescriptionPattern, conditions.AmountMin, conditions.AmountMax,
+		conditions.AccountID, conditions.MerchantCategoryCode, conditions.Type,
+		actions.Category, transactionTags(actions.Tags), actions.ExcludeFromBudget, actions.MarkAsTransfer,
+	}
+}
+
+// ListCategorizationRuleTargets returns booked rows in a date range for a
+// rules re-run. Synced rows whose classification the user corrected are
+// left out so a re-run never undoes a manual fix.
+func (r *Repository) ListCategorizationRuleTargets(
+	ctx context.Context,
+	userID int,
+	from, toExclusive time.Time,
+) ([]CategorizationRuleTarget, error) {
+	rows, err := r.db.Query(ctx, `SELECT id,type,category,description,amount::text,source_account_id,
+			COALESCE(source_metadata->>'merchant_category_code',''),tags,excluded_from_budget
+		FROM transactions
+		WHERE user_id=$1 AND status='booked' AND occurred_at >= $2 AND occurred_at < $3
+			AND NOT COALESCE(source_metadata @> '{"classification_override":true}'::jsonb,false)
+			AND NOT COALESCE(source_metadata @> '{"category_override":true}'::jsonb,false)
+		ORDER BY id`, userID, from, toExclusive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	targets := make([]CategorizationRuleTarget, 0)
+	for rows.Next() {
+		var target CategorizationRuleTarget
+		var accountID pgtype.Int8
+		if err := rows.Scan(
+			&target.TransactionID, &target.Type, &target.Category, &target.Description, &target.Amount,
+			&accountID, &target.MerchantCategoryCode, &target.Tags, &target.ExcludedFromBudget,
+		); err != nil {
+			return nil, err
+		}
+		if accountID.Valid {
+			target.AccountID = int(accountID.Int64)
+		}
+		if target.Tags == nil {
+			target.Tags = []string{}
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+// ApplyCategorizationRuleUpdates writes re-run outcomes in one transaction
+// and reports how many rows actually changed. A synced row whose category
+// changes records the rule as its category source.
+func (r *Repository) ApplyCategorizationRuleUpdates(
+	ctx context.Context,
+	userID int,
+	updates []CategorizationRuleUpdate,
+) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	updated := 0
+	for _, update := range updates {
+		tag, err := tx.Exec(ctx, `UPDATE transactions SET
+				source_metadata=CASE
+					WHEN source='open_banking' AND category IS DISTINCT FROM $1
+					THEN source_metadata || '{"category_source":"rule"}'::jsonb
+					ELSE source_metadata
+				END,
+				category=$1,tags=$2,excluded_from_budget=$3,updated_at=now()
+			WHERE id=$4 AND user_id=$5
+				AND (category,tags,excluded_from_budget) IS DISTINCT FROM ($1,$2::text[],$3)`,
+			update.Category, transactionTags(update.Tags), update.ExcludedFromBudget, update.TransactionID, userID)
+		if err != nil {
+			return 0, err
+		}
+		updated += int(tag.RowsAffected())
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func scanCategorizationRule(row rowScanner) (model.CategorizationRule, error) {
+	var rule model.CategorizationRule
+	var accountID pgtype.Int8
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Priority, &rule.Enabled,
+		&rule.Conditions.DescriptionContains, &rule.Conditions.DescriptionPattern,
+		&rule.Conditions.AmountMin, &rule.Conditions.AmountMax, &accountID,
+		&rule.Conditions.MerchantCategoryCode, &rule.Conditions.Type,
+		&rule.Actions.Category, &rule.Actions.Tags, &rule.Actions.ExcludeFromBudget, &rule.Actions.MarkAsTransfer,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if accountID.Valid {
+		value := int(accountID.Int64)
+		rule.Conditions.AccountID = &value
+	}
+	if len(rule.Actions.Tags) == 0 {
+		rule.Actions.Tags = nil
+	}
+	return rule, err
+}
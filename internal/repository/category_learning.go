@@ -0,0 +1,47 @@
+package repository
+
+import "context"
+
+// CategoryTrainingExample is a row whose category a person chose.
+type CategoryTrainingExample struct {
+	Type        string
+	Category    string
+	Description string
+	Amount      string
+}
+
+// ListCategoryTrainingExamples returns the most recent examples whose
+// category is still active, so the classifier never proposes an archived
+// category.
+func (r *Repository) ListCategoryTrainingExamples(ctx context.Context, userID, limit int) ([]CategoryTrainingExample, error) {
+	rows, err := r.db.Query(ctx, `SELECT t.type,c.name,t.description,t.amount::text
+		FROM transactions t
+		JOIN categories c ON c.user_id=t.user_id AND c.type=t.type AND lower(c.name)=lower(t.category) AND c.active
+		WHERE t.user_id=$1 AND t.category_learned_at IS NOT NULL
+		ORDER BY t.category_learned_at DESC,t.id DESC
+		LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	examples := make([]CategoryTrainingExample, 0)
+	for rows.Next() {
+		var example CategoryTrainingExample
+		if err := rows.Scan(&example.Type, &example.Category, &example.Description, &example.Amount); err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+	return examples, rows.Err()
+}
+
+// ResetCategoryLearning forgets every training example. Transactions keep
+// their categories; they just stop teaching the classifier.
+func (r *Repository) ResetCategoryLearning(ctx context.Context, userID int) (int, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE transactions SET category_learned_at=NULL
+		WHERE user_id=$1 AND category_learned_at IS NOT NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"money-manager-server/internal/model"
+)
+
+const csvImportProfileColumns = `id,name,delimiter,encoding,date_format,decimal_separator,columns,
+	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+func (r *Repository) ListCSVImportProfiles(ctx context.Context, userID int) ([]model.CSVImportProfile, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+csvImportProfileColumns+`
+		FROM csv_import_profiles WHERE user_id=$1 ORDER BY lower(name),id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.CSVImportProfile, 0)
+	for rows.Next() {
+		item, err := scanCSVImportProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetCSVImportProfile(ctx context.Context, userID, profileID int) (model.CSVImportProfile, error) {
+	item, err := scanCSVImportProfile(r.db.QueryRow(ctx, `SELECT `+csvImportProfileColumns+`
+		FROM csv_import_profiles WHERE id=$1 AND user_id=$2`, profileID, userID))
+	return item, mapNotFound(err)
+}
+
+func (r *Repository) CreateCSVImportProfile(ctx context.Context, userID int, request model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	columns, err := json.Marshal(request.Columns)
+	if err != nil {
+		return model.CSVImportProfile{}, fmt.Errorf("encode CSV import columns: %w", err)
+	}
+	item, err := scanCSVImportProfile(r.db.QueryRow(ctx, `INSERT INTO csv_import_profiles(
+		user_id,name,delimiter,encoding,date_format,decimal_separator,columns
+	) VALUES($1,$2,$3,$4,$5,$6,$7)
+	RETURNING `+csvImportProfileColumns,
+		userID, request.Name, request.Delimiter, request.Encoding, request.DateFormat,
+		request.DecimalSeparator, columns))
+	return item, mapConflict(err)
+}
+
+func (r *Repository) UpdateCSVImportProfile(ctx context.Context, userID, profileID int, request model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	columns, err := json.Marshal(request.Columns)
+	if err != nil {
+		return model.CSVImportProfile{}, fmt.Errorf("encode CSV import columns: %w", err)
+	}
+	item, err := scanCSVImportProfile(r.db.QueryRow(ctx, `UPDATE csv_import_profiles
+		SET name=$1,delimiter=$2,encoding=$3,date_format=$4,decimal_separator=$5,columns=$6,updated_at=now()
+		WHERE id=$7 AND user_id=$8
+		RETURNING `+csvImportProfileColumns,
+		request.Name, request.Delimiter, request.Encoding, request.DateFormat,
+		request.DecimalSeparator, columns, profileID, userID))
+	return item, mapNotFound(mapConflict(err))
+}
+
+func (r *Repository) DeleteCSVImportProfile(ctx context.Context, userID, profileID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM csv_import_profiles WHERE id=$1 AND user_id=$2`, profileID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanCSVImportProfile(row rowScanner) (model.CSVImportProfile, error) {
+	var item model.CSVImportProfile
+	var columns []byte
+	if err := row.Scan(
+		&item.ID, &item.Name, &item.Delimiter, &item.Encoding, &item.DateFormat,
+		&item.DecimalSeparator, &columns, &item.CreatedAt, &item.UpdatedAt,
+	); err != nil {
+		return model.CSVImportProfile{}, err
+	}
+	if err := json.Unmarshal(columns, &item.Columns); err != nil {
+		return model.CSVImportProfile{}, fmt.Errorf("decode CSV import columns: %w", err)
+	}
+	return item, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"money-manager-server/internal/model"
+)
+
+func (r *Repository) GetForecastSettings(ctx context.Context, userID int) (model.ForecastSettings, error) {
+	var settings model.ForecastSettings
+	err := r.db.QueryRow(ctx, `SELECT COALESCE((
+			SELECT low_balance_threshold::text FROM forecast_settings WHERE user_id=$1
+		),'')`, userID).Scan(&settings.LowBalanceThreshold)
+	return settings, err
+}
+
+func (r *Repository) UpdateForecastSettings(ctx context.Context, userID int, settings model.ForecastSettings) (model.ForecastSettings, error) {
+	if _, err := r.db.Exec(ctx, `INSERT INTO forecast_settings(user_id,low_balance_threshold)
+		VALUES($1,NULLIF($2,'')::numeric)
+		ON CONFLICT(user_id) DO UPDATE SET
+			low_balance_threshold=EXCLUDED.low_balance_threshold,updated_at=now()`,
+		userID, settings.LowBalanceThreshold); err != nil {
+		return model.ForecastSettings{}, err
+	}
+	return r.GetForecastSettings(ctx, userID)
+}
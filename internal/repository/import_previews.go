@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+// ImportPreviewRecord is a stored import dry run. Each row keeps its import
+// fingerprint next to the fields shown to the client, so a commit imports
+// exactly what was previewed.
+type ImportPreviewRecord struct {
+	ID        int
+	Source    string
+	Rows      []ImportPreviewRowRecord
+	CreatedAt string
+	ExpiresAt string
+}
+
+type ImportPreviewRowRecord struct {
+	model.ImportPreviewRow
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+const importPreviewColumns = `id,source,rows,
+	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(expires_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// CreateImportPreview stores a dry run and drops the user's expired ones.
+func (r *Repository) CreateImportPreview(
+	ctx context.Context,
+	userID int,
+	source string,
+	rows []ImportPreviewRowRecord,
+	expiresAt time.Time,
+) (ImportPreviewRecord, error) {
+	encoded, err := json.Marshal(rows)
+	if err != nil {
+		return ImportPreviewRecord{}, fmt.Errorf("encode import preview rows: %w", err)
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return ImportPreviewRecord{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `DELETE FROM import_previews WHERE user_id=$1 AND expires_at<=now()`, userID); err != nil {
+		return ImportPreviewRecord{}, err
+	}
+	record, err := scanImportPreview(tx.QueryRow(ctx, `INSERT INTO import_previews(user_id,source,rows,expires_at)
+		VALUES($1,$2,$3,$4)
+		RETURNING `+importPreviewColumns, userID, source, encoded, expiresAt))
+	if err != nil {
+		return ImportPreviewRecord{}, err
+	}
+	return record, tx.Commit(ctx)
+}
+
+func (r *Repository) GetImportPreview(ctx context.Context, userID, previewID int) (ImportPreviewRecord, error) {
+	record, err := scanImportPreview(r.db.QueryRow(ctx, `SELECT `+importPreviewColumns+`
+		FROM import_previews WHERE id=$1 AND user_id=$2 AND expires_at>now()`, previewID, userID))
+	return record, mapNotFound(err)
+}
+
+func (r *Repository) DeleteImportPreview(ctx context.Context, userID, previewID int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM import_previews WHERE id=$1 AND user_id=$2`, previewID, userID)
+	return err
+}
+
+// DeleteExpiredImportPreviews drops every user's expired previews, including
+// ones abandoned by users who never import again.
+func (r *Repository) DeleteExpiredImportPreviews(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM import_previews WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ExistingImportFingerprints returns the fingerprints that were already
+// imported from the same source, including rows since merged into a
+// duplicate from another source, even if that duplicate was later deleted.
+func (r *Repository) ExistingImportFingerprints(ctx context.Context, userID int, source string, fingerprints []string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT import_fingerprint FROM transactions
+		WHERE user_id=$1 AND import_source=$2 AND import_fingerprint=ANY($3)
+		UNION
+		SELECT import_fingerprint FROM transaction_merges
+		WHERE user_id=$1 AND import_source=$2 AND import_fingerprint=ANY($3)`, userID, source, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	existing := make([]string, 0)
+	for rows.Next() {
+		var fingerprint string
+		if err := rows.Scan(&fingerprint); err != nil {
+			return nil, err
+		}
+		existing = append(existing, fingerprint)
+	}
+	return existing, rows.Err()
+}
+
+func scanImportPreview(row rowScanner) (ImportPreviewRecord, error) {
+	var record ImportPreviewRecord
+	var rows []byte
+	if err := row.Scan(&record.ID, &record.Source, &rows, &record.CreatedAt, &record.ExpiresAt); err != nil {
+		return ImportPreviewRecord{}, err
+	}
+	if err := json.Unmarshal(rows, &record.Rows); err != nil {
+		return ImportPreviewRecord{}, fmt.Errorf("decode import preview rows: %w", err)
+	}
+	return record, nil
+}
@@ -25,11 +25,14 @@ type InvestmentScheduleOccurrenceSeed struct {
 	ScheduleID   int
 	UserID       int
 	ScheduledFor time.Time
+	NominalDate  time.Time
+	Amount       string
 }
 
 type DueInvestmentScheduleOccurrence struct {
 	ID           int
 	ScheduledFor time.Time
+	Amount       string
 	Schedule     model.InvestmentSchedule
 }
 
@@ -307,7 +310,7 @@ func (r *Repository) UpsertInvestmentMarketHistory(
 
 const investmentScheduleSelect = `SELECT id,user_id,asset_type,symbol,asset_name,exchange,market_currency,broker,amount::text,currency,
 	frequency,frequency_interval,to_char(start_date,'YYYY-MM-DD'),COALESCE(to_char(end_date,'YYYY-MM-DD'),''),
-	day_of_week,day_of_month,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
+	day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
 	COALESCE(to_char(materialized_through,'YYYY-MM-DD'),''),COALESCE(to_char(last_posted_on,'YYYY-MM-DD'),''),
 	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 	to_char(updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
@@ -317,17 +320,18 @@ func (r *Repository) CreateInvestmentSchedule(ctx context.Context, userID int, r
 	request = investmentScheduleMarketDefaults(request)
 	row := r.db.QueryRow(ctx, `INSERT INTO investment_schedules(
 		user_id,asset_type,symbol,asset_name,exchange,market_currency,broker,amount,currency,frequency,frequency_interval,
-		start_date,end_date,day_of_week,day_of_month,timezone
-	) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NULLIF($13,'')::date,$14,$15,$16)
+		start_date,end_date,day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,timezone
+	) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NULLIF($13,'')::date,$14,$15,$16,COALESCE(NULLIF($17,''),'none'),$18,$19)
 		RETURNING id,user_id,asset_type,symbol,asset_name,exchange,market_currency,broker,amount::text,currency,
 			frequency,frequency_interval,to_char(start_date,'YYYY-MM-DD'),COALESCE(to_char(end_date,'YYYY-MM-DD'),''),
-			day_of_week,day_of_month,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
+			day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
 			COALESCE(to_char(materialized_through,'YYYY-MM-DD'),''),COALESCE(to_char(last_posted_on,'YYYY-MM-DD'),''),
 			to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 		to_char(updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
 		userID, request.AssetType, request.Symbol, request.AssetName, request.Exchange, request.MarketCurrency,
 		request.Broker, request.Amount, request.Currency, request.Frequency, request.FrequencyInterval,
-		request.StartDate, request.EndDate, request.DayOfWeek, request.DayOfMonth, request.Timezone)
+		request.StartDate, request.EndDate, request.DayOfWeek, request.DayOfMonth, request.RRule,
+		request.BusinessDayAdjustment, request.HolidayCalendar, request.Timezone)
 	return scanInvestmentSchedule(row)
 }
 
@@ -366,24 +370,48 @@ func (r *Repository) UpdateInvestmentSchedule(
 	ctx context.Context,
 	userID, scheduleID int,
 	request model.InvestmentScheduleRequest,
+	today time.Time,
 ) (model.InvestmentSchedule, error) {
 	request = investmentScheduleMarketDefaults(request)
-	row := r.db.QueryRow(ctx, `UPDATE investment_schedules SET
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.InvestmentSchedule{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	row := tx.QueryRow(ctx, `UPDATE investment_schedules SET
 		asset_type=$1,symbol=$2,asset_name=$3,exchange=$4,market_currency=$5,broker=$6,amount=$7,currency=$8,
 		frequency=$9,frequency_interval=$10,start_date=$11,end_date=NULLIF($12,'')::date,
-		day_of_week=$13,day_of_month=$14,timezone=$15,last_notified_on=NULL,updated_at=now()
-		WHERE id=$16 AND user_id=$17 AND status <> 'archived'
+		day_of_week=$13,day_of_month=$14,rrule=$15,business_day_adjustment=COALESCE(NULLIF($16,''),'none'),holiday_calendar=$17,
+		timezone=$18,last_notified_on=NULL,
+		materialized_through=CASE WHEN materialized_through >= $21::date THEN $21::date-1 ELSE materialized_through END,
+		updated_at=now()
+		WHERE id=$19 AND user_id=$20 AND status <> 'archived'
 		RETURNING id,user_id,asset_type,symbol,asset_name,exchange,market_currency,broker,amount::text,currency,
 			frequency,frequency_interval,to_char(start_date,'YYYY-MM-DD'),COALESCE(to_char(end_date,'YYYY-MM-DD'),''),
-			day_of_week,day_of_month,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
+			day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,timezone,status,COALESCE(to_char(last_notified_on,'YYYY-MM-DD'),''),
 			COALESCE(to_char(materialized_through,'YYYY-MM-DD'),''),COALESCE(to_char(last_posted_on,'YYYY-MM-DD'),''),
 			to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 		to_char(updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
 		request.AssetType, request.Symbol, request.AssetName, request.Exchange, request.MarketCurrency,
 		request.Broker, request.Amount, request.Currency, request.Frequency, request.FrequencyInterval,
-		request.StartDate, request.EndDate, request.DayOfWeek, request.DayOfMonth, request.Timezone, scheduleID, userID)
+		request.StartDate, request.EndDate, request.DayOfWeek, request.DayOfMonth, request.RRule,
+		request.BusinessDayAdjustment, request.HolidayCalendar, request.Timezone, scheduleID, userID, today)
 	item, err := scanInvestmentSchedule(row)
-	return item, mapNotFound(err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.InvestmentSchedule{}, ErrNotFound
+	}
+	if err != nil {
+		return model.InvestmentSchedule{}, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM investment_schedule_occurrences
+		WHERE schedule_id=$1 AND status='planned' AND NOT overridden AND scheduled_for >= $2::date`, scheduleID, today); err != nil {
+		return model.InvestmentSchedule{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.InvestmentSchedule{}, err
+	}
+	return item, nil
 }
 
 func investmentScheduleMarketDefaults(request model.InvestmentScheduleRequest) model.InvestmentScheduleRequest {
@@ -451,9 +479,9 @@ func (r *Repository) UpsertInvestmentScheduleOccurrences(
 	defer func() { _ = tx.Rollback(ctx) }()
 	inserted := 0
 	for _, seed := range seeds {
-		tag, err := tx.Exec(ctx, `INSERT INTO investment_schedule_occurrences(schedule_id,user_id,scheduled_for)
-			VALUES($1,$2,$3::date) ON CONFLICT(schedule_id,scheduled_for) DO NOTHING`,
-			seed.ScheduleID, seed.UserID, seed.ScheduledFor.UTC())
+		tag, err := tx.Exec(ctx, `INSERT INTO investment_schedule_occurrences(schedule_id,user_id,scheduled_for,nominal_date,amount)
+			VALUES($1,$2,$3::date,$4::date,$5) ON CONFLICT(schedule_id,nominal_date) DO NOTHING`,
+			seed.ScheduleID, seed.UserID, seed.ScheduledFor.UTC(), seed.NominalDate.UTC(), seed.Amount)
 		if err != nil {
 			return 0, err
 		}
@@ -487,12 +515,12 @@ func (r *Repository) ListDueInvestmentScheduleOccurrences(
 	now time.Time,
 	limit int,
 ) ([]DueInvestmentScheduleOccurrence, error) {
-	rows, err := r.db.Query(ctx, `SELECT occurrence.id,occurrence.scheduled_for,
+	rows, err := r.db.Query(ctx, `SELECT occurrence.id,occurrence.scheduled_for,occurrence.amount::text,
 		schedule.id,schedule.user_id,schedule.asset_type,schedule.symbol,schedule.asset_name,
 		schedule.exchange,schedule.market_currency,schedule.broker,schedule.amount::text,schedule.currency,
 		schedule.frequency,schedule.frequency_interval,to_char(schedule.start_date,'YYYY-MM-DD'),
 		COALESCE(to_char(schedule.end_date,'YYYY-MM-DD'),''),schedule.day_of_week,schedule.day_of_month,
-		schedule.timezone,schedule.status,COALESCE(to_char(schedule.last_notified_on,'YYYY-MM-DD'),''),
+		schedule.rrule,schedule.business_day_adjustment,schedule.holiday_calendar,schedule.timezone,schedule.status,COALESCE(to_char(schedule.last_notified_on,'YYYY-MM-DD'),''),
 		COALESCE(to_char(schedule.materialized_through,'YYYY-MM-DD'),''),
 		COALESCE(to_char(schedule.last_posted_on,'YYYY-MM-DD'),''),
 		to_char(schedule.created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
@@ -511,12 +539,13 @@ func (r *Repository) ListDueInvestmentScheduleOccurrences(
 		var item DueInvestmentScheduleOccurrence
 		var dayOfWeek, dayOfMonth pgtype.Int2
 		if err := rows.Scan(
-			&item.ID, &item.ScheduledFor,
+			&item.ID, &item.ScheduledFor, &item.Amount,
 			&item.Schedule.ID, &item.Schedule.UserID, &item.Schedule.AssetType, &item.Schedule.Symbol,
 			&item.Schedule.AssetName, &item.Schedule.Exchange, &item.Schedule.MarketCurrency,
 			&item.Schedule.Broker, &item.Schedule.Amount, &item.Schedule.Currency,
 			&item.Schedule.Frequency, &item.Schedule.FrequencyInterval, &item.Schedule.StartDate,
-			&item.Schedule.EndDate, &dayOfWeek, &dayOfMonth, &item.Schedule.Timezone,
+			&item.Schedule.EndDate, &dayOfWeek, &dayOfMonth, &item.Schedule.RRule,
+			&item.Schedule.BusinessDayAdjustment, &item.Schedule.HolidayCalendar, &item.Schedule.Timezone,
 			&item.Schedule.Status, &item.Schedule.LastNotifiedOn, &item.Schedule.MaterializedThrough,
 			&item.Schedule.LastPostedOn, &item.Schedule.CreatedAt, &item.Schedule.UpdatedAt,
 		); err != nil {
@@ -538,6 +567,91 @@ func (r *Repository) ListDueInvestmentScheduleOccurrences(
 	return items, rows.Err()
 }
 
+const investmentScheduleOccurrenceColumns = `occurrence.id,occurrence.schedule_id,
+	to_char(occurrence.scheduled_for,'YYYY-MM-DD'),to_char(occurrence.nominal_date,'YYYY-MM-DD'),occurrence.status,
+	schedule.symbol,schedule.asset_name,occurrence.amount::text,schedule.currency,occurrence.overridden`
+
+func (r *Repository) ListInvestmentScheduleOccurrences(
+	ctx context.Context,
+	userID int,
+	filter ScheduleOccurrenceFilter,
+) ([]model.InvestmentScheduleOccurrence, error) {
+	query := `SELECT ` + investmentScheduleOccurrenceColumns + `
+		FROM investment_schedule_occurrences occurrence
+		JOIN investment_schedules schedule ON schedule.id=occurrence.schedule_id
+		WHERE occurrence.user_id=$1 AND occurrence.scheduled_for >= $2 AND occurrence.scheduled_for <= $3`
+	args := []any{userID, filter.From, filter.Through}
+	if filter.ScheduleID > 0 {
+		query += fmt.Sprintf(" AND occurrence.schedule_id=$%d", len(args)+1)
+		args = append(args, filter.ScheduleID)
+	}
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND occurrence.status=$%d", len(args)+1)
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY occurrence.scheduled_for,occurrence.id`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.InvestmentScheduleOccurrence, 0)
+	for rows.Next() {
+		item, err := scanInvestmentScheduleOccurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.InvestmentScheduleOccurrence, error) {
+	item, err := scanInvestmentScheduleOccurrence(r.db.QueryRow(ctx, `SELECT `+investmentScheduleOccurrenceColumns+`
+		FROM investment_schedule_occurrences occurrence
+		JOIN investment_schedules schedule ON schedule.id=occurrence.schedule_id
+		WHERE occurrence.user_id=$1 AND occurrence.id=$2`, userID, occurrenceID))
+	return item, mapNotFound(err)
+}
+
+// UpdateInvestmentScheduleOccurrence overrides the date and amount of a
+// planned buy. Overridden occurrences are kept when the schedule is edited.
+func (r *Repository) UpdateInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	request model.InvestmentScheduleOccurrenceRequest,
+) (model.InvestmentScheduleOccurrence, error) {
+	item, err := scanInvestmentScheduleOccurrence(r.db.QueryRow(ctx, `UPDATE investment_schedule_occurrences occurrence
+		SET scheduled_for=$1,amount=$2,overridden=true,updated_at=now()
+		FROM investment_schedules schedule
+		WHERE schedule.id=occurrence.schedule_id AND occurrence.id=$3 AND occurrence.user_id=$4
+		  AND occurrence.status='planned'
+		RETURNING `+investmentScheduleOccurrenceColumns,
+		request.ScheduledFor, request.Amount, occurrenceID, userID))
+	return item, mapNotFound(err)
+}
+
+// SetInvestmentScheduleOccurrenceStatus moves an occurrence between planned
+// and skipped. It returns ErrNotFound when the occurrence is not in status
+// from.
+func (r *Repository) SetInvestmentScheduleOccurrenceStatus(
+	ctx context.Context,
+	userID, occurrenceID int,
+	from, to string,
+) (model.InvestmentScheduleOccurrence, error) {
+	item, err := scanInvestmentScheduleOccurrence(r.db.QueryRow(ctx, `UPDATE investment_schedule_occurrences occurrence
+		SET status=$1,updated_at=now()
+		FROM investment_schedules schedule
+		WHERE schedule.id=occurrence.schedule_id AND occurrence.id=$2 AND occurrence.user_id=$3
+		  AND occurrence.status=$4
+		RETURNING `+investmentScheduleOccurrenceColumns, to, occurrenceID, userID, from))
+	return item, mapNotFound(err)
+}
+
 func (r *Repository) PostInvestmentScheduleOccurrence(
 	ctx context.Context,
 	occurrenceID int,
@@ -626,13 +740,21 @@ func scanInvestmentTrade(row rowScanner) (model.InvestmentTrade, error) {
 	return item, err
 }
 
+func scanInvestmentScheduleOccurrence(row rowScanner) (model.InvestmentScheduleOccurrence, error) {
+	var item model.InvestmentScheduleOccurrence
+	err := row.Scan(&item.ID, &item.ScheduleID, &item.ScheduledFor, &item.NominalDate, &item.Status,
+		&item.Symbol, &item.AssetName, &item.Amount, &item.Currency, &item.Overridden)
+	return item, err
+}
+
 func scanInvestmentSchedule(row rowScanner) (model.InvestmentSchedule, error) {
 	var item model.InvestmentSchedule
 	var dayOfWeek, dayOfMonth pgtype.Int2
 	err := row.Scan(&item.ID, &item.UserID, &item.AssetType, &item.Symbol, &item.AssetName,
 		&item.Exchange, &item.MarketCurrency, &item.Broker, &item.Amount, &item.Currency,
 		&item.Frequency, &item.FrequencyInterval,
-		&item.StartDate, &item.EndDate, &dayOfWeek, &dayOfMonth, &item.Timezone, &item.Status,
+		&item.StartDate, &item.EndDate, &dayOfWeek, &dayOfMonth, &item.RRule,
+		&item.BusinessDayAdjustment, &item.HolidayCalendar, &item.Timezone, &item.Status,
 		&item.LastNotifiedOn, &item.MaterializedThrough, &item.LastPostedOn,
 		&item.CreatedAt, &item.UpdatedAt)
 	if err != nil {
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"money-manager-server/internal/model"
+)
+
+const loanSelect = `SELECT l.id,l.name,l.kind,l.principal::text,l.annual_rate::text,l.term_months,
+	to_char(l.start_date,'YYYY-MM-DD'),l.payment_day,l.currency,l.status,
+	to_char(l.created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(l.updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
+FROM loans l
+WHERE l.user_id=$1`
+
+func (r *Repository) ListLoans(ctx context.Context, userID int, includeArchived bool) ([]model.Loan, error) {
+	query := loanSelect
+	if !includeArchived {
+		query += ` AND l.status='active'`
+	}
+	query += ` ORDER BY l.name,l.id`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.Loan, 0)
+	for rows.Next() {
+		item, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetLoan(ctx context.Context, userID, loanID int) (model.Loan, error) {
+	item, err := scanLoan(r.db.QueryRow(ctx, loanSelect+` AND l.id=$2`, userID, loanID))
+	return item, mapNotFound(err)
+}
+
+func (r *Repository) CreateLoan(ctx context.Context, userID int, request model.LoanRequest) (model.Loan, error) {
+	var id int
+	if err := r.db.QueryRow(ctx, `INSERT INTO loans(
+			user_id,name,kind,principal,annual_rate,term_months,start_date,payment_day,currency
+		) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`, userID, request.Name, request.Kind, request.Principal,
+		request.AnnualRate, request.TermMonths, request.StartDate, request.PaymentDay, request.Currency).Scan(&id); err != nil {
+		return model.Loan{}, err
+	}
+	return r.GetLoan(ctx, userID, id)
+}
+
+func (r *Repository) UpdateLoan(ctx context.Context, userID, loanID int, request model.LoanRequest) (model.Loan, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE loans SET name=$1,kind=$2,principal=$3,annual_rate=$4,term_months=$5,
+		start_date=$6,payment_day=$7,currency=$8,updated_at=now()
+		WHERE id=$9 AND user_id=$10 AND status='active'`, request.Name, request.Kind, request.Principal,
+		request.AnnualRate, request.TermMonths, request.StartDate, request.PaymentDay, request.Currency, loanID, userID)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.Loan{}, ErrNotFound
+	}
+	return r.GetLoan(ctx, userID, loanID)
+}
+
+func (r *Repository) ArchiveLoan(ctx context.Context, userID, loanID int) error {
+	tag, err := r.db.Exec(ctx, `UPDATE loans SET status='archived',updated_at=now()
+		WHERE id=$1 AND user_id=$2 AND status='active'`, loanID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListLoanPayments returns the payments of a loan, oldest first.
+func (r *Repository) ListLoanPayments(ctx context.Context, userID, loanID int) ([]model.LoanPayment, error) {
+	rows, err := r.db.Query(ctx, `SELECT id,kind,transaction_id,amount::text,to_char(paid_on,'YYYY-MM-DD')
+		FROM loan_payments WHERE loan_id=$1 AND user_id=$2
+		ORDER BY paid_on,id`, loanID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.LoanPayment, 0)
+	for rows.Next() {
+		item, err := scanLoanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CreateLoanPayment records a payment on an active loan. A transaction can be
+// linked to one loan payment only.
+func (r *Repository) CreateLoanPayment(
+	ctx context.Context,
+	userID, loanID int,
+	request model.LoanPaymentRequest,
+) (model.LoanPayment, error) {
+	item, err := scanLoanPayment(r.db.QueryRow(ctx, `INSERT INTO loan_payments(loan_id,user_id,kind,transaction_id,amount,paid_on)
+		SELECT id,user_id,$3,$4,$5,$6 FROM loans WHERE id=$1 AND user_id=$2 AND status='active'
+		RETURNING id,kind,transaction_id,amount::text,to_char(paid_on,'YYYY-MM-DD')`,
+		loanID, userID, request.Kind, request.TransactionID, request.Amount, request.Date))
+	if err != nil {
+		return model.LoanPayment{}, mapConflict(mapNotFound(err))
+	}
+	return item, nil
+}
+
+func (r *Repository) DeleteLoanPayment(ctx context.Context, userID, loanID, paymentID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM loan_payments p
+		USING loans l
+		WHERE p.id=$1 AND p.loan_id=$2 AND p.user_id=$3 AND l.id=p.loan_id AND l.status='active'`,
+		paymentID, loanID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanLoan(row rowScanner) (model.Loan, error) {
+	var item model.Loan
+	err := row.Scan(&item.ID, &item.Name, &item.Kind, &item.Principal, &item.AnnualRate, &item.TermMonths,
+		&item.StartDate, &item.PaymentDay, &item.Currency, &item.Status, &item.CreatedAt, &item.UpdatedAt)
+	return item, err
+}
+
+func scanLoanPayment(row rowScanner) (model.LoanPayment, error) {
+	var item model.LoanPayment
+	var transactionID pgtype.Int8
+	err := row.Scan(&item.ID, &item.Kind, &transactionID, &item.Amount, &item.Date)
+	if transactionID.Valid {
+		value := int(transactionID.Int64)
+		item.TransactionID = &value
+	}
+	return item, err
+}
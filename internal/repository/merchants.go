@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"money-manager-server/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MerchantCandidate is a synced or imported row that has no merchant yet.
+type MerchantCandidate struct {
+	TransactionID int
+	Description   string
+}
+
+type MerchantAssignment struct {
+	TransactionID int
+	Merchant      model.MerchantMatch
+}
+
+// ensureMerchant returns the merchant that owns an alias key, creating the
+// merchant and the alias on first sight. A new key whose name matches an
+// existing merchant joins that merchant.
+func ensureMerchant(ctx context.Context, tx pgx.Tx, userID int, match model.MerchantMatch) (int64, error) {
+	var merchantID int64
+	err := tx.QueryRow(ctx, `SELECT merchant_id FROM merchant_aliases WHERE user_id=$1 AND alias=$2`,
+		userID, match.Key).Scan(&merchantID)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return merchantID, err
+	}
+	err = tx.QueryRow(ctx, `WITH merchant AS (
+		INSERT INTO merchants(user_id,name) VALUES($1,$2)
+		ON CONFLICT (user_id,lower(name)) DO UPDATE SET updated_at=merchants.updated_at
+		RETURNING id
+	)
+	INSERT INTO merchant_aliases(user_id,alias,merchant_id)
+	SELECT $1,$3,id FROM merchant
+	ON CONFLICT (user_id,alias) DO UPDATE SET alias=EXCLUDED.alias
+	RETURNING merchant_id`, userID, match.Name, match.Key).Scan(&merchantID)
+	return merchantID, err
+}
+
+// assignMerchant links a transaction to the merchant of match. A row whose
+// description normalized to nothing keeps no merchant.
+func assignMerchant(ctx context.Context, tx pgx.Tx, userID, transactionID int, match model.MerchantMatch) error {
+	if match.Key == "" || match.Name == "" {
+		return nil
+	}
+	merchantID, err := ensureMerchant(ctx, tx, userID, match)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE transactions SET merchant_id=$1 WHERE id=$2 AND user_id=$3`,
+		merchantID, transactionID, userID)
+	return err
+}
+
+// ListMerchantCandidates pages through imported and synced rows without a
+// merchant in id order. Manual rows are left alone since their descriptions
+// are notes rather than bank texts.
+func (r *Repository) ListMerchantCandidates(ctx context.Context, userID, afterID, limit int) ([]MerchantCandidate, error) {
+	rows, err := r.db.Query(ctx, `SELECT id,description FROM transactions
+		WHERE user_id=$1 AND id > $2 AND merchant_id IS NULL AND source IN ('import','open_banking')
+		ORDER BY id LIMIT $3`, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	candidates := make([]MerchantCandidate, 0)
+	for rows.Next() {
+		var candidate MerchantCandidate
+		if err := rows.Scan(&candidate.TransactionID, &candidate.Description); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
+
+func (r *Repository) AssignTransactionMerchants(ctx context.Context, userID int, assignments []MerchantAssignment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	for _, assignment := range assignments {
+		if err := assignMerchant(ctx, tx, userID, assignment.TransactionID, assignment.Merchant); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+const merchantColumns = `m.id,m.name,
+	COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM merchant_aliases a WHERE a.merchant_id=m.id),'{}'),
+	(SELECT COUNT(*) FROM transactions t WHERE t.user_id=m.user_id AND t.merchant_id=m.id)`
+
+func (r *Repository) ListMerchants(ctx context.Context, userID int) ([]model.Merchant, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+merchantColumns+`
+		FROM merchants m WHERE m.user_id=$1 ORDER BY lower(m.name),m.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	merchants := make([]model.Merchant, 0)
+	for rows.Next() {
+		merchant, err := scanMerchant(rows)
+		if err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, merchant)
+	}
+	return merchants, rows.Err()
+}
+
+func (r *Repository) GetMerchant(ctx context.Context, userID, merchantID int) (model.Merchant, error) {
+	merchant, err := scanMerchant(r.db.QueryRow(ctx, `SELECT `+merchantColumns+`
+		FROM merchants m WHERE m.id=$1 AND m.user_id=$2`, merchantID, userID))
+	return merchant, mapNotFound(err)
+}
+
+// RenameMerchant changes the display name. Transactions reference the
+// merchant by id, so they follow without being rewritten.
+func (r *Repository) RenameMerchant(ctx context.Context, userID, merchantID int, name string) (model.Merchant, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE merchants SET name=$1,updated_at=now() WHERE id=$2 AND user_id=$3`,
+		name, merchantID, userID)
+	if err != nil {
+		return model.Merchant{}, mapConflict(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.Merchant{}, ErrNotFound
+	}
+	return r.GetMerchant(ctx, userID, merchantID)
+}
+
+// MergeMerchants moves the aliases and transactions of mergeID to keepID and
+// deletes mergeID, so later imports of its descriptions land on keepID.
+func (r *Repository) MergeMerchants(ctx context.Context, userID, keepID, mergeID int) (model.Merchant, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	var locked int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM (
+		SELECT id FROM merchants WHERE user_id=$1 AND id=ANY($2) ORDER BY id FOR UPDATE
+	) owned`, userID, []int{keepID, mergeID}).Scan(&locked); err != nil {
+		return model.Merchant{}, err
+	}
+	if locked != 2 {
+		return model.Merchant{}, ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `UPDATE merchant_aliases SET merchant_id=$1 WHERE user_id=$2 AND merchant_id=$3`,
+		keepID, userID, mergeID); err != nil {
+		return model.Merchant{}, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE transactions SET merchant_id=$1,updated_at=now() WHERE user_id=$2 AND merchant_id=$3`,
+		keepID, userID, mergeID); err != nil {
+		return model.Merchant{}, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM merchants WHERE id=$1 AND user_id=$2`, mergeID, userID); err != nil {
+		return model.Merchant{}, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE merchants SET updated_at=now() WHERE id=$1`, keepID); err != nil {
+		return model.Merchant{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.Merchant{}, err
+	}
+	return r.GetMerchant(ctx, userID, keepID)
+}
+
+// ListMerchantSpending totals booked expenses per merchant, largest first.
+func (r *Repository) ListMerchantSpending(ctx context.Context, userID int, from, toExclusive time.Time) ([]model.MerchantSpending, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.id,m.name,COUNT(*),SUM(t.amount)::text
+		FROM transactions t JOIN merchants m ON m.id=t.merchant_id
+		WHERE t.user_id=$1 AND t.type='expense' AND t.status='booked' AND t.currency='EUR'
+			AND t.occurred_at >= $2 AND t.occurred_at < $3
+		GROUP BY m.id,m.name
+		ORDER BY SUM(t.amount) DESC,m.id`, userID, from, toExclusive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.MerchantSpending, 0)
+	for rows.Next() {
+		item := model.MerchantSpending{Currency: "EUR"}
+		if err := rows.Scan(&item.MerchantID, &item.Name, &item.Transactions, &item.Total); err != nil {
+			return nil, err
+		}
+		if item.Total, err = decimalWithTwoPlaces(item.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ListMerchantMonthlySpending totals one merchant's booked expenses by
+// calendar month. Months without spending are omitted.
+func (r *Repository) ListMerchantMonthlySpending(
+	ctx context.Context,
+	userID, merchantID int,
+	from, toExclusive time.Time,
+) ([]model.MerchantMonthSpending, error) {
+	rows, err := r.db.Query(ctx, `SELECT to_char(date_trunc('month',occurred_at),'YYYY-MM'),COUNT(*),SUM(amount)::text
+		FROM transactions
+		WHERE user_id=$1 AND merchant_id=$2 AND type='expense' AND status='booked' AND currency='EUR'
+			AND occurred_at >= $3 AND occurred_at < $4
+		GROUP BY 1 ORDER BY 1`, userID, merchantID, from, toExclusive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	months := make([]model.MerchantMonthSpending, 0)
+	for rows.Next() {
+		var month model.MerchantMonthSpending
+		if err := rows.Scan(&month.Month, &month.Transactions, &month.Total); err != nil {
+			return nil, err
+		}
+		if month.Total, err = decimalWithTwoPlaces(month.Total); err != nil {
+			return nil, err
+		}
+		months = append(months, month)
+	}
+	return months, rows.Err()
+}
+
+func scanMerchant(row rowScanner) (model.Merchant, error) {
+	var merchant model.Merchant
+	err := row.Scan(&merchant.ID, &merchant.Name, &merchant.Aliases, &merchant.TransactionCount)
+	if merchant.Aliases == nil {
+		merchant.Aliases = []string{}
+	}
+	return merchant, err
+}
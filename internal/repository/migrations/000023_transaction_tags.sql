@@ -0,0 +1,7 @@
+ALTER TABLE transactions
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT transactions_tags_check
+        CHECK (cardinality(tags) <= 20);
+
+CREATE INDEX transactions_tags_idx
+    ON transactions USING GIN (tags);
@@ -0,0 +1,21 @@
+CREATE TABLE csv_import_profiles (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    delimiter TEXT NOT NULL,
+    encoding TEXT NOT NULL,
+    date_format TEXT NOT NULL,
+    decimal_separator TEXT NOT NULL,
+    columns JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT csv_import_profiles_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 80),
+    CONSTRAINT csv_import_profiles_delimiter_check CHECK (delimiter IN (',', ';', E'\t', '|')),
+    CONSTRAINT csv_import_profiles_encoding_check
+        CHECK (encoding IN ('utf-8', 'utf-16le', 'utf-16be', 'windows-1251', 'windows-1252')),
+    CONSTRAINT csv_import_profiles_decimal_separator_check CHECK (decimal_separator IN ('.', ',')),
+    CONSTRAINT csv_import_profiles_columns_check CHECK (jsonb_typeof(columns) = 'object')
+);
+
+CREATE UNIQUE INDEX csv_import_profiles_user_name_idx
+    ON csv_import_profiles(user_id, lower(name));
@@ -0,0 +1,11 @@
+CREATE TABLE import_previews (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    rows JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT import_previews_rows_check CHECK (jsonb_typeof(rows) = 'array')
+);
+
+CREATE INDEX import_previews_user_expires_idx ON import_previews(user_id, expires_at);
@@ -0,0 +1,23 @@
+CREATE TABLE transaction_merges (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    source_account_id BIGINT REFERENCES open_banking_accounts(id) ON DELETE SET NULL,
+    external_id TEXT,
+    import_source TEXT,
+    import_fingerprint TEXT,
+    description TEXT NOT NULL,
+    amount NUMERIC(14,2) NOT NULL,
+    occurred_at DATE NOT NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT transaction_merges_source_check CHECK (source IN ('manual', 'import', 'open_banking'))
+);
+
+CREATE INDEX transaction_merges_transaction_idx ON transaction_merges(transaction_id);
+
+-- A merged-away import row must not come back when the same file is imported
+-- again, so imports check this index next to the transactions one.
+CREATE UNIQUE INDEX transaction_merges_import_idx
+    ON transaction_merges(user_id, import_source, import_fingerprint)
+    WHERE import_source IS NOT NULL AND import_fingerprint IS NOT NULL;
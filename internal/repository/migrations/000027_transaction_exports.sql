@@ -0,0 +1,28 @@
+CREATE TABLE transaction_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format TEXT NOT NULL,
+    from_date DATE NOT NULL,
+    to_date DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    row_count INT NOT NULL DEFAULT 0,
+    byte_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    claimed_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT transaction_exports_status_check CHECK (status IN ('pending','running','completed','failed')),
+    CONSTRAINT transaction_exports_range_check CHECK (from_date <= to_date)
+);
+
+CREATE INDEX transaction_exports_user_created_idx ON transaction_exports(user_id, created_at DESC);
+CREATE INDEX transaction_exports_queue_idx ON transaction_exports(created_at) WHERE status IN ('pending','running');
+
+CREATE TABLE transaction_export_chunks (
+    export_id BIGINT NOT NULL REFERENCES transaction_exports(id) ON DELETE CASCADE,
+    sequence INT NOT NULL,
+    data BYTEA NOT NULL,
+    PRIMARY KEY (export_id, sequence)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE open_banking_account_balances (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES open_banking_accounts(id) ON DELETE CASCADE,
+    reference_date DATE NOT NULL,
+    balance_type TEXT NOT NULL,
+    amount NUMERIC(14,2) NOT NULL,
+    currency TEXT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (account_id, reference_date)
+);
+
+CREATE INDEX open_banking_account_balances_user_date_idx
+    ON open_banking_account_balances(user_id, reference_date);
@@ -0,0 +1,29 @@
+CREATE TABLE merchants (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT merchants_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 100)
+);
+
+CREATE UNIQUE INDEX merchants_user_name_idx ON merchants(user_id, lower(name));
+
+-- An alias is the normalized form of a bank description. Each one belongs to
+-- exactly one merchant, so merging merchants moves their aliases too.
+CREATE TABLE merchant_aliases (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alias TEXT NOT NULL,
+    merchant_id BIGINT NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, alias)
+);
+
+CREATE INDEX merchant_aliases_merchant_idx ON merchant_aliases(merchant_id);
+
+ALTER TABLE transactions
+    ADD COLUMN merchant_id BIGINT REFERENCES merchants(id) ON DELETE SET NULL;
+
+CREATE INDEX transactions_merchant_idx
+    ON transactions(user_id, merchant_id, occurred_at)
+    WHERE merchant_id IS NOT NULL;
@@ -0,0 +1,28 @@
+CREATE TABLE categorization_rules (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    description_contains TEXT,
+    description_pattern TEXT,
+    amount_min NUMERIC(14,2),
+    amount_max NUMERIC(14,2),
+    account_id BIGINT REFERENCES open_banking_accounts(id) ON DELETE CASCADE,
+    merchant_category_code TEXT,
+    transaction_type TEXT,
+    category TEXT,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    exclude_from_budget BOOLEAN NOT NULL DEFAULT false,
+    mark_as_transfer BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT categorization_rules_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 80),
+    CONSTRAINT categorization_rules_type_check CHECK (transaction_type IN ('expense', 'income')),
+    CONSTRAINT categorization_rules_amount_check CHECK (amount_min IS NULL OR amount_max IS NULL OR amount_min <= amount_max),
+    CONSTRAINT categorization_rules_category_type_check CHECK (category IS NULL OR transaction_type IS NOT NULL),
+    CONSTRAINT categorization_rules_tags_check CHECK (cardinality(tags) <= 20)
+);
+
+CREATE INDEX categorization_rules_user_priority_idx
+    ON categorization_rules(user_id, priority DESC, id);
@@ -0,0 +1,16 @@
+-- category_learned_at marks rows whose category a person chose: manual
+-- entries and corrections of imported or synced rows. They are the
+-- training examples of the per-user category classifier.
+ALTER TABLE transactions ADD COLUMN category_learned_at TIMESTAMPTZ;
+
+UPDATE transactions SET category_learned_at=created_at
+WHERE source='manual';
+
+UPDATE transactions SET category_learned_at=updated_at
+WHERE source='open_banking'
+    AND (source_metadata @> '{"category_override":true}'::jsonb
+        OR source_metadata @> '{"classification_override":true}'::jsonb);
+
+CREATE INDEX transactions_category_learned_idx
+    ON transactions(user_id, category_learned_at DESC)
+    WHERE category_learned_at IS NOT NULL;
@@ -0,0 +1,8 @@
+-- A category may sit under one top-level category of the same type, so
+-- "groceries" can roll up into "Food" in summaries and budgets. Deeper
+-- nesting and cross-type parents are rejected by the service.
+ALTER TABLE categories
+    ADD COLUMN parent_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    ADD CONSTRAINT categories_parent_check CHECK (parent_id <> id);
+
+CREATE INDEX categories_parent_idx ON categories(parent_id) WHERE parent_id IS NOT NULL;
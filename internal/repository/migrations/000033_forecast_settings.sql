@@ -0,0 +1,10 @@
+-- A forecast warns when the projected total balance falls below the user's
+-- threshold. Without a row, or with a NULL threshold, it never warns.
+CREATE TABLE forecast_settings (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    low_balance_threshold NUMERIC(14,2),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT forecast_settings_threshold_check CHECK (
+        low_balance_threshold IS NULL OR low_balance_threshold > 0
+    )
+);
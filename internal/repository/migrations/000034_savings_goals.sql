@@ -0,0 +1,50 @@
+CREATE TABLE savings_goals (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    target_amount NUMERIC(14,2) NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    start_date DATE NOT NULL,
+    target_date DATE NOT NULL,
+    account_id BIGINT REFERENCES open_banking_accounts(id) ON DELETE SET NULL,
+    tag TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT savings_goals_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 100),
+    CONSTRAINT savings_goals_target_amount_check CHECK (target_amount > 0 AND target_amount <= 999999999999.99),
+    CONSTRAINT savings_goals_currency_check CHECK (currency = 'EUR'),
+    CONSTRAINT savings_goals_dates_check CHECK (target_date > start_date),
+    CONSTRAINT savings_goals_tag_length_check CHECK (char_length(tag) <= 40),
+    CONSTRAINT savings_goals_status_check CHECK (status IN ('active', 'archived'))
+);
+
+CREATE INDEX savings_goals_user_status_idx ON savings_goals(user_id, status, id);
+
+-- Manual contributions; negative amounts are withdrawals. Contributions from
+-- tagged transactions and linked accounts are derived from transactions.
+CREATE TABLE savings_goal_contributions (
+    id BIGSERIAL PRIMARY KEY,
+    goal_id BIGINT NOT NULL REFERENCES savings_goals(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(14,2) NOT NULL,
+    contributed_on DATE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT savings_goal_contributions_amount_check
+        CHECK (amount <> 0 AND abs(amount) <= 999999999999.99),
+    CONSTRAINT savings_goal_contributions_note_length_check CHECK (char_length(note) <= 200)
+);
+
+CREATE INDEX savings_goal_contributions_goal_idx
+    ON savings_goal_contributions(goal_id, contributed_on, id);
+
+CREATE TABLE savings_goal_milestones (
+    goal_id BIGINT NOT NULL REFERENCES savings_goals(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    milestone SMALLINT NOT NULL,
+    saved_amount NUMERIC(14,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (goal_id, milestone),
+    CONSTRAINT savings_goal_milestones_milestone_check CHECK (milestone IN (25, 50, 75, 100))
+);
@@ -0,0 +1,43 @@
+CREATE TABLE loans (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'other',
+    principal NUMERIC(14,2) NOT NULL,
+    annual_rate NUMERIC(6,3) NOT NULL,
+    term_months INT NOT NULL,
+    start_date DATE NOT NULL,
+    payment_day SMALLINT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    status TEXT NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT loans_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 100),
+    CONSTRAINT loans_kind_check CHECK (kind IN ('mortgage', 'car', 'personal', 'other')),
+    CONSTRAINT loans_principal_check CHECK (principal > 0 AND principal <= 999999999999.99),
+    CONSTRAINT loans_annual_rate_check CHECK (annual_rate BETWEEN 0 AND 100),
+    CONSTRAINT loans_term_months_check CHECK (term_months BETWEEN 1 AND 600),
+    CONSTRAINT loans_payment_day_check CHECK (payment_day BETWEEN 1 AND 31),
+    CONSTRAINT loans_currency_check CHECK (currency = 'EUR'),
+    CONSTRAINT loans_status_check CHECK (status IN ('active', 'archived'))
+);
+
+CREATE INDEX loans_user_status_idx ON loans(user_id, status, id);
+
+-- Installment payments are always ledger transactions. Extra principal
+-- payments may be recorded without one.
+CREATE TABLE loan_payments (
+    id BIGSERIAL PRIMARY KEY,
+    loan_id BIGINT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    transaction_id INT UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    amount NUMERIC(14,2) NOT NULL,
+    paid_on DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT loan_payments_kind_check CHECK (kind IN ('installment', 'extra')),
+    CONSTRAINT loan_payments_transaction_check CHECK (kind = 'extra' OR transaction_id IS NOT NULL),
+    CONSTRAINT loan_payments_amount_check CHECK (amount > 0 AND amount <= 999999999999.99)
+);
+
+CREATE INDEX loan_payments_loan_idx ON loan_payments(loan_id, paid_on, id);
@@ -0,0 +1,83 @@
+CREATE TABLE expense_groups (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    status TEXT NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT expense_groups_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 100),
+    CONSTRAINT expense_groups_currency_check CHECK (currency = 'EUR'),
+    CONSTRAINT expense_groups_status_check CHECK (status IN ('active', 'archived'))
+);
+
+CREATE INDEX expense_groups_user_status_idx ON expense_groups(user_id, status, id);
+
+-- Every group has exactly one member standing for its owner. Other members
+-- may be matched to a registered user by email.
+CREATE TABLE expense_group_members (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    member_user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    is_self BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT expense_group_members_name_length_check CHECK (char_length(btrim(name)) BETWEEN 1 AND 60)
+);
+
+CREATE UNIQUE INDEX expense_group_members_name_idx ON expense_group_members(group_id, lower(name));
+CREATE UNIQUE INDEX expense_group_members_self_idx ON expense_group_members(group_id) WHERE is_self;
+
+CREATE TABLE shared_expenses (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    amount NUMERIC(14,2) NOT NULL,
+    expense_date DATE NOT NULL,
+    paid_by_member_id BIGINT NOT NULL REFERENCES expense_group_members(id),
+    split_method TEXT NOT NULL,
+    transaction_id INT UNIQUE REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT shared_expenses_description_length_check CHECK (char_length(btrim(description)) BETWEEN 1 AND 200),
+    CONSTRAINT shared_expenses_amount_check CHECK (amount > 0 AND amount <= 999999999999.99),
+    CONSTRAINT shared_expenses_split_method_check CHECK (split_method IN ('equal', 'exact', 'percentage'))
+);
+
+CREATE INDEX shared_expenses_group_idx ON shared_expenses(group_id, expense_date, id);
+CREATE INDEX shared_expenses_paid_by_idx ON shared_expenses(paid_by_member_id);
+
+CREATE TABLE shared_expense_shares (
+    expense_id BIGINT NOT NULL REFERENCES shared_expenses(id) ON DELETE CASCADE,
+    member_id BIGINT NOT NULL REFERENCES expense_group_members(id),
+    amount NUMERIC(14,2) NOT NULL,
+    percentage NUMERIC(6,3),
+    PRIMARY KEY (expense_id, member_id),
+    CONSTRAINT shared_expense_shares_amount_check CHECK (amount >= 0),
+    CONSTRAINT shared_expense_shares_percentage_check CHECK (percentage IS NULL OR percentage BETWEEN 0 AND 100)
+);
+
+CREATE INDEX shared_expense_shares_member_idx ON shared_expense_shares(member_id);
+
+-- A settlement is money handed from one member to another. When the owner
+-- takes part it can be backed by the ledger transaction that moved it.
+CREATE TABLE expense_settlements (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_member_id BIGINT NOT NULL REFERENCES expense_group_members(id),
+    to_member_id BIGINT NOT NULL REFERENCES expense_group_members(id),
+    amount NUMERIC(14,2) NOT NULL,
+    settled_on DATE NOT NULL,
+    transaction_id INT UNIQUE REFERENCES transactions(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT expense_settlements_members_check CHECK (from_member_id <> to_member_id),
+    CONSTRAINT expense_settlements_amount_check CHECK (amount > 0 AND amount <= 999999999999.99),
+    CONSTRAINT expense_settlements_note_length_check CHECK (char_length(note) <= 200)
+);
+
+CREATE INDEX expense_settlements_group_idx ON expense_settlements(group_id, settled_on, id);
+CREATE INDEX expense_settlements_from_idx ON expense_settlements(from_member_id);
+CREATE INDEX expense_settlements_to_idx ON expense_settlements(to_member_id);
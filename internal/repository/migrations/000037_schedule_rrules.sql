@@ -0,0 +1,25 @@
+ALTER TABLE transaction_schedules
+    ADD COLUMN rrule TEXT NOT NULL DEFAULT '',
+    DROP CONSTRAINT transaction_schedules_frequency_check,
+    DROP CONSTRAINT transaction_schedules_frequency_fields_check,
+    ADD CONSTRAINT transaction_schedules_frequency_check CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    ADD CONSTRAINT transaction_schedules_rrule_length_check CHECK (char_length(rrule) <= 500),
+    ADD CONSTRAINT transaction_schedules_frequency_fields_check CHECK (
+        (rrule <> '' AND day_of_week IS NULL AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'daily' AND day_of_week IS NULL AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'weekly' AND day_of_week BETWEEN 1 AND 7 AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'monthly' AND day_of_month BETWEEN 1 AND 31 AND day_of_week IS NULL)
+    );
+
+ALTER TABLE investment_schedules
+    ADD COLUMN rrule TEXT NOT NULL DEFAULT '',
+    DROP CONSTRAINT investment_schedules_frequency_check,
+    DROP CONSTRAINT investment_schedules_frequency_fields_check,
+    ADD CONSTRAINT investment_schedules_frequency_check CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    ADD CONSTRAINT investment_schedules_rrule_length_check CHECK (char_length(rrule) <= 500),
+    ADD CONSTRAINT investment_schedules_frequency_fields_check CHECK (
+        (rrule <> '' AND day_of_week IS NULL AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'daily' AND day_of_week IS NULL AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'weekly' AND day_of_week BETWEEN 1 AND 7 AND day_of_month IS NULL) OR
+        (rrule = '' AND frequency = 'monthly' AND day_of_month BETWEEN 1 AND 31 AND day_of_week IS NULL)
+    );
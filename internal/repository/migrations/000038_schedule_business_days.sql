@@ -0,0 +1,33 @@
+ALTER TABLE transaction_schedules
+    ADD COLUMN business_day_adjustment TEXT NOT NULL DEFAULT 'none',
+    ADD COLUMN holiday_calendar TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT transaction_schedules_business_day_adjustment_check CHECK (
+        business_day_adjustment IN ('none', 'previous', 'next', 'modified_following')
+    ),
+    ADD CONSTRAINT transaction_schedules_holiday_calendar_check CHECK (holiday_calendar IN ('', 'BG', 'TARGET2'));
+
+ALTER TABLE investment_schedules
+    ADD COLUMN business_day_adjustment TEXT NOT NULL DEFAULT 'none',
+    ADD COLUMN holiday_calendar TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT investment_schedules_business_day_adjustment_check CHECK (
+        business_day_adjustment IN ('none', 'previous', 'next', 'modified_following')
+    ),
+    ADD CONSTRAINT investment_schedules_holiday_calendar_check CHECK (holiday_calendar IN ('', 'BG', 'TARGET2'));
+
+-- An adjusted occurrence is identified by the date the rule produced, so two
+-- occurrences moved onto the same business day are both kept.
+ALTER TABLE transaction_schedule_occurrences ADD COLUMN nominal_date DATE;
+UPDATE transaction_schedule_occurrences SET nominal_date = scheduled_for;
+ALTER TABLE transaction_schedule_occurrences
+    ALTER COLUMN nominal_date SET NOT NULL,
+    DROP CONSTRAINT transaction_schedule_occurrences_schedule_id_scheduled_for_key,
+    ADD CONSTRAINT transaction_schedule_occurrences_schedule_id_nominal_date_key UNIQUE (schedule_id, nominal_date);
+CREATE INDEX transaction_schedule_occurrences_schedule_date_idx
+    ON transaction_schedule_occurrences(schedule_id, scheduled_for);
+
+ALTER TABLE investment_schedule_occurrences ADD COLUMN nominal_date DATE;
+UPDATE investment_schedule_occurrences SET nominal_date = scheduled_for;
+ALTER TABLE investment_schedule_occurrences
+    ALTER COLUMN nominal_date SET NOT NULL,
+    DROP CONSTRAINT investment_schedule_occurrences_schedule_id_scheduled_for_key,
+    ADD CONSTRAINT investment_schedule_occurrences_schedule_id_nominal_date_key UNIQUE (schedule_id, nominal_date);
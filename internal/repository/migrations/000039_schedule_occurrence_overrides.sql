@@ -0,0 +1,17 @@
+-- Overridden occurrences were edited by the user and survive schedule edits.
+ALTER TABLE transaction_schedule_occurrences ADD COLUMN overridden BOOLEAN NOT NULL DEFAULT false;
+
+-- Investment occurrences carry their own amount so a single buy can differ
+-- from the schedule, and can be skipped like transaction occurrences.
+ALTER TABLE investment_schedule_occurrences
+    ADD COLUMN amount NUMERIC(14,2),
+    ADD COLUMN overridden BOOLEAN NOT NULL DEFAULT false;
+UPDATE investment_schedule_occurrences occurrence
+SET amount = schedule.amount
+FROM investment_schedules schedule
+WHERE schedule.id = occurrence.schedule_id;
+ALTER TABLE investment_schedule_occurrences
+    ALTER COLUMN amount SET NOT NULL,
+    ADD CONSTRAINT investment_schedule_occurrences_amount_check CHECK (amount > 0 AND amount <= 999999999999.99),
+    DROP CONSTRAINT investment_schedule_occurrences_status_check,
+    ADD CONSTRAINT investment_schedule_occurrences_status_check CHECK (status IN ('planned', 'posted', 'skipped'));
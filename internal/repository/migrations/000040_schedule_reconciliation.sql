@@ -0,0 +1,3 @@
+-- Reconciled occurrences are linked to the bank transaction that paid them
+-- instead of a transaction posted by the schedule.
+ALTER TABLE transaction_schedule_occurrences ADD COLUMN reconciled_at TIMESTAMPTZ;
@@ -0,0 +1,24 @@
+-- Schedules with a variable amount plan an estimate: a fixed one, the rolling
+-- average of recent reconciled payments, or a value within a range.
+ALTER TABLE transaction_schedules
+    ADD COLUMN amount_mode TEXT NOT NULL DEFAULT 'fixed',
+    ADD COLUMN amount_min NUMERIC(14,2),
+    ADD COLUMN amount_max NUMERIC(14,2),
+    ADD COLUMN average_window SMALLINT,
+    ADD CONSTRAINT transaction_schedules_amount_mode_check
+        CHECK (amount_mode IN ('fixed', 'estimate', 'rolling_average', 'range')),
+    ADD CONSTRAINT transaction_schedules_amount_range_check CHECK (
+        (amount_mode = 'range' AND amount_min > 0 AND amount_min <= amount_max AND amount_max <= 999999999999.99)
+        OR (amount_mode <> 'range' AND amount_min IS NULL AND amount_max IS NULL)
+    ),
+    ADD CONSTRAINT transaction_schedules_average_window_check CHECK (
+        (amount_mode = 'rolling_average' AND average_window BETWEEN 1 AND 12)
+        OR (amount_mode <> 'rolling_average' AND average_window IS NULL)
+    );
+
+-- Occurrences keep the estimate they were planned with, while amount becomes
+-- the paid amount once a bank transaction reconciles them.
+ALTER TABLE transaction_schedule_occurrences
+    ADD COLUMN estimated_amount NUMERIC(14,2),
+    ADD COLUMN amount_min NUMERIC(14,2),
+    ADD COLUMN amount_max NUMERIC(14,2);
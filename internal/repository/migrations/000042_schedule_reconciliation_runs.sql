@@ -0,0 +1,6 @@
+-- When each user's schedules were last reconciled, so maintenance only
+-- reconciles users with bank or statement rows stored since then.
+CREATE TABLE transaction_schedule_reconciliations (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reconciled_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,8 @@
+-- Merged-away rows outlive the row they were merged into. Like bank
+-- suppressions, their import fingerprints must keep a re-import from bringing
+-- the duplicates back after the kept row is deleted.
+ALTER TABLE transaction_merges
+    ALTER COLUMN transaction_id DROP NOT NULL,
+    DROP CONSTRAINT transaction_merges_transaction_id_fkey,
+    ADD CONSTRAINT transaction_merges_transaction_id_fkey
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL;
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+// RecordOpenBankingAccountBalances keeps the booked balances a bank reported
+// for an account, one per reference date. A later report for the same date
+// replaces the earlier one.
+func (r *Repository) RecordOpenBankingAccountBalances(ctx context.Context, userID int, balances []model.OpenBankingAccountBalance) error {
+	for _, balance := range balances {
+		tag, err := r.db.Exec(ctx, `INSERT INTO open_banking_account_balances(
+			user_id,account_id,reference_date,balance_type,amount,currency
+		)
+		SELECT c.user_id,a.id,$3,$4,$5,$6
+		FROM open_banking_accounts a
+		JOIN open_banking_connections c ON c.id=a.connection_id
+		WHERE a.id=$2 AND c.user_id=$1
+		ON CONFLICT(account_id,reference_date) DO UPDATE SET
+			balance_type=EXCLUDED.balance_type,amount=EXCLUDED.amount,currency=EXCLUDED.currency,recorded_at=now()`,
+			userID, balance.AccountID, balance.ReferenceDate, balance.Type, balance.Amount, balance.Currency)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// ListOpenBankingAccountBalances returns recorded balances with a reference
+// date in [from, through], oldest first.
+func (r *Repository) ListOpenBankingAccountBalances(ctx context.Context, userID int, from, through time.Time) ([]model.OpenBankingAccountBalance, error) {
+	rows, err := r.db.Query(ctx, `SELECT account_id,to_char(reference_date,'YYYY-MM-DD'),balance_type,amount::text,currency
+		FROM open_banking_account_balances
+		WHERE user_id=$1 AND reference_date >= $2 AND reference_date <= $3
+		ORDER BY reference_date,account_id`, userID, from, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.OpenBankingAccountBalance, 0)
+	for rows.Next() {
+		var item model.OpenBankingAccountBalance
+		if err := rows.Scan(&item.AccountID, &item.ReferenceDate, &item.Type, &item.Amount, &item.Currency); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -24,6 +24,11 @@ type OpenBankingTransactionSeed struct {
 	Currency    string
 	OccurredAt  time.Time
 	Metadata    json.RawMessage
+	Merchant    model.MerchantMatch
+	// Tags and ExcludedFromBudget come from categorization rules and are
+	// only written on insert; later syncs keep the user's edits.
+	Tags               []string
+	ExcludedFromBudget bool
 }
 
 func (r *Repository) ClaimOpenBankingAccountsForSync(
@@ -122,12 +127,13 @@ func (r *Repository) ImportOpenBankingTransactions(
 		var transactionID int
 		err = tx.QueryRow(ctx, `INSERT INTO transactions(
 			user_id,type,category,description,amount,currency,occurred_at,source,status,
-			source_account_id,external_id,source_metadata
-		) VALUES($1,$2,$3,$4,$5,$6,$7,'open_banking','booked',$8,$9,$10)
+			source_account_id,external_id,source_metadata,tags,excluded_from_budget
+		) VALUES($1,$2,$3,$4,$5,$6,$7,'open_banking','booked',$8,$9,$10,$11,$12)
 		ON CONFLICT(user_id,source_account_id,external_id)
 		WHERE source='open_banking' AND source_account_id IS NOT NULL AND external_id IS NOT NULL
 		DO NOTHING RETURNING id`, userID, effectiveType, effectiveCategory, item.Description,
 			item.Amount, item.Currency, item.OccurredAt, accountID, item.ExternalID, effectiveMetadata,
+			transactionTags(item.Tags), item.ExcludedFromBudget,
 		).Scan(&transactionID)
 		inserted := err == nil
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -175,6 +181,9 @@ func (r *Repository) ImportOpenBankingTransactions(
 		} else if err != nil {
 			return model.OpenBankingSyncResult{}, err
 		} else {
+			if err := assignMerchant(ctx, tx, userID, transactionID, item.Merchant); err != nil {
+				return model.OpenBankingSyncResult{}, err
+			}
 			result.Imported++
 		}
 
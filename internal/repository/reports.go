@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"money-manager-server/internal/model"
+)
+
+// ReportRange is a report's month range and the two ranges it is compared
+// with. Every To is exclusive.
+type ReportRange struct {
+	From, To                 time.Time
+	PreviousFrom, PreviousTo time.Time
+	LastYearFrom, LastYearTo time.Time
+}
+
+func (r ReportRange) arguments() []any {
+	return []any{r.From, r.To, r.PreviousFrom, r.PreviousTo, r.LastYearFrom, r.LastYearTo}
+}
+
+// reportChangePercent renders the change from a reference amount as a
+// percentage, or an empty string when the reference is zero.
+func reportChangePercent(current, reference string) string {
+	return `COALESCE(round((` + current + `-` + reference + `)*100/NULLIF(` + reference + `,0),1)::text,'')`
+}
+
+// CashFlowReport totals booked income and expenses for every month of the
+// range and for the two comparison ranges.
+func (r *Repository) CashFlowReport(ctx context.Context, userID int, reportRange ReportRange) (model.CashFlowReport, error) {
+	rows, err := r.db.Query(ctx, `WITH months AS (
+			SELECT month::date FROM generate_series($2::date,$3::date - 1,INTERVAL '1 month') AS month
+		), totals AS (
+			SELECT date_trunc('month',occurred_at)::date AS month,
+				SUM(amount) FILTER (WHERE type='income') AS income,
+				SUM(amount) FILTER (WHERE type='expense') AS expense
+			FROM transactions
+			WHERE user_id=$1 AND status='booked' AND occurred_at >= $2 AND occurred_at < $3
+			GROUP BY 1
+		)
+		SELECT to_char(months.month,'YYYY-MM'),COALESCE(income,0)::text,COALESCE(expense,0)::text,
+			(COALESCE(income,0)-COALESCE(expense,0))::text
+		FROM months LEFT JOIN totals USING (month)
+		ORDER BY months.month`, userID, reportRange.From, reportRange.To)
+	if err != nil {
+		return model.CashFlowReport{}, err
+	}
+	defer rows.Close()
+	report := model.CashFlowReport{Months: make([]model.CashFlowMonth, 0)}
+	for rows.Next() {
+		var month model.CashFlowMonth
+		if err := rows.Scan(&month.Month, &month.Income, &month.Expense, &month.Net); err != nil {
+			return model.CashFlowReport{}, err
+		}
+		if month.CashFlowTotals, err = formatCashFlowTotals(month.CashFlowTotals); err != nil {
+			return model.CashFlowReport{}, err
+		}
+		report.Months = append(report.Months, month)
+	}
+	if err := rows.Err(); err != nil {
+		return model.CashFlowReport{}, err
+	}
+
+	arguments := append([]any{userID}, reportRange.arguments()...)
+	current, previous, lastYear := &report.Total, &report.PreviousPeriod, &report.SamePeriodLastYear
+	err = r.db.QueryRow(ctx, `WITH sums AS (
+			SELECT
+				COALESCE(SUM(amount) FILTER (WHERE type='income' AND occurred_at >= $2 AND occurred_at < $3),0) AS income,
+				COALESCE(SUM(amount) FILTER (WHERE type='expense' AND occurred_at >= $2 AND occurred_at < $3),0) AS expense,
+				COALESCE(SUM(amount) FILTER (WHERE type='income' AND occurred_at >= $4 AND occurred_at < $5),0) AS previous_income,
+				COALESCE(SUM(amount) FILTER (WHERE type='expense' AND occurred_at >= $4 AND occurred_at < $5),0) AS previous_expense,
+				COALESCE(SUM(amount) FILTER (WHERE type='income' AND occurred_at >= $6 AND occurred_at < $7),0) AS last_year_income,
+				COALESCE(SUM(amount) FILTER (WHERE type='expense' AND occurred_at >= $6 AND occurred_at < $7),0) AS last_year_expense
+			FROM transactions
+			WHERE user_id=$1 AND status='booked' AND (
+				(occurred_at >= $2 AND occurred_at < $3) OR (occurred_at >= $4 AND occurred_at < $5)
+				OR (occurred_at >= $6 AND occurred_at < $7))
+		)
+		SELECT income::text,expense::text,(income-expense)::text,
+			previous_income::text,previous_expense::text,(previous_income-previous_expense)::text,
+			`+reportChangePercent("income", "previous_income")+`,`+reportChangePercent("expense", "previous_expense")+`,
+			last_year_income::text,last_year_expense::text,(last_year_income-last_year_expense)::text,
+			`+reportChangePercent("income", "last_year_income")+`,`+reportChangePercent("expense", "last_year_expense")+`
+		FROM sums`, arguments...).Scan(
+		&current.Income, &current.Expense, &current.Net,
+		&previous.Income, &previous.Expense, &previous.Net,
+		&previous.IncomeChangePercent, &previous.ExpenseChangePercent,
+		&lastYear.Income, &lastYear.Expense, &lastYear.Net,
+		&lastYear.IncomeChangePercent, &lastYear.ExpenseChangePercent,
+	)
+	if err != nil {
+		return model.CashFlowReport{}, err
+	}
+	for _, totals := range []*model.CashFlowTotals{current, &previous.CashFlowTotals, &lastYear.CashFlowTotals} {
+		if *totals, err = formatCashFlowTotals(*totals); err != nil {
+			return model.CashFlowReport{}, err
+		}
+	}
+	return report, nil
+}
+
+func formatCashFlowTotals(totals model.CashFlowTotals) (model.CashFlowTotals, error) {
+	var err error
+	if totals.Income, err = decimalWithTwoPlaces(totals.Income); err != nil {
+		return totals, fmt.Errorf("format income aggregate: %w", err)
+	}
+	if totals.Expense, err = decimalWithTwoPlaces(totals.Expense); err != nil {
+		return totals, fmt.Errorf("format expense aggregate: %w", err)
+	}
+	if totals.Net, err = decimalWithTwoPlaces(totals.Net); err != nil {
+		return totals, fmt.Errorf("format net aggregate: %w", err)
+	}
+	return totals, nil
+}
+
+// CategoryReport totals booked amounts of one type by top-level category,
+// over the whole range with comparisons and for each month with a share.
+// Months without any amounts are omitted.
+func (r *Repository) CategoryReport(
+	ctx context.Context,
+	userID int,
+	transactionType string,
+	reportRange ReportRange,
+) (model.CategoryReport, error) {
+	arguments := append([]any{userID, transactionType}, reportRange.arguments()...)
+	rows, err := r.db.Query(ctx, `WITH rolled AS (
+			SELECT t.occurred_at,COALESCE(parent.name,t.category) AS category,t.amount
+			FROM transactions t `+rolledUpCategoryJoin+`
+			WHERE t.user_id=$1 AND t.type=$2 AND t.status='booked' AND (
+				(t.occurred_at >= $3 AND t.occurred_at < $4) OR (t.occurred_at >= $5 AND t.occurred_at < $6)
+				OR (t.occurred_at >= $7 AND t.occurred_at < $8))
+		), sums AS (
+			SELECT category,
+				COALESCE(SUM(amount) FILTER (WHERE occurred_at >= $3 AND occurred_at < $4),0) AS current,
+				COALESCE(SUM(amount) FILTER (WHERE occurred_at >= $5 AND occurred_at < $6),0) AS previous,
+				COALESCE(SUM(amount) FILTER (WHERE occurred_at >= $7 AND occurred_at < $8),0) AS last_year
+			FROM rolled GROUP BY category
+		)
+		SELECT category,current::text,round(current*100/SUM(current) OVER (),1)::text,
+			previous::text,last_year::text,
+			`+reportChangePercent("current", "previous")+`,`+reportChangePercent("current", "last_year")+`,
+			(SUM(current) OVER ())::text
+		FROM sums WHERE current > 0
+		ORDER BY current DESC,category`, arguments...)
+	if err != nil {
+		return model.CategoryReport{}, err
+	}
+	defer rows.Close()
+	report := model.CategoryReport{
+		Total: "0.00", Categories: make([]model.CategoryReportItem, 0), Months: make([]model.CategoryReportMonth, 0),
+	}
+	for rows.Next() {
+		var item model.CategoryReportItem
+		if err := rows.Scan(
+			&item.Category, &item.Amount, &item.SharePercent, &item.PreviousAmount, &item.LastYearAmount,
+			&item.PreviousChangePercent, &item.LastYearChangePercent, &report.Total,
+		); err != nil {
+			return model.CategoryReport{}, err
+		}
+		for _, amount := range []*string{&item.Amount, &item.PreviousAmount, &item.LastYearAmount, &report.Total} {
+			if *amount, err = decimalWithTwoPlaces(*amount); err != nil {
+				return model.CategoryReport{}, fmt.Errorf("format category aggregate: %w", err)
+			}
+		}
+		report.Categories = append(report.Categories, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.CategoryReport{}, err
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(ctx, `WITH grouped AS (
+			SELECT date_trunc('month',t.occurred_at)::date AS month,COALESCE(parent.name,t.category) AS category,
+				SUM(t.amount) AS amount
+			FROM transactions t `+rolledUpCategoryJoin+`
+			WHERE t.user_id=$1 AND t.type=$2 AND t.status='booked' AND t.occurred_at >= $3 AND t.occurred_at < $4
+			GROUP BY 1,2
+		)
+		SELECT to_char(month,'YYYY-MM'),category,amount::text,
+			round(amount*100/SUM(amount) OVER (PARTITION BY month),1)::text,
+			(SUM(amount) OVER (PARTITION BY month))::text
+		FROM grouped
+		ORDER BY month,amount DESC,category`, userID, transactionType, reportRange.From, reportRange.To)
+	if err != nil {
+		return model.CategoryReport{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var month, total string
+		var share model.CategoryShare
+		if err := rows.Scan(&month, &share.Category, &share.Amount, &share.SharePercent, &total); err != nil {
+			return model.CategoryReport{}, err
+		}
+		if share.Amount, err = decimalWithTwoPlaces(share.Amount); err != nil {
+			return model.CategoryReport{}, fmt.Errorf("format category aggregate: %w", err)
+		}
+		if len(report.Months) == 0 || report.Months[len(report.Months)-1].Month != month {
+			if total, err = decimalWithTwoPlaces(total); err != nil {
+				return model.CategoryReport{}, fmt.Errorf("format month aggregate: %w", err)
+			}
+			report.Months = append(report.Months, model.CategoryReportMonth{Month: month, Total: total})
+		}
+		current := &report.Months[len(report.Months)-1]
+		current.Categories = append(current.Categories, share)
+	}
+	return report, rows.Err()
+}
+
+// MerchantReport returns the merchants with the largest booked expenses in
+// the range, with what they cost in the two comparison ranges.
+func (r *Repository) MerchantReport(ctx context.Context, userID int, reportRange ReportRange, limit int) (model.MerchantReport, error) {
+	report := model.MerchantReport{Merchants: make([]model.MerchantReportItem, 0)}
+	if err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(amount),0)::text FROM transactions
+		WHERE user_id=$1 AND type='expense' AND status='booked' AND occurred_at >= $2 AND occurred_at < $3`,
+		userID, reportRange.From, reportRange.To).Scan(&report.Total); err != nil {
+		return model.MerchantReport{}, err
+	}
+	var err error
+	if report.Total, err = decimalWithTwoPlaces(report.Total); err != nil {
+		return model.MerchantReport{}, fmt.Errorf("format expense aggregate: %w", err)
+	}
+	arguments := append(append([]any{userID}, reportRange.arguments()...), report.Total, limit)
+	rows, err := r.db.Query(ctx, `WITH sums AS (
+			SELECT m.id,m.name,
+				COUNT(*) FILTER (WHERE t.occurred_at >= $2 AND t.occurred_at < $3) AS transactions,
+				COALESCE(SUM(t.amount) FILTER (WHERE t.occurred_at >= $2 AND t.occurred_at < $3),0) AS current,
+				COALESCE(SUM(t.amount) FILTER (WHERE t.occurred_at >= $4 AND t.occurred_at < $5),0) AS previous,
+				COALESCE(SUM(t.amount) FILTER (WHERE t.occurred_at >= $6 AND t.occurred_at < $7),0) AS last_year
+			FROM transactions t JOIN merchants m ON m.id=t.merchant_id
+			WHERE t.user_id=$1 AND t.type='expense' AND t.status='booked' AND (
+				(t.occurred_at >= $2 AND t.occurred_at < $3) OR (t.occurred_at >= $4 AND t.occurred_at < $5)
+				OR (t.occurred_at >= $6 AND t.occurred_at < $7))
+			GROUP BY m.id,m.name
+		)
+		SELECT id,name,transactions,current::text,round(current*100/$8::numeric,1)::text,previous::text,last_year::text
+		FROM sums WHERE current > 0
+		ORDER BY current DESC,id
+		LIMIT $9`, arguments...)
+	if err != nil {
+		return model.MerchantReport{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item model.MerchantReportItem
+		if err := rows.Scan(
+			&item.MerchantID, &item.Name, &item.Transactions, &item.Amount, &item.SharePercent,
+			&item.PreviousAmount, &item.LastYearAmount,
+		); err != nil {
+			return model.MerchantReport{}, err
+		}
+		for _, amount := range []*string{&item.Amount, &item.PreviousAmount, &item.LastYearAmount} {
+			if *amount, err = decimalWithTwoPlaces(*amount); err != nil {
+				return model.MerchantReport{}, fmt.Errorf("format merchant aggregate: %w", err)
+			}
+		}
+		report.Merchants = append(report.Merchants, item)
+	}
+	return report, rows.Err()
+}
+
+// BudgetHitRate replays every period of the user's active budgets that
+// starts on or after from and ends by to, counting those whose spending
+// stayed within the amount. Periods before a budget was created are skipped.
+func (r *Repository) BudgetHitRate(ctx context.Context, userID int, from, to time.Time) (model.AnnualBudgets, error) {
+	var result model.AnnualBudgets
+	err := r.db.QueryRow(ctx, `WITH periods AS (
+			SELECT b.user_id,b.category,b.amount,starts.period_start::date AS period_start,
+				CASE b.period WHEN 'weekly' THEN starts.period_start::date + 7
+					ELSE (starts.period_start + INTERVAL '1 month')::date END AS period_end,
+				(b.created_at AT TIME ZONE 'UTC')::date AS created_on
+			FROM budgets b CROSS JOIN LATERAL generate_series(
+				CASE b.period WHEN 'weekly' THEN date_trunc('week',$2::timestamp) ELSE date_trunc('month',$2::timestamp) END,
+				($3::date - 1)::timestamp,
+				CASE b.period WHEN 'weekly' THEN INTERVAL '1 week' ELSE INTERVAL '1 month' END) AS starts(period_start)
+			WHERE b.user_id=$1 AND b.status='active'
+		), outcomes AS (
+			SELECT periods.amount,
+				COALESCE((SELECT sum(`+budgetSpend+`) FROM transactions t
+					WHERE t.user_id=periods.user_id AND t.type='expense' AND t.status='booked'
+						AND NOT t.excluded_from_budget
+						AND t.occurred_at >= periods.period_start AND t.occurred_at < periods.period_end
+						AND (periods.category='' OR lower(t.category)=lower(periods.category)
+							OR lower(t.category) IN (SELECT lower(child.name)
+								FROM categories parent JOIN categories child ON child.parent_id=parent.id AND child.active
+								WHERE parent.user_id=periods.user_id AND parent.type='expense' AND parent.active
+									AND lower(parent.name)=lower(periods.category)))),0) AS spent
+			FROM periods
+			WHERE period_start >= $2 AND period_end <= $3 AND period_end > created_on
+		)
+		SELECT count(*)::int,(count(*) FILTER (WHERE spent <= amount))::int,
+			COALESCE(round((count(*) FILTER (WHERE spent <= amount))*100.0/NULLIF(count(*),0),1)::text,'')
+		FROM outcomes`, userID, from, to).Scan(&result.Periods, &result.WithinBudget, &result.HitRatePercent)
+	return result, err
+}
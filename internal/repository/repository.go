@@ -14,6 +14,9 @@ import (
 var (
 	ErrNotFound = errors.New("repository: not found")
 	ErrConflict = errors.New("repository: conflict")
+	// ErrLimitExceeded reports that a bounded bulk selection matched more
+	// rows than the caller allowed, so nothing was changed.
+	ErrLimitExceeded = errors.New("repository: limit exceeded")
 )
 
 type Options struct {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -71,6 +72,7 @@ func TestRepositoryIntegration(t *testing.T) {
 			Type: "expense", Category: "other", Description: "Imported shop", Amount: "9.50",
 			Currency: "EUR", OccurredAt: "2026-07-12",
 		},
+		Source:      "revolut",
 		Fingerprint: "classifier-fingerprint",
 	}
 	if imported, skipped, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{importSeed}); err != nil || imported != 1 || skipped != 0 {
@@ -462,7 +464,7 @@ func TestRepositoryIntegration(t *testing.T) {
 	}
 	occurrenceDate := monthStart.AddDate(0, 0, 1)
 	insertedOccurrences, err := repo.UpsertInvestmentScheduleOccurrences(ctx, []InvestmentScheduleOccurrenceSeed{{
-		ScheduleID: investmentSchedule.ID, UserID: user.ID, ScheduledFor: occurrenceDate,
+		ScheduleID: investmentSchedule.ID, UserID: user.ID, ScheduledFor: occurrenceDate, Amount: "25.00",
 	}})
 	if err != nil || insertedOccurrences != 1 {
 		t.Fatalf("upsert investment occurrences = %d, %v", insertedOccurrences, err)
@@ -1064,3 +1066,1547 @@ func openIntegrationRepository(t *testing.T, requestedTimezone ...string) (conte
 	t.Cleanup(repo.Close)
 	return ctx, repo, pool
 }
+
+func TestBulkTransactionsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "bulk@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	ids := make([]int, 0, 3)
+	for _, description := range []string{"Market", "Bakery", "Cinema"} {
+		transaction, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+			Type: "expense", Category: "other", Description: description, Amount: "4.00",
+			Currency: "EUR", OccurredAt: "2026-07-11",
+		})
+		if err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+		ids = append(ids, transaction.ID)
+	}
+
+	items, err := repo.BulkTransactions(ctx, user.ID, BulkTransactionOperation{
+		Action: "recategorize", IDs: []int{ids[0], ids[1], 999999}, Type: "expense", Category: "groceries",
+	})
+	if err != nil || len(items) != 3 || items[0].Status != "updated" || items[2].Status != "not_found" {
+		t.Fatalf("bulk recategorize = %#v, %v", items, err)
+	}
+	items, err = repo.BulkTransactions(ctx, user.ID, BulkTransactionOperation{
+		Action: "tag", IDs: []int{ids[0]}, Tags: []string{"weekly"},
+	})
+	if err != nil || items[0].Status != "updated" {
+		t.Fatalf("bulk tag = %#v, %v", items, err)
+	}
+	items, err = repo.BulkTransactions(ctx, user.ID, BulkTransactionOperation{
+		Action: "tag", IDs: []int{ids[0]}, Tags: []string{"weekly"},
+	})
+	if err != nil || items[0].Status != "unchanged" {
+		t.Fatalf("repeated bulk tag = %#v, %v", items, err)
+	}
+	monthStart := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := repo.BulkTransactions(ctx, user.ID, BulkTransactionOperation{
+		Action: "delete", Filter: &TransactionFilter{From: monthStart, To: monthStart.AddDate(0, 1, 0)}, Limit: 2,
+	}); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("bounded filter error = %v", err)
+	}
+	items, err = repo.BulkTransactions(ctx, user.ID, BulkTransactionOperation{
+		Action: "delete", Filter: &TransactionFilter{From: monthStart, To: monthStart.AddDate(0, 1, 0), Category: "GROCERIES"}, Limit: 10,
+	})
+	if err != nil || len(items) != 2 || items[0].Status != "deleted" {
+		t.Fatalf("bulk delete = %#v, %v", items, err)
+	}
+	remaining, err := repo.ListTransactions(ctx, user.ID, TransactionFilter{From: monthStart, To: monthStart.AddDate(0, 1, 0)})
+	if err != nil || len(remaining) != 1 || remaining[0].ID != ids[2] || len(remaining[0].Tags) != 0 {
+		t.Fatalf("remaining transactions = %#v, %v", remaining, err)
+	}
+}
+
+func TestCSVImportProfilesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "csv@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	request := model.CSVImportProfileRequest{
+		Name: "DSK", Delimiter: "\t", Encoding: "windows-1251", DateFormat: "DD.MM.YYYY", DecimalSeparator: ",",
+		Columns: model.CSVImportColumns{Date: "дата", Amount: "сума", Description: "основание"},
+	}
+	profile, err := repo.CreateCSVImportProfile(ctx, user.ID, request)
+	if err != nil || profile.Columns.Amount != "сума" || profile.Delimiter != "\t" {
+		t.Fatalf("create profile = %#v, %v", profile, err)
+	}
+	request.Name = "dsk"
+	if _, err := repo.CreateCSVImportProfile(ctx, user.ID, request); !errors.Is(err, ErrConflict) {
+		t.Fatalf("duplicate profile name error = %v", err)
+	}
+	request.Name = "DSK savings"
+	request.Columns.Amount, request.Columns.Debit = "", "дебит"
+	updated, err := repo.UpdateCSVImportProfile(ctx, user.ID, profile.ID, request)
+	if err != nil || updated.Name != "DSK savings" || updated.Columns.Debit != "дебит" || updated.Columns.Amount != "" {
+		t.Fatalf("update profile = %#v, %v", updated, err)
+	}
+	if profiles, err := repo.ListCSVImportProfiles(ctx, user.ID); err != nil || len(profiles) != 1 {
+		t.Fatalf("list profiles = %#v, %v", profiles, err)
+	}
+	if err := repo.DeleteCSVImportProfile(ctx, user.ID, profile.ID); err != nil {
+		t.Fatalf("delete profile: %v", err)
+	}
+	if _, err := repo.GetCSVImportProfile(ctx, user.ID, profile.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted profile error = %v", err)
+	}
+}
+
+func TestImportPreviewsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "preview@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if _, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{{
+		Request: model.TransactionRequest{
+			Type: "expense", Category: "other", Description: "Coffee", Amount: "2.40", Currency: "EUR", OccurredAt: "2026-07-11",
+		},
+		Source: "ofx", Fingerprint: "seen",
+	}}); err != nil {
+		t.Fatalf("seed import: %v", err)
+	}
+	existing, err := repo.ExistingImportFingerprints(ctx, user.ID, "ofx", []string{"seen", "unseen"})
+	if err != nil || len(existing) != 1 || existing[0] != "seen" {
+		t.Fatalf("existing fingerprints = %#v, %v", existing, err)
+	}
+	if existing, err := repo.ExistingImportFingerprints(ctx, user.ID, "qif", []string{"seen"}); err != nil || len(existing) != 0 {
+		t.Fatalf("other source fingerprints = %#v, %v", existing, err)
+	}
+
+	rows := []ImportPreviewRowRecord{{
+		ImportPreviewRow: model.ImportPreviewRow{Row: 1, Status: "new", Type: "expense", Category: "groceries", Description: "LIDL"},
+		Fingerprint:      "abc",
+	}}
+	expired, err := repo.CreateImportPreview(ctx, user.ID, "ofx", rows, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("create expired preview: %v", err)
+	}
+	if _, err := repo.GetImportPreview(ctx, user.ID, expired.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expired preview error = %v", err)
+	}
+	if deleted, err := repo.DeleteExpiredImportPreviews(ctx, time.Now()); err != nil || deleted != 1 {
+		t.Fatalf("expired previews = %d, %v", deleted, err)
+	}
+	preview, err := repo.CreateImportPreview(ctx, user.ID, "ofx", rows, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("create preview: %v", err)
+	}
+	loaded, err := repo.GetImportPreview(ctx, user.ID, preview.ID)
+	if err != nil || len(loaded.Rows) != 1 || loaded.Rows[0].Fingerprint != "abc" || loaded.Rows[0].Category != "groceries" {
+		t.Fatalf("get preview = %#v, %v", loaded, err)
+	}
+	if _, err := repo.GetImportPreview(ctx, user.ID+1, preview.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("foreign preview error = %v", err)
+	}
+	if err := repo.DeleteImportPreview(ctx, user.ID, preview.ID); err != nil {
+		t.Fatalf("delete preview: %v", err)
+	}
+	if _, err := repo.GetImportPreview(ctx, user.ID, preview.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted preview error = %v", err)
+	}
+}
+
+func TestTransactionDuplicatesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "duplicates@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	manual, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "other", Description: "Lidl", Amount: "12.50", Currency: "EUR", OccurredAt: "2026-07-10",
+	})
+	if err != nil {
+		t.Fatalf("create manual transaction: %v", err)
+	}
+	imported := model.ImportedTransaction{
+		Request: model.TransactionRequest{
+			Type: "expense", Category: "groceries", Description: "LIDL SOFIA", Amount: "12.50", Currency: "EUR", OccurredAt: "2026-07-11",
+		},
+		Source: "ofx", Fingerprint: "lidl",
+	}
+	if _, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{imported}); err != nil {
+		t.Fatalf("import transaction: %v", err)
+	}
+	from, to := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	pairs, err := repo.ListDuplicateCandidates(ctx, user.ID, from, to, 10)
+	if err != nil || len(pairs) != 1 || pairs[0].Origins[0] != "manual" || pairs[0].Origins[1] != "import:ofx" {
+		t.Fatalf("duplicate candidates = %#v, %v", pairs, err)
+	}
+
+	result, err := repo.MergeTransactions(ctx, user.ID, manual.ID, pairs[0].Transactions[1].ID)
+	if err != nil {
+		t.Fatalf("merge transactions: %v", err)
+	}
+	if result.Transaction.Category != "groceries" || len(result.Provenance) != 2 || result.Provenance[1].Origin != "import:ofx" {
+		t.Fatalf("merge result = %#v", result)
+	}
+	if pairs, err := repo.ListDuplicateCandidates(ctx, user.ID, from, to, 10); err != nil || len(pairs) != 0 {
+		t.Fatalf("candidates after merge = %#v, %v", pairs, err)
+	}
+	if importedCount, skipped, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{imported}); err != nil || importedCount != 0 || skipped != 1 {
+		t.Fatalf("reimport merged row = %d/%d, %v", importedCount, skipped, err)
+	}
+	if existing, err := repo.ExistingImportFingerprints(ctx, user.ID, "ofx", []string{"lidl"}); err != nil || len(existing) != 1 {
+		t.Fatalf("merged fingerprints = %#v, %v", existing, err)
+	}
+	provenance, err := repo.ListTransactionProvenance(ctx, user.ID, manual.ID)
+	if err != nil || len(provenance) != 2 || provenance[0].Origin != "manual" || provenance[1].Description != "LIDL SOFIA" {
+		t.Fatalf("provenance = %#v, %v", provenance, err)
+	}
+	if _, err := repo.MergeTransactions(ctx, user.ID, manual.ID, manual.ID+1000); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing merge error = %v", err)
+	}
+
+	// Deleting the kept row does not let the merged-away import come back.
+	if err := repo.DeleteTransaction(ctx, user.ID, manual.ID); err != nil {
+		t.Fatalf("delete kept transaction: %v", err)
+	}
+	if importedCount, skipped, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{imported}); err != nil || importedCount != 0 || skipped != 1 {
+		t.Fatalf("reimport after deleting kept row = %d/%d, %v", importedCount, skipped, err)
+	}
+	if existing, err := repo.ExistingImportFingerprints(ctx, user.ID, "ofx", []string{"lidl"}); err != nil || len(existing) != 1 {
+		t.Fatalf("fingerprints after deleting kept row = %#v, %v", existing, err)
+	}
+}
+
+func TestTransactionExportsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "exports@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	for day := 1; day <= transactionExportFetchSize+3; day++ {
+		occurredAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, day).Format(time.DateOnly)
+		if _, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+			Type: "expense", Category: "other", Description: "Row", Amount: "1.00", Currency: "EUR", OccurredAt: occurredAt,
+		}); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+	from, to := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	streamed, previous := 0, ""
+	if err := repo.StreamTransactions(ctx, user.ID, from, to, func(transaction model.Transaction) error {
+		if transaction.OccurredAt < previous {
+			t.Fatalf("rows out of order: %s after %s", transaction.OccurredAt, previous)
+		}
+		previous = transaction.OccurredAt
+		streamed++
+		return nil
+	}); err != nil || streamed != transactionExportFetchSize+3 {
+		t.Fatalf("streamed %d rows, error = %v", streamed, err)
+	}
+
+	request := model.TransactionExportRequest{From: "2020-01-01", To: "2025-12-31", Format: "csv"}
+	item, err := repo.CreateTransactionExport(ctx, user.ID, request, time.Now().Add(time.Hour), 1)
+	if err != nil || item.Status != "pending" {
+		t.Fatalf("create export = %#v, %v", item, err)
+	}
+	if _, err := repo.CreateTransactionExport(ctx, user.ID, request, time.Now().Add(time.Hour), 1); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("second active export error = %v", err)
+	}
+	now := time.Now()
+	jobs, err := repo.ClaimTransactionExports(ctx, now, now.Add(time.Minute), 5, 3)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != item.ID || jobs[0].Attempts != 1 {
+		t.Fatalf("claim exports = %#v, %v", jobs, err)
+	}
+	if again, err := repo.ClaimTransactionExports(ctx, now, now.Add(time.Minute), 5, 3); err != nil || len(again) != 0 {
+		t.Fatalf("claimed running export again = %#v, %v", again, err)
+	}
+	for sequence, chunk := range []string{"first,", "second"} {
+		if err := repo.AppendTransactionExportChunk(ctx, item.ID, sequence, []byte(chunk), now.Add(time.Minute)); err != nil {
+			t.Fatalf("append chunk: %v", err)
+		}
+	}
+	if err := repo.CompleteTransactionExport(ctx, item.ID, 2, 12, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("complete export: %v", err)
+	}
+	var file []byte
+	if err := repo.StreamTransactionExportFile(ctx, item.ID, func(data []byte) error {
+		file = append(file, data...)
+		return nil
+	}); err != nil || string(file) != "first,second" {
+		t.Fatalf("export file = %q, %v", file, err)
+	}
+	completed, err := repo.GetTransactionExport(ctx, user.ID, item.ID)
+	if err != nil || completed.Status != "completed" || completed.Rows != 2 || completed.CompletedAt == "" {
+		t.Fatalf("completed export = %#v, %v", completed, err)
+	}
+	if _, err := repo.GetTransactionExport(ctx, user.ID+1, item.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("foreign export error = %v", err)
+	}
+	if expired, err := repo.DeleteExpiredTransactionExports(ctx, time.Now().Add(2*time.Hour)); err != nil || expired != 1 {
+		t.Fatalf("expired exports = %d, %v", expired, err)
+	}
+
+	// An export interrupted mid-write is claimed again from scratch until it
+	// runs out of attempts, then fails.
+	slow, err := repo.CreateTransactionExport(ctx, user.ID, request, time.Now().Add(time.Hour), 1)
+	if err != nil {
+		t.Fatalf("create slow export: %v", err)
+	}
+	claimAt := time.Now()
+	for attempt := 1; attempt <= 2; attempt++ {
+		jobs, err := repo.ClaimTransactionExports(ctx, claimAt, claimAt.Add(time.Minute), 5, 2)
+		if err != nil || len(jobs) != 1 || jobs[0].ID != slow.ID || jobs[0].Attempts != attempt {
+			t.Fatalf("claim %d = %#v, %v", attempt, jobs, err)
+		}
+		if err := repo.AppendTransactionExportChunk(ctx, slow.ID, 0, []byte("partial"), claimAt.Add(time.Minute)); err != nil {
+			t.Fatalf("append chunk %d: %v", attempt, err)
+		}
+		claimAt = claimAt.Add(2 * time.Minute)
+	}
+	if jobs, err := repo.ClaimTransactionExports(ctx, claimAt, claimAt.Add(time.Minute), 5, 2); err != nil || len(jobs) != 0 {
+		t.Fatalf("claimed exhausted export = %#v, %v", jobs, err)
+	}
+	exhausted, err := repo.GetTransactionExport(ctx, user.ID, slow.ID)
+	if err != nil || exhausted.Status != "failed" || exhausted.Error != "export took too long" {
+		t.Fatalf("exhausted export = %#v, %v", exhausted, err)
+	}
+	var leftover int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM transaction_export_chunks WHERE export_id=$1`, slow.ID).Scan(&leftover); err != nil || leftover != 0 {
+		t.Fatalf("exhausted export chunks = %d, %v", leftover, err)
+	}
+
+	// A claimed export that never started goes back without spending an attempt.
+	queued, err := repo.CreateTransactionExport(ctx, user.ID, request, time.Now().Add(time.Hour), 1)
+	if err != nil {
+		t.Fatalf("create queued export: %v", err)
+	}
+	if _, err := repo.ClaimTransactionExports(ctx, claimAt, claimAt.Add(time.Minute), 5, 2); err != nil {
+		t.Fatalf("claim queued export: %v", err)
+	}
+	if err := repo.ReleaseTransactionExport(ctx, queued.ID); err != nil {
+		t.Fatalf("release export: %v", err)
+	}
+	if jobs, err := repo.ClaimTransactionExports(ctx, claimAt, claimAt.Add(time.Minute), 5, 2); err != nil ||
+		len(jobs) != 1 || jobs[0].ID != queued.ID || jobs[0].Attempts != 1 {
+		t.Fatalf("claim released export = %#v, %v", jobs, err)
+	}
+}
+
+func TestJournalExportSourcesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "journal@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	other, err := repo.RegisterUser(ctx, "journal-other@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register other user: %v", err)
+	}
+	var connectionID, accountID int
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_connections(
+		user_id,provider_session_id,institution_name,country,psu_type,status,valid_until
+	) VALUES($1,'journal-session','Journal Bank','BG','personal','AUTHORIZED',now()+interval '30 days')
+	RETURNING id`, user.ID).Scan(&connectionID); err != nil {
+		t.Fatalf("create bank connection: %v", err)
+	}
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_accounts(
+		connection_id,provider_account_id,identification_hash,name,cash_account_type,currency,provider_payload
+	) VALUES($1,'journal-provider-account','journal-account','Current','CACC','EUR','{}')
+	RETURNING id`, connectionID).Scan(&accountID); err != nil {
+		t.Fatalf("create bank account: %v", err)
+	}
+	balances := []model.OpenBankingAccountBalance{
+		{AccountID: accountID, ReferenceDate: "2026-07-10", Type: "ITBD", Amount: "10.00", Currency: "EUR"},
+		{AccountID: accountID, ReferenceDate: "2026-07-10", Type: "CLBD", Amount: "12.50", Currency: "EUR"},
+	}
+	for _, balance := range balances {
+		if err := repo.RecordOpenBankingAccountBalances(ctx, user.ID, []model.OpenBankingAccountBalance{balance}); err != nil {
+			t.Fatalf("record balance: %v", err)
+		}
+	}
+	if err := repo.RecordOpenBankingAccountBalances(ctx, other.ID, balances); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("record foreign balance error = %v", err)
+	}
+	from, through := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC)
+	recorded, err := repo.ListOpenBankingAccountBalances(ctx, user.ID, from, through)
+	if err != nil || len(recorded) != 1 || recorded[0].Type != "CLBD" || recorded[0].Amount != "12.50" {
+		t.Fatalf("recorded balances = %#v, %v", recorded, err)
+	}
+
+	if _, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "groceries", Description: "Market", Amount: "4.00", Currency: "EUR", OccurredAt: "2026-07-12",
+	}); err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO transactions(
+		user_id,type,category,description,amount,currency,occurred_at,source,source_account_id,external_id
+	) VALUES($1,'expense','transport','Metro',2.00,'EUR','2026-07-11','open_banking',$2,'journal-bank-1')`,
+		user.ID, accountID); err != nil {
+		t.Fatalf("create bank transaction: %v", err)
+	}
+	uses, err := repo.ListTransactionAccountUses(ctx, user.ID, from, through.AddDate(0, 0, 1))
+	if err != nil || len(uses) != 2 || uses[0].Category != "groceries" || uses[0].Origin != "manual" ||
+		uses[1].Origin != "open_banking:"+strconv.Itoa(accountID) {
+		t.Fatalf("account uses = %#v, %v", uses, err)
+	}
+	origins := make([]string, 0, 2)
+	if err := repo.StreamTransactionsWithOrigin(ctx, user.ID, from, through.AddDate(0, 0, 1), func(_ model.Transaction, origin string) error {
+		origins = append(origins, origin)
+		return nil
+	}); err != nil || len(origins) != 2 || origins[0] != "open_banking:"+strconv.Itoa(accountID) || origins[1] != "manual" {
+		t.Fatalf("streamed origins = %v, %v", origins, err)
+	}
+}
+
+func TestMerchantsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "merchants@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	lidl := model.MerchantMatch{Key: "lidl", Name: "Lidl"}
+	imported, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{
+		{Request: model.TransactionRequest{Type: "expense", Category: "Groceries", Description: "LIDL 1234 SOFIA BG",
+			Amount: "20.00", Currency: "EUR", OccurredAt: "2026-07-02"}, Source: "ofx", Fingerprint: "m1", Merchant: lidl},
+		{Request: model.TransactionRequest{Type: "expense", Category: "Groceries", Description: "LIDL 88 VARNA BG",
+			Amount: "5.50", Currency: "EUR", OccurredAt: "2026-08-03"}, Source: "ofx", Fingerprint: "m2", Merchant: lidl},
+		{Request: model.TransactionRequest{Type: "expense", Category: "Groceries", Description: "LIDL BULGARIA",
+			Amount: "7.25", Currency: "EUR", OccurredAt: "2026-08-04"}, Source: "ofx", Fingerprint: "m3",
+			Merchant: model.MerchantMatch{Key: "lidl bulgaria", Name: "Lidl Bulgaria"}},
+		{Request: model.TransactionRequest{Type: "expense", Category: "Groceries", Description: "000123",
+			Amount: "1.00", Currency: "EUR", OccurredAt: "2026-08-05"}, Source: "ofx", Fingerprint: "m4"},
+	})
+	if err != nil || imported != 4 {
+		t.Fatalf("import = %d, %v", imported, err)
+	}
+	merchants, err := repo.ListMerchants(ctx, user.ID)
+	if err != nil || len(merchants) != 2 || merchants[0].Name != "Lidl" || merchants[0].TransactionCount != 2 {
+		t.Fatalf("merchants = %#v, %v", merchants, err)
+	}
+	keepID, mergeID := merchants[0].ID, merchants[1].ID
+	if _, err := repo.RenameMerchant(ctx, user.ID, keepID, "lidl bulgaria"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("conflicting rename error = %v", err)
+	}
+	merged, err := repo.MergeMerchants(ctx, user.ID, keepID, mergeID)
+	if err != nil || merged.TransactionCount != 3 || len(merged.Aliases) != 2 {
+		t.Fatalf("merged = %#v, %v", merged, err)
+	}
+	renamed, err := repo.RenameMerchant(ctx, user.ID, keepID, "Lidl Stores")
+	if err != nil || renamed.Name != "Lidl Stores" {
+		t.Fatalf("renamed = %#v, %v", renamed, err)
+	}
+	candidates, err := repo.ListMerchantCandidates(ctx, user.ID, 0, 10)
+	if err != nil || len(candidates) != 1 || candidates[0].Description != "000123" {
+		t.Fatalf("candidates = %#v, %v", candidates, err)
+	}
+	if err := repo.AssignTransactionMerchants(ctx, user.ID, []MerchantAssignment{
+		{TransactionID: candidates[0].TransactionID, Merchant: model.MerchantMatch{Key: "lidl bulgaria", Name: "Lidl Bulgaria"}},
+	}); err != nil {
+		t.Fatalf("assign merchants: %v", err)
+	}
+	from, to := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	spending, err := repo.ListMerchantSpending(ctx, user.ID, from, to)
+	if err != nil || len(spending) != 1 || spending[0].Total != "33.75" || spending[0].Transactions != 4 {
+		t.Fatalf("spending = %#v, %v", spending, err)
+	}
+	months, err := repo.ListMerchantMonthlySpending(ctx, user.ID, keepID, from, to)
+	if err != nil || len(months) != 2 || months[0].Month != "2026-07" || months[1].Total != "13.75" {
+		t.Fatalf("months = %#v, %v", months, err)
+	}
+}
+
+func TestCategorizationRulesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "rules@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if err := repo.EnsureDefaultCategories(ctx, user.ID); err != nil {
+		t.Fatalf("ensure categories: %v", err)
+	}
+	enabled := true
+	rule, err := repo.CreateCategorizationRule(ctx, user.ID, model.CategorizationRuleRequest{
+		Name: "Happy Bar", Priority: 5, Enabled: &enabled,
+		Conditions: model.CategorizationRuleConditions{DescriptionContains: "happy bar", AmountMax: "50.00", Type: "expense"},
+		Actions:    model.CategorizationRuleActions{Category: "dining_out", Tags: []string{"bars"}},
+	})
+	if err != nil || rule.Conditions.AmountMax != "50.00" || rule.Actions.Category != "dining_out" || !rule.Enabled {
+		t.Fatalf("created rule = %#v, %v", rule, err)
+	}
+	foreignAccount := 999999
+	if _, err := repo.CreateCategorizationRule(ctx, user.ID, model.CategorizationRuleRequest{
+		Name:       "Foreign",
+		Conditions: model.CategorizationRuleConditions{AccountID: &foreignAccount},
+		Actions:    model.CategorizationRuleActions{ExcludeFromBudget: true},
+	}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("foreign account rule error = %v", err)
+	}
+	imported, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{
+		{Request: model.TransactionRequest{Type: "expense", Category: "other", Description: "HAPPY BAR",
+			Amount: "12.00", Currency: "EUR", OccurredAt: "2026-07-02", ExcludedFromBudget: true, Tags: []string{"imported"}},
+			Source: "ofx", Fingerprint: "r1"},
+	})
+	if err != nil || imported != 1 {
+		t.Fatalf("import = %d, %v", imported, err)
+	}
+	from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	targets, err := repo.ListCategorizationRuleTargets(ctx, user.ID, from, from.AddDate(0, 1, 0))
+	if err != nil || len(targets) != 1 || !targets[0].ExcludedFromBudget || len(targets[0].Tags) != 1 {
+		t.Fatalf("targets = %#v, %v", targets, err)
+	}
+	update := CategorizationRuleUpdate{
+		TransactionID: targets[0].TransactionID, Category: "dining_out",
+		Tags: []string{"bars", "imported"}, ExcludedFromBudget: true,
+	}
+	updated, err := repo.ApplyCategorizationRuleUpdates(ctx, user.ID, []CategorizationRuleUpdate{update})
+	if err != nil || updated != 1 {
+		t.Fatalf("apply = %d, %v", updated, err)
+	}
+	if updated, err := repo.ApplyCategorizationRuleUpdates(ctx, user.ID, []CategorizationRuleUpdate{update}); err != nil || updated != 0 {
+		t.Fatalf("repeated apply = %d, %v", updated, err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE categories SET active=false WHERE user_id=$1 AND name='dining_out'`, user.ID); err != nil {
+		t.Fatalf("archive category: %v", err)
+	}
+	active, err := repo.ListActiveCategorizationRules(ctx, user.ID)
+	if err != nil || len(active) != 1 || active[0].Actions.Category != "" || len(active[0].Actions.Tags) != 1 {
+		t.Fatalf("active rules = %#v, %v", active, err)
+	}
+	if err := repo.DeleteCategorizationRule(ctx, user.ID, rule.ID); err != nil {
+		t.Fatalf("delete rule: %v", err)
+	}
+	if err := repo.DeleteCategorizationRule(ctx, user.ID, rule.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("repeated delete error = %v", err)
+	}
+}
+
+func TestCategoryLearningIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "learning@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if err := repo.EnsureDefaultCategories(ctx, user.ID); err != nil {
+		t.Fatalf("ensure categories: %v", err)
+	}
+	if _, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "dining_out", Description: "Happy Bar", Amount: "18.00", Currency: "EUR",
+		OccurredAt: "2026-07-01", CategoryChosen: true,
+	}); err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	if _, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "other", Description: "Kiosk", Amount: "2.00", Currency: "EUR", OccurredAt: "2026-07-01",
+	}); err != nil {
+		t.Fatalf("create catch-all transaction: %v", err)
+	}
+	if _, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{
+		{Request: model.TransactionRequest{Type: "expense", Category: "other", Description: "LIDL 12",
+			Amount: "30.00", Currency: "EUR", OccurredAt: "2026-07-02"}, Source: "ofx", Fingerprint: "l1"},
+		{Request: model.TransactionRequest{Type: "expense", Category: "groceries", Description: "KAUFLAND",
+			Amount: "12.00", Currency: "EUR", OccurredAt: "2026-07-03"}, Source: "ofx", Fingerprint: "l2", CategoryChosen: true},
+	}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	examples, err := repo.ListCategoryTrainingExamples(ctx, user.ID, 10)
+	if err != nil || len(examples) != 2 {
+		t.Fatalf("examples = %#v, %v", examples, err)
+	}
+	var importedID int
+	if err := pool.QueryRow(ctx, `SELECT id FROM transactions WHERE user_id=$1 AND import_fingerprint='l1'`, user.ID).Scan(&importedID); err != nil {
+		t.Fatalf("find imported row: %v", err)
+	}
+	if _, err := repo.UpdateTransaction(ctx, user.ID, importedID, model.TransactionRequest{
+		Type: "expense", Category: "groceries", Description: "LIDL 12", Amount: "30.00", Currency: "EUR", OccurredAt: "2026-07-02",
+	}); err != nil {
+		t.Fatalf("update transaction: %v", err)
+	}
+	examples, err = repo.ListCategoryTrainingExamples(ctx, user.ID, 10)
+	if err != nil || len(examples) != 3 || examples[0].Description != "LIDL 12" || examples[0].Category != "groceries" {
+		t.Fatalf("examples after correction = %#v, %v", examples, err)
+	}
+	forgotten, err := repo.ResetCategoryLearning(ctx, user.ID)
+	if err != nil || forgotten != 3 {
+		t.Fatalf("reset = %d, %v", forgotten, err)
+	}
+	if examples, err := repo.ListCategoryTrainingExamples(ctx, user.ID, 10); err != nil || len(examples) != 0 {
+		t.Fatalf("examples after reset = %#v, %v", examples, err)
+	}
+}
+
+func TestCategoryHierarchyIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "hierarchy@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if err := repo.EnsureDefaultCategories(ctx, user.ID); err != nil {
+		t.Fatalf("ensure categories: %v", err)
+	}
+	food, err := repo.CreateCategory(ctx, user.ID, model.CategoryRequest{Type: "expense", Name: "Food"})
+	if err != nil {
+		t.Fatalf("create food: %v", err)
+	}
+	snacks, err := repo.CreateCategory(ctx, user.ID, model.CategoryRequest{Type: "expense", Name: "Snaks", ParentID: &food.ID})
+	if err != nil || snacks.ParentID == nil || *snacks.ParentID != food.ID {
+		t.Fatalf("create snacks = %#v, %v", snacks, err)
+	}
+	for _, request := range []model.TransactionRequest{
+		{Type: "expense", Category: "Snaks", Description: "Chips", Amount: "4.00", Currency: "EUR", OccurredAt: "2026-07-02"},
+		{Type: "expense", Category: "Food", Description: "Market", Amount: "6.00", Currency: "EUR", OccurredAt: "2026-07-03"},
+	} {
+		if _, err := repo.CreateTransaction(ctx, user.ID, request); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+	reference := time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC)
+	if _, err := repo.CreateBudget(ctx, user.ID, model.BudgetRequest{
+		Name: "Food", Category: "Food", Amount: "100.00", Currency: "EUR", Period: "monthly", WarningThreshold: 80,
+	}, reference); err != nil {
+		t.Fatalf("create budget: %v", err)
+	}
+	if _, err := repo.CreateBudget(ctx, user.ID, model.BudgetRequest{
+		Name: "Snacks", Category: "Snaks", Amount: "10.00", Currency: "EUR", Period: "monthly", WarningThreshold: 80,
+	}, reference); err != nil {
+		t.Fatalf("create snacks budget: %v", err)
+	}
+
+	renamed, err := repo.UpdateCategory(ctx, user.ID, snacks.ID, model.CategoryUpdateRequest{Name: "Snacks", ParentID: &food.ID})
+	if err != nil || renamed.Name != "Snacks" {
+		t.Fatalf("rename = %#v, %v", renamed, err)
+	}
+	budgets, err := repo.ListBudgets(ctx, user.ID, reference, false)
+	if err != nil || len(budgets) != 2 {
+		t.Fatalf("budgets = %#v, %v", budgets, err)
+	}
+	for _, budget := range budgets {
+		if budget.Category == "Food" && budget.SpentAmount != "10.00" {
+			t.Fatalf("parent budget spent = %s", budget.SpentAmount)
+		}
+		if budget.Category == "Snacks" && budget.SpentAmount != "4.00" {
+			t.Fatalf("renamed budget = %#v", budget)
+		}
+		if budget.Category == "Snaks" {
+			t.Fatalf("budget kept the old name: %#v", budget)
+		}
+	}
+	summary, err := repo.Summary(ctx, user.ID, "2026-07", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(summary.Categories) != 1 || summary.Categories[0].Category != "Food" || summary.Categories[0].Amount != "10.00" ||
+		len(summary.Categories[0].Subcategories) != 1 || summary.Categories[0].Subcategories[0].Amount != "4.00" {
+		t.Fatalf("summary = %#v, %v", summary, err)
+	}
+
+	groceries, err := repo.FindActiveCategoryName(ctx, user.ID, "expense", "groceries")
+	if err != nil {
+		t.Fatalf("find groceries: %v", err)
+	}
+	var groceriesID int
+	if err := pool.QueryRow(ctx, `SELECT id FROM categories WHERE user_id=$1 AND type='expense' AND name=$2 AND active`,
+		user.ID, groceries).Scan(&groceriesID); err != nil {
+		t.Fatalf("groceries id: %v", err)
+	}
+	result, err := repo.MergeCategories(ctx, user.ID, food.ID, groceriesID)
+	if err != nil || result.Transactions != 1 || result.Budgets != 1 || result.Category.Name != "groceries" {
+		t.Fatalf("merge = %#v, %v", result, err)
+	}
+	moved, err := repo.GetCategory(ctx, user.ID, snacks.ID)
+	if err != nil || moved.ParentID == nil || *moved.ParentID != groceriesID {
+		t.Fatalf("subcategory after merge = %#v, %v", moved, err)
+	}
+	if _, err := repo.GetCategory(ctx, user.ID, food.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("merged category still active: %v", err)
+	}
+}
+
+func TestReportsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "reports@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if err := repo.EnsureDefaultCategories(ctx, user.ID); err != nil {
+		t.Fatalf("ensure categories: %v", err)
+	}
+	food, err := repo.CreateCategory(ctx, user.ID, model.CategoryRequest{Type: "expense", Name: "Food"})
+	if err != nil {
+		t.Fatalf("create food: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE categories SET parent_id=$1 WHERE user_id=$2 AND name='groceries'`, food.ID, user.ID); err != nil {
+		t.Fatalf("nest groceries: %v", err)
+	}
+	for _, request := range []model.TransactionRequest{
+		{Type: "income", Category: "salary", Amount: "1000.00", Currency: "EUR", OccurredAt: "2026-05-01"},
+		{Type: "expense", Category: "groceries", Amount: "30.00", Currency: "EUR", OccurredAt: "2026-05-03"},
+		{Type: "expense", Category: "transport", Amount: "10.00", Currency: "EUR", OccurredAt: "2026-06-03"},
+		{Type: "expense", Category: "Food", Amount: "20.00", Currency: "EUR", OccurredAt: "2026-06-04"},
+		{Type: "expense", Category: "groceries", Amount: "25.00", Currency: "EUR", OccurredAt: "2026-04-10"},
+		{Type: "expense", Category: "groceries", Amount: "40.00", Currency: "EUR", OccurredAt: "2025-05-10"},
+	} {
+		if _, err := repo.CreateTransaction(ctx, user.ID, request); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+	reportRange := ReportRange{
+		From: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		PreviousFrom: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), PreviousTo: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		LastYearFrom: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), LastYearTo: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cashFlow, err := repo.CashFlowReport(ctx, user.ID, reportRange)
+	if err != nil || len(cashFlow.Months) != 2 || cashFlow.Months[0].Net != "970.00" || cashFlow.Months[1].Income != "0.00" ||
+		cashFlow.Total.Expense != "60.00" || cashFlow.PreviousPeriod.Expense != "25.00" ||
+		cashFlow.PreviousPeriod.ExpenseChangePercent != "140.0" || cashFlow.SamePeriodLastYear.IncomeChangePercent != "" {
+		t.Fatalf("cash flow = %#v, %v", cashFlow, err)
+	}
+
+	categories, err := repo.CategoryReport(ctx, user.ID, "expense", reportRange)
+	if err != nil || categories.Total != "60.00" || len(categories.Categories) != 2 || len(categories.Months) != 2 {
+		t.Fatalf("categories = %#v, %v", categories, err)
+	}
+	if top := categories.Categories[0]; top.Category != "Food" || top.Amount != "50.00" || top.SharePercent != "83.3" ||
+		top.PreviousAmount != "25.00" || top.LastYearAmount != "40.00" || top.LastYearChangePercent != "25.0" {
+		t.Fatalf("top category = %#v", top)
+	}
+	if june := categories.Months[1]; june.Month != "2026-06" || june.Total != "30.00" || june.Categories[0].SharePercent != "66.7" {
+		t.Fatalf("june = %#v", june)
+	}
+
+	lidl := model.MerchantMatch{Key: "lidl", Name: "Lidl"}
+	if _, _, err := repo.ImportTransactions(ctx, user.ID, []model.ImportedTransaction{
+		{Request: model.TransactionRequest{Type: "expense", Category: "groceries", Description: "LIDL 1", Amount: "15.00",
+			Currency: "EUR", OccurredAt: "2026-06-20"}, Source: "ofx", Fingerprint: "r1", Merchant: lidl},
+		{Request: model.TransactionRequest{Type: "expense", Category: "groceries", Description: "LIDL 2", Amount: "5.00",
+			Currency: "EUR", OccurredAt: "2026-04-20"}, Source: "ofx", Fingerprint: "r2", Merchant: lidl},
+	}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	merchants, err := repo.MerchantReport(ctx, user.ID, reportRange, 5)
+	if err != nil || merchants.Total != "75.00" || len(merchants.Merchants) != 1 {
+		t.Fatalf("merchants = %#v, %v", merchants, err)
+	}
+	if item := merchants.Merchants[0]; item.Name != "Lidl" || item.Transactions != 1 || item.Amount != "15.00" ||
+		item.SharePercent != "20.0" || item.PreviousAmount != "5.00" || item.LastYearAmount != "0.00" {
+		t.Fatalf("merchant = %#v", item)
+	}
+
+	budget, err := repo.CreateBudget(ctx, user.ID, model.BudgetRequest{
+		Name: "Food", Category: "Food", Amount: "32.00", Currency: "EUR", Period: "monthly", WarningThreshold: 80,
+	}, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("create budget: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE budgets SET created_at='2026-04-15T09:00:00Z' WHERE id=$1`, budget.ID); err != nil {
+		t.Fatalf("backdate budget: %v", err)
+	}
+	hitRate, err := repo.BudgetHitRate(ctx, user.ID,
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || hitRate != (model.AnnualBudgets{Periods: 3, WithinBudget: 2, HitRatePercent: "66.7"}) {
+		t.Fatalf("budget hit rate = %#v, %v", hitRate, err)
+	}
+}
+
+func TestForecastSettingsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "forecast@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	settings, err := repo.GetForecastSettings(ctx, user.ID)
+	if err != nil || settings.LowBalanceThreshold != "" {
+		t.Fatalf("default forecast settings = %#v, %v", settings, err)
+	}
+	settings, err = repo.UpdateForecastSettings(ctx, user.ID, model.ForecastSettings{LowBalanceThreshold: "250.00"})
+	if err != nil || settings.LowBalanceThreshold != "250.00" {
+		t.Fatalf("updated forecast settings = %#v, %v", settings, err)
+	}
+	settings, err = repo.UpdateForecastSettings(ctx, user.ID, model.ForecastSettings{})
+	if err != nil || settings.LowBalanceThreshold != "" {
+		t.Fatalf("cleared forecast settings = %#v, %v", settings, err)
+	}
+}
+
+func TestSavingsGoalsIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "savings-goals@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	var connectionID, accountID int
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_connections(
+		user_id,provider_session_id,institution_name,country,psu_type,status,valid_until
+	) VALUES($1,'savings-goal-session','Savings Bank','BG','personal','AUTHORIZED',now()+interval '30 days')
+	RETURNING id`, user.ID).Scan(&connectionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_accounts(
+		connection_id,provider_account_id,identification_hash,name,cash_account_type,currency,provider_payload
+	) VALUES($1,'savings-account','savings-account-hash','Savings','SVGS','EUR','{}') RETURNING id`,
+		connectionID,
+	).Scan(&accountID); err != nil {
+		t.Fatal(err)
+	}
+	goal, err := repo.CreateSavingsGoal(ctx, user.ID, model.SavingsGoalRequest{
+		Name: "Car", TargetAmount: "1000.00", Currency: "EUR", StartDate: "2026-07-01", TargetDate: "2027-07-01",
+		AccountID: &accountID, Tag: "car",
+	})
+	if err != nil {
+		t.Fatalf("create savings goal: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO transactions(
+		user_id,type,category,description,amount,currency,occurred_at,source,status,source_account_id,external_id,tags
+	) VALUES
+		($1,'income','other','Transfer in',300,'EUR','2026-07-05','open_banking','booked',$2,'savings-in',$3),
+		($1,'expense','other','Card payment',20,'EUR','2026-07-06','open_banking','booked',$2,'savings-out','{}'),
+		($1,'income','other','Before the goal',999,'EUR','2026-06-30','open_banking','booked',$2,'savings-early','{}')`,
+		user.ID, accountID, []string{"car"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO transactions(user_id,type,category,description,amount,currency,occurred_at,tags)
+		VALUES($1,'expense','other','To savings',200,'EUR','2026-07-10',$2),
+			($1,'income','other','Back from savings',50,'EUR','2026-07-11',$2),
+			($1,'expense','other','Untagged',80,'EUR','2026-07-12','{}')`, user.ID, []string{"car"}); err != nil {
+		t.Fatal(err)
+	}
+	contribution, err := repo.CreateSavingsGoalContribution(ctx, user.ID, goal.ID, model.SavingsGoalContributionRequest{
+		Amount: "100.00", Date: "2026-07-15", Note: "Birthday money",
+	})
+	if err != nil || contribution.ID == 0 || contribution.Source != "manual" {
+		t.Fatalf("add contribution = %#v, %v", contribution, err)
+	}
+
+	// The tagged transfer into the linked account counts once, as an account
+	// entry: 300 - 20 + 200 - 50 + 100.
+	goal, err = repo.GetSavingsGoal(ctx, user.ID, goal.ID)
+	if err != nil || goal.SavedAmount != "530.00" || goal.AccountID == nil || *goal.AccountID != accountID || goal.Tag != "car" {
+		t.Fatalf("savings goal = %#v, %v", goal, err)
+	}
+	contributions, err := repo.ListSavingsGoalContributions(ctx, user.ID, goal.ID)
+	if err != nil || len(contributions) != 5 || contributions[0].Source != "manual" ||
+		contributions[4].Source != "account" || contributions[4].Amount != "300.00" || contributions[4].TransactionID == nil {
+		t.Fatalf("contributions = %#v, %v", contributions, err)
+	}
+
+	milestones, err := repo.QueueSavingsGoalMilestones(ctx)
+	if err != nil || milestones < 1 {
+		t.Fatalf("queue savings goal milestones = %d, %v", milestones, err)
+	}
+	var notified, recorded int
+	if err := pool.QueryRow(ctx, `SELECT
+		(SELECT count(*) FROM notification_outbox WHERE user_id=$1 AND event_type='savings_goal_milestone'
+			AND event_key='savings_goal:'||$2::text||':50'),
+		(SELECT count(*) FROM savings_goal_milestones WHERE goal_id=$2)`, user.ID, goal.ID,
+	).Scan(&notified, &recorded); err != nil || notified != 1 || recorded != 2 {
+		t.Fatalf("milestones notified %d, recorded %d, %v", notified, recorded, err)
+	}
+	if again, err := repo.QueueSavingsGoalMilestones(ctx); err != nil || again != 0 {
+		t.Fatalf("requeue savings goal milestones = %d, %v", again, err)
+	}
+
+	if err := repo.DeleteSavingsGoalContribution(ctx, user.ID, goal.ID, contribution.ID); err != nil {
+		t.Fatalf("delete contribution: %v", err)
+	}
+	if err := repo.ArchiveSavingsGoal(ctx, user.ID, goal.ID); err != nil {
+		t.Fatalf("archive savings goal: %v", err)
+	}
+	if _, err := repo.CreateSavingsGoalContribution(ctx, user.ID, goal.ID, model.SavingsGoalContributionRequest{
+		Amount: "5.00", Date: "2026-07-16",
+	}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("contribution to archived goal error = %v", err)
+	}
+	if goals, err := repo.ListSavingsGoals(ctx, user.ID, false); err != nil || len(goals) != 0 {
+		t.Fatalf("active savings goals = %#v, %v", goals, err)
+	}
+}
+
+func TestLoansIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "loans@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	loan, err := repo.CreateLoan(ctx, user.ID, model.LoanRequest{
+		Name: "Car", Kind: "car", Principal: "12000.00", AnnualRate: "6.000", TermMonths: 12,
+		StartDate: "2026-01-15", PaymentDay: 1, Currency: "EUR",
+	})
+	if err != nil || loan.AnnualRate != "6.000" || loan.PaymentDay != 1 || loan.Status != "active" {
+		t.Fatalf("create loan = %#v, %v", loan, err)
+	}
+	transaction, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "other", Description: "Car loan", Amount: "1032.80", Currency: "EUR", OccurredAt: "2026-02-01",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := repo.CreateLoanPayment(ctx, user.ID, loan.ID, model.LoanPaymentRequest{
+		Kind: "installment", TransactionID: &transaction.ID, Amount: "1032.80", Date: "2026-02-01",
+	})
+	if err != nil || payment.TransactionID == nil || *payment.TransactionID != transaction.ID {
+		t.Fatalf("link installment = %#v, %v", payment, err)
+	}
+	if _, err := repo.CreateLoanPayment(ctx, user.ID, loan.ID, model.LoanPaymentRequest{
+		Kind: "extra", TransactionID: &transaction.ID, Amount: "1032.80", Date: "2026-02-01",
+	}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("duplicate link error = %v", err)
+	}
+	extra, err := repo.CreateLoanPayment(ctx, user.ID, loan.ID, model.LoanPaymentRequest{
+		Kind: "extra", Amount: "1000.00", Date: "2026-03-10",
+	})
+	if err != nil || extra.TransactionID != nil {
+		t.Fatalf("record extra = %#v, %v", extra, err)
+	}
+	payments, err := repo.ListLoanPayments(ctx, user.ID, loan.ID)
+	if err != nil || len(payments) != 2 || payments[0].Kind != "installment" || payments[1].Amount != "1000.00" {
+		t.Fatalf("payments = %#v, %v", payments, err)
+	}
+
+	// Deleting the ledger transaction removes its loan payment.
+	if err := repo.DeleteTransaction(ctx, user.ID, transaction.ID); err != nil {
+		t.Fatal(err)
+	}
+	if payments, err := repo.ListLoanPayments(ctx, user.ID, loan.ID); err != nil || len(payments) != 1 {
+		t.Fatalf("payments after transaction delete = %#v, %v", payments, err)
+	}
+	if err := repo.DeleteLoanPayment(ctx, user.ID, loan.ID, extra.ID); err != nil {
+		t.Fatalf("delete loan payment: %v", err)
+	}
+	if err := repo.ArchiveLoan(ctx, user.ID, loan.ID); err != nil {
+		t.Fatalf("archive loan: %v", err)
+	}
+	if _, err := repo.CreateLoanPayment(ctx, user.ID, loan.ID, model.LoanPaymentRequest{
+		Kind: "extra", Amount: "5.00", Date: "2026-03-11",
+	}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("payment on archived loan error = %v", err)
+	}
+	if loans, err := repo.ListLoans(ctx, user.ID, true); err != nil || len(loans) != 1 || loans[0].Status != "archived" {
+		t.Fatalf("loans = %#v, %v", loans, err)
+	}
+}
+
+func TestSharedExpensesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "shared-owner@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	if _, err := repo.RegisterUser(ctx, "shared-friend@example.com", "hash"); err != nil {
+		t.Fatalf("register friend: %v", err)
+	}
+	group, err := repo.CreateExpenseGroup(ctx, user.ID, model.ExpenseGroupRequest{
+		Name: "Trip", Currency: "EUR", SelfName: "Me",
+		Members: []model.ExpenseGroupMemberRequest{{Name: "Ana", Email: "shared-friend@example.com"}, {Name: "Ben"}},
+	})
+	if err != nil || len(group.Members) != 3 || !group.Members[0].IsSelf || !group.Members[1].HasAccount || group.Members[2].HasAccount {
+		t.Fatalf("create expense group = %#v, %v", group, err)
+	}
+	self, ana, ben := group.Members[0].ID, group.Members[1].ID, group.Members[2].ID
+	if _, err := repo.AddExpenseGroupMember(ctx, user.ID, group.ID, model.ExpenseGroupMemberRequest{Name: "ben"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("duplicate member error = %v", err)
+	}
+
+	reference := time.Date(2026, 7, 18, 0, 0, 0, 0, time.UTC)
+	budget, err := repo.CreateBudget(ctx, user.ID, model.BudgetRequest{
+		Name: "Eating out", Category: "restaurants", Amount: "100.00", Currency: "EUR", Period: "monthly", WarningThreshold: 80,
+	}, reference)
+	if err != nil {
+		t.Fatalf("create budget: %v", err)
+	}
+	dinner, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "restaurants", Description: "Dinner", Amount: "90.00", Currency: "EUR", OccurredAt: "2026-07-10",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expense, err := repo.CreateSharedExpense(ctx, user.ID, group.ID, model.SharedExpense{
+		Description: "Dinner", Amount: "90.00", Date: "2026-07-10", PaidByMemberID: self, SplitMethod: "equal",
+		TransactionID: &dinner.ID, Shares: []model.SharedExpenseShare{
+			{MemberID: self, Amount: "30.00"}, {MemberID: ana, Amount: "30.00"}, {MemberID: ben, Amount: "30.00"},
+		},
+	})
+	if err != nil || expense.ID == 0 || len(expense.Shares) != 3 {
+		t.Fatalf("create shared expense = %#v, %v", expense, err)
+	}
+	if _, err := repo.CreateSharedExpense(ctx, user.ID, group.ID, model.SharedExpense{
+		Description: "Again", Amount: "90.00", Date: "2026-07-10", PaidByMemberID: self, SplitMethod: "equal",
+		TransactionID: &dinner.ID,
+	}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("duplicate transaction link error = %v", err)
+	}
+	repayment, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+		Type: "expense", Category: "restaurants", Description: "Paid Ana back", Amount: "15.00", Currency: "EUR", OccurredAt: "2026-07-12",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateExpenseSettlement(ctx, user.ID, group.ID, model.ExpenseSettlementRequest{
+		FromMemberID: self, ToMemberID: ana, Amount: "15.00", Date: "2026-07-12", TransactionID: &repayment.ID,
+	}); err != nil {
+		t.Fatalf("create settlement: %v", err)
+	}
+
+	// Only the owner's 30.00 share of the dinner counts; the repayment counts
+	// nothing.
+	budgets, err := repo.ListBudgets(ctx, user.ID, reference, false)
+	if err != nil || len(budgets) != 1 || budgets[0].ID != budget.ID || budgets[0].SpentAmount != "30.00" {
+		t.Fatalf("budgets = %#v, %v", budgets, err)
+	}
+
+	expenses, err := repo.ListSharedExpenses(ctx, user.ID, group.ID)
+	if err != nil || len(expenses) != 1 || len(expenses[0].Shares) != 3 || expenses[0].TransactionID == nil {
+		t.Fatalf("shared expenses = %#v, %v", expenses, err)
+	}
+	settlements, err := repo.ListExpenseSettlements(ctx, user.ID, group.ID)
+	if err != nil || len(settlements) != 1 || settlements[0].Amount != "15.00" {
+		t.Fatalf("settlements = %#v, %v", settlements, err)
+	}
+	if err := repo.DeleteExpenseGroupMember(ctx, user.ID, group.ID, ben); !errors.Is(err, ErrConflict) {
+		t.Fatalf("delete referenced member error = %v", err)
+	}
+	extra, err := repo.AddExpenseGroupMember(ctx, user.ID, group.ID, model.ExpenseGroupMemberRequest{Name: "Cleo"})
+	if err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	if err := repo.DeleteExpenseGroupMember(ctx, user.ID, group.ID, extra.ID); err != nil {
+		t.Fatalf("delete unused member: %v", err)
+	}
+	if err := repo.DeleteSharedExpense(ctx, user.ID, group.ID, expense.ID); err != nil {
+		t.Fatalf("delete shared expense: %v", err)
+	}
+	if budgets, err := repo.ListBudgets(ctx, user.ID, reference, false); err != nil || budgets[0].SpentAmount != "90.00" {
+		t.Fatalf("budgets after unsplitting = %#v, %v", budgets, err)
+	}
+	if err := repo.ArchiveExpenseGroup(ctx, user.ID, group.ID); err != nil {
+		t.Fatalf("archive expense group: %v", err)
+	}
+	if _, err := repo.CreateExpenseSettlement(ctx, user.ID, group.ID, model.ExpenseSettlementRequest{
+		FromMemberID: ben, ToMemberID: self, Amount: "5.00", Date: "2026-07-13",
+	}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("settlement in archived group error = %v", err)
+	}
+}
+
+func TestScheduleRRulesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "rrules@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	now := time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC)
+	schedule, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "income", Name: "Salary", Category: "salary", Amount: "2500.00", Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-07-01",
+		RRule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", Timezone: "Europe/Sofia",
+	})
+	if err != nil || schedule.RRule != "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" || schedule.DayOfMonth != nil {
+		t.Fatalf("create rrule schedule = %#v, %v", schedule, err)
+	}
+	dayOfMonth := 1
+	schedule, err = repo.UpdateTransactionSchedule(ctx, user.ID, schedule.ID, model.TransactionScheduleRequest{
+		Type: "income", Name: "Salary", Category: "salary", Amount: "2500.00", Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-07-01", DayOfMonth: &dayOfMonth,
+		Timezone: "Europe/Sofia",
+	}, now)
+	if err != nil || schedule.RRule != "" || schedule.DayOfMonth == nil {
+		t.Fatalf("update schedule to day of month = %#v, %v", schedule, err)
+	}
+	if _, err := repo.UpdateTransactionSchedule(ctx, user.ID, schedule.ID, model.TransactionScheduleRequest{
+		Type: "income", Name: "Salary", Category: "salary", Amount: "2500.00", Currency: "EUR",
+		Frequency: "yearly", FrequencyInterval: 1, StartDate: "2026-07-01", Timezone: "Europe/Sofia",
+	}, now); err == nil {
+		t.Fatal("yearly schedule without rrule was stored")
+	}
+
+	investment, err := repo.CreateInvestmentSchedule(ctx, user.ID, model.InvestmentScheduleRequest{
+		AssetType: "crypto", Symbol: "BTC", AssetName: "Bitcoin", Broker: "manual",
+		Amount: "100.00", Currency: "EUR", Frequency: "yearly", FrequencyInterval: 1,
+		StartDate: "2026-07-01", RRule: "FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=1", Timezone: "Europe/Sofia",
+	})
+	if err != nil || investment.Frequency != "yearly" || investment.RRule != "FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=1" {
+		t.Fatalf("create rrule investment schedule = %#v, %v", investment, err)
+	}
+	investments, err := repo.ListActiveInvestmentSchedules(ctx)
+	if err != nil || len(investments) != 1 || investments[0].RRule != investment.RRule {
+		t.Fatalf("active investment schedules = %#v, %v", investments, err)
+	}
+}
+
+func TestScheduleBusinessDaysIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "business-days@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	schedule, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Gym", Category: "other", Amount: "5.00", Currency: "EUR",
+		Frequency: "daily", FrequencyInterval: 1, StartDate: "2026-07-17",
+		BusinessDayAdjustment: "next", HolidayCalendar: "BG", Timezone: "Europe/Sofia",
+	})
+	if err != nil || schedule.BusinessDayAdjustment != "next" || schedule.HolidayCalendar != "BG" {
+		t.Fatalf("create adjusted schedule = %#v, %v", schedule, err)
+	}
+	monday := time.Date(2026, time.July, 20, 0, 0, 0, 0, time.UTC)
+	seeds := make([]ScheduleOccurrenceSeed, 0, 3)
+	for _, nominal := range []time.Time{monday.AddDate(0, 0, -2), monday.AddDate(0, 0, -1), monday} {
+		seeds = append(seeds, ScheduleOccurrenceSeed{
+			ScheduleID: schedule.ID, UserID: user.ID, ScheduledFor: monday, NominalDate: nominal,
+			Type: "expense", Name: "Gym", Category: "other", Amount: "5.00", Currency: "EUR",
+		})
+	}
+	if inserted, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil || inserted != 3 {
+		t.Fatalf("insert adjusted occurrences = %d, %v", inserted, err)
+	}
+	if inserted, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil || inserted != 0 {
+		t.Fatalf("repeat adjusted occurrences = %d, %v", inserted, err)
+	}
+	occurrences, err := repo.ListTransactionScheduleOccurrences(ctx, user.ID, ScheduleOccurrenceFilter{
+		From: monday, Through: monday, ScheduleID: schedule.ID,
+	})
+	if err != nil || len(occurrences) != 3 || occurrences[0].ScheduledFor != "2026-07-20" || occurrences[0].NominalDate != "2026-07-18" {
+		t.Fatalf("adjusted occurrences = %#v, %v", occurrences, err)
+	}
+
+	// Callers that leave the adjustment empty, like most tests in this file,
+	// store 'none' rather than failing the check constraint.
+	plain, err := repo.UpdateTransactionSchedule(ctx, user.ID, schedule.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Gym", Category: "other", Amount: "5.00", Currency: "EUR",
+		Frequency: "daily", FrequencyInterval: 1, StartDate: "2026-07-17", Timezone: "Europe/Sofia",
+	}, monday)
+	if err != nil || plain.BusinessDayAdjustment != "none" || plain.HolidayCalendar != "" {
+		t.Fatalf("update unadjusted schedule = %#v, %v", plain, err)
+	}
+	investment, err := repo.CreateInvestmentSchedule(ctx, user.ID, model.InvestmentScheduleRequest{
+		AssetType: "crypto", Symbol: "BTC", AssetName: "Bitcoin", Broker: "manual",
+		Amount: "100.00", Currency: "EUR", Frequency: "daily", FrequencyInterval: 1,
+		StartDate: "2026-07-17", Timezone: "Europe/Sofia",
+	})
+	if err != nil || investment.BusinessDayAdjustment != "none" {
+		t.Fatalf("create unadjusted investment schedule = %#v, %v", investment, err)
+	}
+}
+
+func TestScheduleOccurrenceOverridesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "occurrence-overrides@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	monday := 1
+	request := model.TransactionScheduleRequest{
+		Type: "expense", Name: "Rent", Category: "other", Amount: "900.00", Currency: "EUR",
+		Frequency: "weekly", FrequencyInterval: 1, StartDate: "2026-07-20", DayOfWeek: &monday,
+		Timezone: "Europe/Sofia",
+	}
+	schedule, err := repo.CreateTransactionSchedule(ctx, user.ID, request)
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	first := time.Date(2026, time.July, 20, 0, 0, 0, 0, time.UTC)
+	seeds := make([]ScheduleOccurrenceSeed, 0, 3)
+	for week := range 3 {
+		day := first.AddDate(0, 0, 7*week)
+		seeds = append(seeds, ScheduleOccurrenceSeed{
+			ScheduleID: schedule.ID, UserID: user.ID, ScheduledFor: day, NominalDate: day,
+			Type: "expense", Name: "Rent", Category: "other", Amount: "900.00", Currency: "EUR",
+		})
+	}
+	if _, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil {
+		t.Fatalf("insert occurrences: %v", err)
+	}
+	occurrences, err := repo.ListTransactionScheduleOccurrences(ctx, user.ID, ScheduleOccurrenceFilter{
+		From: first, Through: first.AddDate(0, 0, 14), ScheduleID: schedule.ID,
+	})
+	if err != nil || len(occurrences) != 3 {
+		t.Fatalf("list occurrences = %#v, %v", occurrences, err)
+	}
+	overridden, err := repo.UpdateTransactionScheduleOccurrence(ctx, user.ID, occurrences[0].ID, model.ScheduleOccurrenceRequest{
+		ScheduledFor: "2026-07-21", Amount: "950.00", Category: "other", Description: "Heating",
+	})
+	if err != nil || !overridden.Overridden || overridden.ScheduledFor != "2026-07-21" || overridden.NominalDate != "2026-07-20" ||
+		overridden.Amount != "950.00" {
+		t.Fatalf("override occurrence = %#v, %v", overridden, err)
+	}
+	skipped, err := repo.SetTransactionScheduleOccurrenceStatus(ctx, user.ID, occurrences[1].ID, "planned", "skipped")
+	if err != nil || skipped.Status != "skipped" {
+		t.Fatalf("skip occurrence = %#v, %v", skipped, err)
+	}
+	if _, err := repo.SetTransactionScheduleOccurrenceStatus(ctx, user.ID, occurrences[1].ID, "planned", "skipped"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("skip skipped occurrence error = %v", err)
+	}
+
+	request.Amount = "1000.00"
+	if _, err := repo.UpdateTransactionSchedule(ctx, user.ID, schedule.ID, request, first); err != nil {
+		t.Fatalf("update schedule: %v", err)
+	}
+	if inserted, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil || inserted != 1 {
+		t.Fatalf("re-materialize occurrences = %d, %v", inserted, err)
+	}
+	occurrences, err = repo.ListTransactionScheduleOccurrences(ctx, user.ID, ScheduleOccurrenceFilter{
+		From: first, Through: first.AddDate(0, 0, 14), ScheduleID: schedule.ID,
+	})
+	if err != nil || len(occurrences) != 3 || occurrences[0].Amount != "950.00" || occurrences[1].Status != "skipped" ||
+		occurrences[2].Overridden {
+		t.Fatalf("occurrences after schedule edit = %#v, %v", occurrences, err)
+	}
+
+	investment, err := repo.CreateInvestmentSchedule(ctx, user.ID, model.InvestmentScheduleRequest{
+		AssetType: "crypto", Symbol: "BTC", AssetName: "Bitcoin", Broker: "manual", Amount: "50.00", Currency: "EUR",
+		Frequency: "weekly", FrequencyInterval: 1, StartDate: "2026-07-20", DayOfWeek: &monday,
+		Timezone: "Europe/Sofia",
+	})
+	if err != nil {
+		t.Fatalf("create investment schedule: %v", err)
+	}
+	if _, err := repo.UpsertInvestmentScheduleOccurrences(ctx, []InvestmentScheduleOccurrenceSeed{{
+		ScheduleID: investment.ID, UserID: user.ID, ScheduledFor: first, NominalDate: first, Amount: "50.00",
+	}}); err != nil {
+		t.Fatalf("insert investment occurrence: %v", err)
+	}
+	investmentOccurrences, err := repo.ListInvestmentScheduleOccurrences(ctx, user.ID, ScheduleOccurrenceFilter{
+		From: first, Through: first, Status: "planned",
+	})
+	if err != nil || len(investmentOccurrences) != 1 || investmentOccurrences[0].Symbol != "BTC" {
+		t.Fatalf("list investment occurrences = %#v, %v", investmentOccurrences, err)
+	}
+	override, err := repo.UpdateInvestmentScheduleOccurrence(ctx, user.ID, investmentOccurrences[0].ID,
+		model.InvestmentScheduleOccurrenceRequest{ScheduledFor: "2026-07-20", Amount: "75.00"})
+	if err != nil || !override.Overridden || override.Amount != "75.00" {
+		t.Fatalf("override investment occurrence = %#v, %v", override, err)
+	}
+	due, err := repo.ListDueInvestmentScheduleOccurrences(ctx, time.Date(2026, time.July, 20, 12, 0, 0, 0, time.UTC), 100)
+	if err != nil {
+		t.Fatalf("list due investment occurrences: %v", err)
+	}
+	dueAmount := ""
+	for _, item := range due {
+		if item.ID == override.ID {
+			dueAmount = item.Amount
+		}
+	}
+	if dueAmount != "75.00" {
+		t.Fatalf("due overridden investment amount = %q", dueAmount)
+	}
+}
+
+func TestScheduleReconciliationIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "schedule-reconciliation@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	schedule, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Electricity", Category: "other", Description: "EVN", Amount: "80.00", Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-10-01", Timezone: "Europe/Sofia", AutoPost: true,
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	first := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 4)
+	seeds := []ScheduleOccurrenceSeed{
+		{ScheduleID: schedule.ID, UserID: user.ID, ScheduledFor: first, NominalDate: first, Type: "expense",
+			Name: "Electricity", Category: "other", Description: "EVN", Amount: "80.00", Currency: "EUR", AutoPost: true},
+		{ScheduleID: schedule.ID, UserID: user.ID, ScheduledFor: second, NominalDate: second, Type: "expense",
+			Name: "Electricity", Category: "other", Description: "EVN", Amount: "80.00", Currency: "EUR"},
+	}
+	if _, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil {
+		t.Fatalf("insert occurrences: %v", err)
+	}
+	if _, err := repo.PostDueTransactionScheduleOccurrences(ctx, first.Add(12*time.Hour), 500); err != nil {
+		t.Fatalf("post due occurrences: %v", err)
+	}
+	occurrences, err := repo.ListUnreconciledTransactionScheduleOccurrences(ctx, user.ID, first, second)
+	if err != nil || len(occurrences) != 2 || occurrences[0].Status != "posted" || occurrences[0].TransactionID == nil ||
+		occurrences[1].Status != "planned" {
+		t.Fatalf("unreconciled occurrences = %#v, %v", occurrences, err)
+	}
+	synthetic := *occurrences[0].TransactionID
+
+	var connectionID, accountID int
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_connections(
+		user_id,provider_session_id,institution_name,country,psu_type,status,valid_until
+	) VALUES($1,'reconciliation-session','Reconciliation Bank','BG','personal','AUTHORIZED',now()+interval '30 days')
+	RETURNING id`, user.ID).Scan(&connectionID); err != nil {
+		t.Fatalf("create bank connection: %v", err)
+	}
+	if err := pool.QueryRow(ctx, `INSERT INTO open_banking_accounts(
+		connection_id,provider_account_id,identification_hash,name,cash_account_type,currency,provider_payload
+	) VALUES($1,'reconciliation-provider-account','reconciliation-account','Current','CACC','EUR','{}')
+	RETURNING id`, connectionID).Scan(&accountID); err != nil {
+		t.Fatalf("create bank account: %v", err)
+	}
+	if _, err := repo.ImportOpenBankingTransactions(ctx, user.ID, accountID, []OpenBankingTransactionSeed{{
+		ExternalID: "reconciliation-evn", Type: "expense", Category: "other", Description: "EVN Bulgaria",
+		Amount: "81.40", Currency: "EUR", OccurredAt: first.AddDate(0, 0, 2), Metadata: []byte(`{}`),
+	}}, time.Date(2026, time.October, 20, 6, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("import bank transaction: %v", err)
+	}
+	bank, err := repo.ListReconcilableBankTransactions(ctx, user.ID, first, second)
+	if err != nil || len(bank) != 1 || bank[0].Source != "open_banking" {
+		t.Fatalf("reconcilable bank transactions = %#v, %v", bank, err)
+	}
+	dueUsers := func() map[int]bool {
+		users, err := repo.ListTransactionScheduleReconciliationUsers(ctx)
+		if err != nil {
+			t.Fatalf("list reconciliation users: %v", err)
+		}
+		due := make(map[int]bool, len(users))
+		for _, userID := range users {
+			due[userID] = true
+		}
+		return due
+	}
+	if !dueUsers()[user.ID] {
+		t.Fatal("user with a new bank row is not due for reconciliation")
+	}
+	if err := repo.MarkTransactionSchedulesReconciled(ctx, user.ID, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("mark schedules reconciled: %v", err)
+	}
+	if err := repo.MarkTransactionSchedulesReconciled(ctx, user.ID, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("mark schedules reconciled earlier: %v", err)
+	}
+	if dueUsers()[user.ID] {
+		t.Fatal("user is due for reconciliation without new bank rows")
+	}
+
+	if err := repo.ReconcileTransactionScheduleOccurrence(ctx, user.ID, occurrences[0].ID, bank[0].ID); err != nil {
+		t.Fatalf("reconcile occurrence: %v", err)
+	}
+	if err := repo.ReconcileTransactionScheduleOccurrence(ctx, user.ID, occurrences[0].ID, bank[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("reconcile reconciled occurrence error = %v", err)
+	}
+	if _, err := repo.GetTransaction(ctx, user.ID, synthetic); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("scheduled transaction after reconciliation error = %v", err)
+	}
+	reconciled, err := repo.GetTransactionScheduleOccurrence(ctx, user.ID, occurrences[0].ID)
+	if err != nil || reconciled.Status != "posted" || reconciled.ReconciledAt == "" ||
+		reconciled.TransactionID == nil || *reconciled.TransactionID != bank[0].ID {
+		t.Fatalf("reconciled occurrence = %#v, %v", reconciled, err)
+	}
+	if bank, err = repo.ListReconcilableBankTransactions(ctx, user.ID, first, second); err != nil || len(bank) != 0 {
+		t.Fatalf("reconcilable bank transactions after link = %#v, %v", bank, err)
+	}
+
+	// A paused schedule, even one the bank paid before, and a cash schedule the
+	// bank never paid are not reported as missed.
+	water, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Water", Category: "other", Description: "Sofiyska Voda", Amount: "30.00", Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-10-01", Timezone: "Europe/Sofia",
+	})
+	if err != nil {
+		t.Fatalf("create paused schedule: %v", err)
+	}
+	lessons, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Piano lessons", Category: "other", Description: "Piano", Amount: "40.00", Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-10-01", Timezone: "Europe/Sofia",
+	})
+	if err != nil {
+		t.Fatalf("create cash schedule: %v", err)
+	}
+	if _, err := repo.UpsertTransactionScheduleOccurrences(ctx, []ScheduleOccurrenceSeed{
+		{ScheduleID: water.ID, UserID: user.ID, ScheduledFor: first, NominalDate: first, Type: "expense",
+			Name: "Water", Category: "other", Description: "Sofiyska Voda", Amount: "30.00", Currency: "EUR"},
+		{ScheduleID: water.ID, UserID: user.ID, ScheduledFor: second, NominalDate: second, Type: "expense",
+			Name: "Water", Category: "other", Description: "Sofiyska Voda", Amount: "30.00", Currency: "EUR"},
+		{ScheduleID: lessons.ID, UserID: user.ID, ScheduledFor: second, NominalDate: second, Type: "expense",
+			Name: "Piano lessons", Category: "other", Description: "Piano", Amount: "40.00", Currency: "EUR"},
+	}); err != nil {
+		t.Fatalf("insert other occurrences: %v", err)
+	}
+	if _, err := repo.ImportOpenBankingTransactions(ctx, user.ID, accountID, []OpenBankingTransactionSeed{{
+		ExternalID: "reconciliation-water", Type: "expense", Category: "other", Description: "Sofiyska Voda",
+		Amount: "30.00", Currency: "EUR", OccurredAt: first, Metadata: []byte(`{}`),
+	}}, time.Date(2026, time.October, 20, 6, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("import water payment: %v", err)
+	}
+	waterOccurrences, err := repo.ListUnreconciledTransactionScheduleOccurrences(ctx, user.ID, first, first)
+	if err != nil {
+		t.Fatalf("list water occurrences: %v", err)
+	}
+	bank, err = repo.ListReconcilableBankTransactions(ctx, user.ID, first, first)
+	if err != nil || len(bank) != 1 {
+		t.Fatalf("water bank transactions = %#v, %v", bank, err)
+	}
+	waterPaid := false
+	for _, occurrence := range waterOccurrences {
+		if occurrence.ScheduleID == water.ID {
+			if err := repo.ReconcileTransactionScheduleOccurrence(ctx, user.ID, occurrence.ID, bank[0].ID); err != nil {
+				t.Fatalf("reconcile water occurrence: %v", err)
+			}
+			waterPaid = true
+		}
+	}
+	if !waterPaid {
+		t.Fatalf("water occurrences = %#v", waterOccurrences)
+	}
+	if err := repo.SetTransactionScheduleStatus(ctx, user.ID, water.ID, "paused"); err != nil {
+		t.Fatalf("pause schedule: %v", err)
+	}
+
+	if _, err := repo.QueueMissedTransactionScheduleOccurrences(
+		ctx, time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC), 5, 45, 500,
+	); err != nil {
+		t.Fatalf("queue missed occurrences: %v", err)
+	}
+	var missedKeys []string
+	rows, err := pool.Query(ctx, `SELECT event_key FROM notification_outbox
+		WHERE user_id=$1 AND event_type='scheduled_transaction_missed' ORDER BY event_key`, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			t.Fatal(err)
+		}
+		missedKeys = append(missedKeys, key)
+	}
+	rows.Close()
+	if want := fmt.Sprintf("schedule-occurrence:%d:missed", occurrences[1].ID); len(missedKeys) != 1 || missedKeys[0] != want {
+		t.Fatalf("missed payment notifications = %v, want %s", missedKeys, want)
+	}
+}
+
+func TestScheduleAmountModesIntegration(t *testing.T) {
+	ctx, repo, pool := openIntegrationRepository(t)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user, err := repo.RegisterUser(ctx, "schedule-amount-modes@example.com", "hash")
+	if err != nil {
+		t.Fatalf("register user: %v", err)
+	}
+	window := 2
+	schedule, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Electricity", Category: "other", Description: "EVN", Amount: "80.00",
+		AmountMode: "rolling_average", AverageWindow: &window, Currency: "EUR",
+		Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-08-01", Timezone: "Europe/Sofia",
+	})
+	if err != nil || schedule.AmountMode != "rolling_average" || schedule.AverageWindow == nil || *schedule.AverageWindow != 2 {
+		t.Fatalf("create rolling average schedule = %#v, %v", schedule, err)
+	}
+	if _, err := repo.CreateTransactionSchedule(ctx, user.ID, model.TransactionScheduleRequest{
+		Type: "expense", Name: "Water", Category: "other", Amount: "25.00", AmountMode: "range", AmountMin: "30.00",
+		AmountMax: "20.00", Currency: "EUR", Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-08-01",
+		Timezone: "Europe/Sofia",
+	}); err == nil {
+		t.Fatal("schedule with an inverted amount range was stored")
+	}
+
+	seeds := make([]ScheduleOccurrenceSeed, 0, 3)
+	for month := range 3 {
+		day := time.Date(2026, time.August+time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		seeds = append(seeds, ScheduleOccurrenceSeed{
+			ScheduleID: schedule.ID, UserID: user.ID, ScheduledFor: day, NominalDate: day, Type: "expense",
+			Name: "Electricity", Category: "other", Description: "EVN", Amount: "80.00", Currency: "EUR",
+			EstimatedAmount: "80.00",
+		})
+	}
+	if _, err := repo.UpsertTransactionScheduleOccurrences(ctx, seeds); err != nil {
+		t.Fatalf("insert occurrences: %v", err)
+	}
+	occurrences, err := repo.ListTransactionScheduleOccurrences(ctx, user.ID, ScheduleOccurrenceFilter{
+		From: seeds[0].ScheduledFor, Through: seeds[2].ScheduledFor, ScheduleID: schedule.ID, Status: "planned",
+	})
+	if err != nil || len(occurrences) != 3 || !occurrences[0].Estimated || occurrences[0].EstimatedAmount != "80.00" {
+		t.Fatalf("estimated occurrences = %#v, %v", occurrences, err)
+	}
+
+	for index, amount := range []string{"90.00", "101.00"} {
+		bank, err := repo.CreateTransaction(ctx, user.ID, model.TransactionRequest{
+			Type: "expense", Category: "other", Description: "EVN Bulgaria", Amount: amount, Currency: "EUR",
+			OccurredAt: seeds[index].ScheduledFor.AddDate(0, 0, 1).Format("2006-01-02"),
+		})
+		if err != nil {
+			t.Fatalf("create bank transaction: %v", err)
+		}
+		if _, err := pool.Exec(ctx, `UPDATE transactions SET source='import' WHERE id=$1`, bank.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.ReconcileTransactionScheduleOccurrence(ctx, user.ID, occurrences[index].ID, bank.ID); err != nil {
+			t.Fatalf("reconcile occurrence %d: %v", index, err)
+		}
+	}
+	paid, err := repo.GetTransactionScheduleOccurrence(ctx, user.ID, occurrences[1].ID)
+	if err != nil || paid.Amount != "101.00" || paid.EstimatedAmount != "90.00" || paid.Estimated {
+		t.Fatalf("reconciled occurrence = %#v, %v", paid, err)
+	}
+	planned, err := repo.GetTransactionScheduleOccurrence(ctx, user.ID, occurrences[2].ID)
+	if err != nil || planned.Amount != "95.50" || planned.EstimatedAmount != "95.50" || !planned.Estimated {
+		t.Fatalf("re-estimated occurrence = %#v, %v", planned, err)
+	}
+	stored, err := repo.GetTransactionSchedule(ctx, user.ID, schedule.ID, time.Now())
+	if err != nil || stored.Amount != "95.50" {
+		t.Fatalf("re-estimated schedule = %#v, %v", stored, err)
+	}
+
+	if _, err := repo.UpdateTransaction(ctx, user.ID, *paid.TransactionID, model.TransactionRequest{
+		Type: "expense", Category: "other", Description: "EVN Bulgaria", Amount: "99.00", Currency: "EUR",
+		OccurredAt: "2026-09-02",
+	}); err != nil {
+		t.Fatalf("update reconciled transaction: %v", err)
+	}
+	if paid, err = repo.GetTransactionScheduleOccurrence(ctx, user.ID, occurrences[1].ID); err != nil || paid.Amount != "99.00" {
+		t.Fatalf("occurrence after transaction edit = %#v, %v", paid, err)
+	}
+	// The edited payment moves the rolling average too.
+	planned, err = repo.GetTransactionScheduleOccurrence(ctx, user.ID, occurrences[2].ID)
+	if err != nil || planned.Amount != "94.50" || planned.EstimatedAmount != "94.50" {
+		t.Fatalf("occurrence after payment edit = %#v, %v", planned, err)
+	}
+	if stored, err = repo.GetTransactionSchedule(ctx, user.ID, schedule.ID, time.Now()); err != nil || stored.Amount != "94.50" {
+		t.Fatalf("schedule after payment edit = %#v, %v", stored, err)
+	}
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"money-manager-server/internal/model"
+)
+
+// savingsGoalEntries lists the contributions of goal g: manual entries, and
+// booked transactions since the start date that went through the linked
+// account or carry the goal's tag. Money arriving in the linked account and
+// tagged spending, such as a transfer out to savings, count toward the goal;
+// the opposite directions are withdrawals. A row matching both is counted
+// once, as an account entry.
+const savingsGoalEntries = `SELECT 'manual' AS source,c.id AS contribution_id,NULL::bigint AS transaction_id,
+		c.amount,c.contributed_on AS entry_date,c.note
+	FROM savings_goal_contributions c WHERE c.goal_id=g.id
+	UNION ALL
+	SELECT CASE WHEN linked.via_account THEN 'account' ELSE 'tag' END,NULL,t.id,
+		CASE WHEN linked.via_account=(t.type='income') THEN t.amount ELSE -t.amount END,
+		t.occurred_at,t.description
+	FROM transactions t
+	CROSS JOIN LATERAL (SELECT COALESCE(t.source='open_banking' AND t.source_account_id=g.account_id,false) AS via_account) linked
+	WHERE t.user_id=g.user_id AND t.status='booked' AND t.currency=g.currency AND t.occurred_at >= g.start_date
+		AND (linked.via_account OR (g.tag<>'' AND g.tag=ANY(t.tags)))`
+
+const savingsGoalSelect = `SELECT g.id,g.name,g.target_amount::text,g.currency,
+	to_char(g.start_date,'YYYY-MM-DD'),to_char(g.target_date,'YYYY-MM-DD'),g.account_id,g.tag,g.status,
+	COALESCE((SELECT sum(entries.amount) FROM (` + savingsGoalEntries + `) entries),0)::text,
+	to_char(g.created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(g.updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
+FROM savings_goals g
+WHERE g.user_id=$1`
+
+func (r *Repository) ListSavingsGoals(ctx context.Context, userID int, includeArchived bool) ([]model.SavingsGoal, error) {
+	query := savingsGoalSelect
+	if !includeArchived {
+		query += ` AND g.status='active'`
+	}
+	query += ` ORDER BY g.target_date,g.name,g.id`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.SavingsGoal, 0)
+	for rows.Next() {
+		item, err := scanSavingsGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetSavingsGoal(ctx context.Context, userID, goalID int) (model.SavingsGoal, error) {
+	item, err := scanSavingsGoal(r.db.QueryRow(ctx, savingsGoalSelect+` AND g.id=$2`, userID, goalID))
+	return item, mapNotFound(err)
+}
+
+func (r *Repository) CreateSavingsGoal(ctx context.Context, userID int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	var id int
+	if err := r.db.QueryRow(ctx, `INSERT INTO savings_goals(
+			user_id,name,target_amount,currency,start_date,target_date,account_id,tag
+		) VALUES($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`, userID, request.Name, request.TargetAmount,
+		request.Currency, request.StartDate, request.TargetDate, request.AccountID, request.Tag).Scan(&id); err != nil {
+		return model.SavingsGoal{}, err
+	}
+	return r.GetSavingsGoal(ctx, userID, id)
+}
+
+func (r *Repository) UpdateSavingsGoal(ctx context.Context, userID, goalID int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE savings_goals SET name=$1,target_amount=$2,currency=$3,start_date=$4,
+		target_date=$5,account_id=$6,tag=$7,updated_at=now()
+		WHERE id=$8 AND user_id=$9 AND status='active'`, request.Name, request.TargetAmount, request.Currency,
+		request.StartDate, request.TargetDate, request.AccountID, request.Tag, goalID, userID)
+	if err != nil {
+		return model.SavingsGoal{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.SavingsGoal{}, ErrNotFound
+	}
+	return r.GetSavingsGoal(ctx, userID, goalID)
+}
+
+func (r *Repository) ArchiveSavingsGoal(ctx context.Context, userID, goalID int) error {
+	tag, err := r.db.Exec(ctx, `UPDATE savings_goals SET status='archived',updated_at=now()
+		WHERE id=$1 AND user_id=$2 AND status='active'`, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) ListSavingsGoalContributions(ctx context.Context, userID, goalID int) ([]model.SavingsGoalContribution, error) {
+	rows, err := r.db.Query(ctx, `SELECT entries.source,entries.contribution_id,entries.transaction_id,
+			entries.amount::text,to_char(entries.entry_date,'YYYY-MM-DD'),entries.note
+		FROM savings_goals g CROSS JOIN LATERAL (`+savingsGoalEntries+`) entries
+		WHERE g.id=$1 AND g.user_id=$2
+		ORDER BY entries.entry_date DESC,entries.contribution_id DESC NULLS LAST,entries.transaction_id DESC`,
+		goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.SavingsGoalContribution, 0)
+	for rows.Next() {
+		var item model.SavingsGoalContribution
+		var contributionID, transactionID pgtype.Int8
+		if err := rows.Scan(&item.Source, &contributionID, &transactionID, &item.Amount, &item.Date, &item.Note); err != nil {
+			return nil, err
+		}
+		if contributionID.Valid {
+			item.ID = int(contributionID.Int64)
+		}
+		if transactionID.Valid {
+			value := int(transactionID.Int64)
+			item.TransactionID = &value
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CreateSavingsGoalContribution adds a manual contribution to an active
+// goal.
+func (r *Repository) CreateSavingsGoalContribution(
+	ctx context.Context,
+	userID, goalID int,
+	request model.SavingsGoalContributionRequest,
+) (model.SavingsGoalContribution, error) {
+	item := model.SavingsGoalContribution{Source: "manual"}
+	err := r.db.QueryRow(ctx, `INSERT INTO savings_goal_contributions(goal_id,user_id,amount,contributed_on,note)
+		SELECT id,user_id,$3,$4,$5 FROM savings_goals WHERE id=$1 AND user_id=$2 AND status='active'
+		RETURNING id,amount::text,to_char(contributed_on,'YYYY-MM-DD'),note`,
+		goalID, userID, request.Amount, request.Date, request.Note,
+	).Scan(&item.ID, &item.Amount, &item.Date, &item.Note)
+	return item, mapNotFound(err)
+}
+
+func (r *Repository) DeleteSavingsGoalContribution(ctx context.Context, userID, goalID, contributionID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM savings_goal_contributions c
+		USING savings_goals g
+		WHERE c.id=$1 AND c.goal_id=$2 AND c.user_id=$3 AND g.id=c.goal_id AND g.status='active'`,
+		contributionID, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// QueueSavingsGoalMilestones records every 25% step an active goal has
+// reached and queues one notification per goal for the highest new step,
+// so a large contribution does not send several at once. Notifications
+// follow the budget alert preference.
+func (r *Repository) QueueSavingsGoalMilestones(ctx context.Context) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	rows, err := tx.Query(ctx, `WITH goals AS (
+		SELECT g.id,g.user_id,g.target_amount,
+			COALESCE((SELECT sum(entries.amount) FROM (`+savingsGoalEntries+`) entries),0) AS saved
+		FROM savings_goals g WHERE g.status='active'
+	), candidates AS (
+		SELECT goals.id,goals.user_id,levels.milestone,goals.saved
+		FROM goals CROSS JOIN (VALUES (25),(50),(75),(100)) AS levels(milestone)
+		WHERE goals.saved >= goals.target_amount*levels.milestone/100.0
+	), inserted AS (
+		INSERT INTO savings_goal_milestones(goal_id,user_id,milestone,saved_amount)
+		SELECT id,user_id,milestone,saved FROM candidates
+		ON CONFLICT(goal_id,milestone) DO NOTHING
+		RETURNING goal_id,user_id,milestone,saved_amount
+	)
+	SELECT inserted.goal_id,inserted.user_id,max(inserted.milestone),max(inserted.saved_amount)::text,
+		g.name,g.target_amount::text,g.currency
+	FROM inserted JOIN savings_goals g ON g.id=inserted.goal_id
+	GROUP BY inserted.goal_id,inserted.user_id,g.name,g.target_amount,g.currency`)
+	if err != nil {
+		return 0, err
+	}
+	type milestone struct {
+		goalID, userID, level  int
+		saved, name            string
+		targetAmount, currency string
+	}
+	milestones := make([]milestone, 0)
+	for rows.Next() {
+		var item milestone
+		if err := rows.Scan(&item.goalID, &item.userID, &item.level, &item.saved,
+			&item.name, &item.targetAmount, &item.currency); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		milestones = append(milestones, item)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+	for _, item := range milestones {
+		title := "Savings goal is " + strconv.Itoa(item.level) + "% of the way"
+		if item.level == 100 {
+			title = "Savings goal reached"
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO notification_outbox(user_id,event_type,event_key,title,body,payload)
+			SELECT $1,'savings_goal_milestone',$2,$3,$4,jsonb_build_object(
+				'goal_id',$5::bigint,'milestone',$6::integer,'saved_amount',$7::numeric)
+			WHERE COALESCE((SELECT budget_alerts FROM notification_preferences WHERE user_id=$1),true)
+			ON CONFLICT(event_key) DO NOTHING`, item.userID,
+			"savings_goal:"+strconv.Itoa(item.goalID)+":"+strconv.Itoa(item.level), title,
+			item.name+" · "+item.saved+" of "+item.targetAmount+" "+item.currency,
+			item.goalID, item.level, item.saved)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(milestones), nil
+}
+
+func scanSavingsGoal(row rowScanner) (model.SavingsGoal, error) {
+	var item model.SavingsGoal
+	var accountID pgtype.Int8
+	err := row.Scan(&item.ID, &item.Name, &item.TargetAmount, &item.Currency, &item.StartDate, &item.TargetDate,
+		&accountID, &item.Tag, &item.Status, &item.SavedAmount, &item.CreatedAt, &item.UpdatedAt)
+	if accountID.Valid {
+		value := int(accountID.Int64)
+		item.AccountID = &value
+	}
+	return item, err
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"money-manager-server/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListUnreconciledTransactionScheduleOccurrences returns the occurrences
+// scheduled between from and through that no bank transaction has paid yet:
+// planned ones, and posted ones still backed by the transaction the schedule
+// booked itself.
+func (r *Repository) ListUnreconciledTransactionScheduleOccurrences(
+	ctx context.Context,
+	userID int,
+	from, through time.Time,
+) ([]model.TransactionScheduleOccurrence, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+transactionScheduleOccurrenceColumns+`
+		FROM transaction_schedule_occurrences occurrence
+		WHERE user_id=$1 AND scheduled_for >= $2 AND scheduled_for <= $3 AND reconciled_at IS NULL
+		  AND (status='planned' OR (status='posted' AND (transaction_id IS NULL OR EXISTS (
+			SELECT 1 FROM transactions WHERE transactions.id=occurrence.transaction_id
+			  AND transactions.source='schedule'))))
+		ORDER BY scheduled_for,id`, userID, from, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.TransactionScheduleOccurrence, 0)
+	for rows.Next() {
+		item, err := scanTransactionScheduleOccurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// ListReconcilableBankTransactions returns booked bank and statement rows
+// between from and through, inclusive, that are not linked to a schedule
+// occurrence.
+func (r *Repository) ListReconcilableBankTransactions(
+	ctx context.Context,
+	userID int,
+	from, through time.Time,
+) ([]model.Transaction, error) {
+	rows, err := r.db.Query(ctx, `SELECT id,type,category,description,amount::text,currency,
+		to_char(occurred_at,'YYYY-MM-DD'),source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id
+		FROM transactions
+		WHERE user_id=$1 AND occurred_at >= $2 AND occurred_at <= $3 AND status='booked'
+		  AND source IN ('open_banking','import') AND schedule_occurrence_id IS NULL
+		ORDER BY occurred_at,id`, userID, from, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.Transaction, 0)
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, transaction)
+	}
+	return out, rows.Err()
+}
+
+// ListTransactionScheduleReconciliationUsers returns the users with an active
+// schedule and an unlinked bank or statement row stored or changed since their
+// schedules were last reconciled.
+func (r *Repository) ListTransactionScheduleReconciliationUsers(ctx context.Context) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT schedule.user_id
+		FROM transaction_schedules schedule
+		LEFT JOIN transaction_schedule_reconciliations reconciliation ON reconciliation.user_id=schedule.user_id
+		WHERE schedule.status='active' AND EXISTS (
+			SELECT 1 FROM transactions
+			WHERE transactions.user_id=schedule.user_id AND transactions.status='booked'
+			  AND transactions.source IN ('open_banking','import') AND transactions.schedule_occurrence_id IS NULL
+			  AND (reconciliation.reconciled_at IS NULL OR transactions.updated_at > reconciliation.reconciled_at))
+		ORDER BY schedule.user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int, 0)
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		out = append(out, userID)
+	}
+	return out, rows.Err()
+}
+
+// MarkTransactionSchedulesReconciled records that the user's schedules were
+// reconciled with every bank row stored before at.
+func (r *Repository) MarkTransactionSchedulesReconciled(ctx context.Context, userID int, at time.Time) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO transaction_schedule_reconciliations(user_id,reconciled_at)
+		VALUES($1,$2)
+		ON CONFLICT(user_id) DO UPDATE
+		SET reconciled_at=greatest(transaction_schedule_reconciliations.reconciled_at,EXCLUDED.reconciled_at)`,
+		userID, at)
+	return err
+}
+
+// ReconcileTransactionScheduleOccurrence links an occurrence to the bank
+// transaction that paid it and takes over its amount. The transaction the
+// schedule posted for the occurrence, if any, is deleted so the payment is
+// counted once. A rolling-average schedule then re-estimates its amount and
+// its planned occurrences from the latest reconciled payments. It returns
+// ErrNotFound when the occurrence is already reconciled or skipped, or when
+// the bank transaction is gone or linked elsewhere.
+func (r *Repository) ReconcileTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID, transactionID int,
+) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var scheduleID int
+	if err := tx.QueryRow(ctx, `SELECT schedule_id FROM transaction_schedule_occurrences
+		WHERE id=$1 AND user_id=$2 AND reconciled_at IS NULL AND status IN ('planned','posted')
+		FOR UPDATE`, occurrenceID, userID).Scan(&scheduleID); err != nil {
+		return mapNotFound(err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM transactions
+		WHERE user_id=$1 AND schedule_occurrence_id=$2 AND source='schedule'`, userID, occurrenceID); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `UPDATE transactions SET schedule_occurrence_id=$1,updated_at=now()
+		WHERE id=$2 AND user_id=$3 AND status='booked' AND source IN ('open_banking','import')
+		  AND schedule_occurrence_id IS NULL`, occurrenceID, transactionID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `UPDATE transaction_schedule_occurrences
+		SET status='posted',transaction_id=$1,posted_at=COALESCE(posted_at,now()),reconciled_at=now(),
+			amount=(SELECT amount FROM transactions WHERE id=$1),updated_at=now()
+		WHERE id=$2`, transactionID, occurrenceID); err != nil {
+		return err
+	}
+	if err := refreshRollingAverageSchedule(ctx, tx, scheduleID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// refreshRollingAverageSchedule re-estimates a rolling-average schedule and
+// its planned occurrences from the latest reconciled payments. Schedules with
+// other amount modes are left alone.
+func refreshRollingAverageSchedule(ctx context.Context, tx pgx.Tx, scheduleID int) error {
+	if _, err := tx.Exec(ctx, `UPDATE transaction_schedules schedule
+		SET amount=recent.average,updated_at=now()
+		FROM (
+			SELECT round(avg(paid.amount),2) AS average
+			FROM (
+				SELECT occurrence.amount
+				FROM transaction_schedule_occurrences occurrence
+				WHERE occurrence.schedule_id=$1 AND occurrence.reconciled_at IS NOT NULL
+				ORDER BY occurrence.scheduled_for DESC,occurrence.id DESC
+				LIMIT (SELECT average_window FROM transaction_schedules WHERE id=$1)
+			) paid
+		) recent
+		WHERE schedule.id=$1 AND schedule.amount_mode='rolling_average' AND recent.average IS NOT NULL`,
+		scheduleID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `UPDATE transaction_schedule_occurrences occurrence
+		SET amount=schedule.amount,estimated_amount=schedule.amount,updated_at=now()
+		FROM transaction_schedules schedule
+		WHERE schedule.id=occurrence.schedule_id AND occurrence.schedule_id=$1
+		  AND schedule.amount_mode='rolling_average' AND occurrence.status='planned' AND NOT occurrence.overridden
+		  AND occurrence.reconciled_at IS NULL`, scheduleID)
+	return err
+}
+
+// QueueMissedTransactionScheduleOccurrences notifies users about occurrences
+// of active schedules that no bank transaction paid within graceDays of their
+// date. An occurrence counts only when its payments show up in the bank feed:
+// the schedule has been paid by a bank transaction before, and the user has a
+// bank account in its currency synced after the grace period. For cash and
+// other off-bank schedules a missing match says nothing about the payment.
+// Occurrences older than lookbackDays are left alone.
+func (r *Repository) QueueMissedTransactionScheduleOccurrences(
+	ctx context.Context,
+	now time.Time,
+	graceDays, lookbackDays, limit int,
+) (int, error) {
+	tag, err := r.db.Exec(ctx, `WITH missed AS (
+		SELECT occurrence.id,occurrence.user_id,occurrence.type,occurrence.name,
+			occurrence.amount::text,occurrence.currency,
+			to_char(occurrence.scheduled_for,'YYYY-MM-DD') AS scheduled_for
+		FROM transaction_schedule_occurrences occurrence
+		JOIN transaction_schedules schedule ON schedule.id=occurrence.schedule_id
+		WHERE occurrence.reconciled_at IS NULL AND schedule.status='active'
+		  AND (occurrence.status='planned' OR (occurrence.status='posted' AND EXISTS (
+			SELECT 1 FROM transactions WHERE transactions.id=occurrence.transaction_id
+			  AND transactions.source='schedule')))
+		  AND occurrence.scheduled_for < ($1 AT TIME ZONE schedule.timezone)::date - $2::int
+		  AND occurrence.scheduled_for >= ($1 AT TIME ZONE schedule.timezone)::date - $3::int
+		  AND EXISTS (
+			SELECT 1 FROM transaction_schedule_occurrences paid
+			WHERE paid.schedule_id=occurrence.schedule_id AND paid.reconciled_at IS NOT NULL)
+		  AND EXISTS (
+			SELECT 1 FROM open_banking_accounts account
+			JOIN open_banking_connections connection ON connection.id=account.connection_id
+			WHERE connection.user_id=occurrence.user_id AND account.currency=occurrence.currency
+			  AND (account.last_synced_at AT TIME ZONE schedule.timezone)::date > occurrence.scheduled_for + $2::int)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notification_outbox
+			WHERE event_key='schedule-occurrence:'||occurrence.id::text||':missed')
+		ORDER BY occurrence.scheduled_for,occurrence.id
+		LIMIT $4
+	)
+	INSERT INTO notification_outbox(user_id,event_type,event_key,title,body,payload)
+	SELECT missed.user_id,'scheduled_transaction_missed',
+		'schedule-occurrence:'||missed.id::text||':missed',
+		CASE WHEN missed.type='income' THEN 'Scheduled income not received' ELSE 'Scheduled payment missed' END,
+		missed.name||' · '||missed.amount||' '||missed.currency||' was due '||missed.scheduled_for,
+		jsonb_build_object('schedule_occurrence_id',missed.id,'type',missed.type,'scheduled_for',missed.scheduled_for)
+	FROM missed
+	WHERE COALESCE((SELECT scheduled_money FROM notification_preferences
+		WHERE user_id=missed.user_id),true)
+	ON CONFLICT(event_key) DO NOTHING`, now, graceDays, lookbackDays, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -17,6 +17,7 @@ type ScheduleOccurrenceSeed struct {
 	ScheduleID   int
 	UserID       int
 	ScheduledFor time.Time
+	NominalDate  time.Time
 	Type         string
 	Name         string
 	Category     string
@@ -24,6 +25,11 @@ type ScheduleOccurrenceSeed struct {
 	Amount       string
 	Currency     string
 	AutoPost     bool
+	// EstimatedAmount is set for schedules without a fixed amount, and the
+	// range for schedules with amount_mode range.
+	EstimatedAmount string
+	AmountMin       string
+	AmountMax       string
 }
 
 type ScheduleOccurrenceFilter struct {
@@ -34,9 +40,11 @@ type ScheduleOccurrenceFilter struct {
 }
 
 const transactionScheduleSelect = `SELECT
-	s.id,s.user_id,s.type,s.name,s.category,s.description,s.amount::text,s.currency,
+	s.id,s.user_id,s.type,s.name,s.category,s.description,s.amount::text,
+	s.amount_mode,COALESCE(s.amount_min::text,''),COALESCE(s.amount_max::text,''),s.average_window,s.currency,
 	s.frequency,s.frequency_interval,to_char(s.start_date,'YYYY-MM-DD'),
-	COALESCE(to_char(s.end_date,'YYYY-MM-DD'),''),s.day_of_week,s.day_of_month,
+	COALESCE(to_char(s.end_date,'YYYY-MM-DD'),''),s.day_of_week,s.day_of_month,s.rrule,
+	s.business_day_adjustment,s.holiday_calendar,
 	s.timezone,s.auto_post,s.status,COALESCE(to_char(s.materialized_through,'YYYY-MM-DD'),''),
 	COALESCE(to_char((
 		SELECT min(o.scheduled_for)
@@ -48,9 +56,10 @@ const transactionScheduleSelect = `SELECT
 	to_char(s.updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
 	FROM transaction_schedules s`
 
-const transactionScheduleReturning = `id,user_id,type,name,category,description,amount::text,currency,
+const transactionScheduleReturning = `id,user_id,type,name,category,description,amount::text,
+	amount_mode,COALESCE(amount_min::text,''),COALESCE(amount_max::text,''),average_window,currency,
 	frequency,frequency_interval,to_char(start_date,'YYYY-MM-DD'),
-	COALESCE(to_char(end_date,'YYYY-MM-DD'),''),day_of_week,day_of_month,
+	COALESCE(to_char(end_date,'YYYY-MM-DD'),''),day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,
 	timezone,auto_post,status,COALESCE(to_char(materialized_through,'YYYY-MM-DD'),''),
 	''::text,
 	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
@@ -63,12 +72,16 @@ func (r *Repository) CreateTransactionSchedule(
 ) (model.TransactionSchedule, error) {
 	row := r.db.QueryRow(ctx, `INSERT INTO transaction_schedules(
 		user_id,type,name,category,description,amount,currency,frequency,frequency_interval,
-		start_date,end_date,day_of_week,day_of_month,timezone,auto_post
-	) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULLIF($11,'')::date,$12,$13,$14,$15)
+		start_date,end_date,day_of_week,day_of_month,rrule,business_day_adjustment,holiday_calendar,timezone,auto_post,
+		amount_mode,amount_min,amount_max,average_window
+	) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULLIF($11,'')::date,$12,$13,$14,COALESCE(NULLIF($15,''),'none'),$16,$17,$18,
+		COALESCE(NULLIF($19,''),'fixed'),NULLIF($20,'')::numeric,NULLIF($21,'')::numeric,$22)
 	RETURNING `+transactionScheduleReturning,
 		userID, request.Type, request.Name, request.Category, request.Description, request.Amount,
 		request.Currency, request.Frequency, request.FrequencyInterval, request.StartDate, request.EndDate,
-		request.DayOfWeek, request.DayOfMonth, request.Timezone, request.AutoPost,
+		request.DayOfWeek, request.DayOfMonth, request.RRule, request.BusinessDayAdjustment,
+		request.HolidayCalendar, request.Timezone, request.AutoPost,
+		request.AmountMode, request.AmountMin, request.AmountMax, request.AverageWindow,
 	)
 	return scanTransactionSchedule(row)
 }
@@ -133,14 +146,17 @@ func (r *Repository) UpdateTransactionSchedule(
 	row := tx.QueryRow(ctx, `UPDATE transaction_schedules SET
 		type=$1,name=$2,category=$3,description=$4,amount=$5,currency=$6,
 		frequency=$7,frequency_interval=$8,start_date=$9,end_date=NULLIF($10,'')::date,
-		day_of_week=$11,day_of_month=$12,timezone=$13,auto_post=$14,
-		materialized_through=$15::date-1,updated_at=now()
-		WHERE id=$16 AND user_id=$17 AND status <> 'archived'
+		day_of_week=$11,day_of_month=$12,rrule=$13,business_day_adjustment=COALESCE(NULLIF($14,''),'none'),holiday_calendar=$15,
+		timezone=$16,auto_post=$17,materialized_through=$18::date-1,
+		amount_mode=COALESCE(NULLIF($21,''),'fixed'),amount_min=NULLIF($22,'')::numeric,amount_max=NULLIF($23,'')::numeric,
+		average_window=$24,updated_at=now()
+		WHERE id=$19 AND user_id=$20 AND status <> 'archived'
 		RETURNING `+transactionScheduleReturning,
 		request.Type, request.Name, request.Category, request.Description, request.Amount, request.Currency,
 		request.Frequency, request.FrequencyInterval, request.StartDate, request.EndDate,
-		request.DayOfWeek, request.DayOfMonth, request.Timezone, request.AutoPost,
-		today, scheduleID, userID,
+		request.DayOfWeek, request.DayOfMonth, request.RRule, request.BusinessDayAdjustment,
+		request.HolidayCalendar, request.Timezone, request.AutoPost, today, scheduleID, userID,
+		request.AmountMode, request.AmountMin, request.AmountMax, request.AverageWindow,
 	)
 	item, err := scanTransactionSchedule(row)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -150,7 +166,7 @@ func (r *Repository) UpdateTransactionSchedule(
 		return model.TransactionSchedule{}, err
 	}
 	if _, err := tx.Exec(ctx, `DELETE FROM transaction_schedule_occurrences
-		WHERE schedule_id=$1 AND status='planned' AND scheduled_for >= $2::date`, scheduleID, today); err != nil {
+		WHERE schedule_id=$1 AND status='planned' AND NOT overridden AND scheduled_for >= $2::date`, scheduleID, today); err != nil {
 		return model.TransactionSchedule{}, err
 	}
 	if err := tx.Commit(ctx); err != nil {
@@ -232,11 +248,13 @@ func (r *Repository) UpsertTransactionScheduleOccurrences(
 	inserted := 0
 	for _, seed := range seeds {
 		tag, err := tx.Exec(ctx, `INSERT INTO transaction_schedule_occurrences(
-			schedule_id,user_id,scheduled_for,type,name,category,description,amount,currency,auto_post
-		) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
-		ON CONFLICT(schedule_id,scheduled_for) DO NOTHING`,
-			seed.ScheduleID, seed.UserID, seed.ScheduledFor, seed.Type, seed.Name, seed.Category,
+			schedule_id,user_id,scheduled_for,nominal_date,type,name,category,description,amount,currency,auto_post,
+			estimated_amount,amount_min,amount_max
+		) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NULLIF($12,'')::numeric,NULLIF($13,'')::numeric,NULLIF($14,'')::numeric)
+		ON CONFLICT(schedule_id,nominal_date) DO NOTHING`,
+			seed.ScheduleID, seed.UserID, seed.ScheduledFor, seed.NominalDate, seed.Type, seed.Name, seed.Category,
 			seed.Description, seed.Amount, seed.Currency, seed.AutoPost,
+			seed.EstimatedAmount, seed.AmountMin, seed.AmountMax,
 		)
 		if err != nil {
 			return 0, err
@@ -266,13 +284,18 @@ func (r *Repository) MarkTransactionScheduleMaterializedThrough(
 	return nil
 }
 
+const transactionScheduleOccurrenceColumns = `id,schedule_id,to_char(scheduled_for,'YYYY-MM-DD'),
+	to_char(nominal_date,'YYYY-MM-DD'),status,type,name,category,description,amount::text,currency,
+	estimated_amount IS NOT NULL AND reconciled_at IS NULL,COALESCE(estimated_amount::text,''),
+	COALESCE(amount_min::text,''),COALESCE(amount_max::text,''),auto_post,overridden,transaction_id,
+	COALESCE(to_char(reconciled_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),'')`
+
 func (r *Repository) ListTransactionScheduleOccurrences(
 	ctx context.Context,
 	userID int,
 	filter ScheduleOccurrenceFilter,
 ) ([]model.TransactionScheduleOccurrence, error) {
-	query := `SELECT id,schedule_id,to_char(scheduled_for,'YYYY-MM-DD'),status,type,name,category,
-		description,amount::text,currency,auto_post,transaction_id
+	query := `SELECT ` + transactionScheduleOccurrenceColumns + `
 		FROM transaction_schedule_occurrences
 		WHERE user_id=$1 AND scheduled_for >= $2 AND scheduled_for <= $3`
 	args := []any{userID, filter.From, filter.Through}
@@ -293,32 +316,83 @@ func (r *Repository) ListTransactionScheduleOccurrences(
 	defer rows.Close()
 	out := make([]model.TransactionScheduleOccurrence, 0)
 	for rows.Next() {
-		var item model.TransactionScheduleOccurrence
-		var transactionID pgtype.Int4
-		if err := rows.Scan(
-			&item.ID, &item.ScheduleID, &item.ScheduledFor, &item.Status, &item.Type,
-			&item.Name, &item.Category, &item.Description, &item.Amount, &item.Currency,
-			&item.AutoPost, &transactionID,
-		); err != nil {
+		item, err := scanTransactionScheduleOccurrence(rows)
+		if err != nil {
 			return nil, err
 		}
-		if transactionID.Valid {
-			value := int(transactionID.Int32)
-			item.TransactionID = &value
-		}
 		out = append(out, item)
 	}
 	return out, rows.Err()
 }
 
+func (r *Repository) GetTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.TransactionScheduleOccurrence, error) {
+	item, err := scanTransactionScheduleOccurrence(r.db.QueryRow(ctx, `SELECT `+transactionScheduleOccurrenceColumns+`
+		FROM transaction_schedule_occurrences WHERE user_id=$1 AND id=$2`, userID, occurrenceID))
+	return item, mapNotFound(err)
+}
+
+// UpdateTransactionScheduleOccurrence overrides a planned occurrence. The
+// override flag keeps the row when its schedule is edited, and the nominal
+// date keeps re-materialization from adding the occurrence again.
+func (r *Repository) UpdateTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	request model.ScheduleOccurrenceRequest,
+) (model.TransactionScheduleOccurrence, error) {
+	item, err := scanTransactionScheduleOccurrence(r.db.QueryRow(ctx, `UPDATE transaction_schedule_occurrences
+		SET scheduled_for=$1,amount=$2,category=$3,description=$4,overridden=true,updated_at=now()
+		WHERE id=$5 AND user_id=$6 AND status='planned'
+		RETURNING `+transactionScheduleOccurrenceColumns,
+		request.ScheduledFor, request.Amount, request.Category, request.Description, occurrenceID, userID))
+	return item, mapNotFound(err)
+}
+
+// SetTransactionScheduleOccurrenceStatus moves an occurrence between planned
+// and skipped. It returns ErrNotFound when the occurrence is not in status
+// from.
+func (r *Repository) SetTransactionScheduleOccurrenceStatus(
+	ctx context.Context,
+	userID, occurrenceID int,
+	from, to string,
+) (model.TransactionScheduleOccurrence, error) {
+	item, err := scanTransactionScheduleOccurrence(r.db.QueryRow(ctx, `UPDATE transaction_schedule_occurrences
+		SET status=$1,updated_at=now()
+		WHERE id=$2 AND user_id=$3 AND status=$4
+		RETURNING `+transactionScheduleOccurrenceColumns, to, occurrenceID, userID, from))
+	return item, mapNotFound(err)
+}
+
+func scanTransactionScheduleOccurrence(row rowScanner) (model.TransactionScheduleOccurrence, error) {
+	var item model.TransactionScheduleOccurrence
+	var transactionID pgtype.Int4
+	if err := row.Scan(
+		&item.ID, &item.ScheduleID, &item.ScheduledFor, &item.NominalDate, &item.Status, &item.Type,
+		&item.Name, &item.Category, &item.Description, &item.Amount, &item.Currency,
+		&item.Estimated, &item.EstimatedAmount, &item.AmountMin, &item.AmountMax,
+		&item.AutoPost, &item.Overridden, &transactionID, &item.ReconciledAt,
+	); err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	if transactionID.Valid {
+		value := int(transactionID.Int32)
+		item.TransactionID = &value
+	}
+	return item, nil
+}
+
+// PostDueTransactionScheduleOccurrences books due auto-post occurrences and
+// returns the owner of each one it posted.
 func (r *Repository) PostDueTransactionScheduleOccurrences(
 	ctx context.Context,
 	now time.Time,
 	limit int,
-) (int, error) {
+) ([]int, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
@@ -332,7 +406,7 @@ func (r *Repository) PostDueTransactionScheduleOccurrences(
 		FOR UPDATE OF o SKIP LOCKED
 		LIMIT $2`, now, limit)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	type dueOccurrence struct {
 		ID, UserID                        int
@@ -347,13 +421,13 @@ func (r *Repository) PostDueTransactionScheduleOccurrences(
 			&item.Description, &item.Amount, &item.Currency, &item.ScheduledFor,
 		); err != nil {
 			rows.Close()
-			return 0, err
+			return nil, err
 		}
 		due = append(due, item)
 	}
 	if err := rows.Err(); err != nil {
 		rows.Close()
-		return 0, err
+		return nil, err
 	}
 	rows.Close()
 
@@ -372,12 +446,12 @@ func (r *Repository) PostDueTransactionScheduleOccurrences(
 			item.UserID, item.Type, item.Category, description, item.Amount, item.Currency,
 			item.ScheduledFor, item.ID,
 		).Scan(&transactionID); err != nil {
-			return 0, err
+			return nil, err
 		}
 		if _, err := tx.Exec(ctx, `UPDATE transaction_schedule_occurrences
 			SET status='posted',transaction_id=$1,posted_at=now(),updated_at=now()
 			WHERE id=$2`, transactionID, item.ID); err != nil {
-			return 0, err
+			return nil, err
 		}
 		title := "Scheduled income added"
 		if item.Type == "expense" {
@@ -390,7 +464,7 @@ func (r *Repository) PostDueTransactionScheduleOccurrences(
 			"scheduled_for":          item.ScheduledFor,
 		})
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		if _, err := tx.Exec(ctx, `INSERT INTO notification_outbox(
 			user_id,event_type,event_key,title,body,payload
@@ -403,13 +477,17 @@ func (r *Repository) PostDueTransactionScheduleOccurrences(
 			item.UserID, fmt.Sprintf("schedule-occurrence:%d:posted", item.ID), title,
 			fmt.Sprintf("%s · %s %s", item.Name, item.Amount, item.Currency), payload,
 		); err != nil {
-			return 0, err
+			return nil, err
 		}
 	}
 	if err := tx.Commit(ctx); err != nil {
-		return 0, err
+		return nil, err
 	}
-	return len(due), nil
+	users := make([]int, len(due))
+	for index, item := range due {
+		users[index] = item.UserID
+	}
+	return users, nil
 }
 
 func (r *Repository) QueueDueTransactionScheduleReminders(
@@ -446,11 +524,12 @@ func (r *Repository) QueueDueTransactionScheduleReminders(
 
 func scanTransactionSchedule(row rowScanner) (model.TransactionSchedule, error) {
 	var item model.TransactionSchedule
-	var dayOfWeek, dayOfMonth pgtype.Int2
+	var dayOfWeek, dayOfMonth, averageWindow pgtype.Int2
 	err := row.Scan(
 		&item.ID, &item.UserID, &item.Type, &item.Name, &item.Category, &item.Description, &item.Amount,
-		&item.Currency, &item.Frequency, &item.FrequencyInterval, &item.StartDate, &item.EndDate,
-		&dayOfWeek, &dayOfMonth, &item.Timezone, &item.AutoPost, &item.Status,
+		&item.AmountMode, &item.AmountMin, &item.AmountMax, &averageWindow, &item.Currency, &item.Frequency, &item.FrequencyInterval, &item.StartDate, &item.EndDate,
+		&dayOfWeek, &dayOfMonth, &item.RRule, &item.BusinessDayAdjustment, &item.HolidayCalendar,
+		&item.Timezone, &item.AutoPost, &item.Status,
 		&item.MaterializedThrough, &item.NextOccurrenceDate, &item.CreatedAt, &item.UpdatedAt,
 	)
 	if err != nil {
@@ -464,5 +543,9 @@ func scanTransactionSchedule(row rowScanner) (model.TransactionSchedule, error)
 		value := int(dayOfMonth.Int16)
 		item.DayOfMonth = &value
 	}
+	if averageWindow.Valid {
+		value := int(averageWindow.Int16)
+		item.AverageWindow = &value
+	}
 	return item, nil
 }
@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"money-manager-server/internal/model"
+)
+
+const expenseGroupSelect = `SELECT g.id,g.name,g.currency,g.status,
+	to_char(g.created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	to_char(g.updated_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
+FROM expense_groups g
+WHERE g.user_id=$1`
+
+func (r *Repository) ListExpenseGroups(ctx context.Context, userID int, includeArchived bool) ([]model.ExpenseGroup, error) {
+	query := expenseGroupSelect
+	if !includeArchived {
+		query += ` AND g.status='active'`
+	}
+	query += ` ORDER BY g.name,g.id`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.ExpenseGroup, 0)
+	for rows.Next() {
+		item, err := scanExpenseGroup(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	members, err := r.listExpenseGroupMembers(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for index := range items {
+		items[index].Members = append(items[index].Members, members[items[index].ID]...)
+	}
+	return items, nil
+}
+
+func (r *Repository) GetExpenseGroup(ctx context.Context, userID, groupID int) (model.ExpenseGroup, error) {
+	item, err := scanExpenseGroup(r.db.QueryRow(ctx, expenseGroupSelect+` AND g.id=$2`, userID, groupID))
+	if err != nil {
+		return model.ExpenseGroup{}, mapNotFound(err)
+	}
+	members, err := r.listExpenseGroupMembers(ctx, userID, &groupID)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	item.Members = append(item.Members, members[groupID]...)
+	return item, nil
+}
+
+// CreateExpenseGroup stores a group with its owner's member and the initial
+// participants. A participant whose email belongs to a registered user is
+// linked to that account.
+func (r *Repository) CreateExpenseGroup(ctx context.Context, userID int, request model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	var id int
+	if err := tx.QueryRow(ctx, `INSERT INTO expense_groups(user_id,name,currency) VALUES($1,$2,$3) RETURNING id`,
+		userID, request.Name, request.Currency).Scan(&id); err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO expense_group_members(group_id,name,member_user_id,is_self)
+		VALUES($1,$2,$3,true)`, id, request.SelfName, userID); err != nil {
+		return model.ExpenseGroup{}, mapConflict(err)
+	}
+	for _, member := range request.Members {
+		if _, err := tx.Exec(ctx, `INSERT INTO expense_group_members(group_id,name,email,member_user_id)
+			VALUES($1,$2,$3,(SELECT id FROM users WHERE $3<>'' AND email=$3))`, id, member.Name, member.Email); err != nil {
+			return model.ExpenseGroup{}, mapConflict(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	return r.GetExpenseGroup(ctx, userID, id)
+}
+
+func (r *Repository) UpdateExpenseGroup(ctx context.Context, userID, groupID int, request model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE expense_groups SET name=$1,updated_at=now()
+		WHERE id=$2 AND user_id=$3 AND status='active'`, request.Name, groupID, userID)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ExpenseGroup{}, ErrNotFound
+	}
+	return r.GetExpenseGroup(ctx, userID, groupID)
+}
+
+func (r *Repository) ArchiveExpenseGroup(ctx context.Context, userID, groupID int) error {
+	tag, err := r.db.Exec(ctx, `UPDATE expense_groups SET status='archived',updated_at=now()
+		WHERE id=$1 AND user_id=$2 AND status='active'`, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) AddExpenseGroupMember(
+	ctx context.Context,
+	userID, groupID int,
+	request model.ExpenseGroupMemberRequest,
+) (model.ExpenseGroupMember, error) {
+	var item model.ExpenseGroupMember
+	err := r.db.QueryRow(ctx, `INSERT INTO expense_group_members(group_id,name,email,member_user_id)
+		SELECT id,$3,$4,(SELECT id FROM users WHERE $4<>'' AND email=$4)
+		FROM expense_groups WHERE id=$1 AND user_id=$2 AND status='active'
+		RETURNING id,name,email,member_user_id IS NOT NULL,is_self`,
+		groupID, userID, request.Name, request.Email,
+	).Scan(&item.ID, &item.Name, &item.Email, &item.HasAccount, &item.IsSelf)
+	if err != nil {
+		return model.ExpenseGroupMember{}, mapConflict(mapNotFound(err))
+	}
+	return item, nil
+}
+
+// DeleteExpenseGroupMember removes a participant who has no expenses, shares,
+// or settlements. It returns ErrConflict for the owner's member and for
+// participants who are still referenced.
+func (r *Repository) DeleteExpenseGroupMember(ctx context.Context, userID, groupID, memberID int) error {
+	var deleted, exists bool
+	err := r.db.QueryRow(ctx, `WITH target AS (
+		SELECT m.id,m.is_self OR EXISTS(SELECT 1 FROM shared_expenses e WHERE e.paid_by_member_id=m.id)
+			OR EXISTS(SELECT 1 FROM shared_expense_shares s WHERE s.member_id=m.id)
+			OR EXISTS(SELECT 1 FROM expense_settlements s WHERE m.id IN (s.from_member_id,s.to_member_id)) AS referenced
+		FROM expense_group_members m JOIN expense_groups g ON g.id=m.group_id
+		WHERE m.id=$1 AND m.group_id=$2 AND g.user_id=$3 AND g.status='active'
+	), deleted AS (
+		DELETE FROM expense_group_members m USING target
+		WHERE m.id=target.id AND NOT target.referenced
+		RETURNING m.id
+	)
+	SELECT EXISTS(SELECT 1 FROM deleted),EXISTS(SELECT 1 FROM target)`, memberID, groupID, userID).Scan(&deleted, &exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	if !deleted {
+		return ErrConflict
+	}
+	return nil
+}
+
+// ListSharedExpenses returns the expenses of a group with their shares,
+// newest first.
+func (r *Repository) ListSharedExpenses(ctx context.Context, userID, groupID int) ([]model.SharedExpense, error) {
+	rows, err := r.db.Query(ctx, `SELECT e.id,e.description,e.amount::text,to_char(e.expense_date,'YYYY-MM-DD'),
+			e.paid_by_member_id,e.split_method,e.transaction_id,
+			to_char(e.created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
+		FROM shared_expenses e WHERE e.group_id=$1 AND e.user_id=$2
+		ORDER BY e.expense_date DESC,e.id DESC`, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.SharedExpense, 0)
+	positions := make(map[int]int)
+	for rows.Next() {
+		item, err := scanSharedExpense(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		positions[item.ID] = len(items)
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	shares, err := r.db.Query(ctx, `SELECT s.expense_id,s.member_id,s.amount::text,COALESCE(s.percentage::text,'')
+		FROM shared_expense_shares s JOIN shared_expenses e ON e.id=s.expense_id
+		WHERE e.group_id=$1 AND e.user_id=$2
+		ORDER BY s.expense_id,s.member_id`, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer shares.Close()
+	for shares.Next() {
+		var expenseID int
+		var share model.SharedExpenseShare
+		if err := shares.Scan(&expenseID, &share.MemberID, &share.Amount, &share.Percentage); err != nil {
+			return nil, err
+		}
+		if position, ok := positions[expenseID]; ok {
+			items[position].Shares = append(items[position].Shares, share)
+		}
+	}
+	return items, shares.Err()
+}
+
+// CreateSharedExpense stores an expense of an active group with its shares.
+// A transaction can back only one shared expense.
+func (r *Repository) CreateSharedExpense(
+	ctx context.Context,
+	userID, groupID int,
+	expense model.SharedExpense,
+) (model.SharedExpense, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.SharedExpense{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	item, err := scanSharedExpense(tx.QueryRow(ctx, `INSERT INTO shared_expenses(
+			group_id,user_id,description,amount,expense_date,paid_by_member_id,split_method,transaction_id
+		)
+		SELECT id,user_id,$3,$4,$5,$6,$7,$8 FROM expense_groups WHERE id=$1 AND user_id=$2 AND status='active'
+		RETURNING id,description,amount::text,to_char(expense_date,'YYYY-MM-DD'),paid_by_member_id,split_method,
+			transaction_id,to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
+		groupID, userID, expense.Description, expense.Amount, expense.Date, expense.PaidByMemberID,
+		expense.SplitMethod, expense.TransactionID))
+	if err != nil {
+		return model.SharedExpense{}, mapConflict(mapNotFound(err))
+	}
+	for _, share := range expense.Shares {
+		var percentage *string
+		if share.Percentage != "" {
+			percentage = &share.Percentage
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO shared_expense_shares(expense_id,member_id,amount,percentage)
+			VALUES($1,$2,$3,$4)`, item.ID, share.MemberID, share.Amount, percentage); err != nil {
+			return model.SharedExpense{}, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.SharedExpense{}, err
+	}
+	item.Shares = append(item.Shares, expense.Shares...)
+	return item, nil
+}
+
+func (r *Repository) DeleteSharedExpense(ctx context.Context, userID, groupID, expenseID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM shared_expenses e
+		USING expense_groups g
+		WHERE e.id=$1 AND e.group_id=$2 AND e.user_id=$3 AND g.id=e.group_id AND g.status='active'`,
+		expenseID, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const expenseSettlementColumns = `id,from_member_id,to_member_id,amount::text,to_char(settled_on,'YYYY-MM-DD'),
+	transaction_id,note,to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// ListExpenseSettlements returns the settlements of a group, newest first.
+func (r *Repository) ListExpenseSettlements(ctx context.Context, userID, groupID int) ([]model.ExpenseSettlement, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+expenseSettlementColumns+`
+		FROM expense_settlements WHERE group_id=$1 AND user_id=$2
+		ORDER BY settled_on DESC,id DESC`, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.ExpenseSettlement, 0)
+	for rows.Next() {
+		item, err := scanExpenseSettlement(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CreateExpenseSettlement records a transfer in an active group. A
+// transaction can back only one settlement.
+func (r *Repository) CreateExpenseSettlement(
+	ctx context.Context,
+	userID, groupID int,
+	request model.ExpenseSettlementRequest,
+) (model.ExpenseSettlement, error) {
+	item, err := scanExpenseSettlement(r.db.QueryRow(ctx, `INSERT INTO expense_settlements(
+			group_id,user_id,from_member_id,to_member_id,amount,settled_on,transaction_id,note
+		)
+		SELECT id,user_id,$3,$4,$5,$6,$7,$8 FROM expense_groups WHERE id=$1 AND user_id=$2 AND status='active'
+		RETURNING `+expenseSettlementColumns, groupID, userID, request.FromMemberID, request.ToMemberID,
+		request.Amount, request.Date, request.TransactionID, request.Note))
+	if err != nil {
+		return model.ExpenseSettlement{}, mapConflict(mapNotFound(err))
+	}
+	return item, nil
+}
+
+func (r *Repository) DeleteExpenseSettlement(ctx context.Context, userID, groupID, settlementID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM expense_settlements s
+		USING expense_groups g
+		WHERE s.id=$1 AND s.group_id=$2 AND s.user_id=$3 AND g.id=s.group_id AND g.status='active'`,
+		settlementID, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// listExpenseGroupMembers returns the members of the user's groups, or of
+// one group, keyed by group. The owner's member comes first.
+func (r *Repository) listExpenseGroupMembers(ctx context.Context, userID int, groupID *int) (map[int][]model.ExpenseGroupMember, error) {
+	rows, err := r.db.Query(ctx, `SELECT m.group_id,m.id,m.name,m.email,m.member_user_id IS NOT NULL,m.is_self
+		FROM expense_group_members m JOIN expense_groups g ON g.id=m.group_id
+		WHERE g.user_id=$1 AND ($2::bigint IS NULL OR g.id=$2)
+		ORDER BY m.group_id,m.is_self DESC,m.id`, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	members := make(map[int][]model.ExpenseGroupMember)
+	for rows.Next() {
+		var group int
+		var item model.ExpenseGroupMember
+		if err := rows.Scan(&group, &item.ID, &item.Name, &item.Email, &item.HasAccount, &item.IsSelf); err != nil {
+			return nil, err
+		}
+		members[group] = append(members[group], item)
+	}
+	return members, rows.Err()
+}
+
+func scanExpenseGroup(row rowScanner) (model.ExpenseGroup, error) {
+	item := model.ExpenseGroup{Members: make([]model.ExpenseGroupMember, 0)}
+	err := row.Scan(&item.ID, &item.Name, &item.Currency, &item.Status, &item.CreatedAt, &item.UpdatedAt)
+	return item, err
+}
+
+func scanSharedExpense(row rowScanner) (model.SharedExpense, error) {
+	item := model.SharedExpense{Shares: make([]model.SharedExpenseShare, 0)}
+	var transactionID pgtype.Int8
+	err := row.Scan(&item.ID, &item.Description, &item.Amount, &item.Date, &item.PaidByMemberID,
+		&item.SplitMethod, &transactionID, &item.CreatedAt)
+	if transactionID.Valid {
+		value := int(transactionID.Int64)
+		item.TransactionID = &value
+	}
+	return item, err
+}
+
+func scanExpenseSettlement(row rowScanner) (model.ExpenseSettlement, error) {
+	var item model.ExpenseSettlement
+	var transactionID pgtype.Int8
+	err := row.Scan(&item.ID, &item.FromMemberID, &item.ToMemberID, &item.Amount, &item.Date,
+		&transactionID, &item.Note, &item.CreatedAt)
+	if transactionID.Valid {
+		value := int(transactionID.Int64)
+		item.TransactionID = &value
+	}
+	return item, err
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"money-manager-server/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MaximumTransactionTags is how many tags a transaction can carry. It mirrors
+// the transactions_tags_check constraint.
+const MaximumTransactionTags = 20
+
+// BulkTransactionOperation is one validated bulk action. Exactly one of IDs
+// or Filter selects the rows; Limit bounds filter selections so a broad
+// filter cannot rewrite an unbounded part of the ledger.
+type BulkTransactionOperation struct {
+	Action             string
+	IDs                []int
+	Filter             *TransactionFilter
+	Limit              int
+	Type               string
+	Category           string
+	ExcludedFromBudget bool
+	Tags               []string
+}
+
+// BulkTransactions applies one action to every selected transaction inside a
+// single database transaction and reports the outcome for each requested row.
+func (r *Repository) BulkTransactions(ctx context.Context, userID int, operation BulkTransactionOperation) ([]model.BulkTransactionItemResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	matched, err := selectBulkTransactionIDs(ctx, tx, userID, operation)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[int]bool{}
+	if len(matched) > 0 {
+		changed, err = applyBulkTransactionAction(ctx, tx, userID, matched, operation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	changedStatus := "updated"
+	if operation.Action == "delete" {
+		changedStatus = "deleted"
+	}
+	found := make(map[int]bool, len(matched))
+	for _, id := range matched {
+		found[id] = true
+	}
+	order := matched
+	if operation.Filter == nil {
+		order = operation.IDs
+	}
+	items := make([]model.BulkTransactionItemResult, 0, len(order))
+	for _, id := range order {
+		status := "unchanged"
+		switch {
+		case !found[id]:
+			status = "not_found"
+		case changed[id]:
+			status = changedStatus
+		}
+		items = append(items, model.BulkTransactionItemResult{ID: id, Status: status})
+	}
+	return items, nil
+}
+
+func selectBulkTransactionIDs(ctx context.Context, tx pgx.Tx, userID int, operation BulkTransactionOperation) ([]int, error) {
+	var rows pgx.Rows
+	var err error
+	if operation.Filter != nil {
+		clause, args := transactionFilterClause(userID, *operation.Filter)
+		args = append(args, operation.Limit+1)
+		rows, err = tx.Query(ctx, `SELECT id FROM transactions WHERE user_id=$1`+clause+
+			fmt.Sprintf(" ORDER BY occurred_at DESC,id DESC LIMIT $%d FOR UPDATE", len(args)), args...)
+	} else {
+		rows, err = tx.Query(ctx, `SELECT id FROM transactions
+			WHERE user_id=$1 AND id=ANY($2) AND status='booked'
+			ORDER BY id FOR UPDATE`, userID, operation.IDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if operation.Filter != nil && len(ids) > operation.Limit {
+		return nil, ErrLimitExceeded
+	}
+	return ids, nil
+}
+
+func applyBulkTransactionAction(ctx context.Context, tx pgx.Tx, userID int, ids []int, operation BulkTransactionOperation) (map[int]bool, error) {
+	var rows pgx.Rows
+	var err error
+	switch operation.Action {
+	case "recategorize":
+		// Mirrors UpdateTransaction so bank sync keeps the user's choice.
+		rows, err = tx.Query(ctx, `UPDATE transactions
+			SET source_metadata=CASE
+					WHEN source='open_banking'
+					THEN source_metadata || jsonb_strip_nulls(jsonb_build_object(
+						'classification_override',true,
+						'type_override',CASE WHEN type IS DISTINCT FROM $3 THEN true END,
+						'category_override',CASE WHEN category IS DISTINCT FROM $4 THEN true END,
+						'category_source','user_override'
+					))
+					ELSE source_metadata
+				END,
+				type=$3,category=$4,category_learned_at=now(),updated_at=now()
+			WHERE user_id=$1 AND id=ANY($2)
+				AND (type IS DISTINCT FROM $3 OR category IS DISTINCT FROM $4)
+			RETURNING id`, userID, ids, operation.Type, operation.Category)
+	case "set_excluded_from_budget":
+		rows, err = tx.Query(ctx, `UPDATE transactions
+			SET excluded_from_budget=$3,updated_at=now()
+			WHERE user_id=$1 AND id=ANY($2) AND excluded_from_budget<>$3
+			RETURNING id`, userID, ids, operation.ExcludedFromBudget)
+	case "tag":
+		var overflow bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(
+			SELECT 1 FROM transactions
+			WHERE user_id=$1 AND id=ANY($2)
+				AND cardinality(ARRAY(SELECT DISTINCT tag FROM unnest(tags || $3::text[]) AS tag)) > $4
+		)`, userID, ids, operation.Tags, MaximumTransactionTags).Scan(&overflow); err != nil {
+			return nil, err
+		}
+		if overflow {
+			return nil, ErrConflict
+		}
+		rows, err = tx.Query(ctx, `UPDATE transactions
+			SET tags=ARRAY(
+					SELECT DISTINCT tag FROM unnest(tags || $3::text[]) AS tag ORDER BY tag
+				),
+				updated_at=now()
+			WHERE user_id=$1 AND id=ANY($2) AND NOT tags @> $3::text[]
+			RETURNING id`, userID, ids, operation.Tags)
+	case "delete":
+		rows, err = tx.Query(ctx, `WITH deleted AS (
+			DELETE FROM transactions
+			WHERE user_id=$1 AND id=ANY($2)
+			RETURNING id,user_id,source,source_account_id,external_id
+		), suppressed AS (
+			INSERT INTO open_banking_transaction_suppressions(user_id,source_account_id,external_id)
+			SELECT user_id,source_account_id,external_id
+			FROM deleted
+			WHERE source='open_banking' AND source_account_id IS NOT NULL AND external_id IS NOT NULL
+			ON CONFLICT(user_id,external_id)
+			DO UPDATE SET source_account_id=EXCLUDED.source_account_id,deleted_at=now()
+		)
+		SELECT id FROM deleted`, userID, ids)
+	default:
+		return nil, fmt.Errorf("unsupported bulk transaction action %q", operation.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+	changedIDs, err := scanIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[int]bool, len(changedIDs))
+	for _, id := range changedIDs {
+		changed[id] = true
+	}
+	return changed, nil
+}
+
+func scanIDs(rows pgx.Rows) ([]int, error) {
+	defer rows.Close()
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
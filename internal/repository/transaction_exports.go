@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"money-manager-server/internal/model"
+)
+
+// transactionExportFetchSize bounds how many rows a streaming export holds
+// in memory at once.
+const transactionExportFetchSize = 500
+
+// StreamTransactions reads booked transactions in date order through a
+// server-side cursor and hands them to visit one at a time. The cursor runs in
+// a read-only transaction, so the export sees one consistent snapshot.
+func (r *Repository) StreamTransactions(
+	ctx context.Context,
+	userID int,
+	from, toExclusive time.Time,
+	visit func(model.Transaction) error,
+) error {
+	return r.StreamTransactionsWithOrigin(ctx, userID, from, toExclusive, func(transaction model.Transaction, _ string) error {
+		return visit(transaction)
+	})
+}
+
+// StreamTransactionsWithOrigin is StreamTransactions with the origin label of
+// each row: manual, schedule, import:<format>, or open_banking:<account id>.
+func (r *Repository) StreamTransactionsWithOrigin(
+	ctx context.Context,
+	userID int,
+	from, toExclusive time.Time,
+	visit func(model.Transaction, string) error,
+) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `DECLARE transaction_export NO SCROLL CURSOR FOR
+		SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
+			source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id,`+transactionOrigin+`
+		FROM transactions
+		WHERE user_id=$1 AND occurred_at >= $2 AND occurred_at < $3 AND status='booked'
+		ORDER BY occurred_at ASC,id ASC`, userID, from, toExclusive); err != nil {
+		return err
+	}
+	for {
+		rows, err := tx.Query(ctx, `FETCH FORWARD `+strconv.Itoa(transactionExportFetchSize)+` FROM transaction_export`)
+		if err != nil {
+			return err
+		}
+		fetched := 0
+		for rows.Next() {
+			var origin string
+			transaction, err := scanTransaction(originScanner{row: rows, origin: &origin})
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			fetched++
+			if err := visit(transaction, origin); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if fetched < transactionExportFetchSize {
+			break
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// TransactionAccountUse is a distinct type, category, and origin of booked
+// transactions in a range, enough to declare every journal account up front.
+type TransactionAccountUse struct {
+	Type     string
+	Category string
+	Origin   string
+}
+
+func (r *Repository) ListTransactionAccountUses(ctx context.Context, userID int, from, toExclusive time.Time) ([]TransactionAccountUse, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT type,category,`+transactionOrigin+`
+		FROM transactions
+		WHERE user_id=$1 AND occurred_at >= $2 AND occurred_at < $3 AND status='booked'
+		ORDER BY 1,2,3`, userID, from, toExclusive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]TransactionAccountUse, 0)
+	for rows.Next() {
+		var item TransactionAccountUse
+		if err := rows.Scan(&item.Type, &item.Category, &item.Origin); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// TransactionExportJob is a claimed background export.
+type TransactionExportJob struct {
+	ID       int
+	UserID   int
+	Format   string
+	From     time.Time
+	To       time.Time
+	Attempts int
+}
+
+const transactionExportColumns = `id,format,to_char(from_date,'YYYY-MM-DD'),to_char(to_date,'YYYY-MM-DD'),
+	status,row_count,byte_size,COALESCE(error,''),
+	to_char(created_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+	COALESCE(to_char(completed_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"'),''),
+	to_char(expires_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// CreateTransactionExport queues a background export unless the user already
+// has maximumActive exports waiting or running.
+func (r *Repository) CreateTransactionExport(
+	ctx context.Context,
+	userID int,
+	request model.TransactionExportRequest,
+	expiresAt time.Time,
+	maximumActive int,
+) (model.TransactionExport, error) {
+	item, err := scanTransactionExport(r.db.QueryRow(ctx, `INSERT INTO transaction_exports(user_id,format,from_date,to_date,expires_at)
+		SELECT $1,$2,$3,$4,$5
+		WHERE (SELECT count(*) FROM transaction_exports
+			WHERE user_id=$1 AND status IN ('pending','running')) < $6
+		RETURNING `+transactionExportColumns,
+		userID, request.Format, request.From, request.To, expiresAt, maximumActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.TransactionExport{}, ErrLimitExceeded
+	}
+	return item, err
+}
+
+func (r *Repository) ListTransactionExports(ctx context.Context, userID int) ([]model.TransactionExport, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+transactionExportColumns+`
+		FROM transaction_exports WHERE user_id=$1 AND expires_at>now()
+		ORDER BY created_at DESC,id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.TransactionExport, 0)
+	for rows.Next() {
+		item, err := scanTransactionExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetTransactionExport(ctx context.Context, userID, exportID int) (model.TransactionExport, error) {
+	item, err := scanTransactionExport(r.db.QueryRow(ctx, `SELECT `+transactionExportColumns+`
+		FROM transaction_exports WHERE id=$1 AND user_id=$2 AND expires_at>now()`, exportID, userID))
+	return item, mapNotFound(err)
+}
+
+func (r *Repository) DeleteTransactionExport(ctx context.Context, userID, exportID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM transaction_exports WHERE id=$1 AND user_id=$2`, exportID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// StreamTransactionExportFile hands the stored chunks of a completed export
+// to visit in order.
+func (r *Repository) StreamTransactionExportFile(ctx context.Context, exportID int, visit func([]byte) error) error {
+	rows, err := r.db.Query(ctx, `SELECT data FROM transaction_export_chunks
+		WHERE export_id=$1 ORDER BY sequence`, exportID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := visit(data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ClaimTransactionExports marks queued exports, and running ones whose claim
+// lapsed, as running until claimUntil. Chunks left by an interrupted attempt
+// are discarded. A lapsed export that already used maximumAttempts is marked
+// failed instead of being claimed again.
+func (r *Repository) ClaimTransactionExports(
+	ctx context.Context,
+	now, claimUntil time.Time,
+	limit, maximumAttempts int,
+) ([]TransactionExportJob, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `WITH exhausted AS (
+		UPDATE transaction_exports SET status='failed',error='export took too long',claimed_until=NULL
+		WHERE status='running' AND claimed_until <= $1 AND attempts >= $2
+		RETURNING id
+	)
+	DELETE FROM transaction_export_chunks WHERE export_id IN (SELECT id FROM exhausted)`, now, maximumAttempts); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, `WITH due AS (
+		SELECT id FROM transaction_exports
+		WHERE expires_at > $1 AND attempts < $4
+		  AND (status='pending' OR (status='running' AND claimed_until <= $1))
+		ORDER BY created_at,id
+		FOR UPDATE SKIP LOCKED
+		LIMIT $3
+	)
+	UPDATE transaction_exports export
+	SET status='running',claimed_until=$2,attempts=export.attempts+1
+	FROM due
+	WHERE export.id=due.id
+	RETURNING export.id,export.user_id,export.format,export.from_date,export.to_date,export.attempts`,
+		now, claimUntil, limit, maximumAttempts)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]TransactionExportJob, 0)
+	ids := make([]int, 0)
+	for rows.Next() {
+		var job TransactionExportJob
+		if err := rows.Scan(&job.ID, &job.UserID, &job.Format, &job.From, &job.To, &job.Attempts); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		jobs = append(jobs, job)
+		ids = append(ids, job.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM transaction_export_chunks WHERE export_id=ANY($1)`, ids); err != nil {
+		return nil, err
+	}
+	return jobs, tx.Commit(ctx)
+}
+
+// AppendTransactionExportChunk stores the next piece of a running export and
+// extends its claim.
+func (r *Repository) AppendTransactionExportChunk(ctx context.Context, exportID, sequence int, data []byte, claimUntil time.Time) error {
+	_, err := r.db.Exec(ctx, `WITH claim AS (
+		UPDATE transaction_exports SET claimed_until=$4 WHERE id=$1 AND status='running'
+		RETURNING id
+	)
+	INSERT INTO transaction_export_chunks(export_id,sequence,data)
+	SELECT id,$2,$3 FROM claim`, exportID, sequence, data, claimUntil)
+	return err
+}
+
+func (r *Repository) CompleteTransactionExport(ctx context.Context, exportID, rowCount int, byteSize int64, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE transaction_exports
+		SET status='completed',row_count=$2,byte_size=$3,error=NULL,claimed_until=NULL,
+			completed_at=now(),expires_at=$4
+		WHERE id=$1 AND status='running'`, exportID, rowCount, byteSize, expiresAt)
+	return err
+}
+
+// ReleaseTransactionExport returns a claimed export that was never started to
+// the queue without counting the attempt.
+func (r *Repository) ReleaseTransactionExport(ctx context.Context, exportID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE transaction_exports
+		SET status='pending',claimed_until=NULL,attempts=greatest(attempts-1,0)
+		WHERE id=$1 AND status='running'`, exportID)
+	return err
+}
+
+// FailTransactionExport records an error. A retryable failure returns the job
+// to the queue; a final one marks it failed and drops its partial file.
+func (r *Repository) FailTransactionExport(ctx context.Context, exportID int, message string, final bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `UPDATE transaction_exports
+		SET status=CASE WHEN $3 THEN 'failed' ELSE 'pending' END,error=$2,claimed_until=NULL
+		WHERE id=$1 AND status='running'`, exportID, message, final); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM transaction_export_chunks WHERE export_id=$1`, exportID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) DeleteExpiredTransactionExports(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM transaction_exports WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func scanTransactionExport(row rowScanner) (model.TransactionExport, error) {
+	var item model.TransactionExport
+	err := row.Scan(
+		&item.ID, &item.Format, &item.From, &item.To, &item.Status, &item.Rows, &item.Bytes, &item.Error,
+		&item.CreatedAt, &item.CompletedAt, &item.ExpiresAt,
+	)
+	return item, err
+}
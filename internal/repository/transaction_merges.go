@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"money-manager-server/internal/model"
+)
+
+// queryer is satisfied by both the pool and a transaction.
+type queryer interface {
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, arguments ...any) pgx.Row
+}
+
+// transactionOrigin labels the source that recorded a row. Two rows with the
+// same origin are never cross-source duplicates.
+const transactionOrigin = `CASE source
+	WHEN 'import' THEN 'import:' || COALESCE(import_source,'file')
+	WHEN 'open_banking' THEN 'open_banking:' || COALESCE(source_account_id::text,'unlinked')
+	ELSE source END`
+
+// ListDuplicateCandidates returns pairs of booked rows from different origins
+// with the same type, amount, and currency, dated at most three days apart.
+// Scheduled postings are reconciled separately and are left out.
+func (r *Repository) ListDuplicateCandidates(ctx context.Context, userID int, from, to time.Time, limit int) ([]model.DuplicateTransactionPair, error) {
+	rows, err := r.db.Query(ctx, `WITH candidates AS (
+		SELECT id,type,amount,currency,occurred_at,`+transactionOrigin+` AS origin
+		FROM transactions
+		WHERE user_id=$1 AND status='booked' AND source<>'schedule'
+			AND occurred_at >= $2::date - 3 AND occurred_at < $3::date + 3
+	)
+	SELECT first.id,second.id
+	FROM candidates first
+	JOIN candidates second ON second.id>first.id
+		AND second.type=first.type AND second.amount=first.amount AND second.currency=first.currency
+		AND second.occurred_at BETWEEN first.occurred_at - 3 AND first.occurred_at + 3
+		AND second.origin<>first.origin
+	WHERE first.occurred_at >= $2 AND first.occurred_at < $3
+	ORDER BY first.occurred_at DESC,first.id,second.id
+	LIMIT $4`, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var pairs [][2]int
+	ids := make([]int, 0)
+	for rows.Next() {
+		var pair [2]int
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+		ids = append(ids, pair[0], pair[1])
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	items := make([]model.DuplicateTransactionPair, 0, len(pairs))
+	if len(pairs) == 0 {
+		return items, nil
+	}
+
+	transactionRows, err := r.db.Query(ctx, `SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
+		source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id,`+transactionOrigin+`
+		FROM transactions WHERE user_id=$1 AND id=ANY($2)`, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer transactionRows.Close()
+	transactions := make(map[int]model.Transaction, len(ids))
+	origins := make(map[int]string, len(ids))
+	for transactionRows.Next() {
+		var origin string
+		transaction, err := scanTransaction(originScanner{row: transactionRows, origin: &origin})
+		if err != nil {
+			return nil, err
+		}
+		transactions[transaction.ID], origins[transaction.ID] = transaction, origin
+	}
+	if err := transactionRows.Err(); err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		items = append(items, model.DuplicateTransactionPair{
+			Transactions: []model.Transaction{transactions[pair[0]], transactions[pair[1]]},
+			Origins:      []string{origins[pair[0]], origins[pair[1]]},
+		})
+	}
+	return items, nil
+}
+
+// originScanner appends the origin column to a transaction scan.
+type originScanner struct {
+	row    rowScanner
+	origin *string
+}
+
+func (s originScanner) Scan(destinations ...any) error {
+	return s.row.Scan(append(destinations, s.origin)...)
+}
+
+// MergeTransactions folds mergeID into keepID. The merged row is deleted and
+// recorded as provenance of the kept row; an open-banking row is suppressed
+// like a user deletion and an imported row's fingerprint is retired, so
+// neither source can bring the duplicate back. Tags are combined and a kept
+// row still in "other" takes the merged row's category.
+func (r *Repository) MergeTransactions(ctx context.Context, userID, keepID, mergeID int) (model.TransactionMergeResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	type mergeRow struct {
+		transactionType, currency, source, category string
+		tags                                        []string
+		merchantID                                  *int64
+	}
+	locked := make(map[int]mergeRow, 2)
+	rows, err := tx.Query(ctx, `SELECT id,type,currency,source,category,tags,merchant_id
+		FROM transactions WHERE user_id=$1 AND id=ANY($2) ORDER BY id FOR UPDATE`, userID, []int{keepID, mergeID})
+	if err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	for rows.Next() {
+		var id int
+		var row mergeRow
+		if err := rows.Scan(&id, &row.transactionType, &row.currency, &row.source, &row.category, &row.tags, &row.merchantID); err != nil {
+			rows.Close()
+			return model.TransactionMergeResult{}, err
+		}
+		locked[id] = row
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	keep, keepFound := locked[keepID]
+	merged, mergeFound := locked[mergeID]
+	if !keepFound || !mergeFound {
+		return model.TransactionMergeResult{}, ErrNotFound
+	}
+	if keep.transactionType != merged.transactionType || keep.currency != merged.currency ||
+		keep.source == "schedule" || merged.source == "schedule" {
+		return model.TransactionMergeResult{}, ErrConflict
+	}
+	tags := append(slices.Clone(keep.tags), merged.tags...)
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+	if len(tags) > MaximumTransactionTags {
+		return model.TransactionMergeResult{}, ErrConflict
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE transaction_merges SET transaction_id=$1
+		WHERE transaction_id=$2 AND user_id=$3`, keepID, mergeID, userID); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	if _, err := tx.Exec(ctx, `WITH merged AS (
+		DELETE FROM transactions
+		WHERE id=$1 AND user_id=$2
+		RETURNING user_id,source,source_account_id,external_id,import_source,import_fingerprint,description,amount,occurred_at
+	), suppressed AS (
+		INSERT INTO open_banking_transaction_suppressions(user_id,source_account_id,external_id)
+		SELECT user_id,source_account_id,external_id
+		FROM merged
+		WHERE source='open_banking' AND source_account_id IS NOT NULL AND external_id IS NOT NULL
+		ON CONFLICT(user_id,external_id)
+		DO UPDATE SET source_account_id=EXCLUDED.source_account_id,deleted_at=now()
+	)
+	INSERT INTO transaction_merges(
+		user_id,transaction_id,source,source_account_id,external_id,import_source,import_fingerprint,
+		description,amount,occurred_at
+	)
+	SELECT user_id,$3::int,source,source_account_id,external_id,import_source,import_fingerprint,description,amount,occurred_at
+	FROM merged`, mergeID, userID, keepID); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	transaction, err := scanTransaction(tx.QueryRow(ctx, `UPDATE transactions
+		SET source_metadata=CASE
+				WHEN source='open_banking' AND lower(category)='other' AND lower($1)<>'other'
+				THEN source_metadata || '{"classification_override":true,"category_override":true,"category_source":"user_override"}'::jsonb
+				ELSE source_metadata
+			END,
+			category=CASE WHEN lower(category)='other' THEN $1 ELSE category END,
+			tags=$2,merchant_id=COALESCE(merchant_id,$5),updated_at=now()
+		WHERE id=$3 AND user_id=$4
+		RETURNING id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
+			source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id`,
+		merged.category, tags, keepID, userID, merged.merchantID))
+	if err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	provenance, err := transactionProvenance(ctx, tx, userID, keepID)
+	if err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	return model.TransactionMergeResult{Transaction: transaction, Provenance: provenance}, nil
+}
+
+// ListTransactionProvenance returns the row's own origin followed by every
+// duplicate merged into it.
+func (r *Repository) ListTransactionProvenance(ctx context.Context, userID, transactionID int) ([]model.TransactionProvenance, error) {
+	return transactionProvenance(ctx, r.db, userID, transactionID)
+}
+
+func transactionProvenance(ctx context.Context, db queryer, userID, transactionID int) ([]model.TransactionProvenance, error) {
+	var own model.TransactionProvenance
+	err := db.QueryRow(ctx, `SELECT `+transactionOrigin+`,description,amount::text,to_char(occurred_at,'YYYY-MM-DD')
+		FROM transactions WHERE id=$1 AND user_id=$2`, transactionID, userID,
+	).Scan(&own.Origin, &own.Description, &own.Amount, &own.OccurredAt)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+	rows, err := db.Query(ctx, `SELECT `+transactionOrigin+`,description,amount::text,to_char(occurred_at,'YYYY-MM-DD'),
+		to_char(merged_at AT TIME ZONE 'UTC','YYYY-MM-DD"T"HH24:MI:SS"Z"')
+		FROM transaction_merges WHERE transaction_id=$1 AND user_id=$2 ORDER BY merged_at,id`, transactionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []model.TransactionProvenance{own}
+	for rows.Next() {
+		var item model.TransactionProvenance
+		if err := rows.Scan(&item.Origin, &item.Description, &item.Amount, &item.OccurredAt, &item.MergedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
 	"money-manager-server/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -20,19 +22,11 @@ type TransactionFilter struct {
 
 func (r *Repository) ListTransactions(ctx context.Context, userID int, filter TransactionFilter) ([]model.Transaction, error) {
 	query := `SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
-		source,status,excluded_from_budget,schedule_occurrence_id
+		source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id
         FROM transactions
-        WHERE user_id=$1 AND occurred_at >= $2 AND occurred_at < $3 AND status='booked'`
-	args := []any{userID, filter.From, filter.To}
-	if filter.Type != "" {
-		query += fmt.Sprintf(" AND type=$%d", len(args)+1)
-		args = append(args, filter.Type)
-	}
-	if filter.Category != "" {
-		query += fmt.Sprintf(" AND lower(category)=lower($%d)", len(args)+1)
-		args = append(args, filter.Category)
-	}
-	query += " ORDER BY occurred_at DESC,id DESC"
+        WHERE user_id=$1`
+	clause, args := transactionFilterClause(userID, filter)
+	query += clause + " ORDER BY occurred_at DESC,id DESC"
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -51,36 +45,28 @@ func (r *Repository) ListTransactions(ctx context.Context, userID int, filter Tr
 	return out, rows.Err()
 }
 
-func (r *Repository) ExportTransactions(ctx context.Context, userID int, from, toExclusive time.Time, limit int) ([]model.Transaction, error) {
-	rows, err := r.db.Query(ctx, `SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
-		source,status,excluded_from_budget,schedule_occurrence_id
-        FROM transactions
-        WHERE user_id=$1 AND occurred_at >= $2 AND occurred_at < $3 AND status='booked'
-		ORDER BY occurred_at ASC,id ASC LIMIT $4`, userID, from, toExclusive, limit)
-	if err != nil {
-		return nil, err
+func transactionFilterClause(userID int, filter TransactionFilter) (string, []any) {
+	clause := " AND occurred_at >= $2 AND occurred_at < $3 AND status='booked'"
+	args := []any{userID, filter.From, filter.To}
+	if filter.Type != "" {
+		clause += fmt.Sprintf(" AND type=$%d", len(args)+1)
+		args = append(args, filter.Type)
 	}
-	defer rows.Close()
-
-	out := make([]model.Transaction, 0)
-	for rows.Next() {
-		transaction, err := scanTransaction(rows)
-		if err != nil {
-			return nil, err
-		}
-		out = append(out, transaction)
+	if filter.Category != "" {
+		clause += fmt.Sprintf(" AND lower(category)=lower($%d)", len(args)+1)
+		args = append(args, filter.Category)
 	}
-	return out, rows.Err()
+	return clause, args
 }
 
 func (r *Repository) CreateTransaction(ctx context.Context, userID int, request model.TransactionRequest) (model.Transaction, error) {
 	row := r.db.QueryRow(ctx, `INSERT INTO transactions(
-		user_id,type,category,description,amount,currency,occurred_at,source,status,excluded_from_budget
-	) VALUES($1,$2,$3,$4,$5,$6,$7,'manual','booked',$8)
+		user_id,type,category,description,amount,currency,occurred_at,source,status,excluded_from_budget,tags,category_learned_at
+	) VALUES($1,$2,$3,$4,$5,$6,$7,'manual','booked',$8,$9,CASE WHEN $10 THEN now() END)
 		RETURNING id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
-			source,status,excluded_from_budget,schedule_occurrence_id`,
+			source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id`,
 		userID, request.Type, request.Category, request.Description, request.Amount, request.Currency,
-		request.OccurredAt, request.ExcludedFromBudget)
+		request.OccurredAt, request.ExcludedFromBudget, transactionTags(request.Tags), request.CategoryChosen)
 	return scanTransaction(row)
 }
 
@@ -94,25 +80,43 @@ func (r *Repository) ImportTransactions(ctx context.Context, userID int, transac
 	imported, skipped := 0, 0
 	for _, transaction := range transactions {
 		request := transaction.Request
-		tag, err := tx.Exec(ctx, `INSERT INTO transactions(
-            user_id,type,category,description,amount,currency,occurred_at,import_source,import_fingerprint,source,status
-        ) VALUES($1,$2,$3,$4,$5,$6,$7,'revolut',$8,'import','booked')
+		var merged bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(
+			SELECT 1 FROM transaction_merges
+			WHERE user_id=$1 AND import_source=$2 AND import_fingerprint=$3
+		)`, userID, transaction.Source, transaction.Fingerprint).Scan(&merged); err != nil {
+			return 0, 0, err
+		}
+		if merged {
+			skipped++
+			continue
+		}
+		var transactionID int
+		err := tx.QueryRow(ctx, `INSERT INTO transactions(
+            user_id,type,category,description,amount,currency,occurred_at,import_source,import_fingerprint,source,status,
+            excluded_from_budget,tags,category_learned_at
+        ) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,'import','booked',$10,$11,CASE WHEN $12 THEN now() END)
 		ON CONFLICT (user_id,import_source,import_fingerprint)
-		WHERE import_source IS NOT NULL AND import_fingerprint IS NOT NULL DO NOTHING`,
+		WHERE import_source IS NOT NULL AND import_fingerprint IS NOT NULL DO NOTHING
+		RETURNING id`,
 			userID, request.Type, request.Category, request.Description, request.Amount,
-			request.Currency, request.OccurredAt, transaction.Fingerprint)
-		if err != nil {
+			request.Currency, request.OccurredAt, transaction.Source, transaction.Fingerprint,
+			request.ExcludedFromBudget, transactionTags(request.Tags), transaction.CategoryChosen).Scan(&transactionID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 			return 0, 0, err
 		}
-		if tag.RowsAffected() == 1 {
+		if err == nil {
+			if err := assignMerchant(ctx, tx, userID, transactionID, transaction.Merchant); err != nil {
+				return 0, 0, err
+			}
 			imported++
 		} else {
 			if request.Category != "other" {
 				if _, err := tx.Exec(ctx, `UPDATE transactions
 					SET category=$1,updated_at=now()
-					WHERE user_id=$2 AND import_source='revolut' AND import_fingerprint=$3
+					WHERE user_id=$2 AND import_source=$3 AND import_fingerprint=$4
 						AND lower(category)='other'`,
-					request.Category, userID, transaction.Fingerprint); err != nil {
+					request.Category, userID, transaction.Source, transaction.Fingerprint); err != nil {
 					return 0, 0, err
 				}
 			}
@@ -127,14 +131,24 @@ func (r *Repository) ImportTransactions(ctx context.Context, userID int, transac
 
 func (r *Repository) GetTransaction(ctx context.Context, userID, transactionID int) (model.Transaction, error) {
 	row := r.db.QueryRow(ctx, `SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
-		source,status,excluded_from_budget,schedule_occurrence_id
+		source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id
         FROM transactions WHERE id=$1 AND user_id=$2`, transactionID, userID)
 	transaction, err := scanTransaction(row)
 	return transaction, mapNotFound(err)
 }
 
+// UpdateTransaction also carries a new amount over to the schedule occurrence
+// the transaction was posted for or reconciled with, and re-estimates that
+// occurrence's schedule when it follows a rolling average.
 func (r *Repository) UpdateTransaction(ctx context.Context, userID, transactionID int, request model.TransactionRequest) (model.Transaction, error) {
-	row := r.db.QueryRow(ctx, `UPDATE transactions
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	row := tx.QueryRow(ctx, `WITH updated AS (
+		UPDATE transactions
 		SET source_metadata=CASE
 				WHEN source='open_banking' AND (type IS DISTINCT FROM $1 OR category IS DISTINCT FROM $2)
 				THEN source_metadata || jsonb_strip_nulls(jsonb_build_object(
@@ -145,15 +159,43 @@ func (r *Repository) UpdateTransaction(ctx context.Context, userID, transactionI
 				))
 				ELSE source_metadata
 			END,
+			category_learned_at=CASE
+				WHEN type IS DISTINCT FROM $1 OR category IS DISTINCT FROM $2 THEN now()
+				ELSE category_learned_at
+			END,
 			type=$1,category=$2,description=$3,amount=$4,currency=$5,occurred_at=$6,
 			excluded_from_budget=$7,updated_at=now()
 		WHERE id=$8 AND user_id=$9
-		RETURNING id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
-			source,status,excluded_from_budget,schedule_occurrence_id`,
+		RETURNING id,type,category,description,amount,currency,occurred_at,
+			source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id
+	), occurrence AS (
+		UPDATE transaction_schedule_occurrences
+		SET amount=updated.amount,updated_at=now()
+		FROM updated
+		WHERE transaction_schedule_occurrences.id=updated.schedule_occurrence_id
+		  AND transaction_schedule_occurrences.status='posted'
+		  AND transaction_schedule_occurrences.amount IS DISTINCT FROM updated.amount
+	)
+	SELECT id,type,category,description,amount::text,currency,to_char(occurred_at,'YYYY-MM-DD'),
+		source,status,excluded_from_budget,schedule_occurrence_id,tags,merchant_id
+	FROM updated`,
 		request.Type, request.Category, request.Description, request.Amount, request.Currency,
 		request.OccurredAt, request.ExcludedFromBudget, transactionID, userID)
 	transaction, err := scanTransaction(row)
-	return transaction, mapNotFound(err)
+	if err != nil {
+		return model.Transaction{}, mapNotFound(err)
+	}
+	if transaction.ScheduleOccurrenceID != nil {
+		var scheduleID int
+		if err := tx.QueryRow(ctx, `SELECT schedule_id FROM transaction_schedule_occurrences WHERE id=$1`,
+			*transaction.ScheduleOccurrenceID).Scan(&scheduleID); err != nil {
+			return model.Transaction{}, err
+		}
+		if err := refreshRollingAverageSchedule(ctx, tx, scheduleID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+	return transaction, tx.Commit(ctx)
 }
 
 func (r *Repository) DeleteTransaction(ctx context.Context, userID, transactionID int) error {
@@ -206,14 +248,74 @@ func (r *Repository) Summary(ctx context.Context, userID int, month string, from
 	if summary.Balance, err = calculateBalance(summary.Income, summary.CashOutflow); err != nil {
 		return model.Summary{}, err
 	}
+	if summary.Categories, err = r.categoryTotals(ctx, userID, from, to); err != nil {
+		return model.Summary{}, err
+	}
 	return summary, nil
 }
 
+// rolledUpCategoryJoin exposes a transaction's active parent category as
+// "parent", so COALESCE(parent.name,t.category) is its top-level category.
+const rolledUpCategoryJoin = `LEFT JOIN categories c ON c.user_id=t.user_id AND c.type=t.type AND c.active
+		AND lower(c.name)=lower(t.category)
+	LEFT JOIN categories parent ON parent.id=c.parent_id AND parent.active`
+
+// categoryTotals groups booked amounts by top-level category, rolling each
+// subcategory up into its parent. Categories are largest first per type.
+func (r *Repository) categoryTotals(ctx context.Context, userID int, from, to time.Time) ([]model.CategoryTotal, error) {
+	rows, err := r.db.Query(ctx, `WITH grouped AS (
+			SELECT t.type,COALESCE(parent.name,t.category) AS top,
+				CASE WHEN parent.id IS NULL THEN '' ELSE t.category END AS sub,
+				sum(t.amount) AS amount
+			FROM transactions t `+rolledUpCategoryJoin+`
+			WHERE t.user_id=$1 AND t.occurred_at >= $2 AND t.occurred_at < $3 AND t.status='booked'
+			GROUP BY 1,2,3
+		)
+		SELECT type,top,sub,amount::text,(sum(amount) OVER (PARTITION BY type,top))::text
+		FROM grouped
+		ORDER BY type,sum(amount) OVER (PARTITION BY type,top) DESC,top,sub='' DESC,amount DESC,sub`,
+		userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	totals := make([]model.CategoryTotal, 0)
+	for rows.Next() {
+		var transactionType, top, sub, amount, total string
+		if err := rows.Scan(&transactionType, &top, &sub, &amount, &total); err != nil {
+			return nil, err
+		}
+		if len(totals) == 0 || totals[len(totals)-1].Type != transactionType || totals[len(totals)-1].Category != top {
+			if total, err = decimalWithTwoPlaces(total); err != nil {
+				return nil, fmt.Errorf("format category total: %w", err)
+			}
+			totals = append(totals, model.CategoryTotal{Type: transactionType, Category: top, Amount: total})
+		}
+		if sub == "" {
+			continue
+		}
+		if amount, err = decimalWithTwoPlaces(amount); err != nil {
+			return nil, fmt.Errorf("format subcategory total: %w", err)
+		}
+		parent := &totals[len(totals)-1]
+		parent.Subcategories = append(parent.Subcategories, model.CategoryTotal{Type: transactionType, Category: sub, Amount: amount})
+	}
+	return totals, rows.Err()
+}
+
+// transactionTags keeps a nil tag list from reaching the NOT NULL column.
+func transactionTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 type rowScanner interface{ Scan(dest ...any) error }
 
 func scanTransaction(row rowScanner) (model.Transaction, error) {
 	var transaction model.Transaction
-	var scheduleOccurrenceID pgtype.Int8
+	var scheduleOccurrenceID, merchantID pgtype.Int8
 	err := row.Scan(
 		&transaction.ID,
 		&transaction.Type,
@@ -226,11 +328,20 @@ func scanTransaction(row rowScanner) (model.Transaction, error) {
 		&transaction.Status,
 		&transaction.ExcludedFromBudget,
 		&scheduleOccurrenceID,
+		&transaction.Tags,
+		&merchantID,
 	)
+	if transaction.Tags == nil {
+		transaction.Tags = []string{}
+	}
 	if scheduleOccurrenceID.Valid {
 		value := int(scheduleOccurrenceID.Int64)
 		transaction.ScheduleOccurrenceID = &value
 	}
+	if merchantID.Valid {
+		value := int(merchantID.Int64)
+		transaction.MerchantID = &value
+	}
 	return transaction, err
 }
 
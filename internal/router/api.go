@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"io"
 
 	"money-manager-server/internal/model"
 )
@@ -12,8 +13,17 @@ type API interface {
 	profileAPI
 	categoryAPI
 	transactionAPI
+	merchantAPI
+	categorizationRuleAPI
+	importAPI
 	transactionScheduleAPI
 	budgetAPI
+	savingsGoalAPI
+	loanAPI
+	sharedExpenseAPI
+	forecastAPI
+	insightAPI
+	reportAPI
 	notificationAPI
 	investmentAPI
 	openBankingAPI
@@ -37,19 +47,67 @@ type profileAPI interface {
 type categoryAPI interface {
 	ListCategories(context.Context, int, string) ([]model.Category, error)
 	CreateCategory(context.Context, int, model.CategoryRequest) (model.Category, error)
+	UpdateCategory(context.Context, int, int, model.CategoryUpdateRequest) (model.Category, error)
+	MergeCategory(context.Context, int, int, model.CategoryMergeRequest) (model.CategoryMergeResult, error)
 	DeleteCategory(context.Context, int, int) error
 }
 
 type transactionAPI interface {
 	ListTransactions(context.Context, int, string, string, string) ([]model.Transaction, error)
-	ExportTransactions(context.Context, int, string, string) ([]model.Transaction, error)
+	ExportTransactions(context.Context, int, model.TransactionExportRequest, func(model.ExportFile) io.Writer) error
+	CreateTransactionExport(context.Context, int, model.TransactionExportRequest) (model.TransactionExport, error)
+	ListTransactionExports(context.Context, int) ([]model.TransactionExport, error)
+	GetTransactionExport(context.Context, int, int) (model.TransactionExport, error)
+	DeleteTransactionExport(context.Context, int, int) error
+	DownloadTransactionExport(context.Context, int, int, func(model.ExportFile) io.Writer) error
+	ExportJournal(context.Context, int, model.TransactionExportRequest, func(model.ExportFile) io.Writer) error
 	Summary(context.Context, int, string) (model.Summary, error)
 	CreateTransaction(context.Context, int, model.TransactionRequest) (model.Transaction, error)
 	UpdateTransaction(context.Context, int, int, model.TransactionRequest) (model.Transaction, error)
 	DeleteTransaction(context.Context, int, int) error
+	BulkTransactions(context.Context, int, model.BulkTransactionRequest) (model.BulkTransactionResult, error)
+	ListDuplicateTransactions(context.Context, int, string, string) ([]model.DuplicateTransactionPair, error)
+	MergeTransactions(context.Context, int, model.TransactionMergeRequest) (model.TransactionMergeResult, error)
+	GetTransactionProvenance(context.Context, int, int) ([]model.TransactionProvenance, error)
 	ImportRevolutCSV(context.Context, int, []byte) (model.ImportResult, error)
 }
 
+type merchantAPI interface {
+	ListMerchants(context.Context, int) ([]model.Merchant, error)
+	RenameMerchant(context.Context, int, int, model.MerchantRequest) (model.Merchant, error)
+	MergeMerchants(context.Context, int, model.MerchantMergeRequest) (model.Merchant, error)
+	RefreshMerchants(context.Context, int) (model.MerchantRefreshResult, error)
+	ListMerchantSpending(context.Context, int, string, string) ([]model.MerchantSpending, error)
+	GetMerchantSpending(context.Context, int, int, string, string) (model.MerchantSpendingDetail, error)
+}
+
+type categorizationRuleAPI interface {
+	ListCategorizationRules(context.Context, int) ([]model.CategorizationRule, error)
+	CreateCategorizationRule(context.Context, int, model.CategorizationRuleRequest) (model.CategorizationRule, error)
+	UpdateCategorizationRule(context.Context, int, int, model.CategorizationRuleRequest) (model.CategorizationRule, error)
+	DeleteCategorizationRule(context.Context, int, int) error
+	RunCategorizationRules(context.Context, int, model.CategorizationRuleRunRequest) (model.CategorizationRuleRunResult, error)
+	GetCategoryModel(context.Context, int) (model.CategoryModel, error)
+	PredictCategory(context.Context, int, string, string, string) (model.CategoryPrediction, error)
+	ResetCategoryModel(context.Context, int) (model.CategoryModelReset, error)
+}
+
+type importAPI interface {
+	DetectCSVImport(context.Context, int, []byte) (model.CSVImportDetection, error)
+	ImportCSV(context.Context, int, int, []byte) (model.StatementImportResult, error)
+	ImportOFX(context.Context, int, []byte) (model.StatementImportResult, error)
+	ImportQIF(context.Context, int, []byte, string) (model.StatementImportResult, error)
+	ImportCAMT(context.Context, int, []byte) (model.StatementImportResult, error)
+	ImportMT940(context.Context, int, []byte) (model.StatementImportResult, error)
+	PreviewImport(context.Context, int, model.ImportPreviewRequest, []byte) (model.ImportPreview, error)
+	GetImportPreview(context.Context, int, int) (model.ImportPreview, error)
+	CommitImportPreview(context.Context, int, int, model.ImportCommitRequest) (model.StatementImportResult, error)
+	ListCSVImportProfiles(context.Context, int) ([]model.CSVImportProfile, error)
+	CreateCSVImportProfile(context.Context, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error)
+	UpdateCSVImportProfile(context.Context, int, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error)
+	DeleteCSVImportProfile(context.Context, int, int) error
+}
+
 type transactionScheduleAPI interface {
 	ListTransactionSchedules(context.Context, int, string) ([]model.TransactionSchedule, error)
 	CreateTransactionSchedule(context.Context, int, model.TransactionScheduleRequest) (model.TransactionSchedule, error)
@@ -59,6 +117,9 @@ type transactionScheduleAPI interface {
 	ResumeTransactionSchedule(context.Context, int, int) (model.TransactionSchedule, error)
 	DeleteTransactionSchedule(context.Context, int, int) error
 	ListTransactionScheduleOccurrences(context.Context, int, string, string, int, string) ([]model.TransactionScheduleOccurrence, error)
+	UpdateTransactionScheduleOccurrence(context.Context, int, int, model.ScheduleOccurrenceRequest) (model.TransactionScheduleOccurrence, error)
+	SkipTransactionScheduleOccurrence(context.Context, int, int) (model.TransactionScheduleOccurrence, error)
+	UnskipTransactionScheduleOccurrence(context.Context, int, int) (model.TransactionScheduleOccurrence, error)
 }
 
 type budgetAPI interface {
@@ -69,6 +130,66 @@ type budgetAPI interface {
 	DeleteBudget(context.Context, int, int) error
 }
 
+type savingsGoalAPI interface {
+	ListSavingsGoals(context.Context, int, bool) ([]model.SavingsGoal, error)
+	GetSavingsGoal(context.Context, int, int) (model.SavingsGoal, error)
+	CreateSavingsGoal(context.Context, int, model.SavingsGoalRequest) (model.SavingsGoal, error)
+	UpdateSavingsGoal(context.Context, int, int, model.SavingsGoalRequest) (model.SavingsGoal, error)
+	DeleteSavingsGoal(context.Context, int, int) error
+	ListSavingsGoalContributions(context.Context, int, int) ([]model.SavingsGoalContribution, error)
+	AddSavingsGoalContribution(context.Context, int, int, model.SavingsGoalContributionRequest) (model.SavingsGoalContribution, error)
+	DeleteSavingsGoalContribution(context.Context, int, int, int) error
+}
+
+type loanAPI interface {
+	ListLoans(context.Context, int, bool) ([]model.Loan, error)
+	GetLoan(context.Context, int, int) (model.Loan, error)
+	CreateLoan(context.Context, int, model.LoanRequest) (model.Loan, error)
+	UpdateLoan(context.Context, int, int, model.LoanRequest) (model.Loan, error)
+	DeleteLoan(context.Context, int, int) error
+	GetLoanSchedule(context.Context, int, int) ([]model.LoanInstallment, error)
+	ProjectLoanPayoff(context.Context, int, int, string, string) (model.LoanPayoffProjection, error)
+	ListLoanPayments(context.Context, int, int) ([]model.LoanPayment, error)
+	AddLoanPayment(context.Context, int, int, model.LoanPaymentRequest) (model.LoanPayment, error)
+	DeleteLoanPayment(context.Context, int, int, int) error
+}
+
+type sharedExpenseAPI interface {
+	ListExpenseGroups(context.Context, int, bool) ([]model.ExpenseGroup, error)
+	GetExpenseGroup(context.Context, int, int) (model.ExpenseGroup, error)
+	CreateExpenseGroup(context.Context, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error)
+	UpdateExpenseGroup(context.Context, int, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error)
+	DeleteExpenseGroup(context.Context, int, int) error
+	AddExpenseGroupMember(context.Context, int, int, model.ExpenseGroupMemberRequest) (model.ExpenseGroupMember, error)
+	DeleteExpenseGroupMember(context.Context, int, int, int) error
+	ListSharedExpenses(context.Context, int, int) ([]model.SharedExpense, error)
+	AddSharedExpense(context.Context, int, int, model.SharedExpenseRequest) (model.SharedExpense, error)
+	DeleteSharedExpense(context.Context, int, int, int) error
+	ListExpenseSettlements(context.Context, int, int) ([]model.ExpenseSettlement, error)
+	AddExpenseSettlement(context.Context, int, int, model.ExpenseSettlementRequest) (model.ExpenseSettlement, error)
+	DeleteExpenseSettlement(context.Context, int, int, int) error
+	GetExpenseGroupBalances(context.Context, int, int) (model.ExpenseGroupBalances, error)
+}
+
+type forecastAPI interface {
+	Forecast(context.Context, int, string) (model.Forecast, error)
+	GetForecastSettings(context.Context, int) (model.ForecastSettings, error)
+	UpdateForecastSettings(context.Context, int, model.ForecastSettings) (model.ForecastSettings, error)
+}
+
+type insightAPI interface {
+	ListSubscriptions(context.Context, int) ([]model.Subscription, error)
+	ScheduleSubscription(context.Context, int, model.SubscriptionScheduleRequest) (model.TransactionSchedule, error)
+}
+
+type reportAPI interface {
+	CashFlowReport(context.Context, int, model.ReportRequest) (model.CashFlowReport, error)
+	CategoryReport(context.Context, int, model.ReportRequest) (model.CategoryReport, error)
+	MerchantReport(context.Context, int, model.ReportRequest) (model.MerchantReport, error)
+	AnnualReport(context.Context, int, string) (model.AnnualReport, error)
+	ExportAnnualReport(context.Context, int, string, func(model.ExportFile) io.Writer) error
+}
+
 type notificationAPI interface {
 	GetNotificationPreferences(context.Context, int) (model.NotificationPreferences, error)
 	UpdateNotificationPreferences(context.Context, int, model.NotificationPreferences) (model.NotificationPreferences, error)
@@ -83,7 +204,7 @@ type investmentAPI interface {
 	InvestmentPortfolio(context.Context, int) (model.InvestmentPortfolio, error)
 	InvestmentPortfolioHistory(context.Context, int, string) (model.InvestmentPortfolioHistory, error)
 	SetManualInvestmentPrice(context.Context, int, model.InvestmentPriceRequest) (model.InvestmentPrice, error)
-	ExportInvestmentTrades(context.Context, int, string, string) ([]model.InvestmentTrade, error)
+	ExportInvestmentTrades(context.Context, int, model.InvestmentExportRequest, func(model.ExportFile) io.Writer) error
 	ListInvestmentSchedules(context.Context, int, string) ([]model.InvestmentSchedule, error)
 	CreateInvestmentSchedule(context.Context, int, model.InvestmentScheduleRequest) (model.InvestmentSchedule, error)
 	GetInvestmentSchedule(context.Context, int, int) (model.InvestmentSchedule, error)
@@ -91,6 +212,10 @@ type investmentAPI interface {
 	PauseInvestmentSchedule(context.Context, int, int) (model.InvestmentSchedule, error)
 	ResumeInvestmentSchedule(context.Context, int, int) (model.InvestmentSchedule, error)
 	DeleteInvestmentSchedule(context.Context, int, int) error
+	ListInvestmentScheduleOccurrences(context.Context, int, string, string, int, string) ([]model.InvestmentScheduleOccurrence, error)
+	UpdateInvestmentScheduleOccurrence(context.Context, int, int, model.InvestmentScheduleOccurrenceRequest) (model.InvestmentScheduleOccurrence, error)
+	SkipInvestmentScheduleOccurrence(context.Context, int, int) (model.InvestmentScheduleOccurrence, error)
+	UnskipInvestmentScheduleOccurrence(context.Context, int, int) (model.InvestmentScheduleOccurrence, error)
 }
 
 type openBankingAPI interface {
@@ -58,7 +58,9 @@ func observeRequests(next http.Handler, logger *slog.Logger, trustedProxyCIDRs [
 		recorder := &responseRecorder{ResponseWriter: w}
 		started := time.Now()
 		defer func() {
-			if recovered := recover(); recovered != nil {
+			recovered := recover()
+			aborted := recovered == http.ErrAbortHandler
+			if recovered != nil && !aborted {
 				logger.ErrorContext(ctx, "request panic", "request_id", requestID, "panic", recovered)
 				if recorder.status == 0 {
 					writeError(recorder, request, logger, apperrors.Internal(errors.New("request handler panic")))
@@ -77,6 +79,10 @@ func observeRequests(next http.Handler, logger *slog.Logger, trustedProxyCIDRs [
 				"duration_ms", time.Since(started).Milliseconds(),
 				"client_ip", clientIP(request, trustedProxyCIDRs, trustedProxyHops),
 			)
+			if aborted {
+				// Let net/http drop the connection of an interrupted download.
+				panic(http.ErrAbortHandler)
+			}
 		}()
 		next.ServeHTTP(recorder, request)
 	})
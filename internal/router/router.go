@@ -30,8 +30,17 @@ func Build(api API, options Options) http.Handler {
 		h.registerProfileRoutes,
 		h.registerCategoryRoutes,
 		h.registerTransactionRoutes,
+		h.registerMerchantRoutes,
+		h.registerCategorizationRuleRoutes,
+		h.registerImportRoutes,
 		h.registerTransactionScheduleRoutes,
 		h.registerBudgetRoutes,
+		h.registerSavingsGoalRoutes,
+		h.registerLoanRoutes,
+		h.registerSharedExpenseRoutes,
+		h.registerForecastRoutes,
+		h.registerInsightRoutes,
+		h.registerReportRoutes,
 		h.registerNotificationRoutes,
 		h.registerInvestmentRoutes,
 		h.registerInvestmentScheduleRoutes,
@@ -256,14 +256,52 @@ func TestProtectedRouteInventoryRequiresAuthentication(t *testing.T) {
 		{http.MethodDelete, "/me"},
 		{http.MethodGet, "/categories"},
 		{http.MethodPost, "/categories"},
+		{http.MethodPut, "/categories/1"},
+		{http.MethodPost, "/categories/1/merge"},
 		{http.MethodDelete, "/categories/1"},
 		{http.MethodGet, "/transactions"},
 		{http.MethodGet, "/transactions/export"},
+		{http.MethodPost, "/transaction-exports"},
+		{http.MethodGet, "/transaction-exports"},
+		{http.MethodGet, "/transaction-exports/1"},
+		{http.MethodGet, "/transaction-exports/1/download"},
+		{http.MethodDelete, "/transaction-exports/1"},
 		{http.MethodPost, "/transactions/import/revolut"},
+		{http.MethodPost, "/transactions/import/csv/detect"},
+		{http.MethodPost, "/transactions/import/csv?profile_id=1"},
+		{http.MethodPost, "/transactions/import/ofx"},
+		{http.MethodPost, "/transactions/import/qif"},
+		{http.MethodPost, "/transactions/import/camt"},
+		{http.MethodPost, "/transactions/import/mt940"},
+		{http.MethodPost, "/transactions/import/ofx/preview"},
+		{http.MethodGet, "/transactions/import/previews/1"},
+		{http.MethodPost, "/transactions/import/previews/1/commit"},
+		{http.MethodGet, "/csv-import-profiles"},
+		{http.MethodPost, "/csv-import-profiles"},
+		{http.MethodPut, "/csv-import-profiles/1"},
+		{http.MethodDelete, "/csv-import-profiles/1"},
 		{http.MethodGet, "/transactions/summary"},
 		{http.MethodPost, "/transactions"},
+		{http.MethodPost, "/transactions/bulk"},
+		{http.MethodGet, "/transactions/duplicates"},
+		{http.MethodPost, "/transactions/duplicates/merge"},
+		{http.MethodGet, "/transactions/1/provenance"},
 		{http.MethodPut, "/transactions/1"},
 		{http.MethodDelete, "/transactions/1"},
+		{http.MethodGet, "/merchants"},
+		{http.MethodPut, "/merchants/1"},
+		{http.MethodPost, "/merchants/merge"},
+		{http.MethodPost, "/merchants/refresh"},
+		{http.MethodGet, "/merchants/spending"},
+		{http.MethodGet, "/merchants/1/spending"},
+		{http.MethodGet, "/categorization-rules"},
+		{http.MethodPost, "/categorization-rules"},
+		{http.MethodPut, "/categorization-rules/1"},
+		{http.MethodDelete, "/categorization-rules/1"},
+		{http.MethodPost, "/categorization-rules/run"},
+		{http.MethodGet, "/categorization-model"},
+		{http.MethodGet, "/categorization-model/predict"},
+		{http.MethodDelete, "/categorization-model"},
 		{http.MethodGet, "/schedules"},
 		{http.MethodPost, "/schedules"},
 		{http.MethodGet, "/schedules/1"},
@@ -272,11 +310,55 @@ func TestProtectedRouteInventoryRequiresAuthentication(t *testing.T) {
 		{http.MethodPost, "/schedules/1/resume"},
 		{http.MethodDelete, "/schedules/1"},
 		{http.MethodGet, "/schedule-occurrences"},
+		{http.MethodPut, "/schedule-occurrences/1"},
+		{http.MethodPost, "/schedule-occurrences/1/skip"},
+		{http.MethodPost, "/schedule-occurrences/1/unskip"},
 		{http.MethodGet, "/budgets"},
 		{http.MethodPost, "/budgets"},
 		{http.MethodGet, "/budgets/1"},
 		{http.MethodPut, "/budgets/1"},
 		{http.MethodDelete, "/budgets/1"},
+		{http.MethodGet, "/savings-goals"},
+		{http.MethodPost, "/savings-goals"},
+		{http.MethodGet, "/savings-goals/1"},
+		{http.MethodPut, "/savings-goals/1"},
+		{http.MethodDelete, "/savings-goals/1"},
+		{http.MethodGet, "/savings-goals/1/contributions"},
+		{http.MethodPost, "/savings-goals/1/contributions"},
+		{http.MethodDelete, "/savings-goals/1/contributions/2"},
+		{http.MethodGet, "/loans"},
+		{http.MethodPost, "/loans"},
+		{http.MethodGet, "/loans/1"},
+		{http.MethodPut, "/loans/1"},
+		{http.MethodDelete, "/loans/1"},
+		{http.MethodGet, "/loans/1/schedule"},
+		{http.MethodGet, "/loans/1/payoff"},
+		{http.MethodGet, "/loans/1/payments"},
+		{http.MethodPost, "/loans/1/payments"},
+		{http.MethodDelete, "/loans/1/payments/2"},
+		{http.MethodGet, "/expense-groups"},
+		{http.MethodPost, "/expense-groups"},
+		{http.MethodGet, "/expense-groups/1"},
+		{http.MethodPut, "/expense-groups/1"},
+		{http.MethodDelete, "/expense-groups/1"},
+		{http.MethodGet, "/expense-groups/1/balances"},
+		{http.MethodPost, "/expense-groups/1/members"},
+		{http.MethodDelete, "/expense-groups/1/members/2"},
+		{http.MethodGet, "/expense-groups/1/expenses"},
+		{http.MethodPost, "/expense-groups/1/expenses"},
+		{http.MethodDelete, "/expense-groups/1/expenses/2"},
+		{http.MethodGet, "/expense-groups/1/settlements"},
+		{http.MethodPost, "/expense-groups/1/settlements"},
+		{http.MethodDelete, "/expense-groups/1/settlements/2"},
+		{http.MethodGet, "/forecast"},
+		{http.MethodGet, "/forecast/settings"},
+		{http.MethodPut, "/forecast/settings"},
+		{http.MethodGet, "/insights/subscriptions"},
+		{http.MethodPost, "/insights/subscriptions/schedule"},
+		{http.MethodGet, "/reports/cash-flow"},
+		{http.MethodGet, "/reports/categories"},
+		{http.MethodGet, "/reports/merchants"},
+		{http.MethodGet, "/reports/annual"},
 		{http.MethodGet, "/notification-preferences"},
 		{http.MethodPut, "/notification-preferences"},
 		{http.MethodPost, "/push-devices"},
@@ -288,6 +370,7 @@ func TestProtectedRouteInventoryRequiresAuthentication(t *testing.T) {
 		{http.MethodDelete, "/investments/trades/1"},
 		{http.MethodPut, "/investments/prices"},
 		{http.MethodGet, "/investments/export"},
+		{http.MethodGet, "/exports/journal"},
 		{http.MethodGet, "/investment-schedules"},
 		{http.MethodPost, "/investment-schedules"},
 		{http.MethodGet, "/investment-schedules/1"},
@@ -295,6 +378,10 @@ func TestProtectedRouteInventoryRequiresAuthentication(t *testing.T) {
 		{http.MethodPost, "/investment-schedules/1/pause"},
 		{http.MethodPost, "/investment-schedules/1/resume"},
 		{http.MethodDelete, "/investment-schedules/1"},
+		{http.MethodGet, "/investment-schedule-occurrences"},
+		{http.MethodPut, "/investment-schedule-occurrences/1"},
+		{http.MethodPost, "/investment-schedule-occurrences/1/skip"},
+		{http.MethodPost, "/investment-schedule-occurrences/1/unskip"},
 		{http.MethodGet, "/api/open-banking/banks"},
 		{http.MethodPost, "/api/open-banking/authorizations"},
 		{http.MethodGet, "/api/open-banking/connections"},
@@ -324,6 +411,95 @@ func TestProtectedRouteInventoryRequiresAuthentication(t *testing.T) {
 	}
 }
 
+func TestStreamedExportReportsErrorsBeforeAndAbortsAfterFirstByte(t *testing.T) {
+	api := &fakeAPI{exportError: apperrors.Validation("format must be one of csv, jsonl")}
+	invalid := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/transactions/export?format=xml", nil)
+	request.Header.Set("Authorization", "Bearer valid")
+	testHandler(api, Options{}).ServeHTTP(invalid, request)
+	if invalid.Code != http.StatusBadRequest || !strings.Contains(invalid.Body.String(), "format must be") {
+		t.Fatalf("invalid export = %d %s", invalid.Code, invalid.Body.String())
+	}
+
+	api = &fakeAPI{exportContents: "occurred_at\n2026-07-10\n"}
+	server := httptest.NewServer(testHandler(api, Options{}))
+	defer server.Close()
+	download := func() (*http.Response, string, error) {
+		request, _ := http.NewRequest(http.MethodGet, server.URL+"/transactions/export?from=2020-01-01&to=2026-07-31", nil)
+		request.Header.Set("Authorization", "Bearer valid")
+		response, err := server.Client().Do(request)
+		if err != nil {
+			return nil, "", err
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		return response, string(body), err
+	}
+	response, body, err := download()
+	if err != nil || response.StatusCode != http.StatusOK || body != api.exportContents ||
+		response.Header.Get("Content-Disposition") != `attachment; filename="export.csv"` {
+		t.Fatalf("streamed export = %v %q %v", response, body, err)
+	}
+
+	api.exportError = errors.New("connection reset")
+	if _, _, err := download(); err == nil {
+		t.Fatal("interrupted export was delivered as a complete file")
+	}
+}
+
+func TestAnnualReportIsJSONOrAPDFDownload(t *testing.T) {
+	handler := testHandler(&fakeAPI{}, Options{})
+	get := func(query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/reports/annual"+query, nil)
+		request.Header.Set("Authorization", "Bearer valid")
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	if response := get("?year=2025"); response.Code != http.StatusOK || !strings.Contains(response.Body.String(), `"year":2025`) {
+		t.Fatalf("JSON annual report = %d %s", response.Code, response.Body.String())
+	}
+	response := get("?year=2025&format=pdf")
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "application/pdf" ||
+		response.Header().Get("Content-Disposition") != `attachment; filename="year-in-review-2025.pdf"` ||
+		!strings.HasPrefix(response.Body.String(), "%PDF-") {
+		t.Fatalf("PDF annual report = %d %v %q", response.Code, response.Header(), response.Body.String())
+	}
+	if response := get("?format=xlsx"); response.Code != http.StatusBadRequest || !strings.Contains(response.Body.String(), "format must be json or pdf") {
+		t.Fatalf("unsupported annual report format = %d %s", response.Code, response.Body.String())
+	}
+}
+
+func TestSavingsGoalContributionDeleteReadsBothIDs(t *testing.T) {
+	api := &fakeAPI{}
+	handler := testHandler(api, Options{})
+	remove := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodDelete, path, nil)
+		request.Header.Set("Authorization", "Bearer valid")
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	if response := remove("/savings-goals/4/contributions/9"); response.Code != http.StatusNoContent || api.deletedContribution != [2]int{4, 9} {
+		t.Fatalf("delete contribution = %d, deleted %v", response.Code, api.deletedContribution)
+	}
+	if response := remove("/savings-goals/4/contributions/first"); response.Code != http.StatusBadRequest {
+		t.Fatalf("invalid contribution id = %d %s", response.Code, response.Body.String())
+	}
+}
+
+func TestLoanPayoffPassesScenarioQuery(t *testing.T) {
+	api := &fakeAPI{}
+	handler := testHandler(api, Options{})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/loans/3/payoff?extra_monthly=100&lump_sum=2500.50", nil)
+	request.Header.Set("Authorization", "Bearer valid")
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || api.loanPayoffQuery != [2]string{"100", "2500.50"} {
+		t.Fatalf("payoff = %d, query %v", recorder.Code, api.loanPayoffQuery)
+	}
+}
+
 func testHandler(api API, options Options) http.Handler {
 	options.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
 	return Build(api, options)
@@ -336,6 +512,10 @@ type fakeAPI struct {
 	openBankingInstitutions []model.OpenBankingInstitution
 	openBankingCallback     model.OpenBankingCallbackResult
 	openBankingCallbackErr  error
+	exportContents          string
+	exportError             error
+	deletedContribution     [2]int
+	loanPayoffQuery         [2]string
 }
 
 func (f *fakeAPI) Ready(context.Context) error { return f.readyError }
@@ -362,12 +542,59 @@ func (*fakeAPI) ListCategories(context.Context, int, string) ([]model.Category,
 func (*fakeAPI) CreateCategory(context.Context, int, model.CategoryRequest) (model.Category, error) {
 	return model.Category{}, nil
 }
+func (*fakeAPI) UpdateCategory(context.Context, int, int, model.CategoryUpdateRequest) (model.Category, error) {
+	return model.Category{}, nil
+}
+func (*fakeAPI) MergeCategory(context.Context, int, int, model.CategoryMergeRequest) (model.CategoryMergeResult, error) {
+	return model.CategoryMergeResult{}, nil
+}
 func (*fakeAPI) DeleteCategory(context.Context, int, int) error { return nil }
+func (*fakeAPI) CashFlowReport(context.Context, int, model.ReportRequest) (model.CashFlowReport, error) {
+	return model.CashFlowReport{}, nil
+}
+func (*fakeAPI) CategoryReport(context.Context, int, model.ReportRequest) (model.CategoryReport, error) {
+	return model.CategoryReport{}, nil
+}
+func (*fakeAPI) MerchantReport(context.Context, int, model.ReportRequest) (model.MerchantReport, error) {
+	return model.MerchantReport{}, nil
+}
+func (*fakeAPI) AnnualReport(_ context.Context, _ int, year string) (model.AnnualReport, error) {
+	if year == "" {
+		year = "2026"
+	}
+	parsed, _ := strconv.Atoi(year)
+	return model.AnnualReport{Year: parsed, Currency: "EUR"}, nil
+}
+func (*fakeAPI) ExportAnnualReport(_ context.Context, _ int, year string, start func(model.ExportFile) io.Writer) error {
+	_, err := io.WriteString(start(model.ExportFile{Filename: "year-in-review-" + year + ".pdf", ContentType: "application/pdf"}), "%PDF-1.4\n")
+	return err
+}
 func (*fakeAPI) ListTransactions(context.Context, int, string, string, string) ([]model.Transaction, error) {
 	return []model.Transaction{}, nil
 }
-func (*fakeAPI) ExportTransactions(context.Context, int, string, string) ([]model.Transaction, error) {
-	return []model.Transaction{}, nil
+func (f *fakeAPI) ExportTransactions(_ context.Context, _ int, _ model.TransactionExportRequest, start func(model.ExportFile) io.Writer) error {
+	if f.exportContents == "" {
+		return f.exportError
+	}
+	_, _ = io.WriteString(start(model.ExportFile{Filename: "export.csv", ContentType: "text/csv"}), f.exportContents)
+	return f.exportError
+}
+func (*fakeAPI) ExportJournal(_ context.Context, _ int, request model.TransactionExportRequest, start func(model.ExportFile) io.Writer) error {
+	_, err := io.WriteString(start(model.ExportFile{Filename: "journal." + request.Format, ContentType: "text/plain"}), "; journal\n")
+	return err
+}
+func (*fakeAPI) CreateTransactionExport(context.Context, int, model.TransactionExportRequest) (model.TransactionExport, error) {
+	return model.TransactionExport{}, nil
+}
+func (*fakeAPI) ListTransactionExports(context.Context, int) ([]model.TransactionExport, error) {
+	return []model.TransactionExport{}, nil
+}
+func (*fakeAPI) GetTransactionExport(context.Context, int, int) (model.TransactionExport, error) {
+	return model.TransactionExport{}, nil
+}
+func (*fakeAPI) DeleteTransactionExport(context.Context, int, int) error { return nil }
+func (*fakeAPI) DownloadTransactionExport(context.Context, int, int, func(model.ExportFile) io.Writer) error {
+	return nil
 }
 func (*fakeAPI) Summary(context.Context, int, string) (model.Summary, error) {
 	return model.Summary{}, nil
@@ -379,9 +606,100 @@ func (*fakeAPI) UpdateTransaction(context.Context, int, int, model.TransactionRe
 	return model.Transaction{}, nil
 }
 func (*fakeAPI) DeleteTransaction(context.Context, int, int) error { return nil }
+func (*fakeAPI) BulkTransactions(context.Context, int, model.BulkTransactionRequest) (model.BulkTransactionResult, error) {
+	return model.BulkTransactionResult{Items: []model.BulkTransactionItemResult{}}, nil
+}
+func (*fakeAPI) ListDuplicateTransactions(context.Context, int, string, string) ([]model.DuplicateTransactionPair, error) {
+	return []model.DuplicateTransactionPair{}, nil
+}
+func (*fakeAPI) MergeTransactions(context.Context, int, model.TransactionMergeRequest) (model.TransactionMergeResult, error) {
+	return model.TransactionMergeResult{}, nil
+}
+func (*fakeAPI) GetTransactionProvenance(context.Context, int, int) ([]model.TransactionProvenance, error) {
+	return []model.TransactionProvenance{}, nil
+}
+func (*fakeAPI) ListMerchants(context.Context, int) ([]model.Merchant, error) {
+	return []model.Merchant{}, nil
+}
+func (*fakeAPI) RenameMerchant(context.Context, int, int, model.MerchantRequest) (model.Merchant, error) {
+	return model.Merchant{ID: 1, Name: "Lidl"}, nil
+}
+func (*fakeAPI) MergeMerchants(context.Context, int, model.MerchantMergeRequest) (model.Merchant, error) {
+	return model.Merchant{ID: 1, Name: "Lidl"}, nil
+}
+func (*fakeAPI) RefreshMerchants(context.Context, int) (model.MerchantRefreshResult, error) {
+	return model.MerchantRefreshResult{}, nil
+}
+func (*fakeAPI) ListMerchantSpending(context.Context, int, string, string) ([]model.MerchantSpending, error) {
+	return []model.MerchantSpending{}, nil
+}
+func (*fakeAPI) GetMerchantSpending(context.Context, int, int, string, string) (model.MerchantSpendingDetail, error) {
+	return model.MerchantSpendingDetail{}, nil
+}
+func (*fakeAPI) ListCategorizationRules(context.Context, int) ([]model.CategorizationRule, error) {
+	return []model.CategorizationRule{}, nil
+}
+func (*fakeAPI) CreateCategorizationRule(context.Context, int, model.CategorizationRuleRequest) (model.CategorizationRule, error) {
+	return model.CategorizationRule{ID: 1, Name: "Happy Bar", Enabled: true}, nil
+}
+func (*fakeAPI) UpdateCategorizationRule(context.Context, int, int, model.CategorizationRuleRequest) (model.CategorizationRule, error) {
+	return model.CategorizationRule{ID: 1, Name: "Happy Bar", Enabled: true}, nil
+}
+func (*fakeAPI) DeleteCategorizationRule(context.Context, int, int) error {
+	return nil
+}
+func (*fakeAPI) RunCategorizationRules(context.Context, int, model.CategorizationRuleRunRequest) (model.CategorizationRuleRunResult, error) {
+	return model.CategorizationRuleRunResult{}, nil
+}
+func (*fakeAPI) GetCategoryModel(context.Context, int) (model.CategoryModel, error) {
+	return model.CategoryModel{Classes: []model.CategoryModelClass{}}, nil
+}
+func (*fakeAPI) PredictCategory(context.Context, int, string, string, string) (model.CategoryPrediction, error) {
+	return model.CategoryPrediction{Type: "expense"}, nil
+}
+func (*fakeAPI) ResetCategoryModel(context.Context, int) (model.CategoryModelReset, error) {
+	return model.CategoryModelReset{}, nil
+}
 func (*fakeAPI) ImportRevolutCSV(context.Context, int, []byte) (model.ImportResult, error) {
 	return model.ImportResult{}, nil
 }
+func (*fakeAPI) DetectCSVImport(context.Context, int, []byte) (model.CSVImportDetection, error) {
+	return model.CSVImportDetection{}, nil
+}
+func (*fakeAPI) ImportCSV(context.Context, int, int, []byte) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) ImportOFX(context.Context, int, []byte) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) ImportQIF(context.Context, int, []byte, string) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) ImportCAMT(context.Context, int, []byte) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) ImportMT940(context.Context, int, []byte) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) PreviewImport(context.Context, int, model.ImportPreviewRequest, []byte) (model.ImportPreview, error) {
+	return model.ImportPreview{Rows: []model.ImportPreviewRow{}}, nil
+}
+func (*fakeAPI) GetImportPreview(context.Context, int, int) (model.ImportPreview, error) {
+	return model.ImportPreview{Rows: []model.ImportPreviewRow{}}, nil
+}
+func (*fakeAPI) CommitImportPreview(context.Context, int, int, model.ImportCommitRequest) (model.StatementImportResult, error) {
+	return model.StatementImportResult{Rejected: []model.ImportRejection{}}, nil
+}
+func (*fakeAPI) ListCSVImportProfiles(context.Context, int) ([]model.CSVImportProfile, error) {
+	return []model.CSVImportProfile{}, nil
+}
+func (*fakeAPI) CreateCSVImportProfile(context.Context, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	return model.CSVImportProfile{ID: 1}, nil
+}
+func (*fakeAPI) UpdateCSVImportProfile(context.Context, int, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	return model.CSVImportProfile{ID: 1}, nil
+}
+func (*fakeAPI) DeleteCSVImportProfile(context.Context, int, int) error { return nil }
 func (*fakeAPI) ListTransactionSchedules(context.Context, int, string) ([]model.TransactionSchedule, error) {
 	return []model.TransactionSchedule{}, nil
 }
@@ -404,6 +722,21 @@ func (*fakeAPI) DeleteTransactionSchedule(context.Context, int, int) error { ret
 func (*fakeAPI) ListTransactionScheduleOccurrences(context.Context, int, string, string, int, string) ([]model.TransactionScheduleOccurrence, error) {
 	return []model.TransactionScheduleOccurrence{}, nil
 }
+func (*fakeAPI) UpdateTransactionScheduleOccurrence(context.Context, int, int, model.ScheduleOccurrenceRequest) (model.TransactionScheduleOccurrence, error) {
+	return model.TransactionScheduleOccurrence{ID: 1, Status: "planned", Overridden: true}, nil
+}
+func (*fakeAPI) SkipTransactionScheduleOccurrence(context.Context, int, int) (model.TransactionScheduleOccurrence, error) {
+	return model.TransactionScheduleOccurrence{ID: 1, Status: "skipped"}, nil
+}
+func (*fakeAPI) UnskipTransactionScheduleOccurrence(context.Context, int, int) (model.TransactionScheduleOccurrence, error) {
+	return model.TransactionScheduleOccurrence{ID: 1, Status: "planned"}, nil
+}
+func (*fakeAPI) ListSubscriptions(context.Context, int) ([]model.Subscription, error) {
+	return []model.Subscription{}, nil
+}
+func (*fakeAPI) ScheduleSubscription(context.Context, int, model.SubscriptionScheduleRequest) (model.TransactionSchedule, error) {
+	return model.TransactionSchedule{ID: 9, Name: "Netflix", Status: "active"}, nil
+}
 func (*fakeAPI) ListBudgets(context.Context, int, bool) ([]model.Budget, error) {
 	return []model.Budget{}, nil
 }
@@ -417,6 +750,99 @@ func (*fakeAPI) UpdateBudget(context.Context, int, int, model.BudgetRequest) (mo
 	return model.Budget{ID: 1, Name: "Food"}, nil
 }
 func (*fakeAPI) DeleteBudget(context.Context, int, int) error { return nil }
+func (*fakeAPI) ListSavingsGoals(context.Context, int, bool) ([]model.SavingsGoal, error) {
+	return []model.SavingsGoal{}, nil
+}
+func (*fakeAPI) GetSavingsGoal(context.Context, int, int) (model.SavingsGoal, error) {
+	return model.SavingsGoal{ID: 1, Name: "Car"}, nil
+}
+func (*fakeAPI) CreateSavingsGoal(context.Context, int, model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	return model.SavingsGoal{ID: 1, Name: "Car"}, nil
+}
+func (*fakeAPI) UpdateSavingsGoal(context.Context, int, int, model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	return model.SavingsGoal{ID: 1, Name: "Car"}, nil
+}
+func (*fakeAPI) DeleteSavingsGoal(context.Context, int, int) error { return nil }
+func (*fakeAPI) ListSavingsGoalContributions(context.Context, int, int) ([]model.SavingsGoalContribution, error) {
+	return []model.SavingsGoalContribution{}, nil
+}
+func (*fakeAPI) AddSavingsGoalContribution(context.Context, int, int, model.SavingsGoalContributionRequest) (model.SavingsGoalContribution, error) {
+	return model.SavingsGoalContribution{ID: 2, Source: "manual"}, nil
+}
+func (f *fakeAPI) DeleteSavingsGoalContribution(_ context.Context, _ int, goalID, contributionID int) error {
+	f.deletedContribution = [2]int{goalID, contributionID}
+	return nil
+}
+func (*fakeAPI) ListLoans(context.Context, int, bool) ([]model.Loan, error) {
+	return []model.Loan{}, nil
+}
+func (*fakeAPI) GetLoan(context.Context, int, int) (model.Loan, error) {
+	return model.Loan{ID: 1, Name: "Mortgage"}, nil
+}
+func (*fakeAPI) CreateLoan(context.Context, int, model.LoanRequest) (model.Loan, error) {
+	return model.Loan{ID: 1, Name: "Mortgage"}, nil
+}
+func (*fakeAPI) UpdateLoan(context.Context, int, int, model.LoanRequest) (model.Loan, error) {
+	return model.Loan{ID: 1, Name: "Mortgage"}, nil
+}
+func (*fakeAPI) DeleteLoan(context.Context, int, int) error { return nil }
+func (*fakeAPI) GetLoanSchedule(context.Context, int, int) ([]model.LoanInstallment, error) {
+	return []model.LoanInstallment{}, nil
+}
+func (f *fakeAPI) ProjectLoanPayoff(_ context.Context, _ int, _ int, extraMonthly, lumpSum string) (model.LoanPayoffProjection, error) {
+	f.loanPayoffQuery = [2]string{extraMonthly, lumpSum}
+	return model.LoanPayoffProjection{}, nil
+}
+func (*fakeAPI) ListLoanPayments(context.Context, int, int) ([]model.LoanPayment, error) {
+	return []model.LoanPayment{}, nil
+}
+func (*fakeAPI) AddLoanPayment(context.Context, int, int, model.LoanPaymentRequest) (model.LoanPayment, error) {
+	return model.LoanPayment{ID: 2, Kind: "extra"}, nil
+}
+func (*fakeAPI) DeleteLoanPayment(context.Context, int, int, int) error { return nil }
+func (*fakeAPI) ListExpenseGroups(context.Context, int, bool) ([]model.ExpenseGroup, error) {
+	return []model.ExpenseGroup{}, nil
+}
+func (*fakeAPI) GetExpenseGroup(context.Context, int, int) (model.ExpenseGroup, error) {
+	return model.ExpenseGroup{ID: 1, Name: "Trip"}, nil
+}
+func (*fakeAPI) CreateExpenseGroup(context.Context, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	return model.ExpenseGroup{ID: 1, Name: "Trip"}, nil
+}
+func (*fakeAPI) UpdateExpenseGroup(context.Context, int, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	return model.ExpenseGroup{ID: 1, Name: "Trip"}, nil
+}
+func (*fakeAPI) DeleteExpenseGroup(context.Context, int, int) error { return nil }
+func (*fakeAPI) AddExpenseGroupMember(context.Context, int, int, model.ExpenseGroupMemberRequest) (model.ExpenseGroupMember, error) {
+	return model.ExpenseGroupMember{ID: 2, Name: "Ana"}, nil
+}
+func (*fakeAPI) DeleteExpenseGroupMember(context.Context, int, int, int) error { return nil }
+func (*fakeAPI) ListSharedExpenses(context.Context, int, int) ([]model.SharedExpense, error) {
+	return []model.SharedExpense{}, nil
+}
+func (*fakeAPI) AddSharedExpense(context.Context, int, int, model.SharedExpenseRequest) (model.SharedExpense, error) {
+	return model.SharedExpense{ID: 2}, nil
+}
+func (*fakeAPI) DeleteSharedExpense(context.Context, int, int, int) error { return nil }
+func (*fakeAPI) ListExpenseSettlements(context.Context, int, int) ([]model.ExpenseSettlement, error) {
+	return []model.ExpenseSettlement{}, nil
+}
+func (*fakeAPI) AddExpenseSettlement(context.Context, int, int, model.ExpenseSettlementRequest) (model.ExpenseSettlement, error) {
+	return model.ExpenseSettlement{ID: 2}, nil
+}
+func (*fakeAPI) DeleteExpenseSettlement(context.Context, int, int, int) error { return nil }
+func (*fakeAPI) GetExpenseGroupBalances(context.Context, int, int) (model.ExpenseGroupBalances, error) {
+	return model.ExpenseGroupBalances{GroupID: 1, Currency: "EUR"}, nil
+}
+func (*fakeAPI) Forecast(context.Context, int, string) (model.Forecast, error) {
+	return model.Forecast{Currency: "EUR"}, nil
+}
+func (*fakeAPI) GetForecastSettings(context.Context, int) (model.ForecastSettings, error) {
+	return model.ForecastSettings{}, nil
+}
+func (*fakeAPI) UpdateForecastSettings(_ context.Context, _ int, settings model.ForecastSettings) (model.ForecastSettings, error) {
+	return settings, nil
+}
 func (*fakeAPI) GetNotificationPreferences(context.Context, int) (model.NotificationPreferences, error) {
 	return model.NotificationPreferences{Timezone: "Europe/Sofia"}, nil
 }
@@ -443,8 +869,9 @@ func (*fakeAPI) InvestmentPortfolioHistory(context.Context, int, string) (model.
 func (*fakeAPI) SetManualInvestmentPrice(context.Context, int, model.InvestmentPriceRequest) (model.InvestmentPrice, error) {
 	return model.InvestmentPrice{Symbol: "BTC", Price: "1.00"}, nil
 }
-func (*fakeAPI) ExportInvestmentTrades(context.Context, int, string, string) ([]model.InvestmentTrade, error) {
-	return []model.InvestmentTrade{}, nil
+func (*fakeAPI) ExportInvestmentTrades(_ context.Context, _ int, request model.InvestmentExportRequest, start func(model.ExportFile) io.Writer) error {
+	_, err := io.WriteString(start(model.ExportFile{Filename: "investments." + request.Format, ContentType: "text/csv"}), "occurred_at\n")
+	return err
 }
 func (*fakeAPI) ListInvestmentSchedules(context.Context, int, string) ([]model.InvestmentSchedule, error) {
 	return []model.InvestmentSchedule{}, nil
@@ -465,6 +892,18 @@ func (*fakeAPI) ResumeInvestmentSchedule(context.Context, int, int) (model.Inves
 	return model.InvestmentSchedule{ID: 1, Symbol: "BTC", Status: "active"}, nil
 }
 func (*fakeAPI) DeleteInvestmentSchedule(context.Context, int, int) error { return nil }
+func (*fakeAPI) ListInvestmentScheduleOccurrences(context.Context, int, string, string, int, string) ([]model.InvestmentScheduleOccurrence, error) {
+	return []model.InvestmentScheduleOccurrence{}, nil
+}
+func (*fakeAPI) UpdateInvestmentScheduleOccurrence(context.Context, int, int, model.InvestmentScheduleOccurrenceRequest) (model.InvestmentScheduleOccurrence, error) {
+	return model.InvestmentScheduleOccurrence{ID: 1, Status: "planned", Overridden: true}, nil
+}
+func (*fakeAPI) SkipInvestmentScheduleOccurrence(context.Context, int, int) (model.InvestmentScheduleOccurrence, error) {
+	return model.InvestmentScheduleOccurrence{ID: 1, Status: "skipped"}, nil
+}
+func (*fakeAPI) UnskipInvestmentScheduleOccurrence(context.Context, int, int) (model.InvestmentScheduleOccurrence, error) {
+	return model.InvestmentScheduleOccurrence{ID: 1, Status: "planned"}, nil
+}
 func (f *fakeAPI) ListOpenBankingInstitutions(context.Context, string, string) ([]model.OpenBankingInstitution, error) {
 	if f.openBankingInstitutions == nil {
 		return []model.OpenBankingInstitution{}, nil
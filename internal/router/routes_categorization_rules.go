@@ -0,0 +1,61 @@
+package router
+
+import (
+	"net/http"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerCategorizationRuleRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /categorization-rules", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		items, err := h.api.ListCategorizationRules(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /categorization-rules", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.CategorizationRuleRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.CreateCategorizationRule(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("PUT /categorization-rules/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, ruleID int) {
+		var payload model.CategorizationRuleRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateCategorizationRule(request.Context(), userID, ruleID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /categorization-rules/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, ruleID int) {
+		if err := h.api.DeleteCategorizationRule(request.Context(), userID, ruleID); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("POST /categorization-rules/run", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.CategorizationRuleRunRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.RunCategorizationRules(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("GET /categorization-model", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		item, err := h.api.GetCategoryModel(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("GET /categorization-model/predict", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		item, err := h.api.PredictCategory(request.Context(), userID, query.Get("type"), query.Get("description"), query.Get("amount"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /categorization-model", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		result, err := h.api.ResetCategoryModel(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+}
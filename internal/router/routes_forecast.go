@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerForecastRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /forecast", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		item, err := h.api.Forecast(request.Context(), userID, request.URL.Query().Get("through"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("GET /forecast/settings", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		item, err := h.api.GetForecastSettings(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("PUT /forecast/settings", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.ForecastSettings
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateForecastSettings(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+}
@@ -0,0 +1,197 @@
+package router
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+)
+
+const maximumStatementUploadBytes = 2 * 1024 * 1024
+
+// statementUpload describes the accepted body of one import format.
+type statementUpload struct {
+	mediaTypes  []string
+	typeMessage string
+	sizeMessage string
+}
+
+var (
+	csvUpload = statementUpload{
+		mediaTypes:  []string{"text/csv", "application/csv", "application/vnd.ms-excel"},
+		typeMessage: "Content-Type must be text/csv", sizeMessage: "CSV file is too large",
+	}
+	statementUploads = map[string]statementUpload{
+		"revolut": csvUpload,
+		"csv":     csvUpload,
+		"ofx": {
+			mediaTypes: []string{
+				"application/x-ofx", "application/ofx", "application/vnd.intu.qfx", "text/plain", "application/octet-stream",
+			},
+			typeMessage: "Content-Type must be application/x-ofx", sizeMessage: "OFX file is too large",
+		},
+		"qif": {
+			mediaTypes:  []string{"application/qif", "application/x-qif", "text/plain", "application/octet-stream"},
+			typeMessage: "Content-Type must be application/qif", sizeMessage: "QIF file is too large",
+		},
+		"camt": {
+			mediaTypes:  []string{"application/xml", "text/xml", "application/octet-stream"},
+			typeMessage: "Content-Type must be application/xml", sizeMessage: "camt file is too large",
+		},
+		"mt940": {
+			mediaTypes:  []string{"text/plain", "application/x-mt940", "application/octet-stream"},
+			typeMessage: "Content-Type must be text/plain", sizeMessage: "MT940 file is too large",
+		},
+	}
+)
+
+func (h *handler) registerImportRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /transactions/import/csv/detect", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		contents, err := readCSVUpload(w, request)
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		detection, err := h.api.DetectCSVImport(request.Context(), userID, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, detection, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/csv", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		profileID, err := parseID(strings.TrimSpace(request.URL.Query().Get("profile_id")))
+		if err != nil {
+			writeError(w, request, h.options.Logger, apperrors.Validation("profile_id must be a positive integer"))
+			return
+		}
+		contents, err := readCSVUpload(w, request)
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.ImportCSV(request.Context(), userID, profileID, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/ofx", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		contents, err := readStatementUpload(w, request, statementUploads["ofx"])
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.ImportOFX(request.Context(), userID, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/qif", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		contents, err := readStatementUpload(w, request, statementUploads["qif"])
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.ImportQIF(request.Context(), userID, contents, request.URL.Query().Get("date_format"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/camt", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		contents, err := readStatementUpload(w, request, statementUploads["camt"])
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.ImportCAMT(request.Context(), userID, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/mt940", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		contents, err := readStatementUpload(w, request, statementUploads["mt940"])
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.ImportMT940(request.Context(), userID, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/{format}/preview", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		format := request.PathValue("format")
+		upload, ok := statementUploads[format]
+		if !ok {
+			writeError(w, request, h.options.Logger, apperrors.NotFound("import format not found"))
+			return
+		}
+		payload := model.ImportPreviewRequest{Format: format, DateFormat: request.URL.Query().Get("date_format")}
+		if format == "csv" {
+			profileID, err := parseID(strings.TrimSpace(request.URL.Query().Get("profile_id")))
+			if err != nil {
+				writeError(w, request, h.options.Logger, apperrors.Validation("profile_id must be a positive integer"))
+				return
+			}
+			payload.ProfileID = profileID
+		}
+		contents, err := readStatementUpload(w, request, upload)
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		preview, err := h.api.PreviewImport(request.Context(), userID, payload, contents)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, preview, err)
+	}))
+	mux.HandleFunc("GET /transactions/import/previews/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, previewID int) {
+		preview, err := h.api.GetImportPreview(request.Context(), userID, previewID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, preview, err)
+	}))
+	mux.HandleFunc("POST /transactions/import/previews/{id}/commit", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, previewID int) {
+		var payload model.ImportCommitRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.CommitImportPreview(request.Context(), userID, previewID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("GET /csv-import-profiles", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		items, err := h.api.ListCSVImportProfiles(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /csv-import-profiles", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.CSVImportProfileRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.CreateCSVImportProfile(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("PUT /csv-import-profiles/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, profileID int) {
+		var payload model.CSVImportProfileRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateCSVImportProfile(request.Context(), userID, profileID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /csv-import-profiles/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, profileID int) {
+		if err := h.api.DeleteCSVImportProfile(request.Context(), userID, profileID); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func readCSVUpload(w http.ResponseWriter, request *http.Request) ([]byte, error) {
+	return readStatementUpload(w, request, csvUpload)
+}
+
+// readStatementUpload bounds raw statement uploads. They are read whole
+// because every supported format needs the complete file before parsing.
+func readStatementUpload(w http.ResponseWriter, request *http.Request, upload statementUpload) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil || !slices.Contains(upload.mediaTypes, mediaType) {
+		return nil, apperrors.Validation(upload.typeMessage)
+	}
+	request.Body = http.MaxBytesReader(w, request.Body, maximumStatementUploadBytes)
+	contents, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, apperrors.Validation(upload.sizeMessage)
+	}
+	return contents, nil
+}
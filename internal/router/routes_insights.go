@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerInsightRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /insights/subscriptions", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		items, err := h.api.ListSubscriptions(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /insights/subscriptions/schedule", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.SubscriptionScheduleRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.ScheduleSubscription(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+}
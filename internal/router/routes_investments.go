@@ -1,10 +1,10 @@
 package router
 
 import (
-	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
-	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/model"
 )
 
@@ -51,18 +51,11 @@ func (h *handler) registerInvestmentRoutes(mux *http.ServeMux) {
 		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
 	}))
 	mux.HandleFunc("GET /investments/export", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
-		from, through := request.URL.Query().Get("from"), request.URL.Query().Get("through")
-		items, err := h.api.ExportInvestmentTrades(request.Context(), userID, from, through)
-		if err != nil {
-			writeError(w, request, h.options.Logger, err)
-			return
-		}
-		contents, err := investmentTradesCSV(items)
-		if err != nil {
-			writeError(w, request, h.options.Logger, apperrors.Internal(fmt.Errorf("encode investment CSV: %w", err)))
-			return
-		}
-		writeCSV(w, fmt.Sprintf("money-manager-investments-%s-to-%s.csv", from, through), contents)
+		query := request.URL.Query()
+		payload := model.InvestmentExportRequest{From: query.Get("from"), Through: query.Get("through"), Format: query.Get("format")}
+		h.streamFile(w, request, func(start func(model.ExportFile) io.Writer) error {
+			return h.api.ExportInvestmentTrades(request.Context(), userID, payload, start)
+		})
 	}))
 }
 
@@ -108,4 +101,37 @@ func (h *handler) registerInvestmentScheduleRoutes(mux *http.ServeMux) {
 		}
 		w.WriteHeader(http.StatusNoContent)
 	}))
+	mux.HandleFunc("GET /investment-schedule-occurrences", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		scheduleID := 0
+		var err error
+		if rawScheduleID := strings.TrimSpace(query.Get("schedule_id")); rawScheduleID != "" {
+			scheduleID, err = parseID(rawScheduleID)
+		}
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		items, err := h.api.ListInvestmentScheduleOccurrences(
+			request.Context(), userID, query.Get("from"), query.Get("through"), scheduleID, query.Get("status"),
+		)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("PUT /investment-schedule-occurrences/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		var payload model.InvestmentScheduleOccurrenceRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateInvestmentScheduleOccurrence(request.Context(), userID, occurrenceID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /investment-schedule-occurrences/{id}/skip", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		item, err := h.api.SkipInvestmentScheduleOccurrence(request.Context(), userID, occurrenceID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /investment-schedule-occurrences/{id}/unskip", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		item, err := h.api.UnskipInvestmentScheduleOccurrence(request.Context(), userID, occurrenceID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
 }
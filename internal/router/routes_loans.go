@@ -0,0 +1,80 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerLoanRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /loans", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		includeArchived := strings.EqualFold(request.URL.Query().Get("include_archived"), "true")
+		items, err := h.api.ListLoans(request.Context(), userID, includeArchived)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /loans", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.LoanRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.CreateLoan(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("GET /loans/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		item, err := h.api.GetLoan(request.Context(), userID, loanID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("PUT /loans/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		var payload model.LoanRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateLoan(request.Context(), userID, loanID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /loans/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		if err := h.api.DeleteLoan(request.Context(), userID, loanID); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /loans/{id}/schedule", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		items, err := h.api.GetLoanSchedule(request.Context(), userID, loanID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("GET /loans/{id}/payoff", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		query := request.URL.Query()
+		item, err := h.api.ProjectLoanPayoff(request.Context(), userID, loanID, query.Get("extra_monthly"), query.Get("lump_sum"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("GET /loans/{id}/payments", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		items, err := h.api.ListLoanPayments(request.Context(), userID, loanID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /loans/{id}/payments", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+		var payload model.LoanPaymentRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.AddLoanPayment(request.Context(), userID, loanID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("DELETE /loans/{id}/payments/{payment_id}", h.requireUserResource(
+		func(w http.ResponseWriter, request *http.Request, userID, loanID int) {
+			paymentID, err := parseID(request.PathValue("payment_id"))
+			if err == nil {
+				err = h.api.DeleteLoanPayment(request.Context(), userID, loanID, paymentID)
+			}
+			if err != nil {
+				writeError(w, request, h.options.Logger, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	))
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"net/http"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerMerchantRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /merchants", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		items, err := h.api.ListMerchants(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("PUT /merchants/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, merchantID int) {
+		var payload model.MerchantRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.RenameMerchant(request.Context(), userID, merchantID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /merchants/merge", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.MerchantMergeRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.MergeMerchants(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /merchants/refresh", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		result, err := h.api.RefreshMerchants(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("GET /merchants/spending", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		items, err := h.api.ListMerchantSpending(request.Context(), userID, query.Get("from"), query.Get("to"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("GET /merchants/{id}/spending", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, merchantID int) {
+		query := request.URL.Query()
+		item, err := h.api.GetMerchantSpending(request.Context(), userID, merchantID, query.Get("from"), query.Get("to"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+}
@@ -65,6 +65,23 @@ func (h *handler) registerTransactionScheduleRoutes(mux *http.ServeMux) {
 		)
 		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
 	}))
+	mux.HandleFunc("PUT /schedule-occurrences/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		var payload model.ScheduleOccurrenceRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateTransactionScheduleOccurrence(request.Context(), userID, occurrenceID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /schedule-occurrences/{id}/skip", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		item, err := h.api.SkipTransactionScheduleOccurrence(request.Context(), userID, occurrenceID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /schedule-occurrences/{id}/unskip", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, occurrenceID int) {
+		item, err := h.api.UnskipTransactionScheduleOccurrence(request.Context(), userID, occurrenceID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
 }
 
 func (h *handler) registerBudgetRoutes(mux *http.ServeMux) {
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerReportRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /reports/cash-flow", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		payload, err := reportRequest(request.URL.Query())
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		report, err := h.api.CashFlowReport(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, report, err)
+	}))
+	mux.HandleFunc("GET /reports/categories", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		payload, err := reportRequest(request.URL.Query())
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		report, err := h.api.CategoryReport(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, report, err)
+	}))
+	mux.HandleFunc("GET /reports/merchants", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		payload, err := reportRequest(request.URL.Query())
+		if err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		report, err := h.api.MerchantReport(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, report, err)
+	}))
+	mux.HandleFunc("GET /reports/annual", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		switch query.Get("format") {
+		case "", "json":
+			report, err := h.api.AnnualReport(request.Context(), userID, query.Get("year"))
+			writeJSONResult(w, request, h.options.Logger, http.StatusOK, report, err)
+		case "pdf":
+			h.streamFile(w, request, func(start func(model.ExportFile) io.Writer) error {
+				return h.api.ExportAnnualReport(request.Context(), userID, query.Get("year"), start)
+			})
+		default:
+			writeError(w, request, h.options.Logger, apperrors.Validation("format must be json or pdf"))
+		}
+	}))
+}
+
+func reportRequest(query url.Values) (model.ReportRequest, error) {
+	payload := model.ReportRequest{From: query.Get("from"), To: query.Get("to"), Type: query.Get("type")}
+	if rawLimit := strings.TrimSpace(query.Get("limit")); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return model.ReportRequest{}, apperrors.Validation("limit must be a positive integer")
+		}
+		payload.Limit = limit
+	}
+	return payload, nil
+}
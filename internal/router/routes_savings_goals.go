@@ -0,0 +1,71 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerSavingsGoalRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /savings-goals", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		includeArchived := strings.EqualFold(request.URL.Query().Get("include_archived"), "true")
+		items, err := h.api.ListSavingsGoals(request.Context(), userID, includeArchived)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /savings-goals", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.SavingsGoalRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.CreateSavingsGoal(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("GET /savings-goals/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+		item, err := h.api.GetSavingsGoal(request.Context(), userID, goalID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("PUT /savings-goals/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+		var payload model.SavingsGoalRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateSavingsGoal(request.Context(), userID, goalID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /savings-goals/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+		if err := h.api.DeleteSavingsGoal(request.Context(), userID, goalID); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /savings-goals/{id}/contributions", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+		items, err := h.api.ListSavingsGoalContributions(request.Context(), userID, goalID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /savings-goals/{id}/contributions", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+		var payload model.SavingsGoalContributionRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.AddSavingsGoalContribution(request.Context(), userID, goalID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("DELETE /savings-goals/{id}/contributions/{contribution_id}", h.requireUserResource(
+		func(w http.ResponseWriter, request *http.Request, userID, goalID int) {
+			contributionID, err := parseID(request.PathValue("contribution_id"))
+			if err == nil {
+				err = h.api.DeleteSavingsGoalContribution(request.Context(), userID, goalID, contributionID)
+			}
+			if err != nil {
+				writeError(w, request, h.options.Logger, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	))
+}
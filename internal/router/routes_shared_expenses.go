@@ -0,0 +1,123 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"money-manager-server/internal/model"
+)
+
+func (h *handler) registerSharedExpenseRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /expense-groups", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		includeArchived := strings.EqualFold(request.URL.Query().Get("include_archived"), "true")
+		items, err := h.api.ListExpenseGroups(request.Context(), userID, includeArchived)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /expense-groups", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.ExpenseGroupRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.CreateExpenseGroup(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("GET /expense-groups/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		item, err := h.api.GetExpenseGroup(request.Context(), userID, groupID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("PUT /expense-groups/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		var payload model.ExpenseGroupRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.UpdateExpenseGroup(request.Context(), userID, groupID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("DELETE /expense-groups/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		if err := h.api.DeleteExpenseGroup(request.Context(), userID, groupID); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /expense-groups/{id}/balances", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		item, err := h.api.GetExpenseGroupBalances(request.Context(), userID, groupID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("POST /expense-groups/{id}/members", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		var payload model.ExpenseGroupMemberRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.AddExpenseGroupMember(request.Context(), userID, groupID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("GET /expense-groups/{id}/expenses", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		items, err := h.api.ListSharedExpenses(request.Context(), userID, groupID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /expense-groups/{id}/expenses", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		var payload model.SharedExpenseRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.AddSharedExpense(request.Context(), userID, groupID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("GET /expense-groups/{id}/settlements", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		items, err := h.api.ListExpenseSettlements(request.Context(), userID, groupID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("POST /expense-groups/{id}/settlements", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+		var payload model.ExpenseSettlementRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		item, err := h.api.AddExpenseSettlement(request.Context(), userID, groupID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, item, err)
+	}))
+	mux.HandleFunc("DELETE /expense-groups/{id}/members/{member_id}", h.requireUserResource(
+		func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+			memberID, err := parseID(request.PathValue("member_id"))
+			if err == nil {
+				err = h.api.DeleteExpenseGroupMember(request.Context(), userID, groupID, memberID)
+			}
+			if err != nil {
+				writeError(w, request, h.options.Logger, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	))
+	mux.HandleFunc("DELETE /expense-groups/{id}/expenses/{expense_id}", h.requireUserResource(
+		func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+			expenseID, err := parseID(request.PathValue("expense_id"))
+			if err == nil {
+				err = h.api.DeleteSharedExpense(request.Context(), userID, groupID, expenseID)
+			}
+			if err != nil {
+				writeError(w, request, h.options.Logger, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	))
+	mux.HandleFunc("DELETE /expense-groups/{id}/settlements/{settlement_id}", h.requireUserResource(
+		func(w http.ResponseWriter, request *http.Request, userID, groupID int) {
+			settlementID, err := parseID(request.PathValue("settlement_id"))
+			if err == nil {
+				err = h.api.DeleteExpenseSettlement(request.Context(), userID, groupID, settlementID)
+			}
+			if err != nil {
+				writeError(w, request, h.options.Logger, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+	))
+}
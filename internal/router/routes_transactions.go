@@ -1,12 +1,9 @@
 package router
 
 import (
-	"fmt"
 	"io"
-	"mime"
 	"net/http"
 
-	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/model"
 )
 
@@ -24,6 +21,24 @@ func (h *handler) registerCategoryRoutes(mux *http.ServeMux) {
 		category, err := h.api.CreateCategory(request.Context(), userID, payload)
 		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, category, err)
 	}))
+	mux.HandleFunc("PUT /categories/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, categoryID int) {
+		var payload model.CategoryUpdateRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		category, err := h.api.UpdateCategory(request.Context(), userID, categoryID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, category, err)
+	}))
+	mux.HandleFunc("POST /categories/{id}/merge", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, categoryID int) {
+		var payload model.CategoryMergeRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.MergeCategory(request.Context(), userID, categoryID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
 	mux.HandleFunc("DELETE /categories/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, categoryID int) {
 		if err := h.api.DeleteCategory(request.Context(), userID, categoryID); err != nil {
 			writeError(w, request, h.options.Logger, err)
@@ -42,29 +57,52 @@ func (h *handler) registerTransactionRoutes(mux *http.ServeMux) {
 		writeJSONResult(w, request, h.options.Logger, http.StatusOK, transactions, err)
 	}))
 	mux.HandleFunc("GET /transactions/export", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
-		from, to := request.URL.Query().Get("from"), request.URL.Query().Get("to")
-		transactions, err := h.api.ExportTransactions(request.Context(), userID, from, to)
-		if err != nil {
+		query := request.URL.Query()
+		payload := model.TransactionExportRequest{From: query.Get("from"), To: query.Get("to"), Format: query.Get("format")}
+		h.streamFile(w, request, func(start func(model.ExportFile) io.Writer) error {
+			return h.api.ExportTransactions(request.Context(), userID, payload, start)
+		})
+	}))
+	mux.HandleFunc("GET /exports/journal", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		payload := model.TransactionExportRequest{From: query.Get("from"), To: query.Get("to"), Format: query.Get("format")}
+		h.streamFile(w, request, func(start func(model.ExportFile) io.Writer) error {
+			return h.api.ExportJournal(request.Context(), userID, payload, start)
+		})
+	}))
+	mux.HandleFunc("POST /transaction-exports", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.TransactionExportRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
 			writeError(w, request, h.options.Logger, err)
 			return
 		}
-		contents, err := transactionsCSV(transactions)
-		if err != nil {
-			writeError(w, request, h.options.Logger, apperrors.Internal(fmt.Errorf("encode CSV: %w", err)))
+		item, err := h.api.CreateTransactionExport(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusAccepted, item, err)
+	}))
+	mux.HandleFunc("GET /transaction-exports", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		items, err := h.api.ListTransactionExports(request.Context(), userID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, items, err)
+	}))
+	mux.HandleFunc("GET /transaction-exports/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, exportID int) {
+		item, err := h.api.GetTransactionExport(request.Context(), userID, exportID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, item, err)
+	}))
+	mux.HandleFunc("GET /transaction-exports/{id}/download", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, exportID int) {
+		h.streamFile(w, request, func(start func(model.ExportFile) io.Writer) error {
+			return h.api.DownloadTransactionExport(request.Context(), userID, exportID, start)
+		})
+	}))
+	mux.HandleFunc("DELETE /transaction-exports/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, exportID int) {
+		if err := h.api.DeleteTransactionExport(request.Context(), userID, exportID); err != nil {
+			writeError(w, request, h.options.Logger, err)
 			return
 		}
-		writeCSV(w, fmt.Sprintf("money-manager-%s-to-%s.csv", from, to), contents)
+		w.WriteHeader(http.StatusNoContent)
 	}))
 	mux.HandleFunc("POST /transactions/import/revolut", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
-		mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
-		if err != nil || (mediaType != "text/csv" && mediaType != "application/csv" && mediaType != "application/vnd.ms-excel") {
-			writeError(w, request, h.options.Logger, apperrors.Validation("Content-Type must be text/csv"))
-			return
-		}
-		request.Body = http.MaxBytesReader(w, request.Body, 2*1024*1024)
-		contents, err := io.ReadAll(request.Body)
+		contents, err := readCSVUpload(w, request)
 		if err != nil {
-			writeError(w, request, h.options.Logger, apperrors.Validation("CSV file is too large"))
+			writeError(w, request, h.options.Logger, err)
 			return
 		}
 		result, err := h.api.ImportRevolutCSV(request.Context(), userID, contents)
@@ -83,6 +121,33 @@ func (h *handler) registerTransactionRoutes(mux *http.ServeMux) {
 		transaction, err := h.api.CreateTransaction(request.Context(), userID, payload)
 		writeJSONResult(w, request, h.options.Logger, http.StatusCreated, transaction, err)
 	}))
+	mux.HandleFunc("POST /transactions/bulk", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.BulkTransactionRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.BulkTransactions(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("GET /transactions/duplicates", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		query := request.URL.Query()
+		pairs, err := h.api.ListDuplicateTransactions(request.Context(), userID, query.Get("from"), query.Get("to"))
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, pairs, err)
+	}))
+	mux.HandleFunc("POST /transactions/duplicates/merge", h.requireUser(func(w http.ResponseWriter, request *http.Request, userID int) {
+		var payload model.TransactionMergeRequest
+		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
+			writeError(w, request, h.options.Logger, err)
+			return
+		}
+		result, err := h.api.MergeTransactions(request.Context(), userID, payload)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, result, err)
+	}))
+	mux.HandleFunc("GET /transactions/{id}/provenance", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, transactionID int) {
+		provenance, err := h.api.GetTransactionProvenance(request.Context(), userID, transactionID)
+		writeJSONResult(w, request, h.options.Logger, http.StatusOK, provenance, err)
+	}))
 	mux.HandleFunc("PUT /transactions/{id}", h.requireUserResource(func(w http.ResponseWriter, request *http.Request, userID, transactionID int) {
 		var payload model.TransactionRequest
 		if err := decodeJSON(w, request, &payload, h.options.RequestBodyLimit); err != nil {
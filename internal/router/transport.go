@@ -10,6 +10,7 @@ import (
 	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
 	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/model"
@@ -116,9 +117,44 @@ func writeText(w http.ResponseWriter, status int, value string) {
 	_, _ = io.WriteString(w, value)
 }
 
-func writeCSV(w http.ResponseWriter, filename string, contents []byte) {
-	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(contents)
+// streamWriteWindow is how long a streamed download may wait on one write.
+// The deadline moves with every write, so long downloads outlive the server's
+// write timeout as long as the client keeps reading.
+const streamWriteWindow = time.Minute
+
+// streamFile runs a producer that writes a download. Errors raised before the
+// producer starts the file are written as JSON; later ones abort the
+// connection so a truncated file is never mistaken for a complete one.
+func (h *handler) streamFile(
+	w http.ResponseWriter,
+	request *http.Request,
+	produce func(start func(model.ExportFile) io.Writer) error,
+) {
+	started := false
+	err := produce(func(file model.ExportFile) io.Writer {
+		started = true
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+		w.WriteHeader(http.StatusOK)
+		return &streamWriter{w: w, controller: http.NewResponseController(w)}
+	})
+	if err == nil {
+		return
+	}
+	if !started {
+		writeError(w, request, h.options.Logger, err)
+		return
+	}
+	h.options.Logger.ErrorContext(request.Context(), "streamed download failed", "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+type streamWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+func (s *streamWriter) Write(contents []byte) (int, error) {
+	_ = s.controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+	return s.w.Write(contents)
 }
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/export"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	earliestAnnualReportYear = 1970
+	annualReportTopItems     = 5
+)
+
+// AnnualReport summarizes a calendar year, the current one by default, from
+// the ledger, budgets and investment trades.
+func (s *Service) AnnualReport(ctx context.Context, userID int, year string) (model.AnnualReport, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.AnnualReport{}, apperrors.Internal(err)
+	}
+	selected := today.Year()
+	if strings.TrimSpace(year) != "" {
+		selected, err = strconv.Atoi(strings.TrimSpace(year))
+		if err != nil || selected < earliestAnnualReportYear || selected > today.Year() {
+			return model.AnnualReport{}, apperrors.Validation(
+				fmt.Sprintf("year must be between %d and %d", earliestAnnualReportYear, today.Year()),
+			)
+		}
+	}
+	return cachedReport(ctx, s, userID, []string{"annual", strconv.Itoa(selected)}, func() (model.AnnualReport, error) {
+		return s.annualReport(ctx, userID, selected, today)
+	})
+}
+
+// ExportAnnualReport renders the annual report as a PDF.
+func (s *Service) ExportAnnualReport(
+	ctx context.Context,
+	userID int,
+	year string,
+	start func(model.ExportFile) io.Writer,
+) error {
+	report, err := s.AnnualReport(ctx, userID, year)
+	if err != nil {
+		return err
+	}
+	w := start(model.ExportFile{
+		Filename:    fmt.Sprintf("year-in-review-%d.pdf", report.Year),
+		ContentType: export.PDFContentType,
+	})
+	if err := export.WriteAnnualReportPDF(w, report, s.now()); err != nil {
+		return apperrors.Internal(fmt.Errorf("write annual report: %w", err))
+	}
+	return nil
+}
+
+func (s *Service) annualReport(ctx context.Context, userID, year int, today time.Time) (model.AnnualReport, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	if thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC); thisMonth.Before(to) {
+		to = thisMonth.AddDate(0, 1, 0)
+	}
+	reportRange := repository.ReportRange{
+		From: from, To: to,
+		PreviousFrom: from.AddDate(-1, 0, 0), PreviousTo: to.AddDate(-1, 0, 0),
+		LastYearFrom: from.AddDate(-1, 0, 0), LastYearTo: to.AddDate(-1, 0, 0),
+	}
+	report := model.AnnualReport{Year: year, Currency: supportedCurrency}
+
+	cashFlow, err := s.store.CashFlowReport(ctx, userID, reportRange)
+	if err != nil {
+		return model.AnnualReport{}, apperrors.Internal(fmt.Errorf("build annual cash flow: %w", err))
+	}
+	report.Totals, report.Months = cashFlow.Total, cashFlow.Months
+	income, incomeOK := new(big.Rat).SetString(report.Totals.Income)
+	net, netOK := new(big.Rat).SetString(report.Totals.Net)
+	if incomeOK && netOK && income.Sign() > 0 {
+		rate := new(big.Rat).Mul(new(big.Rat).Quo(net, income), big.NewRat(100, 1))
+		report.SavingsRatePercent = formatRat(rate, 1)
+	}
+	highest := new(big.Rat)
+	for index, month := range report.Months {
+		if expense, ok := new(big.Rat).SetString(month.Expense); ok && expense.Cmp(highest) > 0 {
+			highest, report.TopSpendingMonth = expense, &report.Months[index]
+		}
+	}
+
+	categories, err := s.store.CategoryReport(ctx, userID, "expense", reportRange)
+	if err != nil {
+		return model.AnnualReport{}, apperrors.Internal(fmt.Errorf("build annual category totals: %w", err))
+	}
+	report.TopCategories = make([]model.CategoryShare, 0, annualReportTopItems)
+	for _, category := range categories.Categories[:min(len(categories.Categories), annualReportTopItems)] {
+		report.TopCategories = append(report.TopCategories, category.CategoryShare)
+	}
+	merchants, err := s.store.MerchantReport(ctx, userID, reportRange, annualReportTopItems)
+	if err != nil {
+		return model.AnnualReport{}, apperrors.Internal(fmt.Errorf("build annual merchant totals: %w", err))
+	}
+	report.TopMerchants = merchants.Merchants
+
+	if report.Subscriptions, err = s.annualSubscriptions(ctx, userID, from, to); err != nil {
+		return model.AnnualReport{}, err
+	}
+	if report.Investments, err = s.annualInvestments(ctx, userID, from, to); err != nil {
+		return model.AnnualReport{}, err
+	}
+	budgetsThrough := to
+	if today.Before(budgetsThrough) {
+		budgetsThrough = today
+	}
+	if report.Budgets, err = s.store.BudgetHitRate(ctx, userID, from, budgetsThrough); err != nil {
+		return model.AnnualReport{}, apperrors.Internal(fmt.Errorf("count annual budget periods: %w", err))
+	}
+	return report, nil
+}
+
+// annualSubscriptions detects recurring charges within the year alone, so a
+// subscription cancelled during the year still counts for the months it ran.
+func (s *Service) annualSubscriptions(ctx context.Context, userID int, from, to time.Time) (model.AnnualSubscriptions, error) {
+	groups, err := s.subscriptionCharges(ctx, userID, from, to)
+	if err != nil {
+		return model.AnnualSubscriptions{}, err
+	}
+	total := new(big.Rat)
+	result := model.AnnualSubscriptions{}
+	for merchant, charges := range groups {
+		if item, ok := detectSubscription(merchant, charges, time.Time{}); ok {
+			result.Count++
+			total.Add(total, item.charged)
+		}
+	}
+	result.Total = formatRat(total, 2)
+	return result, nil
+}
+
+// annualInvestments replays the whole trade history up to the end of the
+// year, as calculateInvestmentPortfolio does, so sales in the year are
+// measured against the average cost carried into it.
+func (s *Service) annualInvestments(ctx context.Context, userID int, from, to time.Time) (model.AnnualInvestments, error) {
+	trades, err := s.store.ListInvestmentTrades(ctx, userID, repository.InvestmentTradeFilter{
+		Through: to, Limit: maximumInvestmentTradeRows + 1,
+	})
+	if err != nil {
+		return model.AnnualInvestments{}, apperrors.Internal(fmt.Errorf("list annual trades: %w", err))
+	}
+	if len(trades) > maximumInvestmentTradeRows {
+		return model.AnnualInvestments{}, apperrors.Validation("portfolio contains more than 10000 trades")
+	}
+	supportedTrades, _, err := supportedInvestmentTrades(trades, true)
+	if err != nil {
+		return model.AnnualInvestments{}, apperrors.Internal(err)
+	}
+	fromDate := from.Format(time.DateOnly)
+	result := model.AnnualInvestments{}
+	contributions, realized := new(big.Rat), new(big.Rat)
+	_, _, err = walkInvestmentLedger(supportedTrades, func(step investmentLedgerStep) {
+		if step.trade.OccurredAt < fromDate {
+			return
+		}
+		result.Trades++
+		if step.proceeds == nil {
+			contributions.Add(contributions, step.cost)
+		} else {
+			realized.Add(realized, new(big.Rat).Sub(step.proceeds, step.cost))
+		}
+	})
+	if err != nil {
+		return model.AnnualInvestments{}, err
+	}
+	result.Contributions, result.RealizedProfit = formatRat(contributions, 2), formatRat(realized, 2)
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestAnnualReportCombinesLedgerBudgetsAndTrades(t *testing.T) {
+	var cashFlowRange repository.ReportRange
+	var budgetsFrom, budgetsTo, tradesThrough time.Time
+	store := &fakeStore{
+		cashFlowReport: func(_ context.Context, _ int, reportRange repository.ReportRange) (model.CashFlowReport, error) {
+			cashFlowRange = reportRange
+			report := model.CashFlowReport{Total: model.CashFlowTotals{Income: "3000.00", Expense: "1200.00", Net: "1800.00"}}
+			for month, expense := range []string{"100.00", "350.00", "350.00", "0.00"} {
+				report.Months = append(report.Months, model.CashFlowMonth{
+					Month: fmt.Sprintf("2026-%02d", month+1), CashFlowTotals: model.CashFlowTotals{Expense: expense},
+				})
+			}
+			return report, nil
+		},
+		categoryReport: func(context.Context, int, string, repository.ReportRange) (model.CategoryReport, error) {
+			report := model.CategoryReport{}
+			for _, name := range []string{"housing", "groceries", "transport", "dining", "travel", "gifts"} {
+				report.Categories = append(report.Categories, model.CategoryReportItem{CategoryShare: model.CategoryShare{Category: name}})
+			}
+			return report, nil
+		},
+		merchantReport: func(_ context.Context, _ int, _ repository.ReportRange, limit int) (model.MerchantReport, error) {
+			if limit != annualReportTopItems {
+				t.Fatalf("merchant limit = %d", limit)
+			}
+			return model.MerchantReport{Merchants: []model.MerchantReportItem{{Name: "Lidl", Amount: "400.00"}}}, nil
+		},
+		streamTransactions: func(_ context.Context, _ int, from, to time.Time, visit func(model.Transaction) error) error {
+			if !from.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("subscription window = %s..%s", from, to)
+			}
+			// A streaming plan cancelled in April still counts for the year.
+			for month := 1; month <= 4; month++ {
+				if err := visit(model.Transaction{
+					Type: "expense", Description: "NETFLIX.COM", Amount: "12.99", Currency: "EUR",
+					OccurredAt: fmt.Sprintf("2026-%02d-05", month),
+				}); err != nil {
+					return err
+				}
+			}
+			return visit(model.Transaction{Type: "expense", Description: "Hardware store", Amount: "80.00", Currency: "EUR", OccurredAt: "2026-02-11"})
+		},
+		listInvestmentTrades: func(_ context.Context, _ int, filter repository.InvestmentTradeFilter) ([]model.InvestmentTrade, error) {
+			tradesThrough = filter.Through
+			return []model.InvestmentTrade{
+				{ID: 1, AssetType: "crypto", Symbol: "BTC", Broker: "manual", Side: "buy", Quantity: "1", PricePerUnit: "100", Fees: "0", OccurredAt: "2025-11-02"},
+				{ID: 2, AssetType: "crypto", Symbol: "BTC", Broker: "manual", Side: "buy", Quantity: "1", PricePerUnit: "200", Fees: "1", OccurredAt: "2026-03-02"},
+				{ID: 3, AssetType: "crypto", Symbol: "BTC", Broker: "manual", Side: "sell", Quantity: "1", PricePerUnit: "300", Fees: "0", OccurredAt: "2026-05-02"},
+			}, nil
+		},
+		budgetHitRate: func(_ context.Context, _ int, from, to time.Time) (model.AnnualBudgets, error) {
+			budgetsFrom, budgetsTo = from, to
+			return model.AnnualBudgets{Periods: 6, WithinBudget: 4, HitRatePercent: "66.7"}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	report, err := service.AnnualReport(context.Background(), 7, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	january := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	august := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	if !cashFlowRange.From.Equal(january) || !cashFlowRange.To.Equal(august) ||
+		!cashFlowRange.LastYearFrom.Equal(january.AddDate(-1, 0, 0)) || !tradesThrough.Equal(august) ||
+		!budgetsFrom.Equal(january) || !budgetsTo.Equal(time.Date(2026, 7, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("ranges: cash flow %#v, trades through %s, budgets %s..%s", cashFlowRange, tradesThrough, budgetsFrom, budgetsTo)
+	}
+	if report.Year != 2026 || report.Currency != "EUR" || report.SavingsRatePercent != "60.0" ||
+		report.TopSpendingMonth == nil || report.TopSpendingMonth.Month != "2026-02" {
+		t.Fatalf("annual totals = %#v", report)
+	}
+	if len(report.TopCategories) != annualReportTopItems || report.TopCategories[0].Category != "housing" ||
+		len(report.TopMerchants) != 1 || report.TopMerchants[0].Name != "Lidl" {
+		t.Fatalf("top categories and merchants = %#v %#v", report.TopCategories, report.TopMerchants)
+	}
+	if report.Subscriptions != (model.AnnualSubscriptions{Count: 1, Total: "51.96"}) {
+		t.Fatalf("subscriptions = %#v", report.Subscriptions)
+	}
+	if report.Investments != (model.AnnualInvestments{Trades: 2, Contributions: "201.00", RealizedProfit: "149.50"}) {
+		t.Fatalf("investments = %#v", report.Investments)
+	}
+	if report.Budgets.HitRatePercent != "66.7" {
+		t.Fatalf("budgets = %#v", report.Budgets)
+	}
+
+	var file model.ExportFile
+	var output bytes.Buffer
+	if err := service.ExportAnnualReport(context.Background(), 7, "2026", func(started model.ExportFile) io.Writer {
+		file = started
+		return &output
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if file.Filename != "year-in-review-2026.pdf" || file.ContentType != "application/pdf" ||
+		!strings.HasPrefix(output.String(), "%PDF-") || !strings.Contains(output.String(), "(Year in review 2026)") {
+		t.Fatalf("PDF export = %#v %q", file, output.String()[:min(output.Len(), 40)])
+	}
+
+	for _, year := range []string{"2027", "1969", "last"} {
+		if _, err := service.AnnualReport(context.Background(), 7, year); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("AnnualReport(%q) error = %v", year, err)
+		}
+	}
+}
+
+func TestAnnualReportForAPastYearCoversTheWholeYear(t *testing.T) {
+	var budgetsTo time.Time
+	store := &fakeStore{budgetHitRate: func(_ context.Context, _ int, _, to time.Time) (model.AnnualBudgets, error) {
+		budgetsTo = to
+		return model.AnnualBudgets{}, nil
+	}}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	report, err := service.AnnualReport(context.Background(), 7, "2025")
+	if err != nil || report.Year != 2025 || report.SavingsRatePercent != "" || report.TopSpendingMonth != nil ||
+		report.Subscriptions.Total != "0.00" || report.Investments.RealizedProfit != "0.00" {
+		t.Fatalf("empty past year = %#v, %v", report, err)
+	}
+	if !budgetsTo.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("budget periods through %s", budgetsTo)
+	}
+}
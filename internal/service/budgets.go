@@ -57,6 +57,7 @@ func (s *Service) CreateBudget(ctx context.Context, userID int, request model.Bu
 	if err != nil {
 		return model.Budget{}, apperrors.Internal(fmt.Errorf("create budget: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return item, nil
 }
 
@@ -86,6 +87,7 @@ func (s *Service) UpdateBudget(ctx context.Context, userID, budgetID int, reques
 	if err != nil {
 		return model.Budget{}, apperrors.Internal(fmt.Errorf("update budget: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return item, nil
 }
 
@@ -100,6 +102,7 @@ func (s *Service) DeleteBudget(ctx context.Context, userID, budgetID int) error
 	if err != nil {
 		return apperrors.Internal(fmt.Errorf("archive budget: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return nil
 }
 
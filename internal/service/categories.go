@@ -36,6 +36,11 @@ func (s *Service) CreateCategory(ctx context.Context, userID int, request model.
 	}
 	request.Type = transactionType
 	request.Name = name
+	if request.ParentID != nil {
+		if err := s.validateCategoryParent(ctx, userID, model.Category{Type: transactionType}, *request.ParentID); err != nil {
+			return model.Category{}, err
+		}
+	}
 
 	category, err := s.store.CreateCategory(ctx, userID, request)
 	if errors.Is(err, repository.ErrConflict) {
@@ -47,6 +52,164 @@ func (s *Service) CreateCategory(ctx context.Context, userID int, request model.
 	return category, nil
 }
 
+// UpdateCategory renames a category and moves it in the hierarchy. Default
+// categories keep their names because imports and syncs classify into them
+// by name, but they can still be placed under a parent.
+func (s *Service) UpdateCategory(
+	ctx context.Context,
+	userID, categoryID int,
+	request model.CategoryUpdateRequest,
+) (model.Category, error) {
+	if err := validateID(categoryID); err != nil {
+		return model.Category{}, err
+	}
+	name, err := normalizeLimitedText(request.Name, "category name", maximumCategoryRunes, false)
+	if err != nil {
+		return model.Category{}, err
+	}
+	request.Name = name
+	existing, err := s.activeCategory(ctx, userID, categoryID, "category not found")
+	if err != nil {
+		return model.Category{}, err
+	}
+	if existing.IsDefault && name != existing.Name {
+		return model.Category{}, apperrors.Validation("default categories cannot be renamed")
+	}
+	if request.ParentID != nil {
+		if err := s.validateCategoryParent(ctx, userID, existing, *request.ParentID); err != nil {
+			return model.Category{}, err
+		}
+	}
+
+	category, err := s.store.UpdateCategory(ctx, userID, categoryID, request)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Category{}, apperrors.NotFound("category not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.Category{}, apperrors.Conflict("category already exists")
+	}
+	if err != nil {
+		return model.Category{}, apperrors.Internal(fmt.Errorf("update category: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return category, nil
+}
+
+// MergeCategory folds a custom category into another of the same type. Its
+// transactions, budgets, schedules, rules and subcategories move to the
+// target and the category is archived.
+func (s *Service) MergeCategory(
+	ctx context.Context,
+	userID, categoryID int,
+	request model.CategoryMergeRequest,
+) (model.CategoryMergeResult, error) {
+	if err := validateID(categoryID); err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if request.TargetID <= 0 {
+		return model.CategoryMergeResult{}, apperrors.Validation("target_id must be a positive integer")
+	}
+	if request.TargetID == categoryID {
+		return model.CategoryMergeResult{}, apperrors.Validation("a category cannot be merged into itself")
+	}
+	source, err := s.activeCategory(ctx, userID, categoryID, "category not found")
+	if err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	target, err := s.activeCategory(ctx, userID, request.TargetID, "target category not found")
+	if err != nil {
+		return model.CategoryMergeResult{}, err
+	}
+	if source.IsDefault {
+		return model.CategoryMergeResult{}, apperrors.Validation("default categories cannot be merged into another category")
+	}
+	if source.Type != target.Type {
+		return model.CategoryMergeResult{}, apperrors.Validation("categories must have the same type")
+	}
+	if target.ParentID != nil && *target.ParentID != source.ID {
+		children, err := s.subcategoryCount(ctx, userID, source)
+		if err != nil {
+			return model.CategoryMergeResult{}, err
+		}
+		if children > 0 {
+			return model.CategoryMergeResult{}, apperrors.Validation("a category with subcategories can only be merged into a top-level category")
+		}
+	}
+
+	result, err := s.store.MergeCategories(ctx, userID, categoryID, request.TargetID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.CategoryMergeResult{}, apperrors.NotFound("category not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.CategoryMergeResult{}, apperrors.Conflict("categories changed while merging; try again")
+	}
+	if err != nil {
+		return model.CategoryMergeResult{}, apperrors.Internal(fmt.Errorf("merge categories: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return result, nil
+}
+
+func (s *Service) activeCategory(ctx context.Context, userID, categoryID int, missing string) (model.Category, error) {
+	category, err := s.store.GetCategory(ctx, userID, categoryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Category{}, apperrors.NotFound(missing)
+	}
+	if err != nil {
+		return model.Category{}, apperrors.Internal(fmt.Errorf("get category: %w", err))
+	}
+	return category, nil
+}
+
+// validateCategoryParent keeps the hierarchy one level deep: the parent must
+// be an active top-level category of the same type, and a category that
+// already has subcategories cannot become one.
+func (s *Service) validateCategoryParent(ctx context.Context, userID int, category model.Category, parentID int) error {
+	if parentID <= 0 {
+		return apperrors.Validation("parent_id must be a positive integer")
+	}
+	if parentID == category.ID {
+		return apperrors.Validation("a category cannot be its own parent")
+	}
+	parent, err := s.store.GetCategory(ctx, userID, parentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.Validation("parent category must be an active category")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("get parent category: %w", err))
+	}
+	if parent.Type != category.Type {
+		return apperrors.Validation("parent category must have the same type")
+	}
+	if parent.ParentID != nil {
+		return apperrors.Validation("parent category must be a top-level category")
+	}
+	if category.ID != 0 {
+		children, err := s.subcategoryCount(ctx, userID, category)
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return apperrors.Validation("a category with subcategories cannot become a subcategory")
+		}
+	}
+	return nil
+}
+
+func (s *Service) subcategoryCount(ctx context.Context, userID int, category model.Category) (int, error) {
+	categories, err := s.store.ListCategories(ctx, userID, category.Type)
+	if err != nil {
+		return 0, apperrors.Internal(fmt.Errorf("list categories: %w", err))
+	}
+	count := 0
+	for _, candidate := range categories {
+		if candidate.ParentID != nil && *candidate.ParentID == category.ID {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (s *Service) DeleteCategory(ctx context.Context, userID, categoryID int) error {
 	if err := validateID(categoryID); err != nil {
 		return err
@@ -58,5 +221,6 @@ func (s *Service) DeleteCategory(ctx context.Context, userID, categoryID int) er
 	if err != nil {
 		return apperrors.Internal(fmt.Errorf("delete category: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func hierarchyFakeStore() *fakeStore {
+	food, snacks := 10, 12
+	categories := []model.Category{
+		{ID: 1, Type: "expense", Name: "groceries", IsDefault: true, ParentID: &food},
+		{ID: 2, Type: "income", Name: "salary", IsDefault: true},
+		{ID: 10, Type: "expense", Name: "Food"},
+		{ID: 11, Type: "expense", Name: "Drinks"},
+		{ID: 12, Type: "expense", Name: "Snacks"},
+		{ID: 13, Type: "expense", Name: "Crisps", ParentID: &snacks},
+	}
+	return &fakeStore{
+		getCategory: func(_ context.Context, _ int, categoryID int) (model.Category, error) {
+			for _, category := range categories {
+				if category.ID == categoryID {
+					return category, nil
+				}
+			}
+			return model.Category{}, repository.ErrNotFound
+		},
+		listCategories: func(_ context.Context, _ int, transactionType string) ([]model.Category, error) {
+			matching := make([]model.Category, 0)
+			for _, category := range categories {
+				if category.Type == transactionType {
+					matching = append(matching, category)
+				}
+			}
+			return matching, nil
+		},
+	}
+}
+
+func TestUpdateCategoryKeepsHierarchyOneLevelDeep(t *testing.T) {
+	store := hierarchyFakeStore()
+	var updated model.CategoryUpdateRequest
+	store.updateCategory = func(_ context.Context, _ int, categoryID int, request model.CategoryUpdateRequest) (model.Category, error) {
+		updated = request
+		return model.Category{ID: categoryID, Type: "expense", Name: request.Name, ParentID: request.ParentID}, nil
+	}
+	service := testService(store)
+	food := 10
+
+	category, err := service.UpdateCategory(context.Background(), 7, 11, model.CategoryUpdateRequest{Name: " Beverages ", ParentID: &food})
+	if err != nil || category.Name != "Beverages" || updated.ParentID == nil || *updated.ParentID != food {
+		t.Fatalf("UpdateCategory() = %#v, %v", category, err)
+	}
+	if _, err := service.UpdateCategory(context.Background(), 7, 1, model.CategoryUpdateRequest{Name: "groceries"}); err != nil {
+		t.Fatalf("moving a default category to top-level error = %v", err)
+	}
+
+	groceries, salary, drinks := 1, 2, 11
+	invalid := map[string]struct {
+		id      int
+		request model.CategoryUpdateRequest
+	}{
+		"rename default":       {1, model.CategoryUpdateRequest{Name: "Supermarket"}},
+		"grandchild":           {11, model.CategoryUpdateRequest{Name: "Drinks", ParentID: &groceries}},
+		"other type parent":    {11, model.CategoryUpdateRequest{Name: "Drinks", ParentID: &salary}},
+		"own parent":           {11, model.CategoryUpdateRequest{Name: "Drinks", ParentID: &drinks}},
+		"parent with children": {10, model.CategoryUpdateRequest{Name: "Food", ParentID: &drinks}},
+		"missing parent":       {11, model.CategoryUpdateRequest{Name: "Drinks", ParentID: new(int)}},
+		"empty name":           {11, model.CategoryUpdateRequest{Name: " "}},
+	}
+	for name, item := range invalid {
+		if _, err := service.UpdateCategory(context.Background(), 7, item.id, item.request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s error = %v", name, err)
+		}
+	}
+	if _, err := service.UpdateCategory(context.Background(), 7, 99, model.CategoryUpdateRequest{Name: "Missing"}); apperrors.KindOf(err) != apperrors.KindNotFound {
+		t.Fatalf("missing category error = %v", err)
+	}
+
+	store.updateCategory = func(context.Context, int, int, model.CategoryUpdateRequest) (model.Category, error) {
+		return model.Category{}, repository.ErrConflict
+	}
+	if _, err := service.UpdateCategory(context.Background(), 7, 11, model.CategoryUpdateRequest{Name: "Food"}); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("duplicate name error = %v", err)
+	}
+}
+
+func TestMergeCategoryValidatesSourceAndTarget(t *testing.T) {
+	store := hierarchyFakeStore()
+	var merged [2]int
+	store.mergeCategories = func(_ context.Context, _ int, sourceID, targetID int) (model.CategoryMergeResult, error) {
+		merged = [2]int{sourceID, targetID}
+		return model.CategoryMergeResult{Category: model.Category{ID: targetID}, Transactions: 3}, nil
+	}
+	service := testService(store)
+
+	result, err := service.MergeCategory(context.Background(), 7, 11, model.CategoryMergeRequest{TargetID: 1})
+	if err != nil || result.Transactions != 3 || merged != [2]int{11, 1} {
+		t.Fatalf("MergeCategory() = %#v, %v (merged %v)", result, err, merged)
+	}
+	if _, err := service.MergeCategory(context.Background(), 7, 12, model.CategoryMergeRequest{TargetID: 13}); err != nil {
+		t.Fatalf("merging a parent into its own subcategory error = %v", err)
+	}
+
+	invalid := map[string]struct {
+		id, target int
+	}{
+		"itself":                  {11, 11},
+		"default source":          {1, 11},
+		"other type":              {11, 2},
+		"children to subcategory": {12, 1},
+		"missing target id":       {11, 0},
+	}
+	for name, item := range invalid {
+		_, err := service.MergeCategory(context.Background(), 7, item.id, model.CategoryMergeRequest{TargetID: item.target})
+		if apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s error = %v", name, err)
+		}
+	}
+	if _, err := service.MergeCategory(context.Background(), 7, 11, model.CategoryMergeRequest{TargetID: 99}); apperrors.KindOf(err) != apperrors.KindNotFound {
+		t.Fatalf("missing target error = %v", err)
+	}
+}
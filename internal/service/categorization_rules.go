@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumCategorizationRuleNameRunes   = 80
+	maximumCategorizationRuleTextRunes   = 200
+	categorizationRuleMCCLength          = 4
+	maximumCategorizationRuleAbsPriority = 1000
+	categorizationRuleSource             = "rule"
+	categorizationRuleTransferTag        = "transfer"
+	defaultCategorizationRuleHistoryDays = 365
+	maximumCategorizationRuleRunDays     = 366
+)
+
+// categorizationInput is what a rule can see of a transaction. AccountID is
+// zero for rows that did not come from a bank sync.
+type categorizationInput struct {
+	Type                 string
+	Description          string
+	Amount               string
+	AccountID            int
+	MerchantCategoryCode string
+}
+
+// categorizationOutcome merges the actions of every matching rule. The
+// highest-priority rule with a category decides it; tags accumulate and any
+// rule can exclude the row from budgets.
+type categorizationOutcome struct {
+	Matched            bool
+	Category           string
+	Tags               []string
+	ExcludedFromBudget bool
+}
+
+type compiledCategorizationRule struct {
+	rule      model.CategorizationRule
+	contains  string
+	pattern   *regexp.Regexp
+	amountMin *big.Rat
+	amountMax *big.Rat
+}
+
+type categorizationRules []compiledCategorizationRule
+
+// loadCategorizationRules reads the user's enabled rules once per import or
+// sync. Stored rules were validated on save, so a rule that no longer
+// compiles is skipped rather than failing the whole import.
+func (s *Service) loadCategorizationRules(ctx context.Context, userID int) (categorizationRules, error) {
+	items, err := s.store.ListActiveCategorizationRules(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list categorization rules: %w", err))
+	}
+	rules := make(categorizationRules, 0, len(items))
+	for _, item := range items {
+		if rule, err := compileCategorizationRule(item); err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func compileCategorizationRule(rule model.CategorizationRule) (compiledCategorizationRule, error) {
+	compiled := compiledCategorizationRule{
+		rule:     rule,
+		contains: strings.ToLower(rule.Conditions.DescriptionContains),
+	}
+	if rule.Conditions.DescriptionPattern != "" {
+		pattern, err := regexp.Compile("(?i)" + rule.Conditions.DescriptionPattern)
+		if err != nil {
+			return compiledCategorizationRule{}, err
+		}
+		compiled.pattern = pattern
+	}
+	for _, bound := range []struct {
+		value  string
+		target **big.Rat
+	}{
+		{rule.Conditions.AmountMin, &compiled.amountMin},
+		{rule.Conditions.AmountMax, &compiled.amountMax},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		value, ok := new(big.Rat).SetString(bound.value)
+		if !ok {
+			return compiledCategorizationRule{}, fmt.Errorf("invalid rule amount %q", bound.value)
+		}
+		*bound.target = value
+	}
+	return compiled, nil
+}
+
+func (rule compiledCategorizationRule) matches(input categorizationInput) bool {
+	conditions := rule.rule.Conditions
+	if conditions.Type != "" && conditions.Type != input.Type {
+		return false
+	}
+	if conditions.AccountID != nil && *conditions.AccountID != input.AccountID {
+		return false
+	}
+	if conditions.MerchantCategoryCode != "" && conditions.MerchantCategoryCode != input.MerchantCategoryCode {
+		return false
+	}
+	if rule.contains != "" && !strings.Contains(strings.ToLower(input.Description), rule.contains) {
+		return false
+	}
+	if rule.pattern != nil && !rule.pattern.MatchString(input.Description) {
+		return false
+	}
+	if rule.amountMin != nil || rule.amountMax != nil {
+		amount, ok := new(big.Rat).SetString(input.Amount)
+		if !ok {
+			return false
+		}
+		if rule.amountMin != nil && amount.Cmp(rule.amountMin) < 0 {
+			return false
+		}
+		if rule.amountMax != nil && amount.Cmp(rule.amountMax) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (rules categorizationRules) apply(input categorizationInput) categorizationOutcome {
+	outcome := categorizationOutcome{}
+	for _, rule := range rules {
+		if !rule.matches(input) {
+			continue
+		}
+		outcome.Matched = true
+		actions := rule.rule.Actions
+		if outcome.Category == "" {
+			outcome.Category = actions.Category
+		}
+		outcome.Tags = mergeTags(outcome.Tags, actions.Tags)
+		if actions.MarkAsTransfer {
+			outcome.Tags = mergeTags(outcome.Tags, []string{categorizationRuleTransferTag})
+		}
+		outcome.ExcludedFromBudget = outcome.ExcludedFromBudget || actions.ExcludeFromBudget || actions.MarkAsTransfer
+	}
+	return outcome
+}
+
+// mergeTags adds tags to existing ones, keeping the result sorted and within
+// the per-transaction limit that mirrors the transactions_tags_check
+// constraint. Tags that do not fit are dropped.
+func mergeTags(existing, tags []string) []string {
+	merged := slices.Clone(existing)
+	for _, tag := range tags {
+		if len(merged) >= repository.MaximumTransactionTags {
+			break
+		}
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	slices.Sort(merged)
+	return merged
+}
+
+func (s *Service) ListCategorizationRules(ctx context.Context, userID int) ([]model.CategorizationRule, error) {
+	rules, err := s.store.ListCategorizationRules(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list categorization rules: %w", err))
+	}
+	return rules, nil
+}
+
+func (s *Service) CreateCategorizationRule(
+	ctx context.Context,
+	userID int,
+	request model.CategorizationRuleRequest,
+) (model.CategorizationRule, error) {
+	normalized, err := s.validateCategorizationRule(ctx, userID, request)
+	if err != nil {
+		return model.CategorizationRule{}, err
+	}
+	rule, err := s.store.CreateCategorizationRule(ctx, userID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.CategorizationRule{}, apperrors.Validation("conditions.account_id must be one of your bank accounts")
+	}
+	if err != nil {
+		return model.CategorizationRule{}, apperrors.Internal(fmt.Errorf("create categorization rule: %w", err))
+	}
+	return rule, nil
+}
+
+func (s *Service) UpdateCategorizationRule(
+	ctx context.Context,
+	userID, ruleID int,
+	request model.CategorizationRuleRequest,
+) (model.CategorizationRule, error) {
+	if err := validateID(ruleID); err != nil {
+		return model.CategorizationRule{}, err
+	}
+	if _, err := s.store.GetCategorizationRule(ctx, userID, ruleID); errors.Is(err, repository.ErrNotFound) {
+		return model.CategorizationRule{}, apperrors.NotFound("categorization rule not found")
+	} else if err != nil {
+		return model.CategorizationRule{}, apperrors.Internal(fmt.Errorf("get categorization rule: %w", err))
+	}
+	normalized, err := s.validateCategorizationRule(ctx, userID, request)
+	if err != nil {
+		return model.CategorizationRule{}, err
+	}
+	rule, err := s.store.UpdateCategorizationRule(ctx, userID, ruleID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.CategorizationRule{}, apperrors.Validation("conditions.account_id must be one of your bank accounts")
+	}
+	if err != nil {
+		return model.CategorizationRule{}, apperrors.Internal(fmt.Errorf("update categorization rule: %w", err))
+	}
+	return rule, nil
+}
+
+func (s *Service) DeleteCategorizationRule(ctx context.Context, userID, ruleID int) error {
+	if err := validateID(ruleID); err != nil {
+		return err
+	}
+	err := s.store.DeleteCategorizationRule(ctx, userID, ruleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("categorization rule not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete categorization rule: %w", err))
+	}
+	return nil
+}
+
+// RunCategorizationRules re-applies the rules to booked history, by default
+// the last 365 days. Rows keep their category when no rule sets one, and
+// synced rows the user reclassified are not touched.
+func (s *Service) RunCategorizationRules(
+	ctx context.Context,
+	userID int,
+	request model.CategorizationRuleRunRequest,
+) (model.CategorizationRuleRunResult, error) {
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	if request.To != "" {
+		parsed, err := parseDate(request.To, "to")
+		if err != nil {
+			return model.CategorizationRuleRunResult{}, err
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultCategorizationRuleHistoryDays)
+	if request.From != "" {
+		parsed, err := parseDate(request.From, "from")
+		if err != nil {
+			return model.CategorizationRuleRunResult{}, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return model.CategorizationRuleRunResult{}, apperrors.Validation("from must be before or equal to to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maximumCategorizationRuleRunDays {
+		return model.CategorizationRuleRunResult{}, apperrors.Validation(
+			fmt.Sprintf("rule run date range must be %d days or less", maximumCategorizationRuleRunDays),
+		)
+	}
+	rules, err := s.loadCategorizationRules(ctx, userID)
+	if err != nil {
+		return model.CategorizationRuleRunResult{}, err
+	}
+	targets, err := s.store.ListCategorizationRuleTargets(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return model.CategorizationRuleRunResult{}, apperrors.Internal(fmt.Errorf("list categorization rule targets: %w", err))
+	}
+	result := model.CategorizationRuleRunResult{Scanned: len(targets)}
+	updates := make([]repository.CategorizationRuleUpdate, 0)
+	for _, target := range targets {
+		outcome := rules.apply(categorizationInput{
+			Type: target.Type, Description: target.Description, Amount: target.Amount,
+			AccountID: target.AccountID, MerchantCategoryCode: target.MerchantCategoryCode,
+		})
+		if !outcome.Matched {
+			continue
+		}
+		result.Matched++
+		update := repository.CategorizationRuleUpdate{
+			TransactionID:      target.TransactionID,
+			Category:           target.Category,
+			Tags:               mergeTags(target.Tags, outcome.Tags),
+			ExcludedFromBudget: target.ExcludedFromBudget || outcome.ExcludedFromBudget,
+		}
+		if outcome.Category != "" {
+			update.Category = outcome.Category
+		}
+		updates = append(updates, update)
+	}
+	if len(updates) == 0 {
+		return result, nil
+	}
+	result.Updated, err = s.store.ApplyCategorizationRuleUpdates(ctx, userID, updates)
+	if err != nil {
+		return model.CategorizationRuleRunResult{}, apperrors.Internal(fmt.Errorf("apply categorization rules: %w", err))
+	}
+	if result.Updated > 0 {
+		s.invalidateReports(ctx, userID)
+	}
+	return result, nil
+}
+
+func (s *Service) validateCategorizationRule(
+	ctx context.Context,
+	userID int,
+	request model.CategorizationRuleRequest,
+) (model.CategorizationRuleRequest, error) {
+	name, err := normalizeLimitedText(request.Name, "name", maximumCategorizationRuleNameRunes, false)
+	if err != nil {
+		return model.CategorizationRuleRequest{}, err
+	}
+	if request.Priority < -maximumCategorizationRuleAbsPriority || request.Priority > maximumCategorizationRuleAbsPriority {
+		return model.CategorizationRuleRequest{}, apperrors.Validation(
+			fmt.Sprintf("priority must be between -%d and %d", maximumCategorizationRuleAbsPriority, maximumCategorizationRuleAbsPriority),
+		)
+	}
+	enabled := request.Enabled == nil || *request.Enabled
+	normalized := model.CategorizationRuleRequest{Name: name, Priority: request.Priority, Enabled: &enabled}
+
+	conditions := request.Conditions
+	if normalized.Conditions.DescriptionContains, err = normalizeLimitedText(
+		conditions.DescriptionContains, "conditions.description_contains", maximumCategorizationRuleTextRunes, true,
+	); err != nil {
+		return model.CategorizationRuleRequest{}, err
+	}
+	if normalized.Conditions.DescriptionPattern, err = normalizeLimitedText(
+		conditions.DescriptionPattern, "conditions.description_pattern", maximumCategorizationRuleTextRunes, true,
+	); err != nil {
+		return model.CategorizationRuleRequest{}, err
+	}
+	if normalized.Conditions.DescriptionPattern != "" {
+		if _, err := regexp.Compile(normalized.Conditions.DescriptionPattern); err != nil {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.description_pattern must be a valid regular expression")
+		}
+	}
+	var amountMin, amountMax *big.Rat
+	if strings.TrimSpace(conditions.AmountMin) != "" {
+		if normalized.Conditions.AmountMin, err = normalizeAmount(conditions.AmountMin); err != nil {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.amount_min must be a positive decimal with at most 2 decimal places")
+		}
+		amountMin, _ = new(big.Rat).SetString(normalized.Conditions.AmountMin)
+	}
+	if strings.TrimSpace(conditions.AmountMax) != "" {
+		if normalized.Conditions.AmountMax, err = normalizeAmount(conditions.AmountMax); err != nil {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.amount_max must be a positive decimal with at most 2 decimal places")
+		}
+		amountMax, _ = new(big.Rat).SetString(normalized.Conditions.AmountMax)
+	}
+	if amountMin != nil && amountMax != nil && amountMin.Cmp(amountMax) > 0 {
+		return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.amount_min must not exceed conditions.amount_max")
+	}
+	if conditions.AccountID != nil {
+		if err := validateID(*conditions.AccountID); err != nil {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.account_id must be a positive integer")
+		}
+		accountID := *conditions.AccountID
+		normalized.Conditions.AccountID = &accountID
+	}
+	normalized.Conditions.MerchantCategoryCode = strings.TrimSpace(conditions.MerchantCategoryCode)
+	if code := normalized.Conditions.MerchantCategoryCode; code != "" {
+		if len(code) != categorizationRuleMCCLength || strings.Trim(code, "0123456789") != "" {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("conditions.merchant_category_code must be a 4-digit code")
+		}
+	}
+	if strings.TrimSpace(conditions.Type) != "" {
+		if normalized.Conditions.Type, err = normalizeTransactionType(conditions.Type); err != nil {
+			return model.CategorizationRuleRequest{}, err
+		}
+	}
+	if normalized.Conditions == (model.CategorizationRuleConditions{}) {
+		return model.CategorizationRuleRequest{}, apperrors.Validation("a rule needs at least one condition")
+	}
+
+	actions := request.Actions
+	if strings.TrimSpace(actions.Category) != "" {
+		if normalized.Conditions.Type == "" {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("a rule that sets a category needs a conditions.type")
+		}
+		category, err := normalizeLimitedText(actions.Category, "actions.category", maximumCategoryRunes, false)
+		if err != nil {
+			return model.CategorizationRuleRequest{}, err
+		}
+		normalized.Actions.Category, err = s.store.FindActiveCategoryName(ctx, userID, normalized.Conditions.Type, category)
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.CategorizationRuleRequest{}, apperrors.Validation("actions.category must be active and match conditions.type")
+		}
+		if err != nil {
+			return model.CategorizationRuleRequest{}, apperrors.Internal(fmt.Errorf("validate rule category: %w", err))
+		}
+	}
+	if len(actions.Tags) > 0 {
+		if normalized.Actions.Tags, err = normalizeTags(actions.Tags); err != nil {
+			return model.CategorizationRuleRequest{}, err
+		}
+	}
+	normalized.Actions.ExcludeFromBudget = actions.ExcludeFromBudget
+	normalized.Actions.MarkAsTransfer = actions.MarkAsTransfer
+	if normalized.Actions.Category == "" && len(normalized.Actions.Tags) == 0 &&
+		!normalized.Actions.ExcludeFromBudget && !normalized.Actions.MarkAsTransfer {
+		return model.CategorizationRuleRequest{}, apperrors.Validation("a rule needs at least one action")
+	}
+	return normalized, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func rulesFakeStore(rules ...model.CategorizationRule) *fakeStore {
+	return &fakeStore{
+		listActiveCategorizationRules: func(context.Context, int) ([]model.CategorizationRule, error) {
+			return rules, nil
+		},
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) {
+			return strings.ToLower(name), nil
+		},
+	}
+}
+
+func TestCreateCategorizationRuleValidatesConditionsAndActions(t *testing.T) {
+	store := rulesFakeStore()
+	store.createCategorizationRule = func(_ context.Context, _ int, request model.CategorizationRuleRequest) (model.CategorizationRule, error) {
+		if request.Name != "Happy Bar" || request.Conditions.Type != "expense" || request.Actions.Category != "dining_out" ||
+			!slices.Equal(request.Actions.Tags, []string{"bars", "friends"}) || request.Conditions.AmountMax != "50.00" {
+			t.Fatalf("stored rule = %#v", request)
+		}
+		return model.CategorizationRule{ID: 3, Name: request.Name}, nil
+	}
+	service := testService(store)
+
+	rule, err := service.CreateCategorizationRule(context.Background(), 7, model.CategorizationRuleRequest{
+		Name: " Happy Bar ",
+		Conditions: model.CategorizationRuleConditions{
+			DescriptionContains: "happy bar", AmountMax: "50", Type: "Expense",
+		},
+		Actions: model.CategorizationRuleActions{Category: "Dining_Out", Tags: []string{"Friends", "bars", "friends"}},
+	})
+	if err != nil || rule.ID != 3 {
+		t.Fatalf("CreateCategorizationRule() = %#v, %v", rule, err)
+	}
+
+	invalid := []model.CategorizationRuleRequest{
+		{Name: "No condition", Actions: model.CategorizationRuleActions{ExcludeFromBudget: true}},
+		{Name: "No action", Conditions: model.CategorizationRuleConditions{DescriptionContains: "bar"}},
+		{
+			Name:       "Untyped category",
+			Conditions: model.CategorizationRuleConditions{DescriptionContains: "bar"},
+			Actions:    model.CategorizationRuleActions{Category: "dining_out"},
+		},
+		{
+			Name:       "Bad pattern",
+			Conditions: model.CategorizationRuleConditions{DescriptionPattern: "bar("},
+			Actions:    model.CategorizationRuleActions{MarkAsTransfer: true},
+		},
+		{
+			Name:       "Inverted range",
+			Conditions: model.CategorizationRuleConditions{AmountMin: "20", AmountMax: "10"},
+			Actions:    model.CategorizationRuleActions{ExcludeFromBudget: true},
+		},
+		{
+			Name:       "Bad MCC",
+			Conditions: model.CategorizationRuleConditions{MerchantCategoryCode: "58A2"},
+			Actions:    model.CategorizationRuleActions{ExcludeFromBudget: true},
+		},
+	}
+	for _, request := range invalid {
+		if _, err := service.CreateCategorizationRule(context.Background(), 7, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s error = %v", request.Name, err)
+		}
+	}
+}
+
+func TestCategorizationRulesTakePrecedenceOverBuiltInKeywords(t *testing.T) {
+	store := rulesFakeStore(
+		model.CategorizationRule{
+			ID: 1, Name: "Happy Bar", Priority: 10, Enabled: true,
+			Conditions: model.CategorizationRuleConditions{DescriptionPattern: `^happy\s+bar`, Type: "expense"},
+			Actions:    model.CategorizationRuleActions{Category: "dining_out", Tags: []string{"bars"}},
+		},
+		model.CategorizationRule{
+			ID: 2, Name: "Big card spend", Enabled: true,
+			Conditions: model.CategorizationRuleConditions{AmountMin: "100.00", Type: "expense"},
+			Actions:    model.CategorizationRuleActions{Category: "shopping", ExcludeFromBudget: true},
+		},
+	)
+	service := testService(store)
+
+	prepared, err := service.prepareStatementImport(context.Background(), 7, []statementRow{
+		{Line: 2, Type: "expense", Amount: "120.00", Currency: "EUR", Date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Description: "HAPPY BAR supermarket"},
+		{Line: 3, Type: "expense", Amount: "12.00", Currency: "EUR", Date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Description: "Lidl supermarket"},
+		{Line: 4, Type: "expense", Amount: "12.00", Currency: "EUR", Date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Description: "Happy Bar", Category: "Fun"},
+	}, statementImportOptions{Source: "csv"})
+	if err != nil || len(prepared) != 3 {
+		t.Fatalf("prepareStatementImport() = %#v, %v", prepared, err)
+	}
+	first := prepared[0]
+	if first.Transaction.Request.Category != "dining_out" || first.ClassificationSource != categorizationRuleSource ||
+		!slices.Equal(first.Transaction.Request.Tags, []string{"bars"}) || !first.Transaction.Request.ExcludedFromBudget {
+		t.Fatalf("ruled row = %#v", first)
+	}
+	if second := prepared[1]; second.Transaction.Request.Category != "groceries" || second.ClassificationSource == categorizationRuleSource {
+		t.Fatalf("built-in row = %#v", second)
+	}
+	if annotated := prepared[2]; annotated.Transaction.Request.Category != "fun" || annotated.ClassificationSource != "annotation" ||
+		!slices.Equal(annotated.Transaction.Request.Tags, []string{"bars"}) {
+		t.Fatalf("annotated row = %#v", annotated)
+	}
+}
+
+func TestNormalizeOpenBankingTransactionAppliesAccountRules(t *testing.T) {
+	accountID := 4
+	rules := categorizationRules{}
+	for _, item := range []model.CategorizationRule{{
+		ID: 1, Name: "Savings sweep", Enabled: true,
+		Conditions: model.CategorizationRuleConditions{AccountID: &accountID, DescriptionContains: "sweep"},
+		Actions:    model.CategorizationRuleActions{MarkAsTransfer: true},
+	}, {
+		ID: 2, Name: "ACME salary", Enabled: true,
+		Conditions: model.CategorizationRuleConditions{DescriptionContains: "acme", Type: "income"},
+		Actions:    model.CategorizationRuleActions{Category: "salary"},
+	}} {
+		rule, err := compileCategorizationRule(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+	}
+	raw := json.RawMessage(`{
+		"entry_reference":"sweep-1",
+		"transaction_amount":{"currency":"EUR","amount":"300.00"},
+		"credit_debit_indicator":"CRDT",
+		"status":"BOOK",
+		"booking_date":"2026-07-12",
+		"debtor":{"name":"ACME Sweep Ltd"}
+	}`)
+	today := time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC)
+
+	item, ok := normalizeOpenBankingTransactionForAccount(raw, today, "Bank", accountID, rules, nil)
+	if !ok || item.Category != "salary" || !item.ExcludedFromBudget || !slices.Equal(item.Tags, []string{"transfer"}) {
+		t.Fatalf("normalized transaction = %#v, included=%v", item, ok)
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(item.Metadata, &metadata); err != nil || metadata["category_source"] != categorizationRuleSource {
+		t.Fatalf("metadata = %s", item.Metadata)
+	}
+
+	other, ok := normalizeOpenBankingTransactionForAccount(raw, today, "Bank", accountID+1, rules, nil)
+	if !ok || other.ExcludedFromBudget || len(other.Tags) != 0 || other.Category != "salary" {
+		t.Fatalf("other account transaction = %#v, included=%v", other, ok)
+	}
+}
+
+func TestCreateTransactionRefinesCatchAllCategoryWithRules(t *testing.T) {
+	store := rulesFakeStore(model.CategorizationRule{
+		ID: 1, Name: "Happy Bar", Enabled: true,
+		Conditions: model.CategorizationRuleConditions{DescriptionContains: "happy bar", Type: "expense"},
+		Actions:    model.CategorizationRuleActions{Category: "dining_out", Tags: []string{"bars"}},
+	})
+	var created []model.TransactionRequest
+	store.createTransaction = func(_ context.Context, _ int, request model.TransactionRequest) (model.Transaction, error) {
+		created = append(created, request)
+		return model.Transaction{ID: len(created)}, nil
+	}
+	service := testService(store)
+
+	for _, category := range []string{"other", "fun"} {
+		if _, err := service.CreateTransaction(context.Background(), 7, model.TransactionRequest{
+			Type: "expense", Category: category, Description: "Drinks at Happy Bar", Amount: "18.40",
+			Currency: "EUR", OccurredAt: "2026-07-10",
+		}); err != nil {
+			t.Fatalf("CreateTransaction(%s) error = %v", category, err)
+		}
+	}
+	// Only the category the user picked is learned from, not the rule's.
+	if len(created) != 2 || created[0].Category != "dining_out" || created[1].Category != "fun" ||
+		!slices.Equal(created[0].Tags, []string{"bars"}) || !slices.Equal(created[1].Tags, []string{"bars"}) ||
+		created[0].CategoryChosen || !created[1].CategoryChosen {
+		t.Fatalf("created = %#v", created)
+	}
+}
+
+func TestRunCategorizationRulesUpdatesMatchingHistory(t *testing.T) {
+	store := rulesFakeStore(model.CategorizationRule{
+		ID: 1, Name: "Happy Bar", Enabled: true,
+		Conditions: model.CategorizationRuleConditions{DescriptionContains: "happy bar", Type: "expense"},
+		Actions:    model.CategorizationRuleActions{Category: "dining_out", Tags: []string{"bars"}},
+	})
+	store.listCategorizationRuleTargets = func(_ context.Context, _ int, from, to time.Time) ([]repository.CategorizationRuleTarget, error) {
+		if from.Format("2006-01-02") != "2026-06-01" || to.Format("2006-01-02") != "2026-07-01" {
+			t.Fatalf("range = %s %s", from, to)
+		}
+		return []repository.CategorizationRuleTarget{
+			{TransactionID: 1, Type: "expense", Category: "other", Description: "HAPPY BAR", Amount: "9.00", Tags: []string{"work"}},
+			{TransactionID: 2, Type: "expense", Category: "groceries", Description: "Lidl", Amount: "9.00", Tags: []string{}},
+		}, nil
+	}
+	store.applyCategorizationRuleUpdates = func(_ context.Context, _ int, updates []repository.CategorizationRuleUpdate) (int, error) {
+		if len(updates) != 1 || updates[0].TransactionID != 1 || updates[0].Category != "dining_out" ||
+			!slices.Equal(updates[0].Tags, []string{"bars", "work"}) {
+			t.Fatalf("updates = %#v", updates)
+		}
+		return 1, nil
+	}
+	service := testService(store)
+
+	result, err := service.RunCategorizationRules(context.Background(), 7, model.CategorizationRuleRunRequest{
+		From: "2026-06-01", To: "2026-06-30",
+	})
+	if err != nil || result != (model.CategorizationRuleRunResult{Scanned: 2, Matched: 1, Updated: 1}) {
+		t.Fatalf("RunCategorizationRules() = %#v, %v", result, err)
+	}
+}
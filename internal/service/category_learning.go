@@ -0,0 +1,243 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumCategoryTrainingExamples  = 5000
+	minimumCategoryTrainingExamples  = 3
+	minimumLearnedCategoryConfidence = 0.6
+	learnedCategorySource            = "learned"
+	categoryModelSummaryTokens       = 10
+	categoryAmountFeaturePrefix      = "amount:"
+)
+
+// categoryAmountBuckets split amounts into coarse ranges so a coffee and a
+// monthly rent at the same merchant can still be told apart.
+var categoryAmountBuckets = []int64{5, 20, 50, 100, 250, 1000}
+
+// categoryClassifier is a multinomial naive Bayes model per transaction type
+// over description words and an amount bucket, trained on rows whose
+// category a person chose. A nil classifier predicts nothing.
+type categoryClassifier struct {
+	types map[string]*categoryTypeModel
+}
+
+type categoryTypeModel struct {
+	examples   int
+	vocabulary map[string]bool
+	classes    map[string]*categoryClassModel
+}
+
+type categoryClassModel struct {
+	examples int
+	features map[string]int
+	total    int
+}
+
+func (s *Service) loadCategoryClassifier(ctx context.Context, userID int) (*categoryClassifier, error) {
+	examples, err := s.store.ListCategoryTrainingExamples(ctx, userID, maximumCategoryTrainingExamples)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list category training examples: %w", err))
+	}
+	return newCategoryClassifier(examples), nil
+}
+
+func newCategoryClassifier(examples []repository.CategoryTrainingExample) *categoryClassifier {
+	classifier := &categoryClassifier{types: make(map[string]*categoryTypeModel)}
+	for _, example := range examples {
+		typeModel := classifier.types[example.Type]
+		if typeModel == nil {
+			typeModel = &categoryTypeModel{vocabulary: make(map[string]bool), classes: make(map[string]*categoryClassModel)}
+			classifier.types[example.Type] = typeModel
+		}
+		class := typeModel.classes[example.Category]
+		if class == nil {
+			class = &categoryClassModel{features: make(map[string]int)}
+			typeModel.classes[example.Category] = class
+		}
+		typeModel.examples++
+		class.examples++
+		for _, feature := range categoryFeatures(example.Description, example.Amount) {
+			typeModel.vocabulary[feature] = true
+			class.features[feature]++
+			class.total++
+		}
+	}
+	return classifier
+}
+
+// categoryFeatures are the description words of three or more letters plus
+// one amount bucket.
+func categoryFeatures(description, amount string) []string {
+	features := make([]string, 0, 8)
+	for token := range merchantTokens(description) {
+		features = append(features, token)
+	}
+	slices.Sort(features)
+	if bucket := categoryAmountBucket(amount); bucket != "" {
+		features = append(features, bucket)
+	}
+	return features
+}
+
+func categoryAmountBucket(amount string) string {
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return ""
+	}
+	for _, limit := range categoryAmountBuckets {
+		if value.Cmp(new(big.Rat).SetInt64(limit)) < 0 {
+			return fmt.Sprintf("%s<%d", categoryAmountFeaturePrefix, limit)
+		}
+	}
+	return fmt.Sprintf("%s>=%d", categoryAmountFeaturePrefix, categoryAmountBuckets[len(categoryAmountBuckets)-1])
+}
+
+// predict returns the most likely category and its posterior probability.
+// It only answers when the type has enough examples and the description
+// shares at least one word with them; an amount alone is not evidence.
+func (c *categoryClassifier) predict(transactionType, description, amount string) (string, float64) {
+	if c == nil {
+		return "", 0
+	}
+	typeModel := c.types[transactionType]
+	if typeModel == nil || typeModel.examples < minimumCategoryTrainingExamples {
+		return "", 0
+	}
+	features := categoryFeatures(description, amount)
+	known := false
+	for _, feature := range features {
+		if typeModel.vocabulary[feature] && !strings.HasPrefix(feature, categoryAmountFeaturePrefix) {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return "", 0
+	}
+	categories := make([]string, 0, len(typeModel.classes))
+	for category := range typeModel.classes {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	vocabularySize := float64(len(typeModel.vocabulary))
+	scores := make([]float64, len(categories))
+	best := 0
+	for index, category := range categories {
+		class := typeModel.classes[category]
+		score := math.Log(float64(class.examples) / float64(typeModel.examples))
+		for _, feature := range features {
+			score += math.Log((float64(class.features[feature]) + 1) / (float64(class.total) + vocabularySize))
+		}
+		scores[index] = score
+		if score > scores[best] {
+			best = index
+		}
+	}
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	return categories[best], 1 / sum
+}
+
+// classify is predict limited to confident answers, which imports and syncs
+// use ahead of the built-in keyword lists.
+func (c *categoryClassifier) classify(transactionType, description, amount string) (string, float64, bool) {
+	category, confidence := c.predict(transactionType, description, amount)
+	if category == "" || confidence < minimumLearnedCategoryConfidence {
+		return "", 0, false
+	}
+	return category, confidence, true
+}
+
+// GetCategoryModel reports what the classifier has learned.
+func (s *Service) GetCategoryModel(ctx context.Context, userID int) (model.CategoryModel, error) {
+	examples, err := s.store.ListCategoryTrainingExamples(ctx, userID, maximumCategoryTrainingExamples)
+	if err != nil {
+		return model.CategoryModel{}, apperrors.Internal(fmt.Errorf("list category training examples: %w", err))
+	}
+	classifier := newCategoryClassifier(examples)
+	summary := model.CategoryModel{Examples: len(examples), Classes: []model.CategoryModelClass{}}
+	for transactionType, typeModel := range classifier.types {
+		for category, class := range typeModel.classes {
+			tokens := make([]string, 0, len(class.features))
+			for feature := range class.features {
+				if !strings.HasPrefix(feature, categoryAmountFeaturePrefix) {
+					tokens = append(tokens, feature)
+				}
+			}
+			slices.SortFunc(tokens, func(left, right string) int {
+				return cmp.Or(cmp.Compare(class.features[right], class.features[left]), cmp.Compare(left, right))
+			})
+			if len(tokens) > categoryModelSummaryTokens {
+				tokens = tokens[:categoryModelSummaryTokens]
+			}
+			summary.Classes = append(summary.Classes, model.CategoryModelClass{
+				Type: transactionType, Category: category, Examples: class.examples, Tokens: tokens,
+			})
+		}
+	}
+	slices.SortFunc(summary.Classes, func(left, right model.CategoryModelClass) int {
+		return cmp.Or(
+			cmp.Compare(left.Type, right.Type),
+			cmp.Compare(right.Examples, left.Examples),
+			cmp.Compare(left.Category, right.Category),
+		)
+	})
+	return summary, nil
+}
+
+// PredictCategory shows how the classifier would categorize a transaction.
+func (s *Service) PredictCategory(
+	ctx context.Context,
+	userID int,
+	transactionType, description, amount string,
+) (model.CategoryPrediction, error) {
+	transactionType, err := normalizeTransactionType(transactionType)
+	if err != nil {
+		return model.CategoryPrediction{}, err
+	}
+	description, err = normalizeLimitedText(description, "description", maximumDescriptionRunes, false)
+	if err != nil {
+		return model.CategoryPrediction{}, err
+	}
+	if strings.TrimSpace(amount) != "" {
+		if amount, err = normalizeAmount(amount); err != nil {
+			return model.CategoryPrediction{}, err
+		}
+	}
+	classifier, err := s.loadCategoryClassifier(ctx, userID)
+	if err != nil {
+		return model.CategoryPrediction{}, err
+	}
+	category, confidence := classifier.predict(transactionType, description, amount)
+	return model.CategoryPrediction{
+		Type:       transactionType,
+		Category:   category,
+		Confidence: math.Round(confidence*1000) / 1000,
+		Applied:    category != "" && confidence >= minimumLearnedCategoryConfidence,
+	}, nil
+}
+
+// ResetCategoryModel forgets every training example. Later manual entries
+// and corrections teach the classifier again from scratch.
+func (s *Service) ResetCategoryModel(ctx context.Context, userID int) (model.CategoryModelReset, error) {
+	forgotten, err := s.store.ResetCategoryLearning(ctx, userID)
+	if err != nil {
+		return model.CategoryModelReset{}, apperrors.Internal(fmt.Errorf("reset category learning: %w", err))
+	}
+	return model.CategoryModelReset{Forgotten: forgotten}, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func categoryTrainingExamples() []repository.CategoryTrainingExample {
+	return []repository.CategoryTrainingExample{
+		{Type: "expense", Category: "household", Description: "LIDL 1234 SOFIA", Amount: "42.10"},
+		{Type: "expense", Category: "household", Description: "Lidl Bulgaria", Amount: "38.00"},
+		{Type: "expense", Category: "household", Description: "LIDL 88", Amount: "51.30"},
+		{Type: "expense", Category: "dining_out", Description: "Happy Bar and Grill", Amount: "18.40"},
+		{Type: "expense", Category: "dining_out", Description: "HAPPY BAR", Amount: "22.00"},
+		{Type: "income", Category: "salary", Description: "ACME payroll", Amount: "3000.00"},
+	}
+}
+
+func TestCategoryClassifierLearnsFromCorrections(t *testing.T) {
+	classifier := newCategoryClassifier(categoryTrainingExamples())
+
+	category, confidence, ok := classifier.classify("expense", "LIDL 4410 VARNA", "47.00")
+	if !ok || category != "household" || confidence < minimumLearnedCategoryConfidence || confidence > 1 {
+		t.Fatalf("classify lidl = %q %.3f %v", category, confidence, ok)
+	}
+	if category, _, ok := classifier.classify("expense", "Happy Bar", "20.00"); !ok || category != "dining_out" {
+		t.Fatalf("classify happy bar = %q %v", category, ok)
+	}
+	if category, _, ok := classifier.classify("expense", "Unknown shop", "47.00"); ok {
+		t.Fatalf("unknown description classified as %q", category)
+	}
+	if category, _, ok := classifier.classify("income", "ACME payroll", "3000.00"); ok {
+		t.Fatalf("type with one example classified as %q", category)
+	}
+	var empty *categoryClassifier
+	if _, _, ok := empty.classify("expense", "LIDL", "1.00"); ok {
+		t.Fatal("nil classifier classified a transaction")
+	}
+}
+
+func TestImportUsesLearnedCategoriesAfterRules(t *testing.T) {
+	store := rulesFakeStore(model.CategorizationRule{
+		ID: 1, Name: "Lidl deposits", Enabled: true,
+		Conditions: model.CategorizationRuleConditions{DescriptionContains: "lidl deposit", Type: "expense"},
+		Actions:    model.CategorizationRuleActions{Category: "groceries"},
+	})
+	store.listCategoryTrainingExamples = func(_ context.Context, userID, limit int) ([]repository.CategoryTrainingExample, error) {
+		if userID != 7 || limit != maximumCategoryTrainingExamples {
+			t.Fatalf("training examples = %d %d", userID, limit)
+		}
+		return categoryTrainingExamples(), nil
+	}
+	service := testService(store)
+	date := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	prepared, err := service.prepareStatementImport(context.Background(), 7, []statementRow{
+		{Line: 2, Type: "expense", Amount: "44.00", Currency: "EUR", Date: date, Description: "LIDL supermarket"},
+		{Line: 3, Type: "expense", Amount: "0.50", Currency: "EUR", Date: date, Description: "Lidl deposit return"},
+		{Line: 4, Type: "expense", Amount: "12.00", Currency: "EUR", Date: date, Description: "Kaufland supermarket"},
+	}, statementImportOptions{Source: "csv"})
+	if err != nil || len(prepared) != 3 {
+		t.Fatalf("prepareStatementImport() = %#v, %v", prepared, err)
+	}
+	if row := prepared[0]; row.Transaction.Request.Category != "household" || row.ClassificationSource != learnedCategorySource ||
+		row.ClassificationConfidence < minimumLearnedCategoryConfidence {
+		t.Fatalf("learned row = %#v", row)
+	}
+	if row := prepared[1]; row.Transaction.Request.Category != "groceries" || row.ClassificationSource != categorizationRuleSource {
+		t.Fatalf("ruled row = %#v", row)
+	}
+	if row := prepared[2]; row.Transaction.Request.Category != "groceries" || row.ClassificationSource != openBankingCategorySourceExpenseKeyword {
+		t.Fatalf("built-in row = %#v", row)
+	}
+}
+
+func TestCategoryModelSummaryAndPrediction(t *testing.T) {
+	service := testService(&fakeStore{
+		listCategoryTrainingExamples: func(context.Context, int, int) ([]repository.CategoryTrainingExample, error) {
+			return categoryTrainingExamples(), nil
+		},
+	})
+
+	summary, err := service.GetCategoryModel(context.Background(), 7)
+	if err != nil || summary.Examples != 6 || len(summary.Classes) != 3 {
+		t.Fatalf("GetCategoryModel() = %#v, %v", summary, err)
+	}
+	household := summary.Classes[0]
+	if household.Type != "expense" || household.Category != "household" || household.Examples != 3 ||
+		!slices.Equal(household.Tokens, []string{"lidl", "bulgaria", "sofia"}) {
+		t.Fatalf("household class = %#v", household)
+	}
+	if summary.Classes[2].Type != "income" {
+		t.Fatalf("classes = %#v", summary.Classes)
+	}
+
+	prediction, err := service.PredictCategory(context.Background(), 7, "Expense", "Lidl", "45")
+	if err != nil || prediction.Category != "household" || !prediction.Applied || prediction.Confidence < minimumLearnedCategoryConfidence {
+		t.Fatalf("PredictCategory() = %#v, %v", prediction, err)
+	}
+	prediction, err = service.PredictCategory(context.Background(), 7, "expense", "Cinema", "")
+	if err != nil || prediction.Category != "" || prediction.Applied {
+		t.Fatalf("unknown PredictCategory() = %#v, %v", prediction, err)
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var csvImportEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8BOM,
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	"windows-1251": charmap.Windows1251,
+	"windows-1252": charmap.Windows1252,
+}
+
+var csvImportDelimiters = []string{",", ";", "\t", "|"}
+
+// csvImportDateFormats lists the user-facing formats in detection order. Day
+// first wins over month first because that is what European banks export;
+// files where a day exceeds 12 still resolve to the month-first layout.
+var csvImportDateFormats = []struct {
+	Format string
+	Layout string
+}{
+	{"YYYY-MM-DD", "2006-1-2"},
+	{"DD.MM.YYYY", "2.1.2006"},
+	{"DD/MM/YYYY", "2/1/2006"},
+	{"DD-MM-YYYY", "2-1-2006"},
+	{"MM/DD/YYYY", "1/2/2006"},
+	{"YYYY/MM/DD", "2006/1/2"},
+	{"DD.MM.YY", "2.1.06"},
+}
+
+// csvImportColumnHints maps normalized header names seen in English and
+// Bulgarian bank exports to the column they most likely hold.
+var csvImportColumnHints = []struct {
+	Column  string
+	Headers []string
+}{
+	{"date", []string{"date", "booking date", "transaction date", "completed date", "value date", "posting date", "дата", "дата на операцията", "дата на плащане", "вальор"}},
+	{"amount", []string{"amount", "amount (eur)", "transaction amount", "sum", "сума", "сума (eur)"}},
+	{"debit", []string{"debit", "debit amount", "withdrawal", "withdrawals", "paid out", "money out", "дебит"}},
+	{"credit", []string{"credit", "credit amount", "deposit", "deposits", "paid in", "money in", "кредит"}},
+	{"description", []string{"description", "details", "narrative", "payee", "merchant", "reference", "memo", "описание", "основание", "наредител/получател", "контрагент"}},
+	{"category", []string{"category", "money manager category", "категория"}},
+	{"currency", []string{"currency", "валута"}},
+}
+
+func detectCSVEncoding(contents []byte) string {
+	switch {
+	case bytes.HasPrefix(contents, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(contents, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	case bytes.HasPrefix(contents, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	case utf8.Valid(contents):
+		return "utf-8"
+	}
+	// Cyrillic words in Windows-1251 are runs of high bytes, while accented
+	// Latin letters in Windows-1252 usually sit alone between ASCII letters.
+	paired, isolated := 0, 0
+	for index, value := range contents {
+		if value < 0x80 {
+			continue
+		}
+		if (index > 0 && contents[index-1] >= 0x80) || (index+1 < len(contents) && contents[index+1] >= 0x80) {
+			paired++
+		} else {
+			isolated++
+		}
+	}
+	if paired > isolated {
+		return "windows-1251"
+	}
+	return "windows-1252"
+}
+
+func decodeCSVImport(contents []byte, encodingName string) (string, error) {
+	decoder, ok := csvImportEncodings[encodingName]
+	if !ok {
+		return "", errors.New("unsupported CSV encoding")
+	}
+	decoded, err := decoder.NewDecoder().Bytes(contents)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(decoded) {
+		return "", errors.New("CSV is not valid text in the selected encoding")
+	}
+	return string(decoded), nil
+}
+
+func readCSVImportRecords(text, delimiter string) ([][]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma, _ = utf8.DecodeRuneInString(delimiter)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader.ReadAll()
+}
+
+// detectCSVDelimiter picks the candidate that splits the first lines into
+// the same, largest number of columns.
+func detectCSVDelimiter(text string) string {
+	lines := make([]string, 0, 10)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+		if len(lines) == 10 {
+			break
+		}
+	}
+	best, bestColumns := ",", 1
+	for _, delimiter := range csvImportDelimiters {
+		records, err := readCSVImportRecords(strings.Join(lines, "\n"), delimiter)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		columns := len(records[0])
+		consistent := true
+		for _, record := range records[1:] {
+			if len(record) != columns {
+				consistent = false
+				break
+			}
+		}
+		if consistent && columns > bestColumns {
+			best, bestColumns = delimiter, columns
+		}
+	}
+	return best
+}
+
+func suggestCSVImportColumns(headers []string) map[string]int {
+	suggested := map[string]int{}
+	for index, header := range headers {
+		normalized := normalizeCSVHeader(header)
+		for _, hint := range csvImportColumnHints {
+			if _, taken := suggested[hint.Column]; taken {
+				continue
+			}
+			for _, candidate := range hint.Headers {
+				if normalized == candidate {
+					suggested[hint.Column] = index
+					break
+				}
+			}
+		}
+	}
+	return suggested
+}
+
+func detectCSVDateFormat(values []string) string {
+	for _, format := range csvImportDateFormats {
+		parsed := 0
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			if _, err := parseCSVImportDate(value, format.Layout); err != nil {
+				parsed = -1
+				break
+			}
+			parsed++
+		}
+		if parsed > 0 {
+			return format.Format
+		}
+	}
+	return ""
+}
+
+func csvImportDateLayout(format string) (string, bool) {
+	for _, candidate := range csvImportDateFormats {
+		if candidate.Format == format {
+			return candidate.Layout, true
+		}
+	}
+	return "", false
+}
+
+// parseCSVImportDate ignores a trailing time of day, which many banks append
+// to the booking date.
+func parseCSVImportDate(value, layout string) (time.Time, error) {
+	fields := strings.FieldsFunc(strings.TrimSpace(value), func(character rune) bool {
+		return character == ' ' || character == 'T'
+	})
+	if len(fields) == 0 {
+		return time.Time{}, errors.New("empty date")
+	}
+	return time.Parse(layout, fields[0])
+}
+
+func detectCSVDecimalSeparator(values []string) string {
+	comma, dot := 0, 0
+	for _, value := range values {
+		value = strings.Map(func(character rune) rune {
+			if (character >= '0' && character <= '9') || character == '.' || character == ',' {
+				return character
+			}
+			return -1
+		}, value)
+		lastComma, lastDot := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+		switch {
+		case lastComma >= 0 && lastDot >= 0 && lastComma > lastDot:
+			comma++
+		case lastComma >= 0 && lastDot >= 0:
+			dot++
+		case lastComma >= 0 && strings.Count(value, ",") == 1 && len(value)-lastComma-1 != 3:
+			comma++
+		case lastDot >= 0:
+			dot++
+		}
+	}
+	if comma > dot {
+		return ","
+	}
+	return "."
+}
+
+// csvImportAmountMarkers are the debit and credit suffixes some banks print
+// instead of a sign, mapped to whether they make the amount negative.
+var csvImportAmountMarkers = map[string]bool{"DR": true, "DT": true, "CR": false, "CT": false}
+
+// parseCSVImportAmount returns the unsigned decimal and whether the value was
+// negative. Currency markers, grouping separators, parentheses, trailing minus
+// signs and DR/CR suffixes are accepted because bank exports use all of them.
+// Letters are only allowed as a leading or trailing currency code or debit or
+// credit marker, so values such as 1e5 are rejected instead of misread.
+func parseCSVImportAmount(value, decimalSeparator string) (string, bool, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false, false
+	}
+	negative := false
+	first := strings.IndexFunc(value, func(character rune) bool { return !isASCIILetter(character) })
+	if first < 0 {
+		return "", false, false
+	}
+	last := strings.LastIndexFunc(value, func(character rune) bool { return !isASCIILetter(character) })
+	_, width := utf8.DecodeRuneInString(value[last:])
+	words := append(strings.Fields(value[:first]), strings.Fields(value[last+width:])...)
+	for _, word := range words {
+		if marksDebit, ok := csvImportAmountMarkers[strings.ToUpper(word)]; ok {
+			negative = negative != marksDebit
+			continue
+		}
+		if len(word) != 3 || strings.ToUpper(word) != word {
+			return "", false, false
+		}
+	}
+	value = strings.TrimSpace(value[first : last+width])
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = !negative
+		value = value[1 : len(value)-1]
+	}
+	grouping := "."
+	if decimalSeparator == "." {
+		grouping = ","
+	}
+	var builder strings.Builder
+	for _, character := range value {
+		switch {
+		case character >= '0' && character <= '9':
+			builder.WriteRune(character)
+		case string(character) == decimalSeparator:
+			builder.WriteByte('.')
+		case character == '-' || character == '−':
+			negative = !negative
+		case character == '+', string(character) == grouping, character == ' ', character == '\u00a0', character == '\'':
+		case character == '€':
+		default:
+			return "", false, false
+		}
+	}
+	amount := builder.String()
+	if amount == "" || strings.Count(amount, ".") > 1 {
+		return "", false, false
+	}
+	return amount, negative, true
+}
+
+func isASCIILetter(character rune) bool {
+	return (character >= 'A' && character <= 'Z') || (character >= 'a' && character <= 'z')
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumCSVImportProfileNameRunes = 80
+	maximumCSVImportHeaderRunes      = 100
+	csvImportSampleRows              = 5
+	csvImportDetectionRows           = 50
+)
+
+// DetectCSVImport inspects an uploaded statement and suggests the settings
+// for a column-mapping profile. Nothing is stored.
+func (s *Service) DetectCSVImport(_ context.Context, _ int, contents []byte) (model.CSVImportDetection, error) {
+	encodingName := detectCSVEncoding(contents)
+	text, err := decodeCSVImport(contents, encodingName)
+	if err != nil {
+		return model.CSVImportDetection{}, apperrors.Validation("file must be a valid CSV")
+	}
+	delimiter := detectCSVDelimiter(text)
+	records, err := readCSVImportRecords(text, delimiter)
+	if err != nil || len(records) < 2 {
+		return model.CSVImportDetection{}, apperrors.Validation("CSV must contain a header and at least one transaction")
+	}
+	headers := make([]string, len(records[0]))
+	for index, header := range records[0] {
+		headers[index] = strings.TrimSpace(strings.TrimPrefix(header, "\ufeff"))
+	}
+	detection := model.CSVImportDetection{
+		Delimiter: delimiter, Encoding: encodingName, Headers: headers,
+		SampleRows: records[1:min(len(records), csvImportSampleRows+1)],
+	}
+	suggested := suggestCSVImportColumns(headers)
+	column := func(name string) string {
+		if index, ok := suggested[name]; ok {
+			return headers[index]
+		}
+		return ""
+	}
+	values := func(names ...string) []string {
+		out := make([]string, 0, csvImportDetectionRows)
+		for _, record := range records[1:min(len(records), csvImportDetectionRows+1)] {
+			for _, name := range names {
+				if index, ok := suggested[name]; ok && index < len(record) {
+					out = append(out, record[index])
+				}
+			}
+		}
+		return out
+	}
+	detection.Columns = model.CSVImportColumns{
+		Date: column("date"), Description: column("description"),
+		Category: column("category"), Currency: column("currency"),
+	}
+	if amount := column("amount"); amount != "" {
+		detection.Columns.Amount = amount
+	} else {
+		detection.Columns.Debit, detection.Columns.Credit = column("debit"), column("credit")
+	}
+	detection.DateFormat = detectCSVDateFormat(values("date"))
+	detection.DecimalSeparator = detectCSVDecimalSeparator(values("amount", "debit", "credit"))
+	return detection, nil
+}
+
+func (s *Service) ListCSVImportProfiles(ctx context.Context, userID int) ([]model.CSVImportProfile, error) {
+	items, err := s.store.ListCSVImportProfiles(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list CSV import profiles: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) CreateCSVImportProfile(ctx context.Context, userID int, request model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	normalized, err := normalizeCSVImportProfile(request)
+	if err != nil {
+		return model.CSVImportProfile{}, err
+	}
+	item, err := s.store.CreateCSVImportProfile(ctx, userID, normalized)
+	if errors.Is(err, repository.ErrConflict) {
+		return model.CSVImportProfile{}, apperrors.Conflict("a CSV import profile with this name already exists")
+	}
+	if err != nil {
+		return model.CSVImportProfile{}, apperrors.Internal(fmt.Errorf("create CSV import profile: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) UpdateCSVImportProfile(ctx context.Context, userID, profileID int, request model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	if err := validateID(profileID); err != nil {
+		return model.CSVImportProfile{}, err
+	}
+	normalized, err := normalizeCSVImportProfile(request)
+	if err != nil {
+		return model.CSVImportProfile{}, err
+	}
+	item, err := s.store.UpdateCSVImportProfile(ctx, userID, profileID, normalized)
+	if errors.Is(err, repository.ErrConflict) {
+		return model.CSVImportProfile{}, apperrors.Conflict("a CSV import profile with this name already exists")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.CSVImportProfile{}, apperrors.NotFound("CSV import profile not found")
+	}
+	if err != nil {
+		return model.CSVImportProfile{}, apperrors.Internal(fmt.Errorf("update CSV import profile: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) DeleteCSVImportProfile(ctx context.Context, userID, profileID int) error {
+	if err := validateID(profileID); err != nil {
+		return err
+	}
+	err := s.store.DeleteCSVImportProfile(ctx, userID, profileID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("CSV import profile not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete CSV import profile: %w", err))
+	}
+	return nil
+}
+
+// ImportCSV imports a statement with a saved column-mapping profile. Rows are
+// fingerprinted from their raw fields, so re-importing the same file, even
+// after the profile is corrected, never duplicates transactions.
+func (s *Service) ImportCSV(ctx context.Context, userID, profileID int, contents []byte) (model.StatementImportResult, error) {
+	rows, err := s.parseCSVImport(ctx, userID, profileID, contents)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.importStatementRows(ctx, userID, rows, "csv")
+}
+
+func (s *Service) parseCSVImport(ctx context.Context, userID, profileID int, contents []byte) ([]statementRow, error) {
+	if err := validateID(profileID); err != nil {
+		return nil, err
+	}
+	profile, err := s.store.GetCSVImportProfile(ctx, userID, profileID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, apperrors.NotFound("CSV import profile not found")
+	}
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("get CSV import profile: %w", err))
+	}
+	text, err := decodeCSVImport(contents, profile.Encoding)
+	if err != nil {
+		return nil, apperrors.Validation("file is not valid " + profile.Encoding + " text")
+	}
+	records, err := readCSVImportRecords(text, profile.Delimiter)
+	if err != nil {
+		return nil, apperrors.Validation("file must be a valid CSV")
+	}
+	if len(records) < 2 {
+		return nil, apperrors.Validation("CSV must contain a header and at least one transaction")
+	}
+	if len(records)-1 > maximumImportRows {
+		return nil, apperrors.Validation("CSV contains more than 5000 transactions")
+	}
+	headers := make(map[string]int, len(records[0]))
+	for index, header := range records[0] {
+		headers[normalizeCSVHeader(header)] = index
+	}
+	mapped := []string{profile.Columns.Date, profile.Columns.Amount, profile.Columns.Debit, profile.Columns.Credit,
+		profile.Columns.Description, profile.Columns.Category, profile.Columns.Currency}
+	for _, column := range mapped {
+		if _, ok := headers[column]; column != "" && !ok {
+			return nil, apperrors.Validation(fmt.Sprintf("CSV is missing the mapped column %q", column))
+		}
+	}
+	dateLayout, _ := csvImportDateLayout(profile.DateFormat)
+
+	rows := make([]statementRow, 0, len(records)-1)
+	for rowIndex, record := range records[1:] {
+		row := statementRow{Line: rowIndex + 2, Currency: supportedCurrency}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			row.Ignored = "row is blank"
+			rows = append(rows, row)
+			continue
+		}
+		field := func(column string) string {
+			index, ok := headers[column]
+			if column == "" || !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		row.Fingerprint = statementFingerprint(record...)
+		row.Description = field(profile.Columns.Description)
+		row.Category = field(profile.Columns.Category)
+		if currency := field(profile.Columns.Currency); currency != "" {
+			row.Currency = currency
+		}
+		if date, err := parseCSVImportDate(field(profile.Columns.Date), dateLayout); err == nil {
+			row.Date = date
+		}
+		row.Type, row.Amount, row.Invalid = csvImportAmount(profile, field)
+		rows = append(rows, row)
+	}
+	disambiguateFingerprints(rows)
+	return rows, nil
+}
+
+// disambiguateFingerprints keeps genuinely repeated rows, such as two equal
+// card payments on the same day, from collapsing into one transaction. The
+// first occurrence keeps its plain fingerprint so earlier imports still match.
+func disambiguateFingerprints(rows []statementRow) {
+	seen := make(map[string]int, len(rows))
+	for index, row := range rows {
+		if row.Fingerprint == "" {
+			continue
+		}
+		seen[row.Fingerprint]++
+		if occurrence := seen[row.Fingerprint]; occurrence > 1 {
+			rows[index].Fingerprint = statementFingerprint(row.Fingerprint, strconv.Itoa(occurrence))
+		}
+	}
+}
+
+// csvImportAmount reads either one signed amount column or a debit/credit
+// pair. Debit columns are expenses whatever sign the bank prints.
+func csvImportAmount(profile model.CSVImportProfile, field func(string) string) (string, string, string) {
+	if profile.Columns.Amount != "" {
+		amount, negative, ok := parseCSVImportAmount(field(profile.Columns.Amount), profile.DecimalSeparator)
+		if !ok {
+			return "", "", "amount"
+		}
+		if negative {
+			return "expense", amount, ""
+		}
+		return "income", amount, ""
+	}
+	debit, _, hasDebit := parseCSVImportAmount(field(profile.Columns.Debit), profile.DecimalSeparator)
+	credit, _, hasCredit := parseCSVImportAmount(field(profile.Columns.Credit), profile.DecimalSeparator)
+	switch {
+	case hasDebit && !isZeroCSVAmount(debit):
+		return "expense", debit, ""
+	case hasCredit:
+		return "income", credit, ""
+	case hasDebit:
+		return "expense", debit, ""
+	default:
+		return "", "", "amount"
+	}
+}
+
+func normalizeCSVImportProfile(request model.CSVImportProfileRequest) (model.CSVImportProfileRequest, error) {
+	name, err := normalizeLimitedText(request.Name, "name", maximumCSVImportProfileNameRunes, false)
+	if err != nil {
+		return model.CSVImportProfileRequest{}, err
+	}
+	if !slices.Contains(csvImportDelimiters, request.Delimiter) {
+		return model.CSVImportProfileRequest{}, apperrors.Validation("delimiter must be a comma, semicolon, tab, or pipe")
+	}
+	encodingName := strings.ToLower(strings.TrimSpace(request.Encoding))
+	if _, ok := csvImportEncodings[encodingName]; !ok {
+		return model.CSVImportProfileRequest{}, apperrors.Validation(
+			"encoding must be utf-8, utf-16le, utf-16be, windows-1251, or windows-1252",
+		)
+	}
+	dateFormat := strings.ToUpper(strings.TrimSpace(request.DateFormat))
+	if _, ok := csvImportDateLayout(dateFormat); !ok {
+		return model.CSVImportProfileRequest{}, apperrors.Validation(
+			"date_format must be YYYY-MM-DD, DD.MM.YYYY, DD/MM/YYYY, DD-MM-YYYY, MM/DD/YYYY, YYYY/MM/DD, or DD.MM.YY",
+		)
+	}
+	if request.DecimalSeparator != "." && request.DecimalSeparator != "," {
+		return model.CSVImportProfileRequest{}, apperrors.Validation("decimal_separator must be . or ,")
+	}
+	columns := request.Columns
+	for _, column := range []*string{
+		&columns.Date, &columns.Amount, &columns.Debit, &columns.Credit,
+		&columns.Description, &columns.Category, &columns.Currency,
+	} {
+		if _, err := normalizeLimitedText(*column, "column", maximumCSVImportHeaderRunes, true); err != nil {
+			return model.CSVImportProfileRequest{}, err
+		}
+		*column = normalizeCSVHeader(*column)
+	}
+	if columns.Date == "" || columns.Description == "" {
+		return model.CSVImportProfileRequest{}, apperrors.Validation("columns.date and columns.description are required")
+	}
+	hasDebitCredit := columns.Debit != "" || columns.Credit != ""
+	if (columns.Amount == "") == !hasDebitCredit {
+		return model.CSVImportProfileRequest{}, apperrors.Validation("map either columns.amount or columns.debit and columns.credit")
+	}
+	return model.CSVImportProfileRequest{
+		Name: name, Delimiter: request.Delimiter, Encoding: encodingName,
+		DateFormat: dateFormat, DecimalSeparator: request.DecimalSeparator, Columns: columns,
+	}, nil
+}
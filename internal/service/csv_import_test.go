@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestDetectCSVImportReadsBulgarianBankExport(t *testing.T) {
+	text := "Дата;Основание;Дебит;Кредит\n" +
+		"11.07.2026;Плащане LIDL;12,50;\n" +
+		"25.07.2026;Заплата;;1 250,00\n"
+	contents, err := charmap.Windows1251.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detection, err := testService(&fakeStore{}).DetectCSVImport(context.Background(), 1, contents)
+	if err != nil {
+		t.Fatalf("DetectCSVImport() error = %v", err)
+	}
+	if detection.Encoding != "windows-1251" || detection.Delimiter != ";" || detection.DateFormat != "DD.MM.YYYY" || detection.DecimalSeparator != "," {
+		t.Fatalf("detected settings = %#v", detection)
+	}
+	columns := detection.Columns
+	if columns.Date != "Дата" || columns.Description != "Основание" || columns.Debit != "Дебит" || columns.Credit != "Кредит" || columns.Amount != "" {
+		t.Fatalf("suggested columns = %#v", columns)
+	}
+	if len(detection.SampleRows) != 2 || detection.SampleRows[0][1] != "Плащане LIDL" {
+		t.Fatalf("sample rows = %#v", detection.SampleRows)
+	}
+}
+
+func TestImportCSVUsesProfileAndReportsRejectedRows(t *testing.T) {
+	profile := model.CSVImportProfile{
+		ID: 4, Delimiter: ";", Encoding: "utf-8", DateFormat: "DD.MM.YYYY", DecimalSeparator: ",",
+		Columns: model.CSVImportColumns{Date: "date", Debit: "debit", Credit: "credit", Description: "details"},
+	}
+	store := &fakeStore{
+		getCSVImportProfile: func(_ context.Context, userID, profileID int) (model.CSVImportProfile, error) {
+			if userID != 7 || profileID != 4 {
+				t.Fatalf("profile lookup = %d/%d", userID, profileID)
+			}
+			return profile, nil
+		},
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			if len(transactions) != 2 {
+				t.Fatalf("imported rows = %#v", transactions)
+			}
+			expense, income := transactions[0], transactions[1]
+			if expense.Source != "csv" || expense.Fingerprint == "" || expense.Request.Type != "expense" || expense.Request.Amount != "1234.50" || expense.Request.OccurredAt != "2026-07-11" {
+				t.Fatalf("expense row = %#v", expense)
+			}
+			if income.Request.Type != "income" || income.Request.Amount != "2000.00" || income.Request.Category != "salary" {
+				t.Fatalf("income row = %#v", income)
+			}
+			return 2, 0, nil
+		},
+	}
+	contents := []byte("Date;Details;Debit;Credit\n" +
+		"11.07.2026;Furniture store;1.234,50;\n" +
+		"31.07.2026;Salary July;;2000\n" +
+		"32.07.2026;Broken date;5,00;\n" +
+		"01.08.2026;Zero fee;0,00;\n")
+	result, err := testService(store).ImportCSV(context.Background(), 7, 4, contents)
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	if result.Imported != 2 || result.Ignored != 1 || len(result.Rejected) != 1 || result.Rejected[0].Row != 4 || result.Rejected[0].Reason != "has an invalid date" {
+		t.Fatalf("ImportCSV() = %#v", result)
+	}
+}
+
+func TestImportCSVReadsDebitMarkersAndRejectsOtherLetters(t *testing.T) {
+	store := &fakeStore{
+		getCSVImportProfile: func(context.Context, int, int) (model.CSVImportProfile, error) {
+			return model.CSVImportProfile{
+				ID: 4, Delimiter: ",", Encoding: "utf-8", DateFormat: "YYYY-MM-DD", DecimalSeparator: ".",
+				Columns: model.CSVImportColumns{Date: "date", Amount: "amount", Description: "details"},
+			}, nil
+		},
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			if len(transactions) != 1 || transactions[0].Request.Type != "expense" || transactions[0].Request.Amount != "12.50" {
+				t.Fatalf("imported rows = %#v", transactions)
+			}
+			return 1, 0, nil
+		},
+	}
+	contents := []byte("date,details,amount\n" +
+		"2026-07-11,Bookshop,12.50 DR\n" +
+		"2026-07-12,Exponent typo,1e5\n")
+	result, err := testService(store).ImportCSV(context.Background(), 7, 4, contents)
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	if result.Imported != 1 || len(result.Rejected) != 1 ||
+		result.Rejected[0] != (model.ImportRejection{Row: 3, Reason: "has an invalid amount"}) {
+		t.Fatalf("ImportCSV() = %#v", result)
+	}
+}
+
+func TestImportCSVKeepsIdenticalRows(t *testing.T) {
+	profile := model.CSVImportProfile{
+		ID: 4, Delimiter: ",", Encoding: "utf-8", DateFormat: "YYYY-MM-DD", DecimalSeparator: ".",
+		Columns: model.CSVImportColumns{Date: "date", Amount: "amount", Description: "details"},
+	}
+	fingerprints := make([]string, 0)
+	store := &fakeStore{
+		getCSVImportProfile: func(context.Context, int, int) (model.CSVImportProfile, error) { return profile, nil },
+		findCategory:        func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			for _, transaction := range transactions {
+				fingerprints = append(fingerprints, transaction.Fingerprint)
+			}
+			return len(transactions), 0, nil
+		},
+	}
+	// Two coffees of the same price on the same day are two payments.
+	contents := []byte("date,details,amount\n" +
+		"2026-07-11,Coffee,-3.20\n" +
+		"2026-07-11,Coffee,-3.20\n")
+	result, err := testService(store).ImportCSV(context.Background(), 7, 4, contents)
+	if err != nil || result.Imported != 2 {
+		t.Fatalf("ImportCSV() = %#v, %v", result, err)
+	}
+	if len(fingerprints) != 2 || fingerprints[0] == fingerprints[1] {
+		t.Fatalf("fingerprints = %#v", fingerprints)
+	}
+	if fingerprints[0] != statementFingerprint("2026-07-11", "Coffee", "-3.20") {
+		t.Fatalf("first fingerprint changed: %q", fingerprints[0])
+	}
+}
+
+func TestCSVImportProfileValidation(t *testing.T) {
+	valid := model.CSVImportProfileRequest{
+		Name: "DSK", Delimiter: ";", Encoding: "UTF-8", DateFormat: "dd.mm.yyyy", DecimalSeparator: ",",
+		Columns: model.CSVImportColumns{Date: " Booking  Date ", Amount: "Amount", Description: "Details"},
+	}
+	created, err := testService(&fakeStore{}).CreateCSVImportProfile(context.Background(), 1, valid)
+	if err != nil || created.Columns.Date != "booking date" || created.Columns.Amount != "amount" {
+		t.Fatalf("CreateCSVImportProfile() = %#v, %v", created, err)
+	}
+
+	invalid := []func(*model.CSVImportProfileRequest){
+		func(request *model.CSVImportProfileRequest) { request.Name = "" },
+		func(request *model.CSVImportProfileRequest) { request.Delimiter = ":" },
+		func(request *model.CSVImportProfileRequest) { request.Encoding = "koi8-r" },
+		func(request *model.CSVImportProfileRequest) { request.DateFormat = "YYYYMMDD" },
+		func(request *model.CSVImportProfileRequest) { request.DecimalSeparator = " " },
+		func(request *model.CSVImportProfileRequest) { request.Columns.Description = "" },
+		func(request *model.CSVImportProfileRequest) { request.Columns.Debit = "Debit" },
+		func(request *model.CSVImportProfileRequest) { request.Columns.Amount = "" },
+	}
+	for index, mutate := range invalid {
+		request := valid
+		mutate(&request)
+		if _, err := testService(&fakeStore{}).CreateCSVImportProfile(context.Background(), 1, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("case %d error = %v", index, err)
+		}
+	}
+}
+
+func TestParseCSVImportAmountFormats(t *testing.T) {
+	tests := []struct {
+		value, separator, amount string
+		negative                 bool
+	}{
+		{"-12.50", ".", "12.50", true},
+		{"1,234.56", ".", "1234.56", false},
+		{"1.234,56 EUR", ",", "1234.56", false},
+		{"(45,00)", ",", "45.00", true},
+		{"€ 9.99-", ".", "9.99", true},
+		{"+1 000,5", ",", "1000.5", false},
+		{"12.50 DR", ".", "12.50", true},
+		{"EUR 12.50 Cr", ".", "12.50", false},
+		{"1.234,56 Dt", ",", "1234.56", true},
+		{"-12.50 DR", ".", "12.50", false},
+	}
+	for _, test := range tests {
+		amount, negative, ok := parseCSVImportAmount(test.value, test.separator)
+		if !ok || amount != test.amount || negative != test.negative {
+			t.Errorf("parseCSVImportAmount(%q) = %q, %v, %v", test.value, amount, negative, ok)
+		}
+	}
+	for _, value := range []string{"12.5.0", "1e5", "12.50 debit", "EURO 12.50", "12.50 eur", "12 x 50", "DR"} {
+		if _, _, ok := parseCSVImportAmount(value, "."); ok {
+			t.Errorf("parseCSVImportAmount(%q) was accepted", value)
+		}
+	}
+}
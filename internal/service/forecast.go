@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/recurrence"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	// forecastLookbackDays of history, ending yesterday, train the average
+	// daily income and spending per category.
+	forecastLookbackDays = 90
+	// forecastBandWidth is the z-score of a two-sided 90% normal band.
+	forecastBandWidth = 1.645
+	// Bank balances recorded longer ago than this are too stale to start from.
+	forecastBalanceMaxAgeDays = 365
+)
+
+// forecastBucket is one projected balance: a bank account, or the flows
+// that belong to no known account. flows holds the known amounts per day
+// ahead, index 0 being today.
+type forecastBucket struct {
+	accountID   int
+	name        string
+	balanceDate time.Time
+	start       *big.Rat
+	flows       []*big.Rat
+	rate        *big.Rat
+	variance    float64
+}
+
+// forecastSeries is the daily history of one category in one bucket.
+type forecastSeries struct {
+	bucket          *forecastBucket
+	transactionType string
+	category        string
+	total           *big.Rat
+	daily           []float64
+}
+
+func (s *Service) GetForecastSettings(ctx context.Context, userID int) (model.ForecastSettings, error) {
+	settings, err := s.store.GetForecastSettings(ctx, userID)
+	if err != nil {
+		return model.ForecastSettings{}, apperrors.Internal(fmt.Errorf("get forecast settings: %w", err))
+	}
+	return settings, nil
+}
+
+func (s *Service) UpdateForecastSettings(ctx context.Context, userID int, settings model.ForecastSettings) (model.ForecastSettings, error) {
+	if strings.TrimSpace(settings.LowBalanceThreshold) != "" {
+		threshold, err := normalizeAmount(settings.LowBalanceThreshold)
+		if err != nil {
+			return model.ForecastSettings{}, apperrors.Validation(
+				"low_balance_threshold must be a positive decimal with at most 2 decimal places",
+			)
+		}
+		settings.LowBalanceThreshold = threshold
+	} else {
+		settings.LowBalanceThreshold = ""
+	}
+	updated, err := s.store.UpdateForecastSettings(ctx, userID, settings)
+	if err != nil {
+		return model.ForecastSettings{}, apperrors.Internal(fmt.Errorf("update forecast settings: %w", err))
+	}
+	return updated, nil
+}
+
+// Forecast projects daily balances from today through the given date, by
+// default as far as schedule occurrences are materialized. Planned schedule
+// occurrences, detected subscriptions and investment schedules are placed
+// on their dates; everything else is the recent daily average per category.
+func (s *Service) Forecast(ctx context.Context, userID int, through string) (model.Forecast, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.Forecast{}, apperrors.Internal(err)
+	}
+	horizon := today.AddDate(0, 0, s.scheduleHorizonDays)
+	end := horizon
+	if strings.TrimSpace(through) != "" {
+		if end, err = parseDate(through, "through"); err != nil {
+			return model.Forecast{}, err
+		}
+		if !end.After(today) || end.After(horizon) {
+			return model.Forecast{}, apperrors.Validation(
+				fmt.Sprintf("through must be after today and at most %d days ahead", s.scheduleHorizonDays),
+			)
+		}
+	}
+	days := int(end.Sub(today).Hours() / 24)
+
+	settings, err := s.GetForecastSettings(ctx, userID)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+	buckets, err := s.forecastBuckets(ctx, userID, today, days)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+	byAccount := make(map[int]*forecastBucket, len(buckets))
+	for _, bucket := range buckets {
+		byAccount[bucket.accountID] = bucket
+	}
+	unassigned := byAccount[0]
+
+	schedules, err := s.store.ListTransactionSchedules(ctx, userID, "active", s.now().UTC())
+	if err != nil {
+		return model.Forecast{}, apperrors.Internal(fmt.Errorf("list forecast schedules: %w", err))
+	}
+	detected, _, err := s.detectSubscriptions(ctx, userID)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+	keys, err := s.loadMerchantKeys(ctx, userID)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+	// projectedMerchants are the merchants whose charges are projected on
+	// their dates, by a schedule or as a detected subscription.
+	projectedMerchants := make(map[string]bool, len(schedules)+len(detected))
+	activeSchedules := make(map[int]bool, len(schedules))
+	for _, schedule := range schedules {
+		activeSchedules[schedule.ID] = true
+		if schedule.Type == "expense" {
+			projectedMerchants[keys.key(nil, schedule.Description)] = true
+		}
+	}
+	subscriptions := make([]detectedSubscription, 0, len(detected))
+	latestCharges := make(map[int]*forecastBucket, len(detected))
+	for _, item := range detected {
+		if projectedMerchants[item.Merchant] {
+			continue
+		}
+		subscriptions = append(subscriptions, item)
+		latestCharges[item.latestTransactionID] = unassigned
+	}
+	for _, item := range subscriptions {
+		projectedMerchants[item.Merchant] = true
+	}
+
+	series, err := s.forecastHistory(ctx, userID, today, byAccount, keys, projectedMerchants, latestCharges)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+
+	forecast := model.Forecast{
+		From: today.Format(time.DateOnly), Through: end.Format(time.DateOnly), Currency: supportedCurrency,
+		LowBalanceThreshold: settings.LowBalanceThreshold,
+		Flows:               make([]model.ForecastFlow, 0), Warnings: make([]model.ForecastWarning, 0),
+	}
+	addFlow := func(bucket *forecastBucket, date time.Time, flow model.ForecastFlow, amount *big.Rat) {
+		offset := int(date.Sub(today).Hours() / 24)
+		if offset < 1 || offset > days {
+			return
+		}
+		bucket.flows[offset].Add(bucket.flows[offset], amount)
+		flow.Date, flow.Amount = date.Format(time.DateOnly), formatRat(amount, 2)
+		if bucket.accountID != 0 {
+			flow.AccountID = &bucket.accountID
+		}
+		forecast.Flows = append(forecast.Flows, flow)
+	}
+
+	occurrences, err := s.store.ListTransactionScheduleOccurrences(ctx, userID, repository.ScheduleOccurrenceFilter{
+		From: today.AddDate(0, 0, 1), Through: end, Status: "planned",
+	})
+	if err != nil {
+		return model.Forecast{}, apperrors.Internal(fmt.Errorf("list forecast occurrences: %w", err))
+	}
+	for _, occurrence := range occurrences {
+		date, dateErr := time.Parse(time.DateOnly, occurrence.ScheduledFor)
+		amount, ok := new(big.Rat).SetString(occurrence.Amount)
+		if !activeSchedules[occurrence.ScheduleID] || dateErr != nil || !ok || occurrence.Currency != supportedCurrency {
+			continue
+		}
+		if occurrence.Type == "expense" {
+			amount.Neg(amount)
+		}
+		addFlow(unassigned, date, model.ForecastFlow{
+			Source: "schedule", Name: occurrence.Name, Category: occurrence.Category, Estimated: occurrence.Estimated,
+		}, amount)
+	}
+
+	for _, item := range subscriptions {
+		dates, err := recurrence.Occurrences(item.rule, today.AddDate(0, 0, 1), end)
+		if err != nil {
+			return model.Forecast{}, apperrors.Internal(fmt.Errorf("project subscription %q: %w", item.Merchant, err))
+		}
+		amount, ok := new(big.Rat).SetString(item.Amount)
+		if !ok {
+			continue
+		}
+		amount.Neg(amount)
+		for _, date := range dates {
+			addFlow(latestCharges[item.latestTransactionID], date, model.ForecastFlow{
+				Source: "subscription", Name: subscriptionScheduleName(item.Subscription), Category: item.Category,
+			}, amount)
+		}
+	}
+
+	investmentSchedules, err := s.store.ListInvestmentSchedules(ctx, userID, "active")
+	if err != nil {
+		return model.Forecast{}, apperrors.Internal(fmt.Errorf("list forecast investment schedules: %w", err))
+	}
+	investmentOccurrences, err := s.store.ListInvestmentScheduleOccurrences(ctx, userID, repository.ScheduleOccurrenceFilter{
+		From: today.AddDate(0, 0, 1), Through: end, Status: "planned",
+	})
+	if err != nil {
+		return model.Forecast{}, apperrors.Internal(fmt.Errorf("list forecast investment occurrences: %w", err))
+	}
+	activeInvestmentSchedules := make(map[int]bool, len(investmentSchedules))
+	for _, schedule := range investmentSchedules {
+		activeInvestmentSchedules[schedule.ID] = true
+	}
+	// Stored occurrences carry per-occurrence edits and skips; the rule only
+	// projects the dates past the materialized range.
+	for _, occurrence := range investmentOccurrences {
+		date, dateErr := time.Parse(time.DateOnly, occurrence.ScheduledFor)
+		amount, ok := new(big.Rat).SetString(occurrence.Amount)
+		if !activeInvestmentSchedules[occurrence.ScheduleID] || dateErr != nil || !ok || occurrence.Currency != supportedCurrency {
+			continue
+		}
+		amount.Neg(amount)
+		addFlow(unassigned, date, model.ForecastFlow{Source: "investment_schedule", Name: occurrence.AssetName}, amount)
+	}
+	for _, schedule := range investmentSchedules {
+		from := today.AddDate(0, 0, 1)
+		if schedule.MaterializedThrough != "" {
+			materializedThrough, err := time.Parse(time.DateOnly, schedule.MaterializedThrough)
+			if err != nil {
+				return model.Forecast{}, apperrors.Internal(fmt.Errorf("parse investment materialization date: %w", err))
+			}
+			if next := materializedThrough.AddDate(0, 0, 1); next.After(from) {
+				from = next
+			}
+		}
+		if from.After(end) {
+			continue
+		}
+		rule, err := investmentRecurrenceRule(schedule)
+		if err != nil {
+			return model.Forecast{}, apperrors.Internal(fmt.Errorf("build investment recurrence rule: %w", err))
+		}
+		dates, err := scheduleOccurrenceDates(
+			rule, schedule.BusinessDayAdjustment, schedule.HolidayCalendar, from, end,
+		)
+		if err != nil {
+			return model.Forecast{}, apperrors.Internal(fmt.Errorf("project investment schedule %d: %w", schedule.ID, err))
+		}
+		amount, ok := new(big.Rat).SetString(schedule.Amount)
+		if !ok || schedule.Currency != supportedCurrency {
+			continue
+		}
+		amount.Neg(amount)
+		for _, date := range dates {
+			addFlow(unassigned, date.scheduled, model.ForecastFlow{Source: "investment_schedule", Name: schedule.AssetName}, amount)
+		}
+	}
+	sort.SliceStable(forecast.Flows, func(i, j int) bool { return forecast.Flows[i].Date < forecast.Flows[j].Date })
+
+	forecast.VariableRates = forecastVariableRates(series)
+	forecast.Accounts = make([]model.ForecastAccount, 0, len(buckets))
+	totals := make([]*big.Rat, days+1)
+	for offset := range totals {
+		totals[offset] = new(big.Rat)
+	}
+	totalVariance := 0.0
+	for _, bucket := range buckets {
+		account := model.ForecastAccount{Name: bucket.name, StartingBalance: formatRat(bucket.start, 2)}
+		if bucket.accountID != 0 {
+			account.AccountID = &bucket.accountID
+			account.BalanceDate = bucket.balanceDate.Format(time.DateOnly)
+		}
+		balances := bucket.project()
+		account.Days = forecastDays(today, balances, bucket.variance)
+		for offset, balance := range balances {
+			totals[offset].Add(totals[offset], balance)
+		}
+		totalVariance += bucket.variance
+		forecast.Accounts = append(forecast.Accounts, account)
+	}
+	forecast.StartingBalance = formatRat(totals[0], 2)
+	forecast.Days = forecastDays(today, totals, totalVariance)
+	forecast.Warnings = forecastWarnings(forecast.Days, settings.LowBalanceThreshold)
+	return forecast, nil
+}
+
+// forecastBuckets starts every linked bank account with a recent recorded
+// balance, followed by the unassigned bucket.
+func (s *Service) forecastBuckets(ctx context.Context, userID int, today time.Time, days int) ([]*forecastBucket, error) {
+	accounts, err := s.store.ListOpenBankingAccounts(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list forecast accounts: %w", err))
+	}
+	balances, err := s.store.ListOpenBankingAccountBalances(ctx, userID, today.AddDate(0, 0, -forecastBalanceMaxAgeDays), today)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list forecast balances: %w", err))
+	}
+	latest := make(map[int]model.OpenBankingAccountBalance, len(balances))
+	for _, balance := range balances {
+		if balance.Currency == supportedCurrency {
+			latest[balance.AccountID] = balance
+		}
+	}
+	newBucket := func(accountID int, name string, start *big.Rat) *forecastBucket {
+		bucket := &forecastBucket{accountID: accountID, name: name, start: start, flows: make([]*big.Rat, days+1), rate: new(big.Rat)}
+		for offset := range bucket.flows {
+			bucket.flows[offset] = new(big.Rat)
+		}
+		return bucket
+	}
+	buckets := make([]*forecastBucket, 0, len(latest)+1)
+	for _, account := range accounts {
+		balance, found := latest[account.ID]
+		if !found {
+			continue
+		}
+		start, ok := new(big.Rat).SetString(balance.Amount)
+		date, err := time.Parse(time.DateOnly, balance.ReferenceDate)
+		if !ok || err != nil {
+			return nil, apperrors.Internal(fmt.Errorf("stored balance of account %d is invalid", account.ID))
+		}
+		bucket := newBucket(account.ID, forecastAccountName(account), start)
+		bucket.balanceDate = date
+		buckets = append(buckets, bucket)
+	}
+	return append(buckets, newBucket(0, "Unassigned", new(big.Rat))), nil
+}
+
+// forecastHistory rolls each account's balance forward to today and
+// collects the daily history of unplanned income and spending. Rows posted
+// by a schedule and charges of projected merchants are left out of the
+// history, since those flows are projected on their own dates. It also
+// assigns each subscription's latest charge to the account it went through.
+func (s *Service) forecastHistory(
+	ctx context.Context,
+	userID int,
+	today time.Time,
+	buckets map[int]*forecastBucket,
+	keys merchantKeys,
+	projectedMerchants map[string]bool,
+	latestCharges map[int]*forecastBucket,
+) ([]*forecastSeries, error) {
+	historyFrom := today.AddDate(0, 0, -forecastLookbackDays)
+	from := historyFrom
+	for _, bucket := range buckets {
+		if bucket.accountID != 0 && bucket.balanceDate.Before(from) {
+			from = bucket.balanceDate
+		}
+	}
+	series := make(map[string]*forecastSeries)
+	ordered := make([]*forecastSeries, 0)
+	err := s.store.StreamTransactionsWithOrigin(ctx, userID, from, today.AddDate(0, 0, 1),
+		func(transaction model.Transaction, origin string) error {
+			date, err := time.Parse(time.DateOnly, transaction.OccurredAt)
+			amount, ok := new(big.Rat).SetString(transaction.Amount)
+			if err != nil || !ok || transaction.Currency != supportedCurrency {
+				return nil
+			}
+			if transaction.Type == "expense" {
+				amount.Neg(amount)
+			}
+			bucket := buckets[0]
+			if accountID, err := strconv.Atoi(strings.TrimPrefix(origin, "open_banking:")); err == nil && buckets[accountID] != nil {
+				bucket = buckets[accountID]
+			}
+			if _, ok := latestCharges[transaction.ID]; ok {
+				latestCharges[transaction.ID] = bucket
+			}
+			if bucket.accountID != 0 && date.After(bucket.balanceDate) {
+				bucket.start.Add(bucket.start, amount)
+			}
+			if date.Before(historyFrom) || !date.Before(today) || transaction.ScheduleOccurrenceID != nil ||
+				(transaction.Type == "expense" && projectedMerchants[keys.key(transaction.MerchantID, transaction.Description)]) {
+				return nil
+			}
+			key := strconv.Itoa(bucket.accountID) + "\x00" + transaction.Type + "\x00" + transaction.Category
+			item := series[key]
+			if item == nil {
+				item = &forecastSeries{
+					bucket: bucket, transactionType: transaction.Type, category: transaction.Category,
+					total: new(big.Rat), daily: make([]float64, forecastLookbackDays),
+				}
+				series[key] = item
+				ordered = append(ordered, item)
+			}
+			item.total.Add(item.total, amount)
+			value, _ := amount.Float64()
+			item.daily[int(date.Sub(historyFrom).Hours()/24)] += value
+			return nil
+		})
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("stream forecast history: %w", err))
+	}
+	for _, item := range ordered {
+		mean := new(big.Rat).Quo(item.total, big.NewRat(forecastLookbackDays, 1))
+		meanValue, _ := mean.Float64()
+		variance := 0.0
+		for _, value := range item.daily {
+			variance += (value - meanValue) * (value - meanValue)
+		}
+		item.bucket.rate.Add(item.bucket.rate, mean)
+		item.bucket.variance += variance / forecastLookbackDays
+	}
+	return ordered, nil
+}
+
+// project returns the expected balance for today and every day ahead.
+func (b *forecastBucket) project() []*big.Rat {
+	balances := make([]*big.Rat, len(b.flows))
+	balance := new(big.Rat).Set(b.start)
+	for offset := range b.flows {
+		if offset > 0 {
+			balance.Add(balance, b.rate)
+			balance.Add(balance, b.flows[offset])
+		}
+		balances[offset] = new(big.Rat).Set(balance)
+	}
+	return balances
+}
+
+// forecastDays widens each day's band with the variance of the daily
+// averages accumulated since today, treating days as independent.
+func forecastDays(today time.Time, balances []*big.Rat, variance float64) []model.ForecastDay {
+	days := make([]model.ForecastDay, 0, len(balances))
+	for offset, balance := range balances {
+		width := forecastBandWidth * math.Sqrt(float64(offset)*variance)
+		value, _ := balance.Float64()
+		days = append(days, model.ForecastDay{
+			Date:    today.AddDate(0, 0, offset).Format(time.DateOnly),
+			Balance: formatRat(balance, 2),
+			Low:     strconv.FormatFloat(value-width, 'f', 2, 64),
+			High:    strconv.FormatFloat(value+width, 'f', 2, 64),
+		})
+	}
+	return days
+}
+
+// forecastVariableRates sums each category's daily average over buckets.
+// Spending is reported as a positive amount.
+func forecastVariableRates(series []*forecastSeries) []model.ForecastCategoryRate {
+	type key struct{ transactionType, category string }
+	totals := make(map[key]*big.Rat)
+	keys := make([]key, 0)
+	for _, item := range series {
+		rateKey := key{item.transactionType, item.category}
+		if totals[rateKey] == nil {
+			totals[rateKey] = new(big.Rat)
+			keys = append(keys, rateKey)
+		}
+		totals[rateKey].Add(totals[rateKey], new(big.Rat).Quo(item.total, big.NewRat(forecastLookbackDays, 1)))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].transactionType != keys[j].transactionType {
+			return keys[i].transactionType < keys[j].transactionType
+		}
+		if order := new(big.Rat).Abs(totals[keys[i]]).Cmp(new(big.Rat).Abs(totals[keys[j]])); order != 0 {
+			return order > 0
+		}
+		return keys[i].category < keys[j].category
+	})
+	rates := make([]model.ForecastCategoryRate, 0, len(keys))
+	for _, rateKey := range keys {
+		rates = append(rates, model.ForecastCategoryRate{
+			Type: rateKey.transactionType, Category: rateKey.category,
+			DailyAverage: formatRat(new(big.Rat).Abs(totals[rateKey]), 2),
+		})
+	}
+	return rates
+}
+
+func forecastWarnings(days []model.ForecastDay, threshold string) []model.ForecastWarning {
+	warnings := make([]model.ForecastWarning, 0, 2)
+	limit, ok := new(big.Rat).SetString(threshold)
+	if !ok {
+		return warnings
+	}
+	below := func(value string) bool {
+		amount, ok := new(big.Rat).SetString(value)
+		return ok && amount.Cmp(limit) < 0
+	}
+	for _, day := range days {
+		if below(day.Balance) {
+			warnings = append(warnings, model.ForecastWarning{
+				Type: "projected_below_threshold", Date: day.Date, Balance: day.Balance, Threshold: threshold,
+			})
+			break
+		}
+	}
+	for _, day := range days {
+		if below(day.Low) {
+			warnings = append(warnings, model.ForecastWarning{
+				Type: "may_fall_below_threshold", Date: day.Date, Balance: day.Low, Threshold: threshold,
+			})
+			break
+		}
+	}
+	return warnings
+}
+
+func forecastAccountName(account model.OpenBankingAccount) string {
+	label := strings.TrimSpace(account.Name)
+	if label == "" {
+		label = strings.TrimSpace(account.DisplayIdentifier)
+	}
+	if label == "" {
+		label = "Account " + strconv.Itoa(account.ID)
+	}
+	return strings.TrimSpace(account.InstitutionName + " " + label)
+}
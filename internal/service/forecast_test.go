@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestForecastCombinesPlannedFlowsSubscriptionsAndVariableSpending(t *testing.T) {
+	occurrenceID, investmentDay := 41, 20
+	history := []struct {
+		transaction model.Transaction
+		origin      string
+	}{
+		{model.Transaction{ID: 1, Type: "income", Category: "salary", Description: "Salary", Amount: "2000.00", Currency: "EUR",
+			OccurredAt: "2026-07-01", ScheduleOccurrenceID: &occurrenceID}, "schedule"},
+		{model.Transaction{ID: 2, Type: "expense", Category: "groceries", Description: "Lidl", Amount: "45.00", Currency: "EUR",
+			OccurredAt: "2026-07-17"}, "open_banking:3"},
+	}
+	for month := 5; month <= 7; month++ {
+		history = append(history,
+			struct {
+				transaction model.Transaction
+				origin      string
+			}{model.Transaction{ID: 10 + month, Type: "expense", Category: "entertainment", Description: "NETFLIX.COM", Amount: "12.99",
+				Currency: "EUR", OccurredAt: fmt.Sprintf("2026-%02d-05", month)}, "open_banking:3"},
+			struct {
+				transaction model.Transaction
+				origin      string
+			}{model.Transaction{ID: 20 + month, Type: "expense", Category: "entertainment", Description: "SPOTIFY", Amount: "9.99",
+				Currency: "EUR", OccurredAt: fmt.Sprintf("2026-%02d-10", month)}, "manual"},
+		)
+	}
+	var occurrenceFilter repository.ScheduleOccurrenceFilter
+	threshold := model.ForecastSettings{LowBalanceThreshold: "950.00"}
+	store := &fakeStore{
+		forecastSettings: &threshold,
+		listOpenBankingAccounts: func(context.Context, int) ([]model.OpenBankingAccount, error) {
+			return []model.OpenBankingAccount{{ID: 3, InstitutionName: "Revolut", Name: "Checking"}, {ID: 4, InstitutionName: "Revolut"}}, nil
+		},
+		listOpenBankingAccountBalances: func(context.Context, int, time.Time, time.Time) ([]model.OpenBankingAccountBalance, error) {
+			return []model.OpenBankingAccountBalance{
+				{AccountID: 3, ReferenceDate: "2026-07-10", Amount: "900.00", Currency: "EUR"},
+				{AccountID: 3, ReferenceDate: "2026-07-16", Amount: "1000.00", Currency: "EUR"},
+				{AccountID: 4, ReferenceDate: "2026-07-16", Amount: "50.00", Currency: "USD"},
+			}, nil
+		},
+		streamTransactions: func(_ context.Context, _ int, _, _ time.Time, visit func(model.Transaction) error) error {
+			for _, item := range history {
+				if err := visit(item.transaction); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		streamTransactionsWithOrigin: func(_ context.Context, _ int, _, _ time.Time, visit func(model.Transaction, string) error) error {
+			for _, item := range history {
+				if err := visit(item.transaction, item.origin); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		listTransactionSchedules: func(_ context.Context, _ int, status string, _ time.Time) ([]model.TransactionSchedule, error) {
+			if status != "active" {
+				t.Fatalf("schedule status = %q", status)
+			}
+			return []model.TransactionSchedule{
+				{ID: 4, Type: "income", Name: "Salary", Description: "Salary"},
+				{ID: 5, Type: "expense", Name: "Spotify", Description: "Spotify"},
+			}, nil
+		},
+		listScheduleOccurrences: func(_ context.Context, _ int, filter repository.ScheduleOccurrenceFilter) ([]model.TransactionScheduleOccurrence, error) {
+			occurrenceFilter = filter
+			return []model.TransactionScheduleOccurrence{
+				{ScheduleID: 5, ScheduledFor: "2026-07-25", Type: "expense", Name: "Spotify", Category: "entertainment", Amount: "9.99", Currency: "EUR"},
+				{ScheduleID: 4, ScheduledFor: "2026-08-01", Type: "income", Name: "Salary", Category: "salary", Amount: "2000.00", Currency: "EUR"},
+				{ScheduleID: 99, ScheduledFor: "2026-08-02", Type: "expense", Name: "Paused gym", Amount: "30.00", Currency: "EUR"},
+			}, nil
+		},
+		listInvestmentSchedules: func(context.Context, int, string) ([]model.InvestmentSchedule, error) {
+			return []model.InvestmentSchedule{{
+				ID: 8, AssetName: "World ETF", Amount: "100.00", Currency: "EUR",
+				Frequency: "monthly", FrequencyInterval: 1, StartDate: "2026-01-20", DayOfMonth: &investmentDay, Timezone: defaultScheduleTimezone,
+			}}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	forecast, err := service.Forecast(context.Background(), 7, "2026-08-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !occurrenceFilter.From.Equal(time.Date(2026, 7, 19, 0, 0, 0, 0, time.UTC)) ||
+		!occurrenceFilter.Through.Equal(time.Date(2026, 8, 10, 0, 0, 0, 0, time.UTC)) || occurrenceFilter.Status != "planned" {
+		t.Fatalf("occurrence filter = %#v", occurrenceFilter)
+	}
+	if forecast.From != "2026-07-18" || forecast.Through != "2026-08-10" || len(forecast.Days) != 24 || forecast.StartingBalance != "955.00" {
+		t.Fatalf("forecast window = %s..%s, %d days, starting %s", forecast.From, forecast.Through, len(forecast.Days), forecast.StartingBalance)
+	}
+
+	// The account starts from its latest balance plus the rows booked since.
+	if len(forecast.Accounts) != 2 || forecast.Accounts[0].AccountID == nil || *forecast.Accounts[0].AccountID != 3 ||
+		forecast.Accounts[0].Name != "Revolut Checking" || forecast.Accounts[0].BalanceDate != "2026-07-16" ||
+		forecast.Accounts[0].StartingBalance != "955.00" || forecast.Accounts[1].Name != "Unassigned" {
+		t.Fatalf("accounts = %#v", forecast.Accounts)
+	}
+	if last := forecast.Accounts[0].Days[23]; last.Balance != "930.51" {
+		t.Fatalf("account balance on %s = %s", last.Date, last.Balance)
+	}
+	if last := forecast.Accounts[1].Days[23]; last.Balance != "1890.01" || last.Low != last.Balance {
+		t.Fatalf("unassigned balance = %#v", last)
+	}
+
+	// Spotify already has a schedule, so it is not projected a second time.
+	wantFlows := []model.ForecastFlow{
+		{Date: "2026-07-20", Source: "investment_schedule", Name: "World ETF", Amount: "-100.00"},
+		{Date: "2026-07-25", Source: "schedule", Name: "Spotify", Category: "entertainment", Amount: "-9.99"},
+		{Date: "2026-08-01", Source: "schedule", Name: "Salary", Category: "salary", Amount: "2000.00"},
+		{Date: "2026-08-05", Source: "subscription", Name: "NETFLIX.COM", Category: "entertainment", Amount: "-12.99"},
+	}
+	if len(forecast.Flows) != len(wantFlows) {
+		t.Fatalf("flows = %#v", forecast.Flows)
+	}
+	for index, want := range wantFlows {
+		got := forecast.Flows[index]
+		accountID := got.AccountID
+		got.AccountID = nil
+		if got != want {
+			t.Errorf("flow %d = %#v, want %#v", index, got, want)
+		}
+		if (want.Source == "subscription") != (accountID != nil && *accountID == 3) {
+			t.Errorf("flow %d account = %v", index, accountID)
+		}
+	}
+
+	if len(forecast.VariableRates) != 1 ||
+		forecast.VariableRates[0] != (model.ForecastCategoryRate{Type: "expense", Category: "groceries", DailyAverage: "0.50"}) {
+		t.Fatalf("variable rates = %#v", forecast.VariableRates)
+	}
+	tomorrow := forecast.Days[1]
+	if tomorrow.Balance != "954.50" || tomorrow.Low != "946.74" || tomorrow.High != "962.26" {
+		t.Fatalf("tomorrow = %#v", tomorrow)
+	}
+	if forecast.Days[23].Balance != "2820.52" {
+		t.Fatalf("total on %s = %s", forecast.Days[23].Date, forecast.Days[23].Balance)
+	}
+	wantWarnings := []model.ForecastWarning{
+		{Type: "projected_below_threshold", Date: "2026-07-20", Balance: "854.00", Threshold: "950.00"},
+		{Type: "may_fall_below_threshold", Date: "2026-07-19", Balance: "946.74", Threshold: "950.00"},
+	}
+	if len(forecast.Warnings) != 2 || forecast.Warnings[0] != wantWarnings[0] || forecast.Warnings[1] != wantWarnings[1] {
+		t.Fatalf("warnings = %#v", forecast.Warnings)
+	}
+}
+
+func TestForecastValidatesThroughAndSettings(t *testing.T) {
+	store := &fakeStore{}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	forecast, err := service.Forecast(context.Background(), 7, "")
+	if err != nil || forecast.Through != "2026-10-16" || len(forecast.Warnings) != 0 || forecast.Days[90].Balance != "0.00" {
+		t.Fatalf("default forecast = %s, %d warnings, %v", forecast.Through, len(forecast.Warnings), err)
+	}
+	for _, through := range []string{"2026-07-18", "2026-10-17", "next month"} {
+		if _, err := service.Forecast(context.Background(), 7, through); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("Forecast(%q) error = %v", through, err)
+		}
+	}
+
+	if _, err := service.UpdateForecastSettings(context.Background(), 7, model.ForecastSettings{LowBalanceThreshold: "-5"}); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("negative threshold error = %v", err)
+	}
+	settings, err := service.UpdateForecastSettings(context.Background(), 7, model.ForecastSettings{LowBalanceThreshold: "12.5"})
+	if err != nil || settings.LowBalanceThreshold != "12.50" {
+		t.Fatalf("settings = %#v, %v", settings, err)
+	}
+	settings, err = service.UpdateForecastSettings(context.Background(), 7, model.ForecastSettings{LowBalanceThreshold: " "})
+	if err != nil || settings.LowBalanceThreshold != "" {
+		t.Fatalf("cleared settings = %#v, %v", settings, err)
+	}
+}
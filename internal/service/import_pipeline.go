@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+// statementRow is one parsed statement line. Parsers only translate their
+// file format into this shape; validation, categorization and storage stay in
+// one pipeline so every import source behaves the same way.
+type statementRow struct {
+	Line        int
+	Type        string
+	Amount      string
+	Currency    string
+	Date        time.Time
+	Description string
+	Category    string
+	Fingerprint string
+	// Ignored explains why a well-formed row is intentionally not imported.
+	Ignored string
+	// Invalid names the field a parser could not read.
+	Invalid string
+}
+
+type statementImportOptions struct {
+	Source string
+	// Strict fails the whole file on the first invalid row, which keeps the
+	// original Revolut contract. Other importers report rejected rows instead.
+	Strict bool
+}
+
+type preparedStatementRow struct {
+	Line                 int
+	Transaction          model.ImportedTransaction
+	ClassificationSource string
+	// ClassificationConfidence is set for learned categories.
+	ClassificationConfidence float64
+	IgnoredReason            string
+	RejectedReason           string
+}
+
+func (s *Service) prepareStatementImport(
+	ctx context.Context,
+	userID int,
+	rows []statementRow,
+	options statementImportOptions,
+) ([]preparedStatementRow, error) {
+	if err := s.store.EnsureDefaultCategories(ctx, userID); err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("ensure default categories: %w", err))
+	}
+	rules, err := s.loadCategorizationRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	learned, err := s.loadCategoryClassifier(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prepared := make([]preparedStatementRow, 0, len(rows))
+	categories := make(map[string]string, 16)
+	reject := func(row statementRow, reason string) error {
+		if options.Strict {
+			return apperrors.Validation(fmt.Sprintf("row %d %s", row.Line, reason))
+		}
+		prepared = append(prepared, preparedStatementRow{Line: row.Line, RejectedReason: reason})
+		return nil
+	}
+	for _, row := range rows {
+		if row.Ignored != "" {
+			prepared = append(prepared, preparedStatementRow{Line: row.Line, IgnoredReason: row.Ignored})
+			continue
+		}
+		currency := strings.ToUpper(strings.TrimSpace(row.Currency))
+		if currency != supportedCurrency {
+			prepared = append(prepared, preparedStatementRow{Line: row.Line, IgnoredReason: "currency is not EUR"})
+			continue
+		}
+		if row.Invalid == "" && isZeroCSVAmount(row.Amount) {
+			prepared = append(prepared, preparedStatementRow{Line: row.Line, IgnoredReason: "amount is zero"})
+			continue
+		}
+		if row.Invalid != "" {
+			if err := reject(row, "has an invalid "+row.Invalid); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		amount, err := normalizeAmount(row.Amount)
+		if err != nil {
+			if err := reject(row, "has an invalid amount"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if row.Date.IsZero() {
+			if err := reject(row, "has an invalid date"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		description, err := normalizeLimitedText(row.Description, "description", maximumDescriptionRunes, false)
+		if err != nil {
+			if err := reject(row, "has an invalid description"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		outcome, requestedCategory, classificationSource, confidence := classifyStatementRow(
+			rules, learned, row.Type, description, amount, strings.ToLower(strings.TrimSpace(row.Category)),
+		)
+		cacheKey := row.Type + "\x00" + requestedCategory
+		category, ok := categories[cacheKey]
+		if !ok {
+			category, err = s.store.FindActiveCategoryName(ctx, userID, row.Type, requestedCategory)
+			if errors.Is(err, repository.ErrNotFound) {
+				if err := reject(row, "uses an unavailable category"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err != nil {
+				return nil, apperrors.Internal(fmt.Errorf("find import category: %w", err))
+			}
+			categories[cacheKey] = category
+		}
+		prepared = append(prepared, preparedStatementRow{
+			Line: row.Line,
+			Transaction: model.ImportedTransaction{
+				Request: model.TransactionRequest{
+					Type: row.Type, Category: category, Description: description,
+					Amount: amount, Currency: currency, OccurredAt: row.Date.Format("2006-01-02"),
+					ExcludedFromBudget: outcome.ExcludedFromBudget, Tags: outcome.Tags,
+				},
+				Source:      options.Source,
+				Fingerprint: row.Fingerprint,
+				Merchant:    merchantMatch(description),
+			},
+			ClassificationSource:     classificationSource,
+			ClassificationConfidence: confidence,
+		})
+	}
+	return prepared, nil
+}
+
+// classifyStatementRow runs the user's rules first, then categories learned
+// from the user's corrections, then the built-in keyword lists. An explicit
+// category from the file still wins over all of them, but the rule outcome is
+// returned either way, since its tags and budget exclusion still apply.
+func classifyStatementRow(
+	rules categorizationRules,
+	learned *categoryClassifier,
+	transactionType, description, amount, category string,
+) (categorizationOutcome, string, string, float64) {
+	outcome := rules.apply(categorizationInput{Type: transactionType, Description: description, Amount: amount})
+	switch {
+	case category != "":
+		return outcome, category, "annotation", 0
+	case outcome.Category != "":
+		return outcome, strings.ToLower(outcome.Category), categorizationRuleSource, 0
+	}
+	if learnedCategory, score, ok := learned.classify(transactionType, description, amount); ok {
+		return outcome, strings.ToLower(learnedCategory), learnedCategorySource, score
+	}
+	classification := classifyOpenBankingTransaction(transactionType, "", description)
+	return outcome, classification.Category, classification.Source, 0
+}
+
+// storeStatementImport writes the accepted rows through the idempotent
+// fingerprint path and summarizes the outcome of the whole file.
+func (s *Service) storeStatementImport(ctx context.Context, userID int, rows []preparedStatementRow) (model.StatementImportResult, error) {
+	result := model.StatementImportResult{Rejected: []model.ImportRejection{}}
+	imports := make([]model.ImportedTransaction, 0, len(rows))
+	for _, row := range rows {
+		switch {
+		case row.IgnoredReason != "":
+			result.Ignored++
+		case row.RejectedReason != "":
+			result.Rejected = append(result.Rejected, model.ImportRejection{Row: row.Line, Reason: row.RejectedReason})
+		default:
+			imports = append(imports, row.Transaction)
+		}
+	}
+	if len(imports) == 0 {
+		return result, nil
+	}
+	imported, skipped, err := s.store.ImportTransactions(ctx, userID, imports)
+	if err != nil {
+		return model.StatementImportResult{}, apperrors.Internal(fmt.Errorf("import transactions: %w", err))
+	}
+	result.Imported, result.Skipped = imported, skipped
+	if imported > 0 {
+		s.invalidateReports(ctx, userID)
+		if result.Reconciled, err = s.reconcileScheduleOccurrences(ctx, userID); err != nil {
+			return model.StatementImportResult{}, err
+		}
+	}
+	return result, nil
+}
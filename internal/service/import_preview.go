@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const importPreviewTTL = time.Hour
+
+// PreviewImport runs an importer without writing transactions. Every parsed
+// row is returned with its proposed classification and whether it would be
+// imported, skipped as a duplicate, ignored, or rejected. The preview is kept
+// for an hour so it can be committed with per-row overrides.
+func (s *Service) PreviewImport(
+	ctx context.Context,
+	userID int,
+	request model.ImportPreviewRequest,
+	contents []byte,
+) (model.ImportPreview, error) {
+	rows, err := s.parseStatementImport(ctx, userID, request, contents)
+	if err != nil {
+		return model.ImportPreview{}, err
+	}
+	source := request.Format
+	prepared, err := s.prepareStatementImport(ctx, userID, rows, statementImportOptions{Source: source})
+	if err != nil {
+		return model.ImportPreview{}, err
+	}
+	fingerprints := make([]string, 0, len(prepared))
+	for _, row := range prepared {
+		if row.IgnoredReason == "" && row.RejectedReason == "" {
+			fingerprints = append(fingerprints, row.Transaction.Fingerprint)
+		}
+	}
+	existing := map[string]bool{}
+	if len(fingerprints) > 0 {
+		found, err := s.store.ExistingImportFingerprints(ctx, userID, source, fingerprints)
+		if err != nil {
+			return model.ImportPreview{}, apperrors.Internal(fmt.Errorf("find imported fingerprints: %w", err))
+		}
+		for _, fingerprint := range found {
+			existing[fingerprint] = true
+		}
+	}
+
+	records := make([]repository.ImportPreviewRowRecord, 0, len(prepared))
+	for index, row := range prepared {
+		record := repository.ImportPreviewRowRecord{ImportPreviewRow: model.ImportPreviewRow{Row: row.Line}}
+		switch {
+		case row.IgnoredReason != "":
+			record.Status, record.Reason = "ignored", row.IgnoredReason
+			record.Description = truncateRunes(rows[index].Description, maximumDescriptionRunes)
+		case row.RejectedReason != "":
+			record.Status, record.Reason = "rejected", row.RejectedReason
+			record.Description = truncateRunes(rows[index].Description, maximumDescriptionRunes)
+		default:
+			transaction := row.Transaction.Request
+			record.Status = "new"
+			if existing[row.Transaction.Fingerprint] {
+				record.Status = "duplicate"
+			}
+			record.Type, record.Category, record.ClassificationSource = transaction.Type, transaction.Category, row.ClassificationSource
+			record.ClassificationConfidence = math.Round(row.ClassificationConfidence*1000) / 1000
+			record.Description, record.Amount, record.Currency = transaction.Description, transaction.Amount, transaction.Currency
+			record.OccurredAt, record.Fingerprint = transaction.OccurredAt, row.Transaction.Fingerprint
+			record.Tags, record.ExcludedFromBudget = transaction.Tags, transaction.ExcludedFromBudget
+		}
+		records = append(records, record)
+	}
+	stored, err := s.store.CreateImportPreview(ctx, userID, source, records, s.now().Add(importPreviewTTL))
+	if err != nil {
+		return model.ImportPreview{}, apperrors.Internal(fmt.Errorf("create import preview: %w", err))
+	}
+	return importPreviewFromRecord(stored), nil
+}
+
+func (s *Service) GetImportPreview(ctx context.Context, userID, previewID int) (model.ImportPreview, error) {
+	record, err := s.importPreview(ctx, userID, previewID)
+	if err != nil {
+		return model.ImportPreview{}, err
+	}
+	return importPreviewFromRecord(record), nil
+}
+
+// CommitImportPreview imports the new and duplicate rows of a preview after
+// applying the overrides. Duplicates go through the same idempotent path as a
+// direct import, so they are counted as skipped.
+func (s *Service) CommitImportPreview(
+	ctx context.Context,
+	userID, previewID int,
+	request model.ImportCommitRequest,
+) (model.StatementImportResult, error) {
+	record, err := s.importPreview(ctx, userID, previewID)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	overrides, err := validateImportOverrides(record, request.Overrides)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+
+	// A row whose type is changed is classified again the way the preview
+	// classified it.
+	rules, err := s.loadCategorizationRules(ctx, userID)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	learned, err := s.loadCategoryClassifier(ctx, userID)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+
+	prepared := make([]preparedStatementRow, 0, len(record.Rows))
+	categories := make(map[string]string, 16)
+	for _, row := range record.Rows {
+		switch row.Status {
+		case "ignored":
+			prepared = append(prepared, preparedStatementRow{Line: row.Row, IgnoredReason: row.Reason})
+			continue
+		case "rejected":
+			prepared = append(prepared, preparedStatementRow{Line: row.Row, RejectedReason: row.Reason})
+			continue
+		}
+		transactionType, category := row.Type, row.Category
+		tags, excludedFromBudget := row.Tags, row.ExcludedFromBudget
+		chosen := false
+		if override, ok := overrides[row.Row]; ok {
+			if override.Skip {
+				prepared = append(prepared, preparedStatementRow{Line: row.Row, IgnoredReason: "skipped on commit"})
+				continue
+			}
+			if override.Type != "" && override.Type != transactionType {
+				transactionType = override.Type
+				var outcome categorizationOutcome
+				outcome, category, _, _ = classifyStatementRow(rules, learned, transactionType, row.Description, row.Amount, "")
+				tags, excludedFromBudget = outcome.Tags, outcome.ExcludedFromBudget
+			}
+			if override.Category != "" {
+				category = override.Category
+			}
+			chosen = override.Type != "" || override.Category != ""
+		}
+		cacheKey := transactionType + "\x00" + strings.ToLower(category)
+		resolved, ok := categories[cacheKey]
+		if !ok {
+			resolved, err = s.store.FindActiveCategoryName(ctx, userID, transactionType, category)
+			if errors.Is(err, repository.ErrNotFound) {
+				if _, overridden := overrides[row.Row]; overridden {
+					return model.StatementImportResult{}, apperrors.Validation(
+						fmt.Sprintf("row %d category must be active and match the transaction type", row.Row),
+					)
+				}
+				prepared = append(prepared, preparedStatementRow{Line: row.Row, RejectedReason: "uses an unavailable category"})
+				continue
+			}
+			if err != nil {
+				return model.StatementImportResult{}, apperrors.Internal(fmt.Errorf("find import category: %w", err))
+			}
+			categories[cacheKey] = resolved
+		}
+		prepared = append(prepared, preparedStatementRow{
+			Line: row.Row,
+			Transaction: model.ImportedTransaction{
+				Request: model.TransactionRequest{
+					Type: transactionType, Category: resolved, Description: row.Description,
+					Amount: row.Amount, Currency: row.Currency, OccurredAt: row.OccurredAt,
+					ExcludedFromBudget: excludedFromBudget, Tags: tags,
+				},
+				Source:         record.Source,
+				Fingerprint:    row.Fingerprint,
+				Merchant:       merchantMatch(row.Description),
+				CategoryChosen: chosen,
+			},
+		})
+	}
+	result, err := s.storeStatementImport(ctx, userID, prepared)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	if err := s.store.DeleteImportPreview(ctx, userID, previewID); err != nil {
+		return model.StatementImportResult{}, apperrors.Internal(fmt.Errorf("delete import preview: %w", err))
+	}
+	return result, nil
+}
+
+func (s *Service) importPreview(ctx context.Context, userID, previewID int) (repository.ImportPreviewRecord, error) {
+	if err := validateID(previewID); err != nil {
+		return repository.ImportPreviewRecord{}, err
+	}
+	record, err := s.store.GetImportPreview(ctx, userID, previewID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.ImportPreviewRecord{}, apperrors.NotFound("import preview not found or expired")
+	}
+	if err != nil {
+		return repository.ImportPreviewRecord{}, apperrors.Internal(fmt.Errorf("get import preview: %w", err))
+	}
+	return record, nil
+}
+
+// parseStatementImport runs the parser of one import format, leaving
+// validation and classification to the shared pipeline.
+func (s *Service) parseStatementImport(
+	ctx context.Context,
+	userID int,
+	request model.ImportPreviewRequest,
+	contents []byte,
+) ([]statementRow, error) {
+	switch request.Format {
+	case "revolut":
+		return parseRevolutCSV(contents)
+	case "csv":
+		return s.parseCSVImport(ctx, userID, request.ProfileID, contents)
+	case "ofx":
+		return s.parseOFXImport(contents)
+	case "qif":
+		return s.parseQIFImport(contents, request.DateFormat)
+	case "camt":
+		return s.parseCAMTImport(contents)
+	case "mt940":
+		return s.parseMT940Import(contents)
+	default:
+		return nil, apperrors.Validation("import format is not supported")
+	}
+}
+
+func validateImportOverrides(record repository.ImportPreviewRecord, overrides []model.ImportRowOverride) (map[int]model.ImportRowOverride, error) {
+	statuses := make(map[int]string, len(record.Rows))
+	for _, row := range record.Rows {
+		statuses[row.Row] = row.Status
+	}
+	normalized := make(map[int]model.ImportRowOverride, len(overrides))
+	for _, override := range overrides {
+		status, ok := statuses[override.Row]
+		if !ok {
+			return nil, apperrors.Validation(fmt.Sprintf("row %d is not in the preview", override.Row))
+		}
+		if status != "new" {
+			return nil, apperrors.Validation(fmt.Sprintf("row %d is %s and cannot be overridden", override.Row, status))
+		}
+		if _, duplicate := normalized[override.Row]; duplicate {
+			return nil, apperrors.Validation(fmt.Sprintf("row %d is overridden more than once", override.Row))
+		}
+		if override.Type != "" {
+			transactionType, err := normalizeTransactionType(override.Type)
+			if err != nil {
+				return nil, err
+			}
+			override.Type = transactionType
+		}
+		if override.Category != "" {
+			category, err := normalizeLimitedText(override.Category, "category", maximumCategoryRunes, false)
+			if err != nil {
+				return nil, err
+			}
+			override.Category = strings.ToLower(category)
+		}
+		normalized[override.Row] = override
+	}
+	return normalized, nil
+}
+
+func importPreviewFromRecord(record repository.ImportPreviewRecord) model.ImportPreview {
+	preview := model.ImportPreview{
+		ID: record.ID, Source: record.Source, Rows: make([]model.ImportPreviewRow, 0, len(record.Rows)),
+		CreatedAt: record.CreatedAt, ExpiresAt: record.ExpiresAt,
+	}
+	for _, row := range record.Rows {
+		switch row.Status {
+		case "new":
+			preview.Counts.New++
+		case "duplicate":
+			preview.Counts.Duplicate++
+		case "ignored":
+			preview.Counts.Ignored++
+		case "rejected":
+			preview.Counts.Rejected++
+		}
+		preview.Rows = append(preview.Rows, row.ImportPreviewRow)
+	}
+	return preview
+}
+
+// RunImportPreviewMaintenance drops every user's expired previews, including
+// ones abandoned by users who never import again.
+func (s *Service) RunImportPreviewMaintenance(ctx context.Context) (model.ImportPreviewMaintenanceResult, error) {
+	expired, err := s.store.DeleteExpiredImportPreviews(ctx, s.now().UTC())
+	if err != nil {
+		return model.ImportPreviewMaintenanceResult{}, apperrors.Internal(fmt.Errorf("delete expired import previews: %w", err))
+	}
+	return model.ImportPreviewMaintenanceResult{Expired: expired}, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestPreviewImportReportsClassificationAndDuplicates(t *testing.T) {
+	var stored []repository.ImportPreviewRowRecord
+	store := &fakeStore{
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		listActiveCategorizationRules: func(context.Context, int) ([]model.CategorizationRule, error) {
+			return []model.CategorizationRule{{
+				ID: 1, Name: "Cinema", Enabled: true,
+				Conditions: model.CategorizationRuleConditions{DescriptionContains: "cinema"},
+				Actions:    model.CategorizationRuleActions{Tags: []string{"fun"}, ExcludeFromBudget: true},
+			}}, nil
+		},
+		existingImportFingerprints: func(_ context.Context, userID int, source string, fingerprints []string) ([]string, error) {
+			if userID != 3 || source != "revolut" || len(fingerprints) != 2 {
+				t.Fatalf("fingerprint lookup = %d/%s/%v", userID, source, fingerprints)
+			}
+			return fingerprints[1:], nil
+		},
+		createImportPreview: func(_ context.Context, _ int, source string, rows []repository.ImportPreviewRowRecord, expiresAt time.Time) (repository.ImportPreviewRecord, error) {
+			if source != "revolut" || expiresAt.IsZero() {
+				t.Fatalf("stored preview = %s at %s", source, expiresAt)
+			}
+			stored = rows
+			return repository.ImportPreviewRecord{ID: 9, Source: source, Rows: rows}, nil
+		},
+		importTransactions: func(context.Context, int, []model.ImportedTransaction) (int, int, error) {
+			t.Fatal("preview imported transactions")
+			return 0, 0, nil
+		},
+	}
+	contents := []byte("Type,Started Date,Completed Date,Description,Amount,Currency,State,Money Manager Category\n" +
+		"CARD_PAYMENT,2026-07-11 10:00:00,2026-07-11 10:00:00,LIDL,-12.50,EUR,COMPLETED,\n" +
+		"CARD_PAYMENT,2026-07-12 10:00:00,2026-07-12 10:00:00,Cinema,-9.00,EUR,COMPLETED,entertainment\n" +
+		"CARD_PAYMENT,2026-07-13 10:00:00,,Pending,-1.00,EUR,PENDING,\n" +
+		"CARD_PAYMENT,2026-07-14 10:00:00,2026-07-14 10:00:00,Broken,abc,EUR,COMPLETED,\n")
+	preview, err := testService(store).PreviewImport(context.Background(), 3, model.ImportPreviewRequest{Format: "revolut"}, contents)
+	if err != nil {
+		t.Fatalf("PreviewImport() error = %v", err)
+	}
+	if preview.ID != 9 || preview.Counts != (model.ImportPreviewCounts{New: 1, Duplicate: 1, Ignored: 1, Rejected: 1}) || len(preview.Rows) != 4 {
+		t.Fatalf("PreviewImport() = %#v", preview)
+	}
+	lidl, cinema, pending, broken := preview.Rows[0], preview.Rows[1], preview.Rows[2], preview.Rows[3]
+	if lidl.Status != "new" || lidl.Category != "groceries" || lidl.ClassificationSource != "expense_keyword" || lidl.Amount != "12.50" {
+		t.Fatalf("keyword row = %#v", lidl)
+	}
+	if cinema.Status != "duplicate" || cinema.Category != "entertainment" || cinema.ClassificationSource != "annotation" ||
+		len(cinema.Tags) != 1 || cinema.Tags[0] != "fun" || !cinema.ExcludedFromBudget {
+		t.Fatalf("annotated row = %#v", cinema)
+	}
+	if pending.Status != "ignored" || pending.Reason != "transaction is not completed" || pending.Description != "Pending" {
+		t.Fatalf("ignored row = %#v", pending)
+	}
+	if broken.Status != "rejected" || broken.Reason != "has an invalid amount" || broken.Row != 5 {
+		t.Fatalf("rejected row = %#v", broken)
+	}
+	if stored[0].Fingerprint == "" || stored[2].Fingerprint != "" {
+		t.Fatalf("stored fingerprints = %#v", stored)
+	}
+}
+
+func TestCommitImportPreviewAppliesOverrides(t *testing.T) {
+	record := repository.ImportPreviewRecord{ID: 9, Source: "ofx", Rows: []repository.ImportPreviewRowRecord{
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 1, Status: "new", Type: "expense", Category: "other", Description: "Transfer from Ivan", Amount: "50.00", Currency: "EUR", OccurredAt: "2026-07-11"}, Fingerprint: "f1"},
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 2, Status: "new", Type: "expense", Category: "groceries", Description: "LIDL", Amount: "12.50", Currency: "EUR", OccurredAt: "2026-07-12"}, Fingerprint: "f2"},
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 3, Status: "duplicate", Type: "expense", Category: "dining", Description: "LIDL 1234 SOFIA BG", Amount: "3.00", Currency: "EUR", OccurredAt: "2026-07-12", Tags: []string{"work"}, ExcludedFromBudget: true}, Fingerprint: "f3"},
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 4, Status: "ignored", Reason: "currency is not EUR"}},
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 5, Status: "rejected", Reason: "has an invalid date"}},
+	}}
+	store := &fakeStore{
+		getImportPreview: func(_ context.Context, userID, previewID int) (repository.ImportPreviewRecord, error) {
+			if userID != 3 || previewID != 9 {
+				return repository.ImportPreviewRecord{}, repository.ErrNotFound
+			}
+			return record, nil
+		},
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			if len(transactions) != 2 {
+				t.Fatalf("committed rows = %#v", transactions)
+			}
+			transfer := transactions[0]
+			if transfer.Source != "ofx" || transfer.Fingerprint != "f1" || transfer.Request.Type != "income" || transfer.Request.Category != "gifts" {
+				t.Fatalf("overridden row = %#v", transfer)
+			}
+			if transactions[1].Fingerprint != "f3" || transactions[1].Request.Category != "dining" ||
+				transactions[1].Merchant != (model.MerchantMatch{Key: "lidl", Name: "Lidl"}) ||
+				!transactions[1].Request.ExcludedFromBudget || len(transactions[1].Request.Tags) != 1 || transactions[1].Request.Tags[0] != "work" {
+				t.Fatalf("duplicate row = %#v", transactions[1])
+			}
+			return 1, 1, nil
+		},
+	}
+	service := testService(store)
+	overrides := []model.ImportRowOverride{{Row: 1, Type: "Income", Category: "Gifts"}, {Row: 2, Skip: true}}
+	result, err := service.CommitImportPreview(context.Background(), 3, 9, model.ImportCommitRequest{Overrides: overrides})
+	if err != nil {
+		t.Fatalf("CommitImportPreview() error = %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 1 || result.Ignored != 2 || len(result.Rejected) != 1 {
+		t.Fatalf("CommitImportPreview() = %#v", result)
+	}
+
+	invalid := [][]model.ImportRowOverride{
+		{{Row: 8, Skip: true}},
+		{{Row: 3, Category: "groceries"}},
+		{{Row: 1, Skip: true}, {Row: 1, Skip: true}},
+		{{Row: 1, Type: "transfer"}},
+	}
+	for index, overrides := range invalid {
+		_, err := service.CommitImportPreview(context.Background(), 3, 9, model.ImportCommitRequest{Overrides: overrides})
+		if apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("case %d error = %v", index, err)
+		}
+	}
+	if _, err := service.CommitImportPreview(context.Background(), 3, 10, model.ImportCommitRequest{}); apperrors.KindOf(err) != apperrors.KindNotFound {
+		t.Fatalf("unknown preview error = %v", err)
+	}
+}
+
+func TestCommitImportPreviewReclassifiesChangedType(t *testing.T) {
+	record := repository.ImportPreviewRecord{ID: 9, Source: "csv", Rows: []repository.ImportPreviewRowRecord{
+		{ImportPreviewRow: model.ImportPreviewRow{Row: 1, Status: "new", Type: "expense", Category: "other", Description: "Transfer from Ivan", Amount: "50.00", Currency: "EUR", OccurredAt: "2026-07-11", Tags: []string{"card"}}, Fingerprint: "f1"},
+	}}
+	store := &fakeStore{
+		getImportPreview: func(context.Context, int, int) (repository.ImportPreviewRecord, error) { return record, nil },
+		listActiveCategorizationRules: func(context.Context, int) ([]model.CategorizationRule, error) {
+			return []model.CategorizationRule{{
+				ID: 1, Name: "Family", Enabled: true,
+				Conditions: model.CategorizationRuleConditions{DescriptionContains: "ivan", Type: "income"},
+				Actions:    model.CategorizationRuleActions{Category: "gifts", Tags: []string{"family"}},
+			}}, nil
+		},
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			request := transactions[0].Request
+			// The income rule now matches, and the expense row's tags are gone.
+			if request.Type != "income" || request.Category != "gifts" || len(request.Tags) != 1 || request.Tags[0] != "family" {
+				t.Fatalf("reclassified row = %#v", transactions[0])
+			}
+			return 1, 0, nil
+		},
+	}
+	overrides := []model.ImportRowOverride{{Row: 1, Type: "income"}}
+	if _, err := testService(store).CommitImportPreview(context.Background(), 3, 9, model.ImportCommitRequest{Overrides: overrides}); err != nil {
+		t.Fatalf("CommitImportPreview() error = %v", err)
+	}
+}
+
+func TestRunImportPreviewMaintenanceDropsExpiredPreviews(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 9, 0, 0, 0, time.UTC)
+	store := &fakeStore{deleteExpiredImportPreviews: func(_ context.Context, at time.Time) (int, error) {
+		if !at.Equal(now) {
+			t.Fatalf("expired previews before %s", at)
+		}
+		return 2, nil
+	}}
+	service := testService(store)
+	service.now = func() time.Time { return now }
+
+	result, err := service.RunImportPreviewMaintenance(context.Background())
+	if err != nil || result.Expired != 2 {
+		t.Fatalf("RunImportPreviewMaintenance() = %#v, %v", result, err)
+	}
+}
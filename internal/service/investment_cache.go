@@ -144,4 +144,6 @@ func (s *Service) invalidateInvestmentResponses(ctx context.Context, userID int)
 	if err := s.investmentCache.InvalidateUser(cacheCtx, userID); err != nil {
 		slog.Warn("investment response cache invalidation failed", "user_id", userID, "error", err)
 	}
+	// The annual report includes the year's trades.
+	s.invalidateReports(ctx, userID)
 }
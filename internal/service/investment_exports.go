@@ -3,35 +3,76 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/export"
 	"money-manager-server/internal/model"
 	"money-manager-server/internal/repository"
 )
 
-func (s *Service) ExportInvestmentTrades(ctx context.Context, userID int, fromString, throughString string) ([]model.InvestmentTrade, error) {
-	from, err := parseDate(fromString, "from")
+// ExportInvestmentTrades writes the trades in a date range in the requested
+// format. Trades are bounded like before, so the whole range is loaded and
+// checked before start is called.
+func (s *Service) ExportInvestmentTrades(
+	ctx context.Context,
+	userID int,
+	request model.InvestmentExportRequest,
+	start func(model.ExportFile) io.Writer,
+) error {
+	request.Format = strings.ToLower(strings.TrimSpace(request.Format))
+	if request.Format == "" {
+		request.Format = "csv"
+	}
+	format, ok := export.LookupTrades(request.Format)
+	if !ok {
+		return apperrors.Validation("format must be one of " + strings.Join(export.TradeNames(), ", "))
+	}
+	from, err := parseDate(request.From, "from")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	through, err := parseDate(throughString, "through")
+	through, err := parseDate(request.Through, "through")
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if through.Before(from) {
-		return nil, apperrors.Validation("through must be on or after from")
+		return apperrors.Validation("through must be on or after from")
 	}
 	if int(through.Sub(from).Hours()/24)+1 > maximumExportDays {
-		return nil, apperrors.Validation("export date range must be 366 days or less")
+		return apperrors.Validation("export date range must be 366 days or less")
 	}
 	items, err := s.store.ListInvestmentTrades(ctx, userID, repository.InvestmentTradeFilter{
 		From: from, Through: through.AddDate(0, 0, 1), Limit: maximumExportRows + 1,
 	})
 	if err != nil {
-		return nil, apperrors.Internal(fmt.Errorf("export investment trades: %w", err))
+		return apperrors.Internal(fmt.Errorf("export investment trades: %w", err))
 	}
 	if len(items) > maximumExportRows {
-		return nil, apperrors.Validation("export contains more than 5000 trades; narrow the date range")
+		return apperrors.Validation("export contains more than 5000 trades; narrow the date range")
+	}
+
+	file := model.ExportFile{
+		Filename: fmt.Sprintf(
+			"money-manager-investments-%s-to-%s.%s", from.Format(time.DateOnly), through.Format(time.DateOnly), format.Extension,
+		),
+		ContentType: format.ContentType,
+	}
+	encoder, err := format.NewEncoder(start(file), export.Options{
+		From: from.Format(time.DateOnly), To: through.Format(time.DateOnly), Currency: supportedCurrency, GeneratedAt: s.now(),
+	})
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("start investment export: %w", err))
+	}
+	for _, trade := range items {
+		if err := encoder.Encode(trade); err != nil {
+			return apperrors.Internal(fmt.Errorf("encode investment trade: %w", err))
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return apperrors.Internal(fmt.Errorf("finish investment export: %w", err))
 	}
-	return items, nil
+	return nil
 }
@@ -192,7 +192,22 @@ func openInvestmentInstruments(trades []model.InvestmentTrade) ([]model.Investme
 	return result, nil
 }
 
-func calculateInvestmentPortfolio(trades []model.InvestmentTrade, prices []model.InvestmentPrice) (model.InvestmentPortfolio, error) {
+// investmentLedgerStep is one trade applied to its position at average cost.
+// A buy adds cost to the basis; a sale removes the average cost of the units
+// sold and realizes proceeds net of fees.
+type investmentLedgerStep struct {
+	trade    model.InvestmentTrade
+	position *investmentLedgerPosition
+	cost     *big.Rat
+	proceeds *big.Rat
+}
+
+// walkInvestmentLedger replays trades in date order, calling visit after each
+// one is applied. It returns the final positions and their sorted keys.
+func walkInvestmentLedger(
+	trades []model.InvestmentTrade,
+	visit func(investmentLedgerStep),
+) (map[string]*investmentLedgerPosition, []string, error) {
 	ordered := append([]model.InvestmentTrade(nil), trades...)
 	sort.SliceStable(ordered, func(i, j int) bool {
 		if ordered[i].OccurredAt == ordered[j].OccurredAt {
@@ -218,38 +233,51 @@ func calculateInvestmentPortfolio(trades []model.InvestmentTrade, prices []model
 		price, priceOK := new(big.Rat).SetString(trade.PricePerUnit)
 		fees, feesOK := new(big.Rat).SetString(trade.Fees)
 		if !quantityOK || !priceOK || !feesOK {
-			return model.InvestmentPortfolio{}, apperrors.Internal(errors.New("stored investment trade contains invalid decimals"))
+			return nil, nil, apperrors.Internal(errors.New("stored investment trade contains invalid decimals"))
 		}
 		gross := new(big.Rat).Mul(quantity, price)
 		if strings.TrimSpace(trade.Amount) != "" {
 			storedAmount, amountOK := new(big.Rat).SetString(trade.Amount)
 			if !amountOK {
-				return model.InvestmentPortfolio{}, apperrors.Internal(errors.New("stored investment trade contains an invalid amount"))
+				return nil, nil, apperrors.Internal(errors.New("stored investment trade contains an invalid amount"))
 			}
 			gross = storedAmount
 		}
+		step := investmentLedgerStep{trade: trade, position: position}
 		if trade.Side == "buy" {
+			step.cost = new(big.Rat).Add(gross, fees)
 			position.quantity.Add(position.quantity, quantity)
-			position.basis.Add(position.basis, new(big.Rat).Add(gross, fees))
-			continue
-		}
-		if position.quantity.Cmp(quantity) < 0 || position.quantity.Sign() == 0 {
-			return model.InvestmentPortfolio{}, apperrors.Internal(errors.New("investment ledger contains a sale larger than its holding"))
+			position.basis.Add(position.basis, step.cost)
+		} else {
+			if position.quantity.Cmp(quantity) < 0 || position.quantity.Sign() == 0 {
+				return nil, nil, apperrors.Internal(errors.New("investment ledger contains a sale larger than its holding"))
+			}
+			step.cost = new(big.Rat).Mul(position.basis, new(big.Rat).Quo(quantity, position.quantity))
+			step.proceeds = new(big.Rat).Sub(gross, fees)
+			position.realized.Add(position.realized, new(big.Rat).Sub(step.proceeds, step.cost))
+			position.basis.Sub(position.basis, step.cost)
+			position.quantity.Sub(position.quantity, quantity)
+			if position.quantity.Sign() == 0 {
+				position.basis.SetInt64(0)
+			}
 		}
-		costRemoved := new(big.Rat).Mul(position.basis, new(big.Rat).Quo(quantity, position.quantity))
-		proceeds := new(big.Rat).Sub(gross, fees)
-		position.realized.Add(position.realized, new(big.Rat).Sub(proceeds, costRemoved))
-		position.basis.Sub(position.basis, costRemoved)
-		position.quantity.Sub(position.quantity, quantity)
-		if position.quantity.Sign() == 0 {
-			position.basis.SetInt64(0)
+		if visit != nil {
+			visit(step)
 		}
 	}
+	sort.Strings(keys)
+	return ledgers, keys, nil
+}
+
+func calculateInvestmentPortfolio(trades []model.InvestmentTrade, prices []model.InvestmentPrice) (model.InvestmentPortfolio, error) {
+	ledgers, keys, err := walkInvestmentLedger(trades, nil)
+	if err != nil {
+		return model.InvestmentPortfolio{}, err
+	}
 	priceMap := make(map[string]model.InvestmentPrice, len(prices))
 	for _, price := range prices {
 		priceMap[price.AssetType+"\x00"+price.Symbol+"\x00"+price.Exchange] = price
 	}
-	sort.Strings(keys)
 	portfolio := model.InvestmentPortfolio{
 		Positions: make([]model.InvestmentPosition, 0, len(keys)), Currency: supportedCurrency,
 		InvestedAmount: "0.00", CurrentValue: "0.00", UnrealizedProfit: "0.00", RealizedProfit: "0.00",
@@ -20,6 +20,14 @@ func investmentRecurrenceRule(schedule model.InvestmentSchedule) (recurrence.Rul
 		}
 		end = &value
 	}
+	if schedule.RRule != "" {
+		rule, err := recurrence.ParseRRule(schedule.RRule)
+		if err != nil {
+			return recurrence.Rule{}, err
+		}
+		rule.StartDate, rule.EndDate = start, end
+		return rule, nil
+	}
 	rule := recurrence.Rule{
 		Frequency: schedule.Frequency, Interval: schedule.FrequencyInterval,
 		StartDate: start, EndDate: end,
@@ -8,7 +8,6 @@ import (
 
 	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/model"
-	"money-manager-server/internal/recurrence"
 	"money-manager-server/internal/repository"
 )
 
@@ -25,7 +24,7 @@ func (s *Service) CreateInvestmentSchedule(
 	if err != nil {
 		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("create investment schedule: %w", err))
 	}
-	return s.decorateInvestmentSchedule(item)
+	return s.materializeInvestmentScheduleNow(ctx, userID, item)
 }
 
 func (s *Service) ListInvestmentSchedules(ctx context.Context, userID int, status string) ([]model.InvestmentSchedule, error) {
@@ -82,14 +81,38 @@ func (s *Service) UpdateInvestmentSchedule(
 	if err != nil {
 		return model.InvestmentSchedule{}, err
 	}
-	item, err := s.store.UpdateInvestmentSchedule(ctx, userID, scheduleID, normalized)
+	today, err := scheduleLocalDate(s.now(), normalized.Timezone)
+	if err != nil {
+		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("load investment schedule timezone: %w", err))
+	}
+	item, err := s.store.UpdateInvestmentSchedule(ctx, userID, scheduleID, normalized, today)
 	if errors.Is(err, repository.ErrNotFound) {
 		return model.InvestmentSchedule{}, apperrors.NotFound("investment schedule not found")
 	}
 	if err != nil {
 		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("update investment schedule: %w", err))
 	}
-	return s.decorateInvestmentSchedule(item)
+	return s.materializeInvestmentScheduleNow(ctx, userID, item)
+}
+
+// materializeInvestmentScheduleNow stores the planned buys of an active
+// schedule right away instead of waiting for the next maintenance run.
+func (s *Service) materializeInvestmentScheduleNow(
+	ctx context.Context,
+	userID int,
+	item model.InvestmentSchedule,
+) (model.InvestmentSchedule, error) {
+	if item.Status != "active" {
+		return s.decorateInvestmentSchedule(item)
+	}
+	today, err := scheduleLocalDate(s.now(), item.Timezone)
+	if err != nil {
+		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("load investment schedule timezone: %w", err))
+	}
+	if _, err := s.materializeInvestmentSchedule(ctx, item, today); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return model.InvestmentSchedule{}, apperrors.Internal(err)
+	}
+	return s.GetInvestmentSchedule(ctx, userID, item.ID)
 }
 
 func (s *Service) PauseInvestmentSchedule(ctx context.Context, userID, scheduleID int) (model.InvestmentSchedule, error) {
@@ -123,7 +146,8 @@ func (s *Service) setInvestmentScheduleStatus(
 	} else if err != nil {
 		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("set investment schedule status: %w", err))
 	}
-	return s.GetInvestmentSchedule(ctx, userID, scheduleID)
+	item.Status = status
+	return s.materializeInvestmentScheduleNow(ctx, userID, item)
 }
 
 func (s *Service) DeleteInvestmentSchedule(ctx context.Context, userID, scheduleID int) error {
@@ -191,8 +215,10 @@ func (s *Service) validateInvestmentSchedule(
 		}
 		endDate = end.Format("2006-01-02")
 	}
-	recurrence, err := normalizeScheduleRecurrence(
+	recurrence, err := normalizeScheduleRule(
 		start,
+		endDate,
+		request.RRule,
 		request.Frequency,
 		request.FrequencyInterval,
 		request.DayOfWeek,
@@ -201,12 +227,17 @@ func (s *Service) validateInvestmentSchedule(
 	if err != nil {
 		return model.InvestmentScheduleRequest{}, err
 	}
+	adjustment, calendar, err := normalizeBusinessDayAdjustment(request.BusinessDayAdjustment, request.HolidayCalendar)
+	if err != nil {
+		return model.InvestmentScheduleRequest{}, err
+	}
 	return model.InvestmentScheduleRequest{
 		AssetType: assetType, Symbol: symbol, AssetName: assetName, Exchange: exchange,
 		MarketCurrency: marketCurrency, Broker: broker,
 		Amount: amount, Currency: currency, Frequency: recurrence.frequency, FrequencyInterval: recurrence.interval,
-		StartDate: start.Format("2006-01-02"), EndDate: endDate, DayOfWeek: recurrence.dayOfWeek,
-		DayOfMonth: recurrence.dayOfMonth, Timezone: timezone,
+		StartDate: start.Format("2006-01-02"), EndDate: recurrence.endDate, DayOfWeek: recurrence.dayOfWeek,
+		DayOfMonth: recurrence.dayOfMonth, RRule: recurrence.rrule, BusinessDayAdjustment: adjustment,
+		HolidayCalendar: calendar, Timezone: timezone,
 	}, nil
 }
 
@@ -226,12 +257,12 @@ func (s *Service) decorateInvestmentSchedule(item model.InvestmentSchedule) (mod
 	if err != nil {
 		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("build investment recurrence: %w", err))
 	}
-	dates, err := recurrence.Occurrences(rule, from, from.AddDate(5, 0, 0))
+	dates, err := scheduleOccurrenceDates(rule, item.BusinessDayAdjustment, item.HolidayCalendar, from, from.AddDate(5, 0, 0))
 	if err != nil {
 		return model.InvestmentSchedule{}, apperrors.Internal(fmt.Errorf("calculate next investment occurrence: %w", err))
 	}
 	if len(dates) > 0 {
-		item.NextOccurrence = dates[0].Format("2006-01-02")
+		item.NextOccurrence = dates[0].scheduled.Format("2006-01-02")
 	}
 	return item, nil
 }
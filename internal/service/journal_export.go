@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/export"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+var (
+	journalOpeningBalances = export.AccountName("Equity", "Opening Balances")
+	journalRealizedGains   = export.AccountName("Income", "Investments", "Realized Gains")
+)
+
+// journalDirective is a dated entry merged into the transaction stream, so
+// Ledger sees balances and trades in date order.
+type journalDirective struct {
+	date  string
+	write func(*export.Journal) error
+}
+
+type journalAccount struct {
+	commodities []string
+	booking     string
+}
+
+// ExportJournal writes booked transactions, investment trades, market prices,
+// and recorded bank balances in a date range as a Beancount or Ledger journal.
+// Categories become expense and income accounts and linked bank accounts
+// become asset accounts. Trades carry their average cost from the portfolio
+// ledger, and positions held before the range are opened at that cost.
+func (s *Service) ExportJournal(
+	ctx context.Context,
+	userID int,
+	request model.TransactionExportRequest,
+	start func(model.ExportFile) io.Writer,
+) error {
+	request.Format = strings.ToLower(strings.TrimSpace(request.Format))
+	if request.Format == "" {
+		request.Format = "beancount"
+	}
+	format, ok := export.LookupJournal(request.Format)
+	if !ok {
+		return apperrors.Validation("format must be one of " + strings.Join(export.JournalNames(), ", "))
+	}
+	from, err := parseDate(request.From, "from")
+	if err != nil {
+		return err
+	}
+	to, err := parseDate(request.To, "to")
+	if err != nil {
+		return err
+	}
+	if from.After(to) {
+		return apperrors.Validation("from must be before or equal to to")
+	}
+	fromDate, toDate := from.Format(time.DateOnly), to.Format(time.DateOnly)
+
+	bankAccounts, err := s.store.ListOpenBankingAccounts(ctx, userID)
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("list journal bank accounts: %w", err))
+	}
+	uses, err := s.store.ListTransactionAccountUses(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("list journal accounts: %w", err))
+	}
+	balances, err := s.store.ListOpenBankingAccountBalances(ctx, userID, from, to)
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("list journal balances: %w", err))
+	}
+	trades, err := s.store.ListInvestmentTrades(ctx, userID, repository.InvestmentTradeFilter{Limit: maximumInvestmentTradeRows + 1})
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("list journal trades: %w", err))
+	}
+	if len(trades) > maximumInvestmentTradeRows {
+		return apperrors.Validation("journal exports support at most 10000 investment trades")
+	}
+
+	banks := journalBankAccounts(bankAccounts)
+	accounts := make(map[string]journalAccount)
+	for _, use := range uses {
+		accounts[journalAssetAccount(use.Origin, banks)] = journalAccount{}
+		accounts[journalCategoryAccount(use.Type, use.Category)] = journalAccount{}
+	}
+	directives, err := s.journalInvestments(ctx, trades, fromDate, toDate, accounts)
+	if err != nil {
+		return err
+	}
+	padded := make(map[string]bool)
+	for _, balance := range balances {
+		account, ok := banks[balance.AccountID]
+		if !ok {
+			continue
+		}
+		opening := ""
+		if !padded[account] {
+			padded[account] = true
+			opening = journalOpeningBalances
+			accounts[journalOpeningBalances] = journalAccount{}
+		}
+		accounts[account] = journalAccount{}
+		reference, err := time.Parse(time.DateOnly, balance.ReferenceDate)
+		if err != nil {
+			return apperrors.Internal(fmt.Errorf("parse balance date: %w", err))
+		}
+		directives = append(directives, journalDirective{
+			date: reference.AddDate(0, 0, 1).Format(time.DateOnly),
+			write: func(journal *export.Journal) error {
+				return journal.Balance(
+					reference.AddDate(0, 0, 1).Format(time.DateOnly), account, balance.Amount, balance.Currency, opening, fromDate,
+				)
+			},
+		})
+	}
+	sort.SliceStable(directives, func(i, j int) bool { return directives[i].date < directives[j].date })
+
+	file := model.ExportFile{
+		Filename:    fmt.Sprintf("money-manager-%s-to-%s.%s", fromDate, toDate, format.Extension),
+		ContentType: format.ContentType,
+	}
+	journal, err := format.NewJournal(start(file), export.Options{
+		From: fromDate, To: toDate, Currency: supportedCurrency, GeneratedAt: s.now(),
+	})
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("start journal export: %w", err))
+	}
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := journal.Open(fromDate, name, accounts[name].commodities, accounts[name].booking); err != nil {
+			return apperrors.Internal(fmt.Errorf("write journal account: %w", err))
+		}
+	}
+	next := 0
+	flush := func(through string) error {
+		for ; next < len(directives) && directives[next].date <= through; next++ {
+			if err := directives[next].write(journal); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := s.store.StreamTransactionsWithOrigin(ctx, userID, from, to.AddDate(0, 0, 1), func(transaction model.Transaction, origin string) error {
+		if err := flush(transaction.OccurredAt); err != nil {
+			return err
+		}
+		return journal.Transaction(journalTransaction(transaction, journalAssetAccount(origin, banks)))
+	}); err != nil {
+		return apperrors.Internal(fmt.Errorf("stream journal transactions: %w", err))
+	}
+	if err := flush(to.AddDate(0, 0, 1).Format(time.DateOnly)); err != nil {
+		return apperrors.Internal(fmt.Errorf("write journal entries: %w", err))
+	}
+	if err := journal.Close(); err != nil {
+		return apperrors.Internal(fmt.Errorf("finish journal export: %w", err))
+	}
+	return nil
+}
+
+// journalInvestments replays every trade at average cost and returns the
+// entries that fall in the range: an opening lot for each position held on
+// from, the trades themselves, and recorded market prices.
+func (s *Service) journalInvestments(
+	ctx context.Context,
+	trades []model.InvestmentTrade,
+	fromDate, toDate string,
+	accounts map[string]journalAccount,
+) ([]journalDirective, error) {
+	type opening struct {
+		quantity, basis *big.Rat
+	}
+	openings := make(map[*investmentLedgerPosition]opening)
+	openingOrder := make([]*investmentLedgerPosition, 0)
+	used := make(map[*investmentLedgerPosition]bool)
+	usedOrder := make([]*investmentLedgerPosition, 0)
+	directives := make([]journalDirective, 0)
+	markUsed := func(position *investmentLedgerPosition) {
+		if !used[position] {
+			used[position] = true
+			usedOrder = append(usedOrder, position)
+		}
+		accounts[journalInvestmentAccount(position)] = journalAccount{commodities: []string{position.symbol}, booking: "NONE"}
+	}
+	_, _, err := walkInvestmentLedger(trades, func(step investmentLedgerStep) {
+		date := step.trade.OccurredAt
+		if date < fromDate {
+			if _, seen := openings[step.position]; !seen {
+				openingOrder = append(openingOrder, step.position)
+			}
+			openings[step.position] = opening{
+				quantity: new(big.Rat).Set(step.position.quantity), basis: new(big.Rat).Set(step.position.basis),
+			}
+			return
+		}
+		if date > toDate {
+			return
+		}
+		markUsed(step.position)
+		cash := journalInvestmentCash(step.position.broker)
+		accounts[cash] = journalAccount{}
+		entry := journalTrade(step, cash)
+		if step.proceeds != nil {
+			accounts[journalRealizedGains] = journalAccount{}
+		}
+		directives = append(directives, journalDirective{date: date, write: func(journal *export.Journal) error {
+			return journal.Transaction(entry)
+		}})
+	})
+	if err != nil {
+		return nil, err
+	}
+	openingDirectives := make([]journalDirective, 0, len(openingOrder))
+	for _, position := range openingOrder {
+		held := openings[position]
+		if held.quantity.Sign() <= 0 {
+			continue
+		}
+		markUsed(position)
+		accounts[journalOpeningBalances] = journalAccount{}
+		basis := formatRat(held.basis, 2)
+		entry := export.JournalTransaction{
+			Date: fromDate, Narration: "Opening balance " + strings.ToUpper(position.symbol),
+			Postings: []export.Posting{
+				{
+					Account: journalInvestmentAccount(position), Amount: formatRatTrimmed(held.quantity, 18),
+					Commodity: position.symbol, Cost: basis, CostCurrency: supportedCurrency,
+				},
+				{Account: journalOpeningBalances, Amount: negateDecimal(basis), Commodity: supportedCurrency},
+			},
+		}
+		openingDirectives = append(openingDirectives, journalDirective{date: fromDate, write: func(journal *export.Journal) error {
+			return journal.Transaction(entry)
+		}})
+	}
+	directives = append(openingDirectives, directives...)
+
+	from, _ := time.Parse(time.DateOnly, fromDate)
+	priced := make(map[string]bool)
+	for _, position := range usedOrder {
+		key := position.assetType + "\x00" + position.symbol + "\x00" + position.exchange
+		if priced[key] {
+			continue
+		}
+		priced[key] = true
+		history, err := s.store.ListInvestmentMarketHistory(
+			ctx, position.assetType, position.symbol, position.exchange, supportedCurrency, from,
+		)
+		if err != nil {
+			return nil, apperrors.Internal(fmt.Errorf("list journal prices: %w", err))
+		}
+		for _, price := range history {
+			date := price.AsOf.Format(time.DateOnly)
+			if date > toDate {
+				break
+			}
+			directives = append(directives, journalDirective{date: date, write: func(journal *export.Journal) error {
+				return journal.Price(date, price.Symbol, price.Price, price.Currency)
+			}})
+		}
+	}
+	return directives, nil
+}
+
+// journalTrade books a buy as a lot at its full cost, fees included, and a
+// sale as the removal of its average cost with the difference to the net
+// proceeds realized as a gain.
+func journalTrade(step investmentLedgerStep, cash string) export.JournalTransaction {
+	trade, position := step.trade, step.position
+	quantity := formatRatTrimmed(ledgerDecimal(trade.Quantity), 18)
+	symbol := strings.ToUpper(position.symbol)
+	entry := export.JournalTransaction{
+		Date: trade.OccurredAt, Payee: journalBrokerNames[position.broker],
+		Narration: "Buy " + quantity + " " + symbol,
+	}
+	cost := formatRat(step.cost, 2)
+	if step.proceeds == nil {
+		entry.Postings = []export.Posting{
+			{
+				Account: journalInvestmentAccount(position), Amount: quantity, Commodity: position.symbol,
+				Cost: cost, CostCurrency: trade.Currency,
+			},
+			{Account: cash, Amount: negateDecimal(cost), Commodity: trade.Currency},
+		}
+	} else {
+		entry.Narration = "Sell " + quantity + " " + symbol
+		proceeds := formatRat(step.proceeds, 2)
+		gross := new(big.Rat).Add(step.proceeds, ledgerDecimal(trade.Fees))
+		gain := new(big.Rat).Sub(ledgerDecimal(proceeds), ledgerDecimal(cost))
+		entry.Postings = []export.Posting{
+			{
+				Account: journalInvestmentAccount(position), Amount: "-" + quantity, Commodity: position.symbol,
+				Cost: cost, CostCurrency: trade.Currency, Price: formatRat(gross, 2),
+			},
+			{Account: cash, Amount: proceeds, Commodity: trade.Currency},
+		}
+		if gain.Sign() != 0 {
+			entry.Postings = append(entry.Postings, export.Posting{
+				Account: journalRealizedGains, Amount: formatRat(new(big.Rat).Neg(gain), 2), Commodity: trade.Currency,
+			})
+		}
+	}
+	if notes := strings.TrimSpace(trade.Notes); notes != "" {
+		entry.Narration += ": " + notes
+	}
+	return entry
+}
+
+func journalTransaction(transaction model.Transaction, asset string) export.JournalTransaction {
+	category := journalCategoryAccount(transaction.Type, transaction.Category)
+	narration := transaction.Description
+	if strings.TrimSpace(narration) == "" {
+		narration = transaction.Category
+	}
+	entry := export.JournalTransaction{Date: transaction.OccurredAt, Narration: narration, Tags: transaction.Tags}
+	amount, negated := transaction.Amount, negateDecimal(transaction.Amount)
+	if transaction.Type == "income" {
+		entry.Postings = []export.Posting{
+			{Account: asset, Amount: amount, Commodity: transaction.Currency},
+			{Account: category, Amount: negated, Commodity: transaction.Currency},
+		}
+	} else {
+		entry.Postings = []export.Posting{
+			{Account: category, Amount: amount, Commodity: transaction.Currency},
+			{Account: asset, Amount: negated, Commodity: transaction.Currency},
+		}
+	}
+	return entry
+}
+
+// journalBankAccounts names each linked account after its institution and
+// label, adding the account ID when two would otherwise collide.
+func journalBankAccounts(accounts []model.OpenBankingAccount) map[int]string {
+	names := make(map[int]string, len(accounts))
+	taken := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		label := account.Name
+		if strings.TrimSpace(label) == "" {
+			label = account.DisplayIdentifier
+		}
+		if strings.TrimSpace(label) == "" {
+			label = "Account " + strconv.Itoa(account.ID)
+		}
+		name := export.AccountName("Assets", "Bank", account.InstitutionName, label)
+		if taken[name] {
+			name = export.AccountName("Assets", "Bank", account.InstitutionName, label+" "+strconv.Itoa(account.ID))
+		}
+		taken[name] = true
+		names[account.ID] = name
+	}
+	return names
+}
+
+// journalAssetAccount maps a transaction origin to the account its money
+// moved through. Manual and scheduled rows share a cash account; imported
+// files get one account per format.
+func journalAssetAccount(origin string, banks map[int]string) string {
+	switch {
+	case strings.HasPrefix(origin, "open_banking:"):
+		id, err := strconv.Atoi(strings.TrimPrefix(origin, "open_banking:"))
+		if name, ok := banks[id]; err == nil && ok {
+			return name
+		}
+		return export.AccountName("Assets", "Bank", "Unlinked")
+	case strings.HasPrefix(origin, "import:"):
+		return export.AccountName("Assets", "Imported", strings.TrimPrefix(origin, "import:"))
+	}
+	return export.AccountName("Assets", "Cash")
+}
+
+func journalCategoryAccount(transactionType, category string) string {
+	if transactionType == "income" {
+		return export.AccountName("Income", category)
+	}
+	return export.AccountName("Expenses", category)
+}
+
+var journalBrokerNames = map[string]string{"manual": "Manual", "revolut_x": "Revolut X", "trading212": "Trading 212"}
+
+func journalInvestmentAccount(position *investmentLedgerPosition) string {
+	return export.AccountName("Assets", "Investments", position.broker, position.symbol)
+}
+
+func journalInvestmentCash(broker string) string {
+	return export.AccountName("Assets", "Investments", broker, "Cash")
+}
+
+// ledgerDecimal parses a decimal the investment ledger has already validated.
+func ledgerDecimal(value string) *big.Rat {
+	number, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return new(big.Rat)
+	}
+	return number
+}
+
+func negateDecimal(value string) string {
+	if strings.HasPrefix(value, "-") {
+		return strings.TrimPrefix(value, "-")
+	}
+	return "-" + value
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func journalTestStore() *fakeStore {
+	return &fakeStore{
+		listOpenBankingAccounts: func(context.Context, int) ([]model.OpenBankingAccount, error) {
+			return []model.OpenBankingAccount{{ID: 4, InstitutionName: "Example Bank", Name: "Current account", Currency: "EUR"}}, nil
+		},
+		listTransactionAccountUses: func(context.Context, int, time.Time, time.Time) ([]repository.TransactionAccountUse, error) {
+			return []repository.TransactionAccountUse{
+				{Type: "expense", Category: "groceries", Origin: "open_banking:4"},
+				{Type: "income", Category: "salary", Origin: "manual"},
+			}, nil
+		},
+		listOpenBankingAccountBalances: func(context.Context, int, time.Time, time.Time) ([]model.OpenBankingAccountBalance, error) {
+			return []model.OpenBankingAccountBalance{
+				{AccountID: 4, ReferenceDate: "2026-07-10", Type: "CLBD", Amount: "987.50", Currency: "EUR"},
+				{AccountID: 4, ReferenceDate: "2026-07-20", Type: "CLBD", Amount: "975.00", Currency: "EUR"},
+			}, nil
+		},
+		listInvestmentTrades: func(context.Context, int, repository.InvestmentTradeFilter) ([]model.InvestmentTrade, error) {
+			return []model.InvestmentTrade{
+				{
+					ID: 1, AssetType: "stock", Symbol: "AAPL", Exchange: "NASDAQ", Broker: "trading212", Side: "buy",
+					Amount: "100.00", Quantity: "1", PricePerUnit: "100", Fees: "0.00", Currency: "EUR", OccurredAt: "2026-06-01",
+				},
+				{
+					ID: 2, AssetType: "stock", Symbol: "AAPL", Exchange: "NASDAQ", Broker: "trading212", Side: "buy",
+					Amount: "120.00", Quantity: "1", PricePerUnit: "120", Fees: "1.00", Currency: "EUR", OccurredAt: "2026-07-05",
+				},
+				{
+					ID: 3, AssetType: "stock", Symbol: "AAPL", Exchange: "NASDAQ", Broker: "trading212", Side: "sell",
+					Amount: "130.00", Quantity: "1", PricePerUnit: "130", Fees: "1.00", Currency: "EUR", OccurredAt: "2026-07-15",
+				},
+			}, nil
+		},
+		listInvestmentMarketHistory: func(context.Context, string, string, string, string, time.Time) ([]model.InvestmentMarketHistoryPrice, error) {
+			return []model.InvestmentMarketHistoryPrice{
+				{AssetType: "stock", Symbol: "AAPL", Currency: "EUR", Price: "125.00", AsOf: time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)},
+				{AssetType: "stock", Symbol: "AAPL", Currency: "EUR", Price: "140.00", AsOf: time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)},
+			}, nil
+		},
+		streamTransactionsWithOrigin: func(_ context.Context, _ int, _, _ time.Time, visit func(model.Transaction, string) error) error {
+			rows := []struct {
+				transaction model.Transaction
+				origin      string
+			}{
+				{model.Transaction{ID: 1, Type: "expense", Category: "groceries", Description: `LIDL "Sofia"`, Amount: "12.50", Currency: "EUR", OccurredAt: "2026-07-10", Tags: []string{"food shop"}}, "open_banking:4"},
+				{model.Transaction{ID: 2, Type: "expense", Category: "groceries", Description: "Market", Amount: "12.50", Currency: "EUR", OccurredAt: "2026-07-11"}, "open_banking:4"},
+				{model.Transaction{ID: 3, Type: "income", Category: "salary", Description: "Salary", Amount: "2500.00", Currency: "EUR", OccurredAt: "2026-07-25"}, "manual"},
+			}
+			for _, row := range rows {
+				if err := visit(row.transaction, row.origin); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func exportJournal(t *testing.T, format string) (model.ExportFile, string) {
+	t.Helper()
+	var output bytes.Buffer
+	var file model.ExportFile
+	err := testService(journalTestStore()).ExportJournal(context.Background(), 1, model.TransactionExportRequest{
+		From: "2026-07-01", To: "2026-07-31", Format: format,
+	}, func(started model.ExportFile) io.Writer {
+		file = started
+		return &output
+	})
+	if err != nil {
+		t.Fatalf("ExportJournal(%s) error = %v", format, err)
+	}
+	return file, output.String()
+}
+
+func TestExportJournalWritesBeancount(t *testing.T) {
+	file, journal := exportJournal(t, "")
+	if file.Filename != "money-manager-2026-07-01-to-2026-07-31.beancount" {
+		t.Fatalf("file = %#v", file)
+	}
+	for _, want := range []string{
+		`option "operating_currency" "EUR"`,
+		"2026-07-01 open Assets:Bank:Example-Bank:Current-Account\n",
+		"2026-07-01 open Assets:Investments:Trading212:AAPL AAPL \"NONE\"\n",
+		"2026-07-01 * \"Opening balance AAPL\"\n  Assets:Investments:Trading212:AAPL  1 AAPL {{100.00 EUR}}\n  Equity:Opening-Balances  -100.00 EUR\n",
+		"2026-07-05 * \"Trading 212\" \"Buy 1 AAPL\"\n  Assets:Investments:Trading212:AAPL  1 AAPL {{121.00 EUR}}\n  Assets:Investments:Trading212:Cash  -121.00 EUR\n",
+		"2026-07-10 * \"LIDL \\\"Sofia\\\"\" #food-shop\n  Expenses:Groceries  12.50 EUR\n  Assets:Bank:Example-Bank:Current-Account  -12.50 EUR\n",
+		"2026-07-10 price AAPL 125.00 EUR\n",
+		"2026-07-01 pad Assets:Bank:Example-Bank:Current-Account Equity:Opening-Balances\n2026-07-11 balance Assets:Bank:Example-Bank:Current-Account  987.50 EUR\n",
+		"2026-07-15 * \"Trading 212\" \"Sell 1 AAPL\"\n  Assets:Investments:Trading212:AAPL  -1 AAPL {{110.50 EUR}} @@ 130.00 EUR\n" +
+			"  Assets:Investments:Trading212:Cash  129.00 EUR\n  Income:Investments:Realized-Gains  -18.50 EUR\n",
+		"2026-07-21 balance Assets:Bank:Example-Bank:Current-Account  975.00 EUR\n",
+		"2026-07-25 * \"Salary\"\n  Assets:Cash  2500.00 EUR\n  Income:Salary  -2500.00 EUR\n",
+	} {
+		if !strings.Contains(journal, want) {
+			t.Fatalf("journal missing %q:\n%s", want, journal)
+		}
+	}
+	if strings.Count(journal, " pad ") != 1 || strings.Contains(journal, "140.00") {
+		t.Fatalf("journal pads or prices outside the range:\n%s", journal)
+	}
+	if strings.Index(journal, "balance Assets:Bank:Example-Bank:Current-Account  987.50") > strings.Index(journal, `"Market"`) {
+		t.Fatalf("balance assertion is not before the next day's transactions:\n%s", journal)
+	}
+}
+
+func TestExportJournalWritesLedger(t *testing.T) {
+	file, journal := exportJournal(t, "ledger")
+	if file.Filename != "money-manager-2026-07-01-to-2026-07-31.ledger" {
+		t.Fatalf("file = %#v", file)
+	}
+	for _, want := range []string{
+		"account Assets:Cash\n",
+		"2026/07/10 * LIDL \"Sofia\"\n    ; :food-shop:\n    Expenses:Groceries  12.50 EUR\n",
+		"2026/07/11 * Balance Assets:Bank:Example-Bank:Current-Account\n    Assets:Bank:Example-Bank:Current-Account  = 987.50 EUR\n    Equity:Opening-Balances\n",
+		"2026/07/21 * Balance Assets:Bank:Example-Bank:Current-Account\n    Assets:Bank:Example-Bank:Current-Account  0 EUR = 975.00 EUR\n",
+		"2026/07/15 * Trading 212 | Sell 1 AAPL\n    Assets:Investments:Trading212:AAPL  -1 AAPL @@ 110.50 EUR\n",
+		"P 2026/07/10 AAPL 125.00 EUR\n",
+	} {
+		if !strings.Contains(journal, want) {
+			t.Fatalf("journal missing %q:\n%s", want, journal)
+		}
+	}
+}
+
+func TestExportJournalValidatesBeforeStarting(t *testing.T) {
+	service := testService(journalTestStore())
+	for _, request := range []model.TransactionExportRequest{
+		{From: "2026-07-01", To: "2026-07-31", Format: "gnucash"},
+		{From: "2026-07-31", To: "2026-07-01"},
+		{From: "2026-07-01"},
+	} {
+		err := service.ExportJournal(context.Background(), 1, request, func(model.ExportFile) io.Writer {
+			t.Fatalf("export %v started before validation", request)
+			return nil
+		})
+		if apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Fatalf("ExportJournal(%v) error = %v", request, err)
+		}
+	}
+}
+
+func TestBookedOpenBankingBalancesKeepsFinalBookedBalances(t *testing.T) {
+	now := time.Date(2026, 7, 20, 9, 0, 0, 0, time.UTC)
+	balances := bookedOpenBankingBalances(4, []byte(`{"balances":[
+		{"balance_type":"ITBD","reference_date":"2026-07-19","balance_amount":{"amount":"10","currency":"eur"}},
+		{"balance_type":"CLBD","reference_date":"2026-07-19","balance_amount":{"amount":"12.5","currency":"EUR"}},
+		{"balance_type":"ITBD","reference_date":"2026-07-18","balance_amount":{"amount":"-3.10","currency":"EUR"}},
+		{"balance_type":"ITBD","reference_date":"2026-07-20","balance_amount":{"amount":"9","currency":"EUR"}},
+		{"balance_type":"XPCD","reference_date":"2026-07-17","balance_amount":{"amount":"9","currency":"EUR"}}
+	]}`), now)
+	if len(balances) != 2 || balances[0].ReferenceDate != "2026-07-18" || balances[0].Amount != "-3.10" ||
+		balances[1].Type != "CLBD" || balances[1].Amount != "12.50" || balances[1].AccountID != 4 {
+		t.Fatalf("balances = %#v", balances)
+	}
+}
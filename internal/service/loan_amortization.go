@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/recurrence"
+)
+
+type loanTerms struct {
+	principal   *big.Rat
+	monthlyRate *big.Rat
+	payment     *big.Rat
+	dates       []time.Time
+}
+
+type loanExtra struct {
+	date   time.Time
+	amount *big.Rat
+}
+
+type loanRow struct {
+	date                                         time.Time
+	payment, principal, interest, extra, balance *big.Rat
+}
+
+// loanTermsFor derives the installment dates and the fixed annuity payment.
+// The first installment falls on the first payment day after the start
+// date.
+func loanTermsFor(loan model.Loan) (loanTerms, error) {
+	principal, ok := new(big.Rat).SetString(loan.Principal)
+	rate, rateOK := new(big.Rat).SetString(loan.AnnualRate)
+	start, err := time.Parse(time.DateOnly, loan.StartDate)
+	if !ok || !rateOK || err != nil || principal.Sign() <= 0 || rate.Sign() < 0 || loan.TermMonths < 1 {
+		return loanTerms{}, fmt.Errorf("stored loan %d is invalid", loan.ID)
+	}
+	first := start.AddDate(0, 0, 1)
+	dates, err := recurrence.Occurrences(recurrence.Rule{
+		Frequency: "monthly", Interval: 1, StartDate: first, DayOfMonth: loan.PaymentDay,
+	}, first, start.AddDate(0, loan.TermMonths+1, 0))
+	if err != nil {
+		return loanTerms{}, fmt.Errorf("loan %d installment dates: %w", loan.ID, err)
+	}
+	if len(dates) < loan.TermMonths {
+		return loanTerms{}, fmt.Errorf("loan %d has %d installment dates for %d months", loan.ID, len(dates), loan.TermMonths)
+	}
+	monthlyRate := new(big.Rat).Quo(rate, big.NewRat(1200, 1))
+	return loanTerms{
+		principal:   principal,
+		monthlyRate: monthlyRate,
+		payment:     annuityPayment(principal, monthlyRate, loan.TermMonths),
+		dates:       dates[:loan.TermMonths],
+	}, nil
+}
+
+// annuityPayment is principal·r / (1 − (1+r)^−months), rounded to cents.
+func annuityPayment(principal, monthlyRate *big.Rat, months int) *big.Rat {
+	if monthlyRate.Sign() == 0 {
+		return roundCents(new(big.Rat).Quo(principal, big.NewRat(int64(months), 1)))
+	}
+	growth := big.NewRat(1, 1)
+	factor := new(big.Rat).Add(big.NewRat(1, 1), monthlyRate)
+	for range months {
+		growth.Mul(growth, factor)
+	}
+	// principal·r·g / (g − 1) equals the textbook form without a negative power.
+	payment := new(big.Rat).Mul(principal, monthlyRate)
+	payment.Mul(payment, growth)
+	payment.Quo(payment, growth.Sub(growth, big.NewRat(1, 1)))
+	return roundCents(payment)
+}
+
+// amortizeLoan walks the schedule until the balance is repaid. Interest is
+// charged monthly on the balance after the previous installment, and the
+// last installment clears whatever rounding left over. An extra payment
+// reduces the balance right after the latest installment on or before its
+// date, so the payment stays the same and the term gets shorter; extras made
+// before the first installment reduce the balance it is charged on and are
+// shown on it. extraMonthly is added to every installment after today.
+func amortizeLoan(terms loanTerms, extras []loanExtra, extraMonthly *big.Rat, today time.Time) []loanRow {
+	sort.SliceStable(extras, func(i, j int) bool { return extras[i].date.Before(extras[j].date) })
+	balance := new(big.Rat).Set(terms.principal)
+	next := 0
+	applyExtras := func(before time.Time, last bool) *big.Rat {
+		total := new(big.Rat)
+		for ; next < len(extras) && (last || extras[next].date.Before(before)); next++ {
+			total.Add(total, extras[next].amount)
+		}
+		return total
+	}
+	clamp := func(value *big.Rat) *big.Rat {
+		if value.Cmp(balance) > 0 {
+			value.Set(balance)
+		}
+		balance.Sub(balance, value)
+		return value
+	}
+
+	upfront := clamp(applyExtras(terms.dates[0], false))
+	rows := make([]loanRow, 0, len(terms.dates))
+	for index, date := range terms.dates {
+		if balance.Sign() == 0 {
+			break
+		}
+		last := index == len(terms.dates)-1
+		interest := roundCents(new(big.Rat).Mul(balance, terms.monthlyRate))
+		principal := new(big.Rat).Sub(terms.payment, interest)
+		if principal.Sign() < 0 {
+			principal.SetInt64(0)
+		}
+		if last || principal.Cmp(balance) > 0 {
+			principal.Set(balance)
+		}
+		balance.Sub(balance, principal)
+
+		extra := new(big.Rat)
+		if !last {
+			extra = applyExtras(terms.dates[index+1], false)
+		}
+		if extraMonthly != nil && date.After(today) {
+			extra.Add(extra, extraMonthly)
+		}
+		extra = clamp(extra)
+		if index == 0 {
+			extra.Add(extra, upfront)
+		}
+		rows = append(rows, loanRow{
+			date: date, payment: new(big.Rat).Add(principal, interest),
+			principal: principal, interest: interest, extra: extra, balance: new(big.Rat).Set(balance),
+		})
+	}
+	return rows
+}
+
+// loanSummary fills in the computed loan fields, assuming every installment
+// up to today was paid on schedule.
+func loanSummary(loan *model.Loan, payments []model.LoanPayment, today time.Time) error {
+	terms, err := loanTermsFor(*loan)
+	if err != nil {
+		return err
+	}
+	extras, err := loanExtras(payments)
+	if err != nil {
+		return err
+	}
+	rows := amortizeLoan(terms, extras, nil, today)
+	remaining := new(big.Rat).Set(terms.principal)
+	for _, extra := range extras {
+		if extra.date.Before(terms.dates[0]) && !extra.date.After(today) {
+			remaining.Sub(remaining, extra.amount)
+		}
+	}
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+	interestYTD, totalInterest := new(big.Rat), new(big.Rat)
+	loan.NextPaymentDate = ""
+	for _, row := range rows {
+		totalInterest.Add(totalInterest, row.interest)
+		if row.date.After(today) {
+			if loan.NextPaymentDate == "" {
+				loan.NextPaymentDate = row.date.Format(time.DateOnly)
+			}
+			continue
+		}
+		remaining.Set(row.balance)
+		if row.date.Year() == today.Year() {
+			interestYTD.Add(interestYTD, row.interest)
+		}
+	}
+	loan.MonthlyPayment = formatRat(terms.payment, 2)
+	loan.RemainingBalance = formatRat(remaining, 2)
+	loan.PrincipalPaid = formatRat(new(big.Rat).Sub(terms.principal, remaining), 2)
+	loan.InterestPaidYearToDate = formatRat(interestYTD, 2)
+	loan.TotalInterest = formatRat(totalInterest, 2)
+	loan.PayoffDate = ""
+	if len(rows) > 0 {
+		loan.PayoffDate = rows[len(rows)-1].date.Format(time.DateOnly)
+	}
+	return nil
+}
+
+// loanPayoff summarizes the installments still due after today.
+func loanPayoff(rows []loanRow, today time.Time) model.LoanPayoff {
+	payoff := model.LoanPayoff{RemainingInterest: "0.00"}
+	interest := new(big.Rat)
+	for _, row := range rows {
+		if row.date.After(today) {
+			payoff.RemainingPayments++
+			interest.Add(interest, row.interest)
+		}
+	}
+	if len(rows) > 0 {
+		payoff.PayoffDate = rows[len(rows)-1].date.Format(time.DateOnly)
+	}
+	payoff.RemainingInterest = formatRat(interest, 2)
+	return payoff
+}
+
+func loanExtras(payments []model.LoanPayment) ([]loanExtra, error) {
+	extras := make([]loanExtra, 0)
+	for _, payment := range payments {
+		if payment.Kind != "extra" {
+			continue
+		}
+		amount, ok := new(big.Rat).SetString(payment.Amount)
+		date, err := time.Parse(time.DateOnly, payment.Date)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("stored loan payment %d is invalid", payment.ID)
+		}
+		extras = append(extras, loanExtra{date: date, amount: amount})
+	}
+	return extras, nil
+}
+
+func roundCents(value *big.Rat) *big.Rat {
+	rounded, _ := new(big.Rat).SetString(value.FloatString(2))
+	return rounded
+}
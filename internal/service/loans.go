@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumLoanNameRunes  = 100
+	maximumLoanTermMonths = 600
+)
+
+var loanKinds = map[string]bool{"mortgage": true, "car": true, "personal": true, "other": true}
+
+func (s *Service) ListLoans(ctx context.Context, userID int, includeArchived bool) ([]model.Loan, error) {
+	items, err := s.store.ListLoans(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list loans: %w", err))
+	}
+	for index := range items {
+		if items[index], err = s.withLoanSummary(ctx, userID, items[index]); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+func (s *Service) GetLoan(ctx context.Context, userID, loanID int) (model.Loan, error) {
+	item, err := s.getLoan(ctx, userID, loanID)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	return s.withLoanSummary(ctx, userID, item)
+}
+
+func (s *Service) CreateLoan(ctx context.Context, userID int, request model.LoanRequest) (model.Loan, error) {
+	normalized, err := validateLoan(request)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	item, err := s.store.CreateLoan(ctx, userID, normalized)
+	if err != nil {
+		return model.Loan{}, apperrors.Internal(fmt.Errorf("create loan: %w", err))
+	}
+	return s.withLoanSummary(ctx, userID, item)
+}
+
+func (s *Service) UpdateLoan(ctx context.Context, userID, loanID int, request model.LoanRequest) (model.Loan, error) {
+	existing, err := s.getLoan(ctx, userID, loanID)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	if existing.Status != "active" {
+		return model.Loan{}, apperrors.Conflict("archived loans cannot be edited")
+	}
+	normalized, err := validateLoan(request)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	item, err := s.store.UpdateLoan(ctx, userID, loanID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Loan{}, apperrors.NotFound("loan not found")
+	}
+	if err != nil {
+		return model.Loan{}, apperrors.Internal(fmt.Errorf("update loan: %w", err))
+	}
+	return s.withLoanSummary(ctx, userID, item)
+}
+
+func (s *Service) DeleteLoan(ctx context.Context, userID, loanID int) error {
+	if err := validateID(loanID); err != nil {
+		return err
+	}
+	err := s.store.ArchiveLoan(ctx, userID, loanID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("loan not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("archive loan: %w", err))
+	}
+	return nil
+}
+
+// GetLoanSchedule returns the amortization schedule including recorded extra
+// payments. The n-th linked installment transaction marks the n-th
+// installment as paid.
+func (s *Service) GetLoanSchedule(ctx context.Context, userID, loanID int) ([]model.LoanInstallment, error) {
+	loan, err := s.getLoan(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.listLoanPayments(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	terms, err := loanTermsFor(loan)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	extras, err := loanExtras(payments)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	linked := make([]model.LoanPayment, 0)
+	for _, payment := range payments {
+		if payment.Kind == "installment" {
+			linked = append(linked, payment)
+		}
+	}
+	rows := amortizeLoan(terms, extras, nil, today)
+	items := make([]model.LoanInstallment, 0, len(rows))
+	for index, row := range rows {
+		item := model.LoanInstallment{
+			Number: index + 1, Date: row.date.Format(time.DateOnly), Payment: formatRat(row.payment, 2),
+			Principal: formatRat(row.principal, 2), Interest: formatRat(row.interest, 2),
+			Balance: formatRat(row.balance, 2),
+		}
+		if row.extra.Sign() > 0 {
+			item.ExtraPayment = formatRat(row.extra, 2)
+		}
+		if index < len(linked) {
+			item.Paid = true
+			item.TransactionID = linked[index].TransactionID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ProjectLoanPayoff compares the remaining schedule with one where
+// extraMonthly is added to every future installment and lumpSum is paid
+// today.
+func (s *Service) ProjectLoanPayoff(
+	ctx context.Context,
+	userID, loanID int,
+	extraMonthly, lumpSum string,
+) (model.LoanPayoffProjection, error) {
+	monthly, err := normalizeOptionalLoanAmount(extraMonthly, "extra_monthly")
+	if err != nil {
+		return model.LoanPayoffProjection{}, err
+	}
+	lump, err := normalizeOptionalLoanAmount(lumpSum, "lump_sum")
+	if err != nil {
+		return model.LoanPayoffProjection{}, err
+	}
+	loan, err := s.getLoan(ctx, userID, loanID)
+	if err != nil {
+		return model.LoanPayoffProjection{}, err
+	}
+	payments, err := s.listLoanPayments(ctx, userID, loanID)
+	if err != nil {
+		return model.LoanPayoffProjection{}, err
+	}
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.LoanPayoffProjection{}, apperrors.Internal(err)
+	}
+	terms, err := loanTermsFor(loan)
+	if err != nil {
+		return model.LoanPayoffProjection{}, apperrors.Internal(err)
+	}
+	extras, err := loanExtras(payments)
+	if err != nil {
+		return model.LoanPayoffProjection{}, apperrors.Internal(err)
+	}
+	baseline := loanPayoff(amortizeLoan(terms, append([]loanExtra(nil), extras...), nil, today), today)
+	if lump.Sign() > 0 {
+		extras = append(extras, loanExtra{date: today, amount: lump})
+	}
+	scenario := loanPayoff(amortizeLoan(terms, extras, monthly, today), today)
+
+	baselineInterest, _ := new(big.Rat).SetString(baseline.RemainingInterest)
+	scenarioInterest, _ := new(big.Rat).SetString(scenario.RemainingInterest)
+	return model.LoanPayoffProjection{
+		ExtraMonthly: formatRat(monthly, 2), LumpSum: formatRat(lump, 2),
+		Baseline: baseline, Scenario: scenario,
+		MonthsSaved:   baseline.RemainingPayments - scenario.RemainingPayments,
+		InterestSaved: formatRat(baselineInterest.Sub(baselineInterest, scenarioInterest), 2),
+	}, nil
+}
+
+func (s *Service) ListLoanPayments(ctx context.Context, userID, loanID int) ([]model.LoanPayment, error) {
+	if _, err := s.getLoan(ctx, userID, loanID); err != nil {
+		return nil, err
+	}
+	return s.listLoanPayments(ctx, userID, loanID)
+}
+
+// AddLoanPayment links an expense transaction to a loan as an installment or
+// an extra principal payment. Extra payments made outside the ledger can be
+// recorded with an amount and date instead.
+func (s *Service) AddLoanPayment(
+	ctx context.Context,
+	userID, loanID int,
+	request model.LoanPaymentRequest,
+) (model.LoanPayment, error) {
+	loan, err := s.getLoan(ctx, userID, loanID)
+	if err != nil {
+		return model.LoanPayment{}, err
+	}
+	if loan.Status != "active" {
+		return model.LoanPayment{}, apperrors.Conflict("archived loans cannot receive payments")
+	}
+	normalized, err := s.validateLoanPayment(ctx, userID, request)
+	if err != nil {
+		return model.LoanPayment{}, err
+	}
+	if normalized.Date <= loan.StartDate {
+		return model.LoanPayment{}, apperrors.Validation("payment date must be after the loan start_date")
+	}
+	item, err := s.store.CreateLoanPayment(ctx, userID, loanID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.LoanPayment{}, apperrors.NotFound("loan not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.LoanPayment{}, apperrors.Conflict("transaction is already linked to a loan")
+	}
+	if err != nil {
+		return model.LoanPayment{}, apperrors.Internal(fmt.Errorf("add loan payment: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) DeleteLoanPayment(ctx context.Context, userID, loanID, paymentID int) error {
+	if err := validateID(loanID); err != nil {
+		return err
+	}
+	if err := validateID(paymentID); err != nil {
+		return err
+	}
+	err := s.store.DeleteLoanPayment(ctx, userID, loanID, paymentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("loan payment not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete loan payment: %w", err))
+	}
+	return nil
+}
+
+func (s *Service) getLoan(ctx context.Context, userID, loanID int) (model.Loan, error) {
+	if err := validateID(loanID); err != nil {
+		return model.Loan{}, err
+	}
+	item, err := s.store.GetLoan(ctx, userID, loanID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Loan{}, apperrors.NotFound("loan not found")
+	}
+	if err != nil {
+		return model.Loan{}, apperrors.Internal(fmt.Errorf("get loan: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) listLoanPayments(ctx context.Context, userID, loanID int) ([]model.LoanPayment, error) {
+	items, err := s.store.ListLoanPayments(ctx, userID, loanID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list loan payments: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) withLoanSummary(ctx context.Context, userID int, item model.Loan) (model.Loan, error) {
+	payments, err := s.listLoanPayments(ctx, userID, item.ID)
+	if err != nil {
+		return model.Loan{}, err
+	}
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.Loan{}, apperrors.Internal(err)
+	}
+	if err := loanSummary(&item, payments, today); err != nil {
+		return model.Loan{}, apperrors.Internal(err)
+	}
+	return item, nil
+}
+
+func validateLoan(request model.LoanRequest) (model.LoanRequest, error) {
+	name, err := normalizeLimitedText(request.Name, "name", maximumLoanNameRunes, false)
+	if err != nil {
+		return model.LoanRequest{}, err
+	}
+	kind := strings.ToLower(strings.TrimSpace(request.Kind))
+	if kind == "" {
+		kind = "other"
+	}
+	if !loanKinds[kind] {
+		return model.LoanRequest{}, apperrors.Validation("kind must be mortgage, car, personal, or other")
+	}
+	principal, err := normalizeAmount(request.Principal)
+	if err != nil {
+		return model.LoanRequest{}, apperrors.Validation("principal must be a positive decimal with at most 2 decimal places")
+	}
+	rate, err := normalizePercentage(request.AnnualRate, "annual_rate")
+	if err != nil {
+		return model.LoanRequest{}, err
+	}
+	if request.TermMonths < 1 || request.TermMonths > maximumLoanTermMonths {
+		return model.LoanRequest{}, apperrors.Validation(
+			fmt.Sprintf("term_months must be between 1 and %d", maximumLoanTermMonths),
+		)
+	}
+	start, err := parseDate(request.StartDate, "start_date")
+	if err != nil {
+		return model.LoanRequest{}, err
+	}
+	paymentDay := request.PaymentDay
+	if paymentDay == 0 {
+		paymentDay = start.Day()
+	}
+	if paymentDay < 1 || paymentDay > 31 {
+		return model.LoanRequest{}, apperrors.Validation("payment_day must be between 1 and 31")
+	}
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	if currency == "" {
+		currency = supportedCurrency
+	}
+	if currency != supportedCurrency {
+		return model.LoanRequest{}, apperrors.Validation("currency must be EUR")
+	}
+	return model.LoanRequest{
+		Name: name, Kind: kind, Principal: principal, AnnualRate: rate, TermMonths: request.TermMonths,
+		StartDate: start.Format(time.DateOnly), PaymentDay: paymentDay, Currency: currency,
+	}, nil
+}
+
+func (s *Service) validateLoanPayment(
+	ctx context.Context,
+	userID int,
+	request model.LoanPaymentRequest,
+) (model.LoanPaymentRequest, error) {
+	kind := strings.ToLower(strings.TrimSpace(request.Kind))
+	if kind != "installment" && kind != "extra" {
+		return model.LoanPaymentRequest{}, apperrors.Validation("kind must be installment or extra")
+	}
+	if request.TransactionID == nil {
+		if kind == "installment" {
+			return model.LoanPaymentRequest{}, apperrors.Validation("installment payments require transaction_id")
+		}
+		amount, err := normalizeAmount(request.Amount)
+		if err != nil {
+			return model.LoanPaymentRequest{}, err
+		}
+		today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+		if err != nil {
+			return model.LoanPaymentRequest{}, apperrors.Internal(err)
+		}
+		date := today
+		if strings.TrimSpace(request.Date) != "" {
+			if date, err = parseDate(request.Date, "date"); err != nil {
+				return model.LoanPaymentRequest{}, err
+			}
+			if date.After(today) {
+				return model.LoanPaymentRequest{}, apperrors.Validation("date must not be in the future")
+			}
+		}
+		return model.LoanPaymentRequest{Kind: kind, Amount: amount, Date: date.Format(time.DateOnly)}, nil
+	}
+
+	if strings.TrimSpace(request.Amount) != "" || strings.TrimSpace(request.Date) != "" {
+		return model.LoanPaymentRequest{}, apperrors.Validation("amount and date are taken from the linked transaction")
+	}
+	if err := validateID(*request.TransactionID); err != nil {
+		return model.LoanPaymentRequest{}, err
+	}
+	transaction, err := s.store.GetTransaction(ctx, userID, *request.TransactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.LoanPaymentRequest{}, apperrors.Validation("transaction_id must be an existing transaction")
+	}
+	if err != nil {
+		return model.LoanPaymentRequest{}, apperrors.Internal(fmt.Errorf("validate loan payment transaction: %w", err))
+	}
+	if transaction.Type != "expense" || transaction.Status != "booked" || transaction.Currency != supportedCurrency {
+		return model.LoanPaymentRequest{}, apperrors.Validation("transaction_id must be a booked EUR expense")
+	}
+	return model.LoanPaymentRequest{
+		Kind: kind, TransactionID: request.TransactionID, Amount: transaction.Amount, Date: transaction.OccurredAt,
+	}, nil
+}
+
+func normalizeOptionalLoanAmount(value, field string) (*big.Rat, error) {
+	if strings.TrimSpace(value) == "" {
+		return new(big.Rat), nil
+	}
+	amount, err := normalizeAmount(value)
+	if err != nil {
+		return nil, apperrors.Validation(field + " must be a positive decimal with at most 2 decimal places")
+	}
+	parsed, _ := new(big.Rat).SetString(amount)
+	return parsed, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func loanTestStore() *fakeStore {
+	transactionID := 50
+	return &fakeStore{
+		getLoan: func(_ context.Context, _ int, loanID int) (model.Loan, error) {
+			return model.Loan{
+				ID: loanID, Principal: "12000.00", AnnualRate: "6.000", TermMonths: 12,
+				StartDate: "2026-01-15", PaymentDay: 1, Currency: "EUR", Status: "active",
+			}, nil
+		},
+		listLoanPayments: func(context.Context, int, int) ([]model.LoanPayment, error) {
+			return []model.LoanPayment{
+				{ID: 1, Kind: "installment", TransactionID: &transactionID, Amount: "1032.80", Date: "2026-02-01"},
+				{ID: 2, Kind: "extra", Amount: "1000.00", Date: "2026-03-10"},
+			}, nil
+		},
+	}
+}
+
+func TestLoanSummaryAppliesExtraPayments(t *testing.T) {
+	service := testService(loanTestStore())
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	loan, err := service.GetLoan(context.Background(), 7, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loan.MonthlyPayment != "1032.80" || loan.RemainingBalance != "5069.61" || loan.PrincipalPaid != "6930.39" ||
+		loan.InterestPaidYearToDate != "266.41" || loan.TotalInterest != "342.53" ||
+		loan.NextPaymentDate != "2026-08-01" || loan.PayoffDate != "2026-12-01" {
+		t.Fatalf("loan = %#v", loan)
+	}
+
+	schedule, err := service.GetLoanSchedule(context.Background(), 7, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 11 {
+		t.Fatalf("schedule has %d installments", len(schedule))
+	}
+	first, second, last := schedule[0], schedule[1], schedule[10]
+	if !first.Paid || first.TransactionID == nil || *first.TransactionID != 50 || first.Interest != "60.00" ||
+		first.Principal != "972.80" || first.Balance != "11027.20" {
+		t.Fatalf("first installment = %#v", first)
+	}
+	if second.Paid || second.Interest != "55.14" || second.ExtraPayment != "1000.00" || second.Balance != "9049.54" {
+		t.Fatalf("second installment = %#v", second)
+	}
+	if last.Date != "2026-12-01" || last.Payment != "1014.53" || last.Balance != "0.00" {
+		t.Fatalf("last installment = %#v", last)
+	}
+}
+
+func TestAmortizeLoanWithoutInterestSplitsPrincipalEvenly(t *testing.T) {
+	terms, err := loanTermsFor(model.Loan{
+		ID: 1, Principal: "1000.00", AnnualRate: "0.000", TermMonths: 3, StartDate: "2026-01-31", PaymentDay: 31,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := amortizeLoan(terms, nil, nil, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC))
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d", len(rows))
+	}
+	for index, want := range []struct{ date, payment string }{
+		{"2026-02-28", "333.33"}, {"2026-03-31", "333.33"}, {"2026-04-30", "333.34"},
+	} {
+		if rows[index].date.Format(time.DateOnly) != want.date || formatRat(rows[index].payment, 2) != want.payment ||
+			rows[index].interest.Sign() != 0 {
+			t.Fatalf("row %d = %s %s", index, rows[index].date.Format(time.DateOnly), formatRat(rows[index].payment, 2))
+		}
+	}
+}
+
+func TestProjectLoanPayoffComparesScenario(t *testing.T) {
+	service := testService(loanTestStore())
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	projection, err := service.ProjectLoanPayoff(context.Background(), 7, 4, "200", "500")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.LoanPayoffProjection{
+		ExtraMonthly: "200.00", LumpSum: "500.00",
+		Baseline:    model.LoanPayoff{PayoffDate: "2026-12-01", RemainingPayments: 5, RemainingInterest: "76.12"},
+		Scenario:    model.LoanPayoff{PayoffDate: "2026-11-01", RemainingPayments: 4, RemainingInterest: "54.98"},
+		MonthsSaved: 1, InterestSaved: "21.14",
+	}
+	if projection != want {
+		t.Fatalf("projection = %#v", projection)
+	}
+	for _, values := range [][2]string{{"-5", ""}, {"", "abc"}} {
+		if _, err := service.ProjectLoanPayoff(context.Background(), 7, 4, values[0], values[1]); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("ProjectLoanPayoff(%q, %q) error = %v", values[0], values[1], err)
+		}
+	}
+}
+
+func TestAddLoanPaymentLinksBookedExpense(t *testing.T) {
+	var created model.LoanPaymentRequest
+	store := loanTestStore()
+	store.getTransaction = func(_ context.Context, _ int, transactionID int) (model.Transaction, error) {
+		switch transactionID {
+		case 60:
+			return model.Transaction{ID: 60, Type: "expense", Status: "booked", Currency: "EUR", Amount: "1032.80", OccurredAt: "2026-03-01"}, nil
+		case 61:
+			return model.Transaction{ID: 61, Type: "income", Status: "booked", Currency: "EUR", Amount: "10.00", OccurredAt: "2026-03-01"}, nil
+		case 62:
+			return model.Transaction{ID: 62, Type: "expense", Status: "booked", Currency: "EUR", Amount: "5.00", OccurredAt: "2026-04-01"}, nil
+		}
+		return model.Transaction{}, repository.ErrNotFound
+	}
+	store.createLoanPayment = func(_ context.Context, _ int, _ int, request model.LoanPaymentRequest) (model.LoanPayment, error) {
+		if request.TransactionID != nil && *request.TransactionID == 62 {
+			return model.LoanPayment{}, repository.ErrConflict
+		}
+		created = request
+		return model.LoanPayment{ID: 3, Kind: request.Kind, TransactionID: request.TransactionID, Amount: request.Amount, Date: request.Date}, nil
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	transactionID := 60
+	if _, err := service.AddLoanPayment(context.Background(), 7, 4, model.LoanPaymentRequest{
+		Kind: " Installment ", TransactionID: &transactionID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if created.Kind != "installment" || created.Amount != "1032.80" || created.Date != "2026-03-01" {
+		t.Fatalf("linked payment = %#v", created)
+	}
+	if _, err := service.AddLoanPayment(context.Background(), 7, 4, model.LoanPaymentRequest{Kind: "extra", Amount: "250"}); err != nil {
+		t.Fatal(err)
+	}
+	if created != (model.LoanPaymentRequest{Kind: "extra", Amount: "250.00", Date: "2026-07-18"}) {
+		t.Fatalf("extra payment = %#v", created)
+	}
+
+	income, missing, duplicate := 61, 99, 62
+	for name, request := range map[string]model.LoanPaymentRequest{
+		"unknown kind":             {Kind: "refund", Amount: "10"},
+		"installment without link": {Kind: "installment"},
+		"income transaction":       {Kind: "installment", TransactionID: &income},
+		"missing transaction":      {Kind: "extra", TransactionID: &missing},
+		"amount with transaction":  {Kind: "extra", TransactionID: &transactionID, Amount: "10"},
+		"future extra":             {Kind: "extra", Amount: "10", Date: "2026-07-19"},
+		"extra before start":       {Kind: "extra", Amount: "10", Date: "2026-01-10"},
+	} {
+		if _, err := service.AddLoanPayment(context.Background(), 7, 4, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+	if _, err := service.AddLoanPayment(context.Background(), 7, 4, model.LoanPaymentRequest{
+		Kind: "installment", TransactionID: &duplicate,
+	}); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("duplicate link error = %v", err)
+	}
+}
+
+func TestValidateLoanNormalizesRequest(t *testing.T) {
+	loan, err := validateLoan(model.LoanRequest{
+		Name: " Flat ", Kind: "Mortgage", Principal: "250000", AnnualRate: "3.5", TermMonths: 300, StartDate: "2026-03-12",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loan != (model.LoanRequest{
+		Name: "Flat", Kind: "mortgage", Principal: "250000.00", AnnualRate: "3.500", TermMonths: 300,
+		StartDate: "2026-03-12", PaymentDay: 12, Currency: "EUR",
+	}) {
+		t.Fatalf("loan = %#v", loan)
+	}
+	base := model.LoanRequest{Name: "Car", Principal: "9000", AnnualRate: "4", TermMonths: 48, StartDate: "2026-03-12"}
+	for name, change := range map[string]func(*model.LoanRequest){
+		"unknown kind":    func(r *model.LoanRequest) { r.Kind = "boat" },
+		"zero principal":  func(r *model.LoanRequest) { r.Principal = "0" },
+		"negative rate":   func(r *model.LoanRequest) { r.AnnualRate = "-1" },
+		"rate above 100":  func(r *model.LoanRequest) { r.AnnualRate = "100.001" },
+		"rate precision":  func(r *model.LoanRequest) { r.AnnualRate = "3.1234" },
+		"zero term":       func(r *model.LoanRequest) { r.TermMonths = 0 },
+		"term too long":   func(r *model.LoanRequest) { r.TermMonths = 601 },
+		"bad payment day": func(r *model.LoanRequest) { r.PaymentDay = 32 },
+		"other currency":  func(r *model.LoanRequest) { r.Currency = "USD" },
+		"missing start":   func(r *model.LoanRequest) { r.StartDate = "" },
+	} {
+		request := base
+		change(&request)
+		if _, err := validateLoan(request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+}
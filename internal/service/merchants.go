@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/merchant"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	defaultMerchantSpendingDays = 365
+	maximumMerchantSpendingDays = 366
+	merchantRefreshBatchSize    = 500
+)
+
+func merchantMatch(description string) model.MerchantMatch {
+	key, name := merchant.Normalize(description)
+	return model.MerchantMatch{Key: key, Name: name}
+}
+
+// merchantKeys groups rows by merchant. A row linked to a registry merchant,
+// or whose normalized description is one of a merchant's aliases, uses that
+// merchant's lowercase name, which is unique per user. Other rows, such as
+// manual entries and schedules, use the normalized description.
+type merchantKeys struct {
+	names   map[int]string
+	aliases map[string]int
+}
+
+func (s *Service) loadMerchantKeys(ctx context.Context, userID int) (merchantKeys, error) {
+	merchants, err := s.store.ListMerchants(ctx, userID)
+	if err != nil {
+		return merchantKeys{}, apperrors.Internal(fmt.Errorf("list merchants: %w", err))
+	}
+	keys := merchantKeys{names: make(map[int]string, len(merchants)), aliases: make(map[string]int)}
+	for _, item := range merchants {
+		keys.names[item.ID] = strings.ToLower(item.Name)
+		for _, alias := range item.Aliases {
+			keys.aliases[alias] = item.ID
+		}
+	}
+	return keys, nil
+}
+
+// key returns the merchant key of a row, or "" when its description names no
+// merchant.
+func (keys merchantKeys) key(merchantID *int, description string) string {
+	if merchantID != nil {
+		if name, ok := keys.names[*merchantID]; ok {
+			return name
+		}
+	}
+	key, _ := merchant.Normalize(description)
+	if id, ok := keys.aliases[key]; ok {
+		return keys.names[id]
+	}
+	return key
+}
+
+func (s *Service) ListMerchants(ctx context.Context, userID int) ([]model.Merchant, error) {
+	merchants, err := s.store.ListMerchants(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list merchants: %w", err))
+	}
+	return merchants, nil
+}
+
+func (s *Service) RenameMerchant(ctx context.Context, userID, merchantID int, request model.MerchantRequest) (model.Merchant, error) {
+	if err := validateID(merchantID); err != nil {
+		return model.Merchant{}, err
+	}
+	name, err := normalizeLimitedText(request.Name, "name", merchant.MaximumNameRunes, false)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	item, err := s.store.RenameMerchant(ctx, userID, merchantID, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Merchant{}, apperrors.NotFound("merchant not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.Merchant{}, apperrors.Conflict("a merchant with this name already exists; merge them instead")
+	}
+	if err != nil {
+		return model.Merchant{}, apperrors.Internal(fmt.Errorf("rename merchant: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return item, nil
+}
+
+// MergeMerchants folds one merchant into another. Its aliases move along, so
+// future imports of the merged merchant's descriptions land on the kept one.
+func (s *Service) MergeMerchants(ctx context.Context, userID int, request model.MerchantMergeRequest) (model.Merchant, error) {
+	if err := validateID(request.KeepID); err != nil {
+		return model.Merchant{}, err
+	}
+	if err := validateID(request.MergeID); err != nil {
+		return model.Merchant{}, err
+	}
+	if request.KeepID == request.MergeID {
+		return model.Merchant{}, apperrors.Validation("keep_id and merge_id must be different merchants")
+	}
+	item, err := s.store.MergeMerchants(ctx, userID, request.KeepID, request.MergeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Merchant{}, apperrors.NotFound("merchant not found")
+	}
+	if err != nil {
+		return model.Merchant{}, apperrors.Internal(fmt.Errorf("merge merchants: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return item, nil
+}
+
+// RefreshMerchants attaches a merchant to imported and synced rows that have
+// none, such as rows recorded before merchants existed.
+func (s *Service) RefreshMerchants(ctx context.Context, userID int) (model.MerchantRefreshResult, error) {
+	result := model.MerchantRefreshResult{}
+	afterID := 0
+	for {
+		candidates, err := s.store.ListMerchantCandidates(ctx, userID, afterID, merchantRefreshBatchSize)
+		if err != nil {
+			return model.MerchantRefreshResult{}, apperrors.Internal(fmt.Errorf("list merchant candidates: %w", err))
+		}
+		if len(candidates) == 0 {
+			if result.Assigned > 0 {
+				s.invalidateReports(ctx, userID)
+			}
+			return result, nil
+		}
+		assignments := make([]repository.MerchantAssignment, 0, len(candidates))
+		for _, candidate := range candidates {
+			afterID = candidate.TransactionID
+			if match := merchantMatch(candidate.Description); match.Key != "" {
+				assignments = append(assignments, repository.MerchantAssignment{TransactionID: candidate.TransactionID, Merchant: match})
+			}
+		}
+		if err := s.store.AssignTransactionMerchants(ctx, userID, assignments); err != nil {
+			return model.MerchantRefreshResult{}, apperrors.Internal(fmt.Errorf("assign transaction merchants: %w", err))
+		}
+		result.Assigned += len(assignments)
+	}
+}
+
+// ListMerchantSpending totals expenses per merchant. The range defaults to
+// the last 365 days.
+func (s *Service) ListMerchantSpending(ctx context.Context, userID int, fromString, toString string) ([]model.MerchantSpending, error) {
+	from, to, err := s.merchantSpendingRange(fromString, toString)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListMerchantSpending(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list merchant spending: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) GetMerchantSpending(
+	ctx context.Context,
+	userID, merchantID int,
+	fromString, toString string,
+) (model.MerchantSpendingDetail, error) {
+	if err := validateID(merchantID); err != nil {
+		return model.MerchantSpendingDetail{}, err
+	}
+	from, to, err := s.merchantSpendingRange(fromString, toString)
+	if err != nil {
+		return model.MerchantSpendingDetail{}, err
+	}
+	item, err := s.store.GetMerchant(ctx, userID, merchantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.MerchantSpendingDetail{}, apperrors.NotFound("merchant not found")
+	}
+	if err != nil {
+		return model.MerchantSpendingDetail{}, apperrors.Internal(fmt.Errorf("get merchant: %w", err))
+	}
+	months, err := s.store.ListMerchantMonthlySpending(ctx, userID, merchantID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return model.MerchantSpendingDetail{}, apperrors.Internal(fmt.Errorf("list merchant monthly spending: %w", err))
+	}
+	detail := model.MerchantSpendingDetail{
+		MerchantSpending: model.MerchantSpending{MerchantID: item.ID, Name: item.Name, Currency: supportedCurrency},
+		From:             from.Format("2006-01-02"),
+		To:               to.Format("2006-01-02"),
+		Months:           months,
+	}
+	total := new(big.Rat)
+	for _, month := range months {
+		value, ok := new(big.Rat).SetString(month.Total)
+		if !ok {
+			return model.MerchantSpendingDetail{}, apperrors.Internal(fmt.Errorf("invalid merchant spending total %q", month.Total))
+		}
+		total.Add(total, value)
+		detail.Transactions += month.Transactions
+	}
+	detail.Total = formatRat(total, 2)
+	return detail, nil
+}
+
+func (s *Service) merchantSpendingRange(fromString, toString string) (time.Time, time.Time, error) {
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	if toString != "" {
+		parsed, err := parseDate(toString, "to")
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultMerchantSpendingDays)
+	if fromString != "" {
+		parsed, err := parseDate(fromString, "from")
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, apperrors.Validation("from must be before or equal to to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maximumMerchantSpendingDays {
+		return time.Time{}, time.Time{}, apperrors.Validation(
+			fmt.Sprintf("merchant spending date range must be %d days or less", maximumMerchantSpendingDays),
+		)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestRefreshMerchantsPagesThroughCandidates(t *testing.T) {
+	var afterIDs []int
+	var assigned []repository.MerchantAssignment
+	store := &fakeStore{
+		listMerchantCandidates: func(_ context.Context, userID, afterID, limit int) ([]repository.MerchantCandidate, error) {
+			if userID != 7 || limit != merchantRefreshBatchSize {
+				t.Fatalf("candidates = %d, %d", userID, limit)
+			}
+			afterIDs = append(afterIDs, afterID)
+			if afterID > 0 {
+				return []repository.MerchantCandidate{}, nil
+			}
+			return []repository.MerchantCandidate{
+				{TransactionID: 3, Description: "LIDL 1234 SOFIA BG"},
+				{TransactionID: 8, Description: "000123"},
+				{TransactionID: 9, Description: "Lidl 77 Plovdiv"},
+			}, nil
+		},
+		assignTransactionMerchants: func(_ context.Context, _ int, assignments []repository.MerchantAssignment) error {
+			assigned = append(assigned, assignments...)
+			return nil
+		},
+	}
+
+	result, err := testService(store).RefreshMerchants(context.Background(), 7)
+	if err != nil || result.Assigned != 2 {
+		t.Fatalf("RefreshMerchants() = %#v, %v", result, err)
+	}
+	if len(afterIDs) != 2 || afterIDs[1] != 9 {
+		t.Fatalf("pages after = %v", afterIDs)
+	}
+	want := model.MerchantMatch{Key: "lidl", Name: "Lidl"}
+	if len(assigned) != 2 || assigned[0].TransactionID != 3 || assigned[0].Merchant != want ||
+		assigned[1].TransactionID != 9 || assigned[1].Merchant != want {
+		t.Fatalf("assignments = %#v", assigned)
+	}
+}
+
+func TestMergeMerchantsRejectsSameMerchant(t *testing.T) {
+	_, err := testService(&fakeStore{}).MergeMerchants(context.Background(), 1, model.MerchantMergeRequest{KeepID: 4, MergeID: 4})
+	if apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+func TestNormalizeOpenBankingTransactionAttachesMerchant(t *testing.T) {
+	raw := json.RawMessage(`{
+		"entry_reference":"card-purchase",
+		"transaction_amount":{"currency":"EUR","amount":"23.10"},
+		"credit_debit_indicator":"DBIT",
+		"status":"BOOK",
+		"booking_date":"2026-07-12",
+		"creditor":{"name":"KAUFLAND 6120 VARNA BG"}
+	}`)
+	item, ok := normalizeOpenBankingTransaction(raw, time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC))
+	if !ok || item.Merchant != (model.MerchantMatch{Key: "kaufland", Name: "Kaufland"}) {
+		t.Fatalf("normalized transaction = %#v, included=%v", item, ok)
+	}
+}
+
+func TestMerchantKeysPreferRegistryMerchants(t *testing.T) {
+	store := &fakeStore{
+		listMerchants: func(context.Context, int) ([]model.Merchant, error) {
+			return []model.Merchant{{ID: 3, Name: "Lidl Stores", Aliases: []string{"lidl", "lidl bulgaria eood"}}}, nil
+		},
+	}
+	keys, err := testService(store).loadMerchantKeys(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("loadMerchantKeys() error = %v", err)
+	}
+	linked, unknown := 3, 99
+	tests := []struct {
+		merchantID  *int
+		description string
+		key         string
+	}{
+		{&linked, "Card payment 0042", "lidl stores"},
+		{nil, "LIDL 1234 SOFIA BG", "lidl stores"},
+		{nil, "Lidl Bulgaria EOOD 0042 Veliko Tarnovo", "lidl stores"},
+		{&unknown, "LIDL 1234 SOFIA BG", "lidl stores"},
+		{nil, "SQ *BLUE BOTTLE COFFEE", "blue bottle coffee"},
+		{nil, "2026-07-14 123456", ""},
+	}
+	for _, test := range tests {
+		if key := keys.key(test.merchantID, test.description); key != test.key {
+			t.Errorf("key(%q) = %q, want %q", test.description, key, test.key)
+		}
+	}
+}
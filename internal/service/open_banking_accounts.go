@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"money-manager-server/internal/apperrors"
@@ -103,9 +107,69 @@ func (s *Service) GetOpenBankingAccountBalances(ctx context.Context, userID, acc
 	if err != nil {
 		return nil, mapOpenBankingProviderError("get account balances", err)
 	}
+	balances := bookedOpenBankingBalances(accountID, response, s.now().UTC())
+	if len(balances) > 0 {
+		if err := s.store.RecordOpenBankingAccountBalances(ctx, userID, balances); err != nil {
+			return nil, apperrors.Internal(fmt.Errorf("record account balances: %w", err))
+		}
+	}
 	return response, nil
 }
 
+// bookedOpenBankingBalances picks the end-of-day booked balances from a
+// provider response so journal exports can assert them later. Closing booked
+// balances are always final; an interim booked balance is only final once its
+// reference date has passed. The closing balance wins when a day has both.
+func bookedOpenBankingBalances(accountID int, response json.RawMessage, now time.Time) []model.OpenBankingAccountBalance {
+	var payload struct {
+		Balances []struct {
+			BalanceAmount struct {
+				Amount   string `json:"amount"`
+				Currency string `json:"currency"`
+			} `json:"balance_amount"`
+			BalanceType   string `json:"balance_type"`
+			ReferenceDate string `json:"reference_date"`
+		} `json:"balances"`
+	}
+	if json.Unmarshal(response, &payload) != nil {
+		return nil
+	}
+	today := now.Format(time.DateOnly)
+	byDate := make(map[string]model.OpenBankingAccountBalance)
+	dates := make([]string, 0)
+	for _, balance := range payload.Balances {
+		if _, err := time.Parse(time.DateOnly, balance.ReferenceDate); err != nil || balance.ReferenceDate > today {
+			continue
+		}
+		balanceType := strings.ToUpper(balance.BalanceType)
+		if balanceType != "CLBD" && (balanceType != "ITBD" || balance.ReferenceDate >= today) {
+			continue
+		}
+		amount, ok := new(big.Rat).SetString(strings.TrimSpace(balance.BalanceAmount.Amount))
+		currency := strings.ToUpper(strings.TrimSpace(balance.BalanceAmount.Currency))
+		if !ok || len(currency) != 3 {
+			continue
+		}
+		existing, seen := byDate[balance.ReferenceDate]
+		if seen && existing.Type == "CLBD" {
+			continue
+		}
+		if !seen {
+			dates = append(dates, balance.ReferenceDate)
+		}
+		byDate[balance.ReferenceDate] = model.OpenBankingAccountBalance{
+			AccountID: accountID, ReferenceDate: balance.ReferenceDate, Type: balanceType,
+			Amount: amount.FloatString(2), Currency: currency,
+		}
+	}
+	sort.Strings(dates)
+	balances := make([]model.OpenBankingAccountBalance, 0, len(dates))
+	for _, date := range dates {
+		balances = append(balances, byDate[date])
+	}
+	return balances
+}
+
 func (s *Service) GetOpenBankingAccountTransactions(
 	ctx context.Context,
 	userID int,
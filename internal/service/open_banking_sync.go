@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"net/url"
 	"strings"
@@ -53,6 +54,14 @@ func (s *Service) SyncOpenBankingAccount(
 		return model.OpenBankingSyncResult{}, apperrors.Validation("bank sync date range cannot exceed 366 days")
 	}
 
+	rules, err := s.loadCategorizationRules(ctx, userID)
+	if err != nil {
+		return model.OpenBankingSyncResult{}, err
+	}
+	learned, err := s.loadCategoryClassifier(ctx, userID)
+	if err != nil {
+		return model.OpenBankingSyncResult{}, err
+	}
 	seeds := make([]repository.OpenBankingTransactionSeed, 0)
 	result := model.OpenBankingSyncResult{}
 	continuationKey := ""
@@ -81,8 +90,8 @@ func (s *Service) SyncOpenBankingAccount(
 			)
 		}
 		for _, raw := range page.Transactions {
-			seed, include := normalizeOpenBankingTransactionForInstitution(
-				raw, today, account.Account.InstitutionName,
+			seed, include := normalizeOpenBankingTransactionForAccount(
+				raw, today, account.Account.InstitutionName, accountID, rules, learned,
 			)
 			if !include {
 				result.Ignored++
@@ -112,6 +121,12 @@ func (s *Service) SyncOpenBankingAccount(
 	if err != nil {
 		return model.OpenBankingSyncResult{}, mapOpenBankingRepositoryNotFound(err, "bank account not found")
 	}
+	if stored.Imported > 0 || stored.Updated > 0 {
+		s.invalidateReports(ctx, userID)
+		if result.Reconciled, err = s.reconcileScheduleOccurrences(ctx, userID); err != nil {
+			return model.OpenBankingSyncResult{}, err
+		}
+	}
 	result.Imported = stored.Imported
 	result.Updated = stored.Updated
 	result.Unchanged = stored.Unchanged
@@ -154,6 +169,7 @@ func (s *Service) RunOpenBankingSyncMaintenance(ctx context.Context) (model.Open
 		result.Imported += synced.Imported
 		result.Updated += synced.Updated
 		result.Notifications += synced.Notifications
+		result.Reconciled += synced.Reconciled
 	}
 	if len(syncErrors) > 0 {
 		return result, errors.Join(syncErrors...)
@@ -205,6 +221,20 @@ func normalizeOpenBankingTransactionForInstitution(
 	raw json.RawMessage,
 	today time.Time,
 	institutionName string,
+) (repository.OpenBankingTransactionSeed, bool) {
+	return normalizeOpenBankingTransactionForAccount(raw, today, institutionName, 0, nil, nil)
+}
+
+// normalizeOpenBankingTransactionForAccount applies the user's
+// categorization rules, then the categories learned from their corrections,
+// ahead of the built-in classifier.
+func normalizeOpenBankingTransactionForAccount(
+	raw json.RawMessage,
+	today time.Time,
+	institutionName string,
+	accountID int,
+	rules categorizationRules,
+	learned *categoryClassifier,
 ) (repository.OpenBankingTransactionSeed, bool) {
 	var transaction enableBankingTransaction
 	if len(raw) == 0 || json.Unmarshal(raw, &transaction) != nil {
@@ -253,6 +283,17 @@ func normalizeOpenBankingTransactionForInstitution(
 		merchantCategoryCode,
 		openBankingTransactionClassificationText(transaction, description),
 	)
+	outcome := rules.apply(categorizationInput{
+		Type: transactionType, Description: description, Amount: amount,
+		AccountID: accountID, MerchantCategoryCode: merchantCategoryCode,
+	})
+	confidence := 0.0
+	if outcome.Category != "" {
+		classification = openBankingCategoryClassification{Category: outcome.Category, Source: categorizationRuleSource}
+	} else if category, score, ok := learned.classify(transactionType, description, amount); ok {
+		classification = openBankingCategoryClassification{Category: category, Source: learnedCategorySource}
+		confidence = score
+	}
 	// Enable Banking documents entry_reference as stable across transaction-list
 	// retrievals. transaction_id is only a detail lookup key and may change.
 	externalID := strings.TrimSpace(transaction.EntryReference)
@@ -267,7 +308,7 @@ func normalizeOpenBankingTransactionForInstitution(
 		sum := sha256.Sum256([]byte(externalID))
 		externalID = "hashed:" + hex.EncodeToString(sum[:])
 	}
-	metadata, err := json.Marshal(map[string]any{
+	fields := map[string]any{
 		"entry_reference":          truncateBytes(strings.TrimSpace(transaction.EntryReference), 500),
 		"merchant_category_code":   truncateBytes(merchantCategoryCode, 20),
 		"bank_transaction_code":    truncateBytes(strings.TrimSpace(transaction.BankTransactionCode.Code), 40),
@@ -276,7 +317,11 @@ func normalizeOpenBankingTransactionForInstitution(
 		"classified_category":      classification.Category,
 		"classified_type":          transactionType,
 		"category_source":          classification.Source,
-	})
+	}
+	if confidence > 0 {
+		fields["classification_confidence"] = math.Round(confidence*1000) / 1000
+	}
+	metadata, err := json.Marshal(fields)
 	if err != nil {
 		return repository.OpenBankingTransactionSeed{}, false
 	}
@@ -284,6 +329,8 @@ func normalizeOpenBankingTransactionForInstitution(
 		ExternalID: externalID, Type: transactionType, Category: classification.Category,
 		Description: description, Amount: amount, Currency: currency,
 		OccurredAt: occurredAt, Metadata: metadata,
+		Merchant: merchantMatch(description),
+		Tags:     outcome.Tags, ExcludedFromBudget: outcome.ExcludedFromBudget,
 	}, true
 }
 
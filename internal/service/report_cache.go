@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	reportResponseCacheTTL = 5 * time.Minute
+	// reportGenerationTTL outlives every cached report, so a generation key
+	// only expires once nothing cached under it can still be read.
+	reportGenerationTTL  = 24 * time.Hour
+	reportCacheNamespace = "money-manager:reports:v1:user:"
+)
+
+// Reports accept arbitrary ranges, so their cache keys cannot be listed for
+// invalidation. Every key embeds the user's current generation instead, and
+// a transaction write replaces the generation; older entries are never read
+// again and expire on their own.
+
+func reportGenerationCacheKey(userID int) string {
+	return reportCacheNamespace + strconv.Itoa(userID) + ":generation"
+}
+
+func reportCacheKey(userID int, generation string, parts ...string) string {
+	return reportCacheNamespace + strconv.Itoa(userID) + ":" + generation + ":" + strings.Join(parts, ":")
+}
+
+// reportGeneration must be read before the report is computed, so a write
+// racing the computation leaves the result under the superseded generation.
+func (s *Service) reportGeneration(ctx context.Context, userID int) (string, bool) {
+	if s.investmentCache == nil {
+		return "", false
+	}
+	cacheCtx, cancel := context.WithTimeout(ctx, investmentCacheOperationTimeout)
+	defer cancel()
+	contents, found, err := s.investmentCache.Get(cacheCtx, reportGenerationCacheKey(userID))
+	if err != nil {
+		slog.Debug("report cache generation read failed", "error", err)
+		return "", false
+	}
+	if !found {
+		return "0", true
+	}
+	return string(contents), true
+}
+
+func (s *Service) loadReportResponse(ctx context.Context, key string, destination any) bool {
+	cacheCtx, cancel := context.WithTimeout(ctx, investmentCacheOperationTimeout)
+	defer cancel()
+	contents, found, err := s.investmentCache.Get(cacheCtx, key)
+	if err != nil {
+		slog.Debug("report cache read failed", "error", err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := json.Unmarshal(contents, destination); err != nil {
+		slog.Warn("report cache entry is invalid", "error", err)
+		return false
+	}
+	return true
+}
+
+func (s *Service) storeReportResponse(ctx context.Context, key string, value any) {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		slog.Warn("encode report cache entry", "error", err)
+		return
+	}
+	cacheCtx, cancel := context.WithTimeout(ctx, investmentCacheOperationTimeout)
+	defer cancel()
+	if err := s.investmentCache.Set(cacheCtx, key, contents, reportResponseCacheTTL); err != nil {
+		slog.Debug("report cache write failed", "error", err)
+	}
+}
+
+// cachedReport returns a cached report or computes and caches it. Without a
+// reachable cache it simply computes the report.
+func cachedReport[T any](
+	ctx context.Context,
+	s *Service,
+	userID int,
+	parts []string,
+	compute func() (T, error),
+) (T, error) {
+	generation, ok := s.reportGeneration(ctx, userID)
+	if !ok {
+		return compute()
+	}
+	key := reportCacheKey(userID, generation, parts...)
+	var cached T
+	if s.loadReportResponse(ctx, key, &cached) {
+		return cached, nil
+	}
+	report, err := compute()
+	if err != nil {
+		return report, err
+	}
+	s.storeReportResponse(ctx, key, report)
+	return report, nil
+}
+
+// invalidateReports starts a new report generation for the user after a
+// write that changes transactions, categories or merchants.
+func (s *Service) invalidateReports(ctx context.Context, userID int) {
+	if s.investmentCache == nil {
+		return
+	}
+	cacheCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), investmentCacheOperationTimeout)
+	defer cancel()
+	if err := s.investmentCache.Set(cacheCtx, reportGenerationCacheKey(userID), []byte(rand.Text()), reportGenerationTTL); err != nil {
+		slog.Warn("report cache invalidation failed", "user_id", userID, "error", err)
+	}
+}
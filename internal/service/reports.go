@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	defaultReportMonths        = 12
+	maximumReportMonths        = 60
+	defaultMerchantReportLimit = 10
+	maximumMerchantReportLimit = 50
+)
+
+// reportRange resolves an inclusive YYYY-MM range. The previous period is
+// the same number of months just before it; the same period last year is
+// shifted back twelve months.
+func (s *Service) reportRange(request model.ReportRequest) (string, string, repository.ReportRange, error) {
+	toKey := strings.TrimSpace(request.To)
+	if toKey == "" {
+		toKey = s.now().UTC().Format("2006-01")
+	}
+	toKey, toStart, toExclusive, err := parseMonth(toKey)
+	if err != nil {
+		return "", "", repository.ReportRange{}, apperrors.Validation("to must use YYYY-MM")
+	}
+	fromKey := strings.TrimSpace(request.From)
+	if fromKey == "" {
+		fromKey = toStart.AddDate(0, 1-defaultReportMonths, 0).Format("2006-01")
+	}
+	fromKey, from, _, err := parseMonth(fromKey)
+	if err != nil {
+		return "", "", repository.ReportRange{}, apperrors.Validation("from must use YYYY-MM")
+	}
+	if from.After(toStart) {
+		return "", "", repository.ReportRange{}, apperrors.Validation("from must not be after to")
+	}
+	months := (toStart.Year()-from.Year())*12 + int(toStart.Month()-from.Month()) + 1
+	if months > maximumReportMonths {
+		return "", "", repository.ReportRange{}, apperrors.Validation(
+			fmt.Sprintf("report range must not exceed %d months", maximumReportMonths),
+		)
+	}
+	return fromKey, toKey, repository.ReportRange{
+		From:         from,
+		To:           toExclusive,
+		PreviousFrom: from.AddDate(0, -months, 0),
+		PreviousTo:   from,
+		LastYearFrom: from.AddDate(-1, 0, 0),
+		LastYearTo:   toExclusive.AddDate(-1, 0, 0),
+	}, nil
+}
+
+// CashFlowReport returns income, expenses and net per month of the range.
+func (s *Service) CashFlowReport(ctx context.Context, userID int, request model.ReportRequest) (model.CashFlowReport, error) {
+	from, to, reportRange, err := s.reportRange(request)
+	if err != nil {
+		return model.CashFlowReport{}, err
+	}
+	return cachedReport(ctx, s, userID, []string{"cash-flow", from, to}, func() (model.CashFlowReport, error) {
+		report, err := s.store.CashFlowReport(ctx, userID, reportRange)
+		if err != nil {
+			return model.CashFlowReport{}, apperrors.Internal(fmt.Errorf("build cash-flow report: %w", err))
+		}
+		report.From, report.To, report.Currency = from, to, "EUR"
+		report.PreviousPeriod.From, report.PreviousPeriod.To = comparisonMonths(reportRange.PreviousFrom, reportRange.PreviousTo)
+		report.SamePeriodLastYear.From, report.SamePeriodLastYear.To = comparisonMonths(reportRange.LastYearFrom, reportRange.LastYearTo)
+		return report, nil
+	})
+}
+
+// CategoryReport returns expenses, or income, by top-level category over
+// the range and per month.
+func (s *Service) CategoryReport(ctx context.Context, userID int, request model.ReportRequest) (model.CategoryReport, error) {
+	transactionType := "expense"
+	if strings.TrimSpace(request.Type) != "" {
+		var err error
+		if transactionType, err = normalizeTransactionType(request.Type); err != nil {
+			return model.CategoryReport{}, err
+		}
+	}
+	from, to, reportRange, err := s.reportRange(request)
+	if err != nil {
+		return model.CategoryReport{}, err
+	}
+	return cachedReport(ctx, s, userID, []string{"categories", transactionType, from, to}, func() (model.CategoryReport, error) {
+		report, err := s.store.CategoryReport(ctx, userID, transactionType, reportRange)
+		if err != nil {
+			return model.CategoryReport{}, apperrors.Internal(fmt.Errorf("build category report: %w", err))
+		}
+		report.From, report.To, report.Type, report.Currency = from, to, transactionType, "EUR"
+		return report, nil
+	})
+}
+
+// MerchantReport returns the merchants with the largest expenses.
+func (s *Service) MerchantReport(ctx context.Context, userID int, request model.ReportRequest) (model.MerchantReport, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultMerchantReportLimit
+	}
+	if limit < 0 || limit > maximumMerchantReportLimit {
+		return model.MerchantReport{}, apperrors.Validation(
+			fmt.Sprintf("limit must be between 1 and %d", maximumMerchantReportLimit),
+		)
+	}
+	from, to, reportRange, err := s.reportRange(request)
+	if err != nil {
+		return model.MerchantReport{}, err
+	}
+	parts := []string{"merchants", from, to, strconv.Itoa(limit)}
+	return cachedReport(ctx, s, userID, parts, func() (model.MerchantReport, error) {
+		report, err := s.store.MerchantReport(ctx, userID, reportRange, limit)
+		if err != nil {
+			return model.MerchantReport{}, apperrors.Internal(fmt.Errorf("build merchant report: %w", err))
+		}
+		report.From, report.To, report.Currency = from, to, "EUR"
+		return report, nil
+	})
+}
+
+func comparisonMonths(from, toExclusive time.Time) (string, string) {
+	return from.Format("2006-01"), toExclusive.AddDate(0, -1, 0).Format("2006-01")
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestReportRangeDefaultsAndComparisons(t *testing.T) {
+	var ranges []repository.ReportRange
+	service := testService(&fakeStore{cashFlowReport: func(
+		_ context.Context, _ int, reportRange repository.ReportRange,
+	) (model.CashFlowReport, error) {
+		ranges = append(ranges, reportRange)
+		return model.CashFlowReport{Months: []model.CashFlowMonth{}}, nil
+	}})
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	report, err := service.CashFlowReport(context.Background(), 7, model.ReportRequest{})
+	if err != nil || report.From != "2025-08" || report.To != "2026-07" || report.Currency != "EUR" ||
+		report.PreviousPeriod.From != "2024-08" || report.PreviousPeriod.To != "2025-07" ||
+		report.SamePeriodLastYear.From != "2024-08" || report.SamePeriodLastYear.To != "2025-07" {
+		t.Fatalf("default CashFlowReport() = %#v, %v", report, err)
+	}
+	report, err = service.CashFlowReport(context.Background(), 7, model.ReportRequest{From: "2026-04", To: "2026-06"})
+	if err != nil || report.PreviousPeriod.From != "2026-01" || report.PreviousPeriod.To != "2026-03" ||
+		report.SamePeriodLastYear.From != "2025-04" || report.SamePeriodLastYear.To != "2025-06" {
+		t.Fatalf("quarter CashFlowReport() = %#v, %v", report, err)
+	}
+	quarter := ranges[1]
+	if !quarter.From.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) || !quarter.To.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) ||
+		!quarter.PreviousTo.Equal(quarter.From) || !quarter.LastYearTo.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("quarter range = %#v", quarter)
+	}
+
+	for _, request := range []model.ReportRequest{
+		{From: "2026-07", To: "2026-06"},
+		{From: "2021-06", To: "2026-06"},
+		{From: "2026-13"},
+		{To: "June"},
+	} {
+		if _, err := service.CashFlowReport(context.Background(), 7, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("CashFlowReport(%#v) error = %v", request, err)
+		}
+	}
+	if _, err := service.CategoryReport(context.Background(), 7, model.ReportRequest{Type: "transfer"}); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("category report type error = %v", err)
+	}
+	if _, err := service.MerchantReport(context.Background(), 7, model.ReportRequest{Limit: maximumMerchantReportLimit + 1}); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("merchant report limit error = %v", err)
+	}
+}
+
+func TestReportsAreCachedUntilATransactionWrite(t *testing.T) {
+	calls := 0
+	store := &fakeStore{
+		findCategory: func(context.Context, int, string, string) (string, error) { return "groceries", nil },
+		createTransaction: func(context.Context, int, model.TransactionRequest) (model.Transaction, error) {
+			return model.Transaction{ID: 1}, nil
+		},
+		categoryReport: func(context.Context, int, string, repository.ReportRange) (model.CategoryReport, error) {
+			calls++
+			return model.CategoryReport{Total: "10.00", Categories: []model.CategoryReportItem{{
+				CategoryShare: model.CategoryShare{Category: "groceries", Amount: "10.00", SharePercent: "100.0"},
+			}}}, nil
+		},
+	}
+	cache := newFakeInvestmentResponseCache()
+	service := testService(store)
+	service.investmentCache = cache
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+	request := model.ReportRequest{From: "2026-06", To: "2026-07"}
+
+	first, err := service.CategoryReport(context.Background(), 7, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.CategoryReport(context.Background(), 7, request)
+	if err != nil || calls != 1 || second.Categories[0].Category != "groceries" || second.Type != first.Type {
+		t.Fatalf("cached report = %#v, %v (calls %d)", second, err, calls)
+	}
+	if _, err := service.CategoryReport(context.Background(), 8, request); err != nil || calls != 2 {
+		t.Fatalf("other user's report was served from cache: calls %d, %v", calls, err)
+	}
+	for key := range cache.entries {
+		if strings.HasPrefix(key, investmentCacheNamespace) {
+			t.Fatalf("report cached under the investment namespace: %s", key)
+		}
+	}
+
+	if _, err := service.CreateTransaction(context.Background(), 7, model.TransactionRequest{
+		Type: "expense", Category: "groceries", Amount: "5.00", Currency: "EUR", OccurredAt: "2026-07-10",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CategoryReport(context.Background(), 7, request); err != nil || calls != 3 {
+		t.Fatalf("report after write: calls %d, %v", calls, err)
+	}
+	if _, err := service.CategoryReport(context.Background(), 8, request); err != nil || calls != 3 {
+		t.Fatalf("unrelated user's report was invalidated: calls %d, %v", calls, err)
+	}
+}
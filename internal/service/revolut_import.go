@@ -7,29 +7,40 @@ import (
 	"encoding/csv"
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
 	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/model"
-	"money-manager-server/internal/repository"
 )
 
 const revolutImportCategoryHeader = "money manager category"
 
 func (s *Service) ImportRevolutCSV(ctx context.Context, userID int, contents []byte) (model.ImportResult, error) {
+	rows, err := parseRevolutCSV(contents)
+	if err != nil {
+		return model.ImportResult{}, err
+	}
+	prepared, err := s.prepareStatementImport(ctx, userID, rows, statementImportOptions{Source: "revolut", Strict: true})
+	if err != nil {
+		return model.ImportResult{}, err
+	}
+	result, err := s.storeStatementImport(ctx, userID, prepared)
+	return result.ImportResult, err
+}
+
+func parseRevolutCSV(contents []byte) ([]statementRow, error) {
 	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(contents, []byte{0xEF, 0xBB, 0xBF})))
 	reader.FieldsPerRecord = -1
 	records, err := reader.ReadAll()
 	if err != nil {
-		return model.ImportResult{}, apperrors.Validation("file must be a valid CSV")
+		return nil, apperrors.Validation("file must be a valid CSV")
 	}
 	if len(records) < 2 {
-		return model.ImportResult{}, apperrors.Validation("CSV must contain a header and at least one transaction")
+		return nil, apperrors.Validation("CSV must contain a header and at least one transaction")
 	}
 	if len(records)-1 > maximumImportRows {
-		return model.ImportResult{}, apperrors.Validation("CSV contains more than 5000 transactions")
+		return nil, apperrors.Validation("CSV contains more than 5000 transactions")
 	}
 
 	headers := make(map[string]int, len(records[0]))
@@ -38,24 +49,21 @@ func (s *Service) ImportRevolutCSV(ctx context.Context, userID int, contents []b
 	}
 	for _, required := range []string{"description", "amount", "currency"} {
 		if _, ok := headers[required]; !ok {
-			return model.ImportResult{}, apperrors.Validation("CSV is missing required Revolut columns")
+			return nil, apperrors.Validation("CSV is missing required Revolut columns")
 		}
 	}
 	if _, completed := headers["completed date"]; !completed {
 		if _, started := headers["started date"]; !started {
-			return model.ImportResult{}, apperrors.Validation("CSV is missing required Revolut date columns")
+			return nil, apperrors.Validation("CSV is missing required Revolut date columns")
 		}
 	}
 
-	if err := s.store.EnsureDefaultCategories(ctx, userID); err != nil {
-		return model.ImportResult{}, apperrors.Internal(fmt.Errorf("ensure default categories: %w", err))
-	}
-	imports := make([]model.ImportedTransaction, 0, len(records)-1)
-	ignored := 0
-	importCategories := make(map[string]string, 16)
+	rows := make([]statementRow, 0, len(records)-1)
 	for rowIndex, record := range records[1:] {
+		row := statementRow{Line: rowIndex + 2}
 		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
-			ignored++
+			row.Ignored = "row is blank"
+			rows = append(rows, row)
 			continue
 		}
 		field := func(name string) string {
@@ -66,83 +74,37 @@ func (s *Service) ImportRevolutCSV(ctx context.Context, userID int, contents []b
 			return strings.TrimSpace(record[index])
 		}
 		state := strings.ToUpper(field("state"))
-		if state != "" && state != "COMPLETED" {
-			ignored++
-			continue
-		}
-		if isRevolutTopUpCSVType(field("type")) {
-			ignored++
-			continue
-		}
-		currency := strings.ToUpper(field("currency"))
-		if currency != supportedCurrency {
-			ignored++
-			continue
+		switch {
+		case state != "" && state != "COMPLETED":
+			row.Ignored = "transaction is not completed"
+		case isRevolutTopUpCSVType(field("type")):
+			row.Ignored = "Revolut top-up"
 		}
+		row.Currency = field("currency")
 		rawAmount := strings.ReplaceAll(field("amount"), ",", "")
-		transactionType := "income"
+		row.Type = "income"
 		if strings.HasPrefix(rawAmount, "-") {
-			transactionType = "expense"
+			row.Type = "expense"
 			rawAmount = strings.TrimPrefix(rawAmount, "-")
 		} else {
 			rawAmount = strings.TrimPrefix(rawAmount, "+")
 		}
-		if isZeroCSVAmount(rawAmount) {
-			ignored++
-			continue
-		}
-		amount, amountErr := normalizeAmount(rawAmount)
-		if amountErr != nil {
-			return model.ImportResult{}, apperrors.Validation(fmt.Sprintf("row %d has an invalid amount", rowIndex+2))
-		}
-		date, dateErr := parseRevolutDate(firstNonEmpty(field("completed date"), field("started date")))
-		if dateErr != nil {
-			return model.ImportResult{}, apperrors.Validation(fmt.Sprintf("row %d has an invalid date", rowIndex+2))
-		}
-		description, descriptionErr := normalizeLimitedText(field("description"), "description", maximumDescriptionRunes, false)
-		if descriptionErr != nil {
-			return model.ImportResult{}, apperrors.Validation(fmt.Sprintf("row %d has an invalid description", rowIndex+2))
-		}
-		requestedCategory := strings.ToLower(field(revolutImportCategoryHeader))
-		if requestedCategory == "" {
-			requestedCategory = classifyOpenBankingTransaction(transactionType, "", description).Category
-		}
-		categoryCacheKey := transactionType + "\x00" + requestedCategory
-		category, ok := importCategories[categoryCacheKey]
-		if !ok {
-			var categoryErr error
-			category, categoryErr = s.store.FindActiveCategoryName(ctx, userID, transactionType, requestedCategory)
-			if errors.Is(categoryErr, repository.ErrNotFound) {
-				return model.ImportResult{}, apperrors.Validation(fmt.Sprintf("row %d uses an unavailable category", rowIndex+2))
-			}
-			if categoryErr != nil {
-				return model.ImportResult{}, apperrors.Internal(fmt.Errorf("find import category: %w", categoryErr))
-			}
-			importCategories[categoryCacheKey] = category
+		row.Amount = rawAmount
+		if date, err := parseRevolutDate(firstNonEmpty(field("completed date"), field("started date"))); err == nil {
+			row.Date = date
 		}
+		row.Description = field("description")
+		row.Category = field(revolutImportCategoryHeader)
 		fingerprintRecord := record
 		if categoryIndex, hasCategory := headers[revolutImportCategoryHeader]; hasCategory && categoryIndex < len(record) {
 			fingerprintRecord = make([]string, 0, len(record)-1)
 			fingerprintRecord = append(fingerprintRecord, record[:categoryIndex]...)
 			fingerprintRecord = append(fingerprintRecord, record[categoryIndex+1:]...)
 		}
-		hash := sha256.Sum256([]byte(strings.Join(fingerprintRecord, "\x1f")))
-		imports = append(imports, model.ImportedTransaction{
-			Request: model.TransactionRequest{
-				Type: transactionType, Category: category, Description: description,
-				Amount: amount, Currency: currency, OccurredAt: date.Format("2006-01-02"),
-			},
-			Fingerprint: hex.EncodeToString(hash[:]),
-		})
-	}
-	if len(imports) == 0 {
-		return model.ImportResult{Ignored: ignored}, nil
-	}
-	imported, skipped, err := s.store.ImportTransactions(ctx, userID, imports)
-	if err != nil {
-		return model.ImportResult{}, apperrors.Internal(fmt.Errorf("import transactions: %w", err))
+		row.Fingerprint = statementFingerprint(fingerprintRecord...)
+		rows = append(rows, row)
 	}
-	return model.ImportResult{Imported: imported, Skipped: skipped, Ignored: ignored}, nil
+	return rows, nil
 }
 
 func isRevolutTopUpCSVType(value string) bool {
@@ -173,6 +135,13 @@ func isZeroCSVAmount(value string) bool {
 	return true
 }
 
+// statementFingerprint hashes the raw source fields of one statement row so
+// repeated and overlapping imports of the same file stay idempotent.
+func statementFingerprint(fields ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(hash[:])
+}
+
 func normalizeCSVHeader(value string) string {
 	return strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(value, "\ufeff")), " "))
 }
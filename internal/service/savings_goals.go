@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumSavingsGoalNameRunes  = 100
+	maximumContributionNoteRunes = 200
+)
+
+func (s *Service) ListSavingsGoals(ctx context.Context, userID int, includeArchived bool) ([]model.SavingsGoal, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	items, err := s.store.ListSavingsGoals(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list savings goals: %w", err))
+	}
+	for index := range items {
+		if err := savingsGoalProgress(&items[index], today); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+	}
+	return items, nil
+}
+
+func (s *Service) GetSavingsGoal(ctx context.Context, userID, goalID int) (model.SavingsGoal, error) {
+	if err := validateID(goalID); err != nil {
+		return model.SavingsGoal{}, err
+	}
+	item, err := s.store.GetSavingsGoal(ctx, userID, goalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.SavingsGoal{}, apperrors.NotFound("savings goal not found")
+	}
+	if err != nil {
+		return model.SavingsGoal{}, apperrors.Internal(fmt.Errorf("get savings goal: %w", err))
+	}
+	return s.withSavingsGoalProgress(item)
+}
+
+func (s *Service) CreateSavingsGoal(ctx context.Context, userID int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	normalized, err := s.validateSavingsGoal(ctx, userID, request, nil)
+	if err != nil {
+		return model.SavingsGoal{}, err
+	}
+	item, err := s.store.CreateSavingsGoal(ctx, userID, normalized)
+	if err != nil {
+		return model.SavingsGoal{}, apperrors.Internal(fmt.Errorf("create savings goal: %w", err))
+	}
+	return s.withSavingsGoalProgress(item)
+}
+
+func (s *Service) UpdateSavingsGoal(ctx context.Context, userID, goalID int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	existing, err := s.GetSavingsGoal(ctx, userID, goalID)
+	if err != nil {
+		return model.SavingsGoal{}, err
+	}
+	if existing.Status != "active" {
+		return model.SavingsGoal{}, apperrors.Conflict("archived savings goals cannot be edited")
+	}
+	normalized, err := s.validateSavingsGoal(ctx, userID, request, &existing)
+	if err != nil {
+		return model.SavingsGoal{}, err
+	}
+	item, err := s.store.UpdateSavingsGoal(ctx, userID, goalID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.SavingsGoal{}, apperrors.NotFound("savings goal not found")
+	}
+	if err != nil {
+		return model.SavingsGoal{}, apperrors.Internal(fmt.Errorf("update savings goal: %w", err))
+	}
+	return s.withSavingsGoalProgress(item)
+}
+
+func (s *Service) DeleteSavingsGoal(ctx context.Context, userID, goalID int) error {
+	if err := validateID(goalID); err != nil {
+		return err
+	}
+	err := s.store.ArchiveSavingsGoal(ctx, userID, goalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("savings goal not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("archive savings goal: %w", err))
+	}
+	return nil
+}
+
+// ListSavingsGoalContributions returns manual, tagged, and account entries,
+// newest first.
+func (s *Service) ListSavingsGoalContributions(ctx context.Context, userID, goalID int) ([]model.SavingsGoalContribution, error) {
+	if _, err := s.GetSavingsGoal(ctx, userID, goalID); err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListSavingsGoalContributions(ctx, userID, goalID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list savings goal contributions: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) AddSavingsGoalContribution(
+	ctx context.Context,
+	userID, goalID int,
+	request model.SavingsGoalContributionRequest,
+) (model.SavingsGoalContribution, error) {
+	goal, err := s.GetSavingsGoal(ctx, userID, goalID)
+	if err != nil {
+		return model.SavingsGoalContribution{}, err
+	}
+	if goal.Status != "active" {
+		return model.SavingsGoalContribution{}, apperrors.Conflict("archived savings goals cannot receive contributions")
+	}
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.SavingsGoalContribution{}, apperrors.Internal(err)
+	}
+	amount, err := normalizeContributionAmount(request.Amount)
+	if err != nil {
+		return model.SavingsGoalContribution{}, err
+	}
+	date := today
+	if strings.TrimSpace(request.Date) != "" {
+		if date, err = parseDate(request.Date, "date"); err != nil {
+			return model.SavingsGoalContribution{}, err
+		}
+		if date.After(today) {
+			return model.SavingsGoalContribution{}, apperrors.Validation("date must not be in the future")
+		}
+	}
+	note, err := normalizeLimitedText(request.Note, "note", maximumContributionNoteRunes, true)
+	if err != nil {
+		return model.SavingsGoalContribution{}, err
+	}
+	item, err := s.store.CreateSavingsGoalContribution(ctx, userID, goalID, model.SavingsGoalContributionRequest{
+		Amount: amount, Date: date.Format(time.DateOnly), Note: note,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.SavingsGoalContribution{}, apperrors.NotFound("savings goal not found")
+	}
+	if err != nil {
+		return model.SavingsGoalContribution{}, apperrors.Internal(fmt.Errorf("add savings goal contribution: %w", err))
+	}
+	return item, nil
+}
+
+// DeleteSavingsGoalContribution removes a manual contribution. Automatic
+// ones are changed through their transaction's tags or account.
+func (s *Service) DeleteSavingsGoalContribution(ctx context.Context, userID, goalID, contributionID int) error {
+	if err := validateID(goalID); err != nil {
+		return err
+	}
+	if err := validateID(contributionID); err != nil {
+		return err
+	}
+	err := s.store.DeleteSavingsGoalContribution(ctx, userID, goalID, contributionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("savings goal contribution not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete savings goal contribution: %w", err))
+	}
+	return nil
+}
+
+func (s *Service) validateSavingsGoal(
+	ctx context.Context,
+	userID int,
+	request model.SavingsGoalRequest,
+	existing *model.SavingsGoal,
+) (model.SavingsGoalRequest, error) {
+	name, err := normalizeLimitedText(request.Name, "name", maximumSavingsGoalNameRunes, false)
+	if err != nil {
+		return model.SavingsGoalRequest{}, err
+	}
+	target, err := normalizeAmount(request.TargetAmount)
+	if err != nil {
+		return model.SavingsGoalRequest{}, apperrors.Validation(
+			"target_amount must be a positive decimal with at most 2 decimal places",
+		)
+	}
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	if currency == "" {
+		currency = supportedCurrency
+	}
+	if currency != supportedCurrency {
+		return model.SavingsGoalRequest{}, apperrors.Validation("currency must be EUR")
+	}
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.SavingsGoalRequest{}, apperrors.Internal(err)
+	}
+	startValue := strings.TrimSpace(request.StartDate)
+	if startValue == "" && existing != nil {
+		startValue = existing.StartDate
+	} else if startValue == "" {
+		startValue = today.Format(time.DateOnly)
+	}
+	start, err := parseDate(startValue, "start_date")
+	if err != nil {
+		return model.SavingsGoalRequest{}, err
+	}
+	targetDate, err := parseDate(request.TargetDate, "target_date")
+	if err != nil {
+		return model.SavingsGoalRequest{}, err
+	}
+	if !targetDate.After(start) {
+		return model.SavingsGoalRequest{}, apperrors.Validation("target_date must be after start_date")
+	}
+	if existing == nil && !targetDate.After(today) {
+		return model.SavingsGoalRequest{}, apperrors.Validation("target_date must be in the future")
+	}
+	if request.AccountID != nil {
+		if err := validateID(*request.AccountID); err != nil {
+			return model.SavingsGoalRequest{}, err
+		}
+		_, err := s.store.GetOpenBankingAccount(ctx, userID, *request.AccountID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.SavingsGoalRequest{}, apperrors.Validation("account_id must be a linked bank account")
+		}
+		if err != nil {
+			return model.SavingsGoalRequest{}, apperrors.Internal(fmt.Errorf("validate savings goal account: %w", err))
+		}
+	}
+	tag, err := normalizeLimitedText(request.Tag, "tag", maximumTagRunes, true)
+	if err != nil {
+		return model.SavingsGoalRequest{}, err
+	}
+	return model.SavingsGoalRequest{
+		Name: name, TargetAmount: target, Currency: currency,
+		StartDate: start.Format(time.DateOnly), TargetDate: targetDate.Format(time.DateOnly),
+		AccountID: request.AccountID, Tag: strings.ToLower(tag),
+	}, nil
+}
+
+func (s *Service) withSavingsGoalProgress(item model.SavingsGoal) (model.SavingsGoal, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return model.SavingsGoal{}, apperrors.Internal(err)
+	}
+	if err := savingsGoalProgress(&item, today); err != nil {
+		return model.SavingsGoal{}, apperrors.Internal(err)
+	}
+	return item, nil
+}
+
+// savingsGoalProgress fills in the computed fields. The expected amount
+// grows linearly from the start date to the target date; a goal is on track
+// while it has saved at least that much. The required monthly contribution
+// spreads what is left over the calendar months until the target date,
+// counting a started month as a whole one.
+func savingsGoalProgress(goal *model.SavingsGoal, today time.Time) error {
+	target, ok := new(big.Rat).SetString(goal.TargetAmount)
+	saved, savedOK := new(big.Rat).SetString(goal.SavedAmount)
+	start, startErr := time.Parse(time.DateOnly, goal.StartDate)
+	end, endErr := time.Parse(time.DateOnly, goal.TargetDate)
+	if !ok || !savedOK || startErr != nil || endErr != nil || target.Sign() <= 0 || !end.After(start) {
+		return fmt.Errorf("stored savings goal %d is invalid", goal.ID)
+	}
+	remaining := new(big.Rat).Sub(target, saved)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+	elapsed := min(max(today.Sub(start).Hours()/24, 0), end.Sub(start).Hours()/24)
+	expected := new(big.Rat).Mul(target, new(big.Rat).SetFrac64(int64(elapsed), int64(end.Sub(start).Hours()/24)))
+
+	months := (end.Year()-today.Year())*12 + int(end.Month()-today.Month())
+	if end.Day() > today.Day() {
+		months++
+	}
+	required := new(big.Rat).Set(remaining)
+	if months > 1 {
+		required.Quo(required, big.NewRat(int64(months), 1))
+	}
+
+	goal.SavedAmount = formatRat(saved, 2)
+	goal.RemainingAmount = formatRat(remaining, 2)
+	goal.ProgressPercent = formatRat(new(big.Rat).Mul(new(big.Rat).Quo(saved, target), big.NewRat(100, 1)), 1)
+	goal.ExpectedAmount = formatRat(expected, 2)
+	goal.RequiredMonthlyContribution = formatRat(required, 2)
+	switch {
+	case saved.Cmp(target) >= 0:
+		goal.ProgressStatus = "completed"
+	case today.After(end):
+		goal.ProgressStatus = "overdue"
+	case saved.Cmp(expected) >= 0:
+		goal.ProgressStatus = "on_track"
+	default:
+		goal.ProgressStatus = "behind"
+	}
+	return nil
+}
+
+// normalizeContributionAmount accepts a negative amount for a withdrawal.
+func normalizeContributionAmount(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	withdrawal := strings.HasPrefix(value, "-")
+	amount, err := normalizeAmount(strings.TrimPrefix(value, "-"))
+	if err != nil {
+		return "", apperrors.Validation("amount must be a non-zero decimal with at most 2 decimal places")
+	}
+	if withdrawal {
+		amount = "-" + amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestSavingsGoalProgress(t *testing.T) {
+	for _, test := range []struct {
+		name, saved, today                     string
+		remaining, progress, expected, monthly string
+		status                                 string
+	}{
+		{"ahead of the linear pace", "700.00", "2026-07-18", "500.00", "58.3", "652.75", "83.33", "on_track"},
+		{"behind the linear pace", "500.00", "2026-07-18", "700.00", "41.7", "652.75", "116.67", "behind"},
+		{"target reached early", "1250.00", "2026-07-18", "0.00", "104.2", "652.75", "0.00", "completed"},
+		{"target date passed", "1000.00", "2027-01-05", "200.00", "83.3", "1200.00", "200.00", "overdue"},
+		{"before the start date", "0.00", "2025-12-20", "1200.00", "0.0", "0.00", "92.31", "on_track"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			goal := model.SavingsGoal{TargetAmount: "1200.00", StartDate: "2026-01-01", TargetDate: "2026-12-31", SavedAmount: test.saved}
+			today, _ := time.Parse(time.DateOnly, test.today)
+			if err := savingsGoalProgress(&goal, today); err != nil {
+				t.Fatal(err)
+			}
+			if goal.RemainingAmount != test.remaining || goal.ProgressPercent != test.progress ||
+				goal.ExpectedAmount != test.expected || goal.RequiredMonthlyContribution != test.monthly ||
+				goal.ProgressStatus != test.status {
+				t.Fatalf("progress = %s remaining, %s%%, %s expected, %s monthly, %s",
+					goal.RemainingAmount, goal.ProgressPercent, goal.ExpectedAmount,
+					goal.RequiredMonthlyContribution, goal.ProgressStatus)
+			}
+		})
+	}
+}
+
+func TestCreateSavingsGoalNormalizesRequest(t *testing.T) {
+	var created model.SavingsGoalRequest
+	store := &fakeStore{
+		getOpenBankingAccount: func(_ context.Context, _ int, accountID int) (repository.OpenBankingAccountRecord, error) {
+			if accountID != 3 {
+				return repository.OpenBankingAccountRecord{}, repository.ErrNotFound
+			}
+			return repository.OpenBankingAccountRecord{Account: model.OpenBankingAccount{ID: 3}}, nil
+		},
+		createSavingsGoal: func(_ context.Context, _ int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+			created = request
+			return model.SavingsGoal{
+				ID: 5, Name: request.Name, TargetAmount: request.TargetAmount, Currency: request.Currency,
+				StartDate: request.StartDate, TargetDate: request.TargetDate, Status: "active", SavedAmount: "0",
+			}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+	accountID := 3
+
+	goal, err := service.CreateSavingsGoal(context.Background(), 7, model.SavingsGoalRequest{
+		Name: " Car ", TargetAmount: "6000", TargetDate: "2027-07-18", AccountID: &accountID, Tag: " Car-Fund ",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "Car" || created.TargetAmount != "6000.00" || created.Currency != "EUR" ||
+		created.StartDate != "2026-07-18" || created.Tag != "car-fund" || created.AccountID == nil || *created.AccountID != 3 {
+		t.Fatalf("created request = %#v", created)
+	}
+	if goal.RequiredMonthlyContribution != "500.00" || goal.ProgressStatus != "on_track" {
+		t.Fatalf("created goal = %#v", goal)
+	}
+
+	otherAccount := 99
+	for name, request := range map[string]model.SavingsGoalRequest{
+		"unknown account":     {Name: "Car", TargetAmount: "6000", TargetDate: "2027-07-18", AccountID: &otherAccount},
+		"zero target":         {Name: "Car", TargetAmount: "0", TargetDate: "2027-07-18"},
+		"past target date":    {Name: "Car", TargetAmount: "6000", StartDate: "2026-01-01", TargetDate: "2026-07-01"},
+		"target before start": {Name: "Car", TargetAmount: "6000", StartDate: "2027-08-01", TargetDate: "2027-07-18"},
+		"other currency":      {Name: "Car", TargetAmount: "6000", Currency: "USD", TargetDate: "2027-07-18"},
+	} {
+		if _, err := service.CreateSavingsGoal(context.Background(), 7, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+}
+
+func TestAddSavingsGoalContribution(t *testing.T) {
+	status := "active"
+	var added model.SavingsGoalContributionRequest
+	store := &fakeStore{
+		getSavingsGoal: func(_ context.Context, _ int, goalID int) (model.SavingsGoal, error) {
+			return model.SavingsGoal{
+				ID: goalID, TargetAmount: "1000.00", StartDate: "2026-01-01", TargetDate: "2026-12-31",
+				SavedAmount: "0.00", Status: status,
+			}, nil
+		},
+		createSavingsGoalContribution: func(
+			_ context.Context, _ int, _ int, request model.SavingsGoalContributionRequest,
+		) (model.SavingsGoalContribution, error) {
+			added = request
+			return model.SavingsGoalContribution{ID: 1, Source: "manual", Amount: request.Amount, Date: request.Date}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := service.AddSavingsGoalContribution(context.Background(), 7, 5, model.SavingsGoalContributionRequest{
+		Amount: "-25.5", Note: " moved back ",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if added != (model.SavingsGoalContributionRequest{Amount: "-25.50", Date: "2026-07-18", Note: "moved back"}) {
+		t.Fatalf("withdrawal = %#v", added)
+	}
+	for _, request := range []model.SavingsGoalContributionRequest{
+		{Amount: "0"}, {Amount: "--5"}, {Amount: "10", Date: "2026-07-19"}, {Amount: "10", Date: "18.07.2026"},
+	} {
+		if _, err := service.AddSavingsGoalContribution(context.Background(), 7, 5, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("AddSavingsGoalContribution(%#v) error = %v", request, err)
+		}
+	}
+	status = "archived"
+	if _, err := service.AddSavingsGoalContribution(context.Background(), 7, 5, model.SavingsGoalContributionRequest{Amount: "10"}); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("archived goal contribution error = %v", err)
+	}
+}
+
+func TestMaintenanceQueuesSavingsGoalMilestones(t *testing.T) {
+	service := testService(&fakeStore{queueSavingsGoalMilestones: func(context.Context) (int, error) { return 2, nil }})
+	result, err := service.RunScheduledTransactionMaintenance(context.Background())
+	if err != nil || result.SavingsGoalMilestones != 2 {
+		t.Fatalf("maintenance result = %#v, %v", result, err)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+// UpdateTransactionScheduleOccurrence overrides one planned occurrence
+// without touching its schedule. Later schedule edits keep the override.
+func (s *Service) UpdateTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	request model.ScheduleOccurrenceRequest,
+) (model.TransactionScheduleOccurrence, error) {
+	occurrence, schedule, err := s.plannedTransactionScheduleOccurrence(ctx, userID, occurrenceID, "edited")
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	scheduledFor, err := occurrenceOverrideDate(request.ScheduledFor, schedule.Timezone, s.now())
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	amount, err := normalizeAmount(request.Amount)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	category, err := normalizeLimitedText(request.Category, "category", maximumCategoryRunes, false)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	if !strings.EqualFold(category, occurrence.Category) {
+		category, err = s.store.FindActiveCategoryName(ctx, userID, occurrence.Type, category)
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.TransactionScheduleOccurrence{}, apperrors.Validation("category must be active and match the schedule type")
+		}
+		if err != nil {
+			return model.TransactionScheduleOccurrence{}, apperrors.Internal(fmt.Errorf("validate occurrence category: %w", err))
+		}
+	} else {
+		category = occurrence.Category
+	}
+	description, err := normalizeLimitedText(request.Description, "description", maximumDescriptionRunes, true)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	item, err := s.store.UpdateTransactionScheduleOccurrence(ctx, userID, occurrenceID, model.ScheduleOccurrenceRequest{
+		ScheduledFor: scheduledFor, Amount: amount, Category: category, Description: description,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.TransactionScheduleOccurrence{}, apperrors.Conflict("only planned occurrences can be edited")
+	}
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, apperrors.Internal(fmt.Errorf("update schedule occurrence: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) SkipTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.TransactionScheduleOccurrence, error) {
+	if _, _, err := s.plannedTransactionScheduleOccurrence(ctx, userID, occurrenceID, "skipped"); err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	return s.setTransactionScheduleOccurrenceStatus(ctx, userID, occurrenceID, "planned", "skipped")
+}
+
+func (s *Service) UnskipTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.TransactionScheduleOccurrence, error) {
+	occurrence, schedule, err := s.transactionScheduleOccurrence(ctx, userID, occurrenceID)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, err
+	}
+	if occurrence.Status != "skipped" {
+		return model.TransactionScheduleOccurrence{}, apperrors.Conflict("only skipped occurrences can be unskipped")
+	}
+	if schedule.Status == "archived" {
+		return model.TransactionScheduleOccurrence{}, apperrors.Conflict("occurrences of archived schedules cannot be unskipped")
+	}
+	return s.setTransactionScheduleOccurrenceStatus(ctx, userID, occurrenceID, "skipped", "planned")
+}
+
+func (s *Service) setTransactionScheduleOccurrenceStatus(
+	ctx context.Context,
+	userID, occurrenceID int,
+	from, to string,
+) (model.TransactionScheduleOccurrence, error) {
+	item, err := s.store.SetTransactionScheduleOccurrenceStatus(ctx, userID, occurrenceID, from, to)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.TransactionScheduleOccurrence{}, apperrors.Conflict("schedule occurrence is no longer " + from)
+	}
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, apperrors.Internal(fmt.Errorf("set schedule occurrence status: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) plannedTransactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	action string,
+) (model.TransactionScheduleOccurrence, model.TransactionSchedule, error) {
+	occurrence, schedule, err := s.transactionScheduleOccurrence(ctx, userID, occurrenceID)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{}, err
+	}
+	if occurrence.Status != "planned" {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{},
+			apperrors.Conflict("only planned occurrences can be " + action)
+	}
+	return occurrence, schedule, nil
+}
+
+func (s *Service) transactionScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.TransactionScheduleOccurrence, model.TransactionSchedule, error) {
+	if err := validateID(occurrenceID); err != nil {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{}, err
+	}
+	occurrence, err := s.store.GetTransactionScheduleOccurrence(ctx, userID, occurrenceID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{}, apperrors.NotFound("schedule occurrence not found")
+	}
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{},
+			apperrors.Internal(fmt.Errorf("get schedule occurrence: %w", err))
+	}
+	schedule, err := s.getTransactionSchedule(ctx, userID, occurrence.ScheduleID)
+	if err != nil {
+		return model.TransactionScheduleOccurrence{}, model.TransactionSchedule{}, err
+	}
+	return occurrence, schedule, nil
+}
+
+func (s *Service) ListInvestmentScheduleOccurrences(
+	ctx context.Context,
+	userID int,
+	fromString, throughString string,
+	scheduleID int,
+	status string,
+) ([]model.InvestmentScheduleOccurrence, error) {
+	filter, err := scheduleOccurrenceFilter(fromString, throughString, scheduleID, status, s.now())
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListInvestmentScheduleOccurrences(ctx, userID, filter)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list investment schedule occurrences: %w", err))
+	}
+	return items, nil
+}
+
+// UpdateInvestmentScheduleOccurrence overrides the date and amount of one
+// planned buy. Later schedule edits keep the override.
+func (s *Service) UpdateInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	request model.InvestmentScheduleOccurrenceRequest,
+) (model.InvestmentScheduleOccurrence, error) {
+	_, schedule, err := s.plannedInvestmentScheduleOccurrence(ctx, userID, occurrenceID, "edited")
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, err
+	}
+	scheduledFor, err := occurrenceOverrideDate(request.ScheduledFor, schedule.Timezone, s.now())
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, err
+	}
+	amount, err := normalizeAmount(request.Amount)
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, err
+	}
+	item, err := s.store.UpdateInvestmentScheduleOccurrence(ctx, userID, occurrenceID, model.InvestmentScheduleOccurrenceRequest{
+		ScheduledFor: scheduledFor, Amount: amount,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Conflict("only planned occurrences can be edited")
+	}
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Internal(fmt.Errorf("update investment schedule occurrence: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) SkipInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.InvestmentScheduleOccurrence, error) {
+	if _, _, err := s.plannedInvestmentScheduleOccurrence(ctx, userID, occurrenceID, "skipped"); err != nil {
+		return model.InvestmentScheduleOccurrence{}, err
+	}
+	return s.setInvestmentScheduleOccurrenceStatus(ctx, userID, occurrenceID, "planned", "skipped")
+}
+
+func (s *Service) UnskipInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.InvestmentScheduleOccurrence, error) {
+	occurrence, schedule, err := s.investmentScheduleOccurrence(ctx, userID, occurrenceID)
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, err
+	}
+	if occurrence.Status != "skipped" {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Conflict("only skipped occurrences can be unskipped")
+	}
+	if schedule.Status == "archived" {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Conflict("occurrences of archived investment schedules cannot be unskipped")
+	}
+	return s.setInvestmentScheduleOccurrenceStatus(ctx, userID, occurrenceID, "skipped", "planned")
+}
+
+func (s *Service) setInvestmentScheduleOccurrenceStatus(
+	ctx context.Context,
+	userID, occurrenceID int,
+	from, to string,
+) (model.InvestmentScheduleOccurrence, error) {
+	item, err := s.store.SetInvestmentScheduleOccurrenceStatus(ctx, userID, occurrenceID, from, to)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Conflict("investment schedule occurrence is no longer " + from)
+	}
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, apperrors.Internal(fmt.Errorf("set investment schedule occurrence status: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) plannedInvestmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+	action string,
+) (model.InvestmentScheduleOccurrence, model.InvestmentSchedule, error) {
+	occurrence, schedule, err := s.investmentScheduleOccurrence(ctx, userID, occurrenceID)
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{}, err
+	}
+	if occurrence.Status != "planned" {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.Conflict("only planned occurrences can be " + action)
+	}
+	if schedule.Status == "archived" {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.Conflict("occurrences of archived investment schedules cannot be " + action)
+	}
+	return occurrence, schedule, nil
+}
+
+func (s *Service) investmentScheduleOccurrence(
+	ctx context.Context,
+	userID, occurrenceID int,
+) (model.InvestmentScheduleOccurrence, model.InvestmentSchedule, error) {
+	if err := validateID(occurrenceID); err != nil {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{}, err
+	}
+	occurrence, err := s.store.GetInvestmentScheduleOccurrence(ctx, userID, occurrenceID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.NotFound("investment schedule occurrence not found")
+	}
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.Internal(fmt.Errorf("get investment schedule occurrence: %w", err))
+	}
+	schedule, err := s.store.GetInvestmentSchedule(ctx, userID, occurrence.ScheduleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.NotFound("investment schedule not found")
+	}
+	if err != nil {
+		return model.InvestmentScheduleOccurrence{}, model.InvestmentSchedule{},
+			apperrors.Internal(fmt.Errorf("get investment schedule: %w", err))
+	}
+	return occurrence, schedule, nil
+}
+
+// occurrenceOverrideDate validates a moved occurrence date. Occurrences can
+// only be moved to today or later in the schedule's timezone, so an override
+// never back-dates a posting.
+func occurrenceOverrideDate(value, timezone string, now time.Time) (string, error) {
+	scheduledFor, err := parseDate(value, "scheduled_for")
+	if err != nil {
+		return "", err
+	}
+	today, err := scheduleLocalDate(now, timezone)
+	if err != nil {
+		return "", apperrors.Internal(fmt.Errorf("load schedule timezone: %w", err))
+	}
+	if scheduledFor.Before(today) {
+		return "", apperrors.Validation("scheduled_for cannot be in the past")
+	}
+	return scheduledFor.Format("2006-01-02"), nil
+}
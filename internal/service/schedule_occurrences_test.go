@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func scheduleOccurrenceStore(occurrence model.TransactionScheduleOccurrence) *fakeStore {
+	store := &fakeStore{}
+	store.getScheduleOccurrence = func(_ context.Context, userID, occurrenceID int) (model.TransactionScheduleOccurrence, error) {
+		if userID != 7 || occurrenceID != occurrence.ID {
+			return model.TransactionScheduleOccurrence{}, repository.ErrNotFound
+		}
+		return occurrence, nil
+	}
+	store.getTransactionSchedule = func(context.Context, int, int, time.Time) (model.TransactionSchedule, error) {
+		return model.TransactionSchedule{ID: occurrence.ScheduleID, Timezone: defaultScheduleTimezone, Status: "active"}, nil
+	}
+	return store
+}
+
+func TestUpdateTransactionScheduleOccurrenceNormalizesOverride(t *testing.T) {
+	store := scheduleOccurrenceStore(model.TransactionScheduleOccurrence{
+		ID: 31, ScheduleID: 4, ScheduledFor: "2026-08-01", Status: "planned", Type: "expense",
+		Category: "Housing", Amount: "900.00",
+	})
+	store.findCategory = func(_ context.Context, _ int, transactionType, name string) (string, error) {
+		if transactionType != "expense" || name != "utilities" {
+			t.Fatalf("category lookup = %s/%s", transactionType, name)
+		}
+		return "Utilities", nil
+	}
+	store.updateScheduleOccurrence = func(
+		_ context.Context, _ int, occurrenceID int, request model.ScheduleOccurrenceRequest,
+	) (model.TransactionScheduleOccurrence, error) {
+		if occurrenceID != 31 || request.ScheduledFor != "2026-08-03" || request.Amount != "950.00" ||
+			request.Category != "Utilities" || request.Description != "Rent with heating" {
+			t.Fatalf("occurrence override = %d %#v", occurrenceID, request)
+		}
+		return model.TransactionScheduleOccurrence{ID: occurrenceID, Overridden: true}, nil
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, time.July, 20, 9, 0, 0, 0, time.UTC) }
+
+	item, err := service.UpdateTransactionScheduleOccurrence(context.Background(), 7, 31, model.ScheduleOccurrenceRequest{
+		ScheduledFor: "2026-08-03", Amount: "950", Category: " utilities ", Description: " Rent with heating ",
+	})
+	if err != nil || !item.Overridden {
+		t.Fatalf("UpdateTransactionScheduleOccurrence() = %#v, %v", item, err)
+	}
+
+	_, err = service.UpdateTransactionScheduleOccurrence(context.Background(), 7, 31, model.ScheduleOccurrenceRequest{
+		ScheduledFor: "2026-07-19", Amount: "950", Category: "Housing",
+	})
+	if apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("past occurrence date error = %v", err)
+	}
+}
+
+func TestScheduleOccurrenceSkipAndUnskipFollowStatus(t *testing.T) {
+	posted := scheduleOccurrenceStore(model.TransactionScheduleOccurrence{ID: 31, ScheduleID: 4, Status: "posted"})
+	if _, err := testService(posted).SkipTransactionScheduleOccurrence(context.Background(), 7, 31); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("skip posted occurrence error = %v", err)
+	}
+	if _, err := testService(posted).UnskipTransactionScheduleOccurrence(context.Background(), 7, 31); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("unskip posted occurrence error = %v", err)
+	}
+	if _, err := testService(posted).SkipTransactionScheduleOccurrence(context.Background(), 7, 99); apperrors.KindOf(err) != apperrors.KindNotFound {
+		t.Fatalf("skip missing occurrence error = %v", err)
+	}
+
+	planned := scheduleOccurrenceStore(model.TransactionScheduleOccurrence{ID: 32, ScheduleID: 4, Status: "planned"})
+	planned.setScheduleOccurrenceStatus = func(
+		_ context.Context, _ int, occurrenceID int, from, to string,
+	) (model.TransactionScheduleOccurrence, error) {
+		if occurrenceID != 32 || from != "planned" || to != "skipped" {
+			t.Fatalf("status change = %d %s->%s", occurrenceID, from, to)
+		}
+		return model.TransactionScheduleOccurrence{ID: occurrenceID, Status: to}, nil
+	}
+	item, err := testService(planned).SkipTransactionScheduleOccurrence(context.Background(), 7, 32)
+	if err != nil || item.Status != "skipped" {
+		t.Fatalf("SkipTransactionScheduleOccurrence() = %#v, %v", item, err)
+	}
+}
+
+func TestUnskipInvestmentScheduleOccurrenceRejectsArchivedSchedule(t *testing.T) {
+	store := &fakeStore{}
+	store.getInvestmentOccurrence = func(context.Context, int, int) (model.InvestmentScheduleOccurrence, error) {
+		return model.InvestmentScheduleOccurrence{ID: 51, ScheduleID: 4, Status: "skipped"}, nil
+	}
+	status := "archived"
+	store.getInvestmentSchedule = func(context.Context, int, int) (model.InvestmentSchedule, error) {
+		return model.InvestmentSchedule{ID: 4, Timezone: defaultScheduleTimezone, Status: status}, nil
+	}
+	store.setInvestmentOccurrenceStatus = func(
+		_ context.Context, _ int, occurrenceID int, from, to string,
+	) (model.InvestmentScheduleOccurrence, error) {
+		return model.InvestmentScheduleOccurrence{ID: occurrenceID, Status: to}, nil
+	}
+	service := testService(store)
+
+	if _, err := service.UnskipInvestmentScheduleOccurrence(context.Background(), 7, 51); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("unskip archived occurrence error = %v", err)
+	}
+	status = "paused"
+	item, err := service.UnskipInvestmentScheduleOccurrence(context.Background(), 7, 51)
+	if err != nil || item.Status != "planned" {
+		t.Fatalf("UnskipInvestmentScheduleOccurrence() = %#v, %v", item, err)
+	}
+}
+
+func TestForecastUsesStoredInvestmentOccurrencesBeforeMaterializedThrough(t *testing.T) {
+	dayOfMonth := 20
+	store := &fakeStore{}
+	store.listInvestmentSchedules = func(context.Context, int, string) ([]model.InvestmentSchedule, error) {
+		return []model.InvestmentSchedule{{
+			ID: 4, AssetName: "World ETF", Amount: "100.00", Currency: "EUR", Frequency: "monthly",
+			FrequencyInterval: 1, StartDate: "2026-01-20", DayOfMonth: &dayOfMonth,
+			Timezone: defaultScheduleTimezone, Status: "active", MaterializedThrough: "2026-08-31",
+		}}, nil
+	}
+	store.listInvestmentOccurrences = func(
+		_ context.Context, _ int, filter repository.ScheduleOccurrenceFilter,
+	) ([]model.InvestmentScheduleOccurrence, error) {
+		if filter.Status != "planned" {
+			t.Fatalf("investment occurrence filter = %#v", filter)
+		}
+		// July was skipped and August moved and raised.
+		return []model.InvestmentScheduleOccurrence{{
+			ID: 61, ScheduleID: 4, ScheduledFor: "2026-08-25", Status: "planned", AssetName: "World ETF",
+			Amount: "150.00", Currency: "EUR", Overridden: true,
+		}}, nil
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, time.July, 10, 9, 0, 0, 0, time.UTC) }
+
+	forecast, err := service.Forecast(context.Background(), 7, "2026-09-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	flows := make([]string, 0)
+	for _, flow := range forecast.Flows {
+		if flow.Source == "investment_schedule" {
+			flows = append(flows, flow.Date+" "+flow.Amount)
+		}
+	}
+	if len(flows) != 2 || flows[0] != "2026-08-25 -150.00" || flows[1] != "2026-09-20 -100.00" {
+		t.Fatalf("investment flows = %v", flows)
+	}
+}
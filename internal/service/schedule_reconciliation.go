@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	// Bank debits for a bill are often booked a few days early and usually
+	// land within a working week after the date.
+	scheduleMatchDaysBefore   = 3
+	scheduleMatchDaysAfter    = 5
+	scheduleMatchLookbackDays = 45
+)
+
+// scheduleMatchAmountTolerance is the share of the planned amount a bank
+// transaction may differ by, which covers small price changes and fees. An
+// estimated amount, such as a utility bill, is allowed to differ by more.
+var (
+	scheduleMatchAmountTolerance   = big.NewRat(5, 100)
+	scheduleMatchEstimateTolerance = big.NewRat(30, 100)
+)
+
+type scheduleMatch struct {
+	occurrenceID  int
+	transactionID int
+}
+
+// reconcileScheduleOccurrences links the unpaid occurrences of the user's
+// active schedules to the bank transactions that paid them. A planned
+// occurrence is marked posted without being booked by the schedule, and a
+// transaction the schedule already booked is replaced by the bank one. Each
+// occurrence's window is measured from today in its schedule's timezone. A
+// successful run is recorded, so maintenance skips the user until new bank
+// rows arrive.
+func (s *Service) reconcileScheduleOccurrences(ctx context.Context, userID int) (int, error) {
+	now := s.now()
+	reconciled, err := s.reconcileScheduleOccurrencesAt(ctx, userID, now)
+	if err != nil {
+		return reconciled, err
+	}
+	if err := s.store.MarkTransactionSchedulesReconciled(ctx, userID, now.UTC()); err != nil {
+		return reconciled, apperrors.Internal(fmt.Errorf("record schedule reconciliation: %w", err))
+	}
+	return reconciled, nil
+}
+
+func (s *Service) reconcileScheduleOccurrencesAt(ctx context.Context, userID int, now time.Time) (int, error) {
+	schedules, err := s.store.ListTransactionSchedules(ctx, userID, "active", now.UTC())
+	if err != nil {
+		return 0, apperrors.Internal(fmt.Errorf("list schedules to reconcile: %w", err))
+	}
+	todays := make(map[int]time.Time, len(schedules))
+	var earliest, latest time.Time
+	for _, schedule := range schedules {
+		today, err := scheduleLocalDate(now, schedule.Timezone)
+		if err != nil {
+			return 0, apperrors.Internal(fmt.Errorf("schedule %d timezone: %w", schedule.ID, err))
+		}
+		todays[schedule.ID] = today
+		if earliest.IsZero() || today.Before(earliest) {
+			earliest = today
+		}
+		if today.After(latest) {
+			latest = today
+		}
+	}
+	if len(todays) == 0 {
+		return 0, nil
+	}
+	listed, err := s.store.ListUnreconciledTransactionScheduleOccurrences(
+		ctx, userID, earliest.AddDate(0, 0, -scheduleMatchLookbackDays), latest.AddDate(0, 0, scheduleMatchDaysBefore),
+	)
+	if err != nil {
+		return 0, apperrors.Internal(fmt.Errorf("list unreconciled schedule occurrences: %w", err))
+	}
+	occurrences := make([]model.TransactionScheduleOccurrence, 0, len(listed))
+	for _, occurrence := range listed {
+		today, ok := todays[occurrence.ScheduleID]
+		scheduledFor, err := time.Parse(time.DateOnly, occurrence.ScheduledFor)
+		if !ok || err != nil || scheduledFor.Before(today.AddDate(0, 0, -scheduleMatchLookbackDays)) ||
+			scheduledFor.After(today.AddDate(0, 0, scheduleMatchDaysBefore)) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	if len(occurrences) == 0 {
+		return 0, nil
+	}
+	transactions, err := s.store.ListReconcilableBankTransactions(
+		ctx, userID, earliest.AddDate(0, 0, -scheduleMatchLookbackDays-scheduleMatchDaysBefore), latest,
+	)
+	if err != nil {
+		return 0, apperrors.Internal(fmt.Errorf("list reconcilable bank transactions: %w", err))
+	}
+	reconciled := 0
+	for _, match := range matchScheduleOccurrences(occurrences, transactions) {
+		err := s.store.ReconcileTransactionScheduleOccurrence(ctx, userID, match.occurrenceID, match.transactionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return reconciled, apperrors.Internal(fmt.Errorf("reconcile schedule occurrence: %w", err))
+		}
+		reconciled++
+	}
+	if reconciled > 0 {
+		s.invalidateReports(ctx, userID)
+	}
+	return reconciled, nil
+}
+
+// matchScheduleOccurrences pairs occurrences with bank transactions of the
+// same type and currency whose amount is within the tolerance or the
+// occurrence's amount range, whose date is within the match window, and whose
+// description shares a merchant word with the occurrence's description or
+// name. Closer dates win, then closer amounts, and each occurrence and
+// transaction is used at most once.
+func matchScheduleOccurrences(
+	occurrences []model.TransactionScheduleOccurrence,
+	transactions []model.Transaction,
+) []scheduleMatch {
+	type candidate struct {
+		match      scheduleMatch
+		days       int
+		difference *big.Rat
+	}
+	candidates := make([]candidate, 0)
+	for _, occurrence := range occurrences {
+		scheduledFor, err := time.Parse(time.DateOnly, occurrence.ScheduledFor)
+		planned, ok := new(big.Rat).SetString(occurrence.Amount)
+		if err != nil || !ok {
+			continue
+		}
+		minimum, maximum := scheduleMatchAmountBounds(occurrence, planned)
+		if minimum == nil {
+			continue
+		}
+		for _, transaction := range transactions {
+			if transaction.Type != occurrence.Type || transaction.Currency != occurrence.Currency {
+				continue
+			}
+			occurredAt, err := time.Parse(time.DateOnly, transaction.OccurredAt)
+			amount, ok := new(big.Rat).SetString(transaction.Amount)
+			if err != nil || !ok {
+				continue
+			}
+			days := int(occurredAt.Sub(scheduledFor).Hours() / 24)
+			if days < -scheduleMatchDaysBefore || days > scheduleMatchDaysAfter {
+				continue
+			}
+			if amount.Cmp(minimum) < 0 || amount.Cmp(maximum) > 0 {
+				continue
+			}
+			difference := new(big.Rat).Abs(amount.Sub(amount, planned))
+			if merchantSimilarity(transaction.Description, occurrence.Description) == 0 &&
+				merchantSimilarity(transaction.Description, occurrence.Name) == 0 {
+				continue
+			}
+			if days < 0 {
+				days = -days
+			}
+			candidates = append(candidates, candidate{
+				match:      scheduleMatch{occurrenceID: occurrence.ID, transactionID: transaction.ID},
+				days:       days,
+				difference: difference,
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].days != candidates[j].days {
+			return candidates[i].days < candidates[j].days
+		}
+		return candidates[i].difference.Cmp(candidates[j].difference) < 0
+	})
+	matchedOccurrences := make(map[int]bool)
+	matchedTransactions := make(map[int]bool)
+	matches := make([]scheduleMatch, 0)
+	for _, item := range candidates {
+		if matchedOccurrences[item.match.occurrenceID] || matchedTransactions[item.match.transactionID] {
+			continue
+		}
+		matchedOccurrences[item.match.occurrenceID] = true
+		matchedTransactions[item.match.transactionID] = true
+		matches = append(matches, item.match)
+	}
+	return matches
+}
+
+// scheduleMatchAmountBounds returns the lowest and highest bank amount that
+// can pay an occurrence, or nil bounds when its stored range is invalid.
+func scheduleMatchAmountBounds(
+	occurrence model.TransactionScheduleOccurrence,
+	planned *big.Rat,
+) (*big.Rat, *big.Rat) {
+	if occurrence.AmountMin != "" || occurrence.AmountMax != "" {
+		minimum, minimumOK := new(big.Rat).SetString(occurrence.AmountMin)
+		maximum, maximumOK := new(big.Rat).SetString(occurrence.AmountMax)
+		if !minimumOK || !maximumOK {
+			return nil, nil
+		}
+		return minimum, maximum
+	}
+	tolerance := scheduleMatchAmountTolerance
+	if occurrence.Estimated {
+		tolerance = scheduleMatchEstimateTolerance
+	}
+	margin := new(big.Rat).Mul(planned, tolerance)
+	return new(big.Rat).Sub(planned, margin), new(big.Rat).Add(planned, margin)
+}
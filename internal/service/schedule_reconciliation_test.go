@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestMatchScheduleOccurrencesUsesAmountDateAndMerchant(t *testing.T) {
+	occurrences := []model.TransactionScheduleOccurrence{
+		{ID: 1, ScheduledFor: "2026-10-01", Type: "expense", Name: "Rent", Description: "Landlord Ivanov", Amount: "900.00", Currency: "EUR"},
+		{ID: 2, ScheduledFor: "2026-10-05", Type: "expense", Name: "Internet", Description: "Vivacom", Amount: "30.00", Currency: "EUR"},
+		{ID: 3, ScheduledFor: "2026-10-10", Type: "expense", Name: "Gym", Description: "Pulse Fitness", Amount: "50.00", Currency: "EUR"},
+		{ID: 4, ScheduledFor: "2026-10-12", Type: "income", Name: "Salary", Description: "Acme payroll", Amount: "3000.00", Currency: "EUR"},
+	}
+	transactions := []model.Transaction{
+		// Rent shows up twice; the closer date wins.
+		{ID: 10, Type: "expense", Description: "IVANOV rent transfer", Amount: "900.00", Currency: "EUR", OccurredAt: "2026-10-04"},
+		{ID: 11, Type: "expense", Description: "Ivanov", Amount: "900.00", Currency: "EUR", OccurredAt: "2026-10-02"},
+		// The internet bill went up by a few percent.
+		{ID: 12, Type: "expense", Description: "VIVACOM EAD 4471", Amount: "31.20", Currency: "EUR", OccurredAt: "2026-10-03"},
+		// The gym charge is too far off in amount, and a matching amount is
+		// from another merchant or outside the window.
+		{ID: 13, Type: "expense", Description: "Pulse Fitness", Amount: "65.00", Currency: "EUR", OccurredAt: "2026-10-10"},
+		{ID: 14, Type: "expense", Description: "Lidl", Amount: "50.00", Currency: "EUR", OccurredAt: "2026-10-10"},
+		{ID: 15, Type: "expense", Description: "Pulse Fitness", Amount: "50.00", Currency: "EUR", OccurredAt: "2026-10-16"},
+		// Salary arrives as income, not as a refund-like expense.
+		{ID: 16, Type: "expense", Description: "Acme payroll", Amount: "3000.00", Currency: "EUR", OccurredAt: "2026-10-12"},
+		{ID: 17, Type: "income", Description: "ACME LTD PAYROLL OCT", Amount: "3000.00", Currency: "EUR", OccurredAt: "2026-10-09"},
+	}
+
+	matches := matchScheduleOccurrences(occurrences, transactions)
+	got := make(map[int]int, len(matches))
+	for _, match := range matches {
+		got[match.occurrenceID] = match.transactionID
+	}
+	want := map[int]int{1: 11, 2: 12, 4: 17}
+	if len(got) != len(want) {
+		t.Fatalf("matches = %#v", got)
+	}
+	for occurrenceID, transactionID := range want {
+		if got[occurrenceID] != transactionID {
+			t.Fatalf("matches = %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestMatchScheduleOccurrencesUsesEachTransactionOnce(t *testing.T) {
+	occurrences := []model.TransactionScheduleOccurrence{
+		{ID: 1, ScheduledFor: "2026-10-01", Type: "expense", Name: "Netflix", Amount: "12.99", Currency: "EUR"},
+		{ID: 2, ScheduledFor: "2026-10-03", Type: "expense", Name: "Netflix", Amount: "12.99", Currency: "EUR"},
+	}
+	transactions := []model.Transaction{
+		{ID: 10, Type: "expense", Description: "NETFLIX.COM", Amount: "12.99", Currency: "EUR", OccurredAt: "2026-10-03"},
+	}
+
+	matches := matchScheduleOccurrences(occurrences, transactions)
+	if len(matches) != 1 || matches[0] != (scheduleMatch{occurrenceID: 2, transactionID: 10}) {
+		t.Fatalf("matches = %#v", matches)
+	}
+}
+
+func TestMatchScheduleOccurrencesWidensEstimatedAmounts(t *testing.T) {
+	occurrences := []model.TransactionScheduleOccurrence{
+		{ID: 1, ScheduledFor: "2026-10-10", Type: "expense", Name: "Electricity", Amount: "80.00", Currency: "EUR", Estimated: true, EstimatedAmount: "80.00"},
+		{ID: 2, ScheduledFor: "2026-10-10", Type: "expense", Name: "Water", Amount: "25.00", Currency: "EUR", Estimated: true, EstimatedAmount: "25.00", AmountMin: "10.00", AmountMax: "40.00"},
+		{ID: 3, ScheduledFor: "2026-10-10", Type: "expense", Name: "Heating", Amount: "100.00", Currency: "EUR", Estimated: true, EstimatedAmount: "100.00", AmountMin: "90.00", AmountMax: "110.00"},
+	}
+	transactions := []model.Transaction{
+		{ID: 10, Type: "expense", Description: "EVN electricity", Amount: "101.50", Currency: "EUR", OccurredAt: "2026-10-11"},
+		{ID: 11, Type: "expense", Description: "Water utility", Amount: "38.00", Currency: "EUR", OccurredAt: "2026-10-11"},
+		{ID: 12, Type: "expense", Description: "Toplofikacia heating", Amount: "125.00", Currency: "EUR", OccurredAt: "2026-10-11"},
+	}
+
+	// Electricity is within 30% of its estimate and water within its range,
+	// but heating is above its range.
+	matches := matchScheduleOccurrences(occurrences, transactions)
+	got := make(map[int]int, len(matches))
+	for _, match := range matches {
+		got[match.occurrenceID] = match.transactionID
+	}
+	if len(got) != 2 || got[1] != 10 || got[2] != 11 {
+		t.Fatalf("matches = %#v", got)
+	}
+}
+
+func TestReconcileScheduleOccurrencesLinksMatches(t *testing.T) {
+	store := &fakeStore{}
+	store.listTransactionSchedules = func(_ context.Context, _ int, status string, _ time.Time) ([]model.TransactionSchedule, error) {
+		if status != "active" {
+			t.Fatalf("reconciled schedules with status %q", status)
+		}
+		return []model.TransactionSchedule{{ID: 5, Timezone: "Europe/Sofia"}, {ID: 6, Timezone: "America/New_York"}}, nil
+	}
+	store.listUnreconciledOccurrences = func(_ context.Context, userID int, from, through time.Time) ([]model.TransactionScheduleOccurrence, error) {
+		if userID != 7 || from.Format(time.DateOnly) != "2026-09-02" || through.Format(time.DateOnly) != "2026-10-21" {
+			t.Fatalf("occurrence window = %d %s %s", userID, from, through)
+		}
+		return []model.TransactionScheduleOccurrence{
+			{ID: 31, ScheduleID: 5, ScheduledFor: "2026-10-15", Status: "posted", Type: "expense", Name: "Electricity", Amount: "80.00", Currency: "EUR"},
+			{ID: 32, ScheduleID: 5, ScheduledFor: "2026-10-16", Status: "planned", Type: "expense", Name: "Water", Amount: "20.00", Currency: "EUR"},
+			// Still more than three days ahead in New York.
+			{ID: 33, ScheduleID: 6, ScheduledFor: "2026-10-21", Status: "planned", Type: "expense", Name: "Gym", Amount: "40.00", Currency: "EUR"},
+		}, nil
+	}
+	store.listReconcilableTransactions = func(_ context.Context, _ int, from, through time.Time) ([]model.Transaction, error) {
+		if from.Format(time.DateOnly) != "2026-08-30" || through.Format(time.DateOnly) != "2026-10-18" {
+			t.Fatalf("transaction window = %s %s", from, through)
+		}
+		return []model.Transaction{
+			{ID: 50, Type: "expense", Description: "EVN Electricity", Amount: "82.10", Currency: "EUR", OccurredAt: "2026-10-17", Source: "open_banking"},
+			{ID: 51, Type: "expense", Description: "Sofiyska Voda water", Amount: "20.00", Currency: "EUR", OccurredAt: "2026-10-18", Source: "open_banking"},
+			{ID: 52, Type: "expense", Description: "Pulse gym", Amount: "40.00", Currency: "EUR", OccurredAt: "2026-10-18", Source: "open_banking"},
+		}, nil
+	}
+	linked := make(map[int]int)
+	store.reconcileScheduleOccurrence = func(_ context.Context, _ int, occurrenceID, transactionID int) error {
+		if occurrenceID == 32 {
+			// Another run reconciled it first.
+			return repository.ErrNotFound
+		}
+		linked[occurrenceID] = transactionID
+		return nil
+	}
+	service := testService(store)
+	// Late evening in UTC is already the next day in Sofia.
+	service.now = func() time.Time { return time.Date(2026, time.October, 17, 22, 30, 0, 0, time.UTC) }
+
+	var marked time.Time
+	store.markSchedulesReconciled = func(_ context.Context, userID int, at time.Time) error {
+		if userID != 7 {
+			t.Fatalf("marked user %d", userID)
+		}
+		marked = at
+		return nil
+	}
+
+	reconciled, err := service.reconcileScheduleOccurrences(context.Background(), 7)
+	if err != nil || reconciled != 1 || len(linked) != 1 || linked[31] != 50 {
+		t.Fatalf("reconcileScheduleOccurrences() = %d, %v, linked %#v", reconciled, err, linked)
+	}
+	if !marked.Equal(service.now()) {
+		t.Fatalf("reconciliation recorded at %s", marked)
+	}
+}
+
+func TestRunScheduledTransactionMaintenanceReconcilesUsersWithNewBankRows(t *testing.T) {
+	store := &fakeStore{}
+	store.listReconciliationUsers = func(context.Context) ([]int, error) { return []int{8}, nil }
+	var listed, marked []int
+	store.listTransactionSchedules = func(_ context.Context, userID int, _ string, _ time.Time) ([]model.TransactionSchedule, error) {
+		listed = append(listed, userID)
+		return []model.TransactionSchedule{}, nil
+	}
+	store.markSchedulesReconciled = func(_ context.Context, userID int, _ time.Time) error {
+		marked = append(marked, userID)
+		return nil
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC) }
+
+	if _, err := service.RunScheduledTransactionMaintenance(context.Background()); err != nil {
+		t.Fatalf("RunScheduledTransactionMaintenance() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0] != 8 || len(marked) != 1 || marked[0] != 8 {
+		t.Fatalf("reconciled users = %v, marked = %v", listed, marked)
+	}
+}
@@ -5,13 +5,93 @@ import (
 	"time"
 
 	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/holidays"
+	"money-manager-server/internal/recurrence"
 )
 
+const (
+	maximumScheduleRRuleRunes = 500
+	// maximumBusinessDayShift bounds how far an adjustment moves a date. The
+	// longest run of non-business days in the bundled calendars is five days.
+	maximumBusinessDayShift = 10
+)
+
+type scheduleOccurrenceDate struct {
+	nominal   time.Time
+	scheduled time.Time
+}
+
 type normalizedScheduleRecurrence struct {
 	frequency  string
 	interval   int
 	dayOfWeek  *int
 	dayOfMonth *int
+	rrule      string
+	endDate    string
+}
+
+// normalizeScheduleRule validates an RRULE when one is given and the
+// frequency fields otherwise. The RRULE's UNTIL becomes the end date and is
+// not kept in the stored rule, so the end date stays the single source.
+func normalizeScheduleRule(
+	start time.Time,
+	endDate string,
+	rrule string,
+	frequency string,
+	interval int,
+	dayOfWeek, dayOfMonth *int,
+) (normalizedScheduleRecurrence, error) {
+	rrule = strings.TrimSpace(rrule)
+	if rrule == "" {
+		normalized, err := normalizeScheduleRecurrence(start, frequency, interval, dayOfWeek, dayOfMonth)
+		normalized.endDate = endDate
+		return normalized, err
+	}
+	if len([]rune(rrule)) > maximumScheduleRRuleRunes {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("rrule must be 500 characters or less")
+	}
+	rule, err := recurrence.ParseRRule(rrule)
+	if err != nil {
+		return normalizedScheduleRecurrence{}, apperrors.Validation(err.Error())
+	}
+	frequency = strings.ToLower(strings.TrimSpace(frequency))
+	if frequency != "" && frequency != rule.Frequency {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("frequency must match the rrule FREQ")
+	}
+	if interval != 0 && interval != rule.Interval {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("frequency_interval must match the rrule INTERVAL")
+	}
+	if rule.Interval > maximumScheduleInterval {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("frequency_interval must be between 1 and 365")
+	}
+	if dayOfWeek != nil || dayOfMonth != nil {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("rrule schedules cannot set day_of_week or day_of_month")
+	}
+	if rule.EndDate != nil {
+		until := rule.EndDate.Format("2006-01-02")
+		if endDate != "" && endDate != until {
+			return normalizedScheduleRecurrence{}, apperrors.Validation("end_date must match the rrule UNTIL")
+		}
+		if rule.EndDate.Before(start) {
+			return normalizedScheduleRecurrence{}, apperrors.Validation("end_date must be on or after start_date")
+		}
+		endDate = until
+		rule.EndDate = nil
+	}
+	rule.StartDate = start
+	dates, err := recurrence.Occurrences(rule, start, start.AddDate(5, 0, 0))
+	if err != nil {
+		return normalizedScheduleRecurrence{}, apperrors.Validation(err.Error())
+	}
+	if len(dates) == 0 || (endDate != "" && dates[0].Format("2006-01-02") > endDate) {
+		return normalizedScheduleRecurrence{}, apperrors.Validation("rrule must produce an occurrence within five years of start_date")
+	}
+	return normalizedScheduleRecurrence{
+		frequency: rule.Frequency,
+		interval:  rule.Interval,
+		rrule:     rule.RRule(),
+		endDate:   endDate,
+	}, nil
 }
 
 func normalizeScheduleRecurrence(
@@ -68,6 +148,50 @@ func normalizeScheduleRecurrence(
 	}, nil
 }
 
+func normalizeBusinessDayAdjustment(adjustment, calendar string) (string, string, error) {
+	adjustment = strings.ToLower(strings.TrimSpace(adjustment))
+	if adjustment == "" {
+		adjustment = "none"
+	}
+	if adjustment != "none" && adjustment != "previous" && adjustment != "next" && adjustment != "modified_following" {
+		return "", "", apperrors.Validation("business_day_adjustment must be none, previous, next, or modified_following")
+	}
+	calendar = strings.ToUpper(strings.TrimSpace(calendar))
+	if _, ok := holidays.Lookup(calendar); calendar != "" && !ok {
+		return "", "", apperrors.Validation("holiday_calendar must be BG or TARGET2")
+	}
+	return adjustment, calendar, nil
+}
+
+// scheduleOccurrenceDates returns the occurrences whose business-day adjusted
+// date is between from and through. Windows are matched on the adjusted date
+// so that consecutive windows neither skip nor repeat an occurrence that an
+// adjustment moved across their boundary.
+func scheduleOccurrenceDates(
+	rule recurrence.Rule,
+	adjustment, calendarCode string,
+	from, through time.Time,
+) ([]scheduleOccurrenceDate, error) {
+	calendar, _ := holidays.Lookup(calendarCode)
+	shift := 0
+	if adjustment != "" && adjustment != "none" {
+		shift = maximumBusinessDayShift
+	}
+	dates, err := recurrence.Occurrences(rule, from.AddDate(0, 0, -shift), through.AddDate(0, 0, shift))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]scheduleOccurrenceDate, 0, len(dates))
+	for _, date := range dates {
+		scheduled := calendar.Adjust(date, adjustment)
+		if scheduled.Before(from) || scheduled.After(through) {
+			continue
+		}
+		out = append(out, scheduleOccurrenceDate{nominal: date, scheduled: scheduled})
+	}
+	return out, nil
+}
+
 func scheduleLocalDate(now time.Time, timezone string) (time.Time, error) {
 	location, err := time.LoadLocation(timezone)
 	if err != nil {
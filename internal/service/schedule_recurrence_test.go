@@ -1,10 +1,12 @@
 package service
 
 import (
+	"slices"
 	"testing"
 	"time"
 
 	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/recurrence"
 )
 
 func TestNormalizeScheduleRecurrenceDefaults(t *testing.T) {
@@ -64,3 +66,114 @@ func TestNormalizeScheduleRecurrenceRejectsInvalidRules(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeScheduleRuleMovesUntilToEndDate(t *testing.T) {
+	start := time.Date(2026, time.July, 12, 0, 0, 0, 0, time.UTC)
+
+	normalized, err := normalizeScheduleRule(
+		start, "", " freq=monthly;byday=mo,tu,we,th,fr;bysetpos=-1;until=20271231 ", "", 0, nil, nil,
+	)
+	if err != nil {
+		t.Fatalf("normalize rrule: %v", err)
+	}
+	if normalized.frequency != "monthly" || normalized.interval != 1 || normalized.endDate != "2027-12-31" ||
+		normalized.rrule != "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" {
+		t.Fatalf("unexpected rrule recurrence: %+v", normalized)
+	}
+
+	legacy, err := normalizeScheduleRule(start, "2026-12-31", "", "daily", 2, nil, nil)
+	if err != nil || legacy.rrule != "" || legacy.endDate != "2026-12-31" || legacy.interval != 2 {
+		t.Fatalf("unexpected legacy recurrence: %+v, %v", legacy, err)
+	}
+}
+
+func TestNormalizeScheduleRuleRejectsInvalidRules(t *testing.T) {
+	start := time.Date(2026, time.July, 12, 0, 0, 0, 0, time.UTC)
+	value := 1
+
+	tests := []struct {
+		name       string
+		endDate    string
+		rrule      string
+		frequency  string
+		interval   int
+		dayOfMonth *int
+	}{
+		{name: "syntax", rrule: "FREQ=MONTHLY;BYDAY=XX"},
+		{name: "frequency", rrule: "FREQ=YEARLY", frequency: "monthly"},
+		{name: "interval", rrule: "FREQ=MONTHLY;INTERVAL=3", interval: 2},
+		{name: "interval range", rrule: "FREQ=DAILY;INTERVAL=366"},
+		{name: "day of month", rrule: "FREQ=MONTHLY;BYMONTHDAY=1", dayOfMonth: &value},
+		{name: "until", endDate: "2026-12-31", rrule: "FREQ=MONTHLY;UNTIL=20270131"},
+		{name: "until before start", rrule: "FREQ=MONTHLY;UNTIL=20260601"},
+		{name: "never", rrule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := normalizeScheduleRule(
+				start, test.endDate, test.rrule, test.frequency, test.interval, nil, test.dayOfMonth,
+			)
+			if apperrors.KindOf(err) != apperrors.KindValidation {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScheduleOccurrenceDatesAdjustToBusinessDays(t *testing.T) {
+	day := func(value string) time.Time {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	format := func(dates []scheduleOccurrenceDate) []string {
+		out := make([]string, len(dates))
+		for index, date := range dates {
+			out[index] = date.nominal.Format(time.DateOnly) + ">" + date.scheduled.Format(time.DateOnly)
+		}
+		return out
+	}
+
+	// 6 September 2026 is a Sunday and the Monday after it is its substitute holiday.
+	salary := recurrence.Rule{Frequency: "monthly", Interval: 1, StartDate: day("2026-09-01"), DayOfMonth: 6}
+	dates, err := scheduleOccurrenceDates(salary, "next", "BG", day("2026-09-01"), day("2026-11-30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-09-06>2026-09-08", "2026-10-06>2026-10-06", "2026-11-06>2026-11-06"}
+	if !slices.Equal(format(dates), want) {
+		t.Fatalf("adjusted dates = %v, want %v", format(dates), want)
+	}
+
+	// 1 May is a TARGET2 holiday, so its debit moves into the April window.
+	debit := recurrence.Rule{Frequency: "monthly", Interval: 1, StartDate: day("2026-04-01"), DayOfMonth: 1}
+	april, err := scheduleOccurrenceDates(debit, "previous", "TARGET2", day("2026-04-01"), day("2026-04-30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	may, err := scheduleOccurrenceDates(debit, "previous", "TARGET2", day("2026-05-01"), day("2026-05-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-04-01>2026-04-01", "2026-05-01>2026-04-30"}; !slices.Equal(format(april), want) || len(may) != 0 {
+		t.Fatalf("April = %v, May = %v", format(april), format(may))
+	}
+}
+
+func TestNormalizeBusinessDayAdjustment(t *testing.T) {
+	adjustment, calendar, err := normalizeBusinessDayAdjustment(" Modified_Following ", " bg ")
+	if err != nil || adjustment != "modified_following" || calendar != "BG" {
+		t.Fatalf("normalized = %q, %q, %v", adjustment, calendar, err)
+	}
+	if adjustment, calendar, err := normalizeBusinessDayAdjustment("", ""); err != nil || adjustment != "none" || calendar != "" {
+		t.Fatalf("defaults = %q, %q, %v", adjustment, calendar, err)
+	}
+	for _, values := range [][2]string{{"following", ""}, {"next", "US"}} {
+		if _, _, err := normalizeBusinessDayAdjustment(values[0], values[1]); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%v error = %v", values, err)
+		}
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"money-manager-server/internal/apperrors"
 	"money-manager-server/internal/marketdata"
 	"money-manager-server/internal/model"
-	"money-manager-server/internal/recurrence"
 	"money-manager-server/internal/repository"
 )
 
@@ -29,47 +28,68 @@ func (s *Service) materializeDueInvestmentSchedules(ctx context.Context, now tim
 		if err != nil {
 			return materialized, fmt.Errorf("load investment schedule timezone: %w", err)
 		}
-		from, err := parseDate(schedule.StartDate, "start_date")
-		if err != nil {
-			return materialized, fmt.Errorf("parse stored investment schedule start date: %w", err)
-		}
-		if schedule.MaterializedThrough != "" {
-			through, err := parseDate(schedule.MaterializedThrough, "materialized_through")
-			if err != nil {
-				return materialized, fmt.Errorf("parse stored investment materialization date: %w", err)
-			}
-			from = through.AddDate(0, 0, 1)
-		}
-		if from.After(today) {
+		count, err := s.materializeInvestmentSchedule(ctx, schedule, today)
+		if errors.Is(err, repository.ErrNotFound) {
 			continue
 		}
-		rule, err := investmentRecurrenceRule(schedule)
 		if err != nil {
-			return materialized, fmt.Errorf("build investment recurrence: %w", err)
+			return materialized, err
 		}
-		dates, err := recurrence.Occurrences(rule, from, today)
-		if err != nil {
-			return materialized, fmt.Errorf("generate investment occurrences: %w", err)
-		}
-		seeds := make([]repository.InvestmentScheduleOccurrenceSeed, 0, len(dates))
-		for _, date := range dates {
-			seeds = append(seeds, repository.InvestmentScheduleOccurrenceSeed{
-				ScheduleID: schedule.ID, UserID: schedule.UserID, ScheduledFor: date,
-			})
-		}
-		count, err := s.store.UpsertInvestmentScheduleOccurrences(ctx, seeds)
+		materialized += count
+	}
+	return materialized, nil
+}
+
+// materializeInvestmentSchedule stores the planned buys through the schedule
+// horizon so single occurrences can be edited or skipped ahead of time. Buys
+// missed since the last run are caught up and posted by the next maintenance
+// run.
+func (s *Service) materializeInvestmentSchedule(
+	ctx context.Context,
+	schedule model.InvestmentSchedule,
+	today time.Time,
+) (int, error) {
+	from, err := parseDate(schedule.StartDate, "start_date")
+	if err != nil {
+		return 0, fmt.Errorf("parse stored investment schedule start date: %w", err)
+	}
+	if schedule.MaterializedThrough != "" {
+		materializedThrough, err := parseDate(schedule.MaterializedThrough, "materialized_through")
 		if err != nil {
-			return materialized, fmt.Errorf("store investment schedule occurrences: %w", err)
+			return 0, fmt.Errorf("parse stored investment materialization date: %w", err)
 		}
-		if err := s.store.MarkInvestmentScheduleMaterializedThrough(ctx, schedule.ID, today); err != nil {
-			if errors.Is(err, repository.ErrNotFound) {
-				continue
-			}
-			return materialized, fmt.Errorf("mark investment schedule materialized: %w", err)
+		from = materializedThrough.AddDate(0, 0, 1)
+	}
+	through := today.AddDate(0, 0, s.scheduleHorizonDays)
+	if from.After(through) {
+		return 0, nil
+	}
+	rule, err := investmentRecurrenceRule(schedule)
+	if err != nil {
+		return 0, fmt.Errorf("build investment recurrence: %w", err)
+	}
+	dates, err := scheduleOccurrenceDates(rule, schedule.BusinessDayAdjustment, schedule.HolidayCalendar, from, through)
+	if err != nil {
+		return 0, fmt.Errorf("generate investment occurrences: %w", err)
+	}
+	seeds := make([]repository.InvestmentScheduleOccurrenceSeed, 0, len(dates))
+	for _, date := range dates {
+		seeds = append(seeds, repository.InvestmentScheduleOccurrenceSeed{
+			ScheduleID: schedule.ID, UserID: schedule.UserID, ScheduledFor: date.scheduled, NominalDate: date.nominal,
+			Amount: schedule.Amount,
+		})
+	}
+	count, err := s.store.UpsertInvestmentScheduleOccurrences(ctx, seeds)
+	if err != nil {
+		return 0, fmt.Errorf("store investment schedule occurrences: %w", err)
+	}
+	if err := s.store.MarkInvestmentScheduleMaterializedThrough(ctx, schedule.ID, through); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return count, repository.ErrNotFound
 		}
-		materialized += count
+		return 0, fmt.Errorf("mark investment schedule materialized: %w", err)
 	}
-	return materialized, nil
+	return count, nil
 }
 
 func (s *Service) postDueInvestmentScheduleOccurrences(ctx context.Context, now time.Time) (int, error) {
@@ -123,7 +143,7 @@ func (s *Service) prepareScheduledInvestmentTrade(
 	request, err := s.validateInvestmentTrade(model.InvestmentTradeRequest{
 		AssetType: schedule.AssetType, Symbol: schedule.Symbol, AssetName: schedule.AssetName,
 		Exchange: schedule.Exchange, MarketCurrency: schedule.MarketCurrency, Broker: schedule.Broker,
-		Side: "buy", Amount: schedule.Amount, Fees: "0", Currency: schedule.Currency,
+		Side: "buy", Amount: occurrence.Amount, Fees: "0", Currency: schedule.Currency,
 		OccurredAt: occurredAt.Format(time.RFC3339), Notes: "Scheduled investment",
 	})
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 	_ "time/tzdata"
@@ -15,11 +16,13 @@ import (
 )
 
 const (
-	defaultScheduleTimezone    = "Europe/Sofia"
-	maximumScheduleNameRunes   = 100
-	maximumScheduleInterval    = 365
-	maximumOccurrenceRangeDays = 366
-	schedulePostingBatchSize   = 500
+	defaultScheduleTimezone      = "Europe/Sofia"
+	maximumScheduleNameRunes     = 100
+	maximumScheduleInterval      = 365
+	maximumOccurrenceRangeDays   = 366
+	schedulePostingBatchSize     = 500
+	defaultScheduleAverageWindow = 3
+	maximumScheduleAverageWindow = 12
 )
 
 func (s *Service) CreateTransactionSchedule(
@@ -187,47 +190,60 @@ func (s *Service) ListTransactionScheduleOccurrences(
 	scheduleID int,
 	status string,
 ) ([]model.TransactionScheduleOccurrence, error) {
-	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	filter, err := scheduleOccurrenceFilter(fromString, throughString, scheduleID, status, s.now())
 	if err != nil {
-		return nil, apperrors.Internal(err)
+		return nil, err
+	}
+	items, err := s.store.ListTransactionScheduleOccurrences(ctx, userID, filter)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list transaction schedule occurrences: %w", err))
+	}
+	return items, nil
+}
+
+// scheduleOccurrenceFilter validates the query of an occurrence listing. It
+// defaults to planned occurrences over the next 30 days.
+func scheduleOccurrenceFilter(
+	fromString, throughString string,
+	scheduleID int,
+	status string,
+	now time.Time,
+) (repository.ScheduleOccurrenceFilter, error) {
+	today, err := scheduleLocalDate(now, defaultScheduleTimezone)
+	if err != nil {
+		return repository.ScheduleOccurrenceFilter{}, apperrors.Internal(err)
 	}
 	from := today
 	if strings.TrimSpace(fromString) != "" {
 		from, err = parseDate(fromString, "from")
 		if err != nil {
-			return nil, err
+			return repository.ScheduleOccurrenceFilter{}, err
 		}
 	}
 	through := from.AddDate(0, 0, 30)
 	if strings.TrimSpace(throughString) != "" {
 		through, err = parseDate(throughString, "through")
 		if err != nil {
-			return nil, err
+			return repository.ScheduleOccurrenceFilter{}, err
 		}
 	}
 	if through.Before(from) {
-		return nil, apperrors.Validation("through must be on or after from")
+		return repository.ScheduleOccurrenceFilter{}, apperrors.Validation("through must be on or after from")
 	}
 	if int(through.Sub(from).Hours()/24)+1 > maximumOccurrenceRangeDays {
-		return nil, apperrors.Validation("occurrence date range must be 366 days or less")
+		return repository.ScheduleOccurrenceFilter{}, apperrors.Validation("occurrence date range must be 366 days or less")
 	}
 	if scheduleID < 0 {
-		return nil, apperrors.Validation("schedule_id must be a positive integer")
+		return repository.ScheduleOccurrenceFilter{}, apperrors.Validation("schedule_id must be a positive integer")
 	}
 	status = strings.ToLower(strings.TrimSpace(status))
 	if status == "" {
 		status = "planned"
 	}
 	if status != "planned" && status != "posted" && status != "skipped" {
-		return nil, apperrors.Validation("status must be planned, posted, or skipped")
+		return repository.ScheduleOccurrenceFilter{}, apperrors.Validation("status must be planned, posted, or skipped")
 	}
-	items, err := s.store.ListTransactionScheduleOccurrences(ctx, userID, repository.ScheduleOccurrenceFilter{
-		From: from, Through: through, ScheduleID: scheduleID, Status: status,
-	})
-	if err != nil {
-		return nil, apperrors.Internal(fmt.Errorf("list transaction schedule occurrences: %w", err))
-	}
-	return items, nil
+	return repository.ScheduleOccurrenceFilter{From: from, Through: through, ScheduleID: scheduleID, Status: status}, nil
 }
 
 func (s *Service) RunScheduledTransactionMaintenance(ctx context.Context) (model.ScheduleMaintenanceResult, error) {
@@ -251,21 +267,54 @@ func (s *Service) RunScheduledTransactionMaintenance(ctx context.Context) (model
 		}
 		result.Materialized += count
 	}
-	posted, err := s.store.PostDueTransactionScheduleOccurrences(ctx, now, schedulePostingBatchSize)
+	// Reconcile before posting so a bill the bank already paid is not
+	// booked a second time by its schedule. Syncs and imports reconcile as
+	// they store bank rows; this catches the users whose last attempt failed.
+	reconcileUsers, err := s.store.ListTransactionScheduleReconciliationUsers(ctx)
+	if err != nil {
+		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("list users to reconcile: %w", err))
+	}
+	for _, userID := range reconcileUsers {
+		count, err := s.reconcileScheduleOccurrences(ctx, userID)
+		if err != nil {
+			return model.ScheduleMaintenanceResult{}, err
+		}
+		result.Reconciled += count
+	}
+	postedUsers, err := s.store.PostDueTransactionScheduleOccurrences(ctx, now, schedulePostingBatchSize)
 	if err != nil {
 		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("post due transaction schedule occurrences: %w", err))
 	}
-	result.Posted = posted
+	result.Posted = len(postedUsers)
+	invalidated := make(map[int]bool, len(postedUsers))
+	for _, userID := range postedUsers {
+		if !invalidated[userID] {
+			invalidated[userID] = true
+			s.invalidateReports(ctx, userID)
+		}
+	}
 	scheduleReminders, err := s.store.QueueDueTransactionScheduleReminders(ctx, now, schedulePostingBatchSize)
 	if err != nil {
 		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("queue scheduled money reminders: %w", err))
 	}
 	result.ScheduleReminders = scheduleReminders
+	missedPayments, err := s.store.QueueMissedTransactionScheduleOccurrences(
+		ctx, now, scheduleMatchDaysAfter, scheduleMatchLookbackDays, schedulePostingBatchSize,
+	)
+	if err != nil {
+		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("queue missed scheduled payments: %w", err))
+	}
+	result.MissedPayments = missedPayments
 	budgetAlerts, err := s.queueBudgetAlerts(ctx, now)
 	if err != nil {
 		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("queue budget alerts: %w", err))
 	}
 	result.BudgetAlerts = budgetAlerts
+	savingsGoalMilestones, err := s.store.QueueSavingsGoalMilestones(ctx)
+	if err != nil {
+		return model.ScheduleMaintenanceResult{}, apperrors.Internal(fmt.Errorf("queue savings goal milestones: %w", err))
+	}
+	result.SavingsGoalMilestones = savingsGoalMilestones
 	investmentMaterialized, err := s.materializeDueInvestmentSchedules(ctx, now)
 	if err != nil {
 		return result, apperrors.Internal(fmt.Errorf("materialize investment schedules: %w", err))
@@ -306,17 +355,22 @@ func (s *Service) materializeTransactionSchedule(
 	if err != nil {
 		return 0, apperrors.Internal(fmt.Errorf("build recurrence rule: %w", err))
 	}
-	dates, err := recurrence.Occurrences(rule, from, through)
+	dates, err := scheduleOccurrenceDates(rule, schedule.BusinessDayAdjustment, schedule.HolidayCalendar, from, through)
 	if err != nil {
 		return 0, apperrors.Internal(fmt.Errorf("generate transaction schedule occurrences: %w", err))
 	}
+	estimate := ""
+	if schedule.AmountMode != "" && schedule.AmountMode != "fixed" {
+		estimate = schedule.Amount
+	}
 	seeds := make([]repository.ScheduleOccurrenceSeed, 0, len(dates))
 	for _, date := range dates {
 		seeds = append(seeds, repository.ScheduleOccurrenceSeed{
-			ScheduleID: schedule.ID, UserID: schedule.UserID, ScheduledFor: date,
+			ScheduleID: schedule.ID, UserID: schedule.UserID, ScheduledFor: date.scheduled, NominalDate: date.nominal,
 			Type: schedule.Type, Name: schedule.Name, Category: schedule.Category,
 			Description: schedule.Description, Amount: schedule.Amount, Currency: schedule.Currency,
-			AutoPost: schedule.AutoPost,
+			AutoPost: schedule.AutoPost, EstimatedAmount: estimate,
+			AmountMin: schedule.AmountMin, AmountMax: schedule.AmountMax,
 		})
 	}
 	inserted, err := s.store.UpsertTransactionScheduleOccurrences(ctx, seeds)
@@ -345,6 +399,14 @@ func recurrenceRule(schedule model.TransactionSchedule) (recurrence.Rule, error)
 		}
 		end = &value
 	}
+	if schedule.RRule != "" {
+		rule, err := recurrence.ParseRRule(schedule.RRule)
+		if err != nil {
+			return recurrence.Rule{}, err
+		}
+		rule.StartDate, rule.EndDate = start, end
+		return rule, nil
+	}
 	rule := recurrence.Rule{
 		Frequency: schedule.Frequency, Interval: schedule.FrequencyInterval,
 		StartDate: start, EndDate: end,
@@ -392,7 +454,7 @@ func (s *Service) validateTransactionSchedule(
 	if err != nil {
 		return model.TransactionScheduleRequest{}, time.Time{}, err
 	}
-	amount, err := normalizeAmount(request.Amount)
+	amount, err := normalizeScheduleAmount(request)
 	if err != nil {
 		return model.TransactionScheduleRequest{}, time.Time{}, err
 	}
@@ -429,8 +491,10 @@ func (s *Service) validateTransactionSchedule(
 		}
 		endDate = end.Format("2006-01-02")
 	}
-	recurrence, err := normalizeScheduleRecurrence(
+	recurrence, err := normalizeScheduleRule(
 		start,
+		endDate,
+		request.RRule,
 		request.Frequency,
 		request.FrequencyInterval,
 		request.DayOfWeek,
@@ -439,10 +503,99 @@ func (s *Service) validateTransactionSchedule(
 	if err != nil {
 		return model.TransactionScheduleRequest{}, time.Time{}, err
 	}
+	adjustment, calendar, err := normalizeBusinessDayAdjustment(request.BusinessDayAdjustment, request.HolidayCalendar)
+	if err != nil {
+		return model.TransactionScheduleRequest{}, time.Time{}, err
+	}
 	return model.TransactionScheduleRequest{
 		Type: transactionType, Name: name, Category: canonicalCategory, Description: description,
-		Amount: amount, Currency: currency, Frequency: recurrence.frequency, FrequencyInterval: recurrence.interval,
-		StartDate: start.Format("2006-01-02"), EndDate: endDate, DayOfWeek: recurrence.dayOfWeek,
-		DayOfMonth: recurrence.dayOfMonth, Timezone: timezone, AutoPost: request.AutoPost,
+		Amount: amount.amount, AmountMode: amount.mode, AmountMin: amount.minimum, AmountMax: amount.maximum,
+		AverageWindow: amount.averageWindow, Currency: currency,
+		Frequency: recurrence.frequency, FrequencyInterval: recurrence.interval,
+		StartDate: start.Format("2006-01-02"), EndDate: recurrence.endDate, DayOfWeek: recurrence.dayOfWeek,
+		DayOfMonth: recurrence.dayOfMonth, RRule: recurrence.rrule, BusinessDayAdjustment: adjustment,
+		HolidayCalendar: calendar, Timezone: timezone, AutoPost: request.AutoPost,
 	}, today, nil
 }
+
+type scheduleAmount struct {
+	mode             string
+	amount           string
+	minimum, maximum string
+	averageWindow    *int
+}
+
+// normalizeScheduleAmount validates how a schedule plans its amount. A fixed
+// schedule posts amount as it is; estimate and rolling_average treat it as
+// the estimate, the latter re-estimated from the last average_window
+// reconciled payments; range needs amount_min and amount_max and estimates
+// their midpoint unless amount is given.
+func normalizeScheduleAmount(request model.TransactionScheduleRequest) (scheduleAmount, error) {
+	mode := strings.ToLower(strings.TrimSpace(request.AmountMode))
+	if mode == "" {
+		mode = "fixed"
+	}
+	if mode != "fixed" && mode != "estimate" && mode != "rolling_average" && mode != "range" {
+		return scheduleAmount{}, apperrors.Validation("amount_mode must be fixed, estimate, rolling_average, or range")
+	}
+	result := scheduleAmount{mode: mode}
+	hasRange := strings.TrimSpace(request.AmountMin) != "" || strings.TrimSpace(request.AmountMax) != ""
+	if mode != "range" && hasRange {
+		return scheduleAmount{}, apperrors.Validation("amount_min and amount_max require amount_mode range")
+	}
+	if mode != "rolling_average" && request.AverageWindow != nil {
+		return scheduleAmount{}, apperrors.Validation("average_window requires amount_mode rolling_average")
+	}
+	if mode == "rolling_average" {
+		window := defaultScheduleAverageWindow
+		if request.AverageWindow != nil {
+			window = *request.AverageWindow
+		}
+		if window < 1 || window > maximumScheduleAverageWindow {
+			return scheduleAmount{}, apperrors.Validation(
+				fmt.Sprintf("average_window must be between 1 and %d", maximumScheduleAverageWindow),
+			)
+		}
+		result.averageWindow = &window
+	}
+	if mode != "range" {
+		amount, err := normalizeAmount(request.Amount)
+		if err != nil {
+			return scheduleAmount{}, err
+		}
+		result.amount = amount
+		return result, nil
+	}
+
+	bounds := make([]*big.Rat, 2)
+	for index, field := range []struct{ name, value string }{
+		{"amount_min", request.AmountMin}, {"amount_max", request.AmountMax},
+	} {
+		value, err := normalizeAmount(field.value)
+		if err != nil {
+			return scheduleAmount{}, apperrors.Validation(
+				field.name + " must be a positive decimal with at most 2 decimal places",
+			)
+		}
+		bounds[index], _ = new(big.Rat).SetString(value)
+	}
+	if bounds[0].Cmp(bounds[1]) > 0 {
+		return scheduleAmount{}, apperrors.Validation("amount_min must not be greater than amount_max")
+	}
+	result.minimum, result.maximum = formatRat(bounds[0], 2), formatRat(bounds[1], 2)
+	if strings.TrimSpace(request.Amount) == "" {
+		midpoint := new(big.Rat).Add(bounds[0], bounds[1])
+		result.amount = formatRat(midpoint.Quo(midpoint, big.NewRat(2, 1)), 2)
+		return result, nil
+	}
+	amount, err := normalizeAmount(request.Amount)
+	if err != nil {
+		return scheduleAmount{}, err
+	}
+	estimate, _ := new(big.Rat).SetString(amount)
+	if estimate.Cmp(bounds[0]) < 0 || estimate.Cmp(bounds[1]) > 0 {
+		return scheduleAmount{}, apperrors.Validation("amount must be between amount_min and amount_max")
+	}
+	result.amount = amount
+	return result, nil
+}
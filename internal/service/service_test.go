@@ -226,21 +226,6 @@ func TestUpdateTransactionValidatesChangedCategory(t *testing.T) {
 	}
 }
 
-func TestExportTransactionsIsBounded(t *testing.T) {
-	service := testService(&fakeStore{})
-	if _, err := service.ExportTransactions(context.Background(), 1, "2025-01-01", "2026-01-02"); apperrors.KindOf(err) != apperrors.KindValidation {
-		t.Fatalf("oversized range error = %v", err)
-	}
-
-	store := &fakeStore{exportTransactions: func(context.Context, int, time.Time, time.Time, int) ([]model.Transaction, error) {
-		return make([]model.Transaction, maximumExportRows+1), nil
-	}}
-	service = testService(store)
-	if _, err := service.ExportTransactions(context.Background(), 1, "2026-01-01", "2026-01-31"); apperrors.KindOf(err) != apperrors.KindValidation {
-		t.Fatalf("oversized row count error = %v", err)
-	}
-}
-
 func TestImportRevolutCSVNormalizesAndIgnoresUnsupportedRows(t *testing.T) {
 	store := &fakeStore{
 		findCategory: func(_ context.Context, _ int, transactionType, name string) (string, error) {
@@ -437,6 +422,101 @@ func TestCreateTransactionScheduleRejectsInvalidRecurrence(t *testing.T) {
 	}
 }
 
+func TestNormalizeScheduleAmountModes(t *testing.T) {
+	window := 6
+	tests := []struct {
+		request model.TransactionScheduleRequest
+		want    scheduleAmount
+	}{
+		{model.TransactionScheduleRequest{Amount: "12"}, scheduleAmount{mode: "fixed", amount: "12.00"}},
+		{model.TransactionScheduleRequest{AmountMode: " Estimate ", Amount: "80"}, scheduleAmount{mode: "estimate", amount: "80.00"}},
+		{model.TransactionScheduleRequest{AmountMode: "rolling_average", Amount: "75.5"}, scheduleAmount{mode: "rolling_average", amount: "75.50"}},
+		{
+			model.TransactionScheduleRequest{AmountMode: "rolling_average", Amount: "75", AverageWindow: &window},
+			scheduleAmount{mode: "rolling_average", amount: "75.00"},
+		},
+		{
+			model.TransactionScheduleRequest{AmountMode: "range", AmountMin: "60", AmountMax: "95.25"},
+			scheduleAmount{mode: "range", amount: "77.63", minimum: "60.00", maximum: "95.25"},
+		},
+		{
+			model.TransactionScheduleRequest{AmountMode: "range", Amount: "70", AmountMin: "60", AmountMax: "95"},
+			scheduleAmount{mode: "range", amount: "70.00", minimum: "60.00", maximum: "95.00"},
+		},
+	}
+	for _, test := range tests {
+		got, err := normalizeScheduleAmount(test.request)
+		if err != nil || got.mode != test.want.mode || got.amount != test.want.amount ||
+			got.minimum != test.want.minimum || got.maximum != test.want.maximum {
+			t.Errorf("normalizeScheduleAmount(%#v) = %#v, %v, want %#v", test.request, got, err, test.want)
+		}
+		if (got.averageWindow != nil) != (test.want.mode == "rolling_average") {
+			t.Errorf("normalizeScheduleAmount(%#v) average window = %v", test.request, got.averageWindow)
+		}
+	}
+	if got, _ := normalizeScheduleAmount(tests[2].request); *got.averageWindow != defaultScheduleAverageWindow {
+		t.Errorf("default average window = %d", *got.averageWindow)
+	}
+
+	zero, tooLarge := 0, maximumScheduleAverageWindow+1
+	invalid := []model.TransactionScheduleRequest{
+		{AmountMode: "variable", Amount: "10"},
+		{AmountMode: "estimate"},
+		{Amount: "10", AmountMin: "5", AmountMax: "15"},
+		{AmountMode: "estimate", Amount: "10", AverageWindow: &window},
+		{AmountMode: "rolling_average", Amount: "10", AverageWindow: &zero},
+		{AmountMode: "rolling_average", Amount: "10", AverageWindow: &tooLarge},
+		{AmountMode: "range", AmountMin: "60"},
+		{AmountMode: "range", AmountMin: "95", AmountMax: "60"},
+		{AmountMode: "range", Amount: "100", AmountMin: "60", AmountMax: "95"},
+	}
+	for _, request := range invalid {
+		if _, err := normalizeScheduleAmount(request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("normalizeScheduleAmount(%#v) error = %v", request, err)
+		}
+	}
+}
+
+func TestMaterializeTransactionScheduleCarriesEstimate(t *testing.T) {
+	var seeds []repository.ScheduleOccurrenceSeed
+	store := &fakeStore{
+		upsertScheduleOccurrences: func(_ context.Context, value []repository.ScheduleOccurrenceSeed) (int, error) {
+			seeds = append(seeds, value...)
+			return len(value), nil
+		},
+		markScheduleMaterialized: func(context.Context, int, time.Time) error { return nil },
+	}
+	service := testService(store)
+	today := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	dayOfMonth := 20
+	schedule := model.TransactionSchedule{
+		ID: 5, UserID: 7, Type: "expense", Name: "Electricity", Amount: "77.63", AmountMode: "range",
+		AmountMin: "60.00", AmountMax: "95.25", Currency: "EUR", Frequency: "monthly", FrequencyInterval: 1,
+		StartDate: "2026-10-20", DayOfMonth: &dayOfMonth, Status: "active",
+	}
+	if _, err := service.materializeTransactionSchedule(context.Background(), schedule, today); err != nil {
+		t.Fatalf("materializeTransactionSchedule() error = %v", err)
+	}
+	if len(seeds) == 0 {
+		t.Fatal("no occurrences materialized")
+	}
+	for _, seed := range seeds {
+		if seed.Amount != "77.63" || seed.EstimatedAmount != "77.63" || seed.AmountMin != "60.00" || seed.AmountMax != "95.25" {
+			t.Fatalf("estimated seed = %#v", seed)
+		}
+	}
+
+	seeds = nil
+	schedule.AmountMode, schedule.AmountMin, schedule.AmountMax = "fixed", "", ""
+	schedule.MaterializedThrough = ""
+	if _, err := service.materializeTransactionSchedule(context.Background(), schedule, today); err != nil {
+		t.Fatalf("materializeTransactionSchedule() error = %v", err)
+	}
+	if len(seeds) == 0 || seeds[0].EstimatedAmount != "" {
+		t.Fatalf("fixed seeds = %#v", seeds)
+	}
+}
+
 func TestListTransactionScheduleOccurrencesDefaultsToPlanned(t *testing.T) {
 	store := &fakeStore{
 		listScheduleOccurrences: func(_ context.Context, userID int, filter repository.ScheduleOccurrenceFilter) ([]model.TransactionScheduleOccurrence, error) {
@@ -1080,19 +1160,20 @@ func TestScheduledInvestmentMaintenanceBackfillsCryptoBuy(t *testing.T) {
 		return []model.InvestmentSchedule{schedule}, nil
 	}
 	store.upsertInvestmentOccurrences = func(_ context.Context, seeds []repository.InvestmentScheduleOccurrenceSeed) (int, error) {
-		if len(seeds) != 1 || seeds[0].ScheduleID != schedule.ID || !seeds[0].ScheduledFor.Equal(dueDate) {
+		if len(seeds) != 14 || seeds[0].ScheduleID != schedule.ID || !seeds[0].ScheduledFor.Equal(dueDate) ||
+			seeds[0].Amount != "50.00" || seeds[13].ScheduledFor.Format("2006-01-02") != "2026-10-19" {
 			t.Fatalf("materialized seeds = %#v", seeds)
 		}
 		return 1, nil
 	}
 	store.markInvestmentMaterialized = func(_ context.Context, scheduleID int, through time.Time) error {
-		if scheduleID != schedule.ID || through.Format("2006-01-02") != "2026-07-22" {
+		if scheduleID != schedule.ID || through.Format("2006-01-02") != "2026-10-20" {
 			t.Fatalf("materialized through = %d/%s", scheduleID, through)
 		}
 		return nil
 	}
 	store.listDueInvestmentOccurrences = func(context.Context, time.Time, int) ([]repository.DueInvestmentScheduleOccurrence, error) {
-		return []repository.DueInvestmentScheduleOccurrence{{ID: 41, ScheduledFor: dueDate, Schedule: schedule}}, nil
+		return []repository.DueInvestmentScheduleOccurrence{{ID: 41, ScheduledFor: dueDate, Amount: "50.00", Schedule: schedule}}, nil
 	}
 	store.postInvestmentOccurrence = func(_ context.Context, occurrenceID int, request model.InvestmentTradeRequest) (model.InvestmentTrade, bool, error) {
 		if occurrenceID != 41 || request.Side != "buy" || request.Amount != "50.00" ||
@@ -1133,7 +1214,7 @@ func TestScheduledStockBuyUsesLatestAvailableClose(t *testing.T) {
 	}
 	store := &fakeStore{}
 	store.listDueInvestmentOccurrences = func(context.Context, time.Time, int) ([]repository.DueInvestmentScheduleOccurrence, error) {
-		return []repository.DueInvestmentScheduleOccurrence{{ID: 42, ScheduledFor: dueDate, Schedule: schedule}}, nil
+		return []repository.DueInvestmentScheduleOccurrence{{ID: 42, ScheduledFor: dueDate, Amount: "49.92", Schedule: schedule}}, nil
 	}
 	store.postInvestmentOccurrence = func(_ context.Context, occurrenceID int, request model.InvestmentTradeRequest) (model.InvestmentTrade, bool, error) {
 		if occurrenceID != 42 || request.Symbol != "MSTR" || request.Exchange != "NASDAQ" ||
@@ -1247,44 +1328,106 @@ func testService(store Store) *Service {
 }
 
 type fakeStore struct {
-	registerUser                    func(context.Context, string, string) (model.User, error)
-	findCategory                    func(context.Context, int, string, string) (string, error)
-	createTransaction               func(context.Context, int, model.TransactionRequest) (model.Transaction, error)
-	getTransaction                  func(context.Context, int, int) (model.Transaction, error)
-	updateTransaction               func(context.Context, int, int, model.TransactionRequest) (model.Transaction, error)
-	exportTransactions              func(context.Context, int, time.Time, time.Time, int) ([]model.Transaction, error)
-	importTransactions              func(context.Context, int, []model.ImportedTransaction) (int, int, error)
-	createTransactionSchedule       func(context.Context, int, model.TransactionScheduleRequest) (model.TransactionSchedule, error)
-	getTransactionSchedule          func(context.Context, int, int, time.Time) (model.TransactionSchedule, error)
-	upsertScheduleOccurrences       func(context.Context, []repository.ScheduleOccurrenceSeed) (int, error)
-	markScheduleMaterialized        func(context.Context, int, time.Time) error
-	listScheduleOccurrences         func(context.Context, int, repository.ScheduleOccurrenceFilter) ([]model.TransactionScheduleOccurrence, error)
-	createOpenBankingAuthorization  func(context.Context, repository.NewOpenBankingAuthorization) (int, error)
-	setOpenBankingProviderID        func(context.Context, int, string) error
-	claimOpenBankingAuthorization   func(context.Context, string, time.Time) (repository.OpenBankingAuthorizationRecord, error)
-	failOpenBankingAuthorization    func(context.Context, int, string, string) error
-	storeOpenBankingConnection      func(context.Context, repository.NewOpenBankingConnection) (int, error)
-	getOpenBankingConnection        func(context.Context, int, int) (repository.OpenBankingConnectionRecord, error)
-	getOpenBankingAccount           func(context.Context, int, int) (repository.OpenBankingAccountRecord, error)
-	listOpenBankingProviderSessions func(context.Context, int) ([]string, error)
-	importOpenBankingTransactions   func(context.Context, int, int, []repository.OpenBankingTransactionSeed, time.Time) (model.OpenBankingSyncResult, error)
-	claimOpenBankingAccountsForSync func(context.Context, time.Time, time.Time, time.Time, int) ([]repository.OpenBankingSyncAccount, error)
-	releaseOpenBankingSyncClaim     func(context.Context, int) error
-	claimNotificationDeliveries     func(context.Context, time.Time, time.Time, time.Time, []string, int) ([]repository.NotificationDelivery, error)
-	completeNotificationDelivery    func(context.Context, int, bool, bool, bool, string, time.Time, time.Time) error
-	deleteUser                      func(context.Context, int) error
-	createInvestmentTrade           func(context.Context, int, model.InvestmentTradeRequest) (model.InvestmentTrade, error)
-	getInvestmentSchedule           func(context.Context, int, int) (model.InvestmentSchedule, error)
-	listInvestmentTrades            func(context.Context, int, repository.InvestmentTradeFilter) ([]model.InvestmentTrade, error)
-	deleteInvestmentTrade           func(context.Context, int, int) error
-	investmentHoldingQuantity       func(context.Context, int, string, string, string) (string, error)
-	listInvestmentMarketHistory     func(context.Context, string, string, string, string, time.Time) ([]model.InvestmentMarketHistoryPrice, error)
-	upsertInvestmentMarketHistory   func(context.Context, []model.InvestmentMarketHistoryPrice) error
-	listActiveInvestmentSchedules   func(context.Context) ([]model.InvestmentSchedule, error)
-	upsertInvestmentOccurrences     func(context.Context, []repository.InvestmentScheduleOccurrenceSeed) (int, error)
-	markInvestmentMaterialized      func(context.Context, int, time.Time) error
-	listDueInvestmentOccurrences    func(context.Context, time.Time, int) ([]repository.DueInvestmentScheduleOccurrence, error)
-	postInvestmentOccurrence        func(context.Context, int, model.InvestmentTradeRequest) (model.InvestmentTrade, bool, error)
+	registerUser                     func(context.Context, string, string) (model.User, error)
+	findCategory                     func(context.Context, int, string, string) (string, error)
+	listCategories                   func(context.Context, int, string) ([]model.Category, error)
+	getCategory                      func(context.Context, int, int) (model.Category, error)
+	updateCategory                   func(context.Context, int, int, model.CategoryUpdateRequest) (model.Category, error)
+	mergeCategories                  func(context.Context, int, int, int) (model.CategoryMergeResult, error)
+	cashFlowReport                   func(context.Context, int, repository.ReportRange) (model.CashFlowReport, error)
+	categoryReport                   func(context.Context, int, string, repository.ReportRange) (model.CategoryReport, error)
+	merchantReport                   func(context.Context, int, repository.ReportRange, int) (model.MerchantReport, error)
+	budgetHitRate                    func(context.Context, int, time.Time, time.Time) (model.AnnualBudgets, error)
+	forecastSettings                 *model.ForecastSettings
+	getSavingsGoal                   func(context.Context, int, int) (model.SavingsGoal, error)
+	createSavingsGoal                func(context.Context, int, model.SavingsGoalRequest) (model.SavingsGoal, error)
+	createSavingsGoalContribution    func(context.Context, int, int, model.SavingsGoalContributionRequest) (model.SavingsGoalContribution, error)
+	queueSavingsGoalMilestones       func(context.Context) (int, error)
+	getLoan                          func(context.Context, int, int) (model.Loan, error)
+	listLoanPayments                 func(context.Context, int, int) ([]model.LoanPayment, error)
+	createLoanPayment                func(context.Context, int, int, model.LoanPaymentRequest) (model.LoanPayment, error)
+	expenseGroup                     *model.ExpenseGroup
+	sharedExpenses                   []model.SharedExpense
+	expenseSettlements               []model.ExpenseSettlement
+	createdSharedExpense             *model.SharedExpense
+	createdExpenseSettlement         *model.ExpenseSettlementRequest
+	listTransactionSchedules         func(context.Context, int, string, time.Time) ([]model.TransactionSchedule, error)
+	listInvestmentSchedules          func(context.Context, int, string) ([]model.InvestmentSchedule, error)
+	createTransaction                func(context.Context, int, model.TransactionRequest) (model.Transaction, error)
+	getTransaction                   func(context.Context, int, int) (model.Transaction, error)
+	updateTransaction                func(context.Context, int, int, model.TransactionRequest) (model.Transaction, error)
+	streamTransactions               func(context.Context, int, time.Time, time.Time, func(model.Transaction) error) error
+	streamTransactionsWithOrigin     func(context.Context, int, time.Time, time.Time, func(model.Transaction, string) error) error
+	listTransactionAccountUses       func(context.Context, int, time.Time, time.Time) ([]repository.TransactionAccountUse, error)
+	listOpenBankingAccounts          func(context.Context, int) ([]model.OpenBankingAccount, error)
+	recordOpenBankingAccountBalances func(context.Context, int, []model.OpenBankingAccountBalance) error
+	listOpenBankingAccountBalances   func(context.Context, int, time.Time, time.Time) ([]model.OpenBankingAccountBalance, error)
+	createTransactionExport          func(context.Context, int, model.TransactionExportRequest, time.Time, int) (model.TransactionExport, error)
+	getTransactionExport             func(context.Context, int, int) (model.TransactionExport, error)
+	claimTransactionExports          func(context.Context, time.Time, time.Time, int, int) ([]repository.TransactionExportJob, error)
+	releaseTransactionExport         func(context.Context, int) error
+	appendTransactionExportChunk     func(context.Context, int, int, []byte, time.Time) error
+	completeTransactionExport        func(context.Context, int, int, int64, time.Time) error
+	failTransactionExport            func(context.Context, int, string, bool) error
+	importTransactions               func(context.Context, int, []model.ImportedTransaction) (int, int, error)
+	getCSVImportProfile              func(context.Context, int, int) (model.CSVImportProfile, error)
+	createImportPreview              func(context.Context, int, string, []repository.ImportPreviewRowRecord, time.Time) (repository.ImportPreviewRecord, error)
+	getImportPreview                 func(context.Context, int, int) (repository.ImportPreviewRecord, error)
+	deleteExpiredImportPreviews      func(context.Context, time.Time) (int, error)
+	existingImportFingerprints       func(context.Context, int, string, []string) ([]string, error)
+	listDuplicateCandidates          func(context.Context, int, time.Time, time.Time, int) ([]model.DuplicateTransactionPair, error)
+	mergeTransactions                func(context.Context, int, int, int) (model.TransactionMergeResult, error)
+	listMerchantCandidates           func(context.Context, int, int, int) ([]repository.MerchantCandidate, error)
+	listMerchants                    func(context.Context, int) ([]model.Merchant, error)
+	assignTransactionMerchants       func(context.Context, int, []repository.MerchantAssignment) error
+	listActiveCategorizationRules    func(context.Context, int) ([]model.CategorizationRule, error)
+	createCategorizationRule         func(context.Context, int, model.CategorizationRuleRequest) (model.CategorizationRule, error)
+	listCategorizationRuleTargets    func(context.Context, int, time.Time, time.Time) ([]repository.CategorizationRuleTarget, error)
+	applyCategorizationRuleUpdates   func(context.Context, int, []repository.CategorizationRuleUpdate) (int, error)
+	listCategoryTrainingExamples     func(context.Context, int, int) ([]repository.CategoryTrainingExample, error)
+	bulkTransactions                 func(context.Context, int, repository.BulkTransactionOperation) ([]model.BulkTransactionItemResult, error)
+	createTransactionSchedule        func(context.Context, int, model.TransactionScheduleRequest) (model.TransactionSchedule, error)
+	getTransactionSchedule           func(context.Context, int, int, time.Time) (model.TransactionSchedule, error)
+	upsertScheduleOccurrences        func(context.Context, []repository.ScheduleOccurrenceSeed) (int, error)
+	markScheduleMaterialized         func(context.Context, int, time.Time) error
+	listScheduleOccurrences          func(context.Context, int, repository.ScheduleOccurrenceFilter) ([]model.TransactionScheduleOccurrence, error)
+	createOpenBankingAuthorization   func(context.Context, repository.NewOpenBankingAuthorization) (int, error)
+	setOpenBankingProviderID         func(context.Context, int, string) error
+	claimOpenBankingAuthorization    func(context.Context, string, time.Time) (repository.OpenBankingAuthorizationRecord, error)
+	failOpenBankingAuthorization     func(context.Context, int, string, string) error
+	storeOpenBankingConnection       func(context.Context, repository.NewOpenBankingConnection) (int, error)
+	getOpenBankingConnection         func(context.Context, int, int) (repository.OpenBankingConnectionRecord, error)
+	getOpenBankingAccount            func(context.Context, int, int) (repository.OpenBankingAccountRecord, error)
+	listOpenBankingProviderSessions  func(context.Context, int) ([]string, error)
+	importOpenBankingTransactions    func(context.Context, int, int, []repository.OpenBankingTransactionSeed, time.Time) (model.OpenBankingSyncResult, error)
+	claimOpenBankingAccountsForSync  func(context.Context, time.Time, time.Time, time.Time, int) ([]repository.OpenBankingSyncAccount, error)
+	releaseOpenBankingSyncClaim      func(context.Context, int) error
+	claimNotificationDeliveries      func(context.Context, time.Time, time.Time, time.Time, []string, int) ([]repository.NotificationDelivery, error)
+	completeNotificationDelivery     func(context.Context, int, bool, bool, bool, string, time.Time, time.Time) error
+	deleteUser                       func(context.Context, int) error
+	createInvestmentTrade            func(context.Context, int, model.InvestmentTradeRequest) (model.InvestmentTrade, error)
+	getInvestmentSchedule            func(context.Context, int, int) (model.InvestmentSchedule, error)
+	listInvestmentTrades             func(context.Context, int, repository.InvestmentTradeFilter) ([]model.InvestmentTrade, error)
+	deleteInvestmentTrade            func(context.Context, int, int) error
+	investmentHoldingQuantity        func(context.Context, int, string, string, string) (string, error)
+	listInvestmentMarketHistory      func(context.Context, string, string, string, string, time.Time) ([]model.InvestmentMarketHistoryPrice, error)
+	upsertInvestmentMarketHistory    func(context.Context, []model.InvestmentMarketHistoryPrice) error
+	listActiveInvestmentSchedules    func(context.Context) ([]model.InvestmentSchedule, error)
+	upsertInvestmentOccurrences      func(context.Context, []repository.InvestmentScheduleOccurrenceSeed) (int, error)
+	markInvestmentMaterialized       func(context.Context, int, time.Time) error
+	listDueInvestmentOccurrences     func(context.Context, time.Time, int) ([]repository.DueInvestmentScheduleOccurrence, error)
+	postInvestmentOccurrence         func(context.Context, int, model.InvestmentTradeRequest) (model.InvestmentTrade, bool, error)
+	getScheduleOccurrence            func(context.Context, int, int) (model.TransactionScheduleOccurrence, error)
+	updateScheduleOccurrence         func(context.Context, int, int, model.ScheduleOccurrenceRequest) (model.TransactionScheduleOccurrence, error)
+	setScheduleOccurrenceStatus      func(context.Context, int, int, string, string) (model.TransactionScheduleOccurrence, error)
+	listInvestmentOccurrences        func(context.Context, int, repository.ScheduleOccurrenceFilter) ([]model.InvestmentScheduleOccurrence, error)
+	getInvestmentOccurrence          func(context.Context, int, int) (model.InvestmentScheduleOccurrence, error)
+	setInvestmentOccurrenceStatus    func(context.Context, int, int, string, string) (model.InvestmentScheduleOccurrence, error)
+	listUnreconciledOccurrences      func(context.Context, int, time.Time, time.Time) ([]model.TransactionScheduleOccurrence, error)
+	listReconcilableTransactions     func(context.Context, int, time.Time, time.Time) ([]model.Transaction, error)
+	reconcileScheduleOccurrence      func(context.Context, int, int, int) error
+	listReconciliationUsers          func(context.Context) ([]int, error)
+	markSchedulesReconciled          func(context.Context, int, time.Time) error
 }
 
 func (f *fakeStore) ImportTransactions(ctx context.Context, userID int, transactions []model.ImportedTransaction) (int, int, error) {
@@ -1315,12 +1458,37 @@ func (f *fakeStore) DeleteUser(ctx context.Context, userID int) error {
 	return repository.ErrNotFound
 }
 func (*fakeStore) EnsureDefaultCategories(context.Context, int) error { return nil }
-func (*fakeStore) ListCategories(context.Context, int, string) ([]model.Category, error) {
+func (f *fakeStore) ListCategories(ctx context.Context, userID int, transactionType string) ([]model.Category, error) {
+	if f.listCategories != nil {
+		return f.listCategories(ctx, userID, transactionType)
+	}
 	return []model.Category{}, nil
 }
+func (f *fakeStore) GetCategory(ctx context.Context, userID, categoryID int) (model.Category, error) {
+	if f.getCategory != nil {
+		return f.getCategory(ctx, userID, categoryID)
+	}
+	return model.Category{}, repository.ErrNotFound
+}
 func (*fakeStore) CreateCategory(context.Context, int, model.CategoryRequest) (model.Category, error) {
 	return model.Category{}, nil
 }
+func (f *fakeStore) UpdateCategory(
+	ctx context.Context,
+	userID, categoryID int,
+	request model.CategoryUpdateRequest,
+) (model.Category, error) {
+	if f.updateCategory != nil {
+		return f.updateCategory(ctx, userID, categoryID, request)
+	}
+	return model.Category{}, nil
+}
+func (f *fakeStore) MergeCategories(ctx context.Context, userID, sourceID, targetID int) (model.CategoryMergeResult, error) {
+	if f.mergeCategories != nil {
+		return f.mergeCategories(ctx, userID, sourceID, targetID)
+	}
+	return model.CategoryMergeResult{}, nil
+}
 func (*fakeStore) DeleteCategory(context.Context, int, int) error { return nil }
 func (f *fakeStore) FindActiveCategoryName(ctx context.Context, userID int, transactionType, name string) (string, error) {
 	if f.findCategory != nil {
@@ -1332,11 +1500,85 @@ func (*fakeStore) ListTransactions(context.Context, int, repository.TransactionF
 	return []model.Transaction{}, nil
 }
 
-func (f *fakeStore) ExportTransactions(ctx context.Context, userID int, from, to time.Time, limit int) ([]model.Transaction, error) {
-	if f.exportTransactions != nil {
-		return f.exportTransactions(ctx, userID, from, to, limit)
+func (f *fakeStore) StreamTransactions(ctx context.Context, userID int, from, to time.Time, visit func(model.Transaction) error) error {
+	if f.streamTransactions != nil {
+		return f.streamTransactions(ctx, userID, from, to, visit)
 	}
-	return []model.Transaction{}, nil
+	return nil
+}
+func (f *fakeStore) StreamTransactionsWithOrigin(ctx context.Context, userID int, from, to time.Time, visit func(model.Transaction, string) error) error {
+	if f.streamTransactionsWithOrigin != nil {
+		return f.streamTransactionsWithOrigin(ctx, userID, from, to, visit)
+	}
+	return nil
+}
+func (f *fakeStore) ListTransactionAccountUses(ctx context.Context, userID int, from, to time.Time) ([]repository.TransactionAccountUse, error) {
+	if f.listTransactionAccountUses != nil {
+		return f.listTransactionAccountUses(ctx, userID, from, to)
+	}
+	return []repository.TransactionAccountUse{}, nil
+}
+func (f *fakeStore) CreateTransactionExport(ctx context.Context, userID int, request model.TransactionExportRequest, expiresAt time.Time, maximumActive int) (model.TransactionExport, error) {
+	if f.createTransactionExport != nil {
+		return f.createTransactionExport(ctx, userID, request, expiresAt, maximumActive)
+	}
+	return model.TransactionExport{}, errors.New("unexpected CreateTransactionExport call")
+}
+func (*fakeStore) ListTransactionExports(context.Context, int) ([]model.TransactionExport, error) {
+	return []model.TransactionExport{}, nil
+}
+func (f *fakeStore) GetTransactionExport(ctx context.Context, userID, exportID int) (model.TransactionExport, error) {
+	if f.getTransactionExport != nil {
+		return f.getTransactionExport(ctx, userID, exportID)
+	}
+	return model.TransactionExport{}, repository.ErrNotFound
+}
+func (*fakeStore) DeleteTransactionExport(context.Context, int, int) error { return nil }
+func (*fakeStore) StreamTransactionExportFile(context.Context, int, func([]byte) error) error {
+	return nil
+}
+func (f *fakeStore) ClaimTransactionExports(
+	ctx context.Context,
+	now, claimUntil time.Time,
+	limit, maximumAttempts int,
+) ([]repository.TransactionExportJob, error) {
+	if f.claimTransactionExports != nil {
+		return f.claimTransactionExports(ctx, now, claimUntil, limit, maximumAttempts)
+	}
+	return []repository.TransactionExportJob{}, nil
+}
+func (f *fakeStore) AppendTransactionExportChunk(ctx context.Context, exportID, sequence int, data []byte, claimUntil time.Time) error {
+	if f.appendTransactionExportChunk != nil {
+		return f.appendTransactionExportChunk(ctx, exportID, sequence, data, claimUntil)
+	}
+	return errors.New("unexpected AppendTransactionExportChunk call")
+}
+func (f *fakeStore) CompleteTransactionExport(ctx context.Context, exportID, rows int, size int64, expiresAt time.Time) error {
+	if f.completeTransactionExport != nil {
+		return f.completeTransactionExport(ctx, exportID, rows, size, expiresAt)
+	}
+	return errors.New("unexpected CompleteTransactionExport call")
+}
+func (f *fakeStore) ReleaseTransactionExport(ctx context.Context, exportID int) error {
+	if f.releaseTransactionExport != nil {
+		return f.releaseTransactionExport(ctx, exportID)
+	}
+	return errors.New("unexpected ReleaseTransactionExport call")
+}
+func (f *fakeStore) FailTransactionExport(ctx context.Context, exportID int, message string, final bool) error {
+	if f.failTransactionExport != nil {
+		return f.failTransactionExport(ctx, exportID, message, final)
+	}
+	return errors.New("unexpected FailTransactionExport call")
+}
+func (*fakeStore) DeleteExpiredTransactionExports(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+func (f *fakeStore) DeleteExpiredImportPreviews(ctx context.Context, now time.Time) (int, error) {
+	if f.deleteExpiredImportPreviews != nil {
+		return f.deleteExpiredImportPreviews(ctx, now)
+	}
+	return 0, nil
 }
 func (f *fakeStore) CreateTransaction(ctx context.Context, userID int, request model.TransactionRequest) (model.Transaction, error) {
 	if f.createTransaction != nil {
@@ -1357,16 +1599,163 @@ func (f *fakeStore) UpdateTransaction(ctx context.Context, userID, transactionID
 	return model.Transaction{}, nil
 }
 func (*fakeStore) DeleteTransaction(context.Context, int, int) error { return nil }
+func (f *fakeStore) BulkTransactions(ctx context.Context, userID int, operation repository.BulkTransactionOperation) ([]model.BulkTransactionItemResult, error) {
+	if f.bulkTransactions != nil {
+		return f.bulkTransactions(ctx, userID, operation)
+	}
+	return nil, errors.New("unexpected BulkTransactions call")
+}
 func (*fakeStore) Summary(context.Context, int, string, time.Time, time.Time) (model.Summary, error) {
 	return model.Summary{}, nil
 }
+func (*fakeStore) ListCSVImportProfiles(context.Context, int) ([]model.CSVImportProfile, error) {
+	return []model.CSVImportProfile{}, nil
+}
+func (f *fakeStore) GetCSVImportProfile(ctx context.Context, userID, profileID int) (model.CSVImportProfile, error) {
+	if f.getCSVImportProfile != nil {
+		return f.getCSVImportProfile(ctx, userID, profileID)
+	}
+	return model.CSVImportProfile{}, repository.ErrNotFound
+}
+func (*fakeStore) CreateCSVImportProfile(_ context.Context, _ int, request model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	return model.CSVImportProfile{ID: 1, Name: request.Name, Columns: request.Columns}, nil
+}
+func (*fakeStore) UpdateCSVImportProfile(context.Context, int, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error) {
+	return model.CSVImportProfile{}, repository.ErrNotFound
+}
+func (*fakeStore) DeleteCSVImportProfile(context.Context, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) CreateImportPreview(
+	ctx context.Context,
+	userID int,
+	source string,
+	rows []repository.ImportPreviewRowRecord,
+	expiresAt time.Time,
+) (repository.ImportPreviewRecord, error) {
+	if f.createImportPreview != nil {
+		return f.createImportPreview(ctx, userID, source, rows, expiresAt)
+	}
+	return repository.ImportPreviewRecord{ID: 1, Source: source, Rows: rows}, nil
+}
+func (f *fakeStore) GetImportPreview(ctx context.Context, userID, previewID int) (repository.ImportPreviewRecord, error) {
+	if f.getImportPreview != nil {
+		return f.getImportPreview(ctx, userID, previewID)
+	}
+	return repository.ImportPreviewRecord{}, repository.ErrNotFound
+}
+func (*fakeStore) DeleteImportPreview(context.Context, int, int) error {
+	return nil
+}
+func (f *fakeStore) ExistingImportFingerprints(ctx context.Context, userID int, source string, fingerprints []string) ([]string, error) {
+	if f.existingImportFingerprints != nil {
+		return f.existingImportFingerprints(ctx, userID, source, fingerprints)
+	}
+	return []string{}, nil
+}
+func (f *fakeStore) ListDuplicateCandidates(ctx context.Context, userID int, from, to time.Time, limit int) ([]model.DuplicateTransactionPair, error) {
+	if f.listDuplicateCandidates != nil {
+		return f.listDuplicateCandidates(ctx, userID, from, to, limit)
+	}
+	return []model.DuplicateTransactionPair{}, nil
+}
+func (f *fakeStore) MergeTransactions(ctx context.Context, userID, keepID, mergeID int) (model.TransactionMergeResult, error) {
+	if f.mergeTransactions != nil {
+		return f.mergeTransactions(ctx, userID, keepID, mergeID)
+	}
+	return model.TransactionMergeResult{}, errors.New("unexpected MergeTransactions call")
+}
+func (f *fakeStore) ListMerchantCandidates(ctx context.Context, userID, afterID, limit int) ([]repository.MerchantCandidate, error) {
+	if f.listMerchantCandidates != nil {
+		return f.listMerchantCandidates(ctx, userID, afterID, limit)
+	}
+	return []repository.MerchantCandidate{}, nil
+}
+func (f *fakeStore) AssignTransactionMerchants(ctx context.Context, userID int, assignments []repository.MerchantAssignment) error {
+	if f.assignTransactionMerchants != nil {
+		return f.assignTransactionMerchants(ctx, userID, assignments)
+	}
+	return errors.New("unexpected AssignTransactionMerchants call")
+}
+func (f *fakeStore) ListMerchants(ctx context.Context, userID int) ([]model.Merchant, error) {
+	if f.listMerchants != nil {
+		return f.listMerchants(ctx, userID)
+	}
+	return []model.Merchant{}, nil
+}
+func (*fakeStore) GetMerchant(context.Context, int, int) (model.Merchant, error) {
+	return model.Merchant{}, repository.ErrNotFound
+}
+func (*fakeStore) RenameMerchant(context.Context, int, int, string) (model.Merchant, error) {
+	return model.Merchant{}, repository.ErrNotFound
+}
+func (*fakeStore) MergeMerchants(context.Context, int, int, int) (model.Merchant, error) {
+	return model.Merchant{}, repository.ErrNotFound
+}
+func (*fakeStore) ListMerchantSpending(context.Context, int, time.Time, time.Time) ([]model.MerchantSpending, error) {
+	return []model.MerchantSpending{}, nil
+}
+func (*fakeStore) ListMerchantMonthlySpending(context.Context, int, int, time.Time, time.Time) ([]model.MerchantMonthSpending, error) {
+	return []model.MerchantMonthSpending{}, nil
+}
+func (*fakeStore) ListCategorizationRules(context.Context, int) ([]model.CategorizationRule, error) {
+	return []model.CategorizationRule{}, nil
+}
+func (f *fakeStore) ListActiveCategorizationRules(ctx context.Context, userID int) ([]model.CategorizationRule, error) {
+	if f.listActiveCategorizationRules != nil {
+		return f.listActiveCategorizationRules(ctx, userID)
+	}
+	return []model.CategorizationRule{}, nil
+}
+func (*fakeStore) GetCategorizationRule(context.Context, int, int) (model.CategorizationRule, error) {
+	return model.CategorizationRule{}, repository.ErrNotFound
+}
+func (f *fakeStore) CreateCategorizationRule(ctx context.Context, userID int, request model.CategorizationRuleRequest) (model.CategorizationRule, error) {
+	if f.createCategorizationRule != nil {
+		return f.createCategorizationRule(ctx, userID, request)
+	}
+	return model.CategorizationRule{}, errors.New("unexpected CreateCategorizationRule call")
+}
+func (*fakeStore) UpdateCategorizationRule(context.Context, int, int, model.CategorizationRuleRequest) (model.CategorizationRule, error) {
+	return model.CategorizationRule{}, repository.ErrNotFound
+}
+func (*fakeStore) DeleteCategorizationRule(context.Context, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) ListCategorizationRuleTargets(ctx context.Context, userID int, from, to time.Time) ([]repository.CategorizationRuleTarget, error) {
+	if f.listCategorizationRuleTargets != nil {
+		return f.listCategorizationRuleTargets(ctx, userID, from, to)
+	}
+	return []repository.CategorizationRuleTarget{}, nil
+}
+func (f *fakeStore) ApplyCategorizationRuleUpdates(ctx context.Context, userID int, updates []repository.CategorizationRuleUpdate) (int, error) {
+	if f.applyCategorizationRuleUpdates != nil {
+		return f.applyCategorizationRuleUpdates(ctx, userID, updates)
+	}
+	return 0, errors.New("unexpected ApplyCategorizationRuleUpdates call")
+}
+func (f *fakeStore) ListCategoryTrainingExamples(ctx context.Context, userID, limit int) ([]repository.CategoryTrainingExample, error) {
+	if f.listCategoryTrainingExamples != nil {
+		return f.listCategoryTrainingExamples(ctx, userID, limit)
+	}
+	return []repository.CategoryTrainingExample{}, nil
+}
+func (*fakeStore) ResetCategoryLearning(context.Context, int) (int, error) {
+	return 0, nil
+}
+func (*fakeStore) ListTransactionProvenance(context.Context, int, int) ([]model.TransactionProvenance, error) {
+	return []model.TransactionProvenance{}, nil
+}
 func (f *fakeStore) CreateTransactionSchedule(ctx context.Context, userID int, request model.TransactionScheduleRequest) (model.TransactionSchedule, error) {
 	if f.createTransactionSchedule != nil {
 		return f.createTransactionSchedule(ctx, userID, request)
 	}
 	return model.TransactionSchedule{}, errors.New("unexpected CreateTransactionSchedule call")
 }
-func (*fakeStore) ListTransactionSchedules(context.Context, int, string, time.Time) ([]model.TransactionSchedule, error) {
+func (f *fakeStore) ListTransactionSchedules(ctx context.Context, userID int, status string, now time.Time) ([]model.TransactionSchedule, error) {
+	if f.listTransactionSchedules != nil {
+		return f.listTransactionSchedules(ctx, userID, status, now)
+	}
 	return []model.TransactionSchedule{}, nil
 }
 func (f *fakeStore) GetTransactionSchedule(ctx context.Context, userID, scheduleID int, now time.Time) (model.TransactionSchedule, error) {
@@ -1405,12 +1794,71 @@ func (f *fakeStore) ListTransactionScheduleOccurrences(ctx context.Context, user
 	}
 	return []model.TransactionScheduleOccurrence{}, nil
 }
-func (*fakeStore) PostDueTransactionScheduleOccurrences(context.Context, time.Time, int) (int, error) {
-	return 0, nil
+func (f *fakeStore) GetTransactionScheduleOccurrence(ctx context.Context, userID, occurrenceID int) (model.TransactionScheduleOccurrence, error) {
+	if f.getScheduleOccurrence != nil {
+		return f.getScheduleOccurrence(ctx, userID, occurrenceID)
+	}
+	return model.TransactionScheduleOccurrence{}, repository.ErrNotFound
+}
+func (f *fakeStore) UpdateTransactionScheduleOccurrence(
+	ctx context.Context, userID, occurrenceID int, request model.ScheduleOccurrenceRequest,
+) (model.TransactionScheduleOccurrence, error) {
+	if f.updateScheduleOccurrence != nil {
+		return f.updateScheduleOccurrence(ctx, userID, occurrenceID, request)
+	}
+	return model.TransactionScheduleOccurrence{}, repository.ErrNotFound
+}
+func (f *fakeStore) SetTransactionScheduleOccurrenceStatus(
+	ctx context.Context, userID, occurrenceID int, from, to string,
+) (model.TransactionScheduleOccurrence, error) {
+	if f.setScheduleOccurrenceStatus != nil {
+		return f.setScheduleOccurrenceStatus(ctx, userID, occurrenceID, from, to)
+	}
+	return model.TransactionScheduleOccurrence{}, repository.ErrNotFound
+}
+func (*fakeStore) PostDueTransactionScheduleOccurrences(context.Context, time.Time, int) ([]int, error) {
+	return nil, nil
 }
 func (*fakeStore) QueueDueTransactionScheduleReminders(context.Context, time.Time, int) (int, error) {
 	return 0, nil
 }
+func (f *fakeStore) ListUnreconciledTransactionScheduleOccurrences(
+	ctx context.Context, userID int, from, through time.Time,
+) ([]model.TransactionScheduleOccurrence, error) {
+	if f.listUnreconciledOccurrences != nil {
+		return f.listUnreconciledOccurrences(ctx, userID, from, through)
+	}
+	return []model.TransactionScheduleOccurrence{}, nil
+}
+func (f *fakeStore) ListReconcilableBankTransactions(
+	ctx context.Context, userID int, from, through time.Time,
+) ([]model.Transaction, error) {
+	if f.listReconcilableTransactions != nil {
+		return f.listReconcilableTransactions(ctx, userID, from, through)
+	}
+	return []model.Transaction{}, nil
+}
+func (f *fakeStore) ReconcileTransactionScheduleOccurrence(ctx context.Context, userID, occurrenceID, transactionID int) error {
+	if f.reconcileScheduleOccurrence != nil {
+		return f.reconcileScheduleOccurrence(ctx, userID, occurrenceID, transactionID)
+	}
+	return repository.ErrNotFound
+}
+func (f *fakeStore) ListTransactionScheduleReconciliationUsers(ctx context.Context) ([]int, error) {
+	if f.listReconciliationUsers != nil {
+		return f.listReconciliationUsers(ctx)
+	}
+	return []int{}, nil
+}
+func (f *fakeStore) MarkTransactionSchedulesReconciled(ctx context.Context, userID int, at time.Time) error {
+	if f.markSchedulesReconciled != nil {
+		return f.markSchedulesReconciled(ctx, userID, at)
+	}
+	return nil
+}
+func (*fakeStore) QueueMissedTransactionScheduleOccurrences(context.Context, time.Time, int, int, int) (int, error) {
+	return 0, nil
+}
 func (*fakeStore) ListBudgets(context.Context, int, time.Time, bool) ([]model.Budget, error) {
 	return []model.Budget{}, nil
 }
@@ -1425,6 +1873,140 @@ func (*fakeStore) UpdateBudget(context.Context, int, int, model.BudgetRequest, t
 }
 func (*fakeStore) ArchiveBudget(context.Context, int, int) error             { return repository.ErrNotFound }
 func (*fakeStore) QueueBudgetAlerts(context.Context, time.Time) (int, error) { return 0, nil }
+func (f *fakeStore) BudgetHitRate(ctx context.Context, userID int, from, to time.Time) (model.AnnualBudgets, error) {
+	if f.budgetHitRate != nil {
+		return f.budgetHitRate(ctx, userID, from, to)
+	}
+	return model.AnnualBudgets{}, nil
+}
+func (*fakeStore) ListSavingsGoals(context.Context, int, bool) ([]model.SavingsGoal, error) {
+	return []model.SavingsGoal{}, nil
+}
+func (f *fakeStore) GetSavingsGoal(ctx context.Context, userID, goalID int) (model.SavingsGoal, error) {
+	if f.getSavingsGoal != nil {
+		return f.getSavingsGoal(ctx, userID, goalID)
+	}
+	return model.SavingsGoal{}, repository.ErrNotFound
+}
+func (f *fakeStore) CreateSavingsGoal(ctx context.Context, userID int, request model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	if f.createSavingsGoal != nil {
+		return f.createSavingsGoal(ctx, userID, request)
+	}
+	return model.SavingsGoal{}, errors.New("unexpected CreateSavingsGoal call")
+}
+func (*fakeStore) UpdateSavingsGoal(context.Context, int, int, model.SavingsGoalRequest) (model.SavingsGoal, error) {
+	return model.SavingsGoal{}, repository.ErrNotFound
+}
+func (*fakeStore) ArchiveSavingsGoal(context.Context, int, int) error { return repository.ErrNotFound }
+func (*fakeStore) ListSavingsGoalContributions(context.Context, int, int) ([]model.SavingsGoalContribution, error) {
+	return []model.SavingsGoalContribution{}, nil
+}
+func (f *fakeStore) CreateSavingsGoalContribution(
+	ctx context.Context,
+	userID, goalID int,
+	request model.SavingsGoalContributionRequest,
+) (model.SavingsGoalContribution, error) {
+	if f.createSavingsGoalContribution != nil {
+		return f.createSavingsGoalContribution(ctx, userID, goalID, request)
+	}
+	return model.SavingsGoalContribution{}, errors.New("unexpected CreateSavingsGoalContribution call")
+}
+func (*fakeStore) DeleteSavingsGoalContribution(context.Context, int, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) QueueSavingsGoalMilestones(ctx context.Context) (int, error) {
+	if f.queueSavingsGoalMilestones != nil {
+		return f.queueSavingsGoalMilestones(ctx)
+	}
+	return 0, nil
+}
+func (*fakeStore) ListLoans(context.Context, int, bool) ([]model.Loan, error) {
+	return []model.Loan{}, nil
+}
+func (f *fakeStore) GetLoan(ctx context.Context, userID, loanID int) (model.Loan, error) {
+	if f.getLoan != nil {
+		return f.getLoan(ctx, userID, loanID)
+	}
+	return model.Loan{}, repository.ErrNotFound
+}
+func (*fakeStore) CreateLoan(context.Context, int, model.LoanRequest) (model.Loan, error) {
+	return model.Loan{}, errors.New("unexpected CreateLoan call")
+}
+func (*fakeStore) UpdateLoan(context.Context, int, int, model.LoanRequest) (model.Loan, error) {
+	return model.Loan{}, repository.ErrNotFound
+}
+func (*fakeStore) ArchiveLoan(context.Context, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) ListLoanPayments(ctx context.Context, userID, loanID int) ([]model.LoanPayment, error) {
+	if f.listLoanPayments != nil {
+		return f.listLoanPayments(ctx, userID, loanID)
+	}
+	return []model.LoanPayment{}, nil
+}
+func (f *fakeStore) CreateLoanPayment(ctx context.Context, userID, loanID int, request model.LoanPaymentRequest) (model.LoanPayment, error) {
+	if f.createLoanPayment != nil {
+		return f.createLoanPayment(ctx, userID, loanID, request)
+	}
+	return model.LoanPayment{}, errors.New("unexpected CreateLoanPayment call")
+}
+func (*fakeStore) DeleteLoanPayment(context.Context, int, int, int) error {
+	return repository.ErrNotFound
+}
+func (*fakeStore) ListExpenseGroups(context.Context, int, bool) ([]model.ExpenseGroup, error) {
+	return []model.ExpenseGroup{}, nil
+}
+func (f *fakeStore) GetExpenseGroup(_ context.Context, _ int, groupID int) (model.ExpenseGroup, error) {
+	if f.expenseGroup == nil || f.expenseGroup.ID != groupID {
+		return model.ExpenseGroup{}, repository.ErrNotFound
+	}
+	return *f.expenseGroup, nil
+}
+func (*fakeStore) CreateExpenseGroup(context.Context, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	return model.ExpenseGroup{}, errors.New("unexpected CreateExpenseGroup call")
+}
+func (*fakeStore) UpdateExpenseGroup(context.Context, int, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	return model.ExpenseGroup{}, repository.ErrNotFound
+}
+func (*fakeStore) ArchiveExpenseGroup(context.Context, int, int) error {
+	return repository.ErrNotFound
+}
+func (*fakeStore) AddExpenseGroupMember(context.Context, int, int, model.ExpenseGroupMemberRequest) (model.ExpenseGroupMember, error) {
+	return model.ExpenseGroupMember{}, repository.ErrNotFound
+}
+func (*fakeStore) DeleteExpenseGroupMember(context.Context, int, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) ListSharedExpenses(context.Context, int, int) ([]model.SharedExpense, error) {
+	return append([]model.SharedExpense{}, f.sharedExpenses...), nil
+}
+func (f *fakeStore) CreateSharedExpense(_ context.Context, _ int, _ int, expense model.SharedExpense) (model.SharedExpense, error) {
+	f.createdSharedExpense = &expense
+	return expense, nil
+}
+func (*fakeStore) DeleteSharedExpense(context.Context, int, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) ListExpenseSettlements(context.Context, int, int) ([]model.ExpenseSettlement, error) {
+	return append([]model.ExpenseSettlement{}, f.expenseSettlements...), nil
+}
+func (f *fakeStore) CreateExpenseSettlement(_ context.Context, _ int, _ int, request model.ExpenseSettlementRequest) (model.ExpenseSettlement, error) {
+	f.createdExpenseSettlement = &request
+	return model.ExpenseSettlement{ID: 1, FromMemberID: request.FromMemberID, ToMemberID: request.ToMemberID, Amount: request.Amount}, nil
+}
+func (*fakeStore) DeleteExpenseSettlement(context.Context, int, int, int) error {
+	return repository.ErrNotFound
+}
+func (f *fakeStore) GetForecastSettings(context.Context, int) (model.ForecastSettings, error) {
+	if f.forecastSettings != nil {
+		return *f.forecastSettings, nil
+	}
+	return model.ForecastSettings{}, nil
+}
+func (f *fakeStore) UpdateForecastSettings(_ context.Context, _ int, settings model.ForecastSettings) (model.ForecastSettings, error) {
+	f.forecastSettings = &settings
+	return settings, nil
+}
 func (*fakeStore) GetNotificationPreferences(context.Context, int) (model.NotificationPreferences, error) {
 	return model.NotificationPreferences{Timezone: defaultScheduleTimezone}, nil
 }
@@ -1487,7 +2069,10 @@ func (f *fakeStore) UpsertInvestmentMarketHistory(
 func (*fakeStore) CreateInvestmentSchedule(context.Context, int, model.InvestmentScheduleRequest) (model.InvestmentSchedule, error) {
 	return model.InvestmentSchedule{}, nil
 }
-func (*fakeStore) ListInvestmentSchedules(context.Context, int, string) ([]model.InvestmentSchedule, error) {
+func (f *fakeStore) ListInvestmentSchedules(ctx context.Context, userID int, status string) ([]model.InvestmentSchedule, error) {
+	if f.listInvestmentSchedules != nil {
+		return f.listInvestmentSchedules(ctx, userID, status)
+	}
 	return []model.InvestmentSchedule{}, nil
 }
 
@@ -1497,7 +2082,7 @@ func (f *fakeStore) GetInvestmentSchedule(ctx context.Context, userID, scheduleI
 	}
 	return model.InvestmentSchedule{}, repository.ErrNotFound
 }
-func (*fakeStore) UpdateInvestmentSchedule(context.Context, int, int, model.InvestmentScheduleRequest) (model.InvestmentSchedule, error) {
+func (*fakeStore) UpdateInvestmentSchedule(context.Context, int, int, model.InvestmentScheduleRequest, time.Time) (model.InvestmentSchedule, error) {
 	return model.InvestmentSchedule{}, repository.ErrNotFound
 }
 func (*fakeStore) SetInvestmentScheduleStatus(context.Context, int, int, string) error {
@@ -1537,6 +2122,33 @@ func (f *fakeStore) ListDueInvestmentScheduleOccurrences(
 	}
 	return []repository.DueInvestmentScheduleOccurrence{}, nil
 }
+func (f *fakeStore) ListInvestmentScheduleOccurrences(
+	ctx context.Context, userID int, filter repository.ScheduleOccurrenceFilter,
+) ([]model.InvestmentScheduleOccurrence, error) {
+	if f.listInvestmentOccurrences != nil {
+		return f.listInvestmentOccurrences(ctx, userID, filter)
+	}
+	return []model.InvestmentScheduleOccurrence{}, nil
+}
+func (f *fakeStore) GetInvestmentScheduleOccurrence(ctx context.Context, userID, occurrenceID int) (model.InvestmentScheduleOccurrence, error) {
+	if f.getInvestmentOccurrence != nil {
+		return f.getInvestmentOccurrence(ctx, userID, occurrenceID)
+	}
+	return model.InvestmentScheduleOccurrence{}, repository.ErrNotFound
+}
+func (*fakeStore) UpdateInvestmentScheduleOccurrence(
+	context.Context, int, int, model.InvestmentScheduleOccurrenceRequest,
+) (model.InvestmentScheduleOccurrence, error) {
+	return model.InvestmentScheduleOccurrence{}, repository.ErrNotFound
+}
+func (f *fakeStore) SetInvestmentScheduleOccurrenceStatus(
+	ctx context.Context, userID, occurrenceID int, from, to string,
+) (model.InvestmentScheduleOccurrence, error) {
+	if f.setInvestmentOccurrenceStatus != nil {
+		return f.setInvestmentOccurrenceStatus(ctx, userID, occurrenceID, from, to)
+	}
+	return model.InvestmentScheduleOccurrence{}, repository.ErrNotFound
+}
 func (f *fakeStore) PostInvestmentScheduleOccurrence(
 	ctx context.Context, occurrenceID int, request model.InvestmentTradeRequest,
 ) (model.InvestmentTrade, bool, error) {
@@ -1588,9 +2200,24 @@ func (*fakeStore) UpdateOpenBankingConnectionStatus(context.Context, int, int, s
 	return nil
 }
 func (*fakeStore) DeleteOpenBankingConnection(context.Context, int, int) error { return nil }
-func (*fakeStore) ListOpenBankingAccounts(context.Context, int) ([]model.OpenBankingAccount, error) {
+func (f *fakeStore) ListOpenBankingAccounts(ctx context.Context, userID int) ([]model.OpenBankingAccount, error) {
+	if f.listOpenBankingAccounts != nil {
+		return f.listOpenBankingAccounts(ctx, userID)
+	}
 	return []model.OpenBankingAccount{}, nil
 }
+func (f *fakeStore) RecordOpenBankingAccountBalances(ctx context.Context, userID int, balances []model.OpenBankingAccountBalance) error {
+	if f.recordOpenBankingAccountBalances != nil {
+		return f.recordOpenBankingAccountBalances(ctx, userID, balances)
+	}
+	return nil
+}
+func (f *fakeStore) ListOpenBankingAccountBalances(ctx context.Context, userID int, from, through time.Time) ([]model.OpenBankingAccountBalance, error) {
+	if f.listOpenBankingAccountBalances != nil {
+		return f.listOpenBankingAccountBalances(ctx, userID, from, through)
+	}
+	return []model.OpenBankingAccountBalance{}, nil
+}
 func (f *fakeStore) GetOpenBankingAccount(ctx context.Context, userID, accountID int) (repository.OpenBankingAccountRecord, error) {
 	if f.getOpenBankingAccount != nil {
 		return f.getOpenBankingAccount(ctx, userID, accountID)
@@ -1659,3 +2286,32 @@ func (f *fakeStore) CompleteNotificationDelivery(
 	}
 	return nil
 }
+
+func (f *fakeStore) CashFlowReport(ctx context.Context, userID int, reportRange repository.ReportRange) (model.CashFlowReport, error) {
+	if f.cashFlowReport != nil {
+		return f.cashFlowReport(ctx, userID, reportRange)
+	}
+	return model.CashFlowReport{Months: []model.CashFlowMonth{}}, nil
+}
+func (f *fakeStore) CategoryReport(
+	ctx context.Context,
+	userID int,
+	transactionType string,
+	reportRange repository.ReportRange,
+) (model.CategoryReport, error) {
+	if f.categoryReport != nil {
+		return f.categoryReport(ctx, userID, transactionType, reportRange)
+	}
+	return model.CategoryReport{Categories: []model.CategoryReportItem{}, Months: []model.CategoryReportMonth{}}, nil
+}
+func (f *fakeStore) MerchantReport(
+	ctx context.Context,
+	userID int,
+	reportRange repository.ReportRange,
+	limit int,
+) (model.MerchantReport, error) {
+	if f.merchantReport != nil {
+		return f.merchantReport(ctx, userID, reportRange, limit)
+	}
+	return model.MerchantReport{Merchants: []model.MerchantReportItem{}}, nil
+}
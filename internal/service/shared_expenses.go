@@ -0,0 +1,695 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumExpenseGroupNameRunes  = 100
+	maximumExpenseMemberNameRunes = 60
+	maximumExpenseGroupMembers    = 50
+	maximumSharedExpenseDescRunes = 200
+	maximumSettlementNoteRunes    = 200
+	defaultExpenseGroupSelfName   = "Me"
+)
+
+func (s *Service) ListExpenseGroups(ctx context.Context, userID int, includeArchived bool) ([]model.ExpenseGroup, error) {
+	items, err := s.store.ListExpenseGroups(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list expense groups: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) GetExpenseGroup(ctx context.Context, userID, groupID int) (model.ExpenseGroup, error) {
+	if err := validateID(groupID); err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	item, err := s.store.GetExpenseGroup(ctx, userID, groupID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.ExpenseGroup{}, apperrors.NotFound("expense group not found")
+	}
+	if err != nil {
+		return model.ExpenseGroup{}, apperrors.Internal(fmt.Errorf("get expense group: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) CreateExpenseGroup(ctx context.Context, userID int, request model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	name, err := normalizeLimitedText(request.Name, "name", maximumExpenseGroupNameRunes, false)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	currency := strings.ToUpper(strings.TrimSpace(request.Currency))
+	if currency == "" {
+		currency = supportedCurrency
+	}
+	if currency != supportedCurrency {
+		return model.ExpenseGroup{}, apperrors.Validation("currency must be EUR")
+	}
+	selfName := strings.TrimSpace(request.SelfName)
+	if selfName == "" {
+		selfName = defaultExpenseGroupSelfName
+	}
+	if selfName, err = normalizeLimitedText(selfName, "self_name", maximumExpenseMemberNameRunes, false); err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	if len(request.Members) >= maximumExpenseGroupMembers {
+		return model.ExpenseGroup{}, apperrors.Validation(
+			fmt.Sprintf("a group can have at most %d members", maximumExpenseGroupMembers),
+		)
+	}
+	names := map[string]bool{strings.ToLower(selfName): true}
+	members := make([]model.ExpenseGroupMemberRequest, 0, len(request.Members))
+	for _, member := range request.Members {
+		normalized, err := normalizeExpenseGroupMember(member)
+		if err != nil {
+			return model.ExpenseGroup{}, err
+		}
+		if names[strings.ToLower(normalized.Name)] {
+			return model.ExpenseGroup{}, apperrors.Validation("member names must be unique within a group")
+		}
+		names[strings.ToLower(normalized.Name)] = true
+		members = append(members, normalized)
+	}
+	item, err := s.store.CreateExpenseGroup(ctx, userID, model.ExpenseGroupRequest{
+		Name: name, Currency: currency, SelfName: selfName, Members: members,
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		return model.ExpenseGroup{}, apperrors.Validation("member names must be unique within a group")
+	}
+	if err != nil {
+		return model.ExpenseGroup{}, apperrors.Internal(fmt.Errorf("create expense group: %w", err))
+	}
+	return item, nil
+}
+
+// UpdateExpenseGroup renames a group; members are managed separately.
+func (s *Service) UpdateExpenseGroup(ctx context.Context, userID, groupID int, request model.ExpenseGroupRequest) (model.ExpenseGroup, error) {
+	if _, err := s.activeExpenseGroup(ctx, userID, groupID); err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	name, err := normalizeLimitedText(request.Name, "name", maximumExpenseGroupNameRunes, false)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	item, err := s.store.UpdateExpenseGroup(ctx, userID, groupID, model.ExpenseGroupRequest{Name: name})
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.ExpenseGroup{}, apperrors.NotFound("expense group not found")
+	}
+	if err != nil {
+		return model.ExpenseGroup{}, apperrors.Internal(fmt.Errorf("update expense group: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) DeleteExpenseGroup(ctx context.Context, userID, groupID int) error {
+	if err := validateID(groupID); err != nil {
+		return err
+	}
+	err := s.store.ArchiveExpenseGroup(ctx, userID, groupID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("expense group not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("archive expense group: %w", err))
+	}
+	return nil
+}
+
+func (s *Service) AddExpenseGroupMember(
+	ctx context.Context,
+	userID, groupID int,
+	request model.ExpenseGroupMemberRequest,
+) (model.ExpenseGroupMember, error) {
+	group, err := s.activeExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseGroupMember{}, err
+	}
+	if len(group.Members) >= maximumExpenseGroupMembers {
+		return model.ExpenseGroupMember{}, apperrors.Validation(
+			fmt.Sprintf("a group can have at most %d members", maximumExpenseGroupMembers),
+		)
+	}
+	normalized, err := normalizeExpenseGroupMember(request)
+	if err != nil {
+		return model.ExpenseGroupMember{}, err
+	}
+	item, err := s.store.AddExpenseGroupMember(ctx, userID, groupID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.ExpenseGroupMember{}, apperrors.NotFound("expense group not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.ExpenseGroupMember{}, apperrors.Conflict("a member with this name already exists")
+	}
+	if err != nil {
+		return model.ExpenseGroupMember{}, apperrors.Internal(fmt.Errorf("add expense group member: %w", err))
+	}
+	return item, nil
+}
+
+// DeleteExpenseGroupMember removes a participant who is not part of any
+// expense or settlement yet.
+func (s *Service) DeleteExpenseGroupMember(ctx context.Context, userID, groupID, memberID int) error {
+	if err := validateID(groupID); err != nil {
+		return err
+	}
+	if err := validateID(memberID); err != nil {
+		return err
+	}
+	err := s.store.DeleteExpenseGroupMember(ctx, userID, groupID, memberID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("expense group member not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return apperrors.Conflict("members with expenses or settlements, and the group owner, cannot be removed")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete expense group member: %w", err))
+	}
+	return nil
+}
+
+func (s *Service) ListSharedExpenses(ctx context.Context, userID, groupID int) ([]model.SharedExpense, error) {
+	if _, err := s.GetExpenseGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListSharedExpenses(ctx, userID, groupID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list shared expenses: %w", err))
+	}
+	return items, nil
+}
+
+// AddSharedExpense splits an expense among group members. Share amounts are
+// rounded to the cent, and leftover cents go to the members with the largest
+// remainders, in member order on a tie.
+func (s *Service) AddSharedExpense(
+	ctx context.Context,
+	userID, groupID int,
+	request model.SharedExpenseRequest,
+) (model.SharedExpense, error) {
+	group, err := s.activeExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return model.SharedExpense{}, err
+	}
+	self := expenseGroupSelf(group)
+	expense := model.SharedExpense{PaidByMemberID: request.PaidByMemberID, TransactionID: request.TransactionID}
+	if expense.PaidByMemberID == 0 {
+		expense.PaidByMemberID = self
+	}
+	if !expenseGroupHasMember(group, expense.PaidByMemberID) {
+		return model.SharedExpense{}, apperrors.Validation("paid_by_member_id must be a member of the group")
+	}
+	description := request.Description
+	if request.TransactionID != nil {
+		if expense.PaidByMemberID != self {
+			return model.SharedExpense{}, apperrors.Validation("an expense backed by transaction_id must be paid by you")
+		}
+		if strings.TrimSpace(request.Amount) != "" || strings.TrimSpace(request.Date) != "" {
+			return model.SharedExpense{}, apperrors.Validation("amount and date are taken from the linked transaction")
+		}
+		transaction, err := s.bookedTransaction(ctx, userID, *request.TransactionID, "expense")
+		if err != nil {
+			return model.SharedExpense{}, err
+		}
+		expense.Amount, expense.Date = transaction.Amount, transaction.OccurredAt
+		if strings.TrimSpace(description) == "" {
+			description = transaction.Description
+		}
+	} else {
+		if expense.Amount, err = normalizeAmount(request.Amount); err != nil {
+			return model.SharedExpense{}, err
+		}
+		if expense.Date, err = s.pastOrTodayDate(request.Date); err != nil {
+			return model.SharedExpense{}, err
+		}
+	}
+	if expense.Description, err = normalizeLimitedText(description, "description", maximumSharedExpenseDescRunes, false); err != nil {
+		return model.SharedExpense{}, err
+	}
+	expense.SplitMethod = strings.ToLower(strings.TrimSpace(request.SplitMethod))
+	if expense.Shares, err = splitSharedExpense(group, expense.Amount, expense.SplitMethod, request.Shares); err != nil {
+		return model.SharedExpense{}, err
+	}
+
+	item, err := s.store.CreateSharedExpense(ctx, userID, groupID, expense)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.SharedExpense{}, apperrors.NotFound("expense group not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.SharedExpense{}, apperrors.Conflict("transaction is already linked to a shared expense")
+	}
+	if err != nil {
+		return model.SharedExpense{}, apperrors.Internal(fmt.Errorf("add shared expense: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return item, nil
+}
+
+func (s *Service) DeleteSharedExpense(ctx context.Context, userID, groupID, expenseID int) error {
+	if err := validateID(groupID); err != nil {
+		return err
+	}
+	if err := validateID(expenseID); err != nil {
+		return err
+	}
+	err := s.store.DeleteSharedExpense(ctx, userID, groupID, expenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("shared expense not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete shared expense: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return nil
+}
+
+func (s *Service) ListExpenseSettlements(ctx context.Context, userID, groupID int) ([]model.ExpenseSettlement, error) {
+	if _, err := s.GetExpenseGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListExpenseSettlements(ctx, userID, groupID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list expense settlements: %w", err))
+	}
+	return items, nil
+}
+
+// AddExpenseSettlement records money handed from one member to another. When
+// you pay or receive it, the transfer can be backed by the ledger
+// transaction; that transaction then no longer counts toward budgets.
+func (s *Service) AddExpenseSettlement(
+	ctx context.Context,
+	userID, groupID int,
+	request model.ExpenseSettlementRequest,
+) (model.ExpenseSettlement, error) {
+	group, err := s.activeExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseSettlement{}, err
+	}
+	if !expenseGroupHasMember(group, request.FromMemberID) || !expenseGroupHasMember(group, request.ToMemberID) {
+		return model.ExpenseSettlement{}, apperrors.Validation("from_member_id and to_member_id must be members of the group")
+	}
+	if request.FromMemberID == request.ToMemberID {
+		return model.ExpenseSettlement{}, apperrors.Validation("from_member_id and to_member_id must differ")
+	}
+	note, err := normalizeLimitedText(request.Note, "note", maximumSettlementNoteRunes, true)
+	if err != nil {
+		return model.ExpenseSettlement{}, err
+	}
+	normalized := model.ExpenseSettlementRequest{
+		FromMemberID: request.FromMemberID, ToMemberID: request.ToMemberID, TransactionID: request.TransactionID, Note: note,
+	}
+	if request.TransactionID != nil {
+		if strings.TrimSpace(request.Amount) != "" || strings.TrimSpace(request.Date) != "" {
+			return model.ExpenseSettlement{}, apperrors.Validation("amount and date are taken from the linked transaction")
+		}
+		self := expenseGroupSelf(group)
+		transactionType := ""
+		switch self {
+		case request.FromMemberID:
+			transactionType = "expense"
+		case request.ToMemberID:
+			transactionType = "income"
+		default:
+			return model.ExpenseSettlement{}, apperrors.Validation("transaction_id can only back a settlement you pay or receive")
+		}
+		transaction, err := s.bookedTransaction(ctx, userID, *request.TransactionID, transactionType)
+		if err != nil {
+			return model.ExpenseSettlement{}, err
+		}
+		normalized.Amount, normalized.Date = transaction.Amount, transaction.OccurredAt
+	} else {
+		if normalized.Amount, err = normalizeAmount(request.Amount); err != nil {
+			return model.ExpenseSettlement{}, err
+		}
+		if normalized.Date, err = s.pastOrTodayDate(request.Date); err != nil {
+			return model.ExpenseSettlement{}, err
+		}
+	}
+
+	item, err := s.store.CreateExpenseSettlement(ctx, userID, groupID, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.ExpenseSettlement{}, apperrors.NotFound("expense group not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.ExpenseSettlement{}, apperrors.Conflict("transaction is already linked to a settlement")
+	}
+	if err != nil {
+		return model.ExpenseSettlement{}, apperrors.Internal(fmt.Errorf("add expense settlement: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return item, nil
+}
+
+func (s *Service) DeleteExpenseSettlement(ctx context.Context, userID, groupID, settlementID int) error {
+	if err := validateID(groupID); err != nil {
+		return err
+	}
+	if err := validateID(settlementID); err != nil {
+		return err
+	}
+	err := s.store.DeleteExpenseSettlement(ctx, userID, groupID, settlementID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("expense settlement not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete expense settlement: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return nil
+}
+
+// GetExpenseGroupBalances nets what each member paid and settled against
+// their shares, then pairs the largest debtor with the largest creditor
+// until everyone is even. That needs at most one transfer fewer than there
+// are members with a non-zero balance.
+func (s *Service) GetExpenseGroupBalances(ctx context.Context, userID, groupID int) (model.ExpenseGroupBalances, error) {
+	group, err := s.GetExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseGroupBalances{}, err
+	}
+	expenses, err := s.store.ListSharedExpenses(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseGroupBalances{}, apperrors.Internal(fmt.Errorf("list shared expenses: %w", err))
+	}
+	settlements, err := s.store.ListExpenseSettlements(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseGroupBalances{}, apperrors.Internal(fmt.Errorf("list expense settlements: %w", err))
+	}
+
+	type position struct{ paid, share, sent, received int64 }
+	positions := make(map[int]*position, len(group.Members))
+	for _, member := range group.Members {
+		positions[member.ID] = &position{}
+	}
+	add := func(memberID int, amount string, field func(*position) *int64) error {
+		cents, ok := amountCents(amount)
+		if !ok {
+			return fmt.Errorf("stored amount %q of expense group %d is invalid", amount, groupID)
+		}
+		if item := positions[memberID]; item != nil {
+			*field(item) += cents
+		}
+		return nil
+	}
+	for _, expense := range expenses {
+		if err := add(expense.PaidByMemberID, expense.Amount, func(p *position) *int64 { return &p.paid }); err != nil {
+			return model.ExpenseGroupBalances{}, apperrors.Internal(err)
+		}
+		for _, share := range expense.Shares {
+			if err := add(share.MemberID, share.Amount, func(p *position) *int64 { return &p.share }); err != nil {
+				return model.ExpenseGroupBalances{}, apperrors.Internal(err)
+			}
+		}
+	}
+	for _, settlement := range settlements {
+		if err := add(settlement.FromMemberID, settlement.Amount, func(p *position) *int64 { return &p.sent }); err != nil {
+			return model.ExpenseGroupBalances{}, apperrors.Internal(err)
+		}
+		if err := add(settlement.ToMemberID, settlement.Amount, func(p *position) *int64 { return &p.received }); err != nil {
+			return model.ExpenseGroupBalances{}, apperrors.Internal(err)
+		}
+	}
+
+	result := model.ExpenseGroupBalances{
+		GroupID: group.ID, Currency: group.Currency,
+		Members:   make([]model.ExpenseGroupMemberBalance, 0, len(group.Members)),
+		Transfers: make([]model.ExpenseGroupTransfer, 0),
+	}
+	balances := make([]memberBalance, 0, len(group.Members))
+	for _, member := range group.Members {
+		item := positions[member.ID]
+		balance := item.paid - item.share + item.sent - item.received
+		result.Members = append(result.Members, model.ExpenseGroupMemberBalance{
+			MemberID: member.ID, Name: member.Name, Paid: formatCents(item.paid), Share: formatCents(item.share),
+			Sent: formatCents(item.sent), Received: formatCents(item.received), Balance: formatCents(balance),
+		})
+		balances = append(balances, memberBalance{memberID: member.ID, cents: balance})
+	}
+	for _, transfer := range simplifyDebts(balances) {
+		result.Transfers = append(result.Transfers, model.ExpenseGroupTransfer{
+			FromMemberID: transfer.from, ToMemberID: transfer.to, Amount: formatCents(transfer.cents),
+		})
+	}
+	return result, nil
+}
+
+func (s *Service) activeExpenseGroup(ctx context.Context, userID, groupID int) (model.ExpenseGroup, error) {
+	group, err := s.GetExpenseGroup(ctx, userID, groupID)
+	if err != nil {
+		return model.ExpenseGroup{}, err
+	}
+	if group.Status != "active" {
+		return model.ExpenseGroup{}, apperrors.Conflict("archived expense groups cannot be changed")
+	}
+	return group, nil
+}
+
+// bookedTransaction loads a booked EUR transaction of the given type for a
+// shared expense or settlement.
+func (s *Service) bookedTransaction(ctx context.Context, userID, transactionID int, transactionType string) (model.Transaction, error) {
+	if err := validateID(transactionID); err != nil {
+		return model.Transaction{}, err
+	}
+	transaction, err := s.store.GetTransaction(ctx, userID, transactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Transaction{}, apperrors.Validation("transaction_id must be an existing transaction")
+	}
+	if err != nil {
+		return model.Transaction{}, apperrors.Internal(fmt.Errorf("get linked transaction: %w", err))
+	}
+	if transaction.Type != transactionType || transaction.Status != "booked" || transaction.Currency != supportedCurrency {
+		return model.Transaction{}, apperrors.Validation("transaction_id must be a booked EUR " + transactionType)
+	}
+	return transaction, nil
+}
+
+// pastOrTodayDate parses an optional date that defaults to today.
+func (s *Service) pastOrTodayDate(value string) (string, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return "", apperrors.Internal(err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return today.Format(time.DateOnly), nil
+	}
+	date, err := parseDate(value, "date")
+	if err != nil {
+		return "", err
+	}
+	if date.After(today) {
+		return "", apperrors.Validation("date must not be in the future")
+	}
+	return date.Format(time.DateOnly), nil
+}
+
+func normalizeExpenseGroupMember(request model.ExpenseGroupMemberRequest) (model.ExpenseGroupMemberRequest, error) {
+	name, err := normalizeLimitedText(request.Name, "member name", maximumExpenseMemberNameRunes, false)
+	if err != nil {
+		return model.ExpenseGroupMemberRequest{}, err
+	}
+	email := ""
+	if strings.TrimSpace(request.Email) != "" {
+		if email, err = normalizeEmail(request.Email); err != nil {
+			return model.ExpenseGroupMemberRequest{}, err
+		}
+	}
+	return model.ExpenseGroupMemberRequest{Name: name, Email: email}, nil
+}
+
+// splitSharedExpense turns the requested shares into amounts that add up to
+// the expense exactly.
+func splitSharedExpense(
+	group model.ExpenseGroup,
+	amount, method string,
+	requested []model.SharedExpenseShareRequest,
+) ([]model.SharedExpenseShare, error) {
+	total, ok := amountCents(amount)
+	if !ok {
+		return nil, apperrors.Internal(fmt.Errorf("normalized amount %q is invalid", amount))
+	}
+	if method == "equal" && len(requested) == 0 {
+		for _, member := range group.Members {
+			requested = append(requested, model.SharedExpenseShareRequest{MemberID: member.ID})
+		}
+	}
+	if len(requested) == 0 {
+		return nil, apperrors.Validation("shares must name at least one member")
+	}
+	seen := make(map[int]bool, len(requested))
+	for _, share := range requested {
+		if !expenseGroupHasMember(group, share.MemberID) {
+			return nil, apperrors.Validation("shares must name members of the group")
+		}
+		if seen[share.MemberID] {
+			return nil, apperrors.Validation("each member can appear in shares only once")
+		}
+		seen[share.MemberID] = true
+	}
+
+	shares := make([]model.SharedExpenseShare, len(requested))
+	switch method {
+	case "equal":
+		weights := make([]*big.Rat, len(requested))
+		for index, share := range requested {
+			if strings.TrimSpace(share.Amount) != "" || strings.TrimSpace(share.Percentage) != "" {
+				return nil, apperrors.Validation("equal splits take no share amounts or percentages")
+			}
+			weights[index] = big.NewRat(1, 1)
+		}
+		for index, cents := range allocateCents(total, weights) {
+			shares[index] = model.SharedExpenseShare{MemberID: requested[index].MemberID, Amount: formatCents(cents)}
+		}
+	case "exact":
+		var sum int64
+		for index, share := range requested {
+			value, err := normalizeAmount(share.Amount)
+			if err != nil {
+				return nil, apperrors.Validation("share amount must be a positive decimal with at most 2 decimal places")
+			}
+			cents, _ := amountCents(value)
+			sum += cents
+			shares[index] = model.SharedExpenseShare{MemberID: share.MemberID, Amount: value}
+		}
+		if sum != total {
+			return nil, apperrors.Validation("share amounts must add up to the expense amount")
+		}
+	case "percentage":
+		weights := make([]*big.Rat, len(requested))
+		sum := new(big.Rat)
+		for index, share := range requested {
+			value, err := normalizePercentage(share.Percentage, "share percentage")
+			if err != nil {
+				return nil, err
+			}
+			weights[index], _ = new(big.Rat).SetString(value)
+			sum.Add(sum, weights[index])
+			shares[index] = model.SharedExpenseShare{MemberID: share.MemberID, Percentage: value}
+		}
+		if sum.Cmp(big.NewRat(100, 1)) != 0 {
+			return nil, apperrors.Validation("share percentages must add up to 100")
+		}
+		for index, cents := range allocateCents(total, weights) {
+			shares[index].Amount = formatCents(cents)
+		}
+	default:
+		return nil, apperrors.Validation("split_method must be equal, exact, or percentage")
+	}
+	return shares, nil
+}
+
+// allocateCents splits total in proportion to weights using the largest
+// remainder method, so the parts always add up to total.
+func allocateCents(total int64, weights []*big.Rat) []int64 {
+	sum := new(big.Rat)
+	for _, weight := range weights {
+		sum.Add(sum, weight)
+	}
+	parts := make([]int64, len(weights))
+	remainders := make([]*big.Rat, len(weights))
+	allocated := int64(0)
+	for index, weight := range weights {
+		exact := new(big.Rat).Mul(big.NewRat(total, 1), weight)
+		exact.Quo(exact, sum)
+		floor := new(big.Int).Quo(exact.Num(), exact.Denom())
+		parts[index] = floor.Int64()
+		remainders[index] = exact.Sub(exact, new(big.Rat).SetInt(floor))
+		allocated += parts[index]
+	}
+	order := make([]int, len(weights))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]].Cmp(remainders[order[j]]) > 0 })
+	for index := 0; allocated < total; index++ {
+		parts[order[index%len(order)]]++
+		allocated++
+	}
+	return parts
+}
+
+type memberBalance struct {
+	memberID int
+	cents    int64
+}
+
+type debtTransfer struct {
+	from, to int
+	cents    int64
+}
+
+// simplifyDebts settles balances greedily: the member who owes most pays the
+// member who is owed most, until all balances are zero.
+func simplifyDebts(balances []memberBalance) []debtTransfer {
+	creditors, debtors := make([]memberBalance, 0), make([]memberBalance, 0)
+	for _, balance := range balances {
+		switch {
+		case balance.cents > 0:
+			creditors = append(creditors, balance)
+		case balance.cents < 0:
+			debtors = append(debtors, memberBalance{memberID: balance.memberID, cents: -balance.cents})
+		}
+	}
+	transfers := make([]debtTransfer, 0)
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.SliceStable(creditors, func(i, j int) bool { return creditors[i].cents > creditors[j].cents })
+		sort.SliceStable(debtors, func(i, j int) bool { return debtors[i].cents > debtors[j].cents })
+		amount := min(creditors[0].cents, debtors[0].cents)
+		transfers = append(transfers, debtTransfer{from: debtors[0].memberID, to: creditors[0].memberID, cents: amount})
+		creditors[0].cents -= amount
+		debtors[0].cents -= amount
+		if creditors[0].cents == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].cents == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return transfers
+}
+
+func expenseGroupSelf(group model.ExpenseGroup) int {
+	for _, member := range group.Members {
+		if member.IsSelf {
+			return member.ID
+		}
+	}
+	return 0
+}
+
+func expenseGroupHasMember(group model.ExpenseGroup, memberID int) bool {
+	for _, member := range group.Members {
+		if member.ID == memberID {
+			return true
+		}
+	}
+	return false
+}
+
+func amountCents(value string) (int64, bool) {
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, false
+	}
+	amount.Mul(amount, big.NewRat(100, 1))
+	if !amount.IsInt() || !amount.Num().IsInt64() {
+		return 0, false
+	}
+	return amount.Num().Int64(), true
+}
+
+func formatCents(cents int64) string {
+	return big.NewRat(cents, 100).FloatString(2)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func testExpenseGroup() *model.ExpenseGroup {
+	return &model.ExpenseGroup{ID: 4, Name: "Trip", Currency: "EUR", Status: "active", Members: []model.ExpenseGroupMember{
+		{ID: 1, Name: "Me", IsSelf: true}, {ID: 2, Name: "Ana"}, {ID: 3, Name: "Ben"},
+	}}
+}
+
+func TestSplitSharedExpense(t *testing.T) {
+	group := *testExpenseGroup()
+	for _, test := range []struct {
+		name, amount, method string
+		shares               []model.SharedExpenseShareRequest
+		want                 []model.SharedExpenseShare
+	}{
+		{
+			"equal among everyone", "100.00", "equal", nil,
+			[]model.SharedExpenseShare{{MemberID: 1, Amount: "33.34"}, {MemberID: 2, Amount: "33.33"}, {MemberID: 3, Amount: "33.33"}},
+		},
+		{
+			"equal among some", "10.01", "equal", []model.SharedExpenseShareRequest{{MemberID: 3}, {MemberID: 1}},
+			[]model.SharedExpenseShare{{MemberID: 3, Amount: "5.01"}, {MemberID: 1, Amount: "5.00"}},
+		},
+		{
+			"percentages keep the total", "99.99", "percentage", []model.SharedExpenseShareRequest{
+				{MemberID: 1, Percentage: "50"}, {MemberID: 2, Percentage: "30"}, {MemberID: 3, Percentage: "20"},
+			},
+			[]model.SharedExpenseShare{
+				{MemberID: 1, Amount: "49.99", Percentage: "50.000"}, {MemberID: 2, Amount: "30.00", Percentage: "30.000"},
+				{MemberID: 3, Amount: "20.00", Percentage: "20.000"},
+			},
+		},
+		{
+			"exact amounts", "60.00", "exact", []model.SharedExpenseShareRequest{{MemberID: 1, Amount: "20"}, {MemberID: 3, Amount: "40"}},
+			[]model.SharedExpenseShare{{MemberID: 1, Amount: "20.00"}, {MemberID: 3, Amount: "40.00"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			shares, err := splitSharedExpense(group, test.amount, test.method, test.shares)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(shares, test.want) {
+				t.Fatalf("shares = %#v", shares)
+			}
+		})
+	}
+
+	for name, test := range map[string]struct {
+		method string
+		shares []model.SharedExpenseShareRequest
+	}{
+		"unknown method":         {"thirds", nil},
+		"exact without shares":   {"exact", nil},
+		"exact sum mismatch":     {"exact", []model.SharedExpenseShareRequest{{MemberID: 1, Amount: "20"}, {MemberID: 2, Amount: "30"}}},
+		"percentages below 100":  {"percentage", []model.SharedExpenseShareRequest{{MemberID: 1, Percentage: "50"}, {MemberID: 2, Percentage: "40"}}},
+		"member outside group":   {"equal", []model.SharedExpenseShareRequest{{MemberID: 1}, {MemberID: 9}}},
+		"member twice":           {"equal", []model.SharedExpenseShareRequest{{MemberID: 1}, {MemberID: 1}}},
+		"equal with amounts set": {"equal", []model.SharedExpenseShareRequest{{MemberID: 1, Amount: "10"}}},
+	} {
+		if _, err := splitSharedExpense(group, "60.00", test.method, test.shares); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+}
+
+func TestAddSharedExpenseFromTransaction(t *testing.T) {
+	store := &fakeStore{
+		expenseGroup: testExpenseGroup(),
+		getTransaction: func(_ context.Context, _ int, transactionID int) (model.Transaction, error) {
+			switch transactionID {
+			case 20:
+				return model.Transaction{ID: 20, Type: "expense", Status: "booked", Currency: "EUR",
+					Description: "Dinner at Bistro", Amount: "90.00", OccurredAt: "2026-07-10"}, nil
+			case 21:
+				return model.Transaction{ID: 21, Type: "expense", Status: "pending", Currency: "EUR", Amount: "5.00", OccurredAt: "2026-07-10"}, nil
+			}
+			return model.Transaction{}, repository.ErrNotFound
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+
+	transactionID := 20
+	if _, err := service.AddSharedExpense(context.Background(), 7, 4, model.SharedExpenseRequest{
+		TransactionID: &transactionID, SplitMethod: "Equal",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	created := store.createdSharedExpense
+	if created == nil || created.Description != "Dinner at Bistro" || created.Amount != "90.00" || created.Date != "2026-07-10" ||
+		created.PaidByMemberID != 1 || created.SplitMethod != "equal" || len(created.Shares) != 3 || created.Shares[0].Amount != "30.00" {
+		t.Fatalf("created shared expense = %#v", created)
+	}
+
+	pending := 21
+	for name, request := range map[string]model.SharedExpenseRequest{
+		"transaction paid by someone else": {TransactionID: &transactionID, PaidByMemberID: 2, SplitMethod: "equal"},
+		"amount with transaction":          {TransactionID: &transactionID, Amount: "90", SplitMethod: "equal"},
+		"pending transaction":              {TransactionID: &pending, SplitMethod: "equal"},
+		"payer outside group":              {Description: "Taxi", Amount: "12", PaidByMemberID: 9, SplitMethod: "equal"},
+		"future date":                      {Description: "Taxi", Amount: "12", Date: "2026-07-19", SplitMethod: "equal"},
+		"missing description":              {Amount: "12", SplitMethod: "equal"},
+	} {
+		if _, err := service.AddSharedExpense(context.Background(), 7, 4, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+	store.expenseGroup.Status = "archived"
+	if _, err := service.AddSharedExpense(context.Background(), 7, 4, model.SharedExpenseRequest{
+		Description: "Taxi", Amount: "12", SplitMethod: "equal",
+	}); apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("archived group error = %v", err)
+	}
+}
+
+func TestAddExpenseSettlementChecksTransactionDirection(t *testing.T) {
+	store := &fakeStore{
+		expenseGroup: testExpenseGroup(),
+		getTransaction: func(_ context.Context, _ int, transactionID int) (model.Transaction, error) {
+			return model.Transaction{ID: transactionID, Type: "income", Status: "booked", Currency: "EUR",
+				Amount: "25.00", OccurredAt: "2026-07-12"}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC) }
+	transactionID := 30
+
+	if _, err := service.AddExpenseSettlement(context.Background(), 7, 4, model.ExpenseSettlementRequest{
+		FromMemberID: 2, ToMemberID: 1, TransactionID: &transactionID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if created := store.createdExpenseSettlement; created == nil || created.Amount != "25.00" || created.Date != "2026-07-12" {
+		t.Fatalf("created settlement = %#v", created)
+	}
+	for name, request := range map[string]model.ExpenseSettlementRequest{
+		"income for a payment you make": {FromMemberID: 1, ToMemberID: 2, TransactionID: &transactionID},
+		"transaction between others":    {FromMemberID: 2, ToMemberID: 3, TransactionID: &transactionID},
+		"same member":                   {FromMemberID: 2, ToMemberID: 2, Amount: "5"},
+		"member outside group":          {FromMemberID: 2, ToMemberID: 9, Amount: "5"},
+		"missing amount":                {FromMemberID: 2, ToMemberID: 3},
+	} {
+		if _, err := service.AddExpenseSettlement(context.Background(), 7, 4, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("%s: error = %v", name, err)
+		}
+	}
+}
+
+func TestExpenseGroupBalancesSimplifyDebts(t *testing.T) {
+	store := &fakeStore{
+		expenseGroup: testExpenseGroup(),
+		sharedExpenses: []model.SharedExpense{
+			{ID: 1, Amount: "90.00", PaidByMemberID: 1, Shares: []model.SharedExpenseShare{
+				{MemberID: 1, Amount: "30.00"}, {MemberID: 2, Amount: "30.00"}, {MemberID: 3, Amount: "30.00"},
+			}},
+			{ID: 2, Amount: "60.00", PaidByMemberID: 2, Shares: []model.SharedExpenseShare{
+				{MemberID: 1, Amount: "20.00"}, {MemberID: 3, Amount: "40.00"},
+			}},
+		},
+		expenseSettlements: []model.ExpenseSettlement{{ID: 1, FromMemberID: 3, ToMemberID: 1, Amount: "10.00"}},
+	}
+	balances, err := testService(store).GetExpenseGroupBalances(context.Background(), 7, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.ExpenseGroupMemberBalance{
+		{MemberID: 1, Name: "Me", Paid: "90.00", Share: "50.00", Sent: "0.00", Received: "10.00", Balance: "30.00"},
+		{MemberID: 2, Name: "Ana", Paid: "60.00", Share: "30.00", Sent: "0.00", Received: "0.00", Balance: "30.00"},
+		{MemberID: 3, Name: "Ben", Paid: "0.00", Share: "70.00", Sent: "10.00", Received: "0.00", Balance: "-60.00"},
+	}
+	if !reflect.DeepEqual(balances.Members, want) {
+		t.Fatalf("balances = %#v", balances.Members)
+	}
+	transfers := []model.ExpenseGroupTransfer{
+		{FromMemberID: 3, ToMemberID: 1, Amount: "30.00"}, {FromMemberID: 3, ToMemberID: 2, Amount: "30.00"},
+	}
+	if !reflect.DeepEqual(balances.Transfers, transfers) {
+		t.Fatalf("transfers = %#v", balances.Transfers)
+	}
+}
+
+func TestSimplifyDebtsNeedsFewTransfers(t *testing.T) {
+	transfers := simplifyDebts([]memberBalance{
+		{memberID: 1, cents: 5000}, {memberID: 2, cents: -2000}, {memberID: 3, cents: -3000},
+		{memberID: 4, cents: 1000}, {memberID: 5, cents: -1000},
+	})
+	want := []debtTransfer{{from: 3, to: 1, cents: 3000}, {from: 2, to: 1, cents: 2000}, {from: 5, to: 4, cents: 1000}}
+	if !reflect.DeepEqual(transfers, want) {
+		t.Fatalf("transfers = %#v", transfers)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/statement"
+)
+
+// ImportOFX imports OFX and QFX bank or credit-card statements. Entries are
+// fingerprinted from the account and the bank's FITID, which stays stable
+// across overlapping downloads.
+func (s *Service) ImportOFX(ctx context.Context, userID int, contents []byte) (model.StatementImportResult, error) {
+	rows, err := s.parseOFXImport(contents)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.importStatementRows(ctx, userID, rows, "ofx")
+}
+
+// ImportQIF imports bank, cash, and credit-card QIF files. QIF has no entry
+// identifiers, so identical entries are told apart by their order in the
+// file. An empty dateFormat detects the day and month order.
+func (s *Service) ImportQIF(ctx context.Context, userID int, contents []byte, dateFormat string) (model.StatementImportResult, error) {
+	rows, err := s.parseQIFImport(contents, dateFormat)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.importStatementRows(ctx, userID, rows, "qif")
+}
+
+// ImportCAMT imports ISO 20022 camt.053 statements and camt.054
+// notifications. Entries are fingerprinted from the account and the entry
+// reference. Booking status is handled like linked bank-account sync: pending
+// entries are imported, and entries dated after today are ignored until they
+// appear in a later statement.
+func (s *Service) ImportCAMT(ctx context.Context, userID int, contents []byte) (model.StatementImportResult, error) {
+	rows, err := s.parseCAMTImport(contents)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.importStatementRows(ctx, userID, rows, "camt")
+}
+
+// ImportMT940 imports SWIFT MT940 statements with the same fingerprinting and
+// date handling as camt files, using the bank reference of each entry.
+func (s *Service) ImportMT940(ctx context.Context, userID int, contents []byte) (model.StatementImportResult, error) {
+	rows, err := s.parseMT940Import(contents)
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.importStatementRows(ctx, userID, rows, "mt940")
+}
+
+func (s *Service) importStatementRows(ctx context.Context, userID int, rows []statementRow, source string) (model.StatementImportResult, error) {
+	prepared, err := s.prepareStatementImport(ctx, userID, rows, statementImportOptions{Source: source})
+	if err != nil {
+		return model.StatementImportResult{}, err
+	}
+	return s.storeStatementImport(ctx, userID, prepared)
+}
+
+func (s *Service) parseOFXImport(contents []byte) ([]statementRow, error) {
+	transactions, err := statement.ParseOFX(contents)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+	return s.statementRows(transactions, false)
+}
+
+func (s *Service) parseQIFImport(contents []byte, dateFormat string) ([]statementRow, error) {
+	layout := ""
+	if dateFormat = strings.ToUpper(strings.TrimSpace(dateFormat)); dateFormat != "" {
+		var ok bool
+		if layout, ok = csvImportDateLayout(dateFormat); !ok {
+			return nil, apperrors.Validation(
+				"date_format must be YYYY-MM-DD, DD.MM.YYYY, DD/MM/YYYY, DD-MM-YYYY, MM/DD/YYYY, YYYY/MM/DD, or DD.MM.YY",
+			)
+		}
+	}
+	transactions, err := statement.ParseQIF(contents, layout)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+	return s.statementRows(transactions, false)
+}
+
+func (s *Service) parseCAMTImport(contents []byte) ([]statementRow, error) {
+	transactions, err := statement.ParseCAMT(contents)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+	return s.statementRows(transactions, true)
+}
+
+func (s *Service) parseMT940Import(contents []byte) ([]statementRow, error) {
+	transactions, err := statement.ParseMT940(contents)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+	return s.statementRows(transactions, true)
+}
+
+// statementRows maps parsed statement entries onto the shared import
+// pipeline. Entries with a bank identifier are fingerprinted from it; the
+// rest fall back to their raw fields. Bank statements (excludeFuture) drop
+// entries dated after today like linked bank-account sync does.
+func (s *Service) statementRows(transactions []statement.Transaction, excludeFuture bool) ([]statementRow, error) {
+	if len(transactions) > maximumImportRows {
+		return nil, apperrors.Validation("statement contains more than 5000 transactions")
+	}
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	rows := make([]statementRow, 0, len(transactions))
+	for _, transaction := range transactions {
+		row := statementRow{
+			Line:        transaction.Line,
+			Amount:      transaction.Amount,
+			Currency:    firstNonEmpty(transaction.Currency, supportedCurrency),
+			Date:        transaction.Date,
+			Description: truncateRunes(transaction.Description, maximumDescriptionRunes),
+			Invalid:     transaction.Invalid,
+			Type:        "income",
+		}
+		if transaction.Debit {
+			row.Type = "expense"
+		}
+		if transaction.ID != "" {
+			row.Fingerprint = statementFingerprint(transaction.Account, transaction.ID)
+		} else {
+			row.Fingerprint = statementFingerprint(transaction.Raw...)
+		}
+		if excludeFuture && row.Date.After(today) {
+			row.Ignored = "transaction date is in the future"
+		}
+		rows = append(rows, row)
+	}
+	disambiguateFingerprints(rows)
+	return rows, nil
+}
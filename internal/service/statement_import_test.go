@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+)
+
+func TestImportOFXFingerprintsByFITIDAndReportsRejectedRows(t *testing.T) {
+	var imported []model.ImportedTransaction
+	store := &fakeStore{
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			imported = transactions
+			return len(transactions), 0, nil
+		},
+	}
+	contents := []byte("<OFX><BANKMSGSRSV1><STMTRS><CURDEF>EUR<BANKACCTFROM><ACCTID>ACC-1</BANKACCTFROM>" +
+		"<STMTTRN><DTPOSTED>20260711<TRNAMT>-12.50<FITID>F1<NAME>LIDL</STMTTRN>" +
+		"<STMTTRN><DTPOSTED>20260712<TRNAMT>-12.50<FITID>F2<NAME>LIDL</STMTTRN>" +
+		"<STMTTRN><DTPOSTED>20260713<TRNAMT>oops<FITID>F3<NAME>Broken</STMTTRN>" +
+		"<STMTTRN><DTPOSTED>20260714<TRNAMT>-3<FITID>F4<NAME>Shop<CURRENCY><CURSYM>USD</CURRENCY></STMTTRN>" +
+		"</STMTRS></BANKMSGSRSV1></OFX>")
+	result, err := testService(store).ImportOFX(context.Background(), 1, contents)
+	if err != nil {
+		t.Fatalf("ImportOFX() error = %v", err)
+	}
+	if result.Imported != 2 || result.Ignored != 1 || len(result.Rejected) != 1 || result.Rejected[0] != (model.ImportRejection{Row: 3, Reason: "has an invalid amount"}) {
+		t.Fatalf("ImportOFX() = %#v", result)
+	}
+	if imported[0].Source != "ofx" || imported[0].Fingerprint != statementFingerprint("ACC-1", "F1") ||
+		imported[0].Request.Type != "expense" || imported[0].Request.OccurredAt != "2026-07-11" {
+		t.Fatalf("imported rows = %#v", imported)
+	}
+
+	if _, err := testService(store).ImportOFX(context.Background(), 1, []byte("not a statement")); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("invalid OFX error = %v", err)
+	}
+}
+
+func TestImportQIFKeepsRepeatedEntries(t *testing.T) {
+	var imported []model.ImportedTransaction
+	store := &fakeStore{
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			imported = transactions
+			return len(transactions), 0, nil
+		},
+	}
+	contents := []byte("!Type:Bank\nD11.07.2026\nT-2,40\nPCoffee\n^\nD11.07.2026\nT-2,40\nPCoffee\n^\n")
+	result, err := testService(store).ImportQIF(context.Background(), 1, contents, "dd.mm.yyyy")
+	if err != nil || result.Imported != 2 {
+		t.Fatalf("ImportQIF() = %#v, %v", result, err)
+	}
+	if imported[0].Source != "qif" || imported[0].Fingerprint == imported[1].Fingerprint || imported[0].Request.Amount != "2.40" {
+		t.Fatalf("imported rows = %#v", imported)
+	}
+	if _, err := testService(store).ImportQIF(context.Background(), 1, contents, "YYYYMMDD"); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("invalid date format error = %v", err)
+	}
+}
+
+func TestImportCAMTIgnoresFutureEntriesWithoutFilteringStatus(t *testing.T) {
+	var imported []model.ImportedTransaction
+	store := &fakeStore{
+		findCategory: func(_ context.Context, _ int, _ string, name string) (string, error) { return name, nil },
+		importTransactions: func(_ context.Context, _ int, transactions []model.ImportedTransaction) (int, int, error) {
+			imported = transactions
+			return len(transactions), 0, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 13, 18, 0, 0, 0, time.UTC) }
+	contents := []byte(`<Document><BkToCstmrDbtCdtNtfctn><Ntfctn><Acct><Id><IBAN>BG80</IBAN></Id></Acct>
+<Ntry><NtryRef>N1</NtryRef><Amt Ccy="EUR">42.80</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>PDNG</Sts><BookgDt><Dt>2026-07-13</Dt></BookgDt>
+<NtryDtls><TxDtls><RltdPties><Cdtr><Nm>Fresh Market</Nm></Cdtr></RltdPties></TxDtls></NtryDtls></Ntry>
+<Ntry><NtryRef>N2</NtryRef><Amt Ccy="EUR">10</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts><BookgDt><Dt>2026-07-14</Dt></BookgDt></Ntry>
+</Ntfctn></BkToCstmrDbtCdtNtfctn></Document>`)
+	result, err := service.ImportCAMT(context.Background(), 1, contents)
+	if err != nil {
+		t.Fatalf("ImportCAMT() error = %v", err)
+	}
+	if result.Imported != 1 || result.Ignored != 1 || len(imported) != 1 {
+		t.Fatalf("ImportCAMT() = %#v", result)
+	}
+	if imported[0].Source != "camt" || imported[0].Fingerprint != statementFingerprint("BG80", "N1") ||
+		imported[0].Request.Description != "Fresh Market" || imported[0].Request.Type != "expense" {
+		t.Fatalf("imported rows = %#v", imported)
+	}
+}
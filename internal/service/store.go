@@ -18,8 +18,19 @@ type Store interface {
 	userStore
 	categoryStore
 	transactionStore
+	csvImportProfileStore
+	importPreviewStore
+	transactionDuplicateStore
+	merchantStore
+	categorizationRuleStore
+	categoryLearningStore
+	transactionExportStore
 	transactionScheduleStore
 	budgetStore
+	savingsGoalStore
+	loanStore
+	sharedExpenseStore
+	forecastStore
 	notificationStore
 	investmentStore
 	openBankingStore
@@ -40,20 +51,92 @@ type userStore interface {
 type categoryStore interface {
 	EnsureDefaultCategories(context.Context, int) error
 	ListCategories(context.Context, int, string) ([]model.Category, error)
+	GetCategory(context.Context, int, int) (model.Category, error)
 	CreateCategory(context.Context, int, model.CategoryRequest) (model.Category, error)
+	UpdateCategory(context.Context, int, int, model.CategoryUpdateRequest) (model.Category, error)
+	MergeCategories(context.Context, int, int, int) (model.CategoryMergeResult, error)
 	DeleteCategory(context.Context, int, int) error
 	FindActiveCategoryName(context.Context, int, string, string) (string, error)
 }
 
 type transactionStore interface {
 	ListTransactions(context.Context, int, repository.TransactionFilter) ([]model.Transaction, error)
-	ExportTransactions(context.Context, int, time.Time, time.Time, int) ([]model.Transaction, error)
+	StreamTransactions(context.Context, int, time.Time, time.Time, func(model.Transaction) error) error
+	StreamTransactionsWithOrigin(context.Context, int, time.Time, time.Time, func(model.Transaction, string) error) error
+	ListTransactionAccountUses(context.Context, int, time.Time, time.Time) ([]repository.TransactionAccountUse, error)
 	CreateTransaction(context.Context, int, model.TransactionRequest) (model.Transaction, error)
 	ImportTransactions(context.Context, int, []model.ImportedTransaction) (int, int, error)
 	GetTransaction(context.Context, int, int) (model.Transaction, error)
 	UpdateTransaction(context.Context, int, int, model.TransactionRequest) (model.Transaction, error)
 	DeleteTransaction(context.Context, int, int) error
+	BulkTransactions(context.Context, int, repository.BulkTransactionOperation) ([]model.BulkTransactionItemResult, error)
 	Summary(context.Context, int, string, time.Time, time.Time) (model.Summary, error)
+	CashFlowReport(context.Context, int, repository.ReportRange) (model.CashFlowReport, error)
+	CategoryReport(context.Context, int, string, repository.ReportRange) (model.CategoryReport, error)
+	MerchantReport(context.Context, int, repository.ReportRange, int) (model.MerchantReport, error)
+}
+
+type csvImportProfileStore interface {
+	ListCSVImportProfiles(context.Context, int) ([]model.CSVImportProfile, error)
+	GetCSVImportProfile(context.Context, int, int) (model.CSVImportProfile, error)
+	CreateCSVImportProfile(context.Context, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error)
+	UpdateCSVImportProfile(context.Context, int, int, model.CSVImportProfileRequest) (model.CSVImportProfile, error)
+	DeleteCSVImportProfile(context.Context, int, int) error
+}
+
+type importPreviewStore interface {
+	CreateImportPreview(context.Context, int, string, []repository.ImportPreviewRowRecord, time.Time) (repository.ImportPreviewRecord, error)
+	GetImportPreview(context.Context, int, int) (repository.ImportPreviewRecord, error)
+	DeleteImportPreview(context.Context, int, int) error
+	DeleteExpiredImportPreviews(context.Context, time.Time) (int, error)
+	ExistingImportFingerprints(context.Context, int, string, []string) ([]string, error)
+}
+
+type transactionDuplicateStore interface {
+	ListDuplicateCandidates(context.Context, int, time.Time, time.Time, int) ([]model.DuplicateTransactionPair, error)
+	MergeTransactions(context.Context, int, int, int) (model.TransactionMergeResult, error)
+	ListTransactionProvenance(context.Context, int, int) ([]model.TransactionProvenance, error)
+}
+
+type merchantStore interface {
+	ListMerchants(context.Context, int) ([]model.Merchant, error)
+	GetMerchant(context.Context, int, int) (model.Merchant, error)
+	RenameMerchant(context.Context, int, int, string) (model.Merchant, error)
+	MergeMerchants(context.Context, int, int, int) (model.Merchant, error)
+	ListMerchantCandidates(context.Context, int, int, int) ([]repository.MerchantCandidate, error)
+	AssignTransactionMerchants(context.Context, int, []repository.MerchantAssignment) error
+	ListMerchantSpending(context.Context, int, time.Time, time.Time) ([]model.MerchantSpending, error)
+	ListMerchantMonthlySpending(context.Context, int, int, time.Time, time.Time) ([]model.MerchantMonthSpending, error)
+}
+
+type categorizationRuleStore interface {
+	ListCategorizationRules(context.Context, int) ([]model.CategorizationRule, error)
+	ListActiveCategorizationRules(context.Context, int) ([]model.CategorizationRule, error)
+	GetCategorizationRule(context.Context, int, int) (model.CategorizationRule, error)
+	CreateCategorizationRule(context.Context, int, model.CategorizationRuleRequest) (model.CategorizationRule, error)
+	UpdateCategorizationRule(context.Context, int, int, model.CategorizationRuleRequest) (model.CategorizationRule, error)
+	DeleteCategorizationRule(context.Context, int, int) error
+	ListCategorizationRuleTargets(context.Context, int, time.Time, time.Time) ([]repository.CategorizationRuleTarget, error)
+	ApplyCategorizationRuleUpdates(context.Context, int, []repository.CategorizationRuleUpdate) (int, error)
+}
+
+type categoryLearningStore interface {
+	ListCategoryTrainingExamples(context.Context, int, int) ([]repository.CategoryTrainingExample, error)
+	ResetCategoryLearning(context.Context, int) (int, error)
+}
+
+type transactionExportStore interface {
+	CreateTransactionExport(context.Context, int, model.TransactionExportRequest, time.Time, int) (model.TransactionExport, error)
+	ListTransactionExports(context.Context, int) ([]model.TransactionExport, error)
+	GetTransactionExport(context.Context, int, int) (model.TransactionExport, error)
+	DeleteTransactionExport(context.Context, int, int) error
+	StreamTransactionExportFile(context.Context, int, func([]byte) error) error
+	ClaimTransactionExports(context.Context, time.Time, time.Time, int, int) ([]repository.TransactionExportJob, error)
+	AppendTransactionExportChunk(context.Context, int, int, []byte, time.Time) error
+	CompleteTransactionExport(context.Context, int, int, int64, time.Time) error
+	ReleaseTransactionExport(context.Context, int) error
+	FailTransactionExport(context.Context, int, string, bool) error
+	DeleteExpiredTransactionExports(context.Context, time.Time) (int, error)
 }
 
 type transactionScheduleStore interface {
@@ -67,8 +150,17 @@ type transactionScheduleStore interface {
 	UpsertTransactionScheduleOccurrences(context.Context, []repository.ScheduleOccurrenceSeed) (int, error)
 	MarkTransactionScheduleMaterializedThrough(context.Context, int, time.Time) error
 	ListTransactionScheduleOccurrences(context.Context, int, repository.ScheduleOccurrenceFilter) ([]model.TransactionScheduleOccurrence, error)
-	PostDueTransactionScheduleOccurrences(context.Context, time.Time, int) (int, error)
+	GetTransactionScheduleOccurrence(context.Context, int, int) (model.TransactionScheduleOccurrence, error)
+	UpdateTransactionScheduleOccurrence(context.Context, int, int, model.ScheduleOccurrenceRequest) (model.TransactionScheduleOccurrence, error)
+	SetTransactionScheduleOccurrenceStatus(context.Context, int, int, string, string) (model.TransactionScheduleOccurrence, error)
+	PostDueTransactionScheduleOccurrences(context.Context, time.Time, int) ([]int, error)
 	QueueDueTransactionScheduleReminders(context.Context, time.Time, int) (int, error)
+	ListUnreconciledTransactionScheduleOccurrences(context.Context, int, time.Time, time.Time) ([]model.TransactionScheduleOccurrence, error)
+	ListReconcilableBankTransactions(context.Context, int, time.Time, time.Time) ([]model.Transaction, error)
+	ReconcileTransactionScheduleOccurrence(context.Context, int, int, int) error
+	ListTransactionScheduleReconciliationUsers(context.Context) ([]int, error)
+	MarkTransactionSchedulesReconciled(context.Context, int, time.Time) error
+	QueueMissedTransactionScheduleOccurrences(context.Context, time.Time, int, int, int) (int, error)
 }
 
 type budgetStore interface {
@@ -78,6 +170,51 @@ type budgetStore interface {
 	UpdateBudget(context.Context, int, int, model.BudgetRequest, time.Time) (model.Budget, error)
 	ArchiveBudget(context.Context, int, int) error
 	QueueBudgetAlerts(context.Context, time.Time) (int, error)
+	BudgetHitRate(context.Context, int, time.Time, time.Time) (model.AnnualBudgets, error)
+}
+
+type savingsGoalStore interface {
+	ListSavingsGoals(context.Context, int, bool) ([]model.SavingsGoal, error)
+	GetSavingsGoal(context.Context, int, int) (model.SavingsGoal, error)
+	CreateSavingsGoal(context.Context, int, model.SavingsGoalRequest) (model.SavingsGoal, error)
+	UpdateSavingsGoal(context.Context, int, int, model.SavingsGoalRequest) (model.SavingsGoal, error)
+	ArchiveSavingsGoal(context.Context, int, int) error
+	ListSavingsGoalContributions(context.Context, int, int) ([]model.SavingsGoalContribution, error)
+	CreateSavingsGoalContribution(context.Context, int, int, model.SavingsGoalContributionRequest) (model.SavingsGoalContribution, error)
+	DeleteSavingsGoalContribution(context.Context, int, int, int) error
+	QueueSavingsGoalMilestones(context.Context) (int, error)
+}
+
+type loanStore interface {
+	ListLoans(context.Context, int, bool) ([]model.Loan, error)
+	GetLoan(context.Context, int, int) (model.Loan, error)
+	CreateLoan(context.Context, int, model.LoanRequest) (model.Loan, error)
+	UpdateLoan(context.Context, int, int, model.LoanRequest) (model.Loan, error)
+	ArchiveLoan(context.Context, int, int) error
+	ListLoanPayments(context.Context, int, int) ([]model.LoanPayment, error)
+	CreateLoanPayment(context.Context, int, int, model.LoanPaymentRequest) (model.LoanPayment, error)
+	DeleteLoanPayment(context.Context, int, int, int) error
+}
+
+type sharedExpenseStore interface {
+	ListExpenseGroups(context.Context, int, bool) ([]model.ExpenseGroup, error)
+	GetExpenseGroup(context.Context, int, int) (model.ExpenseGroup, error)
+	CreateExpenseGroup(context.Context, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error)
+	UpdateExpenseGroup(context.Context, int, int, model.ExpenseGroupRequest) (model.ExpenseGroup, error)
+	ArchiveExpenseGroup(context.Context, int, int) error
+	AddExpenseGroupMember(context.Context, int, int, model.ExpenseGroupMemberRequest) (model.ExpenseGroupMember, error)
+	DeleteExpenseGroupMember(context.Context, int, int, int) error
+	ListSharedExpenses(context.Context, int, int) ([]model.SharedExpense, error)
+	CreateSharedExpense(context.Context, int, int, model.SharedExpense) (model.SharedExpense, error)
+	DeleteSharedExpense(context.Context, int, int, int) error
+	ListExpenseSettlements(context.Context, int, int) ([]model.ExpenseSettlement, error)
+	CreateExpenseSettlement(context.Context, int, int, model.ExpenseSettlementRequest) (model.ExpenseSettlement, error)
+	DeleteExpenseSettlement(context.Context, int, int, int) error
+}
+
+type forecastStore interface {
+	GetForecastSettings(context.Context, int) (model.ForecastSettings, error)
+	UpdateForecastSettings(context.Context, int, model.ForecastSettings) (model.ForecastSettings, error)
 }
 
 type notificationStore interface {
@@ -101,13 +238,17 @@ type investmentStore interface {
 	CreateInvestmentSchedule(context.Context, int, model.InvestmentScheduleRequest) (model.InvestmentSchedule, error)
 	ListInvestmentSchedules(context.Context, int, string) ([]model.InvestmentSchedule, error)
 	GetInvestmentSchedule(context.Context, int, int) (model.InvestmentSchedule, error)
-	UpdateInvestmentSchedule(context.Context, int, int, model.InvestmentScheduleRequest) (model.InvestmentSchedule, error)
+	UpdateInvestmentSchedule(context.Context, int, int, model.InvestmentScheduleRequest, time.Time) (model.InvestmentSchedule, error)
 	SetInvestmentScheduleStatus(context.Context, int, int, string) error
 	ArchiveInvestmentSchedule(context.Context, int, int) error
 	ListActiveInvestmentSchedules(context.Context) ([]model.InvestmentSchedule, error)
 	UpsertInvestmentScheduleOccurrences(context.Context, []repository.InvestmentScheduleOccurrenceSeed) (int, error)
 	MarkInvestmentScheduleMaterializedThrough(context.Context, int, time.Time) error
 	ListDueInvestmentScheduleOccurrences(context.Context, time.Time, int) ([]repository.DueInvestmentScheduleOccurrence, error)
+	ListInvestmentScheduleOccurrences(context.Context, int, repository.ScheduleOccurrenceFilter) ([]model.InvestmentScheduleOccurrence, error)
+	GetInvestmentScheduleOccurrence(context.Context, int, int) (model.InvestmentScheduleOccurrence, error)
+	UpdateInvestmentScheduleOccurrence(context.Context, int, int, model.InvestmentScheduleOccurrenceRequest) (model.InvestmentScheduleOccurrence, error)
+	SetInvestmentScheduleOccurrenceStatus(context.Context, int, int, string, string) (model.InvestmentScheduleOccurrence, error)
 	PostInvestmentScheduleOccurrence(context.Context, int, model.InvestmentTradeRequest) (model.InvestmentTrade, bool, error)
 }
 
@@ -123,6 +264,8 @@ type openBankingStore interface {
 	DeleteOpenBankingConnection(context.Context, int, int) error
 	ListOpenBankingAccounts(context.Context, int) ([]model.OpenBankingAccount, error)
 	GetOpenBankingAccount(context.Context, int, int) (repository.OpenBankingAccountRecord, error)
+	RecordOpenBankingAccountBalances(context.Context, int, []model.OpenBankingAccountBalance) error
+	ListOpenBankingAccountBalances(context.Context, int, time.Time, time.Time) ([]model.OpenBankingAccountBalance, error)
 	ListOpenBankingProviderSessions(context.Context, int) ([]string, error)
 	ImportOpenBankingTransactions(context.Context, int, int, []repository.OpenBankingTransactionSeed, time.Time) (model.OpenBankingSyncResult, error)
 	ClaimOpenBankingAccountsForSync(context.Context, time.Time, time.Time, time.Time, int) ([]repository.OpenBankingSyncAccount, error)
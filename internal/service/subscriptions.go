@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/recurrence"
+)
+
+const (
+	subscriptionLookbackDays = 400
+	// subscriptionAmountTolerance is the relative difference from the latest
+	// charge within which an earlier charge still counts as the same plan.
+	subscriptionAmountTolerance = 10
+)
+
+// subscriptionPeriod is a billing cycle that can be detected. Gaps between
+// consecutive charges must fall within the day band, and a subscription whose
+// next charge is more than graceDays overdue is treated as cancelled.
+type subscriptionPeriod struct {
+	frequency      string
+	minimumDays    int
+	maximumDays    int
+	minimumCharges int
+	graceDays      int
+	chargesPerYear int64
+}
+
+var subscriptionPeriods = []subscriptionPeriod{
+	{frequency: "weekly", minimumDays: 6, maximumDays: 8, minimumCharges: 4, graceDays: 3, chargesPerYear: 52},
+	{frequency: "monthly", minimumDays: 27, maximumDays: 34, minimumCharges: 3, graceDays: 10, chargesPerYear: 12},
+	{frequency: "yearly", minimumDays: 358, maximumDays: 372, minimumCharges: 2, graceDays: 30, chargesPerYear: 1},
+}
+
+type subscriptionCharge struct {
+	date        time.Time
+	amount      *big.Rat
+	transaction model.Transaction
+}
+
+type detectedSubscription struct {
+	model.Subscription
+	rule recurrence.Rule
+	// charged is the sum of the charges in the detected run.
+	charged *big.Rat
+	// latestTransactionID is the row of the latest charge.
+	latestTransactionID int
+}
+
+// ListSubscriptions detects weekly, monthly, and yearly charges in the last
+// 400 days of booked expenses. Rows posted by a schedule are left out since
+// they are already planned.
+func (s *Service) ListSubscriptions(ctx context.Context, userID int) ([]model.Subscription, error) {
+	detected, _, err := s.detectSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.Subscription, 0, len(detected))
+	for _, item := range detected {
+		items = append(items, item.Subscription)
+	}
+	return items, nil
+}
+
+// ScheduleSubscription creates a transaction schedule from a detected
+// subscription, starting at its next charge on or after today.
+func (s *Service) ScheduleSubscription(
+	ctx context.Context,
+	userID int,
+	request model.SubscriptionScheduleRequest,
+) (model.TransactionSchedule, error) {
+	merchant := strings.TrimSpace(request.Merchant)
+	if merchant == "" {
+		return model.TransactionSchedule{}, apperrors.Validation("merchant is required")
+	}
+	detected, today, err := s.detectSubscriptions(ctx, userID)
+	if err != nil {
+		return model.TransactionSchedule{}, err
+	}
+	for _, item := range detected {
+		if item.Merchant != merchant {
+			continue
+		}
+		dates, err := recurrence.Occurrences(item.rule, today, today.AddDate(1, 1, 0))
+		if err != nil {
+			return model.TransactionSchedule{}, apperrors.Internal(fmt.Errorf("find next subscription charge: %w", err))
+		}
+		if len(dates) == 0 {
+			return model.TransactionSchedule{}, apperrors.Internal(fmt.Errorf("subscription %q has no upcoming charge", merchant))
+		}
+		name := strings.TrimSpace(request.Name)
+		if name == "" {
+			name = subscriptionScheduleName(item.Subscription)
+		}
+		schedule := model.TransactionScheduleRequest{
+			Type: "expense", Name: name, Category: item.Category, Description: item.Description,
+			Amount: item.Amount, Currency: item.Currency, Frequency: item.rule.Frequency,
+			FrequencyInterval: item.rule.Interval, StartDate: dates[0].Format("2006-01-02"), AutoPost: request.AutoPost,
+		}
+		if item.rule.Frequency == "weekly" {
+			schedule.DayOfWeek = &item.rule.DayOfWeek
+		} else {
+			schedule.DayOfMonth = &item.rule.DayOfMonth
+		}
+		return s.CreateTransactionSchedule(ctx, userID, schedule)
+	}
+	return model.TransactionSchedule{}, apperrors.NotFound("subscription not found")
+}
+
+func (s *Service) detectSubscriptions(ctx context.Context, userID int) ([]detectedSubscription, time.Time, error) {
+	today, err := scheduleLocalDate(s.now(), defaultScheduleTimezone)
+	if err != nil {
+		return nil, time.Time{}, apperrors.Internal(err)
+	}
+	groups, err := s.subscriptionCharges(ctx, userID, today.AddDate(0, 0, -subscriptionLookbackDays), today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	detected := make([]detectedSubscription, 0)
+	for merchant, charges := range groups {
+		if item, ok := detectSubscription(merchant, charges, today); ok {
+			detected = append(detected, item)
+		}
+	}
+	annualCosts := make(map[string]*big.Rat, len(detected))
+	for _, item := range detected {
+		annualCosts[item.Merchant], _ = new(big.Rat).SetString(item.AnnualCost)
+	}
+	sort.Slice(detected, func(i, j int) bool {
+		if order := annualCosts[detected[i].Merchant].Cmp(annualCosts[detected[j].Merchant]); order != 0 {
+			return order > 0
+		}
+		return detected[i].Merchant < detected[j].Merchant
+	})
+	return detected, today, nil
+}
+
+// subscriptionCharges groups the booked expenses in [from, to) by merchant.
+func (s *Service) subscriptionCharges(ctx context.Context, userID int, from, to time.Time) (map[string][]subscriptionCharge, error) {
+	keys, err := s.loadMerchantKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]subscriptionCharge)
+	err = s.store.StreamTransactions(ctx, userID, from, to, func(transaction model.Transaction) error {
+		if transaction.Type != "expense" || transaction.Currency != supportedCurrency || transaction.ScheduleOccurrenceID != nil {
+			return nil
+		}
+		merchant := keys.key(transaction.MerchantID, transaction.Description)
+		date, err := time.Parse(time.DateOnly, transaction.OccurredAt)
+		amount, ok := new(big.Rat).SetString(transaction.Amount)
+		if merchant == "" || err != nil || !ok {
+			return nil
+		}
+		groups[merchant] = append(groups[merchant], subscriptionCharge{date: date, amount: amount, transaction: transaction})
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("stream subscription history: %w", err))
+	}
+	return groups, nil
+}
+
+// detectSubscription looks for a run of evenly spaced charges ending at the
+// latest one. Charges more than 10% away from the latest amount are ignored,
+// so one-off purchases at the same merchant do not break the run and a price
+// change starts a new one. A zero today keeps subscriptions that have since
+// been cancelled.
+func detectSubscription(merchant string, charges []subscriptionCharge, today time.Time) (detectedSubscription, bool) {
+	if len(charges) < 2 {
+		return detectedSubscription{}, false
+	}
+	sort.SliceStable(charges, func(i, j int) bool { return charges[i].date.Before(charges[j].date) })
+	latest := charges[len(charges)-1]
+	tolerance := new(big.Rat).Mul(latest.amount, big.NewRat(subscriptionAmountTolerance, 100))
+	run := []subscriptionCharge{latest}
+	for index := len(charges) - 2; index >= 0; index-- {
+		charge := charges[index]
+		difference := new(big.Rat).Sub(charge.amount, latest.amount)
+		if difference.Abs(difference).Cmp(tolerance) > 0 || !charge.date.Before(run[len(run)-1].date) {
+			continue
+		}
+		run = append(run, charge)
+	}
+	var period subscriptionPeriod
+	found := false
+	for _, candidate := range subscriptionPeriods {
+		if gap := chargeGap(run[1], run[0]); gap >= candidate.minimumDays && gap <= candidate.maximumDays {
+			period, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return detectedSubscription{}, false
+	}
+	length := 2
+	for length < len(run) {
+		if gap := chargeGap(run[length], run[length-1]); gap < period.minimumDays || gap > period.maximumDays {
+			break
+		}
+		length++
+	}
+	run = run[:length]
+	if len(run) < period.minimumCharges {
+		return detectedSubscription{}, false
+	}
+
+	rule := recurrence.Rule{Frequency: "monthly", Interval: 1, StartDate: latest.date}
+	switch period.frequency {
+	case "weekly":
+		rule.Frequency, rule.DayOfWeek = "weekly", isoWeekday(latest.date)
+	case "monthly":
+		rule.DayOfMonth = subscriptionDayOfMonth(run)
+	case "yearly":
+		rule.Interval, rule.DayOfMonth = 12, latest.date.Day()
+	}
+	dates, err := recurrence.Occurrences(rule, latest.date.AddDate(0, 0, 1), latest.date.AddDate(1, 1, 0))
+	if err != nil || len(dates) == 0 {
+		return detectedSubscription{}, false
+	}
+	next := dates[0]
+	if next.AddDate(0, 0, period.graceDays).Before(today) {
+		return detectedSubscription{}, false
+	}
+	annualCost := new(big.Rat).Mul(latest.amount, new(big.Rat).SetInt64(period.chargesPerYear))
+	charged := new(big.Rat)
+	for _, charge := range run {
+		charged.Add(charged, charge.amount)
+	}
+	return detectedSubscription{
+		Subscription: model.Subscription{
+			Merchant:         merchant,
+			Description:      latest.transaction.Description,
+			Category:         latest.transaction.Category,
+			Frequency:        period.frequency,
+			Amount:           formatRat(latest.amount, 2),
+			Currency:         latest.transaction.Currency,
+			Charges:          len(run),
+			FirstChargeDate:  run[len(run)-1].date.Format("2006-01-02"),
+			LastChargeDate:   latest.date.Format("2006-01-02"),
+			NextExpectedDate: next.Format("2006-01-02"),
+			AnnualCost:       formatRat(annualCost, 2),
+		},
+		rule:                rule,
+		charged:             charged,
+		latestTransactionID: latest.transaction.ID,
+	}, true
+}
+
+func chargeGap(earlier, later subscriptionCharge) int {
+	return int(later.date.Sub(earlier.date).Hours() / 24)
+}
+
+// subscriptionDayOfMonth is the most common billing day of a run. Ties go to
+// the later day, since a charge on the 31st lands earlier in short months.
+func subscriptionDayOfMonth(run []subscriptionCharge) int {
+	counts := make(map[int]int)
+	best := 0
+	for _, charge := range run {
+		day := charge.date.Day()
+		counts[day]++
+		if counts[day] > counts[best] || counts[day] == counts[best] && day > best {
+			best = day
+		}
+	}
+	return best
+}
+
+func subscriptionScheduleName(subscription model.Subscription) string {
+	name := strings.Join(strings.Fields(subscription.Description), " ")
+	if name == "" {
+		name = subscription.Merchant
+	}
+	if utf8.RuneCountInString(name) > maximumScheduleNameRunes {
+		name = string([]rune(name)[:maximumScheduleNameRunes])
+	}
+	return name
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func subscriptionHistoryStore(t *testing.T) *fakeStore {
+	t.Helper()
+	scheduled := 4
+	history := []model.Transaction{
+		{ID: 1, Type: "expense", Category: "Fun", Description: "Spotify P1234", Amount: "9.99", Currency: "EUR", OccurredAt: "2026-01-03"},
+		{ID: 2, Type: "expense", Category: "Fun", Description: "Spotify P1235", Amount: "9.99", Currency: "EUR", OccurredAt: "2026-02-03"},
+		{ID: 3, Type: "expense", Category: "Fun", Description: "Spotify P1236", Amount: "9.99", Currency: "EUR", OccurredAt: "2026-03-03"},
+		{ID: 4, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 4829", Amount: "12.99", Currency: "EUR", OccurredAt: "2026-05-15"},
+		{ID: 5, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 4829", Amount: "12.99", Currency: "EUR", OccurredAt: "2026-06-15"},
+		{ID: 6, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 4829", Amount: "15.49", Currency: "EUR", OccurredAt: "2026-07-15"},
+		{ID: 7, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 4829", Amount: "15.49", Currency: "EUR", OccurredAt: "2026-08-14"},
+		{ID: 8, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 9911", Amount: "50.00", Currency: "EUR", OccurredAt: "2026-08-20"},
+		{ID: 9, Type: "expense", Category: "Fun", Description: "NETFLIX.COM 4829", Amount: "15.49", Currency: "EUR", OccurredAt: "2026-09-15"},
+		{ID: 10, Type: "expense", Category: "Fun", Description: "Netflix.com 5120", Amount: "15.49", Currency: "EUR", OccurredAt: "2026-10-15"},
+		{ID: 11, Type: "expense", Category: "Health", Description: "Gym membership", Amount: "10.00", Currency: "EUR", OccurredAt: "2026-09-21"},
+		{ID: 12, Type: "expense", Category: "Health", Description: "Gym membership", Amount: "10.00", Currency: "EUR", OccurredAt: "2026-09-28"},
+		{ID: 13, Type: "expense", Category: "Health", Description: "Gym membership", Amount: "10.00", Currency: "EUR", OccurredAt: "2026-10-05"},
+		{ID: 14, Type: "expense", Category: "Health", Description: "Gym membership", Amount: "10.50", Currency: "EUR", OccurredAt: "2026-10-12"},
+		{ID: 15, Type: "expense", Category: "Web", Description: "Domain renewal", Amount: "12.00", Currency: "EUR", OccurredAt: "2025-09-25"},
+		{ID: 16, Type: "expense", Category: "Web", Description: "Domain renewal", Amount: "12.00", Currency: "EUR", OccurredAt: "2026-09-25"},
+		{ID: 17, Type: "expense", Category: "Housing", Description: "Rent", Amount: "900.00", Currency: "EUR", OccurredAt: "2026-09-01", ScheduleOccurrenceID: &scheduled},
+		{ID: 18, Type: "expense", Category: "Housing", Description: "Rent", Amount: "900.00", Currency: "EUR", OccurredAt: "2026-10-01", ScheduleOccurrenceID: &scheduled},
+		{ID: 19, Type: "expense", Category: "Food", Description: "Grocery store", Amount: "41.20", Currency: "EUR", OccurredAt: "2026-09-02"},
+		{ID: 20, Type: "expense", Category: "Food", Description: "Grocery store", Amount: "39.80", Currency: "EUR", OccurredAt: "2026-09-13"},
+		{ID: 21, Type: "income", Category: "Salary", Description: "Payroll", Amount: "3000.00", Currency: "EUR", OccurredAt: "2026-09-30"},
+	}
+	return &fakeStore{
+		streamTransactions: func(_ context.Context, userID int, from, to time.Time, visit func(model.Transaction) error) error {
+			if userID != 7 || from.Format("2006-01-02") != "2025-09-13" || to.Format("2006-01-02") != "2026-10-19" {
+				t.Fatalf("stream = %d %s %s", userID, from, to)
+			}
+			for _, transaction := range history {
+				if err := visit(transaction); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestListSubscriptionsDetectsPeriodicCharges(t *testing.T) {
+	service := testService(subscriptionHistoryStore(t))
+	service.now = func() time.Time { return time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC) }
+
+	items, err := service.ListSubscriptions(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	want := []model.Subscription{
+		{
+			Merchant: "gym membership", Description: "Gym membership", Category: "Health", Frequency: "weekly",
+			Amount: "10.50", Currency: "EUR", Charges: 4, FirstChargeDate: "2026-09-21", LastChargeDate: "2026-10-12",
+			NextExpectedDate: "2026-10-19", AnnualCost: "546.00",
+		},
+		{
+			Merchant: "netflix.com", Description: "Netflix.com 5120", Category: "Fun", Frequency: "monthly",
+			Amount: "15.49", Currency: "EUR", Charges: 4, FirstChargeDate: "2026-07-15", LastChargeDate: "2026-10-15",
+			NextExpectedDate: "2026-11-15", AnnualCost: "185.88",
+		},
+		{
+			Merchant: "domain renewal", Description: "Domain renewal", Category: "Web", Frequency: "yearly",
+			Amount: "12.00", Currency: "EUR", Charges: 2, FirstChargeDate: "2025-09-25", LastChargeDate: "2026-09-25",
+			NextExpectedDate: "2027-09-25", AnnualCost: "12.00",
+		},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("subscriptions = %#v", items)
+	}
+	for index := range want {
+		if items[index] != want[index] {
+			t.Errorf("subscription %d = %#v, want %#v", index, items[index], want[index])
+		}
+	}
+}
+
+func TestScheduleSubscriptionCreatesScheduleFromNextCharge(t *testing.T) {
+	store := subscriptionHistoryStore(t)
+	store.findCategory = func(context.Context, int, string, string) (string, error) { return "Fun", nil }
+	store.createTransactionSchedule = func(_ context.Context, userID int, request model.TransactionScheduleRequest) (model.TransactionSchedule, error) {
+		if request.Type != "expense" || request.Name != "Netflix.com 5120" || request.Category != "Fun" || request.Amount != "15.49" {
+			t.Fatalf("schedule request = %#v", request)
+		}
+		if request.Frequency != "monthly" || request.FrequencyInterval != 1 || request.StartDate != "2026-11-15" ||
+			request.DayOfMonth == nil || *request.DayOfMonth != 15 || request.DayOfWeek != nil || !request.AutoPost {
+			t.Fatalf("schedule recurrence = %#v", request)
+		}
+		return model.TransactionSchedule{
+			ID: 12, UserID: userID, Type: request.Type, Name: request.Name, Frequency: request.Frequency,
+			FrequencyInterval: request.FrequencyInterval, StartDate: request.StartDate, DayOfMonth: request.DayOfMonth,
+			Timezone: request.Timezone, Status: "active",
+		}, nil
+	}
+	store.upsertScheduleOccurrences = func(_ context.Context, seeds []repository.ScheduleOccurrenceSeed) (int, error) {
+		return len(seeds), nil
+	}
+	store.markScheduleMaterialized = func(context.Context, int, time.Time) error { return nil }
+	store.getTransactionSchedule = func(_ context.Context, userID, scheduleID int, _ time.Time) (model.TransactionSchedule, error) {
+		return model.TransactionSchedule{ID: scheduleID, UserID: userID, Name: "Netflix.com 5120", Status: "active"}, nil
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC) }
+
+	schedule, err := service.ScheduleSubscription(context.Background(), 7, model.SubscriptionScheduleRequest{
+		Merchant: "netflix.com", AutoPost: true,
+	})
+	if err != nil || schedule.ID != 12 {
+		t.Fatalf("ScheduleSubscription() = %#v, %v", schedule, err)
+	}
+
+	_, err = service.ScheduleSubscription(context.Background(), 7, model.SubscriptionScheduleRequest{Merchant: "spotify"})
+	if apperrors.KindOf(err) != apperrors.KindNotFound {
+		t.Fatalf("lapsed subscription error = %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	maximumBulkTransactions = 1000
+	maximumBulkTags         = 10
+	maximumTagRunes         = 40
+)
+
+// BulkTransactions applies one action to a bounded selection of transactions.
+// The repository runs the whole batch atomically; rows that were already in
+// the requested state are reported as unchanged rather than rewritten.
+func (s *Service) BulkTransactions(ctx context.Context, userID int, request model.BulkTransactionRequest) (model.BulkTransactionResult, error) {
+	operation, err := s.validateBulkTransactions(ctx, userID, request)
+	if err != nil {
+		return model.BulkTransactionResult{}, err
+	}
+	items, err := s.store.BulkTransactions(ctx, userID, operation)
+	if errors.Is(err, repository.ErrLimitExceeded) {
+		return model.BulkTransactionResult{}, apperrors.Validation(
+			fmt.Sprintf("bulk operation matches more than %d transactions; narrow the filter", maximumBulkTransactions),
+		)
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.BulkTransactionResult{}, apperrors.Validation(
+			fmt.Sprintf("transactions can have at most %d tags", repository.MaximumTransactionTags),
+		)
+	}
+	if err != nil {
+		return model.BulkTransactionResult{}, apperrors.Internal(fmt.Errorf("apply bulk transaction action: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	result := model.BulkTransactionResult{Action: operation.Action, Items: items}
+	for _, item := range items {
+		if item.Status == "not_found" {
+			continue
+		}
+		result.Matched++
+		if item.Status != "unchanged" {
+			result.Changed++
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) validateBulkTransactions(ctx context.Context, userID int, request model.BulkTransactionRequest) (repository.BulkTransactionOperation, error) {
+	operation := repository.BulkTransactionOperation{
+		Action: strings.ToLower(strings.TrimSpace(request.Action)),
+		Limit:  maximumBulkTransactions,
+	}
+	switch {
+	case len(request.IDs) > 0 && request.Filter != nil:
+		return repository.BulkTransactionOperation{}, apperrors.Validation("provide either ids or filter, not both")
+	case len(request.IDs) > maximumBulkTransactions:
+		return repository.BulkTransactionOperation{}, apperrors.Validation(
+			fmt.Sprintf("ids must contain %d transactions or fewer", maximumBulkTransactions),
+		)
+	case len(request.IDs) > 0:
+		operation.IDs = make([]int, 0, len(request.IDs))
+		for _, id := range request.IDs {
+			if err := validateID(id); err != nil {
+				return repository.BulkTransactionOperation{}, err
+			}
+			if !slices.Contains(operation.IDs, id) {
+				operation.IDs = append(operation.IDs, id)
+			}
+		}
+	case request.Filter != nil:
+		filter, err := transactionFilter(request.Filter.Month, request.Filter.Type, request.Filter.Category)
+		if err != nil {
+			return repository.BulkTransactionOperation{}, err
+		}
+		operation.Filter = &filter
+	default:
+		return repository.BulkTransactionOperation{}, apperrors.Validation("ids or filter is required")
+	}
+
+	switch operation.Action {
+	case "recategorize":
+		transactionType, err := normalizeTransactionType(request.Type)
+		if err != nil {
+			return repository.BulkTransactionOperation{}, err
+		}
+		category, err := normalizeLimitedText(request.Category, "category", maximumCategoryRunes, false)
+		if err != nil {
+			return repository.BulkTransactionOperation{}, err
+		}
+		operation.Type = transactionType
+		operation.Category, err = s.store.FindActiveCategoryName(ctx, userID, transactionType, category)
+		if errors.Is(err, repository.ErrNotFound) {
+			return repository.BulkTransactionOperation{}, apperrors.Validation("category must be active and match the transaction type")
+		}
+		if err != nil {
+			return repository.BulkTransactionOperation{}, apperrors.Internal(fmt.Errorf("validate category: %w", err))
+		}
+	case "set_excluded_from_budget":
+		if request.ExcludedFromBudget == nil {
+			return repository.BulkTransactionOperation{}, apperrors.Validation("excluded_from_budget is required")
+		}
+		operation.ExcludedFromBudget = *request.ExcludedFromBudget
+	case "tag":
+		tags, err := normalizeTags(request.Tags)
+		if err != nil {
+			return repository.BulkTransactionOperation{}, err
+		}
+		if len(tags) == 0 {
+			return repository.BulkTransactionOperation{}, apperrors.Validation("tags is required")
+		}
+		operation.Tags = tags
+	case "delete":
+	default:
+		return repository.BulkTransactionOperation{}, apperrors.Validation(
+			"action must be recategorize, set_excluded_from_budget, tag, or delete",
+		)
+	}
+	return operation, nil
+}
+
+// normalizeTags lower-cases and de-duplicates tags so the same label typed
+// with different casing does not fragment filters.
+func normalizeTags(values []string) ([]string, error) {
+	if len(values) > maximumBulkTags {
+		return nil, apperrors.Validation(fmt.Sprintf("tags must contain %d values or fewer", maximumBulkTags))
+	}
+	tags := make([]string, 0, len(values))
+	for _, value := range values {
+		tag, err := normalizeLimitedText(value, "tag", maximumTagRunes, false)
+		if err != nil {
+			return nil, err
+		}
+		tag = strings.ToLower(tag)
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
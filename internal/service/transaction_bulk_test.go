@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestBulkTransactionsRecategorizesByIDsAndCountsItems(t *testing.T) {
+	store := &fakeStore{
+		findCategory: func(_ context.Context, _ int, transactionType, name string) (string, error) {
+			if transactionType != "expense" || name != "groceries" {
+				t.Fatalf("category lookup = %q/%q", transactionType, name)
+			}
+			return "Groceries", nil
+		},
+		bulkTransactions: func(_ context.Context, userID int, operation repository.BulkTransactionOperation) ([]model.BulkTransactionItemResult, error) {
+			if userID != 7 || operation.Action != "recategorize" || operation.Type != "expense" || operation.Category != "Groceries" {
+				t.Fatalf("unexpected operation: %#v", operation)
+			}
+			if !slices.Equal(operation.IDs, []int{3, 4, 5}) || operation.Filter != nil {
+				t.Fatalf("selection = %#v/%#v", operation.IDs, operation.Filter)
+			}
+			return []model.BulkTransactionItemResult{
+				{ID: 3, Status: "updated"}, {ID: 4, Status: "unchanged"}, {ID: 5, Status: "not_found"},
+			}, nil
+		},
+	}
+	result, err := testService(store).BulkTransactions(context.Background(), 7, model.BulkTransactionRequest{
+		Action: " Recategorize ", IDs: []int{3, 4, 3, 5}, Type: "expense", Category: "groceries",
+	})
+	if err != nil || result.Matched != 2 || result.Changed != 1 || len(result.Items) != 3 {
+		t.Fatalf("BulkTransactions() = %#v, %v", result, err)
+	}
+}
+
+func TestBulkTransactionsNormalizesTagsAndFilter(t *testing.T) {
+	store := &fakeStore{
+		bulkTransactions: func(_ context.Context, _ int, operation repository.BulkTransactionOperation) ([]model.BulkTransactionItemResult, error) {
+			if !slices.Equal(operation.Tags, []string{"holiday", "spain"}) {
+				t.Fatalf("tags = %#v", operation.Tags)
+			}
+			if operation.Filter == nil || operation.Filter.Type != "expense" || operation.Limit != maximumBulkTransactions {
+				t.Fatalf("filter = %#v limit = %d", operation.Filter, operation.Limit)
+			}
+			return nil, repository.ErrLimitExceeded
+		},
+	}
+	_, err := testService(store).BulkTransactions(context.Background(), 7, model.BulkTransactionRequest{
+		Action: "tag", Filter: &model.BulkTransactionFilter{Month: "2026-07", Type: "Expense"},
+		Tags: []string{" Spain", "holiday", "SPAIN"},
+	})
+	if apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("limit error = %v", err)
+	}
+}
+
+func TestBulkTransactionsRejectsInvalidSelectionsAndActions(t *testing.T) {
+	excluded := true
+	tests := []model.BulkTransactionRequest{
+		{Action: "delete"},
+		{Action: "delete", IDs: []int{1}, Filter: &model.BulkTransactionFilter{Month: "2026-07"}},
+		{Action: "delete", IDs: []int{0}},
+		{Action: "delete", Filter: &model.BulkTransactionFilter{Month: "July"}},
+		{Action: "archive", IDs: []int{1}},
+		{Action: "set_excluded_from_budget", IDs: []int{1}},
+		{Action: "tag", IDs: []int{1}},
+		{Action: "recategorize", IDs: []int{1}, Type: "transfer", Category: "food"},
+		{Action: "recategorize", IDs: []int{1}, Type: "expense", Category: "missing", ExcludedFromBudget: &excluded},
+		{Action: "delete", IDs: make([]int, maximumBulkTransactions+1)},
+	}
+	for _, request := range tests {
+		service := testService(&fakeStore{})
+		if _, err := service.BulkTransactions(context.Background(), 1, request); apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("request %#v error = %v", request, err)
+		}
+	}
+}
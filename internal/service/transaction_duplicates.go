@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	defaultDuplicateRangeDays  = 90
+	maximumDuplicateRangeDays  = 366
+	maximumDuplicateCandidates = 200
+	minimumDuplicateScore      = 0.6
+	// Equal amounts on the same day are common for unrelated small payments,
+	// so a pair also needs its merchant words to overlap.
+	minimumDuplicateMerchantSimilarity = 0.2
+)
+
+// ListDuplicateTransactions finds rows that were probably recorded twice by
+// different sources, such as a manual entry later synced from the bank. The
+// range defaults to the last 90 days and pairs are ordered by confidence.
+func (s *Service) ListDuplicateTransactions(ctx context.Context, userID int, fromString, toString string) ([]model.DuplicateTransactionPair, error) {
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	if toString != "" {
+		parsed, err := parseDate(toString, "to")
+		if err != nil {
+			return nil, err
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultDuplicateRangeDays)
+	if fromString != "" {
+		parsed, err := parseDate(fromString, "from")
+		if err != nil {
+			return nil, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, apperrors.Validation("from must be before or equal to to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maximumDuplicateRangeDays {
+		return nil, apperrors.Validation(fmt.Sprintf("duplicate date range must be %d days or less", maximumDuplicateRangeDays))
+	}
+	candidates, err := s.store.ListDuplicateCandidates(ctx, userID, from, to.AddDate(0, 0, 1), maximumDuplicateCandidates)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list duplicate candidates: %w", err))
+	}
+	keys, err := s.loadMerchantKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]model.DuplicateTransactionPair, 0, len(candidates))
+	for _, pair := range candidates {
+		if len(pair.Transactions) != 2 {
+			continue
+		}
+		scoreDuplicatePair(&pair, keys)
+		if pair.Score >= minimumDuplicateScore && pair.MerchantSimilarity >= minimumDuplicateMerchantSimilarity {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Score > pairs[j].Score })
+	return pairs, nil
+}
+
+// MergeTransactions keeps one row of a duplicate pair and records the other
+// as its provenance.
+func (s *Service) MergeTransactions(ctx context.Context, userID int, request model.TransactionMergeRequest) (model.TransactionMergeResult, error) {
+	if err := validateID(request.KeepID); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	if err := validateID(request.MergeID); err != nil {
+		return model.TransactionMergeResult{}, err
+	}
+	if request.KeepID == request.MergeID {
+		return model.TransactionMergeResult{}, apperrors.Validation("keep_id and merge_id must be different transactions")
+	}
+	result, err := s.store.MergeTransactions(ctx, userID, request.KeepID, request.MergeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.TransactionMergeResult{}, apperrors.NotFound("transaction not found")
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return model.TransactionMergeResult{}, apperrors.Conflict(fmt.Sprintf(
+			"only unscheduled transactions with the same type and currency and at most %d combined tags can be merged",
+			repository.MaximumTransactionTags,
+		))
+	}
+	if err != nil {
+		return model.TransactionMergeResult{}, apperrors.Internal(fmt.Errorf("merge transactions: %w", err))
+	}
+	s.invalidateReports(ctx, userID)
+	return result, nil
+}
+
+func (s *Service) GetTransactionProvenance(ctx context.Context, userID, transactionID int) ([]model.TransactionProvenance, error) {
+	if err := validateID(transactionID); err != nil {
+		return nil, err
+	}
+	provenance, err := s.store.ListTransactionProvenance(ctx, userID, transactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, apperrors.NotFound("transaction not found")
+	}
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list transaction provenance: %w", err))
+	}
+	return provenance, nil
+}
+
+// scoreDuplicatePair weighs the exact amount match, how close the dates are,
+// and how alike the merchant names read once bank noise is stripped.
+func scoreDuplicatePair(pair *model.DuplicateTransactionPair, keys merchantKeys) {
+	first, second := pair.Transactions[0], pair.Transactions[1]
+	days := 0
+	firstDate, firstErr := time.Parse(time.DateOnly, first.OccurredAt)
+	secondDate, secondErr := time.Parse(time.DateOnly, second.OccurredAt)
+	if firstErr == nil && secondErr == nil {
+		days = int(math.Abs(secondDate.Sub(firstDate).Hours() / 24))
+	}
+	pair.DayDifference = days
+	pair.MerchantSimilarity = 1
+	if key := keys.key(first.MerchantID, first.Description); key == "" || key != keys.key(second.MerchantID, second.Description) {
+		pair.MerchantSimilarity = roundScore(merchantSimilarity(first.Description, second.Description))
+	}
+	score := 0.4 + 0.3*(1-float64(days)/4) + 0.3*pair.MerchantSimilarity
+	pair.Score = roundScore(score)
+}
+
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// merchantSimilarity is the Jaccard overlap of normalized merchant tokens.
+func merchantSimilarity(first, second string) float64 {
+	firstTokens, secondTokens := merchantTokens(first), merchantTokens(second)
+	if len(firstTokens) == 0 || len(secondTokens) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range firstTokens {
+		if secondTokens[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(firstTokens)+len(secondTokens)-shared)
+}
+
+// merchantTokens lowercases a description and keeps the words of three or
+// more letters, which drops card suffixes, store numbers, and dates.
+func merchantTokens(description string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if len([]rune(field)) >= 3 {
+			tokens[field] = true
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestListDuplicateTransactionsScoresAndFiltersPairs(t *testing.T) {
+	store := &fakeStore{
+		listDuplicateCandidates: func(_ context.Context, userID int, from, to time.Time, limit int) ([]model.DuplicateTransactionPair, error) {
+			if userID != 3 || from.Format(time.DateOnly) != "2026-04-20" || to.Format(time.DateOnly) != "2026-07-19" || limit != maximumDuplicateCandidates {
+				t.Fatalf("candidate query = %d %s %s %d", userID, from, to, limit)
+			}
+			return []model.DuplicateTransactionPair{
+				{
+					Transactions: []model.Transaction{
+						{ID: 1, Description: "LIDL 1234 Sofia", OccurredAt: "2026-07-10"},
+						{ID: 2, Description: "Card payment LIDL Sofia", OccurredAt: "2026-07-11"},
+					},
+					Origins: []string{"manual", "open_banking:4"},
+				},
+				{
+					Transactions: []model.Transaction{
+						{ID: 3, Description: "Rent", OccurredAt: "2026-07-01"},
+						{ID: 4, Description: "Lidl", OccurredAt: "2026-07-04"},
+					},
+					Origins: []string{"manual", "import:ofx"},
+				},
+				{
+					// Same day and amount, but a different merchant.
+					Transactions: []model.Transaction{
+						{ID: 7, Description: "Coffee", OccurredAt: "2026-07-08"},
+						{ID: 8, Description: "PARKING ZONE BLUE", OccurredAt: "2026-07-08"},
+					},
+					Origins: []string{"manual", "open_banking:4"},
+				},
+				{
+					Transactions: []model.Transaction{
+						{ID: 5, Description: "Netflix", OccurredAt: "2026-07-05"},
+						{ID: 6, Description: "NETFLIX.COM", OccurredAt: "2026-07-05"},
+					},
+					Origins: []string{"manual", "import:csv"},
+				},
+			}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 15, 0, 0, 0, time.UTC) }
+	pairs, err := service.ListDuplicateTransactions(context.Background(), 3, "", "")
+	if err != nil {
+		t.Fatalf("ListDuplicateTransactions() error = %v", err)
+	}
+	if len(pairs) != 2 || pairs[0].Transactions[0].ID != 5 || pairs[1].Transactions[0].ID != 1 {
+		t.Fatalf("ListDuplicateTransactions() = %#v", pairs)
+	}
+	if pairs[0].Score != 0.85 || pairs[0].MerchantSimilarity != 0.5 || pairs[0].DayDifference != 0 {
+		t.Fatalf("same-day pair = %#v", pairs[0])
+	}
+	if pairs[1].DayDifference != 1 || pairs[1].MerchantSimilarity != 0.5 {
+		t.Fatalf("next-day pair = %#v", pairs[1])
+	}
+
+	if _, err := service.ListDuplicateTransactions(context.Background(), 3, "2025-01-01", "2026-07-01"); apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("wide range error = %v", err)
+	}
+}
+
+func TestListDuplicateTransactionsMatchesRegistryMerchants(t *testing.T) {
+	merchantID := 3
+	store := &fakeStore{
+		listMerchants: func(context.Context, int) ([]model.Merchant, error) {
+			return []model.Merchant{{ID: merchantID, Name: "Kaufland", Aliases: []string{"kaufland"}}}, nil
+		},
+		listDuplicateCandidates: func(context.Context, int, time.Time, time.Time, int) ([]model.DuplicateTransactionPair, error) {
+			return []model.DuplicateTransactionPair{{
+				// The bank text shares no word with the manual entry, but it
+				// was linked to the merchant the manual entry names.
+				Transactions: []model.Transaction{
+					{ID: 1, Description: "Kaufland", OccurredAt: "2026-07-10"},
+					{ID: 2, Description: "POS 6120 VARNA", OccurredAt: "2026-07-10", MerchantID: &merchantID},
+				},
+				Origins: []string{"manual", "open_banking:4"},
+			}}, nil
+		},
+	}
+	service := testService(store)
+	service.now = func() time.Time { return time.Date(2026, 7, 18, 15, 0, 0, 0, time.UTC) }
+	pairs, err := service.ListDuplicateTransactions(context.Background(), 3, "", "")
+	if err != nil || len(pairs) != 1 || pairs[0].MerchantSimilarity != 1 || pairs[0].Score != 1 {
+		t.Fatalf("ListDuplicateTransactions() = %#v, %v", pairs, err)
+	}
+}
+
+func TestMergeTransactionsMapsStoreErrors(t *testing.T) {
+	store := &fakeStore{
+		mergeTransactions: func(_ context.Context, _ int, keepID, mergeID int) (model.TransactionMergeResult, error) {
+			switch {
+			case keepID == 9:
+				return model.TransactionMergeResult{}, repository.ErrNotFound
+			case mergeID == 8:
+				return model.TransactionMergeResult{}, repository.ErrConflict
+			}
+			return model.TransactionMergeResult{Transaction: model.Transaction{ID: keepID}}, nil
+		},
+	}
+	service := testService(store)
+	result, err := service.MergeTransactions(context.Background(), 3, model.TransactionMergeRequest{KeepID: 1, MergeID: 2})
+	if err != nil || result.Transaction.ID != 1 {
+		t.Fatalf("MergeTransactions() = %#v, %v", result, err)
+	}
+	cases := map[model.TransactionMergeRequest]apperrors.Kind{
+		{KeepID: 1, MergeID: 1}: apperrors.KindValidation,
+		{KeepID: 0, MergeID: 2}: apperrors.KindValidation,
+		{KeepID: 9, MergeID: 2}: apperrors.KindNotFound,
+		{KeepID: 1, MergeID: 8}: apperrors.KindConflict,
+	}
+	for request, kind := range cases {
+		if _, err := service.MergeTransactions(context.Background(), 3, request); apperrors.KindOf(err) != kind {
+			t.Errorf("MergeTransactions(%v) error = %v", request, err)
+		}
+	}
+}
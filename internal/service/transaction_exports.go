@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/export"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+const (
+	transactionExportTTL             = 7 * 24 * time.Hour
+	transactionExportClaimTTL        = 15 * time.Minute
+	transactionExportBatchSize       = 2
+	transactionExportChunkBytes      = 1 << 20
+	maximumActiveTransactionExports  = 3
+	maximumTransactionExportAttempts = 3
+)
+
+// ExportTransactions streams booked transactions in the requested format.
+// The request is validated before start is called, so a validation error
+// never follows a partially written file.
+func (s *Service) ExportTransactions(
+	ctx context.Context,
+	userID int,
+	request model.TransactionExportRequest,
+	start func(model.ExportFile) io.Writer,
+) error {
+	format, from, to, err := validateTransactionExport(&request)
+	if err != nil {
+		return err
+	}
+	encoder, err := format.NewEncoder(start(transactionExportFile(format, request)), export.Options{
+		From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Currency: supportedCurrency, GeneratedAt: s.now(),
+	})
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("start transaction export: %w", err))
+	}
+	if err := s.store.StreamTransactions(ctx, userID, from, to.AddDate(0, 0, 1), encoder.Encode); err != nil {
+		return apperrors.Internal(fmt.Errorf("stream transactions: %w", err))
+	}
+	if err := encoder.Close(); err != nil {
+		return apperrors.Internal(fmt.Errorf("finish transaction export: %w", err))
+	}
+	return nil
+}
+
+// CreateTransactionExport queues an export that the background worker writes
+// to a file. Large ledgers should use this instead of a streamed download.
+func (s *Service) CreateTransactionExport(ctx context.Context, userID int, request model.TransactionExportRequest) (model.TransactionExport, error) {
+	if _, _, _, err := validateTransactionExport(&request); err != nil {
+		return model.TransactionExport{}, err
+	}
+	item, err := s.store.CreateTransactionExport(
+		ctx, userID, request, s.now().Add(transactionExportTTL), maximumActiveTransactionExports,
+	)
+	if errors.Is(err, repository.ErrLimitExceeded) {
+		return model.TransactionExport{}, apperrors.Conflict(
+			fmt.Sprintf("at most %d exports can be queued at once", maximumActiveTransactionExports),
+		)
+	}
+	if err != nil {
+		return model.TransactionExport{}, apperrors.Internal(fmt.Errorf("create transaction export: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) ListTransactionExports(ctx context.Context, userID int) ([]model.TransactionExport, error) {
+	items, err := s.store.ListTransactionExports(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list transaction exports: %w", err))
+	}
+	return items, nil
+}
+
+func (s *Service) GetTransactionExport(ctx context.Context, userID, exportID int) (model.TransactionExport, error) {
+	if err := validateID(exportID); err != nil {
+		return model.TransactionExport{}, err
+	}
+	item, err := s.store.GetTransactionExport(ctx, userID, exportID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.TransactionExport{}, apperrors.NotFound("export not found or expired")
+	}
+	if err != nil {
+		return model.TransactionExport{}, apperrors.Internal(fmt.Errorf("get transaction export: %w", err))
+	}
+	return item, nil
+}
+
+func (s *Service) DeleteTransactionExport(ctx context.Context, userID, exportID int) error {
+	if err := validateID(exportID); err != nil {
+		return err
+	}
+	err := s.store.DeleteTransactionExport(ctx, userID, exportID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.NotFound("export not found")
+	}
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("delete transaction export: %w", err))
+	}
+	return nil
+}
+
+// DownloadTransactionExport streams the file of a completed export.
+func (s *Service) DownloadTransactionExport(
+	ctx context.Context,
+	userID, exportID int,
+	start func(model.ExportFile) io.Writer,
+) error {
+	item, err := s.GetTransactionExport(ctx, userID, exportID)
+	if err != nil {
+		return err
+	}
+	if item.Status != "completed" {
+		return apperrors.Conflict("export is " + item.Status + " and cannot be downloaded")
+	}
+	format, ok := export.Lookup(item.Format)
+	if !ok {
+		return apperrors.Internal(fmt.Errorf("export %d has unknown format %q", item.ID, item.Format))
+	}
+	w := start(transactionExportFile(format, model.TransactionExportRequest{From: item.From, To: item.To}))
+	err = s.store.StreamTransactionExportFile(ctx, item.ID, func(data []byte) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("stream transaction export: %w", err))
+	}
+	return nil
+}
+
+// RunTransactionExportMaintenance drops expired exports and writes the files
+// of queued exports. A job interrupted by shutdown keeps
+// its claim and is picked up again once the claim lapses; one that outlasts
+// the run's deadline counts as a failed attempt.
+func (s *Service) RunTransactionExportMaintenance(ctx context.Context) (model.TransactionExportMaintenanceResult, error) {
+	now := s.now().UTC()
+	expired, err := s.store.DeleteExpiredTransactionExports(ctx, now)
+	if err != nil {
+		return model.TransactionExportMaintenanceResult{}, apperrors.Internal(fmt.Errorf("delete expired transaction exports: %w", err))
+	}
+	result := model.TransactionExportMaintenanceResult{Expired: expired}
+	jobs, err := s.store.ClaimTransactionExports(
+		ctx, now, now.Add(transactionExportClaimTTL), transactionExportBatchSize, maximumTransactionExportAttempts,
+	)
+	if err != nil {
+		return result, apperrors.Internal(fmt.Errorf("claim transaction exports: %w", err))
+	}
+	result.Claimed = len(jobs)
+	var jobErrors []error
+	for index, job := range jobs {
+		if ctx.Err() != nil {
+			return result, s.stopTransactionExports(ctx, nil, jobs[index:])
+		}
+		rows, size, err := s.writeTransactionExport(ctx, job)
+		if err == nil {
+			err = s.store.CompleteTransactionExport(ctx, job.ID, rows, size, s.now().Add(transactionExportTTL))
+			if err == nil {
+				result.Completed++
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				result.Failed++
+			}
+			return result, s.stopTransactionExports(ctx, &job, jobs[index+1:])
+		}
+		jobErrors = append(jobErrors, fmt.Errorf("write transaction export %d: %w", job.ID, err))
+		if err := s.failTransactionExport(ctx, job, "export failed"); err != nil {
+			jobErrors = append(jobErrors, fmt.Errorf("fail transaction export %d: %w", job.ID, err))
+		}
+		result.Failed++
+	}
+	if len(jobErrors) > 0 {
+		return result, apperrors.Internal(errors.Join(jobErrors...))
+	}
+	return result, nil
+}
+
+// stopTransactionExports ends a run whose context is done. Claimed jobs that
+// never started go back to the queue. When the run hit its deadline, the job
+// being written counts as a failed attempt, so an export too large for one run
+// gives up after maximumTransactionExportAttempts instead of restarting until
+// it expires.
+func (s *Service) stopTransactionExports(
+	ctx context.Context,
+	running *repository.TransactionExportJob,
+	unstarted []repository.TransactionExportJob,
+) error {
+	cleanup := context.WithoutCancel(ctx)
+	stopErrors := []error{ctx.Err()}
+	if running != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if err := s.failTransactionExport(cleanup, *running, "export took too long"); err != nil {
+			stopErrors = append(stopErrors, fmt.Errorf("fail transaction export %d: %w", running.ID, err))
+		}
+	}
+	for _, job := range unstarted {
+		if err := s.store.ReleaseTransactionExport(cleanup, job.ID); err != nil {
+			stopErrors = append(stopErrors, fmt.Errorf("release transaction export %d: %w", job.ID, err))
+		}
+	}
+	return errors.Join(stopErrors...)
+}
+
+func (s *Service) failTransactionExport(ctx context.Context, job repository.TransactionExportJob, reason string) error {
+	final := job.Attempts >= maximumTransactionExportAttempts
+	message := reason + " and will be retried"
+	if final {
+		message = reason
+	}
+	return s.store.FailTransactionExport(ctx, job.ID, message, final)
+}
+
+func (s *Service) writeTransactionExport(ctx context.Context, job repository.TransactionExportJob) (int, int64, error) {
+	format, ok := export.Lookup(job.Format)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown format %q", job.Format)
+	}
+	file := &exportChunkWriter{ctx: ctx, service: s, exportID: job.ID}
+	encoder, err := format.NewEncoder(file, export.Options{
+		From: job.From.Format(time.DateOnly), To: job.To.Format(time.DateOnly), Currency: supportedCurrency, GeneratedAt: s.now(),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	rows := 0
+	if err := s.store.StreamTransactions(ctx, job.UserID, job.From, job.To.AddDate(0, 0, 1), func(transaction model.Transaction) error {
+		rows++
+		return encoder.Encode(transaction)
+	}); err != nil {
+		return 0, 0, err
+	}
+	if err := encoder.Close(); err != nil {
+		return 0, 0, err
+	}
+	if err := file.flush(); err != nil {
+		return 0, 0, err
+	}
+	return rows, file.size, nil
+}
+
+// exportChunkWriter stores an export file as fixed-size chunks, extending the
+// job's claim with every chunk.
+type exportChunkWriter struct {
+	ctx      context.Context
+	service  *Service
+	exportID int
+	buffer   []byte
+	sequence int
+	size     int64
+}
+
+func (w *exportChunkWriter) Write(data []byte) (int, error) {
+	written := 0
+	for written < len(data) {
+		if w.buffer == nil {
+			w.buffer = make([]byte, 0, transactionExportChunkBytes)
+		}
+		count := min(len(data)-written, transactionExportChunkBytes-len(w.buffer))
+		w.buffer = append(w.buffer, data[written:written+count]...)
+		written += count
+		if len(w.buffer) == transactionExportChunkBytes {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *exportChunkWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	claimUntil := w.service.now().Add(transactionExportClaimTTL)
+	if err := w.service.store.AppendTransactionExportChunk(w.ctx, w.exportID, w.sequence, w.buffer, claimUntil); err != nil {
+		return err
+	}
+	w.sequence++
+	w.size += int64(len(w.buffer))
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// validateTransactionExport normalizes the format and checks the date range.
+// Streaming keeps memory bounded, so ranges are not capped.
+func validateTransactionExport(request *model.TransactionExportRequest) (export.Format, time.Time, time.Time, error) {
+	request.Format = strings.ToLower(strings.TrimSpace(request.Format))
+	if request.Format == "" {
+		request.Format = "csv"
+	}
+	format, ok := export.Lookup(request.Format)
+	if !ok {
+		return export.Format{}, time.Time{}, time.Time{}, apperrors.Validation(
+			"format must be one of " + strings.Join(export.Names(), ", "),
+		)
+	}
+	from, err := parseDate(request.From, "from")
+	if err != nil {
+		return export.Format{}, time.Time{}, time.Time{}, err
+	}
+	to, err := parseDate(request.To, "to")
+	if err != nil {
+		return export.Format{}, time.Time{}, time.Time{}, err
+	}
+	if from.After(to) {
+		return export.Format{}, time.Time{}, time.Time{}, apperrors.Validation("from must be before or equal to to")
+	}
+	return format, from, to, nil
+}
+
+func transactionExportFile(format export.Format, request model.TransactionExportRequest) model.ExportFile {
+	return model.ExportFile{
+		Filename:    fmt.Sprintf("money-manager-%s-to-%s.%s", request.From, request.To, format.Extension),
+		ContentType: format.ContentType,
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"money-manager-server/internal/apperrors"
+	"money-manager-server/internal/model"
+	"money-manager-server/internal/repository"
+)
+
+func TestExportTransactionsStreamsMultiYearRanges(t *testing.T) {
+	store := &fakeStore{
+		streamTransactions: func(_ context.Context, userID int, from, to time.Time, visit func(model.Transaction) error) error {
+			if userID != 1 || from.Format(time.DateOnly) != "2019-01-01" || to.Format(time.DateOnly) != "2026-08-01" {
+				t.Fatalf("stream range = %d %s %s", userID, from, to)
+			}
+			for day := 1; day <= 3; day++ {
+				if err := visit(model.Transaction{ID: day, Type: "expense", OccurredAt: "2026-07-0" + string(rune('0'+day))}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	service := testService(store)
+	var output bytes.Buffer
+	var file model.ExportFile
+	err := service.ExportTransactions(context.Background(), 1, model.TransactionExportRequest{
+		From: "2019-01-01", To: "2026-07-31", Format: "JSONL",
+	}, func(started model.ExportFile) io.Writer {
+		file = started
+		return &output
+	})
+	if err != nil {
+		t.Fatalf("ExportTransactions() error = %v", err)
+	}
+	if file.Filename != "money-manager-2019-01-01-to-2026-07-31.jsonl" || file.ContentType != "application/x-ndjson" {
+		t.Fatalf("export file = %#v", file)
+	}
+	if lines := strings.Count(output.String(), "\n"); lines != 3 {
+		t.Fatalf("exported lines = %d: %s", lines, output.String())
+	}
+
+	invalid := []model.TransactionExportRequest{
+		{From: "2026-07-31", To: "2026-07-01"},
+		{From: "2026-07-01", To: "2026-07-31", Format: "xml"},
+		{From: "2026-07-01"},
+	}
+	for _, request := range invalid {
+		err := service.ExportTransactions(context.Background(), 1, request, func(model.ExportFile) io.Writer {
+			t.Fatalf("export %v started before validation", request)
+			return nil
+		})
+		if apperrors.KindOf(err) != apperrors.KindValidation {
+			t.Errorf("ExportTransactions(%v) error = %v", request, err)
+		}
+	}
+}
+
+func TestRunTransactionExportMaintenanceWritesChunksAndRetries(t *testing.T) {
+	var chunks [][]byte
+	var completed []int
+	var failed []bool
+	description := strings.Repeat("x", 400)
+	store := &fakeStore{
+		claimTransactionExports: func(
+			_ context.Context, now, claimUntil time.Time, limit, maximumAttempts int,
+		) ([]repository.TransactionExportJob, error) {
+			if !claimUntil.After(now) || limit != transactionExportBatchSize || maximumAttempts != maximumTransactionExportAttempts {
+				t.Fatalf("claim = %s %s %d", now, claimUntil, limit)
+			}
+			from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			return []repository.TransactionExportJob{
+				{ID: 1, UserID: 3, Format: "csv", From: from, To: from.AddDate(6, 0, 0), Attempts: 1},
+				{ID: 2, UserID: 4, Format: "csv", From: from, To: from, Attempts: 1},
+				{ID: 3, UserID: 4, Format: "csv", From: from, To: from, Attempts: maximumTransactionExportAttempts},
+			}, nil
+		},
+		streamTransactions: func(_ context.Context, userID int, _, _ time.Time, visit func(model.Transaction) error) error {
+			if userID == 4 {
+				return errors.New("database went away")
+			}
+			for index := range 3000 {
+				if err := visit(model.Transaction{ID: index, Description: description}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		appendTransactionExportChunk: func(_ context.Context, exportID, sequence int, data []byte, _ time.Time) error {
+			if exportID != 1 || sequence != len(chunks) {
+				t.Fatalf("chunk %d/%d", exportID, sequence)
+			}
+			chunks = append(chunks, bytes.Clone(data))
+			return nil
+		},
+		completeTransactionExport: func(_ context.Context, exportID, rows int, size int64, _ time.Time) error {
+			total := 0
+			for _, chunk := range chunks {
+				total += len(chunk)
+			}
+			if rows != 3000 || size != int64(total) {
+				t.Fatalf("completed %d with %d rows and %d bytes, stored %d", exportID, rows, size, total)
+			}
+			completed = append(completed, exportID)
+			return nil
+		},
+		failTransactionExport: func(_ context.Context, _ int, _ string, final bool) error {
+			failed = append(failed, final)
+			return nil
+		},
+	}
+	result, err := testService(store).RunTransactionExportMaintenance(context.Background())
+	if apperrors.KindOf(err) != apperrors.KindInternal {
+		t.Fatalf("RunTransactionExportMaintenance() error = %v", err)
+	}
+	if result.Claimed != 3 || result.Completed != 1 || result.Failed != 2 {
+		t.Fatalf("RunTransactionExportMaintenance() = %#v", result)
+	}
+	if len(chunks) != 2 || len(chunks[0]) != transactionExportChunkBytes {
+		t.Fatalf("chunks = %d", len(chunks))
+	}
+	if len(completed) != 1 || len(failed) != 2 || failed[0] || !failed[1] {
+		t.Fatalf("completed = %v, failed = %v", completed, failed)
+	}
+}
+
+func TestRunTransactionExportMaintenanceFailsExportsThatOutlastTheRun(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	attempts := 1
+	type failure struct {
+		id      int
+		message string
+		final   bool
+	}
+	var failures []failure
+	var released []int
+	store := &fakeStore{
+		claimTransactionExports: func(context.Context, time.Time, time.Time, int, int) ([]repository.TransactionExportJob, error) {
+			return []repository.TransactionExportJob{
+				{ID: 1, UserID: 3, Format: "csv", From: from, To: from.AddDate(6, 0, 0), Attempts: attempts},
+				{ID: 2, UserID: 4, Format: "csv", From: from, To: from, Attempts: 1},
+			}, nil
+		},
+		streamTransactions: func(ctx context.Context, _ int, _, _ time.Time, _ func(model.Transaction) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		failTransactionExport: func(ctx context.Context, exportID int, message string, final bool) error {
+			if ctx.Err() != nil {
+				t.Fatalf("export %d failed with a done context", exportID)
+			}
+			failures = append(failures, failure{exportID, message, final})
+			return nil
+		},
+		releaseTransactionExport: func(_ context.Context, exportID int) error {
+			released = append(released, exportID)
+			return nil
+		},
+	}
+	service := testService(store)
+	run := func() (model.TransactionExportMaintenanceResult, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		return service.RunTransactionExportMaintenance(ctx)
+	}
+
+	result, err := run()
+	if !errors.Is(err, context.DeadlineExceeded) || result.Failed != 1 {
+		t.Fatalf("first run = %#v, %v", result, err)
+	}
+	if len(failures) != 1 || failures[0] != (failure{1, "export took too long and will be retried", false}) {
+		t.Fatalf("first run failures = %#v", failures)
+	}
+	if len(released) != 1 || released[0] != 2 {
+		t.Fatalf("first run released = %v", released)
+	}
+
+	// The store claims the job again; on its last attempt the failure is final.
+	attempts = maximumTransactionExportAttempts
+	if _, err := run(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("last run error = %v", err)
+	}
+	if len(failures) != 2 || failures[1] != (failure{1, "export took too long", true}) {
+		t.Fatalf("last run failures = %#v", failures)
+	}
+
+	// Shutdown leaves the running job claimed instead of spending an attempt.
+	ctx, cancel := context.WithCancel(context.Background())
+	store.streamTransactions = func(context.Context, int, time.Time, time.Time, func(model.Transaction) error) error {
+		cancel()
+		return context.Canceled
+	}
+	released = nil
+	if _, err := service.RunTransactionExportMaintenance(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("shutdown error = %v", err)
+	}
+	if len(failures) != 2 || len(released) != 1 || released[0] != 2 {
+		t.Fatalf("shutdown failures = %#v, released = %v", failures, released)
+	}
+}
+
+func TestDownloadTransactionExportRequiresCompletedFile(t *testing.T) {
+	store := &fakeStore{getTransactionExport: func(_ context.Context, _ int, exportID int) (model.TransactionExport, error) {
+		return model.TransactionExport{ID: exportID, Format: "csv", Status: "running"}, nil
+	}}
+	err := testService(store).DownloadTransactionExport(context.Background(), 1, 7, func(model.ExportFile) io.Writer {
+		t.Fatal("download started for a running export")
+		return nil
+	})
+	if apperrors.KindOf(err) != apperrors.KindConflict {
+		t.Fatalf("running export download error = %v", err)
+	}
+}
+
+func TestExportInvestmentTradesWritesRequestedFormat(t *testing.T) {
+	store := &fakeStore{listInvestmentTrades: func(
+		_ context.Context, _ int, filter repository.InvestmentTradeFilter,
+	) ([]model.InvestmentTrade, error) {
+		if filter.Limit != maximumExportRows+1 || filter.Through.Format(time.DateOnly) != "2026-08-01" {
+			t.Fatalf("filter = %#v", filter)
+		}
+		return []model.InvestmentTrade{{
+			ID: 1, AssetType: "stock", Symbol: "AAPL", Side: "buy", Amount: "100.00", Quantity: "1", Fees: "0.00",
+			Currency: "EUR", OccurredAt: "2026-07-10",
+		}}, nil
+	}}
+	service := testService(store)
+	var output bytes.Buffer
+	var file model.ExportFile
+	err := service.ExportInvestmentTrades(context.Background(), 1, model.InvestmentExportRequest{
+		From: "2026-07-01", Through: "2026-07-31", Format: "QIF",
+	}, func(started model.ExportFile) io.Writer {
+		file = started
+		return &output
+	})
+	if err != nil {
+		t.Fatalf("ExportInvestmentTrades() error = %v", err)
+	}
+	if file.Filename != "money-manager-investments-2026-07-01-to-2026-07-31.qif" || !strings.Contains(output.String(), "YAAPL\n") {
+		t.Fatalf("export = %#v %q", file, output.String())
+	}
+
+	err = service.ExportInvestmentTrades(context.Background(), 1, model.InvestmentExportRequest{
+		From: "2026-07-01", Through: "2026-07-31", Format: "jsonl",
+	}, func(model.ExportFile) io.Writer {
+		t.Fatal("export started before validation")
+		return nil
+	})
+	if apperrors.KindOf(err) != apperrors.KindValidation {
+		t.Fatalf("jsonl error = %v", err)
+	}
+}
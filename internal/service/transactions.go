@@ -12,29 +12,35 @@ import (
 )
 
 func (s *Service) ListTransactions(ctx context.Context, userID int, month, transactionType, category string) ([]model.Transaction, error) {
-	_, from, to, err := parseMonth(month)
+	filter, err := transactionFilter(month, transactionType, category)
 	if err != nil {
 		return nil, err
 	}
+	transactions, err := s.store.ListTransactions(ctx, userID, filter)
+	if err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("list transactions: %w", err))
+	}
+	return transactions, nil
+}
+
+func transactionFilter(month, transactionType, category string) (repository.TransactionFilter, error) {
+	_, from, to, err := parseMonth(month)
+	if err != nil {
+		return repository.TransactionFilter{}, err
+	}
 	if strings.TrimSpace(transactionType) != "" {
 		transactionType, err = normalizeTransactionType(transactionType)
 		if err != nil {
-			return nil, err
+			return repository.TransactionFilter{}, err
 		}
 	}
 	if strings.TrimSpace(category) != "" {
 		category, err = normalizeLimitedText(category, "category", maximumCategoryRunes, false)
 		if err != nil {
-			return nil, err
+			return repository.TransactionFilter{}, err
 		}
 	}
-	transactions, err := s.store.ListTransactions(ctx, userID, repository.TransactionFilter{
-		From: from, To: to, Type: transactionType, Category: category,
-	})
-	if err != nil {
-		return nil, apperrors.Internal(fmt.Errorf("list transactions: %w", err))
-	}
-	return transactions, nil
+	return repository.TransactionFilter{From: from, To: to, Type: transactionType, Category: category}, nil
 }
 
 func (s *Service) CreateTransaction(ctx context.Context, userID int, request model.TransactionRequest) (model.Transaction, error) {
@@ -42,10 +48,27 @@ func (s *Service) CreateTransaction(ctx context.Context, userID int, request mod
 	if err != nil {
 		return model.Transaction{}, err
 	}
+	rules, err := s.loadCategorizationRules(ctx, userID)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	// A manual entry keeps the category the user chose unless it is the
+	// catch-all, in which case a matching rule may refine it. Only a chosen
+	// category is a training example.
+	outcome := rules.apply(categorizationInput{
+		Type: normalized.Type, Description: normalized.Description, Amount: normalized.Amount,
+	})
+	normalized.CategoryChosen = !strings.EqualFold(normalized.Category, "other")
+	if outcome.Category != "" && !normalized.CategoryChosen {
+		normalized.Category = outcome.Category
+	}
+	normalized.Tags = outcome.Tags
+	normalized.ExcludedFromBudget = normalized.ExcludedFromBudget || outcome.ExcludedFromBudget
 	transaction, err := s.store.CreateTransaction(ctx, userID, normalized)
 	if err != nil {
 		return model.Transaction{}, apperrors.Internal(fmt.Errorf("create transaction: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return transaction, nil
 }
 
@@ -71,6 +94,7 @@ func (s *Service) UpdateTransaction(ctx context.Context, userID, transactionID i
 	if err != nil {
 		return model.Transaction{}, apperrors.Internal(fmt.Errorf("update transaction: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return transaction, nil
 }
 
@@ -85,6 +109,7 @@ func (s *Service) DeleteTransaction(ctx context.Context, userID, transactionID i
 	if err != nil {
 		return apperrors.Internal(fmt.Errorf("delete transaction: %w", err))
 	}
+	s.invalidateReports(ctx, userID)
 	return nil
 }
 
@@ -100,31 +125,6 @@ func (s *Service) Summary(ctx context.Context, userID int, month string) (model.
 	return summary, nil
 }
 
-func (s *Service) ExportTransactions(ctx context.Context, userID int, fromString, toString string) ([]model.Transaction, error) {
-	from, err := parseDate(fromString, "from")
-	if err != nil {
-		return nil, err
-	}
-	to, err := parseDate(toString, "to")
-	if err != nil {
-		return nil, err
-	}
-	if from.After(to) {
-		return nil, apperrors.Validation("from must be before or equal to to")
-	}
-	if days := int(to.Sub(from).Hours()/24) + 1; days > maximumExportDays {
-		return nil, apperrors.Validation("export date range must be 366 days or less")
-	}
-	transactions, err := s.store.ExportTransactions(ctx, userID, from, to.AddDate(0, 0, 1), maximumExportRows+1)
-	if err != nil {
-		return nil, apperrors.Internal(fmt.Errorf("export transactions: %w", err))
-	}
-	if len(transactions) > maximumExportRows {
-		return nil, apperrors.Validation("export contains more than 5000 transactions; narrow the date range")
-	}
-	return transactions, nil
-}
-
 func (s *Service) validateTransaction(ctx context.Context, userID int, request model.TransactionRequest, existing *model.Transaction) (model.TransactionRequest, error) {
 	transactionType, err := normalizeTransactionType(request.Type)
 	if err != nil {
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"math/big"
 	"net/mail"
 	"strings"
 	"time"
@@ -117,6 +118,27 @@ func normalizeAmount(value string) (string, error) {
 	return whole + "." + fraction, nil
 }
 
+// normalizePercentage accepts a percentage between 0 and 100 with at most 3
+// decimal places.
+func normalizePercentage(value, field string) (string, error) {
+	invalid := apperrors.Validation(field + " must be a percentage between 0 and 100 with at most 3 decimal places")
+	value = strings.TrimSpace(value)
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	if whole == "" || (hasFraction && (fraction == "" || len(fraction) > 3)) {
+		return "", invalid
+	}
+	for _, character := range whole + fraction {
+		if character < '0' || character > '9' {
+			return "", invalid
+		}
+	}
+	percentage, ok := new(big.Rat).SetString(value)
+	if !ok || percentage.Cmp(big.NewRat(100, 1)) > 0 {
+		return "", invalid
+	}
+	return percentage.FloatString(3), nil
+}
+
 func parseDate(value, field string) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	date, err := time.Parse("2006-01-02", value)
@@ -0,0 +1,217 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// camtDocument covers camt.052, camt.053, and camt.054 in every published
+// version. Element names are matched without their namespace, and the
+// version-specific shapes (Sts as text or as Cd, party names with or without
+// Pty) are both declared.
+type camtDocument struct {
+	Reports       []camtStatement `xml:"BkToCstmrAcctRpt>Rpt"`
+	Statements    []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	Notifications []camtStatement `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+}
+
+type camtStatement struct {
+	Account struct {
+		IBAN     string `xml:"Id>IBAN"`
+		Other    string `xml:"Id>Othr>Id"`
+		Currency string `xml:"Ccy"`
+	} `xml:"Acct"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Reference         string        `xml:"NtryRef"`
+	Amount            camtAmount    `xml:"Amt"`
+	CreditDebit       string        `xml:"CdtDbtInd"`
+	Status            camtStatus    `xml:"Sts"`
+	BookingDate       camtDate      `xml:"BookgDt"`
+	ValueDate         camtDate      `xml:"ValDt"`
+	ServicerReference string        `xml:"AcctSvcrRef"`
+	AdditionalInfo    string        `xml:"AddtlNtryInf"`
+	Details           []camtDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtDetails struct {
+	ServicerReference string     `xml:"Refs>AcctSvcrRef"`
+	EndToEndID        string     `xml:"Refs>EndToEndId"`
+	Amount            camtAmount `xml:"Amt"`
+	InstructedAmount  camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	CreditDebit       string     `xml:"CdtDbtInd"`
+	Debtor            camtParty  `xml:"RltdPties>Dbtr"`
+	DebtorIBAN        string     `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	Creditor          camtParty  `xml:"RltdPties>Cdtr"`
+	CreditorIBAN      string     `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	Unstructured      []string   `xml:"RmtInf>Ustrd"`
+	Structured        []string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo    string     `xml:"AddtlTxInf"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
+// ParseCAMT reads ISO 20022 camt.053 statements and camt.054 debit/credit
+// notifications (camt.052 reports share the layout). A batch entry whose
+// transaction details carry their own amounts is split into one transaction
+// per detail.
+func ParseCAMT(contents []byte) ([]Transaction, error) {
+	var document camtDocument
+	decoder := xml.NewDecoder(bytes.NewReader(contents))
+	decoder.CharsetReader = camtCharsetReader
+	if err := decoder.Decode(&document); err != nil {
+		return nil, errors.New("file is not a camt XML statement")
+	}
+	statements := append(append(document.Statements, document.Notifications...), document.Reports...)
+	var transactions []Transaction
+	for _, statement := range statements {
+		account := firstValue(statement.Account.IBAN, statement.Account.Other)
+		for _, entry := range statement.Entries {
+			for _, transaction := range camtTransactions(entry, account, statement.Account.Currency) {
+				transaction.Line = len(transactions) + 1
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+	if len(transactions) == 0 {
+		return nil, errors.New("camt statement contains no entries")
+	}
+	return transactions, nil
+}
+
+func camtTransactions(entry camtEntry, account, accountCurrency string) []Transaction {
+	base := Transaction{
+		ID:       firstValue(entry.Reference, entry.ServicerReference),
+		Account:  account,
+		Currency: firstValue(entry.Amount.Currency, accountCurrency),
+		Status:   strings.ToUpper(firstValue(entry.Status.Code, entry.Status.Text)),
+	}
+	date, err := parseCAMTDate(firstValue(entry.BookingDate.Date, entry.BookingDate.DateTime, entry.ValueDate.Date, entry.ValueDate.DateTime))
+	if err != nil {
+		base.Invalid = "date"
+	}
+	base.Date = date
+
+	split := len(entry.Details) > 1
+	for _, details := range entry.Details {
+		if firstValue(details.Amount.Value, details.InstructedAmount.Value) == "" {
+			split = false
+		}
+	}
+	if !split {
+		var details camtDetails
+		if len(entry.Details) > 0 {
+			details = entry.Details[0]
+		}
+		transaction := base
+		transaction.Raw = []string{account, entry.Amount.Value, entry.CreditDebit, date.Format("2006-01-02"), entry.AdditionalInfo}
+		setCAMTAmount(&transaction, entry.Amount.Value, entry.CreditDebit)
+		transaction.Description = camtDescription(details, transaction.Debit, entry.AdditionalInfo)
+		if transaction.ID == "" {
+			transaction.ID = firstValue(details.ServicerReference, details.EndToEndID)
+		}
+		return []Transaction{transaction}
+	}
+
+	transactions := make([]Transaction, 0, len(entry.Details))
+	for index, details := range entry.Details {
+		transaction := base
+		amount := details.Amount
+		if amount.Value == "" {
+			amount = details.InstructedAmount
+		}
+		if amount.Currency != "" {
+			transaction.Currency = amount.Currency
+		}
+		transaction.Raw = []string{account, amount.Value, date.Format("2006-01-02"), details.EndToEndID}
+		setCAMTAmount(&transaction, amount.Value, firstValue(details.CreditDebit, entry.CreditDebit))
+		transaction.Description = camtDescription(details, transaction.Debit, entry.AdditionalInfo)
+		switch {
+		case details.ServicerReference != "":
+			transaction.ID = details.ServicerReference
+		case transaction.ID != "":
+			transaction.ID += "/" + strconv.Itoa(index+1)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions
+}
+
+// setCAMTAmount applies the credit/debit indicator. A reversal keeps the
+// indicator of its own booking, so it needs no special handling.
+func setCAMTAmount(transaction *Transaction, value, indicator string) {
+	amount, negative, ok := parseDecimal(value)
+	if !ok && transaction.Invalid == "" {
+		transaction.Invalid = "amount"
+	}
+	transaction.Amount = amount
+	switch strings.ToUpper(strings.TrimSpace(indicator)) {
+	case "DBIT":
+		transaction.Debit = true
+	case "CRDT":
+		transaction.Debit = false
+	default:
+		transaction.Debit = negative
+	}
+}
+
+// camtDescription names the counterparty (the creditor of a debit, the debtor
+// of a credit) with their IBAN, followed by the remittance information.
+func camtDescription(details camtDetails, debit bool, entryInfo string) string {
+	party, iban := details.Debtor, details.DebtorIBAN
+	if debit {
+		party, iban = details.Creditor, details.CreditorIBAN
+	}
+	parts := []string{firstValue(party.PartyName, party.Name), iban}
+	parts = append(parts, details.Unstructured...)
+	parts = append(parts, details.Structured...)
+	description := joinDescription(parts...)
+	if description == "" {
+		description = joinDescription(details.AdditionalInfo, entryInfo)
+	}
+	return description
+}
+
+func parseCAMTDate(value string) (time.Time, error) {
+	if len(value) < 10 {
+		return time.Time{}, errors.New("invalid camt date")
+	}
+	return time.Parse("2006-01-02", value[:10])
+}
+
+func camtCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "windows-1251", "cp1251":
+		return charmap.Windows1251.NewDecoder().Reader(input), nil
+	case "windows-1252", "iso-8859-1", "latin1":
+		return charmap.Windows1252.NewDecoder().Reader(input), nil
+	}
+	return nil, errors.New("unsupported camt charset")
+}
@@ -0,0 +1,155 @@
+package statement
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	mt940TagPattern = regexp.MustCompile(`^:([0-9]{2}[A-Z]?):`)
+	// mt940LinePattern reads field 61: value date, optional entry date,
+	// debit/credit mark (with R for reversals), optional funds code, amount,
+	// transaction type, customer reference, and optional bank reference.
+	mt940LinePattern  = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?([0-9]+,[0-9]*)([A-Z][A-Z0-9]{3})([^/\n]{0,16})(?://([^\n]{0,16}))?`)
+	mt940SubfieldCode = regexp.MustCompile(`\?([0-9]{2})`)
+	mt940SlashCode    = regexp.MustCompile(`/(NAME|REMI|IBAN|ORDP|BENM|ACCW)/`)
+)
+
+// ParseMT940 reads SWIFT MT940 customer statements. Field 86 details are
+// read in the structured ?nn layout used by German and Bulgarian banks, in
+// the /NAME/ /REMI/ keyword layout, or as free text.
+func ParseMT940(contents []byte) ([]Transaction, error) {
+	text := strings.ReplaceAll(decodeText(contents, ""), "\r\n", "\n")
+	if !strings.Contains(text, ":61:") || !strings.Contains(text, ":25:") {
+		return nil, errors.New("file is not an MT940 statement")
+	}
+
+	type mt940Field struct {
+		tag   string
+		value string
+	}
+	var fields []mt940Field
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if match := mt940TagPattern.FindStringSubmatch(line); match != nil {
+			fields = append(fields, mt940Field{tag: match[1], value: line[len(match[0]):]})
+			continue
+		}
+		if len(fields) > 0 && line != "-" && !strings.HasPrefix(line, "{") && !strings.HasPrefix(line, "}") {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+
+	var (
+		account, currency string
+		transactions      []Transaction
+	)
+	for _, field := range fields {
+		switch field.tag {
+		case "25":
+			account = strings.TrimSpace(field.value)
+		case "60F", "60M":
+			// The opening balance is mark, date, currency, amount: C260701EUR1234,56.
+			if value := strings.TrimSpace(field.value); len(value) >= 10 {
+				currency = value[7:10]
+			}
+		case "61":
+			transactions = append(transactions, mt940Transaction(len(transactions)+1, account, currency, field.value))
+		case "86":
+			if len(transactions) > 0 && transactions[len(transactions)-1].Description == "" {
+				last := &transactions[len(transactions)-1]
+				last.Description = mt940Description(field.value, last.Debit)
+				last.Raw = append(last.Raw, field.value)
+			}
+		}
+	}
+	if len(transactions) == 0 {
+		return nil, errors.New("MT940 statement contains no transactions")
+	}
+	return transactions, nil
+}
+
+func mt940Transaction(line int, account, currency, value string) Transaction {
+	transaction := Transaction{Line: line, Account: account, Currency: currency, Status: "BOOK", Raw: []string{account, value}}
+	match := mt940LinePattern.FindStringSubmatch(value)
+	if match == nil {
+		transaction.Invalid = "amount"
+		return transaction
+	}
+	valueDate, err := time.Parse("060102", match[1])
+	if err != nil {
+		transaction.Invalid = "date"
+	}
+	transaction.Date = valueDate
+	// The entry (booking) date carries no year; it belongs to the value
+	// date's year unless the two straddle New Year.
+	if match[2] != "" && err == nil {
+		if entryDate, err := time.Parse("0102", match[2]); err == nil {
+			entryDate = entryDate.AddDate(valueDate.Year(), 0, 0)
+			switch {
+			case entryDate.Sub(valueDate) > 180*24*time.Hour:
+				entryDate = entryDate.AddDate(-1, 0, 0)
+			case valueDate.Sub(entryDate) > 180*24*time.Hour:
+				entryDate = entryDate.AddDate(1, 0, 0)
+			}
+			transaction.Date = entryDate
+		}
+	}
+	amount, _, ok := parseDecimal(match[5])
+	if !ok && transaction.Invalid == "" {
+		transaction.Invalid = "amount"
+	}
+	transaction.Amount = amount
+	// A reversed credit takes money out of the account and a reversed debit
+	// puts it back.
+	transaction.Debit = match[3] == "D" || match[3] == "RC"
+	transaction.ID = strings.TrimSpace(match[8])
+	if reference := strings.TrimSpace(match[7]); transaction.ID == "" && reference != "" && reference != "NONREF" {
+		transaction.ID = reference
+	}
+	return transaction
+}
+
+// mt940Description names the counterparty with their IBAN, followed by the
+// remittance information.
+func mt940Description(value string, debit bool) string {
+	value = strings.ReplaceAll(value, "\n", "")
+	if indexes := mt940SubfieldCode.FindAllStringSubmatchIndex(value, -1); len(indexes) > 0 {
+		subfields := map[string]string{}
+		for position, index := range indexes {
+			end := len(value)
+			if position+1 < len(indexes) {
+				end = indexes[position+1][0]
+			}
+			code := value[index[2]:index[3]]
+			subfields[code] += value[index[1]:end]
+		}
+		var remittance strings.Builder
+		for _, code := range []string{"20", "21", "22", "23", "24", "25", "26", "27", "28", "29", "60", "61", "62", "63"} {
+			remittance.WriteString(subfields[code])
+		}
+		description := joinDescription(subfields["32"]+subfields["33"], subfields["31"], remittance.String())
+		if description == "" {
+			description = joinDescription(subfields["00"])
+		}
+		return description
+	}
+	if indexes := mt940SlashCode.FindAllStringSubmatchIndex(value, -1); len(indexes) > 0 {
+		subfields := map[string]string{}
+		for position, index := range indexes {
+			end := len(value)
+			if position+1 < len(indexes) {
+				end = indexes[position+1][0]
+			}
+			subfields[value[index[2]:index[3]]] = strings.Trim(value[index[1]:end], "/ ")
+		}
+		name := firstValue(subfields["NAME"], subfields["ORDP"], subfields["BENM"])
+		if debit {
+			name = firstValue(subfields["NAME"], subfields["BENM"], subfields["ORDP"])
+		}
+		return joinDescription(name, firstValue(subfields["IBAN"], subfields["ACCW"]), subfields["REMI"])
+	}
+	return joinDescription(value)
+}
@@ -0,0 +1,142 @@
+package statement
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ofxTagPattern     = regexp.MustCompile(`<(/?)([A-Za-z0-9._]+)[^>]*>`)
+	ofxCharsetPattern = regexp.MustCompile(`(?i)(?:CHARSET:\s*|encoding=["'](?:windows-)?)([A-Za-z0-9-]+)`)
+)
+
+// ParseOFX reads OFX 1.x (SGML) and 2.x (XML) bank and credit-card
+// statements, which also covers Quicken QFX files. Leaf elements may omit
+// their end tags as SGML allows.
+func ParseOFX(contents []byte) ([]Transaction, error) {
+	charset := ""
+	if match := ofxCharsetPattern.FindSubmatch(contents[:min(len(contents), 1024)]); match != nil {
+		charset = string(match[1])
+	}
+	text := decodeText(contents, charset)
+	if !strings.Contains(strings.ToUpper(text), "<OFX>") {
+		return nil, errors.New("file is not an OFX statement")
+	}
+
+	var (
+		stack           []string
+		account         string
+		defaultCurrency string
+		current         map[string]string
+		transactions    []Transaction
+	)
+	parent := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		return stack[len(stack)-1]
+	}
+	finish := func() {
+		if current == nil {
+			return
+		}
+		transactions = append(transactions, ofxTransaction(len(transactions)+1, account, defaultCurrency, current))
+		current = nil
+	}
+	matches := ofxTagPattern.FindAllStringSubmatchIndex(text, -1)
+	for index, match := range matches {
+		closing := match[3] > match[2]
+		name := strings.ToUpper(text[match[4]:match[5]])
+		valueEnd := len(text)
+		if index+1 < len(matches) {
+			valueEnd = matches[index+1][0]
+		}
+		value := strings.TrimSpace(html.UnescapeString(text[match[1]:valueEnd]))
+
+		if closing {
+			for position := len(stack) - 1; position >= 0; position-- {
+				if stack[position] == name {
+					stack = stack[:position]
+					break
+				}
+			}
+			if name == "STMTTRN" {
+				finish()
+			}
+			continue
+		}
+		if value == "" {
+			if name == "STMTTRN" {
+				finish()
+				current = map[string]string{}
+			}
+			stack = append(stack, name)
+			continue
+		}
+		switch {
+		case name == "ACCTID" && (parent() == "BANKACCTFROM" || parent() == "CCACCTFROM"):
+			account = value
+		case name == "CURDEF":
+			defaultCurrency = value
+		case current != nil && parent() == "PAYEE" && name == "NAME":
+			current["PAYEE"] = value
+		case current != nil && parent() == "CURRENCY" && name == "CURSYM":
+			current["CURRENCY"] = value
+		case current != nil && parent() == "STMTTRN":
+			current[name] = value
+		}
+	}
+	finish()
+	if len(transactions) == 0 {
+		return nil, errors.New("OFX statement contains no transactions")
+	}
+	return transactions, nil
+}
+
+func ofxTransaction(line int, account, defaultCurrency string, fields map[string]string) Transaction {
+	transaction := Transaction{
+		Line:        line,
+		ID:          fields["FITID"],
+		Account:     account,
+		Currency:    defaultCurrency,
+		Description: joinDescription(fields["NAME"], fields["PAYEE"], fields["MEMO"]),
+		Raw: []string{
+			account, fields["DTPOSTED"], fields["TRNAMT"], fields["NAME"], fields["MEMO"], fields["CHECKNUM"],
+		},
+	}
+	if currency := fields["CURRENCY"]; currency != "" {
+		transaction.Currency = currency
+	}
+	amount, negative, ok := parseDecimal(fields["TRNAMT"])
+	if !ok {
+		transaction.Invalid = "amount"
+	}
+	transaction.Amount, transaction.Debit = amount, negative
+	date, err := parseOFXDate(firstValue(fields["DTPOSTED"], fields["DTUSER"]))
+	if err != nil && transaction.Invalid == "" {
+		transaction.Invalid = "date"
+	}
+	transaction.Date = date
+	return transaction
+}
+
+// parseOFXDate reads the calendar date of an OFX timestamp such as
+// 20260711120000.000[+2:EET]. The time of day is not used by the ledger.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("invalid OFX date")
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+func firstValue(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package statement
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// qifDateOrders lists QIF date orders in detection order. Quicken mixes two-
+// and four-digit years within one file, so each order accepts both. Month
+// first wins when a file is ambiguous because that is the QIF convention;
+// dotted dates are always day first.
+var qifDateOrders = [][]string{
+	{"1/2/2006", "1/2/06"},
+	{"2/1/2006", "2/1/06"},
+	{"2.1.2006", "2.1.06"},
+	{"2006-1-2"},
+	{"2-1-2006"},
+}
+
+// ParseQIF reads bank, cash, and credit-card QIF files. An empty dateLayout
+// detects the date order from every date in the file.
+func ParseQIF(contents []byte, dateLayout string) ([]Transaction, error) {
+	text := strings.ReplaceAll(decodeText(contents, ""), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	type qifRecord struct {
+		line   int
+		fields map[byte]string
+		raw    []string
+	}
+	var records []qifRecord
+	supported, skipping := false, false
+	current := qifRecord{fields: map[byte]string{}}
+	for index, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "!") {
+			header := strings.ToLower(strings.TrimSpace(line))
+			switch {
+			case header == "!account":
+				skipping = true
+			case strings.HasPrefix(header, "!type:invst"):
+				return nil, errors.New("investment QIF files are not supported")
+			case strings.HasPrefix(header, "!type:"):
+				kind := strings.TrimSpace(strings.TrimPrefix(header, "!type:"))
+				switch kind {
+				case "bank", "cash", "ccard", "oth a", "oth l":
+					supported, skipping = true, false
+				default:
+					supported, skipping = false, true
+				}
+			}
+			continue
+		}
+		if line == "^" {
+			if !skipping && supported && len(current.fields) > 0 {
+				records = append(records, current)
+			}
+			current = qifRecord{fields: map[byte]string{}}
+			if skipping && supported {
+				skipping = false
+			}
+			continue
+		}
+		if skipping {
+			continue
+		}
+		if current.line == 0 {
+			current.line = index + 1
+		}
+		current.raw = append(current.raw, line)
+		code := line[0]
+		if _, exists := current.fields[code]; !exists {
+			current.fields[code] = strings.TrimSpace(line[1:])
+		}
+	}
+	if !skipping && supported && len(current.fields) > 0 {
+		records = append(records, current)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("QIF file contains no bank transactions")
+	}
+
+	layouts := []string{dateLayout}
+	if dateLayout == "" {
+		dates := make([]string, 0, len(records))
+		for _, record := range records {
+			dates = append(dates, normalizeQIFDate(record.fields['D']))
+		}
+		layouts = detectQIFDateOrder(dates)
+	}
+	transactions := make([]Transaction, 0, len(records))
+	for _, record := range records {
+		transaction := Transaction{
+			Line:        record.line,
+			Description: joinDescription(record.fields['P'], record.fields['M']),
+			Raw:         record.raw,
+		}
+		amount, negative, ok := parseDecimal(firstValue(record.fields['T'], record.fields['U']))
+		if !ok {
+			transaction.Invalid = "amount"
+		}
+		transaction.Amount, transaction.Debit = amount, negative
+		date, ok := parseQIFDate(layouts, normalizeQIFDate(record.fields['D']))
+		if !ok && transaction.Invalid == "" {
+			transaction.Invalid = "date"
+		}
+		transaction.Date = date
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+// normalizeQIFDate turns Quicken's apostrophe year separator (7/11'26) and
+// padded day numbers (7/ 1/26) into ordinary slash dates.
+func normalizeQIFDate(value string) string {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "'", "/")
+	return strings.ReplaceAll(value, " ", "")
+}
+
+func parseQIFDate(layouts []string, value string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func detectQIFDateOrder(values []string) []string {
+	best, bestCount := []string(nil), 0
+	for _, layouts := range qifDateOrders {
+		count := 0
+		for _, value := range values {
+			if _, ok := parseQIFDate(layouts, value); ok {
+				count++
+			}
+		}
+		if count == len(values) {
+			return layouts
+		}
+		// No single order fits every row; keep the most common one so only
+		// the unreadable rows are rejected.
+		if count > bestCount {
+			best, bestCount = layouts, count
+		}
+	}
+	return best
+}
@@ -0,0 +1,140 @@
+// Package statement parses bank statement files into a format-neutral list
+// of transactions. It performs no validation beyond reading each field;
+// callers decide which rows are imported, ignored, or rejected.
+package statement
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Transaction is one statement entry.
+type Transaction struct {
+	// Line is the 1-based position of the entry in the file, used in
+	// rejection messages.
+	Line int
+	// ID is the bank's stable identifier (OFX FITID, camt entry reference,
+	// MT940 bank reference). It is empty when the format has none.
+	ID      string
+	Account string
+	// Date is zero when the entry date could not be read.
+	Date time.Time
+	// Amount is an unsigned decimal with a dot separator.
+	Amount      string
+	Debit       bool
+	Currency    string
+	Description string
+	// Status is the booking status reported by the file, if any.
+	Status string
+	// Invalid names the field that could not be read.
+	Invalid string
+	// Raw holds the entry's source fields for fingerprinting entries
+	// without a stable ID.
+	Raw []string
+}
+
+// decodeText converts legacy single-byte statement files to UTF-8. Files that
+// already are valid UTF-8 are returned unchanged; without a declared charset
+// the code page is guessed.
+func decodeText(contents []byte, charset string) string {
+	contents = bytes.TrimPrefix(contents, []byte{0xEF, 0xBB, 0xBF})
+	switch strings.TrimSpace(charset) {
+	case "1251", "windows-1251", "WINDOWS-1251":
+		if decoded, err := charmap.Windows1251.NewDecoder().Bytes(contents); err == nil {
+			return string(decoded)
+		}
+	}
+	if utf8.Valid(contents) {
+		return string(contents)
+	}
+	decoder := charmap.Windows1252.NewDecoder()
+	if looksLikeWindows1251(contents) {
+		decoder = charmap.Windows1251.NewDecoder()
+	}
+	decoded, err := decoder.Bytes(contents)
+	if err != nil {
+		return strings.ToValidUTF8(string(contents), "\uFFFD")
+	}
+	return string(decoded)
+}
+
+// looksLikeWindows1251 tells Bulgarian bank exports from Western ones:
+// Cyrillic words in Windows-1251 are runs of high bytes, while accented Latin
+// letters in Windows-1252 usually sit alone between ASCII letters.
+func looksLikeWindows1251(contents []byte) bool {
+	paired, isolated := 0, 0
+	for index, value := range contents {
+		if value < 0x80 {
+			continue
+		}
+		if (index > 0 && contents[index-1] >= 0x80) || (index+1 < len(contents) && contents[index+1] >= 0x80) {
+			paired++
+		} else {
+			isolated++
+		}
+	}
+	return paired > isolated
+}
+
+// parseDecimal returns the unsigned amount, whether it was negative, and
+// whether the value was readable. Both dot and comma decimal separators are
+// accepted because European OFX and MT940 exporters use either.
+func parseDecimal(value string) (string, bool, bool) {
+	value = strings.TrimSpace(value)
+	negative := false
+	switch {
+	case strings.HasPrefix(value, "-"):
+		negative, value = true, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	value = strings.NewReplacer(" ", "", "\u00a0", "", "'", "").Replace(value)
+	lastComma, lastDot := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+	switch {
+	case lastComma > lastDot && lastDot >= 0:
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	case lastComma >= 0 && lastDot >= 0:
+		value = strings.ReplaceAll(value, ",", "")
+	case lastComma >= 0 && strings.Count(value, ",") == 1:
+		value = strings.Replace(value, ",", ".", 1)
+	case lastComma >= 0:
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	// MT940 writes whole amounts with a bare trailing separator (250,).
+	value = strings.TrimSuffix(value, ".")
+	if value == "" || strings.Count(value, ".") > 1 {
+		return "", false, false
+	}
+	for _, character := range value {
+		if (character < '0' || character > '9') && character != '.' {
+			return "", false, false
+		}
+	}
+	return value, negative, true
+}
+
+func joinDescription(parts ...string) string {
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.Join(strings.Fields(part), " ")
+		if part == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range out {
+			if strings.Contains(strings.ToLower(existing), strings.ToLower(part)) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			out = append(out, part)
+		}
+	}
+	return strings.Join(out, " - ")
+}
@@ -0,0 +1,168 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestParseOFXReadsSGMLStatement(t *testing.T) {
+	contents := []byte("OFXHEADER:100\r\nDATA:OFXSGML\r\nCHARSET:1252\r\n\r\n" +
+		"<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>EUR\r\n" +
+		"<BANKACCTFROM><BANKID>1234<ACCTID>BG80BNBG96611020345678<ACCTTYPE>CHECKING</BANKACCTFROM>\r\n" +
+		"<BANKTRANLIST><DTSTART>20260701\r\n" +
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20260711120000.000[+3:EEST]<TRNAMT>-12,50<FITID>A-1<NAME>LIDL &amp; Co<MEMO>Card payment</STMTTRN>\r\n" +
+		"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20260725<TRNAMT>1250.00<FITID>A-2<NAME>Salary<CURRENCY><CURRATE>1<CURSYM>USD</CURRENCY></STMTTRN>\r\n" +
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>2026<TRNAMT>-1<FITID>A-3<NAME>Broken\r\n" +
+		"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>\r\n")
+	transactions, err := ParseOFX(contents)
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	first := transactions[0]
+	if first.ID != "A-1" || first.Account != "BG80BNBG96611020345678" || first.Amount != "12.50" || !first.Debit ||
+		first.Currency != "EUR" || first.Date.Format("2006-01-02") != "2026-07-11" || first.Description != "LIDL & Co - Card payment" {
+		t.Fatalf("first transaction = %#v", first)
+	}
+	if second := transactions[1]; second.Debit || second.Currency != "USD" || second.Line != 2 {
+		t.Fatalf("second transaction = %#v", second)
+	}
+	if third := transactions[2]; third.Invalid != "date" {
+		t.Fatalf("third transaction = %#v", third)
+	}
+}
+
+func TestParseOFXReadsXMLStatement(t *testing.T) {
+	contents := []byte(`<?xml version="1.0" encoding="UTF-8"?><?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><CURDEF>EUR</CURDEF>
+<CCACCTFROM><ACCTID>4111</ACCTID></CCACCTFROM>
+<BANKTRANLIST><STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20260702</DTPOSTED><TRNAMT>-9.99</TRNAMT>
+<FITID>X1</FITID><PAYEE><NAME>Спотифай</NAME></PAYEE></STMTTRN></BANKTRANLIST>
+</CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>`)
+	transactions, err := ParseOFX(contents)
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if len(transactions) != 1 || transactions[0].Account != "4111" || transactions[0].Description != "Спотифай" {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	if _, err := ParseOFX([]byte("Date,Amount\n")); err == nil {
+		t.Fatal("CSV file was accepted as OFX")
+	}
+}
+
+func TestParseQIFDetectsDateOrderAndSkipsAccounts(t *testing.T) {
+	text := "!Account\nNChecking\nTBank\n^\n!Type:Bank\n" +
+		"D07/11'26\nT-1,234.50\nPFurniture store\nMDelivery\nLHome\n^\n" +
+		"D7/25/2026\nU2000\nPSalary\n^\n" +
+		"D7/25/2026\nTabc\nPBroken\n^\n"
+	transactions, err := ParseQIF([]byte(text), "")
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	first := transactions[0]
+	if first.Line != 6 || first.Amount != "1234.50" || !first.Debit || first.Date.Format("2006-01-02") != "2026-07-11" ||
+		first.Description != "Furniture store - Delivery" {
+		t.Fatalf("first transaction = %#v", first)
+	}
+	if second := transactions[1]; second.Debit || second.Amount != "2000" {
+		t.Fatalf("second transaction = %#v", second)
+	}
+	if third := transactions[2]; third.Invalid != "amount" {
+		t.Fatalf("third transaction = %#v", third)
+	}
+}
+
+func TestParseQIFHonoursDateLayoutAndLegacyEncoding(t *testing.T) {
+	contents, err := charmap.Windows1252.NewEncoder().Bytes([]byte("!Type:CCard\nD03.04.2026\nT-5,00\nPCafé\n^\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactions, err := ParseQIF(contents, "2.1.2006")
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	if len(transactions) != 1 || transactions[0].Date.Format("2006-01-02") != "2026-04-03" || transactions[0].Description != "Café" {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	if _, err := ParseQIF([]byte("!Type:Invst\nD1/2/2026\n^\n"), ""); err == nil || !strings.Contains(err.Error(), "investment") {
+		t.Fatalf("investment QIF error = %v", err)
+	}
+}
+
+func TestParseCAMTReadsStatementAndSplitsBatches(t *testing.T) {
+	contents := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"><BkToCstmrStmt><Stmt>
+<Acct><Id><IBAN>BG80BNBG96611020345678</IBAN></Id><Ccy>EUR</Ccy></Acct>
+<Ntry><NtryRef>E-1</NtryRef><Amt Ccy="EUR">42.80</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+<BookgDt><Dt>2026-07-11</Dt></BookgDt><ValDt><Dt>2026-07-12</Dt></ValDt>
+<NtryDtls><TxDtls><RltdPties><Cdtr><Pty><Nm>Fresh Market</Nm></Pty></Cdtr><CdtrAcct><Id><IBAN>BG18RZBB91550123456789</IBAN></Id></CdtrAcct></RltdPties>
+<RmtInf><Ustrd>Invoice 12</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>
+<Ntry><AcctSvcrRef>B-7</AcctSvcrRef><Amt Ccy="EUR">300.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>PDNG</Sts>
+<BookgDt><DtTm>2026-07-13T09:00:00</DtTm></BookgDt><NtryDtls>
+<TxDtls><Amt Ccy="EUR">100.00</Amt><RltdPties><Dbtr><Nm>Ivan</Nm></Dbtr></RltdPties></TxDtls>
+<TxDtls><Amt Ccy="EUR">200.00</Amt><RltdPties><Dbtr><Nm>Maria</Nm></Dbtr></RltdPties></TxDtls>
+</NtryDtls></Ntry></Stmt></BkToCstmrStmt></Document>`)
+	transactions, err := ParseCAMT(contents)
+	if err != nil {
+		t.Fatalf("ParseCAMT() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	first := transactions[0]
+	if first.ID != "E-1" || first.Account != "BG80BNBG96611020345678" || !first.Debit || first.Amount != "42.80" || first.Status != "BOOK" ||
+		first.Date.Format("2006-01-02") != "2026-07-11" || first.Description != "Fresh Market - BG18RZBB91550123456789 - Invoice 12" {
+		t.Fatalf("first transaction = %#v", first)
+	}
+	second, third := transactions[1], transactions[2]
+	if second.ID != "B-7/1" || third.ID != "B-7/2" || second.Debit || second.Amount != "100.00" || third.Description != "Maria" ||
+		second.Status != "PDNG" || second.Date.Format("2006-01-02") != "2026-07-13" {
+		t.Fatalf("batch transactions = %#v, %#v", second, third)
+	}
+	if _, err := ParseCAMT([]byte("<Document></Document>")); err == nil {
+		t.Fatal("empty camt document was accepted")
+	}
+}
+
+func TestParseMT940ReadsStructuredDetails(t *testing.T) {
+	contents, err := charmap.Windows1251.NewEncoder().Bytes([]byte(":20:STMT\r\n:25:BG80BNBG96611020345678\r\n:28C:1/1\r\n" +
+		":60F:C260701EUR1000,00\r\n" +
+		":61:2607110711D12,50NTRFNONREF//REF-1\r\n" +
+		":86:166?00Плащане?20Фактура 12?32Софийска вода?31BG18RZBB91550123456789\r\n" +
+		":61:2612310102C250,NTRFCUST-2\r\n" +
+		":86:/NAME/Иван Петров/REMI/Наем\r\n" +
+		":61:2607150715RD5,00NMSCNONREF\r\n" +
+		":86:Refund\r\n" +
+		":62F:C260731EUR1232,50\r\n-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactions, err := ParseMT940(contents)
+	if err != nil {
+		t.Fatalf("ParseMT940() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("transactions = %#v", transactions)
+	}
+	first := transactions[0]
+	if first.ID != "REF-1" || !first.Debit || first.Amount != "12.50" || first.Currency != "EUR" ||
+		first.Date.Format("2006-01-02") != "2026-07-11" || first.Description != "Софийска вода - BG18RZBB91550123456789 - Фактура 12" {
+		t.Fatalf("first transaction = %#v", first)
+	}
+	second := transactions[1]
+	if second.ID != "CUST-2" || second.Debit || second.Amount != "250" || second.Date.Format("2006-01-02") != "2027-01-02" ||
+		second.Description != "Иван Петров - Наем" {
+		t.Fatalf("second transaction = %#v", second)
+	}
+	if third := transactions[2]; third.Debit || third.ID != "" || third.Description != "Refund" {
+		t.Fatalf("third transaction = %#v", third)
+	}
+}